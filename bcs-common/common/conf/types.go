@@ -72,6 +72,13 @@ type MetricConfig struct {
 	MetricPort uint `json:"metric_port" value:"8081" usage:"Port to listen on for metric" mapstructure:"metric_port" `
 }
 
+// TracingConfig distributed request tracing config
+type TracingConfig struct {
+	TracingExporter    string  `json:"tracing_exporter" value:"noop" usage:"Exporter for trace spans, available: noop, otlp, zipkin" mapstructure:"tracing_exporter"`
+	TracingEndpoint    string  `json:"tracing_endpoint" value:"" usage:"Collector endpoint that exporter sends spans to, e.g. http://127.0.0.1:4318/v1/traces" mapstructure:"tracing_endpoint"`
+	TracingSampleRatio float64 `json:"tracing_sample_ratio" value:"1" usage:"Sample ratio of root spans, range [0, 1]" mapstructure:"tracing_sample_ratio"`
+}
+
 // ZkConfig bcs zookeeper for service discovery
 type ZkConfig struct {
 	BCSZk string `json:"bcs_zookeeper" value:"127.0.0.1:2181" usage:"Zookeeper server for registering and discovering" mapstructure:"bcs_zookeeper" `
@@ -85,6 +85,12 @@ const (
 	BcsErrApiInternalDbError         = AdditionErrorCode + 62
 	BcsErrApiK8sInternalError        = AdditionErrorCode + 63
 	BcsErrApiWebConsoleFailedCode    = AdditionErrorCode + 64
+	BcsErrApiFederationPartial       = AdditionErrorCode + 65
+	BcsErrApiFederationPartialStr    = "request failed in some member clusters"
+	BcsErrApiFederationFail          = AdditionErrorCode + 66
+	BcsErrApiFederationFailStr       = "request failed in all member clusters"
+	BcsErrApiRateLimited             = AdditionErrorCode + 67
+	BcsErrApiRateLimitedStr          = "too many requests"
 
 	/*Common error code 1401 080~1401 109
 	bcs storage module errno name is as a beginning to BcsErrStorage*/
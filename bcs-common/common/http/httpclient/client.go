@@ -16,7 +16,9 @@ package httpclient
 import (
 	http2 "bk-bcs/bcs-common/common/http"
 	"bk-bcs/bcs-common/common/ssl"
+	"bk-bcs/bcs-common/common/tracing"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"io/ioutil"
 	"net"
@@ -38,6 +40,7 @@ type HttpClient struct {
 	keyFile  string
 	header   map[string]string
 	httpCli  *http.Client
+	ctx      context.Context
 }
 
 func NewHttpClient() *HttpClient {
@@ -47,6 +50,14 @@ func NewHttpClient() *HttpClient {
 	}
 }
 
+//WithContext return shallow copy of client bound to ctx, trace context and
+//request id in ctx are propagated to every request sent by the copy
+func (client *HttpClient) WithContext(ctx context.Context) *HttpClient {
+	c := *client
+	c.ctx = ctx
+	return &c
+}
+
 func (client *HttpClient) GetClient() *http.Client {
 	return client.httpCli
 }
@@ -178,7 +189,8 @@ func (client *HttpClient) Request(url, method string, header http.Header, data [
 
 func (client *HttpClient) RequestEx(url, method string, header http.Header, data []byte) (*HttpRespone, error) {
 	var req *http.Request
-	var errReq error
+	var rsp *http.Response
+	var err, errReq error
 	httpRsp := &HttpRespone{
 		Reply:      nil,
 		StatusCode: http.StatusInternalServerError,
@@ -208,7 +220,21 @@ func (client *HttpClient) RequestEx(url, method string, header http.Header, data
 		req.Header.Set(key, value)
 	}
 
-	rsp, err := client.httpCli.Do(req)
+	if client.ctx != nil {
+		//copy header, do not pollute header of caller when injecting trace context
+		reqHeader := make(http.Header, len(req.Header))
+		for key, values := range req.Header {
+			reqHeader[key] = append([]string(nil), values...)
+		}
+		req.Header = reqHeader
+		req = req.WithContext(client.ctx)
+		span := tracing.StartClientSpan(client.ctx, req)
+		defer func() {
+			tracing.FinishClientSpan(span, rsp, err)
+		}()
+	}
+
+	rsp, err = client.httpCli.Do(req)
 	if err != nil {
 		return httpRsp, err
 	}
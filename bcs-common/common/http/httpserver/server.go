@@ -16,6 +16,7 @@ package httpserver
 import (
 	"bk-bcs/bcs-common/common/blog"
 	"bk-bcs/bcs-common/common/ssl"
+	"bk-bcs/bcs-common/common/tracing"
 	"fmt"
 	"github.com/emicklei/go-restful"
 	"github.com/gorilla/mux"
@@ -41,7 +42,7 @@ type HttpServer struct {
 }
 
 func NewHttpServer(port uint, addr, sock string) *HttpServer {
-	return &HttpServer{
+	s := &HttpServer{
 		addr:         addr,
 		port:         port,
 		sock:         sock,
@@ -49,6 +50,10 @@ func NewHttpServer(port uint, addr, sock string) *HttpServer {
 		router:       mux.NewRouter(),
 		isSSL:        false,
 	}
+	//every request carries trace context and request id
+	s.webContainer.Filter(tracing.RestfulFilter)
+	s.router.Use(tracing.Middleware)
+	return s
 }
 
 func (s *HttpServer) SetInsecureServer(insecureAddr string, insecurePort uint) {
@@ -22,4 +22,7 @@ const (
 
 	// 数据传给defaultExporter
 	DefaultExporterPlugin = 0x03
+
+	// MQExporterPlugin 数据发送到kafka消息队列
+	MQExporterPlugin = 0x04
 )
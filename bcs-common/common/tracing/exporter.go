@@ -0,0 +1,329 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"bk-bcs/bcs-common/common/blog"
+)
+
+const (
+	//NoopExporterName exporter drop all spans
+	NoopExporterName = "noop"
+	//OTLPExporterName exporter send spans by OTLP/HTTP json, jaeger and opentelemetry collector support it
+	OTLPExporterName = "otlp"
+	//ZipkinExporterName exporter send spans by zipkin v2 json, jaeger and zipkin support it
+	ZipkinExporterName = "zipkin"
+
+	exportQueueSize     = 2048
+	exportBatchSize     = 256
+	exportFlushInterval = time.Second * 5
+	exportTimeout       = time.Second * 10
+)
+
+//Exporter send finished spans to tracing backend
+type Exporter interface {
+	//Export span, must not block
+	Export(span *Span)
+	//Shutdown flush and stop exporter
+	Shutdown()
+}
+
+//ExporterFactory create exporter with service name and collector endpoint
+type ExporterFactory func(serviceName, endpoint string) (Exporter, error)
+
+var (
+	exporterFactories = make(map[string]ExporterFactory)
+	factoryLock       sync.RWMutex
+)
+
+func init() {
+	RegisterExporter(NoopExporterName, func(string, string) (Exporter, error) {
+		return &noopExporter{}, nil
+	})
+	RegisterExporter(OTLPExporterName, func(serviceName, endpoint string) (Exporter, error) {
+		return newBatchExporter(serviceName, endpoint, encodeOTLP)
+	})
+	RegisterExporter(ZipkinExporterName, func(serviceName, endpoint string) (Exporter, error) {
+		return newBatchExporter(serviceName, endpoint, encodeZipkin)
+	})
+}
+
+//RegisterExporter register exporter factory by name, registered exporter can be selected by config
+func RegisterExporter(name string, factory ExporterFactory) {
+	factoryLock.Lock()
+	defer factoryLock.Unlock()
+	exporterFactories[name] = factory
+}
+
+//NewExporter create exporter by registered name
+func NewExporter(name, serviceName, endpoint string) (Exporter, error) {
+	factoryLock.RLock()
+	factory, ok := exporterFactories[name]
+	factoryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("tracing exporter %s is not registered", name)
+	}
+	return factory(serviceName, endpoint)
+}
+
+type noopExporter struct{}
+
+func (e *noopExporter) Export(*Span) {}
+
+func (e *noopExporter) Shutdown() {}
+
+//encodeFunc encode spans to request body of collector
+type encodeFunc func(serviceName string, spans []*Span) ([]byte, error)
+
+//batchExporter queue spans, and post them to collector in batch
+type batchExporter struct {
+	serviceName string
+	endpoint    string
+	encode      encodeFunc
+	client      *http.Client
+	queue       chan *Span
+	stop        chan struct{}
+	done        chan struct{}
+	stopOnce    sync.Once
+}
+
+func newBatchExporter(serviceName, endpoint string, encode encodeFunc) (Exporter, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("tracing exporter endpoint is empty")
+	}
+
+	e := &batchExporter{
+		serviceName: serviceName,
+		endpoint:    endpoint,
+		encode:      encode,
+		client:      &http.Client{Timeout: exportTimeout},
+		queue:       make(chan *Span, exportQueueSize),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go e.run()
+
+	return e, nil
+}
+
+//Export queue span, span is dropped if queue is full
+func (e *batchExporter) Export(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		blog.V(3).Infof("tracing exporter queue is full, drop span %s", span.Name)
+	}
+}
+
+//Shutdown flush queued spans
+func (e *batchExporter) Shutdown() {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+		<-e.done
+	})
+}
+
+func (e *batchExporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(exportFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, exportBatchSize)
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= exportBatchSize {
+				e.flush(batch)
+				batch = make([]*Span, 0, exportBatchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				e.flush(batch)
+				batch = make([]*Span, 0, exportBatchSize)
+			}
+		case <-e.stop:
+			for {
+				select {
+				case span := <-e.queue:
+					batch = append(batch, span)
+				default:
+					if len(batch) > 0 {
+						e.flush(batch)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *batchExporter) flush(spans []*Span) {
+	body, err := e.encode(e.serviceName, spans)
+	if err != nil {
+		blog.Errorf("tracing exporter encode %d spans error %s", len(spans), err.Error())
+		return
+	}
+
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		blog.Errorf("tracing exporter post %d spans to %s error %s", len(spans), e.endpoint, err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reply, _ := ioutil.ReadAll(resp.Body)
+		blog.Errorf("tracing exporter post %d spans to %s status %d reply %s",
+			len(spans), e.endpoint, resp.StatusCode, string(reply))
+	}
+}
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []*otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+func otlpAttributes(attrs map[string]string) []*otlpKeyValue {
+	kvs := make([]*otlpKeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		kv := &otlpKeyValue{Key: k}
+		kv.Value.StringValue = v
+		kvs = append(kvs, kv)
+	}
+	return kvs
+}
+
+//encodeOTLP encode spans as OTLP/HTTP json ExportTraceServiceRequest
+func encodeOTLP(serviceName string, spans []*Span) ([]byte, error) {
+	kinds := map[SpanKind]int{SpanKindInternal: 1, SpanKindServer: 2, SpanKindClient: 3}
+
+	otlpSpans := make([]*otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		span.Lock()
+		s := &otlpSpan{
+			TraceID:           span.Context.TraceID.String(),
+			SpanID:            span.Context.SpanID.String(),
+			Name:              span.Name,
+			Kind:              kinds[span.Kind],
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            otlpStatus{Code: 1},
+		}
+		if span.ParentSpanID.IsValid() {
+			s.ParentSpanID = span.ParentSpanID.String()
+		}
+		if span.Error != "" {
+			s.Status = otlpStatus{Code: 2, Message: span.Error}
+		}
+		span.Unlock()
+		otlpSpans = append(otlpSpans, s)
+	}
+
+	request := map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": otlpAttributes(map[string]string{"service.name": serviceName}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": "bk-bcs"},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+	return json.Marshal(request)
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type zipkinSpan struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind,omitempty"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint zipkinEndpoint    `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+//encodeZipkin encode spans as zipkin v2 json
+func encodeZipkin(serviceName string, spans []*Span) ([]byte, error) {
+	kinds := map[SpanKind]string{SpanKindServer: "SERVER", SpanKindClient: "CLIENT"}
+
+	zipkinSpans := make([]*zipkinSpan, 0, len(spans))
+	for _, span := range spans {
+		span.Lock()
+		s := &zipkinSpan{
+			TraceID:       span.Context.TraceID.String(),
+			ID:            span.Context.SpanID.String(),
+			Name:          span.Name,
+			Kind:          kinds[span.Kind],
+			Timestamp:     span.StartTime.UnixNano() / int64(time.Microsecond),
+			Duration:      int64(span.EndTime.Sub(span.StartTime) / time.Microsecond),
+			LocalEndpoint: zipkinEndpoint{ServiceName: serviceName},
+			Tags:          make(map[string]string, len(span.Attributes)+1),
+		}
+		if span.ParentSpanID.IsValid() {
+			s.ParentID = span.ParentSpanID.String()
+		}
+		for k, v := range span.Attributes {
+			s.Tags[k] = v
+		}
+		if span.Error != "" {
+			s.Tags["error"] = span.Error
+		}
+		span.Unlock()
+		zipkinSpans = append(zipkinSpans, s)
+	}
+
+	return json.Marshal(zipkinSpans)
+}
@@ -0,0 +1,176 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"bk-bcs/bcs-common/common/blog"
+
+	"github.com/emicklei/go-restful"
+)
+
+//StartServerSpan extract trace context from request and start server span,
+//request id is generated if request does not carry one
+func StartServerSpan(req *http.Request) (*Span, context.Context) {
+	ctx := Extract(req.Context(), req.Header)
+	if RequestIDFromContext(ctx) == "" {
+		ctx = ContextWithRequestID(ctx, NewRequestID())
+	}
+
+	span, ctx := StartSpan(ctx, fmt.Sprintf("HTTP %s %s", req.Method, req.URL.Path), SpanKindServer)
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.url", req.URL.String())
+	span.SetAttribute("http.client_ip", req.RemoteAddr)
+	span.SetAttribute("request_id", RequestIDFromContext(ctx))
+	return span, ctx
+}
+
+//finishServerSpan record response status and finish server span
+func finishServerSpan(span *Span, status int) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	span.SetAttribute("http.status_code", strconv.Itoa(status))
+	if status >= http.StatusInternalServerError {
+		span.SetError(fmt.Errorf("http status %d", status))
+	}
+	span.Finish()
+}
+
+//RestfulFilter restful container filter, start server span for every request.
+//context of request carries the span and request id after filter
+func RestfulFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	//server span is already started by Middleware when restful container is served by mux router
+	if span := SpanFromContext(req.Request.Context()); span != nil && span.Kind == SpanKindServer {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+
+	span, ctx := StartServerSpan(req.Request)
+	req.Request = req.Request.WithContext(ctx)
+	resp.AddHeader(RequestIDHeader, RequestIDFromContext(ctx))
+
+	Logger(ctx).V(4).Info("receive request %s %s from %s", req.Request.Method, req.Request.URL.Path, req.Request.RemoteAddr)
+	chain.ProcessFilter(req, resp)
+	finishServerSpan(span, resp.StatusCode())
+	Logger(ctx).V(4).Info("finish request %s %s status %d cost %v",
+		req.Request.Method, req.Request.URL.Path, resp.StatusCode(), span.Duration())
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+//Flush support streaming response, such as watch
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+//Hijack support connection upgrade, such as websocket and kubectl exec
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijack")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+//CloseNotify support client disconnection notification
+func (w *statusWriter) CloseNotify() <-chan bool {
+	if n, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return n.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+//Middleware wrap http handler, start server span for every request
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		span, ctx := StartServerSpan(req)
+		w.Header().Set(RequestIDHeader, RequestIDFromContext(ctx))
+
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, req.WithContext(ctx))
+		finishServerSpan(span, sw.status)
+	})
+}
+
+//StartClientSpan start client span for outbound request, and inject trace context into request header
+func StartClientSpan(ctx context.Context, req *http.Request) *Span {
+	span, ctx := StartSpan(ctx, fmt.Sprintf("HTTP %s %s", req.Method, req.URL.Path), SpanKindClient)
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.url", req.URL.String())
+	Inject(ctx, req.Header)
+	return span
+}
+
+//FinishClientSpan record response of outbound request and finish client span
+func FinishClientSpan(span *Span, resp *http.Response, err error) {
+	if err != nil {
+		span.SetError(err)
+	} else if resp != nil {
+		span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode))
+		if resp.StatusCode >= http.StatusInternalServerError {
+			span.SetError(fmt.Errorf("http status %d", resp.StatusCode))
+		}
+	}
+	span.Finish()
+}
+
+//Trace run fn in an internal span, error returned by fn is recorded in span
+func Trace(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	span, ctx := StartSpan(ctx, name, SpanKindInternal)
+	err := fn(ctx)
+	span.SetError(err)
+	span.Finish()
+	return err
+}
+
+//RequestLogger return blog wrapper prefixing request id and trace id of restful request, examples:
+//	tracing.RequestLogger(req).Error("fetch application %s failed: %s", name, err.Error())
+func RequestLogger(req *restful.Request) *blog.Wrapper {
+	return Logger(req.Request.Context())
+}
+
+//Logger return blog wrapper prefixing request id and trace id of ctx, examples:
+//	tracing.Logger(req.Request.Context()).Info("create application %s", name)
+func Logger(ctx context.Context) *blog.Wrapper {
+	reqID := RequestIDFromContext(ctx)
+	sc := SpanContextFromContext(ctx)
+	if reqID == "" && !sc.IsValid() {
+		return blog.Wrap(fmt.Sprintf)
+	}
+
+	prefix := fmt.Sprintf("[request_id=%s trace_id=%s] ", reqID, sc.TraceID.String())
+	return blog.Wrap(func(format string, args ...interface{}) string {
+		return prefix + fmt.Sprintf(format, args...)
+	})
+}
@@ -0,0 +1,121 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	//TraceParentHeader W3C trace context header
+	TraceParentHeader = "traceparent"
+	//RequestIDHeader header of request id
+	RequestIDHeader = "X-Request-Id"
+
+	traceParentVersion = "00"
+	flagSampled        = 0x01
+)
+
+//FormatTraceParent format span context as W3C traceparent, examples:
+//00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func FormatTraceParent(sc SpanContext) string {
+	flags := 0
+	if sc.Sampled {
+		flags = flagSampled
+	}
+	return fmt.Sprintf("%s-%s-%s-%02x", traceParentVersion, sc.TraceID.String(), sc.SpanID.String(), flags)
+}
+
+//ParseTraceParent parse W3C traceparent header
+func ParseTraceParent(value string) (SpanContext, error) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return sc, fmt.Errorf("traceparent %s format error", value)
+	}
+	//version ff is invalid, future versions may have more fields
+	if len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == traceParentVersion && len(parts) != 4) {
+		return sc, fmt.Errorf("traceparent %s version is invalid", value)
+	}
+
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(sc.TraceID) {
+		return sc, fmt.Errorf("traceparent %s trace id is invalid", value)
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(sc.SpanID) {
+		return sc, fmt.Errorf("traceparent %s parent id is invalid", value)
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return sc, fmt.Errorf("traceparent %s flags is invalid", value)
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Sampled = flags[0]&flagSampled == flagSampled
+	if !sc.IsValid() {
+		return sc, fmt.Errorf("traceparent %s has all zero id", value)
+	}
+
+	return sc, nil
+}
+
+//Inject set traceparent and request id of ctx into http header
+func Inject(ctx context.Context, header http.Header) {
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		header.Set(TraceParentHeader, FormatTraceParent(sc))
+	}
+	if reqID := RequestIDFromContext(ctx); reqID != "" {
+		header.Set(RequestIDHeader, reqID)
+	}
+}
+
+//Extract get remote span context and request id from http header into ctx
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if value := header.Get(TraceParentHeader); value != "" {
+		if sc, err := ParseTraceParent(value); err == nil {
+			ctx = ContextWithRemoteSpanContext(ctx, sc)
+		}
+	}
+	if reqID := header.Get(RequestIDHeader); reqID != "" {
+		ctx = ContextWithRequestID(ctx, reqID)
+	}
+	return ctx
+}
+
+type requestIDKey struct{}
+
+//ContextWithRequestID return context carrying request id
+func ContextWithRequestID(ctx context.Context, reqID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, reqID)
+}
+
+//RequestIDFromContext get request id from context, empty if not exist
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	reqID, _ := ctx.Value(requestIDKey{}).(string)
+	return reqID
+}
+
+//NewRequestID generate a random request id
+func NewRequestID() string {
+	return newSpanID().String() + newSpanID().String()
+}
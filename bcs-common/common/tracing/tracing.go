@@ -0,0 +1,277 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+//Package tracing implements distributed request tracing for bcs modules.
+//Trace context is propagated by W3C traceparent header, finished spans are
+//sent to the exporter configured by InitTracer, default exporter is noop.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand"
+	"sync"
+	"time"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-common/common/conf"
+)
+
+//SpanKind kind of span
+type SpanKind string
+
+const (
+	SpanKindInternal SpanKind = "internal"
+	SpanKindServer   SpanKind = "server"
+	SpanKindClient   SpanKind = "client"
+)
+
+//TraceID 16 bytes trace id
+type TraceID [16]byte
+
+//String hex encoded trace id
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+//IsValid trace id is not all zero
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+//SpanID 8 bytes span id
+type SpanID [8]byte
+
+//String hex encoded span id
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+//IsValid span id is not all zero
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+//SpanContext the part of span propagated between processes
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+//IsValid both trace id and span id are valid
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+//Span a timed operation of one request
+type Span struct {
+	sync.Mutex
+
+	Name         string
+	Kind         SpanKind
+	Context      SpanContext
+	ParentSpanID SpanID
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]string
+	//error message if operation failed
+	Error string
+
+	finished bool
+}
+
+//SetAttribute set attribute of span
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	s.Attributes[key] = value
+	s.Unlock()
+}
+
+//SetError mark span failed
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.Lock()
+	s.Error = err.Error()
+	s.Unlock()
+}
+
+//Finish end the span and send it to exporter if sampled, span can be finished only once
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	if s.finished {
+		s.Unlock()
+		return
+	}
+	s.finished = true
+	s.EndTime = time.Now()
+	s.Unlock()
+
+	if s.Context.Sampled {
+		getTracer().exporter.Export(s)
+	}
+}
+
+//Duration time cost of finished span
+func (s *Span) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+type tracer struct {
+	serviceName string
+	sampleRatio float64
+	exporter    Exporter
+}
+
+var (
+	globalTracer = &tracer{sampleRatio: 1, exporter: &noopExporter{}}
+	tracerLock   sync.RWMutex
+)
+
+func getTracer() *tracer {
+	tracerLock.RLock()
+	defer tracerLock.RUnlock()
+	return globalTracer
+}
+
+//InitTracer init global tracer of the process by tracing config
+func InitTracer(serviceName string, config conf.TracingConfig) error {
+	name := config.TracingExporter
+	if name == "" {
+		name = NoopExporterName
+	}
+	exporter, err := NewExporter(name, serviceName, config.TracingEndpoint)
+	if err != nil {
+		return err
+	}
+
+	ratio := config.TracingSampleRatio
+	if ratio < 0 || ratio > 1 {
+		return fmt.Errorf("tracing sample ratio %v must be between 0 and 1", ratio)
+	}
+
+	tracerLock.Lock()
+	old := globalTracer.exporter
+	globalTracer = &tracer{
+		serviceName: serviceName,
+		sampleRatio: ratio,
+		exporter:    exporter,
+	}
+	tracerLock.Unlock()
+
+	old.Shutdown()
+	blog.Infof("tracing init with service %s exporter %s endpoint %s sample ratio %v",
+		serviceName, name, config.TracingEndpoint, ratio)
+	return nil
+}
+
+//Shutdown flush all spans in exporter, called before process exit
+func Shutdown() {
+	getTracer().exporter.Shutdown()
+}
+
+//ServiceName name of current service
+func ServiceName() string {
+	return getTracer().serviceName
+}
+
+type spanKey struct{}
+type remoteKey struct{}
+
+//ContextWithSpan return context carrying the span
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+//SpanFromContext get the span from context, nil if not exist
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+//ContextWithRemoteSpanContext return context carrying span context extracted from remote request
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+//SpanContextFromContext get current span context, local span first and then remote one
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.Context
+	}
+	if ctx == nil {
+		return SpanContext{}
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+//StartSpan start a span as the child of span in ctx, or a new root span if ctx has no span.
+//returned context carries the new span
+func StartSpan(ctx context.Context, name string, kind SpanKind) (*Span, context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	span := &Span{
+		Name:       name,
+		Kind:       kind,
+		StartTime:  time.Now(),
+		Attributes: make(map[string]string),
+	}
+
+	parent := SpanContextFromContext(ctx)
+	if parent.IsValid() {
+		span.Context.TraceID = parent.TraceID
+		span.Context.Sampled = parent.Sampled
+		span.ParentSpanID = parent.SpanID
+	} else {
+		span.Context.TraceID = newTraceID()
+		span.Context.Sampled = sample(getTracer().sampleRatio)
+	}
+	span.Context.SpanID = newSpanID()
+
+	return span, ContextWithSpan(ctx, span)
+}
+
+func sample(ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	return mathrand.Float64() < ratio
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,184 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+)
+
+type recordExporter struct {
+	sync.Mutex
+	spans []*Span
+}
+
+func (e *recordExporter) Export(span *Span) {
+	e.Lock()
+	defer e.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+func (e *recordExporter) Shutdown() {}
+
+func useRecordExporter() *recordExporter {
+	e := &recordExporter{}
+	tracerLock.Lock()
+	globalTracer = &tracer{serviceName: "test", sampleRatio: 1, exporter: e}
+	tracerLock.Unlock()
+	return e
+}
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		value   string
+		sampled bool
+		wantErr bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", false, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, true},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01", false, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", false, true},
+		{"invalid", false, true},
+	}
+	for _, test := range tests {
+		sc, err := ParseTraceParent(test.value)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parse %s: expect error %v, got %v", test.value, test.wantErr, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if sc.Sampled != test.sampled {
+			t.Errorf("parse %s: expect sampled %v, got %v", test.value, test.sampled, sc.Sampled)
+		}
+		if got := FormatTraceParent(sc); got != test.value {
+			t.Errorf("format %s: got %s", test.value, got)
+		}
+	}
+}
+
+func TestStartSpan(t *testing.T) {
+	e := useRecordExporter()
+
+	root, ctx := StartSpan(context.Background(), "root", SpanKindServer)
+	child, _ := StartSpan(ctx, "child", SpanKindInternal)
+	if child.Context.TraceID != root.Context.TraceID {
+		t.Errorf("child trace id %s is not the same as root %s", child.Context.TraceID, root.Context.TraceID)
+	}
+	if child.ParentSpanID != root.Context.SpanID {
+		t.Errorf("child parent id %s is not root span id %s", child.ParentSpanID, root.Context.SpanID)
+	}
+
+	child.Finish()
+	child.Finish()
+	root.Finish()
+	if len(e.spans) != 2 {
+		t.Errorf("expect 2 exported spans, got %d", len(e.spans))
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	useRecordExporter()
+
+	var serverCtx context.Context
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		serverCtx = req.Context()
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	span, ctx := StartSpan(ctx, "client", SpanKindClient)
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	Inject(ctx, req.Header)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err.Error())
+	}
+	resp.Body.Close()
+	span.Finish()
+
+	if resp.Header.Get(RequestIDHeader) != "req-1" {
+		t.Errorf("expect response request id req-1, got %s", resp.Header.Get(RequestIDHeader))
+	}
+	if RequestIDFromContext(serverCtx) != "req-1" {
+		t.Errorf("expect server request id req-1, got %s", RequestIDFromContext(serverCtx))
+	}
+	serverSpan := SpanFromContext(serverCtx)
+	if serverSpan == nil {
+		t.Fatalf("server span not found in request context")
+	}
+	if serverSpan.Context.TraceID != span.Context.TraceID || serverSpan.ParentSpanID != span.Context.SpanID {
+		t.Errorf("server span %s/%s is not child of client span %s/%s", serverSpan.Context.TraceID,
+			serverSpan.ParentSpanID, span.Context.TraceID, span.Context.SpanID)
+	}
+}
+
+func TestMiddlewareHijack(t *testing.T) {
+	useRecordExporter()
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := w.(http.Hijacker); !ok {
+			t.Errorf("response writer of middleware should support hijack")
+		}
+		if _, ok := w.(http.Flusher); !ok {
+			t.Errorf("response writer of middleware should support flush")
+		}
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %s", err.Error())
+	}
+	resp.Body.Close()
+}
+
+func TestMiddlewareWithRestfulFilter(t *testing.T) {
+	e := useRecordExporter()
+
+	container := restful.NewContainer()
+	container.Filter(RestfulFilter)
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/test").To(func(req *restful.Request, resp *restful.Response) {
+		resp.WriteHeader(http.StatusOK)
+	}))
+	container.Add(ws)
+	server := httptest.NewServer(Middleware(container))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/test")
+	if err != nil {
+		t.Fatalf("request failed: %s", err.Error())
+	}
+	resp.Body.Close()
+
+	e.Lock()
+	defer e.Unlock()
+	if len(e.spans) != 1 {
+		t.Fatalf("expect 1 server span, got %d", len(e.spans))
+	}
+	if e.spans[0].Attributes["http.status_code"] != "200" {
+		t.Errorf("expect status 200 in server span, got %s", e.spans[0].Attributes["http.status_code"])
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package types
+
+const (
+	//AgentDrainPhase_Draining taskgroups on agent are being evicted
+	AgentDrainPhase_Draining = "Draining"
+	//AgentDrainPhase_Drained no taskgroup left on agent
+	AgentDrainPhase_Drained = "Drained"
+)
+
+const (
+	//AgentDrainReason_Manual drain requested by api
+	AgentDrainReason_Manual = "manual"
+	//AgentDrainReason_Maintenance drain started ahead of mesos maintenance window
+	AgentDrainReason_Maintenance = "maintenance"
+)
+
+//AgentDrainOption option of agent drain
+type AgentDrainOption struct {
+	//max taskgroups evicting at the same time, default 1
+	Parallelism int `json:"parallelism,omitempty"`
+	//seconds evicted taskgroup waits for this agent to be uncordoned before scheduled to others,
+	//nil means using hostRetainTime in restart policy of taskgroup
+	HostRetainTime *int64 `json:"hostRetainTime,omitempty"`
+}
+
+//BcsAgentDrain progress of agent drain
+type BcsAgentDrain struct {
+	InnerIP string           `json:"innerIP"`
+	Reason  string           `json:"reason"`
+	Option  AgentDrainOption `json:"option"`
+	Phase   string           `json:"phase"`
+	//why drain is blocked now, for example disruption budget exhausted
+	Message string `json:"message,omitempty"`
+	//taskgroups still on agent and not evicted yet
+	Pending []string `json:"pending,omitempty"`
+	//taskgroups being evicted, taskgroup id -> evict unix seconds
+	Evicting map[string]int64 `json:"evicting,omitempty"`
+	//count of taskgroups evicted
+	Evicted    int   `json:"evicted"`
+	StartTime  int64 `json:"startTime"`
+	UpdateTime int64 `json:"updateTime"`
+	FinishTime int64 `json:"finishTime,omitempty"`
+}
@@ -13,6 +13,10 @@
 
 package types
 
+import (
+	"fmt"
+)
+
 type ConstraintValue_Scalar struct {
 	Value float64 `json:"value"`
 }
@@ -68,4 +72,169 @@ type ConstraintDataItem struct {
 type Constraint struct {
 	IntersectionItem []*ConstraintDataItem `json:"intersectionItem,omitempty"`
 	NodeSelector     map[string]string     `json:"nodeSelector,omitempty"`
+	//spread taskgroups evenly across topology domains, such as rack or zone
+	TopologySpreadConstraints []*TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	//soft constraints, influence ordering of offers instead of filtering
+	Preferred []*PreferredConstraint `json:"preferred,omitempty"`
+	//affinity with taskgroups of applications selected by labels
+	Affinity *ApplicationAffinity `json:"affinity,omitempty"`
+	//anti-affinity with taskgroups of applications selected by labels
+	AntiAffinity *ApplicationAffinity `json:"antiAffinity,omitempty"`
+}
+
+const (
+	//offer is not fit if the skew would be greater than maxSkew
+	WhenUnsatisfiable_DoNotSchedule = "DoNotSchedule"
+	//offer is still fit, but offers in domains with less taskgroups are preferred
+	WhenUnsatisfiable_ScheduleAnyway = "ScheduleAnyway"
+
+	//topology key for hostname of offer, other topology keys are offer attributes
+	TopologyKey_Hostname = "hostname"
+)
+
+//TopologySpreadConstraint limits the difference of taskgroup numbers between topology domains
+type TopologySpreadConstraint struct {
+	//max skew between the domain to place taskgroup and the domain with least taskgroups, must be greater than 0
+	MaxSkew int `json:"maxSkew"`
+	//hostname or offer attribute name, offers with the same value are in the same domain
+	TopologyKey string `json:"topologyKey"`
+	//DoNotSchedule or ScheduleAnyway, default DoNotSchedule
+	WhenUnsatisfiable string `json:"whenUnsatisfiable,omitempty"`
+	//labels of applications in the same namespace counted together, empty means the application itself
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
+}
+
+//PreferredConstraint is a weighted soft constraint, weight of every fit constraint is added to score of offer
+type PreferredConstraint struct {
+	//weight in range [1, 100]
+	Weight int `json:"weight"`
+	//same as one item of IntersectionItem
+	Preference *ConstraintDataItem `json:"preference"`
+}
+
+//AffinityTerm selects taskgroups of applications by labels in the topology domain of offer
+type AffinityTerm struct {
+	//labels of applications, must not be empty
+	LabelSelector map[string]string `json:"labelSelector"`
+	//namespaces of applications, empty means namespace of the application itself
+	Namespaces []string `json:"namespaces,omitempty"`
+	//hostname or offer attribute name
+	TopologyKey string `json:"topologyKey"`
+}
+
+//WeightedAffinityTerm soft affinity term
+type WeightedAffinityTerm struct {
+	//weight in range [1, 100]
+	Weight int           `json:"weight"`
+	Term   *AffinityTerm `json:"term"`
+}
+
+//ApplicationAffinity affinity or anti-affinity between applications
+type ApplicationAffinity struct {
+	//offers not fit all required terms are filtered
+	Required []*AffinityTerm `json:"required,omitempty"`
+	//offers fit preferred terms get weight added(affinity) or subtracted(anti-affinity)
+	Preferred []*WeightedAffinityTerm `json:"preferred,omitempty"`
+}
+
+//Matches check whether application labels are selected by term
+func (term *AffinityTerm) Matches(labels map[string]string) bool {
+	for k, v := range term.LabelSelector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+//HasTopology check whether constraint needs taskgroups distribution in topology domains
+func (c *Constraint) HasTopology() bool {
+	if c == nil {
+		return false
+	}
+	if len(c.TopologySpreadConstraints) > 0 {
+		return true
+	}
+	return c.Affinity != nil || c.AntiAffinity != nil
+}
+
+//HasPreference check whether offers need to be ordered by score of constraint
+func (c *Constraint) HasPreference() bool {
+	if c == nil {
+		return false
+	}
+	if len(c.TopologySpreadConstraints) > 0 || len(c.Preferred) > 0 {
+		return true
+	}
+	if c.Affinity != nil && len(c.Affinity.Preferred) > 0 {
+		return true
+	}
+	return c.AntiAffinity != nil && len(c.AntiAffinity.Preferred) > 0
+}
+
+//Validate check topology spread constraints, preferred constraints and affinity terms
+func (c *Constraint) Validate() error {
+	for i, spread := range c.TopologySpreadConstraints {
+		if spread == nil {
+			return fmt.Errorf("topologySpreadConstraints[%d] is nil", i)
+		}
+		if spread.MaxSkew <= 0 {
+			return fmt.Errorf("topologySpreadConstraints[%d] maxSkew must be greater than 0", i)
+		}
+		if spread.TopologyKey == "" {
+			return fmt.Errorf("topologySpreadConstraints[%d] topologyKey must be set", i)
+		}
+		if spread.WhenUnsatisfiable != "" && spread.WhenUnsatisfiable != WhenUnsatisfiable_DoNotSchedule &&
+			spread.WhenUnsatisfiable != WhenUnsatisfiable_ScheduleAnyway {
+			return fmt.Errorf("topologySpreadConstraints[%d] whenUnsatisfiable %s is invalid", i, spread.WhenUnsatisfiable)
+		}
+	}
+	for i, preferred := range c.Preferred {
+		if preferred == nil || preferred.Preference == nil {
+			return fmt.Errorf("preferred[%d] preference must be set", i)
+		}
+		if preferred.Weight < 1 || preferred.Weight > 100 {
+			return fmt.Errorf("preferred[%d] weight must be in range [1, 100]", i)
+		}
+	}
+	if err := c.Affinity.validate("affinity"); err != nil {
+		return err
+	}
+	return c.AntiAffinity.validate("antiAffinity")
+}
+
+func (affinity *ApplicationAffinity) validate(field string) error {
+	if affinity == nil {
+		return nil
+	}
+	for i, term := range affinity.Required {
+		if err := term.validate(); err != nil {
+			return fmt.Errorf("%s.required[%d] %s", field, i, err.Error())
+		}
+	}
+	for i, weighted := range affinity.Preferred {
+		if weighted == nil {
+			return fmt.Errorf("%s.preferred[%d] is nil", field, i)
+		}
+		if weighted.Weight < 1 || weighted.Weight > 100 {
+			return fmt.Errorf("%s.preferred[%d] weight must be in range [1, 100]", field, i)
+		}
+		if err := weighted.Term.validate(); err != nil {
+			return fmt.Errorf("%s.preferred[%d] %s", field, i, err.Error())
+		}
+	}
+	return nil
+}
+
+func (term *AffinityTerm) validate() error {
+	if term == nil {
+		return fmt.Errorf("term is nil")
+	}
+	if len(term.LabelSelector) == 0 {
+		return fmt.Errorf("labelSelector must be set")
+	}
+	if term.TopologyKey == "" {
+		return fmt.Errorf("topologyKey must be set")
+	}
+	return nil
 }
@@ -13,13 +13,106 @@
 
 package types
 
+//CrrScope scope of custom resource, Namespaced or Cluster
+type CrrScope string
+
+const (
+	CrrScopeNamespaced CrrScope = "Namespaced"
+	CrrScopeCluster    CrrScope = "Cluster"
+)
+
 //custom resource register spec
 type CrrSpec struct {
 	Names CrrSpecName `json:"names"`
+	//scope of custom resource, default Namespaced
+	Scope CrrScope `json:"scope,omitempty"`
+	//all versions of custom resource, only one version can be storage version
+	Versions []*CrrVersion `json:"versions,omitempty"`
+	//conversion between versions, default None
+	Conversion *CrrConversion `json:"conversion,omitempty"`
 }
 
 type CrrSpecName struct {
 	Kind string `json:"kind"`
+	//plural name, examples: mysqlclusters
+	Plural string `json:"plural,omitempty"`
+	//short names, examples: ["mc"]
+	ShortNames []string `json:"shortNames,omitempty"`
+}
+
+//CrrVersion one version of custom resource
+type CrrVersion struct {
+	//version name, examples: v1alpha1, v1
+	Name string `json:"name"`
+	//whether version is served by the scheduler api
+	Served bool `json:"served"`
+	//whether custom resource is persisted in this version
+	Storage bool `json:"storage"`
+	//schema for validation and defaulting
+	Schema *CrrValidation `json:"schema,omitempty"`
+	//columns shown by client when list custom resources
+	AdditionalPrinterColumns []*CrrPrinterColumn `json:"additionalPrinterColumns,omitempty"`
+}
+
+//CrrValidation validation schema of custom resource version
+type CrrValidation struct {
+	OpenAPIV3Schema *JSONSchemaProps `json:"openAPIV3Schema,omitempty"`
+}
+
+//CrrPrinterColumn column shown by client
+type CrrPrinterColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+	Priority    int32  `json:"priority,omitempty"`
+	//json path in custom resource, examples: .spec.replicas
+	JSONPath string `json:"JSONPath"`
+}
+
+//JSONSchemaProps subset of OpenAPI v3 schema
+type JSONSchemaProps struct {
+	Type                 string                      `json:"type,omitempty"`
+	Format               string                      `json:"format,omitempty"`
+	Description          string                      `json:"description,omitempty"`
+	Default              interface{}                 `json:"default,omitempty"`
+	Nullable             bool                        `json:"nullable,omitempty"`
+	Enum                 []interface{}               `json:"enum,omitempty"`
+	Maximum              *float64                    `json:"maximum,omitempty"`
+	Minimum              *float64                    `json:"minimum,omitempty"`
+	MaxLength            *int64                      `json:"maxLength,omitempty"`
+	MinLength            *int64                      `json:"minLength,omitempty"`
+	Pattern              string                      `json:"pattern,omitempty"`
+	MaxItems             *int64                      `json:"maxItems,omitempty"`
+	MinItems             *int64                      `json:"minItems,omitempty"`
+	Required             []string                    `json:"required,omitempty"`
+	Items                *JSONSchemaProps            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchemaProps `json:"properties,omitempty"`
+	AdditionalProperties *bool                       `json:"additionalProperties,omitempty"`
+}
+
+//CrrConversionStrategy conversion strategy between custom resource versions
+type CrrConversionStrategy string
+
+const (
+	//only apiVersion is changed
+	CrrConversionNone CrrConversionStrategy = "None"
+	//request webhook to convert custom resource
+	CrrConversionWebhook CrrConversionStrategy = "Webhook"
+)
+
+//CrrConversion conversion of custom resource
+type CrrConversion struct {
+	Strategy            CrrConversionStrategy   `json:"strategy"`
+	WebhookClientConfig *CrrWebhookClientConfig `json:"webhookClientConfig,omitempty"`
+}
+
+//CrrWebhookClientConfig conversion webhook client config
+type CrrWebhookClientConfig struct {
+	//webhook url, examples: https://127.0.0.1:31000/convert
+	URL string `json:"url"`
+	//pem encoded ca cert that signs the server cert used by the webhook, base64 encoded
+	CaBundle string `json:"caBundle,omitempty"`
 }
 
 //custom resource register
@@ -28,6 +121,26 @@ type Crr struct {
 	Spec     CrrSpec `json:"spec"`
 }
 
+//GetVersion get custom resource version by name, return nil if not found
+func (crr *Crr) GetVersion(name string) *CrrVersion {
+	for _, v := range crr.Spec.Versions {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+//GetStorageVersion get storage version, return nil if no version registered
+func (crr *Crr) GetStorageVersion() *CrrVersion {
+	for _, v := range crr.Spec.Versions {
+		if v.Storage {
+			return v
+		}
+	}
+	return nil
+}
+
 //custom resource definition
 type Crd struct {
 	TypeMeta   `json:",inline"`
@@ -37,3 +150,30 @@ type Crd struct {
 
 	Status interface{} `json:"status,omitempty"`
 }
+
+//CrdConversionReview conversion webhook request&response
+type CrdConversionReview struct {
+	Request  *CrdConversionRequest  `json:"request,omitempty"`
+	Response *CrdConversionResponse `json:"response,omitempty"`
+}
+
+//CrdConversionRequest conversion webhook request
+type CrdConversionRequest struct {
+	UID               string `json:"uid"`
+	DesiredAPIVersion string `json:"desiredAPIVersion"`
+	Objects           []*Crd `json:"objects"`
+}
+
+//CrdConversionResponse conversion webhook response
+type CrdConversionResponse struct {
+	UID              string `json:"uid"`
+	ConvertedObjects []*Crd `json:"convertedObjects"`
+	//Success or Failure
+	Result  string `json:"result"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	CrdConversionResultSuccess = "Success"
+	CrdConversionResultFailure = "Failure"
+)
@@ -0,0 +1,136 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//BcsDisruptionBudget limits voluntary disruptions, such as taskgroup reschedule,
+//agent drain and deployment rolling update, of applications matched by selector
+type BcsDisruptionBudget struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata"`
+	Spec       *DisruptionBudgetSpec   `json:"spec"`
+	Status     *DisruptionBudgetStatus `json:"status,omitempty"`
+}
+
+//DisruptionBudgetSpec only one of MinAvailable and MaxUnavailable can be set
+type DisruptionBudgetSpec struct {
+	//min healthy taskgroups after disruption, number or percentage of expected taskgroups
+	MinAvailable DisruptionValue `json:"minAvailable,omitempty"`
+	//max unavailable taskgroups after disruption, number or percentage of expected taskgroups
+	MaxUnavailable DisruptionValue `json:"maxUnavailable,omitempty"`
+	//labels of applications in the same namespace, empty selector matches all applications
+	Selector map[string]string `json:"selector,omitempty"`
+}
+
+//DisruptionBudgetStatus current state of budget, computed by scheduler
+type DisruptionBudgetStatus struct {
+	//taskgroups of all matched applications
+	ExpectedTaskgroups int `json:"expectedTaskgroups"`
+	//taskgroups running, healthy and ready
+	CurrentHealthy int `json:"currentHealthy"`
+	//min healthy taskgroups required by budget
+	DesiredHealthy int `json:"desiredHealthy"`
+	//how many taskgroups can be disrupted now
+	DisruptionsAllowed int `json:"disruptionsAllowed"`
+	//taskgroups disrupted recently but not reflected in status yet
+	DisruptedTaskgroups []string `json:"disruptedTaskgroups,omitempty"`
+	UpdateTime          int64    `json:"updateTime"`
+}
+
+//Validate check spec of budget
+func (budget *BcsDisruptionBudget) Validate() error {
+	if budget.ObjectMeta.Name == "" || budget.ObjectMeta.NameSpace == "" {
+		return fmt.Errorf("name and namespace of disruption budget must be set")
+	}
+	if budget.Spec == nil {
+		return fmt.Errorf("spec of disruption budget must be set")
+	}
+	if (budget.Spec.MinAvailable == "") == (budget.Spec.MaxUnavailable == "") {
+		return fmt.Errorf("one and only one of minAvailable and maxUnavailable must be set")
+	}
+	if _, err := budget.Spec.MinAvailable.Resolve(0); budget.Spec.MinAvailable != "" && err != nil {
+		return fmt.Errorf("minAvailable invalid: %s", err.Error())
+	}
+	if _, err := budget.Spec.MaxUnavailable.Resolve(0); budget.Spec.MaxUnavailable != "" && err != nil {
+		return fmt.Errorf("maxUnavailable invalid: %s", err.Error())
+	}
+	return nil
+}
+
+//Matches check whether application labels are selected by budget
+func (spec *DisruptionBudgetSpec) Matches(labels map[string]string) bool {
+	for k, v := range spec.Selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+//DesiredHealthy min healthy taskgroups required when expected taskgroups exist
+func (spec *DisruptionBudgetSpec) DesiredHealthy(expected int) (int, error) {
+	if spec.MinAvailable != "" {
+		return spec.MinAvailable.Resolve(expected)
+	}
+	maxUnavailable, err := spec.MaxUnavailable.Resolve(expected)
+	if err != nil {
+		return 0, err
+	}
+	if maxUnavailable > expected {
+		return 0, nil
+	}
+	return expected - maxUnavailable, nil
+}
+
+//DisruptionValue number like 2 or percentage like "50%"
+type DisruptionValue string
+
+//UnmarshalJSON accept both json number and string
+func (v *DisruptionValue) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*v = DisruptionValue(strconv.Itoa(number))
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("disruption value must be number or percentage string")
+	}
+	*v = DisruptionValue(str)
+	return nil
+}
+
+//Resolve absolute value of total, percentage is rounded up
+func (v DisruptionValue) Resolve(total int) (int, error) {
+	str := strings.TrimSpace(string(v))
+	if strings.HasSuffix(str, "%") {
+		percent, err := strconv.Atoi(strings.TrimSuffix(str, "%"))
+		if err != nil || percent < 0 || percent > 100 {
+			return 0, fmt.Errorf("invalid percentage %s", str)
+		}
+		return int(math.Ceil(float64(total) * float64(percent) / 100)), nil
+	}
+	number, err := strconv.Atoi(str)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid value %s", str)
+	}
+	return number, nil
+}
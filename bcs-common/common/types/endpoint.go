@@ -27,6 +27,12 @@ type Endpoint struct {
 	ContainerIP string          `json:"containerIP"`
 	Target      TargetRef       `json:"targetRef,omitempty"`
 	Ports       []ContainerPort `json:"ports,omitempty"`
+	//Healthy is nil when no health check is defined for endpoint
+	Healthy *bool `json:"healthy,omitempty"`
+	//Ready is nil when no readiness check is defined for endpoint
+	Ready *bool `json:"ready,omitempty"`
+	//NodeAttributes text attributes of the node endpoint running on, like zone
+	NodeAttributes map[string]string `json:"nodeAttributes,omitempty"`
 }
 
 //TargetRef referrence for endpoint
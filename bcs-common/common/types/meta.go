@@ -32,6 +32,7 @@ const (
 	BcsDataType_CRR              BcsDataType = "crr"
 	BcsDataType_WebConsole       BcsDataType = "webconsole"
 	BcsDataType_Admissionwebhook BcsDataType = "admissionwebhook"
+	BcsDataType_DisruptionBudget BcsDataType = "disruptionbudget"
 )
 
 //TypeMeta for bcs data type
@@ -166,10 +166,45 @@ type Container struct {
 	WorkingDir      string               `json:"workingDir,omitempty"`
 	Ports           []ContainerPort      `json:"ports,omitempty"`
 	HealthChecks    []*HealthCheck       `json:"healthChecks,omitempty"`
+	ReadinessChecks []*HealthCheck       `json:"readinessChecks,omitempty"`
+	StartupChecks   []*HealthCheck       `json:"startupChecks,omitempty"`
 	Resources       ResourceRequirements `json:"resources,omitempty"`
 	Volumes         []VolumeUnit         `json:"volumes,omitempty"`
 	ConfigMaps      []ConfigMap          `json:"configmaps,omitempty"`
 	Secrets         []Secret             `json:"secrets,omitempty"`
+	Lifecycle       *Lifecycle           `json:"lifecycle,omitempty"`
+}
+
+//Lifecycle actions executor takes in response to container lifecycle events
+type Lifecycle struct {
+	//PostStart called immediately after container started, container is
+	//killed if handler failed
+	PostStart *LifecycleHandler `json:"postStart,omitempty"`
+	//PreStop called before container stopped, StopContainer waits for handler
+	//within KillPolicy.GracePeriod
+	PreStop *LifecycleHandler `json:"preStop,omitempty"`
+}
+
+//LifecycleHandler exec command or http get request, only one of Exec and Http works
+type LifecycleHandler struct {
+	Exec           *ExecAction    `json:"exec,omitempty"`
+	Http           *HttpGetAction `json:"http,omitempty"`
+	TimeoutSeconds int            `json:"timeoutSeconds,omitempty"`
+}
+
+//ExecAction command executed in container, exit code 0 is treated as success
+type ExecAction struct {
+	Command []string `json:"command"`
+}
+
+//HttpGetAction http get request, status code in [200, 400) is treated as success.
+//Host defaults to container ip address
+type HttpGetAction struct {
+	Host    string            `json:"host,omitempty"`
+	Port    int32             `json:"port"`
+	Scheme  string            `json:"scheme,omitempty"`
+	Path    string            `json:"path,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
 // a single process that is expected to be run on the host
@@ -208,11 +243,13 @@ type Uri struct {
 
 type PodSpec struct {
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
-	Containers   []Container       `json:"containers,omitempty"`
-	Processes    []Process         `json:"processes,omitempty"`
-	NetworkMode  string            `json:"networkMode,omitempty"`
-	NetworkType  string            `json:"networktype,omitempty"`
-	NetLimit     *NetLimit         `json:"netLimit,omitempty"`
+	//InitContainers run to completion in order before Containers start
+	InitContainers []Container `json:"initContainers,omitempty"`
+	Containers     []Container `json:"containers,omitempty"`
+	Processes      []Process   `json:"processes,omitempty"`
+	NetworkMode    string      `json:"networkMode,omitempty"`
+	NetworkType    string      `json:"networktype,omitempty"`
+	NetLimit       *NetLimit   `json:"netLimit,omitempty"`
 }
 
 //PodTemplateSpec specification for pod
@@ -70,6 +70,7 @@ type ExtraKind string
 
 const (
 	ApplicationExtraKind ExtraKind = "application"
+	UserExtraKind        ExtraKind = "user"
 )
 
 type EventEnv string
@@ -82,7 +83,8 @@ const (
 type EventKind string
 
 const (
-	TaskEventKind EventKind = "task"
+	TaskEventKind  EventKind = "task"
+	AuditEventKind EventKind = "audit"
 )
 
 type EventLevel string
@@ -97,6 +99,7 @@ type EventComponent string
 const (
 	Event_Component_Scheduler  EventComponent = "scheduler"
 	Event_Component_Controller EventComponent = "controller"
+	Event_Component_Api        EventComponent = "api"
 )
 
 // BcsStorageClusterIf define storage config interface data interaction
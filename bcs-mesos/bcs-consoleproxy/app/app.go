@@ -23,6 +23,7 @@ import (
 	"bk-bcs/bcs-mesos/bcs-consoleproxy/console-proxy/config"
 	"bk-bcs/bcs-mesos/bcs-consoleproxy/console-proxy/manager"
 	"os"
+	"time"
 )
 
 type ConsoleProxy struct {
@@ -70,6 +71,13 @@ func setConfig(op *options.ConsoleProxyOption) {
 	op.Conf.Cmd = op.Cmd
 	op.Conf.Ips = op.Ips
 	op.Conf.IsAuth = op.IsAuth
+	op.Conf.ClusterID = op.ClusterID
+	op.Conf.RecordDir = op.RecordDir
+	op.Conf.RecordRetention = time.Duration(op.RecordRetention) * 24 * time.Hour
+	op.Conf.IdleTimeout = time.Duration(op.IdleTimeout) * time.Second
+	op.Conf.MaxSessionDuration = time.Duration(op.MaxSessionDuration) * time.Second
+	op.Conf.MaxSessionsPerUser = op.MaxSessionsPerUser
+	op.Conf.AdminIps = op.AdminIps
 
 	//server cert directoty
 	if op.CertConfig.ServerCertFile != "" && op.CertConfig.CAFile != "" &&
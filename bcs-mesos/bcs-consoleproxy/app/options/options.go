@@ -33,6 +33,14 @@ type ConsoleProxyOption struct {
 	DockerEndpoint string   `json:"docker-endpoint" value:"" usage:"docker endpoint"`
 	Ips            []string `json:"ips" value:"" usage:"IP white list"`
 	IsAuth         bool     `json:"is-auth" value:"" usage:"is auth"`
+	ClusterID      string   `json:"cluster-id" value:"" usage:"cluster id of this agent, requests from bcs-api for other clusters are rejected, requests from bcs-api are all rejected if empty"`
+
+	RecordDir          string   `json:"record-dir" value:"./records" usage:"directory to save console session records in asciinema format, empty to disable recording"`
+	RecordRetention    int      `json:"record-retention" value:"30" usage:"days to keep console session records, 0 to keep forever"`
+	IdleTimeout        int      `json:"idle-timeout" value:"1800" usage:"seconds a console session can be idle before terminated, 0 for no limit"`
+	MaxSessionDuration int      `json:"max-session-duration" value:"0" usage:"max seconds of a console session, 0 for no limit"`
+	MaxSessionsPerUser int      `json:"max-sessions-per-user" value:"10" usage:"max concurrent console sessions of each user on this agent, 0 for no limit"`
+	AdminIps           []string `json:"admin-ips" value:"" usage:"IP white list of session admin api, only local requests are allowed if empty, requests proxied by bcs-api are never allowed"`
 
 	Conf config.ConsoleProxyConfig
 }
@@ -16,7 +16,10 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"bk-bcs/bcs-common/common/blog"
 	"bk-bcs/bcs-common/common/ssl"
@@ -30,6 +33,17 @@ type Router struct {
 	backend manager.Manager
 }
 
+const (
+	//UsernameHeader is set by bcs-api with the authenticated user who operates the console
+	UsernameHeader = "X-Bcs-Username"
+	//NamespaceHeader is set by bcs-api with the namespace which the user is authorized to operate
+	NamespaceHeader = "X-Bcs-Namespace"
+	//ClusterHeader is set by bcs-api with the cluster which the user is authorized to operate
+	ClusterHeader = "X-Bcs-Cluster-Id"
+
+	anonymousUser = "anonymous"
+)
+
 type CreateExecReq struct {
 	ContainerID string   `json:"container_id,omitempty"`
 	Cmd         []string `json:"cmd,omitempty"`
@@ -60,6 +74,13 @@ func (r *Router) initRoutes() {
 	mux.HandleFunc("/bcsapi/v1/consoleproxy/create_exec", r.createExec)
 	mux.HandleFunc("/bcsapi/v1/consoleproxy/start_exec", r.startExec)
 	mux.HandleFunc("/bcsapi/v1/consoleproxy/resize_exec", r.resizeExec)
+	//container logs and file transfer
+	mux.HandleFunc("/bcsapi/v1/consoleproxy/logs", r.containerLogs)
+	mux.HandleFunc("/bcsapi/v1/consoleproxy/copy_from", r.copyFromContainer)
+	mux.HandleFunc("/bcsapi/v1/consoleproxy/copy_to", r.copyToContainer)
+	//admin of live console sessions
+	mux.HandleFunc("/bcsapi/v1/consoleproxy/sessions", r.listSessions)
+	mux.HandleFunc("/bcsapi/v1/consoleproxy/sessions/", r.terminateSession)
 	s := &http.Server{
 		Addr:    fmt.Sprintf("%s:%d", r.conf.Address, r.conf.Port),
 		Handler: mux,
@@ -86,6 +107,9 @@ func (r *Router) initRoutes() {
 }
 
 func (r *Router) createExec(w http.ResponseWriter, req *http.Request) {
+	if !r.checkSource(w, req) {
+		return
+	}
 
 	var createExecReq CreateExecReq
 	decoder := json.NewDecoder(req.Body)
@@ -110,18 +134,28 @@ func (r *Router) createExec(w http.ResponseWriter, req *http.Request) {
 		ContainerId: createExecReq.ContainerID,
 		User:        createExecReq.User,
 		Cmd:         createExecReq.Cmd,
+		Namespace:   req.Header.Get(NamespaceHeader),
 	}
 
 	r.backend.CreateExec(w, req, webconsole)
 }
 
 func (r *Router) startExec(w http.ResponseWriter, req *http.Request) {
+	if !r.checkSource(w, req) {
+		return
+	}
 	req.ParseForm()
 	execId := req.FormValue("exec_id")
 
 	webconsole := &types.WebSocketConfig{
-		ExecId: execId,
-		Origin: req.Header.Get("Origin"),
+		ExecId:     execId,
+		Origin:     req.Header.Get("Origin"),
+		Username:   req.Header.Get(UsernameHeader),
+		RemoteAddr: remoteAddr(req),
+		Namespace:  req.Header.Get(NamespaceHeader),
+	}
+	if webconsole.Username == "" {
+		webconsole.Username = anonymousUsername(req)
 	}
 
 	// handler container web console
@@ -129,6 +163,9 @@ func (r *Router) startExec(w http.ResponseWriter, req *http.Request) {
 }
 
 func (r *Router) resizeExec(w http.ResponseWriter, req *http.Request) {
+	if !r.checkSource(w, req) {
+		return
+	}
 
 	var resizeExecReq ResizeExecReq
 	decoder := json.NewDecoder(req.Body)
@@ -143,10 +180,187 @@ func (r *Router) resizeExec(w http.ResponseWriter, req *http.Request) {
 	}
 
 	webconsole := &types.WebSocketConfig{
-		ExecId: resizeExecReq.ExecId,
-		Height: resizeExecReq.Height,
-		Width:  resizeExecReq.Width,
+		ExecId:    resizeExecReq.ExecId,
+		Height:    resizeExecReq.Height,
+		Width:     resizeExecReq.Width,
+		Namespace: req.Header.Get(NamespaceHeader),
 	}
 
 	r.backend.ResizeExec(w, req, webconsole)
 }
+
+func (r *Router) containerLogs(w http.ResponseWriter, req *http.Request) {
+	if !r.checkSource(w, req) {
+		return
+	}
+	req.ParseForm()
+	containerId := req.FormValue("container_id")
+	if containerId == "" {
+		http.Error(w, "container_id must be provided", http.StatusBadRequest)
+		return
+	}
+
+	logsConf := &types.LogsConfig{
+		ContainerId: containerId,
+		Follow:      req.FormValue("follow") == "true",
+		Tail:        req.FormValue("tail"),
+		Timestamps:  req.FormValue("timestamps") == "true",
+		Namespace:   req.Header.Get(NamespaceHeader),
+	}
+	if since := req.FormValue("since"); since != "" {
+		var err error
+		logsConf.Since, err = strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid since %s: %s", since, err.Error()), http.StatusBadRequest)
+			return
+		}
+	}
+
+	r.backend.ContainerLogs(w, req, logsConf)
+}
+
+func (r *Router) copyFromContainer(w http.ResponseWriter, req *http.Request) {
+	if !r.checkSource(w, req) {
+		return
+	}
+	copyConf, err := parseCopyConfig(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.backend.CopyFromContainer(w, req, copyConf)
+}
+
+func (r *Router) copyToContainer(w http.ResponseWriter, req *http.Request) {
+	if !r.checkSource(w, req) {
+		return
+	}
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		http.Error(w, "copy_to only supports PUT or POST with tar archive in body", http.StatusMethodNotAllowed)
+		return
+	}
+
+	copyConf, err := parseCopyConfig(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.backend.CopyToContainer(w, req, copyConf)
+}
+
+func parseCopyConfig(req *http.Request) (*types.CopyConfig, error) {
+	query := req.URL.Query()
+	copyConf := &types.CopyConfig{
+		ContainerId: query.Get("container_id"),
+		Path:        query.Get("path"),
+		Namespace:   req.Header.Get(NamespaceHeader),
+	}
+	if copyConf.ContainerId == "" {
+		return nil, fmt.Errorf("container_id must be provided")
+	}
+	if copyConf.Path == "" {
+		return nil, fmt.Errorf("path must be provided")
+	}
+	return copyConf, nil
+}
+
+func (r *Router) listSessions(w http.ResponseWriter, req *http.Request) {
+	if !r.isAdmin(req) {
+		http.Error(w, "session admin api is not allowed from "+req.RemoteAddr, http.StatusForbidden)
+		return
+	}
+	if req.Method != http.MethodGet {
+		http.Error(w, "sessions only supports GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.backend.ListSessions(w, req)
+}
+
+func (r *Router) terminateSession(w http.ResponseWriter, req *http.Request) {
+	if !r.isAdmin(req) {
+		http.Error(w, "session admin api is not allowed from "+req.RemoteAddr, http.StatusForbidden)
+		return
+	}
+	if req.Method != http.MethodDelete {
+		http.Error(w, "terminating session only supports DELETE", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(req.URL.Path, "/bcsapi/v1/consoleproxy/sessions/")
+	if id == "" || strings.Contains(id, "/") {
+		http.Error(w, "session id must be provided", http.StatusBadRequest)
+		return
+	}
+
+	r.backend.TerminateSession(w, req, id)
+}
+
+//checkSource allows the container operations from bcs-api for the cluster of this agent, which has authorized
+//the namespace, and from admin sources without namespace. The response is written if it fails.
+func (r *Router) checkSource(w http.ResponseWriter, req *http.Request) bool {
+	if req.Header.Get(NamespaceHeader) == "" {
+		if !r.isAdmin(req) {
+			http.Error(w, fmt.Sprintf("header %s must be provided for requests from %s", NamespaceHeader, req.RemoteAddr),
+				http.StatusForbidden)
+			return false
+		}
+		return true
+	}
+	if cluster := req.Header.Get(ClusterHeader); r.conf.ClusterID == "" || cluster != r.conf.ClusterID {
+		http.Error(w, fmt.Sprintf("cluster %q is not served by this agent", cluster), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+//isAdmin allows the session admin api from loopback and the admin ip white list. Requests proxied by bcs-api
+//come from users, they are never allowed even if bcs-api is in the white list.
+func (r *Router) isAdmin(req *http.Request) bool {
+	if isProxied(req) {
+		return false
+	}
+	host := remoteHost(req)
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+	for _, adminIP := range r.conf.AdminIps {
+		if adminIP == host {
+			return true
+		}
+	}
+	return false
+}
+
+//isProxied returns true if the request is forwarded by bcs-api or any other proxy
+func isProxied(req *http.Request) bool {
+	return req.Header.Get(UsernameHeader) != "" || req.Header.Get(NamespaceHeader) != "" ||
+		req.Header.Get(ClusterHeader) != "" || req.Header.Get("X-Forwarded-For") != ""
+}
+
+//remoteHost is the host of peer address, without port
+func remoteHost(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+//anonymousUsername names the user of unauthenticated session by the peer address, so that anonymous users
+//from different hosts are limited separately. X-Forwarded-For is not used as it can be forged by the client.
+func anonymousUsername(req *http.Request) string {
+	return fmt.Sprintf("%s@%s", anonymousUser, remoteHost(req))
+}
+
+//remoteAddr is the client address if the request is forwarded by bcs-api, which is the last hop appended to
+//X-Forwarded-For by bcs-api. The earlier entries are sent by the client and can be forged, so they are not used.
+func remoteAddr(req *http.Request) string {
+	if forwarded := req.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		return strings.TrimSpace(hops[len(hops)-1])
+	}
+	return req.RemoteAddr
+}
@@ -0,0 +1,109 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"bk-bcs/bcs-mesos/bcs-consoleproxy/console-proxy/config"
+)
+
+func TestIsAdmin(t *testing.T) {
+	r := &Router{conf: &config.ConsoleProxyConfig{AdminIps: []string{"10.0.0.1"}}}
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       bool
+	}{
+		{name: "loopback", remoteAddr: "127.0.0.1:3000", want: true},
+		{name: "white list", remoteAddr: "10.0.0.1:3000", want: true},
+		{name: "not in white list", remoteAddr: "10.0.0.2:3000", want: false},
+		{name: "proxied user", remoteAddr: "10.0.0.1:3000", headers: map[string]string{UsernameHeader: "alice"}, want: false},
+		{name: "proxied namespace", remoteAddr: "10.0.0.1:3000", headers: map[string]string{NamespaceHeader: "ns1"}, want: false},
+		{name: "forwarded", remoteAddr: "127.0.0.1:3000", headers: map[string]string{"X-Forwarded-For": "10.0.0.3"}, want: false},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "/bcsapi/v1/consoleproxy/sessions", nil)
+		req.RemoteAddr = test.remoteAddr
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		if got := r.isAdmin(req); got != test.want {
+			t.Errorf("%s: isAdmin() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestAnonymousUsername(t *testing.T) {
+	req := httptest.NewRequest("GET", "/bcsapi/v1/consoleproxy/start_exec", nil)
+	req.RemoteAddr = "10.0.0.1:3000"
+	req.Header.Set("X-Forwarded-For", "10.0.0.2")
+	if got := anonymousUsername(req); got != "anonymous@10.0.0.1" {
+		t.Errorf("anonymousUsername() = %s, want anonymous@10.0.0.1", got)
+	}
+}
+
+func TestRemoteAddr(t *testing.T) {
+	req := httptest.NewRequest("GET", "/bcsapi/v1/consoleproxy/start_exec", nil)
+	req.RemoteAddr = "10.0.0.1:3000"
+	if got := remoteAddr(req); got != "10.0.0.1:3000" {
+		t.Errorf("remoteAddr() = %s, want 10.0.0.1:3000", got)
+	}
+	// the first entry is forged by the client, the last one is appended by bcs-api
+	req.Header.Set("X-Forwarded-For", "1.1.1.1, 10.0.0.2")
+	if got := remoteAddr(req); got != "10.0.0.2" {
+		t.Errorf("remoteAddr() = %s, want 10.0.0.2", got)
+	}
+}
+
+func TestCheckSource(t *testing.T) {
+	r := &Router{conf: &config.ConsoleProxyConfig{AdminIps: []string{"10.0.0.1"}, ClusterID: "BCS-MESOS-10001"}}
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       bool
+	}{
+		{name: "admin without namespace", remoteAddr: "10.0.0.1:3000", want: true},
+		{name: "loopback without namespace", remoteAddr: "127.0.0.1:3000", want: true},
+		{name: "others without namespace", remoteAddr: "10.0.0.2:3000", want: false},
+		{name: "proxied without namespace", remoteAddr: "10.0.0.1:3000", headers: map[string]string{UsernameHeader: "alice"}, want: false},
+		{name: "proxied for this cluster", remoteAddr: "10.0.0.2:3000",
+			headers: map[string]string{NamespaceHeader: "ns1", ClusterHeader: "BCS-MESOS-10001"}, want: true},
+		{name: "proxied for other cluster", remoteAddr: "10.0.0.2:3000",
+			headers: map[string]string{NamespaceHeader: "ns1", ClusterHeader: "BCS-MESOS-10002"}, want: false},
+		{name: "proxied without cluster", remoteAddr: "10.0.0.2:3000", headers: map[string]string{NamespaceHeader: "ns1"}, want: false},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "/bcsapi/v1/consoleproxy/logs", nil)
+		req.RemoteAddr = test.remoteAddr
+		for k, v := range test.headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		if got := r.checkSource(rec, req); got != test.want {
+			t.Errorf("%s: checkSource() = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	// requests from bcs-api are all rejected if cluster of the agent is not configured
+	r.conf.ClusterID = ""
+	req := httptest.NewRequest("GET", "/bcsapi/v1/consoleproxy/logs", nil)
+	req.Header.Set(NamespaceHeader, "ns1")
+	if r.checkSource(httptest.NewRecorder(), req) {
+		t.Errorf("expect requests from bcs-api rejected without cluster id configured")
+	}
+}
@@ -14,6 +14,8 @@
 package config
 
 import (
+	"time"
+
 	"bk-bcs/bcs-common/common/static"
 )
 
@@ -39,6 +41,16 @@ type ConsoleProxyConfig struct {
 	Tty            bool
 	Ips            []string
 	IsAuth         bool
+	// ClusterID is the cluster of this agent, requests proxied by bcs-api must be authorized for it
+	ClusterID string
+
+	// session recording and limits, zero value means no limit
+	RecordDir          string
+	RecordRetention    time.Duration
+	IdleTimeout        time.Duration
+	MaxSessionDuration time.Duration
+	MaxSessionsPerUser int
+	AdminIps           []string
 }
 
 //NewContainerWareConfig create a config object
@@ -17,6 +17,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"sync"
+	"time"
 
 	"bk-bcs/bcs-common/common/blog"
 	"bk-bcs/bcs-mesos/bcs-consoleproxy/console-proxy/types"
@@ -25,12 +27,20 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+const (
+	// closeTimeout is the deadline of sending close message to the hijacked connection
+	closeTimeout = time.Second
+)
+
 type errMsg struct {
 	Msg string `json:"msg,omitempty"`
 }
 
+// wsConn adapts websocket to io.ReadWriter. Output of exec and the message of session closer are written by
+// different goroutines, while websocket supports only one concurrent writer, so writes are serialized
 type wsConn struct {
-	conn *websocket.Conn
+	conn      *websocket.Conn
+	writeLock sync.Mutex
 }
 
 func (c *wsConn) Read(p []byte) (n int, err error) {
@@ -42,6 +52,8 @@ func (c *wsConn) Read(p []byte) (n int, err error) {
 }
 
 func (c *wsConn) Write(p []byte) (n int, err error) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
 	wc, err := c.conn.NextWriter(websocket.TextMessage)
 	if err != nil {
 		return 0, err
@@ -77,6 +89,18 @@ func (m *manager) StartExec(w http.ResponseWriter, r *http.Request, conf *types.
 		return
 	}
 
+	exec, ok := m.inspectExecInNamespace(w, conf.ExecId, conf.Namespace)
+	if !ok {
+		return
+	}
+	cmd := append([]string{exec.ProcessConfig.EntryPoint}, exec.ProcessConfig.Arguments...)
+	s, status, err := m.openSession(conf, exec.ContainerID, exec.ProcessConfig.User, cmd)
+	if err != nil {
+		ResponseJSON(w, status, errMsg{err.Error()})
+		return
+	}
+	defer m.closeSession(s)
+
 	ws, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		ResponseJSON(w, http.StatusBadRequest, errMsg{err.Error()})
@@ -87,19 +111,23 @@ func (m *manager) StartExec(w http.ResponseWriter, r *http.Request, conf *types.
 	ws.SetCloseHandler(nil)
 	ws.SetPingHandler(nil)
 
-	err = m.startExec(&wsConn{ws}, conf)
+	// the connection is hijacked, the result of exec is sent in the close message
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	err = m.startExec(&wsConn{conn: ws}, conf, s)
 	if err != nil {
 		blog.Info(err.Error())
-		ResponseJSON(w, http.StatusBadRequest, errMsg{err.Error()})
-		return
+		closeMsg = websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error())
+	} else if exec, err = m.dockerClient.InspectExec(conf.ExecId); err == nil {
+		closeMsg = websocket.FormatCloseMessage(websocket.CloseNormalClosure, fmt.Sprintf(types.ExitCodeCloseReason, exec.ExitCode))
 	}
-
-	ResponseJSON(w, http.StatusSwitchingProtocols, nil)
-	return
+	ws.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(closeTimeout))
 }
 
 func (m *manager) CreateExec(w http.ResponseWriter, r *http.Request, conf *types.WebSocketConfig) {
 	blog.Debug(fmt.Sprintf("start create exec for container %s", conf.ContainerId))
+	if _, ok := m.inspectContainerInNamespace(w, conf.ContainerId, conf.Namespace); !ok {
+		return
+	}
 	// 创建连接
 	exec, err := m.dockerClient.CreateExec(docker.CreateExecOptions{
 		AttachStdin:  true,
@@ -122,13 +150,14 @@ func (m *manager) CreateExec(w http.ResponseWriter, r *http.Request, conf *types
 	return
 }
 
-func (m *manager) startExec(ws io.ReadWriter, conf *types.WebSocketConfig) error {
+func (m *manager) startExec(ws io.ReadWriter, conf *types.WebSocketConfig, s *session) error {
 	fmt.Println("start exec")
+	conn := &sessionConn{rw: ws, session: s}
 	// 执行连接
-	err := m.dockerClient.StartExec(conf.ExecId, docker.StartExecOptions{
-		InputStream:  ws,
-		OutputStream: ws,
-		ErrorStream:  ws,
+	waiter, err := m.dockerClient.StartExecNonBlocking(conf.ExecId, docker.StartExecOptions{
+		InputStream:  conn,
+		OutputStream: conn,
+		ErrorStream:  conn,
 		Detach:       false,
 		Tty:          m.conf.Tty,
 		RawTerminal:  true,
@@ -138,16 +167,29 @@ func (m *manager) startExec(ws io.ReadWriter, conf *types.WebSocketConfig) error
 		return err
 	}
 
-	return nil
+	// closing the hijacked connection ends the exec session
+	s.setCloser(func(reason string) {
+		conn.Write([]byte(fmt.Sprintf("\r\nsession terminated: %s\r\n", reason)))
+		waiter.Close()
+	})
+	go m.watchSession(s)
+
+	return waiter.Wait()
 }
 
 func (m *manager) ResizeExec(w http.ResponseWriter, r *http.Request, conf *types.WebSocketConfig) {
 	blog.Debug(fmt.Sprintf("start resize for container exec_id %s", conf.ExecId))
+	if _, ok := m.inspectExecInNamespace(w, conf.ExecId, conf.Namespace); !ok {
+		return
+	}
 	err := m.dockerClient.ResizeExecTTY(conf.ExecId, conf.Height, conf.Width)
 	if err != nil {
 		ResponseJSON(w, http.StatusBadRequest, errMsg{err.Error()})
 		return
 	}
+	if s := m.getSessionByExec(conf.ExecId); s != nil && s.recorder != nil {
+		s.recorder.resize(conf.Width, conf.Height)
+	}
 
 	ResponseJSON(w, http.StatusOK, nil)
 	return
@@ -0,0 +1,121 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manager
+
+import (
+	"fmt"
+	"net/http"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-mesos/bcs-consoleproxy/console-proxy/types"
+	"github.com/fsouza/go-dockerclient"
+)
+
+//flushWriter flushes every write to client, so that the stream is not buffered
+type flushWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	fw.written = true
+	n, err := fw.w.Write(p)
+	if f, ok := fw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
+
+func (m *manager) ContainerLogs(w http.ResponseWriter, r *http.Request, conf *types.LogsConfig) {
+	blog.Debug(fmt.Sprintf("start logs for container %s", conf.ContainerId))
+	container, ok := m.inspectContainerInNamespace(w, conf.ContainerId, conf.Namespace)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fw := &flushWriter{w: w}
+	err := m.dockerClient.Logs(docker.LogsOptions{
+		Context:      r.Context(),
+		Container:    conf.ContainerId,
+		OutputStream: fw,
+		ErrorStream:  fw,
+		Tail:         conf.Tail,
+		Since:        conf.Since,
+		Follow:       conf.Follow,
+		Stdout:       true,
+		Stderr:       true,
+		Timestamps:   conf.Timestamps,
+		// logs of tty container are not multiplexed
+		RawTerminal: container.Config != nil && container.Config.Tty,
+	})
+	if err != nil && !fw.written {
+		responseDockerError(w, err)
+		return
+	}
+	if err != nil {
+		blog.Infof("logs for container %s end: %s", conf.ContainerId, err.Error())
+	}
+}
+
+func (m *manager) CopyFromContainer(w http.ResponseWriter, r *http.Request, conf *types.CopyConfig) {
+	blog.Debug(fmt.Sprintf("start copy %s from container %s", conf.Path, conf.ContainerId))
+	if _, ok := m.inspectContainerInNamespace(w, conf.ContainerId, conf.Namespace); !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-tar")
+	fw := &flushWriter{w: w}
+	err := m.dockerClient.DownloadFromContainer(conf.ContainerId, docker.DownloadFromContainerOptions{
+		Context:      r.Context(),
+		OutputStream: fw,
+		Path:         conf.Path,
+	})
+	if err != nil && !fw.written {
+		responseDockerError(w, err)
+		return
+	}
+	if err != nil {
+		blog.Errorf("copy %s from container %s failed: %s", conf.Path, conf.ContainerId, err.Error())
+	}
+}
+
+func (m *manager) CopyToContainer(w http.ResponseWriter, r *http.Request, conf *types.CopyConfig) {
+	blog.Debug(fmt.Sprintf("start copy to %s of container %s", conf.Path, conf.ContainerId))
+	if _, ok := m.inspectContainerInNamespace(w, conf.ContainerId, conf.Namespace); !ok {
+		return
+	}
+	err := m.dockerClient.UploadToContainer(conf.ContainerId, docker.UploadToContainerOptions{
+		Context:     r.Context(),
+		InputStream: r.Body,
+		Path:        conf.Path,
+	})
+	if err != nil {
+		responseDockerError(w, err)
+		return
+	}
+
+	ResponseJSON(w, http.StatusOK, nil)
+}
+
+//responseDockerError keeps the status code of docker daemon, such as 404 for no such container or path
+func responseDockerError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	switch e := err.(type) {
+	case *docker.Error:
+		status = e.Status
+	case *docker.NoSuchContainer:
+		status = http.StatusNotFound
+	}
+	ResponseJSON(w, status, errMsg{err.Error()})
+}
@@ -26,4 +26,14 @@ type Manager interface {
 	StartExec(http.ResponseWriter, *http.Request, *types.WebSocketConfig)
 	CreateExec(http.ResponseWriter, *http.Request, *types.WebSocketConfig)
 	ResizeExec(http.ResponseWriter, *http.Request, *types.WebSocketConfig)
+
+	//stream container logs
+	ContainerLogs(http.ResponseWriter, *http.Request, *types.LogsConfig)
+	//copy files from or to container in tar archive
+	CopyFromContainer(http.ResponseWriter, *http.Request, *types.CopyConfig)
+	CopyToContainer(http.ResponseWriter, *http.Request, *types.CopyConfig)
+
+	//admin of live console sessions
+	ListSessions(http.ResponseWriter, *http.Request)
+	TerminateSession(w http.ResponseWriter, r *http.Request, id string)
 }
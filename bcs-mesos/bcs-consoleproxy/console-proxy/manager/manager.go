@@ -16,6 +16,7 @@ package manager
 import (
 	"bk-bcs/bcs-mesos/bcs-consoleproxy/console-proxy/config"
 	"github.com/fsouza/go-dockerclient"
+	"os"
 	"sync"
 )
 
@@ -27,11 +28,18 @@ type manager struct {
 
 	containerid     string
 	websocketOrigin string
+
+	// live console sessions, key is session id
+	sessions map[string]*session
+	// sessions being opened of each user, counted in session limit before the record file is created
+	opening map[string]int
 }
 
 func NewManager(conf *config.ConsoleProxyConfig) Manager {
 	return &manager{
-		conf: conf,
+		conf:     conf,
+		sessions: make(map[string]*session),
+		opening:  make(map[string]int),
 	}
 }
 
@@ -42,5 +50,14 @@ func (m *manager) Start() error {
 		return err
 	}
 
+	if m.conf.RecordDir != "" {
+		if err = os.MkdirAll(m.conf.RecordDir, 0750); err != nil {
+			return err
+		}
+		if m.conf.RecordRetention > 0 {
+			go m.cleanRecordsLoop()
+		}
+	}
+
 	return nil
 }
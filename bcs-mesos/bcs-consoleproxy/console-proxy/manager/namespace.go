@@ -0,0 +1,64 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manager
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/fsouza/go-dockerclient"
+)
+
+const (
+	//namespaceLabel is the container label of bcs namespace, set by scheduler over the labels of user
+	namespaceLabel = "namespace"
+)
+
+//inspectContainerInNamespace inspects the container and checks it belongs to the namespace authorized by bcs-api,
+//the response is written if it fails
+func (m *manager) inspectContainerInNamespace(w http.ResponseWriter, id, namespace string) (*docker.Container, bool) {
+	container, err := m.dockerClient.InspectContainer(id)
+	if err != nil {
+		responseDockerError(w, err)
+		return nil, false
+	}
+	if !inNamespace(container, namespace) {
+		ResponseJSON(w, http.StatusForbidden, errMsg{fmt.Sprintf("container %s is not in namespace %s", id, namespace)})
+		return nil, false
+	}
+	return container, true
+}
+
+//inspectExecInNamespace inspects the exec and checks its container belongs to the namespace authorized by bcs-api,
+//the response is written if it fails
+func (m *manager) inspectExecInNamespace(w http.ResponseWriter, id, namespace string) (*docker.ExecInspect, bool) {
+	exec, err := m.dockerClient.InspectExec(id)
+	if err != nil {
+		responseDockerError(w, err)
+		return nil, false
+	}
+	if _, ok := m.inspectContainerInNamespace(w, exec.ContainerID, namespace); !ok {
+		return nil, false
+	}
+	return exec, true
+}
+
+//inNamespace checks the namespace label of container, empty namespace is not checked as the request
+//comes from admin sources directly, requests without namespace from others are rejected by api router
+func inNamespace(container *docker.Container, namespace string) bool {
+	if namespace == "" {
+		return true
+	}
+	return container.Config != nil && container.Config.Labels[namespaceLabel] == namespace
+}
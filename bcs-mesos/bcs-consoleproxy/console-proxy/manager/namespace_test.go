@@ -0,0 +1,41 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/fsouza/go-dockerclient"
+)
+
+func TestInNamespace(t *testing.T) {
+	labeled := &docker.Container{Config: &docker.Config{Labels: map[string]string{namespaceLabel: "ns1"}}}
+	tests := []struct {
+		name      string
+		container *docker.Container
+		namespace string
+		want      bool
+	}{
+		{name: "not checked", container: &docker.Container{}, namespace: "", want: true},
+		{name: "same namespace", container: labeled, namespace: "ns1", want: true},
+		{name: "other namespace", container: labeled, namespace: "ns2", want: false},
+		{name: "no labels", container: &docker.Container{Config: &docker.Config{}}, namespace: "ns1", want: false},
+		{name: "no config", container: &docker.Container{}, namespace: "ns1", want: false},
+	}
+	for _, test := range tests {
+		if got := inNamespace(test.container, test.namespace); got != test.want {
+			t.Errorf("%s: inNamespace() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-mesos/bcs-consoleproxy/console-proxy/types"
+)
+
+const (
+	recordFileSuffix = ".cast"
+
+	// default terminal size in record header before the first resize
+	defaultRecordWidth  = 80
+	defaultRecordHeight = 24
+
+	recordEventOutput = "o"
+	recordEventInput  = "i"
+	recordEventResize = "r"
+	recordEventMarker = "m"
+)
+
+//recordHeader is the header line of asciinema v2 file, the session metadata is saved in env
+//so that the file can still be played by asciinema
+type recordHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+//recorder writes console session in asciinema v2 format, each event is a line of
+//[elapsed seconds, event type, data]
+type recorder struct {
+	sync.Mutex
+	file  *os.File
+	start time.Time
+}
+
+//newRecorder creates the record file of session under dir/yyyymmdd/
+func newRecorder(dir string, session *types.SessionInfo) (*recorder, error) {
+	dayDir := filepath.Join(dir, session.StartTime.Format("20060102"))
+	if err := os.MkdirAll(dayDir, 0750); err != nil {
+		return nil, err
+	}
+
+	name := filepath.Join(dayDir, session.ID+recordFileSuffix)
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0640)
+	if err != nil {
+		return nil, err
+	}
+
+	header := recordHeader{
+		Version:   2,
+		Width:     defaultRecordWidth,
+		Height:    defaultRecordHeight,
+		Timestamp: session.StartTime.Unix(),
+		Command:   strings.Join(session.Cmd, " "),
+		Title:     fmt.Sprintf("%s@%s", session.Username, session.ContainerID),
+		Env: map[string]string{
+			"BCS_USERNAME":     session.Username,
+			"BCS_REMOTE_ADDR":  session.RemoteAddr,
+			"BCS_CONTAINER_ID": session.ContainerID,
+			"BCS_EXEC_ID":      session.ExecID,
+			"BCS_EXEC_USER":    session.User,
+		},
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &recorder{file: file, start: session.StartTime}, nil
+}
+
+func (r *recorder) Name() string {
+	return r.file.Name()
+}
+
+func (r *recorder) write(eventType string, data string) {
+	r.Lock()
+	defer r.Unlock()
+	if r.file == nil {
+		return
+	}
+
+	elapsed := time.Since(r.start).Seconds()
+	line, err := json.Marshal([]interface{}{elapsed, eventType, data})
+	if err != nil {
+		return
+	}
+	if _, err = r.file.Write(append(line, '\n')); err != nil {
+		blog.Errorf("write console record %s failed: %s", r.file.Name(), err.Error())
+	}
+}
+
+func (r *recorder) output(p []byte) {
+	r.write(recordEventOutput, string(p))
+}
+
+func (r *recorder) input(p []byte) {
+	r.write(recordEventInput, string(p))
+}
+
+func (r *recorder) resize(width, height int) {
+	r.write(recordEventResize, fmt.Sprintf("%dx%d", width, height))
+}
+
+//close writes the end marker with reason and closes the file
+func (r *recorder) close(reason string) {
+	r.write(recordEventMarker, fmt.Sprintf("session end: %s", reason))
+
+	r.Lock()
+	defer r.Unlock()
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+}
+
+//cleanRecords removes the records older than retention and the empty day directories
+func cleanRecords(dir string, retention time.Duration, now time.Time) {
+	days, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return
+	}
+
+	for _, day := range days {
+		if info, err := os.Stat(day); err != nil || !info.IsDir() {
+			continue
+		}
+		files, err := filepath.Glob(filepath.Join(day, "*"+recordFileSuffix))
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			info, err := os.Stat(file)
+			if err != nil || now.Sub(info.ModTime()) <= retention {
+				continue
+			}
+			if err = os.Remove(file); err != nil {
+				blog.Errorf("remove expired console record %s failed: %s", file, err.Error())
+				continue
+			}
+			blog.Infof("expired console record %s removed", file)
+		}
+		// only remove the day directory if it is empty
+		os.Remove(day)
+	}
+}
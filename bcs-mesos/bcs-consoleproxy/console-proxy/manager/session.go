@@ -0,0 +1,295 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manager
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-mesos/bcs-consoleproxy/console-proxy/types"
+)
+
+const (
+	sessionEndExit     = "exec exited"
+	sessionEndIdle     = "idle timeout"
+	sessionEndDuration = "max session duration reached"
+	sessionEndAdmin    = "terminated by admin"
+
+	// interval to check idle timeout and max duration of sessions
+	sessionCheckInterval = time.Second
+	// interval to clean expired records
+	recordCleanInterval = time.Hour
+)
+
+//session is a live console session bridging websocket and docker exec
+type session struct {
+	sync.Mutex
+	info     types.SessionInfo
+	recorder *recorder
+
+	once      sync.Once
+	closer    func(reason string)
+	endReason string
+	done      chan struct{}
+}
+
+func (s *session) active() {
+	s.Lock()
+	s.info.LastActive = time.Now()
+	s.Unlock()
+}
+
+func (s *session) snapshot() types.SessionInfo {
+	s.Lock()
+	defer s.Unlock()
+	return s.info
+}
+
+//setCloser sets how to close the exec of session, the exec is closed at once if the session has been terminated
+//before it starts
+func (s *session) setCloser(closer func(reason string)) {
+	s.Lock()
+	s.closer = closer
+	reason := s.endReason
+	s.Unlock()
+
+	if reason != "" {
+		closer(reason)
+	}
+}
+
+//terminate ends the session with reason, only the first call takes effect. The termination is pending
+//until setCloser if the exec has not started yet.
+func (s *session) terminate(reason string) {
+	s.once.Do(func() {
+		s.Lock()
+		s.endReason = reason
+		closer := s.closer
+		s.Unlock()
+
+		blog.Infof("console session %s of %s terminated: %s", s.info.ID, s.info.Username, reason)
+		if closer != nil {
+			closer(reason)
+		}
+	})
+}
+
+func (s *session) reason() string {
+	s.Lock()
+	defer s.Unlock()
+	if s.endReason == "" {
+		return sessionEndExit
+	}
+	return s.endReason
+}
+
+//sessionConn records the traffic between websocket and docker exec, and refreshes active time of session
+type sessionConn struct {
+	rw      io.ReadWriter
+	session *session
+}
+
+func (c *sessionConn) Read(p []byte) (int, error) {
+	n, err := c.rw.Read(p)
+	if n > 0 {
+		c.session.active()
+		if c.session.recorder != nil {
+			c.session.recorder.input(p[:n])
+		}
+	}
+	return n, err
+}
+
+func (c *sessionConn) Write(p []byte) (int, error) {
+	n, err := c.rw.Write(p)
+	if n > 0 {
+		c.session.active()
+		if c.session.recorder != nil {
+			c.session.recorder.output(p[:n])
+		}
+	}
+	return n, err
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+//openSession registers a session for exec if the user does not exceed the session limit,
+//and starts recording if it is enabled. The returned status is the http status code for the error.
+func (m *manager) openSession(conf *types.WebSocketConfig, containerID, user string, cmd []string) (*session, int, error) {
+	now := time.Now()
+	s := &session{
+		info: types.SessionInfo{
+			ID:          newSessionID(),
+			Username:    conf.Username,
+			RemoteAddr:  conf.RemoteAddr,
+			ContainerID: containerID,
+			ExecID:      conf.ExecId,
+			User:        user,
+			Cmd:         cmd,
+			StartTime:   now,
+			LastActive:  now,
+		},
+		done: make(chan struct{}),
+	}
+
+	m.Lock()
+	if m.conf.MaxSessionsPerUser > 0 {
+		count := m.opening[conf.Username]
+		for _, live := range m.sessions {
+			if live.info.Username == conf.Username {
+				count++
+			}
+		}
+		if count >= m.conf.MaxSessionsPerUser {
+			m.Unlock()
+			return nil, http.StatusTooManyRequests,
+				fmt.Errorf("user %s already has %d console sessions, reaching the limit", conf.Username, count)
+		}
+	}
+	// the slot is reserved so that the record file can be created without holding the lock
+	m.opening[conf.Username]++
+	m.Unlock()
+
+	var err error
+	if m.conf.RecordDir != "" {
+		var rec *recorder
+		if rec, err = newRecorder(m.conf.RecordDir, &s.info); err == nil {
+			s.recorder = rec
+			s.info.RecordFile = rec.Name()
+		}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if m.opening[conf.Username]--; m.opening[conf.Username] <= 0 {
+		delete(m.opening, conf.Username)
+	}
+	if err != nil {
+		// sessions must be auditable if recording is enabled
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to record console session: %s", err.Error())
+	}
+	m.sessions[s.info.ID] = s
+	blog.Infof("console session %s opened by %s from %s, container %s, exec %s, cmd %v",
+		s.info.ID, s.info.Username, s.info.RemoteAddr, containerID, conf.ExecId, cmd)
+	return s, http.StatusOK, nil
+}
+
+//closeSession removes session and finishes the record
+func (m *manager) closeSession(s *session) {
+	m.Lock()
+	delete(m.sessions, s.info.ID)
+	m.Unlock()
+
+	reason := s.reason()
+	if s.recorder != nil {
+		s.recorder.close(reason)
+	}
+	close(s.done)
+	blog.Infof("console session %s of %s closed: %s", s.info.ID, s.info.Username, reason)
+}
+
+func (m *manager) getSession(id string) *session {
+	m.RLock()
+	defer m.RUnlock()
+	return m.sessions[id]
+}
+
+func (m *manager) getSessionByExec(execID string) *session {
+	m.RLock()
+	defer m.RUnlock()
+	for _, s := range m.sessions {
+		if s.info.ExecID == execID {
+			return s
+		}
+	}
+	return nil
+}
+
+//watchSession terminates the session when it is idle for too long or lasts too long
+func (m *manager) watchSession(s *session) {
+	if m.conf.IdleTimeout <= 0 && m.conf.MaxSessionDuration <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(sessionCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			if reason := checkSession(s.snapshot(), m.conf.IdleTimeout, m.conf.MaxSessionDuration, now); reason != "" {
+				s.terminate(reason)
+				return
+			}
+		}
+	}
+}
+
+//checkSession returns the reason if session should be terminated, zero timeout means no limit
+func checkSession(info types.SessionInfo, idleTimeout, maxDuration time.Duration, now time.Time) string {
+	if maxDuration > 0 && now.Sub(info.StartTime) >= maxDuration {
+		return sessionEndDuration
+	}
+	if idleTimeout > 0 && now.Sub(info.LastActive) >= idleTimeout {
+		return sessionEndIdle
+	}
+	return ""
+}
+
+//cleanRecordsLoop removes expired records periodically
+func (m *manager) cleanRecordsLoop() {
+	for {
+		cleanRecords(m.conf.RecordDir, m.conf.RecordRetention, time.Now())
+		time.Sleep(recordCleanInterval)
+	}
+}
+
+func (m *manager) ListSessions(w http.ResponseWriter, r *http.Request) {
+	m.RLock()
+	sessions := make([]types.SessionInfo, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s.snapshot())
+	}
+	m.RUnlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartTime.Before(sessions[j].StartTime)
+	})
+	ResponseJSON(w, http.StatusOK, sessions)
+}
+
+func (m *manager) TerminateSession(w http.ResponseWriter, r *http.Request, id string) {
+	s := m.getSession(id)
+	if s == nil {
+		ResponseJSON(w, http.StatusNotFound, errMsg{fmt.Sprintf("session %s not found", id)})
+		return
+	}
+
+	s.terminate(sessionEndAdmin)
+	ResponseJSON(w, http.StatusOK, s.snapshot())
+}
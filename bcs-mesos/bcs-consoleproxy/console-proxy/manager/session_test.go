@@ -0,0 +1,178 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package manager
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bk-bcs/bcs-mesos/bcs-consoleproxy/console-proxy/config"
+	"bk-bcs/bcs-mesos/bcs-consoleproxy/console-proxy/types"
+)
+
+func TestCheckSession(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		start       time.Time
+		lastActive  time.Time
+		idleTimeout time.Duration
+		maxDuration time.Duration
+		expect      string
+	}{
+		{name: "no limit", start: now.Add(-time.Hour), lastActive: now.Add(-time.Hour)},
+		{name: "active", start: now.Add(-time.Hour), lastActive: now, idleTimeout: time.Minute, maxDuration: 2 * time.Hour},
+		{name: "idle", start: now.Add(-time.Hour), lastActive: now.Add(-2 * time.Minute), idleTimeout: time.Minute, expect: sessionEndIdle},
+		{name: "too long", start: now.Add(-3 * time.Hour), lastActive: now, maxDuration: 2 * time.Hour, expect: sessionEndDuration},
+	}
+
+	for _, test := range tests {
+		info := types.SessionInfo{StartTime: test.start, LastActive: test.lastActive}
+		if got := checkSession(info, test.idleTimeout, test.maxDuration, now); got != test.expect {
+			t.Errorf("%s: expect %q, got %q", test.name, test.expect, got)
+		}
+	}
+}
+
+func TestOpenSessionLimitAndRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "consoleproxy-record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	m := NewManager(&config.ConsoleProxyConfig{RecordDir: dir, MaxSessionsPerUser: 1}).(*manager)
+	conf := &types.WebSocketConfig{ExecId: "exec1", Username: "alice", RemoteAddr: "10.0.0.1"}
+	s, _, err := m.openSession(conf, "container1", "root", []string{"/bin/sh"})
+	if err != nil {
+		t.Fatalf("open session failed: %v", err)
+	}
+	if _, _, err = m.openSession(conf, "container1", "root", []string{"/bin/sh"}); err == nil {
+		t.Errorf("expect session limit reached for alice")
+	}
+	other := &types.WebSocketConfig{ExecId: "exec2", Username: "bob"}
+	s2, _, err := m.openSession(other, "container1", "root", []string{"/bin/sh"})
+	if err != nil {
+		t.Errorf("bob should not be limited by sessions of alice: %v", err)
+	}
+
+	s.recorder.resize(120, 40)
+	conn := &sessionConn{rw: &nopReadWriter{}, session: s}
+	conn.Write([]byte("hello\r\n"))
+	s.terminate(sessionEndAdmin)
+	m.closeSession(s)
+	m.closeSession(s2)
+	if len(m.sessions) != 0 {
+		t.Errorf("expect all sessions closed, got %d", len(m.sessions))
+	}
+
+	f, err := os.Open(s.info.RecordFile)
+	if err != nil {
+		t.Fatalf("record file not found: %v", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+
+	scanner.Scan()
+	var header recordHeader
+	if err = json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		t.Fatalf("invalid header: %v", err)
+	}
+	if header.Version != 2 || header.Env["BCS_USERNAME"] != "alice" || header.Command != "/bin/sh" {
+		t.Errorf("unexpected header: %+v", header)
+	}
+
+	var events []string
+	for scanner.Scan() {
+		var event []interface{}
+		if err = json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 {
+			t.Fatalf("invalid event %s: %v", scanner.Text(), err)
+		}
+		events = append(events, event[1].(string))
+	}
+	expect := []string{recordEventResize, recordEventOutput, recordEventMarker}
+	if len(events) != len(expect) {
+		t.Fatalf("expect events %v, got %v", expect, events)
+	}
+	for i := range expect {
+		if events[i] != expect[i] {
+			t.Errorf("expect events %v, got %v", expect, events)
+		}
+	}
+}
+
+func TestCleanRecords(t *testing.T) {
+	dir, err := ioutil.TempDir("", "consoleproxy-clean")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	oldDay := filepath.Join(dir, "20190101")
+	newDay := filepath.Join(dir, "20190601")
+	os.MkdirAll(oldDay, 0750)
+	os.MkdirAll(newDay, 0750)
+	oldFile := filepath.Join(oldDay, "a"+recordFileSuffix)
+	newFile := filepath.Join(newDay, "b"+recordFileSuffix)
+	ioutil.WriteFile(oldFile, []byte("{}"), 0640)
+	ioutil.WriteFile(newFile, []byte("{}"), 0640)
+	os.Chtimes(oldFile, now.Add(-48*time.Hour), now.Add(-48*time.Hour))
+
+	cleanRecords(dir, 24*time.Hour, now)
+	if _, err = os.Stat(oldFile); !os.IsNotExist(err) {
+		t.Errorf("expired record should be removed")
+	}
+	if _, err = os.Stat(oldDay); !os.IsNotExist(err) {
+		t.Errorf("empty day directory should be removed")
+	}
+	if _, err = os.Stat(newFile); err != nil {
+		t.Errorf("record in retention should be kept: %v", err)
+	}
+}
+
+type nopReadWriter struct{}
+
+func (n *nopReadWriter) Read(p []byte) (int, error)  { return 0, nil }
+func (n *nopReadWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func TestTerminateBeforeExecStarts(t *testing.T) {
+	s := &session{done: make(chan struct{})}
+	s.terminate(sessionEndAdmin)
+
+	var closedBy string
+	s.setCloser(func(reason string) {
+		closedBy = reason
+	})
+	if closedBy != sessionEndAdmin {
+		t.Errorf("expect exec closed at once by pending termination, got %q", closedBy)
+	}
+
+	// the closer is called only once when the session is terminated after exec starts
+	s = &session{done: make(chan struct{})}
+	calls := 0
+	s.setCloser(func(reason string) {
+		calls++
+	})
+	s.terminate(sessionEndIdle)
+	s.terminate(sessionEndAdmin)
+	if calls != 1 || s.reason() != sessionEndIdle {
+		t.Errorf("expect closer called once for %s, got %d calls for %s", sessionEndIdle, calls, s.reason())
+	}
+}
@@ -13,6 +13,15 @@
 
 package types
 
+import (
+	"time"
+)
+
+const (
+	//ExitCodeCloseReason is the reason of websocket close message when exec ends, with the exit code of command
+	ExitCodeCloseReason = "exit code %d"
+)
+
 type WebSocketConfig struct {
 	Height      int
 	Width       int
@@ -24,4 +33,43 @@ type WebSocketConfig struct {
 	Origin      string
 	User        string
 	ExecId      string
+	// Username is the bcs user operating the console, RemoteAddr is where the user comes from
+	Username   string
+	RemoteAddr string
+	// Namespace is authorized by bcs-api, the container must belong to it
+	Namespace string
+}
+
+//LogsConfig is the options of streaming container logs
+type LogsConfig struct {
+	ContainerId string
+	Follow      bool
+	// Since is unix timestamp in seconds, 0 for all logs
+	Since      int64
+	Tail       string
+	Timestamps bool
+	// Namespace is authorized by bcs-api, the container must belong to it
+	Namespace string
+}
+
+//CopyConfig is the options of copying files from or to container
+type CopyConfig struct {
+	ContainerId string
+	Path        string
+	// Namespace is authorized by bcs-api, the container must belong to it
+	Namespace string
+}
+
+//SessionInfo is a live console session
+type SessionInfo struct {
+	ID          string    `json:"id"`
+	Username    string    `json:"username"`
+	RemoteAddr  string    `json:"remote_addr"`
+	ContainerID string    `json:"container_id"`
+	ExecID      string    `json:"exec_id"`
+	User        string    `json:"user"`
+	Cmd         []string  `json:"cmd"`
+	StartTime   time.Time `json:"start_time"`
+	LastActive  time.Time `json:"last_active"`
+	RecordFile  string    `json:"record_file,omitempty"`
 }
@@ -14,6 +14,7 @@
 package app
 
 import (
+	"bk-bcs/bcs-mesos/bcs-container-executor/collector"
 	"bk-bcs/bcs-mesos/bcs-container-executor/container"
 	"bk-bcs/bcs-mesos/bcs-container-executor/container/cni"
 	"bk-bcs/bcs-mesos/bcs-container-executor/container/cnm"
@@ -150,6 +151,7 @@ type BcsExecutor struct {
 	exeLock    sync.RWMutex        //lock for tasks & monitors
 	tasks      *BcsTaskInfo        //taskinfo cache, key is TaskName
 	messages   map[int64]*bcstype.BcsMessage
+	collector  *collector.Collector //resource usage collector
 }
 
 //Stop send stop signal
@@ -206,6 +208,8 @@ func (executor *BcsExecutor) LaunchTaskGroup(driver exec.ExecutorDriver, taskGro
 	executor.status = ExecutorStatus_LAUNCHING
 	//construct BcsContainerTask to create Pod
 	var containerTasks []*container.BcsContainerTask
+	var initContainers []*bcstype.Container
+	var initMsgs []*bcstype.BcsMessage
 	for _, taskInfo := range taskGroup.GetTasks() {
 		by, _ := json.Marshal(taskInfo)
 		logs.Infof("Launch Task %s with data %s.\n", taskInfo.GetName(), string(by))
@@ -233,6 +237,11 @@ func (executor *BcsExecutor) LaunchTaskGroup(driver exec.ExecutorDriver, taskGro
 			driver.Stop()
 			return
 		}
+		if len(dataClass.InitContainers) > 0 {
+			initContainers = dataClass.InitContainers
+			//init containers share configmaps and secrets with the task
+			initMsgs = dataClass.Msgs
+		}
 		mesosContainer := taskInfo.GetContainer()
 		dockerInfo := mesosContainer.GetDocker()
 		//create ContainerName for starting Docker Container
@@ -316,12 +325,33 @@ func (executor *BcsExecutor) LaunchTaskGroup(driver exec.ExecutorDriver, taskGro
 			driver.Stop()
 			return
 		}
+		//setting readiness & startup check
+		if err := executor.probeSetting(containerTask, dataClass); err != nil {
+			logs.Errorf("Create probe for image: %s failed, %s\n", containerTask.Image, err.Error())
+			executor.updateTaskGroup(driver, taskGroup, mesos.TaskState_TASK_FAILED, "create probe failed, "+err.Error())
+			executor.status = ExecutorStatus_SHUTDOWN
+			driver.Stop()
+			return
+		}
 		//adding
 		containerTasks = append(containerTasks, containerTask)
 	}
 	//tasks are ready, create Pod now
 	podEvent := &container.PodEventHandler{
-		PreStart: executor.preContainerStartEventCallback,
+		PreStart:  executor.preContainerStartEventCallback,
+		PostStart: executor.postStartEventCallback,
+		PreStop:   executor.preStopEventCallback,
+	}
+
+	//docker pod has no infrastructure container, network container is business
+	//container either, so init containers run with same network before pod init
+	if executor.flag.NetworkMode != "cni" && len(initContainers) > 0 && len(containerTasks) > 0 {
+		if initErr := executor.runInitContainers(initContainers, initMsgs, containerTasks[0].NetworkName); initErr != nil {
+			executor.updateTaskGroup(driver, taskGroup, mesos.TaskState_TASK_FAILED, initErr.Error())
+			executor.status = ExecutorStatus_SHUTDOWN
+			driver.Stop()
+			return
+		}
 	}
 
 	createPod(executor, executor.flag, containerTasks, podEvent)
@@ -356,6 +386,19 @@ func (executor *BcsExecutor) LaunchTaskGroup(driver exec.ExecutorDriver, taskGro
 	}
 	logs.Infof("BcsExecutor Setup pod network success.")
 
+	//cni pod init containers share network with infrastructure container
+	if executor.flag.NetworkMode == "cni" && len(initContainers) > 0 {
+		if initErr := executor.runInitContainers(initContainers, initMsgs, "container:"+executor.podInst.GetContainerID()); initErr != nil {
+			executor.updateTaskGroup(driver, taskGroup, mesos.TaskState_TASK_FAILED, initErr.Error())
+			executor.status = ExecutorStatus_SHUTDOWN
+			executor.podInst.Stop(1)
+			executor.netManager.TearDownPod(executor.podInst)
+			executor.podInst.Finit()
+			driver.Stop()
+			return
+		}
+	}
+
 	if startErr := executor.podInst.Start(); startErr != nil {
 		executor.updateTaskGroup(driver, taskGroup, mesos.TaskState_TASK_FAILED, "Pod Start failed: "+startErr.Error())
 		executor.status = ExecutorStatus_SHUTDOWN
@@ -382,6 +425,8 @@ func (executor *BcsExecutor) LaunchTaskGroup(driver exec.ExecutorDriver, taskGro
 	}
 	//watching all containers
 	go executor.monitorPod()
+	//sampling resource usage of all containers
+	executor.startCollector()
 	return
 }
 
@@ -587,23 +632,23 @@ func (executor *BcsExecutor) monitorPod() {
 					delete(executor.messages, message.Id)
 				}
 
+				if task := startupFailedTask(executor.podInst.GetContainerTasks()); task != nil {
+					//startup check failed, kill pod and report TASK_FAILED in next tick
+					logs.Errorf("container %s startup check failed %d times, ready to kill pod\n", task.Name, task.StartupFailures)
+					executor.podInst.Stop(1)
+					task.RuntimeConf.Message = "container startup check failed"
+					break
+				}
+
 				for _, task := range executor.podInst.GetContainerTasks() {
-					var changed bool
-					if task.HealthCheck != nil {
-						if task.RuntimeConf.Healthy != task.HealthCheck.IsHealthy() || task.RuntimeConf.IsChecked != task.HealthCheck.IsTicks() ||
-							task.RuntimeConf.ConsecutiveFailureTimes != task.HealthCheck.ConsecutiveFailure() {
-							changed = true
-							task.RuntimeConf.Healthy = task.HealthCheck.IsHealthy()
-							task.RuntimeConf.IsChecked = task.HealthCheck.IsTicks()
-							task.RuntimeConf.ConsecutiveFailureTimes = task.HealthCheck.ConsecutiveFailure()
-						}
-					}
+					changed := container.UpdateProbeStatus(task)
 
 					if reporting%30 == 0 || changed || message != nil {
-						//report data every 30 seconds or pod healthy status changed
+						//report data every 30 seconds or pod healthy/ready status changed
 						executor.status = ExecutorStatus_RUNNING
-						logs.Infof("all task is Running, healthy: %t, isChecked: %t, ConsecutiveFailureTimes: %d"+
-							" report task status\n", task.RuntimeConf.Healthy, task.RuntimeConf.IsChecked, task.RuntimeConf.ConsecutiveFailureTimes)
+						logs.Infof("all task is Running, healthy: %t, isChecked: %t, ConsecutiveFailureTimes: %d, ready: %t"+
+							" report task status\n", task.RuntimeConf.Healthy, task.RuntimeConf.IsChecked, task.RuntimeConf.ConsecutiveFailureTimes,
+							task.RuntimeConf.Ready)
 						//get all container update and report to scheduler
 						///for _, info := range containers {
 						info := task.RuntimeConf
@@ -810,8 +855,23 @@ func (executor *BcsExecutor) customSettingContainer(taskInfo *container.BcsConta
 	}
 	taskInfo.Env = append(taskInfo.Env, podID)
 	//check dataClass for bcs define info
-	if dataClass.Msgs != nil && len(dataClass.Msgs) > 0 {
-		for _, item := range dataClass.Msgs {
+	if err := executor.dataClassMsgsSetting(taskInfo, dataClass.Msgs); err != nil {
+		return err
+	}
+	//resource info
+	taskInfo.Resource = dataClass.Resources
+	taskInfo.LimitResource = dataClass.LimitResources
+	taskInfo.NetLimit = dataClass.NetLimit
+	taskInfo.Lifecycle = dataClass.Lifecycle
+	return nil
+
+}
+
+//dataClassMsgsSetting setting configmaps and secrets of task to container as Environments, or as files
+//uploaded to container before it starts
+func (executor *BcsExecutor) dataClassMsgsSetting(taskInfo *container.BcsContainerTask, msgs []*bcstype.BcsMessage) error {
+	if msgs != nil && len(msgs) > 0 {
+		for _, item := range msgs {
 			switch *item.Type {
 			case bcstype.Msg_SECRET:
 				//done(developerJim): check Secret.Type for Environment or File
@@ -866,12 +926,7 @@ func (executor *BcsExecutor) customSettingContainer(taskInfo *container.BcsConta
 			}
 		}
 	}
-	//resource info
-	taskInfo.Resource = dataClass.Resources
-	taskInfo.LimitResource = dataClass.LimitResources
-	taskInfo.NetLimit = dataClass.NetLimit
 	return nil
-
 }
 
 func (executor *BcsExecutor) dockerParameterSetting(containerTask *container.BcsContainerTask, dockerInfo *mesos.ContainerInfo_DockerInfo) {
@@ -1076,17 +1131,20 @@ func (executor *BcsExecutor) preContainerStartEventCallback(containerTask *conta
 		return fmt.Errorf("container status error")
 	}
 	//self define message for setting
-	if containerTask.BcsMessages != nil {
-		for _, item := range containerTask.BcsMessages {
-			switch *item.Type {
-			//upload file to created container
-			case bcstype.Msg_LOCALFILE:
-				if copyErr := executor.copyFileToContainer(containerTask.RuntimeConf.ID, item.Local); copyErr != nil {
-					return copyErr
-				}
-			} //end switch
-		} //end for
-	} //end bcs message
+	return executor.copyMessageFiles(containerTask.RuntimeConf.ID, containerTask.BcsMessages)
+}
+
+//copyMessageFiles upload files in bcs messages to created container
+func (executor *BcsExecutor) copyMessageFiles(containerID string, msgs []*bcstype.BcsMessage) error {
+	for _, item := range msgs {
+		switch *item.Type {
+		//upload file to created container
+		case bcstype.Msg_LOCALFILE:
+			if copyErr := executor.copyFileToContainer(containerID, item.Local); copyErr != nil {
+				return copyErr
+			}
+		} //end switch
+	} //end for
 	return nil
 }
 
@@ -0,0 +1,234 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package app
+
+import (
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-container-executor/container"
+	"bk-bcs/bcs-mesos/bcs-container-executor/logs"
+	bcstype "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	//defaultHookTimeout timeout for postStart hook if not setting
+	defaultHookTimeout = 30
+	//InitContainerNamePrefix name prefix for init containers
+	InitContainerNamePrefix = "bcs-init-"
+)
+
+//postStartEventCallback run postStart hook after container started,
+//container is stopped by pod if hook failed
+func (executor *BcsExecutor) postStartEventCallback(containerTask *container.BcsContainerTask) error {
+	if containerTask.Lifecycle == nil || containerTask.Lifecycle.PostStart == nil {
+		return nil
+	}
+	timeout := containerTask.Lifecycle.PostStart.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	logs.Infof("BcsExecutor run postStart hook for container %s\n", containerTask.Name)
+	return executor.runLifecycleHandler(containerTask, containerTask.Lifecycle.PostStart, timeout)
+}
+
+//preStopEventCallback run preStop hook before container stopped, hook
+//shares the kill policy grace period with StopContainer
+func (executor *BcsExecutor) preStopEventCallback(containerTask *container.BcsContainerTask) error {
+	if containerTask.Lifecycle == nil || containerTask.Lifecycle.PreStop == nil {
+		return nil
+	}
+	timeout := preStopTimeout(containerTask.KillPolicy, containerTask.Lifecycle.PreStop)
+	logs.Infof("BcsExecutor run preStop hook for container %s, timeout %d seconds\n", containerTask.Name, timeout)
+	err := executor.runLifecycleHandler(containerTask, containerTask.Lifecycle.PreStop, timeout)
+	if err != nil {
+		logs.Errorf("BcsExecutor preStop hook for container %s failed: %s\n", containerTask.Name, err.Error())
+	}
+	return err
+}
+
+//preStopTimeout is the timeout of hook in seconds, which is no more than the kill policy grace period
+func preStopTimeout(killPolicy int, hook *commtypes.LifecycleHandler) int {
+	if hook.TimeoutSeconds > 0 && hook.TimeoutSeconds < killPolicy {
+		return hook.TimeoutSeconds
+	}
+	return killPolicy
+}
+
+//runLifecycleHandler run exec or http handler with timeout in seconds
+func (executor *BcsExecutor) runLifecycleHandler(containerTask *container.BcsContainerTask, handler *commtypes.LifecycleHandler, timeout int) error {
+	if containerTask.RuntimeConf == nil {
+		return fmt.Errorf("container %s runtime info lost", containerTask.Name)
+	}
+	done := make(chan error, 1)
+	go func() {
+		switch {
+		case handler.Exec != nil:
+			done <- executor.execHandler(containerTask, handler.Exec)
+		case handler.Http != nil:
+			done <- httpHandler(containerTask, handler.Http, timeout)
+		default:
+			done <- fmt.Errorf("lifecycle handler needs exec or http")
+		}
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Duration(timeout) * time.Second):
+		return fmt.Errorf("lifecycle handler timeout after %d seconds", timeout)
+	}
+}
+
+func (executor *BcsExecutor) execHandler(containerTask *container.BcsContainerTask, action *commtypes.ExecAction) error {
+	if len(action.Command) == 0 {
+		return fmt.Errorf("exec handler command is empty")
+	}
+	resp, err := executor.container.RunCommandV2(&bcstype.RequestCommandTask{
+		TaskId:      containerTask.TaskId,
+		ContainerId: containerTask.RuntimeConf.Name,
+		Cmd:         action.Command,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Status != commtypes.TaskCommandStatusFinish {
+		return fmt.Errorf("exec %v failed: %s", action.Command, resp.Message)
+	}
+	if resp.CommInspect != nil && resp.CommInspect.ExitCode != 0 {
+		return fmt.Errorf("exec %v exit code %d, stderr: %s", action.Command, resp.CommInspect.ExitCode, resp.CommInspect.Stderr)
+	}
+	return nil
+}
+
+func httpHandler(containerTask *container.BcsContainerTask, action *commtypes.HttpGetAction, timeout int) error {
+	host := action.Host
+	if host == "" {
+		host = containerTask.RuntimeConf.IPAddress
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	scheme := action.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	url := scheme + "://" + host + ":" + strconv.Itoa(int(action.Port)) + action.Path
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range action.Headers {
+		request.Header.Set(k, v)
+	}
+	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 400 {
+		return fmt.Errorf("http get %s return status %d", url, response.StatusCode)
+	}
+	return nil
+}
+
+//runInitContainers run all init containers in order, every container must
+//exit with code 0 before next one starts. configmaps and secrets of task in
+//msgs are set to init containers as the task containers
+func (executor *BcsExecutor) runInitContainers(initContainers []*bcstype.Container, msgs []*bcstype.BcsMessage, networkName string) error {
+	for index, initContainer := range initContainers {
+		if initContainer.Docker == nil {
+			return fmt.Errorf("init container %d lost docker info", index)
+		}
+		task := newInitContainerTask(executor.driver.ExecutorID(), index, initContainer, networkName)
+		if err := executor.dataClassMsgsSetting(task, msgs); err != nil {
+			return fmt.Errorf("init container %s setting configmaps and secrets failed: %s", task.Name, err.Error())
+		}
+		logs.Infof("BcsExecutor run init container %s with image %s\n", task.Name, task.Image)
+		if err := executor.runInitContainer(task); err != nil {
+			return fmt.Errorf("init container %s failed: %s", task.Name, err.Error())
+		}
+		logs.Infof("BcsExecutor init container %s completed\n", task.Name)
+	}
+	return nil
+}
+
+func newInitContainerTask(executorID string, index int, initContainer *bcstype.Container, networkName string) *container.BcsContainerTask {
+	task := &container.BcsContainerTask{
+		Name:           InitContainerNamePrefix + strconv.Itoa(index) + "-" + executorID,
+		Image:          initContainer.Docker.Image,
+		HostName:       initContainer.Docker.Hostname,
+		Command:        initContainer.Docker.Command,
+		Args:           initContainer.Docker.Arguments,
+		NetworkName:    networkName,
+		ForcePullImage: initContainer.Docker.ForcePullImage,
+		Privileged:     initContainer.Docker.Privileged,
+		AutoRemove:     true,
+		Resource:       initContainer.Resources,
+		LimitResource:  initContainer.LimitResoures,
+		KillPolicy:     1,
+	}
+	if task.Resource == nil {
+		task.Resource = &bcstype.Resource{}
+	}
+	for k, v := range initContainer.Docker.Env {
+		task.Env = append(task.Env, container.BcsKV{Key: k, Value: v})
+	}
+	for _, vol := range initContainer.Volumes {
+		task.Volums = append(task.Volums, container.BcsVolume{
+			ReadOnly:      vol.Mode == "R",
+			HostPath:      vol.HostPath,
+			ContainerPath: vol.ContainerPath,
+		})
+	}
+	return task
+}
+
+//runInitContainer create & start init container, wait until it exits
+func (executor *BcsExecutor) runInitContainer(task *container.BcsContainerTask) error {
+	info, err := executor.container.CreateContainer(task.Name, task)
+	if err != nil {
+		return err
+	}
+	defer executor.container.RemoveContainer(task.Name, true)
+	if err := executor.copyMessageFiles(info.ID, task.BcsMessages); err != nil {
+		return err
+	}
+	if err := executor.container.StartContainer(info.ID); err != nil {
+		return err
+	}
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case <-executor.exeCxt.Done():
+			executor.container.StopContainer(task.Name, task.KillPolicy)
+			return fmt.Errorf("executor is exiting")
+		case <-tick.C:
+			current, err := executor.container.InspectContainer(task.Name)
+			if err != nil {
+				return err
+			}
+			switch current.Status {
+			case container.ContainerStatus_EXITED, container.ContainerStatus_DEAD:
+				if current.ExitCode != 0 {
+					return fmt.Errorf("exit code %d", current.ExitCode)
+				}
+				return nil
+			}
+		}
+	}
+}
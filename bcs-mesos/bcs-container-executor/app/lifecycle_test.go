@@ -0,0 +1,182 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package app
+
+import (
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-container-executor/container"
+	exec "bk-bcs/bcs-mesos/bcs-container-executor/executor"
+	bcstype "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"encoding/base64"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+)
+
+//fakeDriver only knows its executor id, other methods are not implemented
+type fakeDriver struct {
+	exec.ExecutorDriver
+}
+
+func (d *fakeDriver) ExecutorID() string {
+	return "executor-1"
+}
+
+//fakeContainer runs containers which exit with code 0 at once, other methods are not implemented
+type fakeContainer struct {
+	container.Container
+	created  []*container.BcsContainerTask
+	uploaded map[string]string
+	removed  []string
+}
+
+func (c *fakeContainer) CreateContainer(name string, task *container.BcsContainerTask) (*container.BcsContainerInfo, error) {
+	c.created = append(c.created, task)
+	return &container.BcsContainerInfo{ID: "id-" + name, Name: name, Status: container.ContainerStatus_CREATED}, nil
+}
+
+func (c *fakeContainer) StartContainer(containerID string) error {
+	return nil
+}
+
+func (c *fakeContainer) InspectContainer(name string) (*container.BcsContainerInfo, error) {
+	return &container.BcsContainerInfo{Name: name, Status: container.ContainerStatus_EXITED}, nil
+}
+
+func (c *fakeContainer) RemoveContainer(name string, force bool) error {
+	c.removed = append(c.removed, name)
+	return nil
+}
+
+func (c *fakeContainer) UploadToContainer(containerID, source, dest string) error {
+	data, err := ioutil.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	c.uploaded[containerID+":"+dest] = string(data)
+	return nil
+}
+
+func TestRunInitContainersWithConfigMapsAndSecrets(t *testing.T) {
+	sandbox, err := ioutil.TempDir("", "executor-sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(sandbox)
+	os.Setenv("MESOS_SANDBOX", sandbox)
+	defer os.Unsetenv("MESOS_SANDBOX")
+
+	encode := func(s string) *string {
+		return proto.String(base64.StdEncoding.EncodeToString([]byte(s)))
+	}
+	msgs := []*bcstype.BcsMessage{
+		{Type: bcstype.Msg_ENV.Enum(), Env: &bcstype.Msg_Env{Name: proto.String("CONFIG_ENV"), Value: encode("config")}},
+		{Type: bcstype.Msg_LOCALFILE.Enum(), Local: &bcstype.Msg_LocalFile{
+			To: proto.String("/etc/app/app.conf"), User: proto.String("root"), Right: proto.String("r"), Base64: encode("conf file")}},
+		{Type: bcstype.Msg_SECRET.Enum(), Secret: &bcstype.Msg_Secret{
+			Name: proto.String("SECRET_ENV"), Value: encode("secret"), Type: bcstype.Secret_Env.Enum()}},
+		{Type: bcstype.Msg_SECRET.Enum(), Secret: &bcstype.Msg_Secret{
+			Name: proto.String("/etc/app/token"), Value: encode("token file"), Type: bcstype.Secret_File.Enum()}},
+	}
+	fake := &fakeContainer{uploaded: make(map[string]string)}
+	executor := &BcsExecutor{exeCxt: context.Background(), driver: &fakeDriver{}, container: fake}
+	initContainers := []*bcstype.Container{
+		{Docker: &bcstype.Docker{Image: "busybox"}},
+		{Docker: &bcstype.Docker{Image: "alpine"}},
+	}
+
+	if err := executor.runInitContainers(initContainers, msgs, "bridge"); err != nil {
+		t.Fatalf("run init containers failed: %s", err.Error())
+	}
+	if len(fake.created) != 2 || len(fake.removed) != 2 {
+		t.Fatalf("expect 2 init containers created and removed, got %d, %d", len(fake.created), len(fake.removed))
+	}
+	for _, task := range fake.created {
+		env := make(map[string]string)
+		for _, kv := range task.Env {
+			env[kv.Key] = kv.Value
+		}
+		if env["CONFIG_ENV"] != "config" || env["SECRET_ENV"] != "secret" {
+			t.Errorf("init container %s lost configmap or secret environments: %v", task.Name, env)
+		}
+		id := "id-" + task.Name
+		if fake.uploaded[id+":/etc/app/app.conf"] != "conf file" || fake.uploaded[id+":/etc/app/token"] != "token file" {
+			t.Errorf("init container %s lost configmap or secret files: %v", task.Name, fake.uploaded)
+		}
+	}
+}
+
+func TestPreStopTimeout(t *testing.T) {
+	tests := []struct {
+		killPolicy int
+		hook       int
+		expect     int
+	}{
+		{killPolicy: 30, hook: 0, expect: 30},
+		{killPolicy: 30, hook: 10, expect: 10},
+		{killPolicy: 30, hook: 60, expect: 30},
+	}
+	for _, test := range tests {
+		hook := &commtypes.LifecycleHandler{TimeoutSeconds: test.hook}
+		if got := preStopTimeout(test.killPolicy, hook); got != test.expect {
+			t.Errorf("preStopTimeout(%d, %d) = %d, expect %d", test.killPolicy, test.hook, got, test.expect)
+		}
+	}
+}
+
+func TestRunLifecycleHandlerHttp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/slow":
+			time.Sleep(1500 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	host, portStr, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	executor := &BcsExecutor{}
+	task := &container.BcsContainerTask{Name: "test", RuntimeConf: &container.BcsContainerInfo{IPAddress: host}}
+	tests := []struct {
+		path string
+		err  bool
+	}{
+		{path: "/ok"},
+		{path: "/fail", err: true},
+		{path: "/slow", err: true},
+	}
+	for _, test := range tests {
+		handler := &commtypes.LifecycleHandler{Http: &commtypes.HttpGetAction{Port: int32(port), Path: test.path}}
+		err := executor.runLifecycleHandler(task, handler, 1)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expect error %t, got %v", test.path, test.err, err)
+		}
+	}
+
+	if err := executor.runLifecycleHandler(task, &commtypes.LifecycleHandler{}, 1); err == nil {
+		t.Errorf("expect error for handler without exec or http")
+	}
+}
@@ -35,6 +35,9 @@ type CommandFlags struct {
 	NetworkMode     string //mode for cni/cnm
 	CNIPluginDir    string //cni plugin directory, $CNIPluginDir/bin for binary, $CNIPluginDir/conf for configuration
 	NetworkImage    string //cni network images
+	CollectInterval int    //resource usage sampling interval in seconds, 0 for disable
+	CgroupRoot      string //cgroup mount point for reading container oom events
+	MetricAddress   string //address for exposing prometheus metrics, empty for disable
 }
 
 //NewCommandFlags return new DockerFalgs with default value
@@ -46,6 +49,9 @@ func NewCommandFlags() *CommandFlags {
 		MappedDirectory: "/etc/mnt/bcs",
 		NetworkMode:     "",
 		CNIPluginDir:    DefaultCNIDirectory,
+		CollectInterval: 30,
+		CgroupRoot:      "/sys/fs/cgroup",
+		MetricAddress:   "",
 	}
 }
 
@@ -60,6 +66,9 @@ func ParseCmdFlags() *CommandFlags {
 	flag.StringVar(&cmdFlag.CNIPluginDir, "cni-plugin", cmdFlag.CNIPluginDir, "cni interface plugin directory, $cni_plugin/bin for binary, $cni_plugin/conf for configuration")
 	flag.StringVar(&cmdFlag.NetworkMode, "network-mode", cmdFlag.NetworkMode, "container network mode: cni or cnm. default empty")
 	flag.StringVar(&cmdFlag.NetworkImage, "network-image", cmdFlag.NetworkImage, "container network image")
+	flag.IntVar(&cmdFlag.CollectInterval, "collect-interval", cmdFlag.CollectInterval, "interval seconds for sampling container resource usage, 0 for disable")
+	flag.StringVar(&cmdFlag.CgroupRoot, "cgroup-root", cmdFlag.CgroupRoot, "cgroup mount point for reading container oom events")
+	flag.StringVar(&cmdFlag.MetricAddress, "metric-address", cmdFlag.MetricAddress, "address for exposing container resource usage metrics, like 127.0.0.1:0, empty for disable")
 	util.InitFlags()
 	//parse base64 uuid to password, skip if uuid empty
 	if len(cmdFlag.Passwd) != 0 {
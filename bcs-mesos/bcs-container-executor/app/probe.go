@@ -0,0 +1,96 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package app
+
+import (
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-container-executor/container"
+	"bk-bcs/bcs-mesos/bcs-container-executor/healthcheck"
+	"bk-bcs/bcs-mesos/bcs-container-executor/logs"
+	bcstype "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"fmt"
+)
+
+//defaultStartupFailures consecutive failures of startup check before container failed
+const defaultStartupFailures = 3
+
+//probeSetting create readiness & startup checkers from DataClass,
+//health check is paused until startup check passed, at most one check for each
+func (executor *BcsExecutor) probeSetting(containerTask *container.BcsContainerTask, dataClass *bcstype.DataClass) error {
+	if len(dataClass.ReadinessChecks) > 1 {
+		return fmt.Errorf("readiness check: only one check is supported")
+	}
+	if len(dataClass.StartupChecks) > 1 {
+		return fmt.Errorf("startup check: only one check is supported")
+	}
+	if len(dataClass.ReadinessChecks) == 1 {
+		checker, err := newProbeChecker(containerTask.Name, dataClass.ReadinessChecks[0])
+		if err != nil {
+			return fmt.Errorf("readiness check: %s", err.Error())
+		}
+		containerTask.ReadinessCheck = checker
+	}
+	if len(dataClass.StartupChecks) == 1 {
+		check := dataClass.StartupChecks[0]
+		checker, err := newProbeChecker(containerTask.Name, check)
+		if err != nil {
+			return fmt.Errorf("startup check: %s", err.Error())
+		}
+		containerTask.StartupCheck = checker
+		containerTask.StartupFailures = int(check.ConsecutiveFailures)
+		if containerTask.StartupFailures <= 0 {
+			containerTask.StartupFailures = defaultStartupFailures
+		}
+	}
+	if containerTask.StartupCheck != nil && containerTask.HealthCheck != nil {
+		logs.Infof("container %s has startup check, pause health check until startup check passed\n", containerTask.Name)
+		containerTask.HealthCheck.Pause()
+	}
+	return nil
+}
+
+//newProbeChecker create checker for readiness or startup check,
+//DelaySeconds is the waiting time before the first check
+func newProbeChecker(name string, check *commtypes.HealthCheck) (healthcheck.Checker, error) {
+	tm := &healthcheck.TimeMechanism{
+		IntervalSeconds:     check.IntervalSeconds,
+		TimeoutSeconds:      check.TimeoutSeconds,
+		ConsecutiveFailures: int(check.ConsecutiveFailures),
+		GracePeriodSeconds:  check.DelaySeconds,
+	}
+	switch check.Type {
+	case commtypes.BcsHealthCheckType_HTTP:
+		if check.Http == nil {
+			return nil, fmt.Errorf("http check data is empty")
+		}
+		return healthcheck.NewHTTPChecker(name, check.Http.Scheme, int(check.Http.Port), check.Http.Path, tm, nil)
+	case commtypes.BcsHealthCheckType_TCP:
+		if check.Tcp == nil {
+			return nil, fmt.Errorf("tcp check data is empty")
+		}
+		return healthcheck.NewTCPChecker(name, int(check.Tcp.Port), tm, nil)
+	default:
+		return nil, fmt.Errorf("unsupported check type %s", check.Type)
+	}
+}
+
+//startupFailedTask get the first container task whose startup check failed
+func startupFailedTask(tasks map[string]*container.BcsContainerTask) *container.BcsContainerTask {
+	for _, task := range tasks {
+		if container.StartupFailed(task) {
+			return task
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package app
+
+import (
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-container-executor/container"
+	bcstype "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"testing"
+)
+
+func newTCPCheck(failures uint32) *commtypes.HealthCheck {
+	return &commtypes.HealthCheck{
+		Type:                commtypes.BcsHealthCheckType_TCP,
+		IntervalSeconds:     10,
+		TimeoutSeconds:      5,
+		ConsecutiveFailures: failures,
+		Tcp:                 &commtypes.TcpHealthCheck{Port: 8080},
+	}
+}
+
+func TestProbeSetting(t *testing.T) {
+	executor := &BcsExecutor{}
+	tests := []struct {
+		name      string
+		dataClass *bcstype.DataClass
+		failures  int
+		expectErr bool
+	}{
+		{
+			name:      "default startup failures",
+			dataClass: &bcstype.DataClass{StartupChecks: []*commtypes.HealthCheck{newTCPCheck(0)}},
+			failures:  defaultStartupFailures,
+		},
+		{
+			name:      "startup failures",
+			dataClass: &bcstype.DataClass{StartupChecks: []*commtypes.HealthCheck{newTCPCheck(10)}},
+			failures:  10,
+		},
+		{
+			name:      "multiple readiness checks",
+			dataClass: &bcstype.DataClass{ReadinessChecks: []*commtypes.HealthCheck{newTCPCheck(0), newTCPCheck(0)}},
+			expectErr: true,
+		},
+		{
+			name:      "multiple startup checks",
+			dataClass: &bcstype.DataClass{StartupChecks: []*commtypes.HealthCheck{newTCPCheck(0), newTCPCheck(0)}},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		task := &container.BcsContainerTask{Name: "test", RuntimeConf: &container.BcsContainerInfo{}}
+		err := executor.probeSetting(task, test.dataClass)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: expect error but got nil", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err.Error())
+			continue
+		}
+		if task.StartupCheck == nil || task.StartupFailures != test.failures {
+			t.Errorf("%s: expect startup failures %d, got %d", test.name, test.failures, task.StartupFailures)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package app
+
+import (
+	"bk-bcs/bcs-mesos/bcs-container-executor/collector"
+	"bk-bcs/bcs-mesos/bcs-container-executor/logs"
+	bcstype "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+//startCollector start sampling resource usage of all containers in pod,
+//usage is reported to scheduler and exposed as prometheus metrics
+func (executor *BcsExecutor) startCollector() {
+	if executor.flag.CollectInterval <= 0 {
+		logs.Infoln("BcsExecutor resource usage collector is disabled")
+		return
+	}
+	executor.collector = collector.NewCollector(
+		time.Duration(executor.flag.CollectInterval)*time.Second,
+		executor.flag.CgroupRoot,
+		executor.container,
+		executor.usageTargets,
+		executor.reportUsage,
+	)
+	go executor.collector.Run(executor.exeCxt)
+
+	if executor.flag.MetricAddress == "" {
+		return
+	}
+	if err := prometheus.Register(executor.collector); err != nil {
+		logs.Errorf("BcsExecutor register usage metrics failed: %s\n", err.Error())
+		return
+	}
+	listener, err := net.Listen("tcp", executor.flag.MetricAddress)
+	if err != nil {
+		logs.Errorf("BcsExecutor listen metric address %s failed: %s\n", executor.flag.MetricAddress, err.Error())
+		return
+	}
+	logs.Infof("BcsExecutor expose usage metrics on %s/metrics\n", listener.Addr().String())
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			logs.Errorf("BcsExecutor metric server exit: %s\n", err.Error())
+		}
+	}()
+	go func() {
+		<-executor.exeCxt.Done()
+		listener.Close()
+	}()
+}
+
+//usageTargets list all running containers of pod for collector
+func (executor *BcsExecutor) usageTargets() []*collector.Target {
+	executor.exeLock.RLock()
+	defer executor.exeLock.RUnlock()
+	var targets []*collector.Target
+	for _, name := range executor.tasks.GetAllContainerID() {
+		info := executor.tasks.GetContainer(name)
+		taskInfo := executor.tasks.GetTaskByContainerID(name)
+		if info == nil || taskInfo == nil || info.ID == "" {
+			continue
+		}
+		targets = append(targets, &collector.Target{
+			TaskID:      taskInfo.GetTaskId().GetValue(),
+			ContainerID: info.ID,
+			Pid:         info.Pid,
+		})
+	}
+	return targets
+}
+
+//reportUsage send taskgroup usage to scheduler by framework message
+func (executor *BcsExecutor) reportUsage(usage *bcstype.TaskGroupUsage) {
+	if len(usage.Containers) == 0 {
+		return
+	}
+	taskInfo := executor.tasks.GetTask(usage.Containers[0].TaskID)
+	if taskInfo == nil {
+		return
+	}
+	bcsMsg := &bcstype.BcsMessage{
+		Type:           bcstype.Msg_TASKGROUP_USAGE.Enum(),
+		TaskID:         taskInfo.GetTaskId(),
+		TaskGroupUsage: usage,
+	}
+	by, _ := json.Marshal(bcsMsg)
+	if _, err := executor.driver.SendFrameworkMessage(string(by)); err != nil {
+		logs.Errorf("BcsExecutor send usage framework message error %s\n", err.Error())
+	}
+}
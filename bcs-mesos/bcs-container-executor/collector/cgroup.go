@@ -0,0 +1,84 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//readOOMEvents read oom kill count of container memory cgroup.
+//cgroup v1 reads memory.oom_control, cgroup v2 reads memory.events
+func readOOMEvents(procRoot, cgroupRoot string, pid int) (uint64, error) {
+	v1Path, v2Path, err := memoryCgroupPath(filepath.Join(procRoot, strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return 0, err
+	}
+	if v1Path != "" {
+		return readCounter(filepath.Join(cgroupRoot, "memory", v1Path, "memory.oom_control"), "oom_kill")
+	}
+	if v2Path != "" {
+		return readCounter(filepath.Join(cgroupRoot, v2Path, "memory.events"), "oom_kill")
+	}
+	return 0, fmt.Errorf("no memory cgroup found for pid %d", pid)
+}
+
+//memoryCgroupPath parse /proc/<pid>/cgroup, return memory controller path
+//of cgroup v1, or unified path of cgroup v2
+func memoryCgroupPath(file string) (string, string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+	var v1Path, v2Path string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		//hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			v2Path = parts[2]
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			if controller == "memory" {
+				v1Path = parts[2]
+			}
+		}
+	}
+	return v1Path, v2Path, scanner.Err()
+}
+
+//readCounter read "key value" formatted cgroup file, missing key is zero
+func readCounter(file, key string) (uint64, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	return 0, nil
+}
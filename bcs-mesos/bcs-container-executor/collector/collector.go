@@ -12,3 +12,164 @@
  */
 
 package collector
+
+import (
+	"bk-bcs/bcs-mesos/bcs-container-executor/logs"
+	schedTypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	//DefaultInterval default sampling interval
+	DefaultInterval = 30 * time.Second
+	//DefaultCgroupRoot default cgroup mount point
+	DefaultCgroupRoot = "/sys/fs/cgroup"
+)
+
+//Target container to sample in one round
+type Target struct {
+	TaskID      string
+	ContainerID string
+	Pid         int
+}
+
+//StatsGetter get cumulative usage of container, implemented by container.Container
+type StatsGetter interface {
+	StatsContainer(containerID string) (*schedTypes.ContainerUsage, error)
+}
+
+//TargetFunc list all containers need to sample
+type TargetFunc func() []*Target
+
+//ReportFunc handle usage of each sampling round
+type ReportFunc func(usage *schedTypes.TaskGroupUsage)
+
+//Collector sample resource usage of all containers in pod
+//on interval, report usage and expose them as prometheus metrics
+type Collector struct {
+	interval   time.Duration
+	cgroupRoot string
+	procRoot   string
+	stats      StatsGetter
+	targets    TargetFunc
+	report     ReportFunc
+
+	lock   sync.RWMutex
+	latest *schedTypes.TaskGroupUsage
+	//last cpu sample of container, for cpu usage computing
+	lastCPU map[string]cpuSample
+}
+
+type cpuSample struct {
+	total uint64
+	at    time.Time
+}
+
+//NewCollector create collector, report can be nil
+func NewCollector(interval time.Duration, cgroupRoot string, stats StatsGetter, targets TargetFunc, report ReportFunc) *Collector {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if cgroupRoot == "" {
+		cgroupRoot = DefaultCgroupRoot
+	}
+	return &Collector{
+		interval:   interval,
+		cgroupRoot: cgroupRoot,
+		procRoot:   "/proc",
+		stats:      stats,
+		targets:    targets,
+		report:     report,
+		lastCPU:    make(map[string]cpuSample),
+	}
+}
+
+//Run sampling until context done
+func (c *Collector) Run(cxt context.Context) {
+	tick := time.NewTicker(c.interval)
+	defer tick.Stop()
+	logs.Infof("resource usage collector start, interval %s\n", c.interval)
+	for {
+		select {
+		case <-cxt.Done():
+			logs.Infoln("resource usage collector quit.")
+			return
+		case <-tick.C:
+			usage := c.Sample()
+			if usage == nil || len(usage.Containers) == 0 {
+				continue
+			}
+			if c.report != nil {
+				c.report(usage)
+			}
+		}
+	}
+}
+
+//Latest get usage of last sampling round
+func (c *Collector) Latest() *schedTypes.TaskGroupUsage {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.latest
+}
+
+//Sample sample all target containers once
+func (c *Collector) Sample() *schedTypes.TaskGroupUsage {
+	now := time.Now()
+	usage := &schedTypes.TaskGroupUsage{
+		Timestamp: now.Unix(),
+		Interval:  int64(c.interval / time.Second),
+	}
+	seen := make(map[string]bool)
+	for _, target := range c.targets() {
+		if target == nil || target.ContainerID == "" {
+			continue
+		}
+		cu, err := c.stats.StatsContainer(target.ContainerID)
+		if err != nil {
+			logs.Errorf("collector get stats of container %s failed: %s\n", target.ContainerID, err.Error())
+			continue
+		}
+		cu.TaskID = target.TaskID
+		cu.ContainerID = target.ContainerID
+		if target.Pid > 0 {
+			oom, err := readOOMEvents(c.procRoot, c.cgroupRoot, target.Pid)
+			if err != nil {
+				logs.Errorf("collector read oom events of container %s failed: %s\n", target.ContainerID, err.Error())
+			}
+			cu.OOMEvents = oom
+		}
+		seen[target.ContainerID] = true
+		usage.Containers = append(usage.Containers, cu)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, cu := range usage.Containers {
+		cu.CPUUsage = cpuRate(c.lastCPU[cu.ContainerID], cu.CPUTotalNanos, now)
+		c.lastCPU[cu.ContainerID] = cpuSample{total: cu.CPUTotalNanos, at: now}
+	}
+	//clean containers gone
+	for id := range c.lastCPU {
+		if !seen[id] {
+			delete(c.lastCPU, id)
+		}
+	}
+	c.latest = usage
+	return usage
+}
+
+//cpuRate cores used between last sample and now
+func cpuRate(last cpuSample, total uint64, now time.Time) float64 {
+	if last.at.IsZero() || total < last.total {
+		return 0
+	}
+	elapsed := now.Sub(last.at)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(total-last.total) / float64(elapsed.Nanoseconds())
+}
@@ -0,0 +1,109 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	schedTypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeStats struct {
+	cpu map[string]uint64
+}
+
+func (f *fakeStats) StatsContainer(containerID string) (*schedTypes.ContainerUsage, error) {
+	return &schedTypes.ContainerUsage{CPUTotalNanos: f.cpu[containerID], MemoryUsage: 1024}, nil
+}
+
+func TestCPURate(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		last  cpuSample
+		total uint64
+		want  float64
+	}{
+		{"first sample", cpuSample{}, 100, 0},
+		{"two cores", cpuSample{total: 0, at: now.Add(-time.Second)}, 2e9, 2},
+		{"counter reset", cpuSample{total: 5e9, at: now.Add(-time.Second)}, 1e9, 0},
+	}
+	for _, test := range tests {
+		if got := cpuRate(test.last, test.total, now); got != test.want {
+			t.Errorf("%s: expect %f, got %f", test.name, test.want, got)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	stats := &fakeStats{cpu: map[string]uint64{"c1": 1e9, "c2": 0}}
+	targets := []*Target{{TaskID: "t1", ContainerID: "c1"}, {TaskID: "t2", ContainerID: "c2"}}
+	c := NewCollector(time.Second, "", stats, func() []*Target { return targets }, nil)
+
+	usage := c.Sample()
+	if len(usage.Containers) != 2 || usage.MemoryUsage() != 2048 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+	if usage.Containers[0].TaskID != "t1" || usage.Containers[0].CPUUsage != 0 {
+		t.Errorf("unexpected first sample %+v", usage.Containers[0])
+	}
+
+	stats.cpu["c1"] = 1e10
+	targets = targets[:1]
+	usage = c.Sample()
+	if usage.CPUUsage() <= 0 {
+		t.Errorf("expect cpu usage after second sample, got %f", usage.CPUUsage())
+	}
+	if _, ok := c.lastCPU["c2"]; ok {
+		t.Errorf("expect gone container c2 cleaned")
+	}
+	if c.Latest() != usage {
+		t.Errorf("expect latest usage updated")
+	}
+}
+
+func TestReadOOMEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "collector")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	procRoot := filepath.Join(dir, "proc")
+	cgroupRoot := filepath.Join(dir, "cgroup")
+	write := func(file, content string) {
+		os.MkdirAll(filepath.Dir(file), 0755)
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	//cgroup v1
+	write(filepath.Join(procRoot, "100", "cgroup"), "4:cpu,cpuacct:/docker/abc\n9:memory:/docker/abc\n")
+	write(filepath.Join(cgroupRoot, "memory", "docker", "abc", "memory.oom_control"), "oom_kill_disable 0\nunder_oom 0\noom_kill 3\n")
+	//cgroup v2
+	write(filepath.Join(procRoot, "200", "cgroup"), "0::/system.slice/docker-def.scope\n")
+	write(filepath.Join(cgroupRoot, "system.slice", "docker-def.scope", "memory.events"), "low 0\nhigh 0\nmax 2\noom 1\noom_kill 1\n")
+
+	if n, err := readOOMEvents(procRoot, cgroupRoot, 100); err != nil || n != 3 {
+		t.Errorf("cgroup v1 expect 3 oom events, got %d, err %v", n, err)
+	}
+	if n, err := readOOMEvents(procRoot, cgroupRoot, 200); err != nil || n != 1 {
+		t.Errorf("cgroup v2 expect 1 oom events, got %d, err %v", n, err)
+	}
+	if _, err := readOOMEvents(procRoot, cgroupRoot, 300); err == nil {
+		t.Errorf("expect error for unknown pid")
+	}
+}
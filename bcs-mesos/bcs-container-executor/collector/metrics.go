@@ -0,0 +1,97 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	schedTypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricNamespace = "bcs_executor"
+
+var metricLabels = []string{"task_id", "container_id"}
+
+type usageMetric struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	value     func(*schedTypes.ContainerUsage) float64
+}
+
+func newUsageMetric(name, help string, valueType prometheus.ValueType, value func(*schedTypes.ContainerUsage) float64) *usageMetric {
+	return &usageMetric{
+		desc:      prometheus.NewDesc(prometheus.BuildFQName(metricNamespace, "container", name), help, metricLabels, nil),
+		valueType: valueType,
+		value:     value,
+	}
+}
+
+var usageMetrics = []*usageMetric{
+	newUsageMetric("cpu_usage_cores", "cpu cores used during last sampling interval", prometheus.GaugeValue,
+		func(u *schedTypes.ContainerUsage) float64 { return u.CPUUsage }),
+	newUsageMetric("cpu_usage_seconds_total", "cumulative cpu time consumed", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.CPUTotalNanos) / 1e9 }),
+	newUsageMetric("cpu_cfs_periods_total", "number of elapsed cfs enforcement periods", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.CPUPeriods) }),
+	newUsageMetric("cpu_cfs_throttled_periods_total", "number of throttled cfs periods", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.CPUThrottledPeriod) }),
+	newUsageMetric("cpu_cfs_throttled_seconds_total", "total time duration the container has been throttled", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.CPUThrottledNanos) / 1e9 }),
+	newUsageMetric("memory_usage_bytes", "current memory usage in bytes", prometheus.GaugeValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.MemoryUsage) }),
+	newUsageMetric("memory_working_set_bytes", "current working set in bytes, usage without inactive file cache", prometheus.GaugeValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.MemoryWorkingSet) }),
+	newUsageMetric("memory_limit_bytes", "memory limit in bytes", prometheus.GaugeValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.MemoryLimit) }),
+	newUsageMetric("memory_rss_bytes", "memory rss in bytes", prometheus.GaugeValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.MemoryRSS) }),
+	newUsageMetric("memory_cache_bytes", "memory page cache in bytes", prometheus.GaugeValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.MemoryCache) }),
+	newUsageMetric("memory_failures_total", "number of times memory usage hits limit", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.MemoryFailCount) }),
+	newUsageMetric("oom_events_total", "number of oom kill events of container", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.OOMEvents) }),
+	newUsageMetric("network_receive_bytes_total", "cumulative bytes received", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.NetRxBytes) }),
+	newUsageMetric("network_transmit_bytes_total", "cumulative bytes transmitted", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.NetTxBytes) }),
+	newUsageMetric("network_receive_packets_dropped_total", "cumulative received packets dropped", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.NetRxDropped) }),
+	newUsageMetric("network_transmit_packets_dropped_total", "cumulative transmitted packets dropped", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.NetTxDropped) }),
+	newUsageMetric("fs_reads_bytes_total", "cumulative bytes read from block devices", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.BlkReadBytes) }),
+	newUsageMetric("fs_writes_bytes_total", "cumulative bytes written to block devices", prometheus.CounterValue,
+		func(u *schedTypes.ContainerUsage) float64 { return float64(u.BlkWriteBytes) }),
+}
+
+//Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range usageMetrics {
+		ch <- m.desc
+	}
+}
+
+//Collect implements prometheus.Collector, exports usage of last sampling round
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	usage := c.Latest()
+	if usage == nil {
+		return
+	}
+	for _, cu := range usage.Containers {
+		for _, m := range usageMetrics {
+			ch <- prometheus.MustNewConstMetric(m.desc, m.valueType, m.value(cu), cu.TaskID, cu.ContainerID)
+		}
+	}
+}
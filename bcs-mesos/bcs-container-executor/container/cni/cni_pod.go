@@ -421,7 +421,15 @@ func (p *CNIPod) Start() error {
 		}
 		task.RuntimeConf.Message = "container is starting"
 		if p.events != nil && p.events.PostStart != nil {
-			p.events.PostStart(task)
+			if postErr := p.events.PostStart(task); postErr != nil {
+				logs.Errorf("CNIPod PostStart container %s err: %s\n", task.RuntimeConf.Name, postErr.Error())
+				p.conClient.StopContainer(task.RuntimeConf.Name, 1)
+				p.conClient.RemoveContainer(task.RuntimeConf.Name, true)
+				task.RuntimeConf.Status = container.ContainerStatus_EXITED
+				task.RuntimeConf.Message = "container PostStart failed: " + postErr.Error()
+				p.startFailedStop(postErr)
+				return postErr
+			}
 		}
 		p.runningContainer[task.RuntimeConf.Name] = task.RuntimeConf
 		//starting health
@@ -441,16 +449,19 @@ func (p *CNIPod) Stop(graceExit int) {
 	defer p.lock.Unlock()
 	p.podCancel()
 	logs.Infof("CNIPod prepare to stop %d running containers\n", len(p.runningContainer))
+	//preStop event, hooks of all containers run concurrently within kill policy
+	var tasks []*container.BcsContainerTask
+	for name := range p.runningContainer {
+		tasks = append(tasks, p.conTasks[name])
+	}
+	preStopCost := p.events.RunPreStop(tasks)
 	for name := range p.runningContainer {
-		//preStop event
 		task := p.conTasks[name]
-		if p.events != nil && p.events.PreStop != nil {
-			p.events.PreStop(task)
-		}
 		if task.HealthCheck != nil {
 			task.HealthCheck.Stop()
 		}
-		if err := p.conClient.StopContainer(name, task.KillPolicy); err != nil {
+		container.StopProbes(task)
+		if err := p.conClient.StopContainer(name, container.StopTimeout(task.KillPolicy, preStopCost)); err != nil {
 			logs.Errorf("CNIPod stop container %s failed: %s\n", name, err.Error())
 			//todo(developerJim): if container daemon connection broken, maybe try again later
 			continue
@@ -548,6 +559,7 @@ func (p *CNIPod) runningFailedStop(err error) {
 		if task.HealthCheck != nil {
 			task.HealthCheck.Stop()
 		}
+		container.StopProbes(task)
 		p.conClient.StopContainer(name, task.KillPolicy)
 		if task.AutoRemove {
 			p.conClient.RemoveContainer(name, true)
@@ -636,6 +648,7 @@ func (p *CNIPod) containerCheck() error {
 					task.HealthCheck.SetHost(p.cniIPAddr)
 					go task.HealthCheck.Start()
 				}
+				container.StartProbes(task, p.cniIPAddr)
 				running++
 				if running == len(p.runningContainer) && p.status != container.PodStatus_RUNNING {
 					p.status = container.PodStatus_RUNNING
@@ -648,6 +661,7 @@ func (p *CNIPod) containerCheck() error {
 				if task.HealthCheck != nil {
 					task.HealthCheck.Stop()
 				}
+				container.StopProbes(task)
 				delete(p.runningContainer, name)
 
 				p.exitCode = task.RuntimeConf.ExitCode
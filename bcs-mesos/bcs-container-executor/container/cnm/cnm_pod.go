@@ -247,6 +247,19 @@ func (p *DockerPod) Init() error {
 	p.netTask.RuntimeConf.NodeAddress = util.GetIPAddress()[0]
 	p.netTask.RuntimeConf.IPAddress = info.IPAddress
 	p.netTask.RuntimeConf.NetworkMode = info.NetworkMode
+	//network container is also business container in docker pod
+	if p.events != nil && p.events.PostStart != nil {
+		if postErr := p.events.PostStart(p.netTask); postErr != nil {
+			logs.Errorf("DockerPod PostStart master container %s err: %s\n", p.netTask.RuntimeConf.Name, postErr.Error())
+			p.conClient.StopContainer(p.netTask.RuntimeConf.ID, 1)
+			p.conClient.RemoveContainer(p.netTask.RuntimeConf.ID, true)
+			p.status = container.PodStatus_FAILED
+			p.message = "container PostStart failed: " + postErr.Error()
+			p.netTask.RuntimeConf.Status = container.ContainerStatus_EXITED
+			p.netTask.RuntimeConf.Message = p.message
+			return postErr
+		}
+	}
 	p.runningContainer[p.netTask.RuntimeConf.Name] = p.netTask.RuntimeConf
 	logs.Infof("DockerPod treat container [%s] net container, ip: %s\n", p.netTask.RuntimeConf.Name, p.cnmIPAddr)
 	return nil
@@ -319,7 +332,15 @@ func (p *DockerPod) Start() error {
 		}
 		task.RuntimeConf.Message = "container is starting"
 		if p.events != nil && p.events.PostStart != nil {
-			p.events.PostStart(task)
+			if postErr := p.events.PostStart(task); postErr != nil {
+				logs.Errorf("DockerPod PostStart container %s err: %s\n", task.RuntimeConf.Name, postErr.Error())
+				p.conClient.StopContainer(task.RuntimeConf.Name, 1)
+				p.conClient.RemoveContainer(task.RuntimeConf.Name, true)
+				task.RuntimeConf.Status = container.ContainerStatus_EXITED
+				task.RuntimeConf.Message = "container PostStart failed: " + postErr.Error()
+				p.startFailedStop(postErr)
+				return postErr
+			}
 		}
 		p.runningContainer[task.RuntimeConf.Name] = task.RuntimeConf
 		logs.Infof("Pod add container %s in running container.\n", task.RuntimeConf.Name)
@@ -339,17 +360,20 @@ func (p *DockerPod) Stop(graceExit int) {
 	defer p.lock.Unlock()
 	p.podCancel()
 	logs.Infof("DockerPod prepare to stop %d running containers\n", len(p.runningContainer))
+	//preStop event, hooks of all containers run concurrently within kill policy
+	var tasks []*container.BcsContainerTask
+	for name := range p.runningContainer {
+		tasks = append(tasks, p.conTasks[name])
+	}
+	preStopCost := p.events.RunPreStop(tasks)
 	for name := range p.runningContainer {
-		//preStop event
 		task := p.conTasks[name]
-		if p.events != nil && p.events.PreStop != nil {
-			p.events.PreStop(task)
-		}
 		if task.HealthCheck != nil {
 			task.HealthCheck.Stop()
 		}
+		container.StopProbes(task)
 
-		if err := p.conClient.StopContainer(name, task.KillPolicy); err != nil {
+		if err := p.conClient.StopContainer(name, container.StopTimeout(task.KillPolicy, preStopCost)); err != nil {
 			logs.Errorf("DockerPod stop container %s failed: %s\n", name, err.Error())
 			//todo(developerJim): if container daemon connection broken, maybe try again later
 			continue
@@ -459,6 +483,7 @@ func (p *DockerPod) runningFailedStop(err error) {
 		if task.HealthCheck != nil {
 			task.HealthCheck.Stop()
 		}
+		container.StopProbes(task)
 		p.conClient.StopContainer(name, task.KillPolicy)
 		if task.AutoRemove {
 			p.conClient.RemoveContainer(name, true)
@@ -556,6 +581,7 @@ func (p *DockerPod) containerCheck() error {
 					task.HealthCheck.SetHost(p.cnmIPAddr)
 					go task.HealthCheck.Start()
 				}
+				container.StartProbes(task, p.cnmIPAddr)
 				running++
 				if running == len(p.runningContainer) && p.status != container.PodStatus_RUNNING {
 					logs.Infoln("DockerPod status is first changing to RUNNING")
@@ -569,6 +595,7 @@ func (p *DockerPod) containerCheck() error {
 				if task.HealthCheck != nil {
 					task.HealthCheck.Stop()
 				}
+				container.StopProbes(task)
 				delete(p.runningContainer, name)
 
 				p.exitCode = task.RuntimeConf.ExitCode
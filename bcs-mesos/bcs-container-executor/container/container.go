@@ -47,6 +47,7 @@ type BcsContainerInfo struct {
 	Healthy                 bool                   `json:"Healthy,omitempty"`     //Container healthy
 	IsChecked               bool                   `json:",omitempty"`            //is health check
 	ConsecutiveFailureTimes int                    `json:",omitempty"`            //consecutive failure times
+	Ready                   bool                   `json:"Ready"`                 //ready to serve, readiness check passed
 	ExitCode                int                    `json:"ExitCode,omitempty"`    //container exit code
 	Hostname                string                 `json:"Hostname,omitempty"`    //container host name
 	NetworkMode             string                 `json:"NetworkMode,omitempty"` //Network mode for container
@@ -73,6 +74,7 @@ func (info *BcsContainerInfo) Update(other *BcsContainerInfo) {
 	info.Hostname = other.Hostname
 	info.IsChecked = other.IsChecked
 	info.ConsecutiveFailureTimes = other.ConsecutiveFailureTimes
+	info.Ready = other.Ready
 	if strings.Contains(other.NetworkMode, "container:") {
 		info.NetworkMode = "user"
 	} else {
@@ -132,4 +134,7 @@ type Container interface {
 
 	//exec command
 	RunCommandV2(ops *schedTypes.RequestCommandTask) (*schedTypes.ResponseCommandTask, error)
+
+	//StatsContainer get cumulative resource usage of container by id
+	StatsContainer(containerID string) (*schedTypes.ContainerUsage, error)
 }
@@ -607,3 +607,73 @@ func (docker *DockerContainer) CommitImage(id, image string) error {
 
 	return docker.client.PushImage(opts, auth)
 }
+
+//StatsContainer get one shot resource usage of container from docker stats api
+func (docker *DockerContainer) StatsContainer(containerID string) (*schedTypes.ContainerUsage, error) {
+	if docker.client == nil {
+		return nil, fmt.Errorf("docker client is nil")
+	}
+	statsCh := make(chan *dockerclient.Stats, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- docker.client.Stats(dockerclient.StatsOptions{
+			ID:      containerID,
+			Stats:   statsCh,
+			Stream:  false,
+			Timeout: 10 * time.Second,
+		})
+	}()
+	stats, ok := <-statsCh
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+	if !ok || stats == nil {
+		return nil, fmt.Errorf("container %s got no stats", containerID)
+	}
+	return convertDockerStats(containerID, stats), nil
+}
+
+//convertDockerStats convert docker stats to cumulative container usage
+func convertDockerStats(containerID string, stats *dockerclient.Stats) *schedTypes.ContainerUsage {
+	usage := &schedTypes.ContainerUsage{
+		ContainerID:        containerID,
+		CPUTotalNanos:      stats.CPUStats.CPUUsage.TotalUsage,
+		CPUPeriods:         stats.CPUStats.ThrottlingData.Periods,
+		CPUThrottledPeriod: stats.CPUStats.ThrottlingData.ThrottledPeriods,
+		CPUThrottledNanos:  stats.CPUStats.ThrottlingData.ThrottledTime,
+		MemoryUsage:        stats.MemoryStats.Usage,
+		MemoryLimit:        stats.MemoryStats.Limit,
+		MemoryRSS:          stats.MemoryStats.Stats.Rss,
+		MemoryCache:        stats.MemoryStats.Stats.Cache,
+		MemoryFailCount:    stats.MemoryStats.Failcnt,
+	}
+	//inactive file cache can be reclaimed, not counted in working set
+	if inactive := stats.MemoryStats.Stats.TotalInactiveFile; inactive < usage.MemoryUsage {
+		usage.MemoryWorkingSet = usage.MemoryUsage - inactive
+	}
+	for _, net := range stats.Networks {
+		usage.NetRxBytes += net.RxBytes
+		usage.NetTxBytes += net.TxBytes
+		usage.NetRxPackets += net.RxPackets
+		usage.NetTxPackets += net.TxPackets
+		usage.NetRxDropped += net.RxDropped
+		usage.NetTxDropped += net.TxDropped
+	}
+	for _, entry := range stats.BlkioStats.IOServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			usage.BlkReadBytes += entry.Value
+		case "write":
+			usage.BlkWriteBytes += entry.Value
+		}
+	}
+	for _, entry := range stats.BlkioStats.IOServicedRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			usage.BlkReadOps += entry.Value
+		case "write":
+			usage.BlkWriteOps += entry.Value
+		}
+	}
+	return usage
+}
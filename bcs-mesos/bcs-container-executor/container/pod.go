@@ -15,6 +15,8 @@ package container
 
 import (
 	schedTypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"sync"
+	"time"
 )
 
 //PodStatus for imply container status in pod
@@ -45,6 +47,37 @@ type PodEventHandler struct {
 	PostStop  ConEventCB //call after container stopped, not including failed stop
 }
 
+//RunPreStop call PreStop callback of all tasks concurrently, return time cost
+func (handler *PodEventHandler) RunPreStop(tasks []*BcsContainerTask) time.Duration {
+	start := time.Now()
+	if handler == nil || handler.PreStop == nil {
+		return 0
+	}
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		if task == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(task *BcsContainerTask) {
+			defer wg.Done()
+			handler.PreStop(task)
+		}(task)
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+//StopTimeout seconds left in kill policy after preStop hook cost,
+//container always gets at least 1 second for graceful exit
+func StopTimeout(killPolicy int, preStopCost time.Duration) int {
+	left := killPolicy - int(preStopCost/time.Second)
+	if left < 1 {
+		return 1
+	}
+	return left
+}
+
 //Pod interface for bcs
 type Pod interface {
 	IsHealthy() bool                                  //check pod is healthy
@@ -0,0 +1,71 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package container
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunPreStop(t *testing.T) {
+	var lock sync.Mutex
+	called := make(map[string]bool)
+	handler := &PodEventHandler{
+		PreStop: func(task *BcsContainerTask) error {
+			time.Sleep(100 * time.Millisecond)
+			lock.Lock()
+			called[task.Name] = true
+			lock.Unlock()
+			return nil
+		},
+	}
+	tasks := []*BcsContainerTask{{Name: "a"}, nil, {Name: "b"}, {Name: "c"}}
+
+	cost := handler.RunPreStop(tasks)
+	if len(called) != 3 || !called["a"] || !called["b"] || !called["c"] {
+		t.Errorf("expect preStop called for all tasks, got %v", called)
+	}
+	//hooks run concurrently, cost is about one hook
+	if cost < 100*time.Millisecond || cost >= 300*time.Millisecond {
+		t.Errorf("expect preStop hooks run concurrently, cost %s", cost)
+	}
+
+	var nilHandler *PodEventHandler
+	if cost := nilHandler.RunPreStop(tasks); cost != 0 {
+		t.Errorf("expect no cost without handler, got %s", cost)
+	}
+	if cost := (&PodEventHandler{}).RunPreStop(tasks); cost != 0 {
+		t.Errorf("expect no cost without preStop, got %s", cost)
+	}
+}
+
+func TestStopTimeout(t *testing.T) {
+	tests := []struct {
+		killPolicy int
+		cost       time.Duration
+		expect     int
+	}{
+		{killPolicy: 10, cost: 0, expect: 10},
+		{killPolicy: 10, cost: 3500 * time.Millisecond, expect: 7},
+		{killPolicy: 10, cost: 10 * time.Second, expect: 1},
+		{killPolicy: 10, cost: 30 * time.Second, expect: 1},
+		{killPolicy: 0, cost: 0, expect: 1},
+	}
+	for _, test := range tests {
+		if got := StopTimeout(test.killPolicy, test.cost); got != test.expect {
+			t.Errorf("StopTimeout(%d, %s) = %d, expect %d", test.killPolicy, test.cost, got, test.expect)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package container
+
+import (
+	"bk-bcs/bcs-mesos/bcs-container-executor/logs"
+)
+
+//StartProbes starting startup & readiness checks when container become RUNNING
+func StartProbes(task *BcsContainerTask, host string) {
+	if task.StartupCheck != nil && !task.startupPassed && !task.StartupCheck.IsStarting() {
+		logs.Infof("container [%s] is running, starting StartupChecker, ip: %s\n", task.Name, host)
+		task.StartupCheck.SetHost(host)
+		go task.StartupCheck.Start()
+	}
+	if task.ReadinessCheck != nil && !task.ReadinessCheck.IsStarting() {
+		logs.Infof("container [%s] is running, starting ReadinessChecker, ip: %s\n", task.Name, host)
+		task.ReadinessCheck.SetHost(host)
+		go task.ReadinessCheck.Start()
+	}
+}
+
+//StopProbes stop startup & readiness checks
+func StopProbes(task *BcsContainerTask) {
+	if task.StartupCheck != nil {
+		task.StartupCheck.Stop()
+	}
+	if task.ReadinessCheck != nil {
+		task.ReadinessCheck.Stop()
+	}
+}
+
+//StartupFailed check if startup check failed consecutively before its
+//first success, container should be killed and rescheduled if true
+func StartupFailed(task *BcsContainerTask) bool {
+	if task.StartupCheck == nil || task.startupPassed || task.StartupFailures <= 0 {
+		return false
+	}
+	return task.StartupCheck.ConsecutiveFailure() >= task.StartupFailures
+}
+
+//UpdateProbeStatus update healthy & ready status in RuntimeConf with
+//liveness, startup and readiness checks, return true if status changed.
+//liveness check keeps paused until the first success of startup check,
+//and container is not ready before that.
+func UpdateProbeStatus(task *BcsContainerTask) bool {
+	if task.StartupCheck != nil && !task.startupPassed && task.StartupCheck.IsTicks() && task.StartupCheck.IsHealthy() {
+		logs.Infof("container [%s] startup check passed, resume health check\n", task.Name)
+		task.startupPassed = true
+		task.StartupCheck.Stop()
+		if task.HealthCheck != nil {
+			task.HealthCheck.Resume()
+		}
+	}
+
+	var changed bool
+	if task.HealthCheck != nil {
+		if task.RuntimeConf.Healthy != task.HealthCheck.IsHealthy() || task.RuntimeConf.IsChecked != task.HealthCheck.IsTicks() ||
+			task.RuntimeConf.ConsecutiveFailureTimes != task.HealthCheck.ConsecutiveFailure() {
+			changed = true
+			task.RuntimeConf.Healthy = task.HealthCheck.IsHealthy()
+			task.RuntimeConf.IsChecked = task.HealthCheck.IsTicks()
+			task.RuntimeConf.ConsecutiveFailureTimes = task.HealthCheck.ConsecutiveFailure()
+		}
+	}
+
+	ready := task.StartupCheck == nil || task.startupPassed
+	if ready && task.ReadinessCheck != nil {
+		ready = task.ReadinessCheck.IsTicks() && task.ReadinessCheck.IsHealthy()
+	}
+	if task.RuntimeConf.Ready != ready {
+		logs.Infof("container [%s] ready status changed to %t\n", task.Name, ready)
+		changed = true
+		task.RuntimeConf.Ready = ready
+	}
+	return changed
+}
@@ -0,0 +1,113 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package container
+
+import (
+	"bk-bcs/bcs-mesos/bcs-container-executor/healthcheck"
+	"testing"
+)
+
+func newTestChecker(t *testing.T) *healthcheck.TCPChecker {
+	tm := &healthcheck.TimeMechanism{IntervalSeconds: 10, TimeoutSeconds: 5}
+	checker, err := healthcheck.NewTCPChecker("test", 8080, tm, nil)
+	if err != nil {
+		t.Fatalf("create checker failed: %s", err.Error())
+	}
+	return checker.(*healthcheck.TCPChecker)
+}
+
+func TestUpdateProbeStatus(t *testing.T) {
+	liveness := newTestChecker(t)
+	startup := newTestChecker(t)
+	readiness := newTestChecker(t)
+	liveness.Pause()
+	task := &BcsContainerTask{
+		Name:           "test",
+		RuntimeConf:    &BcsContainerInfo{Healthy: true},
+		HealthCheck:    liveness,
+		StartupCheck:   startup,
+		ReadinessCheck: readiness,
+	}
+
+	//startup check not ticks yet
+	UpdateProbeStatus(task)
+	if task.RuntimeConf.Ready || task.startupPassed {
+		t.Fatalf("task should not be ready before startup check passed")
+	}
+
+	//startup passed, readiness check failed
+	startup.Ticks = 1
+	readiness.Ticks = 1
+	readiness.Healthy = false
+	UpdateProbeStatus(task)
+	if !task.startupPassed || task.RuntimeConf.Ready {
+		t.Fatalf("expect startup passed but not ready, got startup %t, ready %t", task.startupPassed, task.RuntimeConf.Ready)
+	}
+
+	//readiness check passed
+	readiness.Healthy = true
+	if !UpdateProbeStatus(task) || !task.RuntimeConf.Ready {
+		t.Fatalf("expect ready status changed to true")
+	}
+	if UpdateProbeStatus(task) {
+		t.Fatalf("expect nothing changed")
+	}
+
+	//no probes, always ready
+	task = &BcsContainerTask{Name: "plain", RuntimeConf: &BcsContainerInfo{}}
+	if !UpdateProbeStatus(task) || !task.RuntimeConf.Ready {
+		t.Fatalf("task without probes should be ready")
+	}
+}
+
+func TestStartupFailed(t *testing.T) {
+	startup := newTestChecker(t)
+	task := &BcsContainerTask{
+		Name:            "test",
+		RuntimeConf:     &BcsContainerInfo{},
+		StartupCheck:    startup,
+		StartupFailures: 3,
+	}
+
+	tests := []struct {
+		failures int
+		expect   bool
+	}{
+		{failures: 0, expect: false},
+		{failures: 2, expect: false},
+		{failures: 3, expect: true},
+		{failures: 5, expect: true},
+	}
+	for _, test := range tests {
+		startup.Ticks = test.failures
+		startup.ConsecutiveFailures = test.failures
+		if got := StartupFailed(task); got != test.expect {
+			t.Errorf("failures %d: expect %t, got %t", test.failures, test.expect, got)
+		}
+	}
+
+	//failures after startup passed are handled by health check
+	startup.Ticks = 1
+	startup.Healthy = true
+	UpdateProbeStatus(task)
+	startup.ConsecutiveFailures = 3
+	if StartupFailed(task) {
+		t.Fatalf("startup check should not fail after passed")
+	}
+
+	//no startup check
+	if StartupFailed(&BcsContainerTask{Name: "plain", RuntimeConf: &BcsContainerInfo{}}) {
+		t.Fatalf("task without startup check should not fail")
+	}
+}
@@ -69,13 +69,19 @@ type BcsContainerTask struct {
 	BcsMessages     []*bcstypes.BcsMessage //bcs define message
 	RuntimeConf     *BcsContainerInfo      //container runtime info
 	HealthCheck     healthcheck.Checker    //for health check
+	ReadinessCheck  healthcheck.Checker    //for readiness check, container is ready to serve when passed
+	StartupCheck    healthcheck.Checker    //for startup check, HealthCheck is paused until passed
+	StartupFailures int                    //consecutive failures of StartupCheck before container failed
 	KillPolicy      int                    //kill policy timeout, unit is seconds
+	Lifecycle       *comtypes.Lifecycle    //postStart & preStop hooks
 	//container network flow limit args
 	NetLimit *comtypes.NetLimit
 	TaskId   string
 
 	Ipc string //IPC namespace to use
 
+	startupPassed bool //startup check passed once
+
 	/*Healthy                 bool `json:"Healthy,omitempty"` //Container healthy
 	IsChecked               bool `json:",omitempty"`        //is health check
 	ConsecutiveFailureTimes int  `json:",omitempty"`        //consecutive failure times*/
@@ -26,6 +26,7 @@ type MesosDriverOptionsOut struct {
 	conf.ZkConfig
 	conf.CertConfig
 	conf.LicenseServerConfig
+	conf.TracingConfig
 
 	conf.LogConfig
 	conf.ProcessConfig
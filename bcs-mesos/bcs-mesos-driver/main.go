@@ -18,6 +18,7 @@ import (
 	"bk-bcs/bcs-common/common/blog"
 	"bk-bcs/bcs-common/common/conf"
 	"bk-bcs/bcs-common/common/license"
+	"bk-bcs/bcs-common/common/tracing"
 	"bk-bcs/bcs-mesos/bcs-mesos-driver/app"
 	"bk-bcs/bcs-mesos/bcs-mesos-driver/app/options"
 	"fmt"
@@ -50,6 +51,11 @@ func main() {
 
 	license.CheckLicense(op.LicenseServerConfig)
 
+	if err := tracing.InitTracer("bcs-mesos-driver", op.TracingConfig); err != nil {
+		blog.Errorf("init tracer failed: %s", err.Error())
+	}
+	defer tracing.Shutdown()
+
 	if err := app.Run(opIn); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
@@ -18,6 +18,7 @@ import (
 	"bk-bcs/bcs-common/common/blog"
 	bhttp "bk-bcs/bcs-common/common/http"
 	"fmt"
+	"net/url"
 )
 
 func (s *Scheduler) RegisterCustomResource(body []byte) (string, error) {
@@ -135,9 +136,10 @@ func (s *Scheduler) ListCustomResource(ns, kind string) (string, error) {
 	return string(reply), nil
 }
 
-func (s *Scheduler) GetCustomResource(ns, kind, name string) (string, error) {
+//GetCustomResource get custom resource, version is the served version to convert to, empty means storage version
+func (s *Scheduler) GetCustomResource(ns, kind, name, version string) (string, error) {
 
-	blog.Info("Get custom resource(%s %s %s)", ns, kind, name)
+	blog.Info("Get custom resource(%s %s %s) version(%s)", ns, kind, name, version)
 
 	if s.GetHost() == "" {
 		blog.Error("no scheduler is connected by driver")
@@ -145,7 +147,11 @@ func (s *Scheduler) GetCustomResource(ns, kind, name string) (string, error) {
 		return err.Error(), err
 	}
 
-	url := fmt.Sprintf("%s/v1/crd/namespaces/%s/%s/%s", s.GetHost(), ns, kind, name)
+	query := ""
+	if version != "" {
+		query = "?version=" + url.QueryEscape(version)
+	}
+	url := fmt.Sprintf("%s/v1/crd/namespaces/%s/%s/%s%s", s.GetHost(), ns, kind, name, query)
 	blog.Info("post a request to url(%s), request:%s", url)
 
 	reply, err := s.client.GET(url, nil, nil)
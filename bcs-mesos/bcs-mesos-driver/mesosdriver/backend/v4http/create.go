@@ -20,6 +20,7 @@ import (
 	bcstype "bk-bcs/bcs-common/common/types"
 	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
 	"encoding/json"
+	"fmt"
 	//"github.com/golang/protobuf/proto"
 	"strconv"
 	"strings"
@@ -176,126 +177,203 @@ func (s *Scheduler) setVersionWithPodSpec(version *types.Version, spec *bcstype.
 		version.Process = append(version.Process, process)
 	}
 
+	if len(spec.PodSpec.InitContainers) > 0 && NumContainer <= 0 {
+		blog.Warn("initContainers can only work with containers.")
+		replyErr := bhttp.InternalError(common.BcsErrMesosDriverParameterErr, common.BcsErrMesosDriverParameterErrStr+"initContainers can only work with containers")
+		return nil, replyErr
+	}
+	for i := range spec.PodSpec.InitContainers {
+		c := spec.PodSpec.InitContainers[i]
+		if len(c.HealthChecks) > 0 || len(c.ReadinessChecks) > 0 || len(c.StartupChecks) > 0 || c.Lifecycle != nil || len(c.Ports) > 0 {
+			blog.Warn("initContainer %d can not set healthChecks, readinessChecks, startupChecks, lifecycle or ports.", i)
+			replyErr := bhttp.InternalError(common.BcsErrMesosDriverParameterErr, common.BcsErrMesosDriverParameterErrStr+"initContainers can not set healthChecks, readinessChecks, startupChecks, lifecycle or ports")
+			return nil, replyErr
+		}
+		version.InitContainers = append(version.InitContainers, newVersionContainer(spec, c))
+	}
+
 	for i := 0; i < NumContainer; i++ {
-		container := new(types.Container)
 		c := spec.PodSpec.Containers[i]
-
-		if c.Resources.Requests.Cpu == "" && c.Resources.Limits.Cpu != "" {
-			c.Resources.Requests.Cpu = c.Resources.Limits.Cpu
-			c.Resources.Requests.Mem = c.Resources.Limits.Mem
-			c.Resources.Requests.Storage = c.Resources.Limits.Storage
+		if err := checkProbes(c.ReadinessChecks); err != nil {
+			blog.Warn("container %d readinessChecks invalid: %s", i, err.Error())
+			replyErr := bhttp.InternalError(common.BcsErrMesosDriverParameterErr, common.BcsErrMesosDriverParameterErrStr+"readinessChecks "+err.Error())
+			return nil, replyErr
 		}
+		if err := checkProbes(c.StartupChecks); err != nil {
+			blog.Warn("container %d startupChecks invalid: %s", i, err.Error())
+			replyErr := bhttp.InternalError(common.BcsErrMesosDriverParameterErr, common.BcsErrMesosDriverParameterErrStr+"startupChecks "+err.Error())
+			return nil, replyErr
+		}
+		version.Container = append(version.Container, newVersionContainer(spec, c))
+	}
+
+	return version, nil
+}
 
-		container.Type = c.Type
-		//Resources
-		container.Resources = new(types.Resource)
-		container.Resources.Cpus, _ = strconv.ParseFloat(c.Resources.Requests.Cpu, 64)
-		container.Resources.Mem, _ = strconv.ParseFloat(c.Resources.Requests.Mem, 64)
-		container.Resources.Disk, _ = strconv.ParseFloat(c.Resources.Requests.Storage, 64)
-
-		//limit resuroces
-		container.LimitResoures = new(types.Resource)
-		container.LimitResoures.Cpus, _ = strconv.ParseFloat(c.Resources.Limits.Cpu, 64)
-		container.LimitResoures.Mem, _ = strconv.ParseFloat(c.Resources.Limits.Mem, 64)
-		container.LimitResoures.Disk, _ = strconv.ParseFloat(c.Resources.Limits.Storage, 64)
-
-		container.DataClass = &types.DataClass{
-			Resources: new(types.Resource),
-			Msgs:      []*types.BcsMessage{},
+//checkProbes validate readiness & startup checks, only one local HTTP or TCP check is supported
+func checkProbes(checks []*bcstype.HealthCheck) error {
+	if len(checks) > 1 {
+		return fmt.Errorf("only one check is supported")
+	}
+	for _, oneCheck := range checks {
+		switch oneCheck.Type {
+		case bcstype.BcsHealthCheckType_HTTP:
+			if oneCheck.Http == nil {
+				return fmt.Errorf("http check data is empty")
+			}
+		case bcstype.BcsHealthCheckType_TCP:
+			if oneCheck.Tcp == nil {
+				return fmt.Errorf("tcp check data is empty")
+			}
+		default:
+			return fmt.Errorf("check type %s not supported", oneCheck.Type)
+		}
+		if oneCheck.DelaySeconds < 0 {
+			oneCheck.DelaySeconds = 0
+		}
+		if oneCheck.IntervalSeconds <= 0 {
+			oneCheck.IntervalSeconds = 10
+		}
+		if oneCheck.TimeoutSeconds <= 0 {
+			oneCheck.TimeoutSeconds = 5
+		}
+		if oneCheck.TimeoutSeconds >= oneCheck.IntervalSeconds {
+			return fmt.Errorf("intervalSeconds must larger than timeoutSeconds")
 		}
+		if oneCheck.ConsecutiveFailures <= 0 {
+			oneCheck.ConsecutiveFailures = 3
+		}
+	}
+	return nil
+}
 
-		container.DataClass.Resources = container.Resources
-		container.DataClass.LimitResources = container.LimitResoures
+//newVersionContainer convert container in PodSpec to scheduler container
+func newVersionContainer(spec *bcstype.PodTemplateSpec, c bcstype.Container) *types.Container {
+	container := new(types.Container)
 
-		//set network flow limit parameters
-		container.NetLimit = spec.PodSpec.NetLimit
-		container.DataClass.NetLimit = container.NetLimit
+	if c.Resources.Requests.Cpu == "" && c.Resources.Limits.Cpu != "" {
+		c.Resources.Requests.Cpu = c.Resources.Limits.Cpu
+		c.Resources.Requests.Mem = c.Resources.Limits.Mem
+		c.Resources.Requests.Storage = c.Resources.Limits.Storage
+	}
 
-		//docker
-		container.Docker = new(types.Docker)
-		container.Docker.Image = c.Image
+	container.Type = c.Type
+	//Resources
+	container.Resources = new(types.Resource)
+	container.Resources.Cpus, _ = strconv.ParseFloat(c.Resources.Requests.Cpu, 64)
+	container.Resources.Mem, _ = strconv.ParseFloat(c.Resources.Requests.Mem, 64)
+	container.Resources.Disk, _ = strconv.ParseFloat(c.Resources.Requests.Storage, 64)
+
+	//limit resuroces
+	container.LimitResoures = new(types.Resource)
+	container.LimitResoures.Cpus, _ = strconv.ParseFloat(c.Resources.Limits.Cpu, 64)
+	container.LimitResoures.Mem, _ = strconv.ParseFloat(c.Resources.Limits.Mem, 64)
+	container.LimitResoures.Disk, _ = strconv.ParseFloat(c.Resources.Limits.Storage, 64)
+
+	container.DataClass = &types.DataClass{
+		Resources: new(types.Resource),
+		Msgs:      []*types.BcsMessage{},
+	}
 
-		container.Docker.Hostname = c.Hostname
-		container.Docker.ImagePullUser = c.ImagePullUser
-		container.Docker.ImagePullPasswd = c.ImagePullPasswd
+	container.DataClass.Resources = container.Resources
+	container.DataClass.LimitResources = container.LimitResoures
 
-		container.Docker.ForcePullImage = false
-		if c.ImagePullPolicy == bcstype.ImagePullPolicy_ALWAYS {
-			container.Docker.ForcePullImage = true
-		}
+	//set network flow limit parameters
+	container.NetLimit = spec.PodSpec.NetLimit
+	container.DataClass.NetLimit = container.NetLimit
 
-		container.Docker.Privileged = c.Privileged
-		container.Docker.Network = spec.PodSpec.NetworkMode
-		container.Docker.NetworkType = spec.PodSpec.NetworkType
-		container.Docker.Command = c.Command
-		container.Docker.Arguments = c.Args
+	//docker
+	container.Docker = new(types.Docker)
+	container.Docker.Image = c.Image
 
-		//parameter
-		container.Docker.Parameters = []*types.Parameter{}
-		for _, ps := range c.Parameters {
-			container.Docker.Parameters = append(container.Docker.Parameters, &types.Parameter{Key: ps.Key, Value: ps.Value})
-		}
+	container.Docker.Hostname = c.Hostname
+	container.Docker.ImagePullUser = c.ImagePullUser
+	container.Docker.ImagePullPasswd = c.ImagePullPasswd
 
-		//portmaping
-		container.Docker.PortMappings = []*types.PortMapping{}
-		for _, port := range c.Ports {
-			portMap := new(types.PortMapping)
-			portMap.ContainerPort = int32(port.ContainerPort)
+	container.Docker.ForcePullImage = false
+	if c.ImagePullPolicy == bcstype.ImagePullPolicy_ALWAYS {
+		container.Docker.ForcePullImage = true
+	}
 
-			portMap.HostPort = int32(port.HostPort)
-			portMap.Name = port.Name
-			portMap.Protocol = port.Protocol
+	container.Docker.Privileged = c.Privileged
+	container.Docker.Network = spec.PodSpec.NetworkMode
+	container.Docker.NetworkType = spec.PodSpec.NetworkType
+	container.Docker.Command = c.Command
+	container.Docker.Arguments = c.Args
 
-			container.Docker.PortMappings = append(container.Docker.PortMappings, portMap)
-		}
+	//parameter
+	container.Docker.Parameters = []*types.Parameter{}
+	for _, ps := range c.Parameters {
+		container.Docker.Parameters = append(container.Docker.Parameters, &types.Parameter{Key: ps.Key, Value: ps.Value})
+	}
 
-		//env
-		container.Docker.Env = make(map[string]string)
-		for _, env := range c.Env {
-			container.Docker.Env[env.Name] = env.Value
-		}
+	//portmaping
+	container.Docker.PortMappings = []*types.PortMapping{}
+	for _, port := range c.Ports {
+		portMap := new(types.PortMapping)
+		portMap.ContainerPort = int32(port.ContainerPort)
 
-		//volume
-		container.Volumes = []*types.Volume{}
-		for _, volUnit := range c.Volumes {
-			vol := new(types.Volume)
-			vol.ContainerPath = volUnit.Volume.MountPath
-			vol.HostPath = volUnit.Volume.HostPath
-			vol.Mode = "RW"
-			if volUnit.Volume.ReadOnly {
-				vol.Mode = "R"
-			}
+		portMap.HostPort = int32(port.HostPort)
+		portMap.Name = port.Name
+		portMap.Protocol = port.Protocol
 
-			container.Volumes = append(container.Volumes, vol)
+		container.Docker.PortMappings = append(container.Docker.PortMappings, portMap)
+	}
+
+	//env
+	container.Docker.Env = make(map[string]string)
+	for _, env := range c.Env {
+		container.Docker.Env[env.Name] = env.Value
+	}
+
+	//volume
+	container.Volumes = []*types.Volume{}
+	for _, volUnit := range c.Volumes {
+		vol := new(types.Volume)
+		vol.ContainerPath = volUnit.Volume.MountPath
+		vol.HostPath = volUnit.Volume.HostPath
+		vol.Mode = "RW"
+		if volUnit.Volume.ReadOnly {
+			vol.Mode = "R"
 		}
 
-		//configmap
-		container.ConfigMaps = c.ConfigMaps
+		container.Volumes = append(container.Volumes, vol)
+	}
 
-		//secret
-		container.Secrets = c.Secrets
+	//configmap
+	container.ConfigMaps = c.ConfigMaps
 
-		container.HealthChecks = c.HealthChecks
-		for _, oneCheck := range container.HealthChecks {
-			if oneCheck.DelaySeconds <= 0 {
-				oneCheck.DelaySeconds = 10
-			}
-			if oneCheck.IntervalSeconds <= 0 {
-				oneCheck.IntervalSeconds = 60
-			}
-			if oneCheck.TimeoutSeconds <= 0 {
-				oneCheck.TimeoutSeconds = 20
-			}
-			if oneCheck.ConsecutiveFailures < 0 {
-				oneCheck.ConsecutiveFailures = 0
-			}
-			if oneCheck.GracePeriodSeconds <= 0 {
-				oneCheck.GracePeriodSeconds = 300
-			}
+	//secret
+	container.Secrets = c.Secrets
+
+	container.HealthChecks = c.HealthChecks
+	for _, oneCheck := range container.HealthChecks {
+		if oneCheck.DelaySeconds <= 0 {
+			oneCheck.DelaySeconds = 10
+		}
+		if oneCheck.IntervalSeconds <= 0 {
+			oneCheck.IntervalSeconds = 60
 		}
+		if oneCheck.TimeoutSeconds <= 0 {
+			oneCheck.TimeoutSeconds = 20
+		}
+		if oneCheck.ConsecutiveFailures < 0 {
+			oneCheck.ConsecutiveFailures = 0
+		}
+		if oneCheck.GracePeriodSeconds <= 0 {
+			oneCheck.GracePeriodSeconds = 300
+		}
+	}
 
-		version.Container = append(version.Container, container)
-	} //end for i
+	//lifecycle hooks
+	container.Lifecycle = c.Lifecycle
+	container.DataClass.Lifecycle = c.Lifecycle
 
-	return version, nil
+	//readiness & startup checks
+	container.ReadinessChecks = c.ReadinessChecks
+	container.StartupChecks = c.StartupChecks
+	container.DataClass.ReadinessChecks = c.ReadinessChecks
+	container.DataClass.StartupChecks = c.StartupChecks
+
+	return container
 }
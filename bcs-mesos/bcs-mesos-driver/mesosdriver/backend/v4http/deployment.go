@@ -123,6 +123,29 @@ func (s *Scheduler) UpdateDeployment(body []byte) (string, error) {
 	return string(reply), nil
 }
 
+//fetchDeployment get deployment with rolling update status from scheduler
+func (s *Scheduler) fetchDeployment(ns, name string) (string, error) {
+	blog.V(3).Infof("fetch deployment namespace %s name %s", ns, name)
+
+	if s.GetHost() == "" {
+		blog.Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		return err.Error(), err
+	}
+
+	url := fmt.Sprintf("%s/v1/deployment/%s/%s", s.GetHost(), ns, name)
+	blog.V(3).Infof("get a request to url(%s)", url)
+
+	reply, err := s.client.GET(url, nil, nil)
+	if err != nil {
+		blog.Error("get request to url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		return err.Error(), err
+	}
+
+	return string(reply), nil
+}
+
 func (s *Scheduler) deleteDeployment(ns, name string, enforce string) (string, error) {
 	blog.Info("delete deployment namespace %s name %s", ns, name)
 
@@ -0,0 +1,130 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package v4http
+
+import (
+	"bk-bcs/bcs-common/common"
+	"bk-bcs/bcs-common/common/blog"
+	bhttp "bk-bcs/bcs-common/common/http"
+)
+
+func (s *Scheduler) CreateDisruptionBudget(body []byte) (string, error) {
+	blog.Info("create disruptionbudget data(%s)", string(body))
+
+	if s.GetHost() == "" {
+		blog.Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		return err.Error(), err
+	}
+
+	url := s.GetHost() + "/v1/disruptionbudget"
+	blog.Info("post url(%s)", url)
+
+	reply, err := s.client.POST(url, nil, body)
+	if err != nil {
+		blog.Error("post url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		return err.Error(), err
+	}
+
+	return string(reply), nil
+}
+
+func (s *Scheduler) UpdateDisruptionBudget(body []byte) (string, error) {
+	blog.Info("update disruptionbudget data(%s)", string(body))
+
+	if s.GetHost() == "" {
+		blog.Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		return err.Error(), err
+	}
+
+	url := s.GetHost() + "/v1/disruptionbudget"
+	blog.Info("put url(%s)", url)
+
+	reply, err := s.client.PUT(url, nil, body)
+	if err != nil {
+		blog.Error("put url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		return err.Error(), err
+	}
+
+	return string(reply), nil
+}
+
+func (s *Scheduler) DeleteDisruptionBudget(ns string, name string) (string, error) {
+	blog.Info("delete disruptionbudget(%s, %s)", ns, name)
+
+	if s.GetHost() == "" {
+		blog.Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		return err.Error(), err
+	}
+
+	url := s.GetHost() + "/v1/disruptionbudget/" + ns + "/" + name
+	blog.Info("delete url(%s)", url)
+
+	reply, err := s.client.DELETE(url, nil, nil)
+	if err != nil {
+		blog.Error("delete url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		return err.Error(), err
+	}
+
+	return string(reply), nil
+}
+
+func (s *Scheduler) FetchDisruptionBudget(ns string, name string) (string, error) {
+	blog.Info("fetch disruptionbudget(%s, %s)", ns, name)
+
+	if s.GetHost() == "" {
+		blog.Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		return err.Error(), err
+	}
+
+	url := s.GetHost() + "/v1/disruptionbudget/" + ns + "/" + name
+	blog.Info("fetch url(%s)", url)
+
+	reply, err := s.client.GET(url, nil, nil)
+	if err != nil {
+		blog.Error("fetch url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		return err.Error(), err
+	}
+
+	return string(reply), nil
+}
+
+func (s *Scheduler) ListDisruptionBudgets(ns string) (string, error) {
+	blog.Info("list disruptionbudgets(%s)", ns)
+
+	if s.GetHost() == "" {
+		blog.Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		return err.Error(), err
+	}
+
+	url := s.GetHost() + "/v1/disruptionbudgets/" + ns
+	blog.Info("list url(%s)", url)
+
+	reply, err := s.client.GET(url, nil, nil)
+	if err != nil {
+		blog.Error("list url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		return err.Error(), err
+	}
+
+	return string(reply), nil
+}
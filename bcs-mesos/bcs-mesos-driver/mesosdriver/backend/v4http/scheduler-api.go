@@ -19,6 +19,7 @@ import (
 	bhttp "bk-bcs/bcs-common/common/http"
 	"bk-bcs/bcs-common/common/http/httpclient"
 	"bk-bcs/bcs-common/common/http/httpserver"
+	"bk-bcs/bcs-common/common/tracing"
 	"bk-bcs/bcs-common/common/types"
 	commonTypes "bk-bcs/bcs-common/common/types"
 	"bk-bcs/bcs-common/common/util"
@@ -74,6 +75,14 @@ func (s *Scheduler) GetHttpClient() *httpclient.HttpClient {
 	return s.client
 }
 
+//withRequest return shallow copy of scheduler whose client propagates
+//trace context and request id of req to bcs-scheduler
+func (s *Scheduler) withRequest(req *restful.Request) *Scheduler {
+	c := *s
+	c.client = s.client.WithContext(req.Request.Context())
+	return &c
+}
+
 func (s *Scheduler) initActions() {
 	s.acts = []*httpserver.Action{
 		/*================= application ====================*/
@@ -144,6 +153,7 @@ func (s *Scheduler) initActions() {
 		/*================= deployment ====================*/
 		httpserver.NewAction("POST", "/namespaces/{ns}/deployments", nil, s.createDeploymentHandler),
 		httpserver.NewAction("PUT", "/namespaces/{ns}/deployments", nil, s.udpateDeploymentHandler),
+		httpserver.NewAction("GET", "/namespaces/{ns}/deployments/{name}", nil, s.fetchDeploymentHandler),
 		httpserver.NewAction("DELETE", "/namespaces/{ns}/deployments/{name}", nil, s.deleteDeploymentHandler),
 		httpserver.NewAction("PUT", "/namespaces/{ns}/deployments/{name}/cancelupdate", nil, s.cancelupdateDeploymentHandler),
 		httpserver.NewAction("PUT", "/namespaces/{ns}/deployments/{name}/pauseupdate", nil, s.pauseupdateDeploymentHandler),
@@ -163,6 +173,9 @@ func (s *Scheduler) initActions() {
 		httpserver.NewAction("POST", "/agentsettings/update", nil, s.updateAgentSettingListHandler),
 		httpserver.NewAction("POST", "/agentsettings/enable", nil, s.enableAgentListHandler),
 		httpserver.NewAction("POST", "/agentsettings/disable", nil, s.disableAgentListHandler),
+		httpserver.NewAction("POST", "/agentsettings/{IP}/drain", nil, s.drainAgentHandler),
+		httpserver.NewAction("GET", "/agentsettings/{IP}/drain", nil, s.getAgentDrainHandler),
+		httpserver.NewAction("POST", "/agentsettings/{IP}/uncordon", nil, s.uncordonAgentHandler),
 		/*================= agentsetting ====================*/
 
 		/*-------------- custom resource -----------------*/
@@ -183,6 +196,11 @@ func (s *Scheduler) initActions() {
 		httpserver.NewAction("GET", "/definition/deployment/{ns}/{name}", nil, s.getDeploymentDefHander),
 		/*------------- definition --------------------*/
 
+		/*------------- recommendation --------------------*/
+		httpserver.NewAction("GET", "/recommendation/application/{ns}/{name}", nil, s.getApplicationRecommendationHandler),
+		httpserver.NewAction("GET", "/recommendation/deployment/{ns}/{name}", nil, s.getDeploymentRecommendationHandler),
+		/*------------- recommendation --------------------*/
+
 		/*================= command ====================*/
 		httpserver.NewAction("POST", "/command/application/{ns}/{name}", nil, s.sendApplicationCommandHandler),
 		httpserver.NewAction("GET", "/command/application/{ns}/{name}", nil, s.getApplicationCommandHandler),
@@ -199,6 +217,14 @@ func (s *Scheduler) initActions() {
 		httpserver.NewAction("GET", "/namespaces/{ns}/admissionwebhook/{name}", nil, s.FetchAdmissionwebhookHandler),
 		httpserver.NewAction("DELETE", "/admissionwebhooks", nil, s.FetchAllAdmissionwebhooksHandler),
 		/*================= admissionwebhook ====================*/
+
+		/*================= disruptionbudget ====================*/
+		httpserver.NewAction("POST", "/namespaces/{ns}/disruptionbudgets", nil, s.CreateDisruptionBudgetHandler),
+		httpserver.NewAction("PUT", "/namespaces/{ns}/disruptionbudgets", nil, s.UpdateDisruptionBudgetHandler),
+		httpserver.NewAction("DELETE", "/namespaces/{ns}/disruptionbudgets/{name}", nil, s.DeleteDisruptionBudgetHandler),
+		httpserver.NewAction("GET", "/namespaces/{ns}/disruptionbudgets/{name}", nil, s.FetchDisruptionBudgetHandler),
+		httpserver.NewAction("GET", "/namespaces/{ns}/disruptionbudgets", nil, s.ListDisruptionBudgetsHandler),
+		/*================= disruptionbudget ====================*/
 	}
 }
 
@@ -225,21 +251,21 @@ func (s *Scheduler) getApplicationDefHander(req *restful.Request, resp *restful.
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	blog.V(3).Infof("get definition for application(%s:%s) ", ns, name)
+	tracing.RequestLogger(req).V(3).Info("get definition for application(%s:%s) ", ns, name)
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
 	url := s.GetHost() + "/v1/definition/application/" + ns + "/" + name
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.GET(url, nil, nil)
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -254,21 +280,79 @@ func (s *Scheduler) getDeploymentDefHander(req *restful.Request, resp *restful.R
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	blog.V(3).Infof("get definition for deployment(%s:%s) ", ns, name)
+	tracing.RequestLogger(req).V(3).Info("get definition for deployment(%s:%s) ", ns, name)
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
 	url := s.GetHost() + "/v1/definition/deployment/" + ns + "/" + name
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.GET(url, nil, nil)
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	resp.Write([]byte(reply))
+	return
+}
+
+func (s *Scheduler) getApplicationRecommendationHandler(req *restful.Request, resp *restful.Response) {
+
+	ns := req.PathParameter("ns")
+	name := req.PathParameter("name")
+
+	tracing.RequestLogger(req).V(3).Info("get resource recommendation for application(%s:%s) ", ns, name)
+
+	if s.GetHost() == "" {
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	url := s.GetHost() + "/v1/recommendation/application/" + ns + "/" + name
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
+
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
+	if err != nil {
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	resp.Write([]byte(reply))
+	return
+}
+
+func (s *Scheduler) getDeploymentRecommendationHandler(req *restful.Request, resp *restful.Response) {
+
+	ns := req.PathParameter("ns")
+	name := req.PathParameter("name")
+
+	tracing.RequestLogger(req).V(3).Info("get resource recommendation for deployment(%s:%s) ", ns, name)
+
+	if s.GetHost() == "" {
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	url := s.GetHost() + "/v1/recommendation/deployment/" + ns + "/" + name
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
+
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
+	if err != nil {
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -281,21 +365,21 @@ func (s *Scheduler) getDeploymentDefHander(req *restful.Request, resp *restful.R
 func (s *Scheduler) disableAgentHandler(req *restful.Request, resp *restful.Response) {
 
 	IP := req.PathParameter("IP")
-	blog.V(3).Infof("disable agent %s", IP)
+	tracing.RequestLogger(req).V(3).Info("disable agent %s", IP)
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
 	url := s.GetHost() + "/v1/agentsetting/" + IP + "/disable"
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.POST(url, nil, nil)
+	reply, err := s.withRequest(req).client.POST(url, nil, nil)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -308,21 +392,21 @@ func (s *Scheduler) disableAgentHandler(req *restful.Request, resp *restful.Resp
 func (s *Scheduler) enableAgentHandler(req *restful.Request, resp *restful.Response) {
 
 	IP := req.PathParameter("IP")
-	blog.V(3).Infof("enable agent %s", IP)
+	tracing.RequestLogger(req).V(3).Info("enable agent %s", IP)
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
 	url := s.GetHost() + "/v1/agentsetting/" + IP + "/enable"
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.POST(url, nil, nil)
+	reply, err := s.withRequest(req).client.POST(url, nil, nil)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -335,21 +419,21 @@ func (s *Scheduler) enableAgentHandler(req *restful.Request, resp *restful.Respo
 func (s *Scheduler) getAgentSettingHandler(req *restful.Request, resp *restful.Response) {
 
 	IP := req.PathParameter("IP")
-	blog.V(3).Infof("get agent %s setting", IP)
+	tracing.RequestLogger(req).V(3).Info("get agent %s setting", IP)
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
 	url := s.GetHost() + "/v1/agentsetting/" + IP
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.GET(url, nil, nil)
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -361,10 +445,10 @@ func (s *Scheduler) getAgentSettingHandler(req *restful.Request, resp *restful.R
 
 func (s *Scheduler) getAgentSettingListHandler(req *restful.Request, resp *restful.Response) {
 
-	blog.V(3).Infof("get agentsettings")
+	tracing.RequestLogger(req).V(3).Info("get agentsettings")
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -373,11 +457,11 @@ func (s *Scheduler) getAgentSettingListHandler(req *restful.Request, resp *restf
 	ips := req.QueryParameter("ips")
 
 	url := s.GetHost() + "/v1/agentsettings?ips=" + ips
-	blog.V(3).Infof("get a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("get a request to url(%s)", url)
 
-	reply, err := s.client.GET(url, nil, nil)
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -389,10 +473,10 @@ func (s *Scheduler) getAgentSettingListHandler(req *restful.Request, resp *restf
 
 func (s *Scheduler) deleteAgentSettingListHandler(req *restful.Request, resp *restful.Response) {
 
-	blog.V(3).Infof("delete agentsettings")
+	tracing.RequestLogger(req).V(3).Info("delete agentsettings")
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -400,11 +484,11 @@ func (s *Scheduler) deleteAgentSettingListHandler(req *restful.Request, resp *re
 
 	ips := req.QueryParameter("ips")
 	url := s.GetHost() + "/v1/agentsettings/delete?ips=" + ips
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.POST(url, nil, nil)
+	reply, err := s.withRequest(req).client.POST(url, nil, nil)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -416,10 +500,10 @@ func (s *Scheduler) deleteAgentSettingListHandler(req *restful.Request, resp *re
 
 func (s *Scheduler) setAgentSettingListHandler(req *restful.Request, resp *restful.Response) {
 
-	blog.V(3).Infof("set agentsettings")
+	tracing.RequestLogger(req).V(3).Info("set agentsettings")
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -428,11 +512,11 @@ func (s *Scheduler) setAgentSettingListHandler(req *restful.Request, resp *restf
 	body, _ := s.getRequestInfo(req)
 
 	url := s.GetHost() + "/v1/agentsettings"
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.POST(url, nil, body)
+	reply, err := s.withRequest(req).client.POST(url, nil, body)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -444,10 +528,10 @@ func (s *Scheduler) setAgentSettingListHandler(req *restful.Request, resp *restf
 
 func (s *Scheduler) updateAgentSettingListHandler(req *restful.Request, resp *restful.Response) {
 
-	blog.V(3).Infof("update agentsettings")
+	tracing.RequestLogger(req).V(3).Info("update agentsettings")
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -456,11 +540,11 @@ func (s *Scheduler) updateAgentSettingListHandler(req *restful.Request, resp *re
 	body, _ := s.getRequestInfo(req)
 
 	url := s.GetHost() + "/v1/agentsettings/update"
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.POST(url, nil, body)
+	reply, err := s.withRequest(req).client.POST(url, nil, body)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -472,10 +556,10 @@ func (s *Scheduler) updateAgentSettingListHandler(req *restful.Request, resp *re
 
 func (s *Scheduler) enableAgentListHandler(req *restful.Request, resp *restful.Response) {
 
-	blog.V(3).Infof("enable agentlist")
+	tracing.RequestLogger(req).V(3).Info("enable agentlist")
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -483,11 +567,11 @@ func (s *Scheduler) enableAgentListHandler(req *restful.Request, resp *restful.R
 
 	ips := req.QueryParameter("ips")
 	url := s.GetHost() + "/v1/agentsettings/enable?ips=" + ips
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.POST(url, nil, nil)
+	reply, err := s.withRequest(req).client.POST(url, nil, nil)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -499,10 +583,10 @@ func (s *Scheduler) enableAgentListHandler(req *restful.Request, resp *restful.R
 
 func (s *Scheduler) disableAgentListHandler(req *restful.Request, resp *restful.Response) {
 
-	blog.V(3).Infof("disable agentlist")
+	tracing.RequestLogger(req).V(3).Info("disable agentlist")
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -511,11 +595,94 @@ func (s *Scheduler) disableAgentListHandler(req *restful.Request, resp *restful.
 	ips := req.QueryParameter("ips")
 
 	url := s.GetHost() + "/v1/agentsettings/disable?ips=" + ips
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
+
+	reply, err := s.withRequest(req).client.POST(url, nil, nil)
+	if err != nil {
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	resp.Write([]byte(reply))
+	return
+}
+
+func (s *Scheduler) drainAgentHandler(req *restful.Request, resp *restful.Response) {
+
+	IP := req.PathParameter("IP")
+	tracing.RequestLogger(req).V(3).Info("drain agent %s", IP)
+
+	if s.GetHost() == "" {
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	body, _ := s.getRequestInfo(req)
+
+	url := s.GetHost() + "/v1/agentsettings/" + IP + "/drain"
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.POST(url, nil, nil)
+	reply, err := s.withRequest(req).client.POST(url, nil, body)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	resp.Write([]byte(reply))
+	return
+}
+
+func (s *Scheduler) getAgentDrainHandler(req *restful.Request, resp *restful.Response) {
+
+	IP := req.PathParameter("IP")
+	tracing.RequestLogger(req).V(3).Info("get agent drain %s", IP)
+
+	if s.GetHost() == "" {
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	url := s.GetHost() + "/v1/agentsettings/" + IP + "/drain"
+	tracing.RequestLogger(req).V(3).Info("get a request to url(%s)", url)
+
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
+	if err != nil {
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
+		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	resp.Write([]byte(reply))
+	return
+}
+
+func (s *Scheduler) uncordonAgentHandler(req *restful.Request, resp *restful.Response) {
+
+	IP := req.PathParameter("IP")
+	tracing.RequestLogger(req).V(3).Info("uncordon agent %s", IP)
+
+	if s.GetHost() == "" {
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
+		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	url := s.GetHost() + "/v1/agentsettings/" + IP + "/uncordon"
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
+
+	reply, err := s.withRequest(req).client.POST(url, nil, nil)
+	if err != nil {
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -527,21 +694,21 @@ func (s *Scheduler) disableAgentListHandler(req *restful.Request, resp *restful.
 
 func (s *Scheduler) GetClusterResourcesHandler(req *restful.Request, resp *restful.Response) {
 
-	blog.V(3).Infof("get cluster resources")
+	tracing.RequestLogger(req).V(3).Info("get cluster resources")
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
 	url := s.GetHost() + "/v1/cluster/resources"
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.GET(url, nil, nil)
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
 	if err != nil {
-		blog.Error("get request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("get request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -552,21 +719,21 @@ func (s *Scheduler) GetClusterResourcesHandler(req *restful.Request, resp *restf
 }
 
 func (s *Scheduler) GetClusterEndpointsHandler(req *restful.Request, resp *restful.Response) {
-	blog.V(3).Infof("get cluster endpoints")
+	tracing.RequestLogger(req).V(3).Info("get cluster endpoints")
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
 	url := s.GetHost() + "/v1/cluster/endpoints"
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.GET(url, nil, nil)
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
 	if err != nil {
-		blog.Error("get request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("get request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -577,21 +744,21 @@ func (s *Scheduler) GetClusterEndpointsHandler(req *restful.Request, resp *restf
 }
 
 func (s *Scheduler) GetClusterCurrentOffersHandler(req *restful.Request, resp *restful.Response) {
-	blog.V(3).Infof("get cluster current offers")
+	tracing.RequestLogger(req).V(3).Info("get cluster current offers")
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
 	url := s.GetHost() + "/v1/cluster/current/offers"
-	blog.V(3).Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.GET(url, nil, nil)
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
 	if err != nil {
-		blog.Error("get request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("get request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -610,15 +777,15 @@ func (s *Scheduler) CreateConfigMapHandler(req *restful.Request, resp *restful.R
 
 	err = util.CheckKind(types.BcsDataType_CONFIGMAP, body)
 	if err != nil {
-		blog.Error("fail to create configmap(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to create configmap(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.CreateConfigMap(body)
+	reply, err := s.withRequest(req).CreateConfigMap(body)
 	if err != nil {
-		blog.Error("fail to create configmap(%s). reply(%s), err(%s)", string(body), reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to create configmap(%s). reply(%s), err(%s)", string(body), reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -635,15 +802,15 @@ func (s *Scheduler) UpdateConfigMapHandler(req *restful.Request, resp *restful.R
 
 	err = util.CheckKind(types.BcsDataType_CONFIGMAP, body)
 	if err != nil {
-		blog.Error("fail to update configmap(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to update configmap(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.UpdateConfigMap(body)
+	reply, err := s.withRequest(req).UpdateConfigMap(body)
 	if err != nil {
-		blog.Error("fail to update configmap(%s). reply(%s), err(%s)", string(body), reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to update configmap(%s). reply(%s), err(%s)", string(body), reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -655,9 +822,9 @@ func (s *Scheduler) DeleteConfigMapHandler(req *restful.Request, resp *restful.R
 
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
-	reply, err := s.DeleteConfigMap(ns, name)
+	reply, err := s.withRequest(req).DeleteConfigMap(ns, name)
 	if err != nil {
-		blog.Error("fail to delete configmap(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to delete configmap(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
 	}
 	resp.Write([]byte(reply))
 }
@@ -671,15 +838,15 @@ func (s *Scheduler) CreateSecretHandler(req *restful.Request, resp *restful.Resp
 
 	err = util.CheckKind(types.BcsDataType_SECRET, body)
 	if err != nil {
-		blog.Error("fail to create secret(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to create secret(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.CreateSecret(body)
+	reply, err := s.withRequest(req).CreateSecret(body)
 	if err != nil {
-		blog.Error("fail to create secret(%s). reply(%s), err(%s)", string(body), reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to create secret(%s). reply(%s), err(%s)", string(body), reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -696,15 +863,15 @@ func (s *Scheduler) UpdateSecretHandler(req *restful.Request, resp *restful.Resp
 
 	err = util.CheckKind(types.BcsDataType_SECRET, body)
 	if err != nil {
-		blog.Error("fail to update secret(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to update secret(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.UpdateSecret(body)
+	reply, err := s.withRequest(req).UpdateSecret(body)
 	if err != nil {
-		blog.Error("fail to update secret(%s). reply(%s), err(%s)", string(body), reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to update secret(%s). reply(%s), err(%s)", string(body), reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -716,9 +883,9 @@ func (s *Scheduler) DeleteSecretHandler(req *restful.Request, resp *restful.Resp
 
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
-	reply, err := s.DeleteSecret(ns, name)
+	reply, err := s.withRequest(req).DeleteSecret(ns, name)
 	if err != nil {
-		blog.Error("fail to delete secret(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to delete secret(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
 	}
 	resp.Write([]byte(reply))
 }
@@ -732,15 +899,15 @@ func (s *Scheduler) CreateServiceHandler(req *restful.Request, resp *restful.Res
 
 	err = util.CheckKind(types.BcsDataType_SERVICE, body)
 	if err != nil {
-		blog.Error("fail to create service(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to create service(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.CreateService(body)
+	reply, err := s.withRequest(req).CreateService(body)
 	if err != nil {
-		blog.Error("fail to create service(%s). reply(%s), err(%s)", string(body), reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to create service(%s). reply(%s), err(%s)", string(body), reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -757,15 +924,15 @@ func (s *Scheduler) UpdateServiceHandler(req *restful.Request, resp *restful.Res
 
 	err = util.CheckKind(types.BcsDataType_SERVICE, body)
 	if err != nil {
-		blog.Error("fail to update service(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to update service(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.UpdateService(body)
+	reply, err := s.withRequest(req).UpdateService(body)
 	if err != nil {
-		blog.Error("fail to update service(%s). reply(%s), err(%s)", string(body), reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to update service(%s). reply(%s), err(%s)", string(body), reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -777,9 +944,9 @@ func (s *Scheduler) DeleteServiceHandler(req *restful.Request, resp *restful.Res
 
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
-	reply, err := s.DeleteService(ns, name)
+	reply, err := s.withRequest(req).DeleteService(ns, name)
 	if err != nil {
-		blog.Error("fail to delete service(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to delete service(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
 	}
 	resp.Write([]byte(reply))
 }
@@ -793,15 +960,15 @@ func (s *Scheduler) CreateApplicationHandler(req *restful.Request, resp *restful
 
 	err = util.CheckKind(types.BcsDataType_APP, body)
 	if err != nil {
-		blog.Error("fail to create application(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to create application(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.CreateApplication(body)
+	reply, err := s.withRequest(req).CreateApplication(body)
 	if err != nil {
-		blog.Error("fail to create application. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to create application. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -818,15 +985,15 @@ func (s *Scheduler) CreateProcessHandler(req *restful.Request, resp *restful.Res
 
 	err = util.CheckKind(types.BcsDataType_PROCESS, body)
 	if err != nil {
-		blog.Error("fail to create process(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to create process(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.CreateApplication(body)
+	reply, err := s.withRequest(req).CreateApplication(body)
 	if err != nil {
-		blog.Error("fail to create process. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to create process. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -843,7 +1010,7 @@ func (s *Scheduler) UpdateApplicationHandler(req *restful.Request, resp *restful
 
 	err = util.CheckKind(types.BcsDataType_APP, body)
 	if err != nil {
-		blog.Error("fail to update application(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to update application(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -852,9 +1019,9 @@ func (s *Scheduler) UpdateApplicationHandler(req *restful.Request, resp *restful
 	instances := req.QueryParameter("instances")
 	args := req.QueryParameter("args")
 
-	reply, err := s.UpdateApplication(body, instances, args)
+	reply, err := s.withRequest(req).UpdateApplication(body, instances, args)
 	if err != nil {
-		blog.Error("fail to update application for instances(%d). reply(%s), err(%s)", instances, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to update application for instances(%d). reply(%s), err(%s)", instances, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -871,7 +1038,7 @@ func (s *Scheduler) UpdateProcessHandler(req *restful.Request, resp *restful.Res
 
 	err = util.CheckKind(types.BcsDataType_PROCESS, body)
 	if err != nil {
-		blog.Error("fail to update process(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to update process(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -880,9 +1047,9 @@ func (s *Scheduler) UpdateProcessHandler(req *restful.Request, resp *restful.Res
 	instances := req.QueryParameter("instances")
 	args := req.QueryParameter("args")
 
-	reply, err := s.UpdateApplication(body, instances, args)
+	reply, err := s.withRequest(req).UpdateApplication(body, instances, args)
 	if err != nil {
-		blog.Error("fail to update process for instances(%d). reply(%s), err(%s)", instances, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to update process for instances(%d). reply(%s), err(%s)", instances, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -896,9 +1063,9 @@ func (s *Scheduler) DeleteApplicationHandler(req *restful.Request, resp *restful
 	name := req.PathParameter("name")
 	enforce := req.QueryParameter("enforce")
 
-	reply, err := s.DeleteApplication(ns, name, enforce, commonTypes.BcsDataType_APP)
+	reply, err := s.withRequest(req).DeleteApplication(ns, name, enforce, commonTypes.BcsDataType_APP)
 	if err != nil {
-		blog.Error("fail to delete application. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to delete application. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -910,9 +1077,9 @@ func (s *Scheduler) DeleteProcessHandler(req *restful.Request, resp *restful.Res
 	name := req.PathParameter("name")
 	enforce := req.QueryParameter("enforce")
 
-	reply, err := s.DeleteApplication(ns, name, enforce, commonTypes.BcsDataType_PROCESS)
+	reply, err := s.withRequest(req).DeleteApplication(ns, name, enforce, commonTypes.BcsDataType_PROCESS)
 	if err != nil {
-		blog.Error("fail to delete process. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to delete process. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -925,9 +1092,9 @@ func (s *Scheduler) DeleteApplicationTaskGroupsHandler(req *restful.Request, res
 		return
 	}
 
-	reply, err := s.DeleteApplicationTaskGroups(body)
+	reply, err := s.withRequest(req).DeleteApplicationTaskGroups(body)
 	if err != nil {
-		blog.Error("fail to delete application taskgroups. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to delete application taskgroups. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -940,9 +1107,9 @@ func (s *Scheduler) DeleteApplicationTaskGroupHandler(req *restful.Request, resp
 		return
 	}
 
-	reply, err := s.DeleteApplicationTaskGroup(body)
+	reply, err := s.withRequest(req).DeleteApplicationTaskGroup(body)
 	if err != nil {
-		blog.Error("fail to delete application taskgroups. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to delete application taskgroups. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -957,15 +1124,15 @@ func (s *Scheduler) RollbackApplicationHandler(req *restful.Request, resp *restf
 
 	err = util.CheckKind(types.BcsDataType_APP, body)
 	if err != nil {
-		blog.Error("fail to rollback application(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to rollback application(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.RollbackApplication(body, types.BcsDataType_APP)
+	reply, err := s.withRequest(req).RollbackApplication(body, types.BcsDataType_APP)
 	if err != nil {
-		blog.Error("fail to rollback application. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to rollback application. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -982,15 +1149,15 @@ func (s *Scheduler) RollbackProcessHandler(req *restful.Request, resp *restful.R
 
 	err = util.CheckKind(types.BcsDataType_PROCESS, body)
 	if err != nil {
-		blog.Error("fail to rollback process(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to rollback process(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.RollbackApplication(body, types.BcsDataType_PROCESS)
+	reply, err := s.withRequest(req).RollbackApplication(body, types.BcsDataType_PROCESS)
 	if err != nil {
-		blog.Error("fail to rollback process. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to rollback process. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1004,9 +1171,9 @@ func (s *Scheduler) ScaleApplicationHandler(req *restful.Request, resp *restful.
 	name := req.PathParameter("name")
 	instances := req.PathParameter("instances")
 
-	reply, err := s.ScaleApplication(ns, name, instances, types.BcsDataType_APP)
+	reply, err := s.withRequest(req).ScaleApplication(ns, name, instances, types.BcsDataType_APP)
 	if err != nil {
-		blog.Error("fail to scale application. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to scale application. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1018,9 +1185,9 @@ func (s *Scheduler) ScaleProcessHandler(req *restful.Request, resp *restful.Resp
 	name := req.PathParameter("name")
 	instances := req.PathParameter("instances")
 
-	reply, err := s.ScaleApplication(ns, name, instances, types.BcsDataType_PROCESS)
+	reply, err := s.withRequest(req).ScaleApplication(ns, name, instances, types.BcsDataType_PROCESS)
 	if err != nil {
-		blog.Error("fail to scale process. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to scale process. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1036,9 +1203,9 @@ func (s *Scheduler) SendMessageApplicationHandler(req *restful.Request, resp *re
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	reply, err := s.SendMessageApplication(ns, name, "", body)
+	reply, err := s.withRequest(req).SendMessageApplication(ns, name, "", body)
 	if err != nil {
-		blog.Error("fail to send message application. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to send message application. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1055,9 +1222,9 @@ func (s *Scheduler) SendMessageTaskgroupHandler(req *restful.Request, resp *rest
 	name := req.PathParameter("name")
 	taskgroupId := req.PathParameter("taskgroup-name")
 
-	reply, err := s.SendMessageApplication(ns, name, taskgroupId, body)
+	reply, err := s.withRequest(req).SendMessageApplication(ns, name, taskgroupId, body)
 	if err != nil {
-		blog.Error("fail to send message application. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to send message application. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1067,9 +1234,9 @@ func (s *Scheduler) ListApplicationsHandler(req *restful.Request, resp *restful.
 
 	ns := req.PathParameter("ns")
 
-	reply, err := s.ListApplications(ns, types.BcsDataType_APP)
+	reply, err := s.withRequest(req).ListApplications(ns, types.BcsDataType_APP)
 	if err != nil {
-		blog.Error("fail to list applications. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to list applications. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1079,9 +1246,9 @@ func (s *Scheduler) ListProcessesHandler(req *restful.Request, resp *restful.Res
 
 	ns := req.PathParameter("ns")
 
-	reply, err := s.ListApplications(ns, types.BcsDataType_PROCESS)
+	reply, err := s.withRequest(req).ListApplications(ns, types.BcsDataType_PROCESS)
 	if err != nil {
-		blog.Error("fail to list processes. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to list processes. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1092,9 +1259,9 @@ func (s *Scheduler) ListApplicationTasksHandler(req *restful.Request, resp *rest
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	reply, err := s.ListApplicationTasks(ns, name)
+	reply, err := s.withRequest(req).ListApplicationTasks(ns, name)
 	if err != nil {
-		blog.Error("fail to list application tasks. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to list application tasks. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1105,9 +1272,9 @@ func (s *Scheduler) ListApplicationTaskGroupsHandler(req *restful.Request, resp
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	reply, err := s.ListApplicationTaskGroups(ns, name)
+	reply, err := s.withRequest(req).ListApplicationTaskGroups(ns, name)
 	if err != nil {
-		blog.Error("fail to list application taskgroups. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to list application taskgroups. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1118,9 +1285,9 @@ func (s *Scheduler) ListApplicationVersionsHandler(req *restful.Request, resp *r
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	reply, err := s.ListApplicationVersions(ns, name)
+	reply, err := s.withRequest(req).ListApplicationVersions(ns, name)
 	if err != nil {
-		blog.Error("fail to list application versions. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to list application versions. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1131,9 +1298,9 @@ func (s *Scheduler) FetchApplicationHandler(req *restful.Request, resp *restful.
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	reply, err := s.FetchApplication(ns, name, types.BcsDataType_APP)
+	reply, err := s.withRequest(req).FetchApplication(ns, name, types.BcsDataType_APP)
 	if err != nil {
-		blog.Error("fail to list application versions. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to list application versions. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1144,9 +1311,9 @@ func (s *Scheduler) FetchProcessHandler(req *restful.Request, resp *restful.Resp
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	reply, err := s.FetchApplication(ns, name, types.BcsDataType_PROCESS)
+	reply, err := s.withRequest(req).FetchApplication(ns, name, types.BcsDataType_PROCESS)
 	if err != nil {
-		blog.Error("fail to list application versions. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to list application versions. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1158,9 +1325,9 @@ func (s *Scheduler) FetchApplicationVersionHandler(req *restful.Request, resp *r
 	name := req.PathParameter("name")
 	versionid := req.PathParameter("versionid")
 
-	reply, err := s.FetchApplicationVersion(ns, name, versionid)
+	reply, err := s.withRequest(req).FetchApplicationVersion(ns, name, versionid)
 	if err != nil {
-		blog.Error("fail to list application versions. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to list application versions. reply(%s), err(%s)", reply, err.Error())
 	}
 
 	resp.Write([]byte(reply))
@@ -1168,10 +1335,10 @@ func (s *Scheduler) FetchApplicationVersionHandler(req *restful.Request, resp *r
 
 func (s *Scheduler) getRequestInfo(req *restful.Request) ([]byte, error) {
 	appid := req.PathParameter("appid")
-	blog.V(3).Infof("recv a request from app(%s), url(%s)", appid, req.Request.RequestURI)
+	tracing.RequestLogger(req).V(3).Info("recv a request from app(%s), url(%s)", appid, req.Request.RequestURI)
 	body, err := ioutil.ReadAll(req.Request.Body)
 	if err != nil {
-		blog.Error("fail to read request body. err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to read request body. err:%s", err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpReadReqBody, common.BcsErrCommHttpReadReqBodyStr)
 	}
 
@@ -1187,15 +1354,15 @@ func (s *Scheduler) createDeploymentHandler(req *restful.Request, resp *restful.
 
 	err = util.CheckKind(types.BcsDataType_DEPLOYMENT, body)
 	if err != nil {
-		blog.Error("fail to create deployment(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to create deployment(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.CreateDeployment(body)
+	reply, err := s.withRequest(req).CreateDeployment(body)
 	if err != nil {
-		blog.Error("fail to create deployment. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to create deployment. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1212,15 +1379,29 @@ func (s *Scheduler) udpateDeploymentHandler(req *restful.Request, resp *restful.
 
 	err = util.CheckKind(types.BcsDataType_DEPLOYMENT, body)
 	if err != nil {
-		blog.Error("fail to udpate deployment(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to udpate deployment(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.UpdateDeployment(body)
+	reply, err := s.withRequest(req).UpdateDeployment(body)
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to create deployment. reply(%s), err(%s)", reply, err.Error())
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	resp.Write([]byte(reply))
+}
+
+func (s *Scheduler) fetchDeploymentHandler(req *restful.Request, resp *restful.Response) {
+	ns := req.PathParameter("ns")
+	name := req.PathParameter("name")
+
+	reply, err := s.withRequest(req).fetchDeployment(ns, name)
 	if err != nil {
-		blog.Error("fail to create deployment. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to fetch deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1233,9 +1414,9 @@ func (s *Scheduler) deleteDeploymentHandler(req *restful.Request, resp *restful.
 	name := req.PathParameter("name")
 
 	enforce := req.QueryParameter("enforce")
-	reply, err := s.deleteDeployment(ns, name, enforce)
+	reply, err := s.withRequest(req).deleteDeployment(ns, name, enforce)
 	if err != nil {
-		blog.Error("fail to delete deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to delete deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1247,9 +1428,9 @@ func (s *Scheduler) cancelupdateDeploymentHandler(req *restful.Request, resp *re
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	reply, err := s.cancelupdateDeployment(ns, name)
+	reply, err := s.withRequest(req).cancelupdateDeployment(ns, name)
 	if err != nil {
-		blog.Error("fail to cancelupdate deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to cancelupdate deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1261,9 +1442,9 @@ func (s *Scheduler) pauseupdateDeploymentHandler(req *restful.Request, resp *res
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	reply, err := s.pauseupdateDeployment(ns, name)
+	reply, err := s.withRequest(req).pauseupdateDeployment(ns, name)
 	if err != nil {
-		blog.Error("fail to pauseupdate deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to pauseupdate deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1275,9 +1456,9 @@ func (s *Scheduler) resumeupdateDeploymentHandler(req *restful.Request, resp *re
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
-	reply, err := s.resumeupdateDeployment(ns, name)
+	reply, err := s.withRequest(req).resumeupdateDeployment(ns, name)
 	if err != nil {
-		blog.Error("fail to resumeupdate deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to resumeupdate deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1292,14 +1473,14 @@ func (s *Scheduler) scaleDeploymentHandler(req *restful.Request, resp *restful.R
 
 	instances, err := strconv.Atoi(ins)
 	if err != nil {
-		blog.Error("fail to scale deployment namespace %s name %s instances %s is invalid", ns, name, ins)
+		tracing.RequestLogger(req).Error("fail to scale deployment namespace %s name %s instances %s is invalid", ns, name, ins)
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.scaleDeployment(ns, name, instances)
+	reply, err := s.withRequest(req).scaleDeployment(ns, name, instances)
 	if err != nil {
-		blog.Error("fail to resumeupdate deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to resumeupdate deployment namespace %s name %s. reply(%s), err(%s)", ns, name, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1311,9 +1492,9 @@ func (s *Scheduler) reschedulerTaskgroupHandler(req *restful.Request, resp *rest
 	taskgroupId := req.PathParameter("taskgroupId")
 	hostRetainTime := req.QueryParameter("hostRetainTime")
 
-	reply, err := s.RescheduleTaskgroup(taskgroupId, hostRetainTime)
+	reply, err := s.withRequest(req).RescheduleTaskgroup(taskgroupId, hostRetainTime)
 	if err != nil {
-		blog.Error("fail to rescheduler taskgroup (%s) reply(%s), err(%s)", taskgroupId, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to rescheduler taskgroup (%s) reply(%s), err(%s)", taskgroupId, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1324,9 +1505,9 @@ func (s *Scheduler) reschedulerTaskgroupHandler(req *restful.Request, resp *rest
 func (s *Scheduler) restartTaskGroupHandler(req *restful.Request, resp *restful.Response) {
 	taskGroupID := req.PathParameter("taskGroupID")
 
-	reply, err := s.RestartTaskGroup(taskGroupID)
+	reply, err := s.withRequest(req).RestartTaskGroup(taskGroupID)
 	if err != nil {
-		blog.Error("fail to restart taskGroup (%s) reply(%s), err(%s)", taskGroupID, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to restart taskGroup (%s) reply(%s), err(%s)", taskGroupID, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1337,9 +1518,9 @@ func (s *Scheduler) restartTaskGroupHandler(req *restful.Request, resp *restful.
 func (s *Scheduler) reloadTaskGroupHandler(req *restful.Request, resp *restful.Response) {
 	taskGroupID := req.PathParameter("taskGroupID")
 
-	reply, err := s.ReloadTaskGroup(taskGroupID)
+	reply, err := s.withRequest(req).ReloadTaskGroup(taskGroupID)
 	if err != nil {
-		blog.Error("fail to reload taskGroup (%s) reply(%s), err(%s)", taskGroupID, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to reload taskGroup (%s) reply(%s), err(%s)", taskGroupID, reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1354,9 +1535,9 @@ func (s *Scheduler) registerCustomResourceHander(req *restful.Request, resp *res
 		return
 	}
 
-	reply, err := s.RegisterCustomResource(body)
+	reply, err := s.withRequest(req).RegisterCustomResource(body)
 	if err != nil {
-		blog.Error("fail to register custom resource. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to register custom resource. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1374,9 +1555,9 @@ func (s *Scheduler) createCustomResourceHander(req *restful.Request, resp *restf
 	ns := req.PathParameter("ns")
 	kind := req.PathParameter("kind")
 
-	reply, err := s.CreateCustomResource(ns, kind, body)
+	reply, err := s.withRequest(req).CreateCustomResource(ns, kind, body)
 	if err != nil {
-		blog.Error("fail to create custom resource. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to create custom resource. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1394,9 +1575,9 @@ func (s *Scheduler) updateCustomResourceHander(req *restful.Request, resp *restf
 	ns := req.PathParameter("ns")
 	kind := req.PathParameter("kind")
 
-	reply, err := s.UpdateCustomResource(ns, kind, body)
+	reply, err := s.withRequest(req).UpdateCustomResource(ns, kind, body)
 	if err != nil {
-		blog.Error("fail to update custom resource. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to update custom resource. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1409,9 +1590,9 @@ func (s *Scheduler) deleteCustomResourceHander(req *restful.Request, resp *restf
 	kind := req.PathParameter("kind")
 	name := req.PathParameter("name")
 
-	reply, err := s.DeleteCustomResource(ns, kind, name)
+	reply, err := s.withRequest(req).DeleteCustomResource(ns, kind, name)
 	if err != nil {
-		blog.Error("fail to delete custom resource. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to delete custom resource. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1423,9 +1604,9 @@ func (s *Scheduler) listCustomResourceHander(req *restful.Request, resp *restful
 	ns := req.PathParameter("ns")
 	kind := req.PathParameter("kind")
 
-	reply, err := s.ListCustomResource(ns, kind)
+	reply, err := s.withRequest(req).ListCustomResource(ns, kind)
 	if err != nil {
-		blog.Error("fail to list custom resource. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to list custom resource. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1438,9 +1619,11 @@ func (s *Scheduler) getCustomResourceHander(req *restful.Request, resp *restful.
 	kind := req.PathParameter("kind")
 	name := req.PathParameter("name")
 
-	reply, err := s.GetCustomResource(ns, kind, name)
+	version := req.QueryParameter("version")
+
+	reply, err := s.withRequest(req).GetCustomResource(ns, kind, name, version)
 	if err != nil {
-		blog.Error("fail to get custom resource. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to get custom resource. reply(%s), err(%s)", reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1450,10 +1633,10 @@ func (s *Scheduler) getCustomResourceHander(req *restful.Request, resp *restful.
 
 func (s *Scheduler) commitImageHander(req *restful.Request, resp *restful.Response) {
 
-	blog.V(3).Infof("receive commit image")
+	tracing.RequestLogger(req).V(3).Info("receive commit image")
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -1464,11 +1647,11 @@ func (s *Scheduler) commitImageHander(req *restful.Request, resp *restful.Respon
 	commit_url := req.QueryParameter("url")
 
 	url := s.GetHost() + "/v1/image/commit/" + taskgroup + "?image=" + image + "&url=" + commit_url
-	blog.Infof("post a request to url(%s)", url)
+	tracing.RequestLogger(req).Info("post a request to url(%s)", url)
 
-	reply, err := s.client.POST(url, nil, nil)
+	reply, err := s.withRequest(req).client.POST(url, nil, nil)
 	if err != nil {
-		blog.Error("request to url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("request to url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -1480,7 +1663,7 @@ func (s *Scheduler) commitImageHander(req *restful.Request, resp *restful.Respon
 func (s *Scheduler) sendApplicationCommandHandler(req *restful.Request, resp *restful.Response) {
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -1496,11 +1679,11 @@ func (s *Scheduler) sendApplicationCommandHandler(req *restful.Request, resp *re
 	}
 	url := s.GetHost() + "/v1/command/application/" + ns + "/" + name
 
-	blog.Info("post url(%s), request(%s)", url, string(body))
+	tracing.RequestLogger(req).Info("post url(%s), request(%s)", url, string(body))
 
-	rpyPost, rpyError := s.client.POST(url, nil, body)
+	rpyPost, rpyError := s.withRequest(req).client.POST(url, nil, body)
 	if rpyError != nil {
-		blog.Error("post url(%s) failed! err(%s)", url, rpyError.Error())
+		tracing.RequestLogger(req).Error("post url(%s) failed! err(%s)", url, rpyError.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+rpyError.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -1512,7 +1695,7 @@ func (s *Scheduler) sendApplicationCommandHandler(req *restful.Request, resp *re
 func (s *Scheduler) getApplicationCommandHandler(req *restful.Request, resp *restful.Response) {
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -1523,11 +1706,11 @@ func (s *Scheduler) getApplicationCommandHandler(req *restful.Request, resp *res
 
 	id := req.QueryParameter("id")
 	url := s.GetHost() + "/v1/command/application/" + ns + "/" + name + "?id=" + id
-	blog.V(3).Infof("get url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("get url(%s)", url)
 
-	reply, err := s.client.GET(url, nil, nil)
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
 	if err != nil {
-		blog.Error("get url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("get url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -1538,7 +1721,7 @@ func (s *Scheduler) getApplicationCommandHandler(req *restful.Request, resp *res
 
 func (s *Scheduler) deleteApplicationCommandHandler(req *restful.Request, resp *restful.Response) {
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -1549,11 +1732,11 @@ func (s *Scheduler) deleteApplicationCommandHandler(req *restful.Request, resp *
 
 	id := req.QueryParameter("id")
 	url := s.GetHost() + "/v1/command/application/" + ns + "/" + name + "?id=" + id
-	blog.V(3).Infof("delete url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("delete url(%s)", url)
 
-	reply, err := s.client.DELETE(url, nil, nil)
+	reply, err := s.withRequest(req).client.DELETE(url, nil, nil)
 	if err != nil {
-		blog.Error("delete url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("delete url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -1565,7 +1748,7 @@ func (s *Scheduler) deleteApplicationCommandHandler(req *restful.Request, resp *
 func (s *Scheduler) sendDeploymentCommandHandler(req *restful.Request, resp *restful.Response) {
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -1579,11 +1762,11 @@ func (s *Scheduler) sendDeploymentCommandHandler(req *restful.Request, resp *res
 	}
 	url := s.GetHost() + "/v1/command/deployment/" + ns + "/" + name
 
-	blog.Info("post url(%s), request(%s)", url, string(body))
+	tracing.RequestLogger(req).Info("post url(%s), request(%s)", url, string(body))
 
-	rpyPost, rpyError := s.client.POST(url, nil, body)
+	rpyPost, rpyError := s.withRequest(req).client.POST(url, nil, body)
 	if rpyError != nil {
-		blog.Error("post url(%s) failed! err(%s)", url, rpyError.Error())
+		tracing.RequestLogger(req).Error("post url(%s) failed! err(%s)", url, rpyError.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+rpyError.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -1595,7 +1778,7 @@ func (s *Scheduler) sendDeploymentCommandHandler(req *restful.Request, resp *res
 func (s *Scheduler) getDeploymentCommandHandler(req *restful.Request, resp *restful.Response) {
 
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -1605,11 +1788,11 @@ func (s *Scheduler) getDeploymentCommandHandler(req *restful.Request, resp *rest
 	name := req.PathParameter("name")
 	id := req.QueryParameter("id")
 	url := s.GetHost() + "/v1/command/deployment/" + ns + "/" + name + "?id=" + id
-	blog.V(3).Infof("get url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("get url(%s)", url)
 
-	reply, err := s.client.GET(url, nil, nil)
+	reply, err := s.withRequest(req).client.GET(url, nil, nil)
 	if err != nil {
-		blog.Error("get url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("get url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -1620,7 +1803,7 @@ func (s *Scheduler) getDeploymentCommandHandler(req *restful.Request, resp *rest
 
 func (s *Scheduler) deleteDeploymentCommandHandler(req *restful.Request, resp *restful.Response) {
 	if s.GetHost() == "" {
-		blog.Error("no scheduler is connected by driver")
+		tracing.RequestLogger(req).Error("no scheduler is connected by driver")
 		err := bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+"scheduler not exist")
 		resp.Write([]byte(err.Error()))
 		return
@@ -1629,11 +1812,11 @@ func (s *Scheduler) deleteDeploymentCommandHandler(req *restful.Request, resp *r
 	name := req.PathParameter("name")
 	id := req.QueryParameter("id")
 	url := s.GetHost() + "/v1/command/deployment/" + ns + "/" + name + "?id=" + id
-	blog.V(3).Infof("delete url(%s)", url)
+	tracing.RequestLogger(req).V(3).Info("delete url(%s)", url)
 
-	reply, err := s.client.DELETE(url, nil, nil)
+	reply, err := s.withRequest(req).client.DELETE(url, nil, nil)
 	if err != nil {
-		blog.Error("delete url(%s) failed! err(%s)", url, err.Error())
+		tracing.RequestLogger(req).Error("delete url(%s) failed! err(%s)", url, err.Error())
 		err = bhttp.InternalError(common.BcsErrCommHttpDo, common.BcsErrCommHttpDoStr+err.Error())
 		resp.Write([]byte(err.Error()))
 		return
@@ -1651,15 +1834,15 @@ func (s *Scheduler) CreateAdmissionwebhookHandler(req *restful.Request, resp *re
 
 	err = util.CheckKind(types.BcsDataType_Admissionwebhook, body)
 	if err != nil {
-		blog.Error("fail to create admissionwebhook(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to create admissionwebhook(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.CreateAdmissionWebhook(body)
+	reply, err := s.withRequest(req).CreateAdmissionWebhook(body)
 	if err != nil {
-		blog.Error("fail to create admissionwebhook(%s). reply(%s), err(%s)", string(body), reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to create admissionwebhook(%s). reply(%s), err(%s)", string(body), reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1676,15 +1859,15 @@ func (s *Scheduler) UpdateAdmissionwebhookHandler(req *restful.Request, resp *re
 
 	err = util.CheckKind(types.BcsDataType_Admissionwebhook, body)
 	if err != nil {
-		blog.Error("fail to update admissionwebhook(%s). err(%s)", string(body), err.Error())
+		tracing.RequestLogger(req).Error("fail to update admissionwebhook(%s). err(%s)", string(body), err.Error())
 		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
 
-	reply, err := s.UpdateAdmissionWebhook(body)
+	reply, err := s.withRequest(req).UpdateAdmissionWebhook(body)
 	if err != nil {
-		blog.Error("fail to update admissionwebhook(%s). reply(%s), err(%s)", string(body), reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to update admissionwebhook(%s). reply(%s), err(%s)", string(body), reply, err.Error())
 		resp.Write([]byte(err.Error()))
 		return
 	}
@@ -1696,9 +1879,9 @@ func (s *Scheduler) DeleteAdmissionwebhookHandler(req *restful.Request, resp *re
 
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
-	reply, err := s.DeleteAdmissionWebhook(ns, name)
+	reply, err := s.withRequest(req).DeleteAdmissionWebhook(ns, name)
 	if err != nil {
-		blog.Error("fail to delete admissionwebhook(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to delete admissionwebhook(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
 	}
 	resp.Write([]byte(reply))
 }
@@ -1707,17 +1890,99 @@ func (s *Scheduler) FetchAdmissionwebhookHandler(req *restful.Request, resp *res
 
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
-	reply, err := s.FetchAdmissionWebhook(ns, name)
+	reply, err := s.withRequest(req).FetchAdmissionWebhook(ns, name)
 	if err != nil {
-		blog.Error("fail to fetch admissionwebhook(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to fetch admissionwebhook(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
 	}
 	resp.Write([]byte(reply))
 }
 
 func (s *Scheduler) FetchAllAdmissionwebhooksHandler(req *restful.Request, resp *restful.Response) {
-	reply, err := s.FetchAllAdmissionWebhooks()
+	reply, err := s.withRequest(req).FetchAllAdmissionWebhooks()
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to fetch all admissionwebhooks. reply(%s), err(%s)", reply, err.Error())
+	}
+	resp.Write([]byte(reply))
+}
+
+func (s *Scheduler) CreateDisruptionBudgetHandler(req *restful.Request, resp *restful.Response) {
+	body, err := s.getRequestInfo(req)
+	if err != nil {
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	err = util.CheckKind(types.BcsDataType_DisruptionBudget, body)
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to create disruptionbudget(%s). err(%s)", string(body), err.Error())
+		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	reply, err := s.withRequest(req).CreateDisruptionBudget(body)
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to create disruptionbudget(%s). reply(%s), err(%s)", string(body), reply, err.Error())
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	resp.Write([]byte(reply))
+}
+
+func (s *Scheduler) UpdateDisruptionBudgetHandler(req *restful.Request, resp *restful.Response) {
+	body, err := s.getRequestInfo(req)
+	if err != nil {
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	err = util.CheckKind(types.BcsDataType_DisruptionBudget, body)
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to update disruptionbudget(%s). err(%s)", string(body), err.Error())
+		err = bhttp.InternalError(common.BcsErrCommRequestDataErr, err.Error())
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	reply, err := s.withRequest(req).UpdateDisruptionBudget(body)
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to update disruptionbudget(%s). reply(%s), err(%s)", string(body), reply, err.Error())
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	resp.Write([]byte(reply))
+}
+
+func (s *Scheduler) DeleteDisruptionBudgetHandler(req *restful.Request, resp *restful.Response) {
+
+	ns := req.PathParameter("ns")
+	name := req.PathParameter("name")
+	reply, err := s.withRequest(req).DeleteDisruptionBudget(ns, name)
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to delete disruptionbudget(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
+	}
+	resp.Write([]byte(reply))
+}
+
+func (s *Scheduler) FetchDisruptionBudgetHandler(req *restful.Request, resp *restful.Response) {
+
+	ns := req.PathParameter("ns")
+	name := req.PathParameter("name")
+	reply, err := s.withRequest(req).FetchDisruptionBudget(ns, name)
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to fetch disruptionbudget(%s, %s). reply(%s), err(%s)", ns, name, reply, err.Error())
+	}
+	resp.Write([]byte(reply))
+}
+
+func (s *Scheduler) ListDisruptionBudgetsHandler(req *restful.Request, resp *restful.Response) {
+
+	ns := req.PathParameter("ns")
+	reply, err := s.withRequest(req).ListDisruptionBudgets(ns)
 	if err != nil {
-		blog.Error("fail to fetch all admissionwebhooks. reply(%s), err(%s)", reply, err.Error())
+		tracing.RequestLogger(req).Error("fail to list disruptionbudgets(%s). reply(%s), err(%s)", ns, reply, err.Error())
 	}
 	resp.Write([]byte(reply))
 }
@@ -15,6 +15,8 @@ package filter
 
 import (
 	bcshttp "bk-bcs/bcs-common/common/http"
+	"bk-bcs/bcs-common/common/tracing"
+	"fmt"
 	"github.com/emicklei/go-restful"
 	"net/http"
 )
@@ -36,9 +38,16 @@ type RequestFilterFunction interface {
 }
 
 func (gf *GeneralFilter) Filter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	ctx := req.Request.Context()
+	span, ctx := tracing.StartSpan(ctx, "filter chain", tracing.SpanKindInternal)
 	for _, filterFunction := range gf.filterFunctions {
+		fspan, _ := tracing.StartSpan(ctx, fmt.Sprintf("filter %T", filterFunction), tracing.SpanKindInternal)
 		errCode, err := filterFunction.Execute(req)
+		fspan.SetError(err)
+		fspan.Finish()
 		if err != nil {
+			span.SetError(err)
+			span.Finish()
 			resp.WriteHeaderAndEntity(http.StatusBadRequest, bcshttp.APIRespone{
 				Result:  false,
 				Code:    errCode,
@@ -48,6 +57,7 @@ func (gf *GeneralFilter) Filter(req *restful.Request, resp *restful.Response, ch
 			return
 		}
 	}
+	span.Finish()
 	chain.ProcessFilter(req, resp)
 }
 
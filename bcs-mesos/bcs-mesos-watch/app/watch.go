@@ -90,39 +90,53 @@ func Run(cfg *types.CmdConfig) error {
 	RunMetric(cfg)
 
 	//create storage
+	sinkCfgs, sinkErr := storage.LoadSinkConfigs(cfg.SinkConfigFile)
+	if sinkErr != nil {
+		blog.Error("Load sink config Err: %s", sinkErr.Error())
+		return sinkErr
+	}
 	ccStorage, ccErr := storage.NewCCStorage(cfg)
 	if ccErr != nil {
 		blog.Error("Create CCStorage Err: %s", ccErr.Error())
 		return ccErr
 	}
+	sinkStorage, sinkErr := storage.NewSinkStorage(cfg, ccStorage, sinkCfgs)
+	if sinkErr != nil {
+		blog.Error("Create SinkStorage Err: %s", sinkErr.Error())
+		return sinkErr
+	}
 	var DCHosts []string
 	ccStorage.SetDCAddress(DCHosts)
 	servermetric.SetDCStatus(false)
 
 	ccCxt, _ := context.WithCancel(rootCxt)
-	go RefreshDCHost(cfg, ccCxt, ccStorage)
-	time.Sleep(2 * time.Second)
-	for {
-		if ccStorage.GetDCAddress() == "" {
-			blog.Warn("storage address is empty, mesos datawatcher cannot run")
-			time.Sleep(2 * time.Second)
-		} else {
-			break
+	if storage.UseBcsStorage(sinkCfgs) {
+		go RefreshDCHost(cfg, ccCxt, ccStorage)
+		time.Sleep(2 * time.Second)
+		for {
+			if ccStorage.GetDCAddress() == "" {
+				blog.Warn("storage address is empty, mesos datawatcher cannot run")
+				time.Sleep(2 * time.Second)
+			} else {
+				break
+			}
 		}
+	} else {
+		servermetric.SetDCStatus(true)
 	}
 
-	ccStorage.Run(ccCxt)
+	sinkStorage.Run(ccCxt)
 
 	rdCxt, _ := context.WithCancel(rootCxt)
 	blog.Info("after storage created, to run server...")
-	retry, rdErr := runServer(cfg, rdCxt, ccStorage)
+	retry, rdErr := runServer(cfg, rdCxt, sinkStorage)
 	for retry == true {
 		if rdErr != nil {
 			blog.Error("run server err: %s", rdErr.Error())
 		}
 		time.Sleep(3 * time.Second)
 		blog.Info("retry run server...")
-		retry, rdErr = runServer(cfg, rdCxt, ccStorage)
+		retry, rdErr = runServer(cfg, rdCxt, sinkStorage)
 	}
 	if rdErr != nil {
 		blog.Error("run server err: %s", rdErr.Error())
@@ -47,6 +47,7 @@ func setCfg(op *MesosWatchOptions) {
 	cfg.ServerKeyFile = op.ServerKeyFile
 
 	cfg.ClusterID = op.Cluster
+	cfg.SinkConfigFile = op.SinkConfigFile
 
 	if cfg.ServerCertFile != "" && cfg.ServerKeyFile != "" {
 		cfg.ServerSchem = "https"
@@ -95,4 +96,5 @@ type MesosWatchOptions struct {
 	TaskgroupThreadNum     uint   `json:"taskgroup_threads" value:"100" usage:"taskgroup thread num"`
 	ExportserviceThreadNum uint   `json:"exportservice_threads" value:"100" usage:"exportservice thread num"`
 	Cluster                string `json:"cluster" value:"" usage:"the cluster ID under bcs"`
+	SinkConfigFile         string `json:"sink_config" value:"" usage:"json file of sinks for watched data, only bcs-storage is used if empty"`
 }
@@ -0,0 +1,326 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-mesos/bcs-mesos-watch/types"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	//SinkBcsStorage sink type writing data to bcs-storage
+	SinkBcsStorage = "bcs-storage"
+	//SinkWebhook sink type posting data to http webhook
+	SinkWebhook = "webhook"
+	//SinkFile sink type writing data to local rotating ndjson file
+	SinkFile = "file"
+	//SinkKafka sink type producing data to kafka
+	SinkKafka = "kafka"
+
+	defaultSinkQueueSize     = 10240
+	defaultSinkMaxRetries    = 3
+	defaultSinkRetryInterval = 1
+	maxSinkRetryInterval     = 30 * time.Second
+)
+
+//Sink is writer for one destination of sync data
+type Sink interface {
+	Write(data *types.BcsSyncData) error //write one data, retried by sink proxy if error
+	Close() error                        //release resources when watch exits
+}
+
+//SinkEnv is environment for creating sink
+type SinkEnv struct {
+	ClusterID string
+	CCStorage Storage //bcs-storage writer
+}
+
+//SinkFactory create sink with config, options of sink are in cfg.Options
+type SinkFactory func(cfg *SinkConfig, env *SinkEnv) (Sink, error)
+
+var (
+	sinkLock      sync.RWMutex
+	sinkFactories = make(map[string]SinkFactory)
+)
+
+//RegisterSink register sink factory for sink type, registering same type twice panics
+func RegisterSink(sinkType string, factory SinkFactory) {
+	sinkLock.Lock()
+	defer sinkLock.Unlock()
+	if _, ok := sinkFactories[sinkType]; ok {
+		panic(fmt.Sprintf("sink type %s registered twice", sinkType))
+	}
+	sinkFactories[sinkType] = factory
+}
+
+func getSinkFactory(sinkType string) (SinkFactory, bool) {
+	sinkLock.RLock()
+	defer sinkLock.RUnlock()
+	factory, ok := sinkFactories[sinkType]
+	return factory, ok
+}
+
+//SinkConfig config for one sink
+type SinkConfig struct {
+	Name          string          `json:"name"`
+	Type          string          `json:"type"`
+	Kinds         []string        `json:"kinds,omitempty"`         //data kinds to sink, like TaskGroup, Deployment. all kinds if empty
+	QueueSize     int             `json:"queueSize,omitempty"`     //default 10240
+	MaxRetries    int             `json:"maxRetries,omitempty"`    //default 3, negative for no retry
+	RetryInterval int             `json:"retryInterval,omitempty"` //seconds of first retry, doubled for each retry, default 1
+	Options       json.RawMessage `json:"options,omitempty"`       //options of sink type
+}
+
+//SinkFileConfig content of sink config file
+type SinkFileConfig struct {
+	Sinks []*SinkConfig `json:"sinks"`
+}
+
+//LoadSinkConfigs load sink configs from file, only bcs-storage is used if file is empty
+func LoadSinkConfigs(file string) ([]*SinkConfig, error) {
+	if file == "" {
+		return []*SinkConfig{{Name: SinkBcsStorage, Type: SinkBcsStorage}}, nil
+	}
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read sink config %s failed: %s", file, err.Error())
+	}
+	fileCfg := &SinkFileConfig{}
+	if err := json.Unmarshal(content, fileCfg); err != nil {
+		return nil, fmt.Errorf("decode sink config %s failed: %s", file, err.Error())
+	}
+	if len(fileCfg.Sinks) == 0 {
+		return nil, fmt.Errorf("no sink in sink config %s", file)
+	}
+	names := make(map[string]bool)
+	for _, cfg := range fileCfg.Sinks {
+		if cfg.Name == "" {
+			cfg.Name = cfg.Type
+		}
+		if names[cfg.Name] {
+			return nil, fmt.Errorf("sink name %s is duplicated", cfg.Name)
+		}
+		names[cfg.Name] = true
+	}
+	return fileCfg.Sinks, nil
+}
+
+//UseBcsStorage check if bcs-storage is one of sinks
+func UseBcsStorage(cfgs []*SinkConfig) bool {
+	for _, cfg := range cfgs {
+		if cfg.Type == SinkBcsStorage {
+			return true
+		}
+	}
+	return false
+}
+
+//sinkProxy hold queue, retry policy and kind filter for one sink
+type sinkProxy struct {
+	name          string
+	sink          Sink
+	kinds         map[string]bool
+	queue         chan *types.BcsSyncData
+	maxRetries    int
+	retryInterval time.Duration
+}
+
+func newSinkProxy(cfg *SinkConfig, sink Sink) *sinkProxy {
+	proxy := &sinkProxy{
+		name:          cfg.Name,
+		sink:          sink,
+		maxRetries:    cfg.MaxRetries,
+		retryInterval: time.Duration(cfg.RetryInterval) * time.Second,
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultSinkQueueSize
+	}
+	proxy.queue = make(chan *types.BcsSyncData, queueSize)
+	if proxy.maxRetries == 0 {
+		proxy.maxRetries = defaultSinkMaxRetries
+	}
+	if proxy.retryInterval <= 0 {
+		proxy.retryInterval = defaultSinkRetryInterval * time.Second
+	}
+	if len(cfg.Kinds) != 0 {
+		proxy.kinds = make(map[string]bool)
+		for _, kind := range cfg.Kinds {
+			proxy.kinds[kind] = true
+		}
+	}
+	return proxy
+}
+
+//accept check data kind with kind filter
+func (proxy *sinkProxy) accept(data *types.BcsSyncData) bool {
+	return proxy.kinds == nil || proxy.kinds[types.GetDataKind(data.DataType)]
+}
+
+//post push data to queue without blocking, data is dropped if queue is full
+func (proxy *sinkProxy) post(data *types.BcsSyncData) {
+	if !proxy.accept(data) {
+		return
+	}
+	select {
+	case proxy.queue <- data:
+	default:
+		blog.Warnf("sink(%s) queue is full(%d), drop %s %s", proxy.name, cap(proxy.queue), data.DataType, data.Action)
+	}
+}
+
+func (proxy *sinkProxy) run(cxt context.Context) {
+	tick := time.NewTicker(300 * time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case <-cxt.Done():
+			blog.Info("sink(%s) asked to exit, current task queue(%d/%d)", proxy.name, len(proxy.queue), cap(proxy.queue))
+			if err := proxy.sink.Close(); err != nil {
+				blog.Warnf("sink(%s) close failed: %s", proxy.name, err.Error())
+			}
+			return
+		case <-tick.C:
+			blog.Info("tick: sink(%s) is alive, current task queue(%d/%d)", proxy.name, len(proxy.queue), cap(proxy.queue))
+		case data := <-proxy.queue:
+			proxy.write(cxt, data)
+		}
+	}
+}
+
+//write write data with retry, interval is doubled for each retry
+func (proxy *sinkProxy) write(cxt context.Context, data *types.BcsSyncData) {
+	interval := proxy.retryInterval
+	for i := 0; ; i++ {
+		err := proxy.sink.Write(data)
+		if err == nil {
+			return
+		}
+		if i >= proxy.maxRetries {
+			blog.Errorf("sink(%s) write %s %s failed after %d retries, drop it: %s",
+				proxy.name, data.DataType, data.Action, i, err.Error())
+			return
+		}
+		blog.Warnf("sink(%s) write %s %s failed, retry after %s: %s",
+			proxy.name, data.DataType, data.Action, interval.String(), err.Error())
+		select {
+		case <-cxt.Done():
+			return
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if interval > maxSinkRetryInterval {
+			interval = maxSinkRetryInterval
+		}
+	}
+}
+
+//NewSinkStorage create storage fanning out sync data to all sinks,
+//bcs-storage operations are delegated to ccStorage
+func NewSinkStorage(config *types.CmdConfig, ccStorage Storage, cfgs []*SinkConfig) (Storage, error) {
+	st := &SinkStorage{
+		Storage: ccStorage,
+	}
+	env := &SinkEnv{
+		ClusterID: config.ClusterID,
+		CCStorage: ccStorage,
+	}
+	for _, cfg := range cfgs {
+		factory, ok := getSinkFactory(cfg.Type)
+		if !ok {
+			st.close()
+			return nil, fmt.Errorf("sink(%s) type %s is unknown", cfg.Name, cfg.Type)
+		}
+		sink, err := factory(cfg, env)
+		if err != nil {
+			st.close()
+			return nil, fmt.Errorf("create sink(%s) failed: %s", cfg.Name, err.Error())
+		}
+		if cfg.Type == SinkBcsStorage {
+			st.useCC = true
+		}
+		blog.Info("sink(%s) type %s created, kinds: %v", cfg.Name, cfg.Type, cfg.Kinds)
+		st.proxies = append(st.proxies, newSinkProxy(cfg, sink))
+	}
+	return st, nil
+}
+
+//SinkStorage fan out sync data to several sinks
+type SinkStorage struct {
+	Storage              //bcs-storage writer, only running when bcs-storage sink is configured
+	useCC   bool         //flag for bcs-storage sink
+	proxies []*sinkProxy //all sinks
+	exitCxt context.Context
+}
+
+//Sync post data to all sinks
+func (st *SinkStorage) Sync(data *types.BcsSyncData) error {
+	if data == nil {
+		blog.Error("SinkStorage get nil BcsSyncData pointer")
+		return nil
+	}
+	for _, proxy := range st.proxies {
+		proxy.post(data)
+	}
+	return nil
+}
+
+//Run start all sinks
+func (st *SinkStorage) Run(cxt context.Context) error {
+	st.exitCxt = cxt
+	if st.useCC {
+		if err := st.Storage.Run(cxt); err != nil {
+			return err
+		}
+	}
+	for _, proxy := range st.proxies {
+		blog.Info("SinkStorage starting sink(%s)", proxy.name)
+		go proxy.run(cxt)
+	}
+	go st.Worker()
+	return nil
+}
+
+//Worker report queue status of all sinks
+func (st *SinkStorage) Worker() {
+	tick := time.NewTicker(120 * time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case <-st.exitCxt.Done():
+			blog.Info("SinkStorage get exit signal, ready to exit")
+			return
+		case <-tick.C:
+			for _, proxy := range st.proxies {
+				if len(proxy.queue)+1024 > cap(proxy.queue) {
+					blog.Warnf("sink(%s) busy, current task queue(%d/%d)", proxy.name, len(proxy.queue), cap(proxy.queue))
+				}
+			}
+		}
+	}
+}
+
+func (st *SinkStorage) close() {
+	for _, proxy := range st.proxies {
+		proxy.sink.Close()
+	}
+}
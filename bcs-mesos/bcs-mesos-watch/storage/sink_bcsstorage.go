@@ -0,0 +1,46 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package storage
+
+import (
+	"fmt"
+
+	"bk-bcs/bcs-mesos/bcs-mesos-watch/types"
+)
+
+func init() {
+	RegisterSink(SinkBcsStorage, newBcsStorageSink)
+}
+
+//bcsStorageSink hand data over to CCStorage, which has its own channels for every data type
+type bcsStorageSink struct {
+	cc Storage
+}
+
+func newBcsStorageSink(cfg *SinkConfig, env *SinkEnv) (Sink, error) {
+	if env.CCStorage == nil {
+		return nil, fmt.Errorf("bcs-storage writer is not initialized")
+	}
+	return &bcsStorageSink{cc: env.CCStorage}, nil
+}
+
+//Write post data to CCStorage queue
+func (s *bcsStorageSink) Write(data *types.BcsSyncData) error {
+	return s.cc.Sync(data)
+}
+
+//Close CCStorage exits with context
+func (s *bcsStorageSink) Close() error {
+	return nil
+}
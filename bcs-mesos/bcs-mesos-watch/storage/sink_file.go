@@ -0,0 +1,137 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"bk-bcs/bcs-mesos/bcs-mesos-watch/types"
+)
+
+//FileSinkOptions options of file sink
+type FileSinkOptions struct {
+	Path       string `json:"path"`
+	MaxSize    int    `json:"maxSize,omitempty"`    //MB of one file before rotating, default 100
+	MaxBackups int    `json:"maxBackups,omitempty"` //rotated files to keep, default 5
+}
+
+//fileSink write one json event per line, file is rotated as path.<timestamp> when exceeding MaxSize
+type fileSink struct {
+	clusterID string
+	options   *FileSinkOptions
+	file      *os.File
+	size      int64
+}
+
+func newFileSink(cfg *SinkConfig, env *SinkEnv) (Sink, error) {
+	options := &FileSinkOptions{}
+	if err := json.Unmarshal(cfg.Options, options); err != nil {
+		return nil, fmt.Errorf("decode file options failed: %s", err.Error())
+	}
+	if options.Path == "" {
+		return nil, fmt.Errorf("file path is empty")
+	}
+	if options.MaxSize <= 0 {
+		options.MaxSize = 100
+	}
+	if options.MaxBackups <= 0 {
+		options.MaxBackups = 5
+	}
+	s := &fileSink{
+		clusterID: env.ClusterID,
+		options:   options,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.options.Path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.options.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+//rotate rename current file with timestamp suffix and clean old backups
+func (s *fileSink) rotate() error {
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+	backup := s.options.Path + "." + time.Now().Format("20060102150405.000")
+	if err := os.Rename(s.options.Path, backup); err != nil {
+		return err
+	}
+	backups, _ := filepath.Glob(s.options.Path + ".*")
+	sort.Strings(backups)
+	for len(backups) > s.options.MaxBackups {
+		if !strings.HasPrefix(backups[0], s.options.Path+".") {
+			break
+		}
+		os.Remove(backups[0])
+		backups = backups[1:]
+	}
+	return s.open()
+}
+
+//Write append event as one line
+func (s *fileSink) Write(data *types.BcsSyncData) error {
+	line, err := encodeSinkEvent(s.clusterID, data)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	if s.size > 0 && s.size+int64(len(line)) > int64(s.options.MaxSize)*1024*1024 {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+//Close close current file
+func (s *fileSink) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,137 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"bk-bcs/bcs-common/common/ssl"
+	commtypes "bk-bcs/bcs-common/common/types"
+	lbtypes "bk-bcs/bcs-common/pkg/loadbalance/v2"
+	"bk-bcs/bcs-mesos/bcs-mesos-watch/types"
+	schedtypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+
+	"github.com/Shopify/sarama"
+)
+
+func init() {
+	RegisterSink(SinkKafka, newKafkaSink)
+}
+
+//KafkaSinkOptions options of kafka sink
+type KafkaSinkOptions struct {
+	Brokers  []string `json:"brokers"`
+	Topic    string   `json:"topic"`
+	Timeout  int      `json:"timeout,omitempty"` //seconds, default 10
+	CAFile   string   `json:"caFile,omitempty"`
+	CertFile string   `json:"certFile,omitempty"`
+	KeyFile  string   `json:"keyFile,omitempty"`
+	Password string   `json:"password,omitempty"` //password of key file
+}
+
+//kafkaSink produce events with key namespace/name,
+//so events of one object keep in order in one partition
+type kafkaSink struct {
+	clusterID string
+	topic     string
+	producer  sarama.SyncProducer
+}
+
+func newKafkaSink(cfg *SinkConfig, env *SinkEnv) (Sink, error) {
+	options := &KafkaSinkOptions{}
+	if err := json.Unmarshal(cfg.Options, options); err != nil {
+		return nil, fmt.Errorf("decode kafka options failed: %s", err.Error())
+	}
+	if len(options.Brokers) == 0 || options.Topic == "" {
+		return nil, fmt.Errorf("kafka brokers or topic is empty")
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10
+	}
+
+	conf := sarama.NewConfig()
+	conf.ClientID = "bcs-mesos-watch"
+	conf.Net.DialTimeout = time.Duration(options.Timeout) * time.Second
+	conf.Producer.Timeout = time.Duration(options.Timeout) * time.Second
+	conf.Producer.RequiredAcks = sarama.WaitForAll
+	conf.Producer.Partitioner = sarama.NewHashPartitioner
+	//retry is done by sink proxy
+	conf.Producer.Retry.Max = 0
+	conf.Producer.Return.Successes = true
+	if options.CAFile != "" || options.CertFile != "" {
+		tlsConf, err := ssl.ClientTslConfVerity(options.CAFile, options.CertFile, options.KeyFile, options.Password)
+		if err != nil {
+			return nil, fmt.Errorf("load kafka tls config failed: %s", err.Error())
+		}
+		conf.Net.TLS.Enable = true
+		conf.Net.TLS.Config = tlsConf
+	}
+
+	producer, err := sarama.NewSyncProducer(options.Brokers, conf)
+	if err != nil {
+		return nil, fmt.Errorf("create kafka producer failed: %s", err.Error())
+	}
+	return &kafkaSink{
+		clusterID: env.ClusterID,
+		topic:     options.Topic,
+		producer:  producer,
+	}, nil
+}
+
+//Write produce one event
+func (s *kafkaSink) Write(data *types.BcsSyncData) error {
+	value, err := encodeSinkEvent(s.clusterID, data)
+	if err != nil {
+		return err
+	}
+	msg := &sarama.ProducerMessage{
+		Topic: s.topic,
+		Value: sarama.ByteEncoder(value),
+	}
+	if key := getSinkDataKey(data); key != "" {
+		msg.Key = sarama.StringEncoder(key)
+	}
+	_, _, err = s.producer.SendMessage(msg)
+	return err
+}
+
+//Close close producer
+func (s *kafkaSink) Close() error {
+	return s.producer.Close()
+}
+
+//getSinkDataKey return namespace/name of data item
+func getSinkDataKey(data *types.BcsSyncData) string {
+	switch item := data.Item.(type) {
+	case *schedtypes.Application:
+		return item.RunAs + "/" + item.ID
+	case *schedtypes.TaskGroup:
+		return item.RunAs + "/" + item.ID
+	case *schedtypes.Deployment:
+		return item.ObjectMeta.NameSpace + "/" + item.ObjectMeta.Name
+	case *lbtypes.ExportService:
+		return item.Namespace + "/" + item.ServiceName
+	case *commtypes.BcsService:
+		return item.ObjectMeta.NameSpace + "/" + item.ObjectMeta.Name
+	case *commtypes.BcsConfigMap:
+		return item.ObjectMeta.NameSpace + "/" + item.ObjectMeta.Name
+	case *commtypes.BcsSecret:
+		return item.ObjectMeta.NameSpace + "/" + item.ObjectMeta.Name
+	case *commtypes.BcsEndpoint:
+		return item.ObjectMeta.NameSpace + "/" + item.ObjectMeta.Name
+	}
+	return ""
+}
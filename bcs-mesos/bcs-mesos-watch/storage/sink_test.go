@@ -0,0 +1,156 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-mesos-watch/types"
+	schedtypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+
+	"golang.org/x/net/context"
+)
+
+type fakeSink struct {
+	fails   int
+	written []*types.BcsSyncData
+}
+
+func (s *fakeSink) Write(data *types.BcsSyncData) error {
+	if s.fails > 0 {
+		s.fails--
+		return fmt.Errorf("fake failure")
+	}
+	s.written = append(s.written, data)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	return nil
+}
+
+func TestSinkProxyKindFilter(t *testing.T) {
+	proxy := newSinkProxy(&SinkConfig{Name: "test", Kinds: []string{"TaskGroup", "Deployment"}}, &fakeSink{})
+	tests := []struct {
+		dataType string
+		accept   bool
+	}{
+		{types.TaskgroupChannelPrefix + "10", true},
+		{"Deployment", true},
+		{types.ApplicationChannelPrefix + "1", false},
+		{"Service", false},
+	}
+	for _, test := range tests {
+		if proxy.accept(&types.BcsSyncData{DataType: test.dataType}) != test.accept {
+			t.Errorf("data type %s: expect accept %v", test.dataType, test.accept)
+		}
+	}
+}
+
+func TestSinkProxyRetry(t *testing.T) {
+	sink := &fakeSink{fails: 2}
+	proxy := newSinkProxy(&SinkConfig{Name: "test", MaxRetries: 2}, sink)
+	proxy.retryInterval = time.Millisecond
+	proxy.write(context.Background(), &types.BcsSyncData{DataType: "Service", Action: "Add"})
+	if len(sink.written) != 1 {
+		t.Errorf("expect data written after 2 retries, got %d", len(sink.written))
+	}
+
+	sink = &fakeSink{fails: 2}
+	proxy = newSinkProxy(&SinkConfig{Name: "test", MaxRetries: -1}, sink)
+	proxy.write(context.Background(), &types.BcsSyncData{DataType: "Service", Action: "Add"})
+	if len(sink.written) != 0 || sink.fails != 1 {
+		t.Errorf("expect data dropped without retry, written %d, fails left %d", len(sink.written), sink.fails)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var event SinkEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		sign := SignWebhookBody("secret", r.Header.Get(WebhookTimestampHeader), body)
+		if sign != r.Header.Get(WebhookSignatureHeader) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.Unmarshal(body, &event)
+	}))
+	defer srv.Close()
+
+	options, _ := json.Marshal(&WebhookSinkOptions{URL: srv.URL, Secret: "secret"})
+	sink, err := newWebhookSink(&SinkConfig{Type: SinkWebhook, Options: options}, &SinkEnv{ClusterID: "BCS-MESOS-10001"})
+	if err != nil {
+		t.Fatalf("create webhook sink failed: %v", err)
+	}
+	data := &types.BcsSyncData{
+		DataType: types.TaskgroupChannelPrefix + "3",
+		Action:   "Update",
+		Item:     &schedtypes.TaskGroup{ID: "0.app.ns.10001.1", RunAs: "ns"},
+	}
+	if err := sink.Write(data); err != nil {
+		t.Fatalf("write webhook failed: %v", err)
+	}
+	if event.ClusterID != "BCS-MESOS-10001" || event.Kind != "TaskGroup" || event.Action != "Update" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestFileSinkRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "events.json")
+	options, _ := json.Marshal(&FileSinkOptions{Path: path, MaxBackups: 1})
+	sink, err := newFileSink(&SinkConfig{Type: SinkFile, Options: options}, &SinkEnv{ClusterID: "BCS-MESOS-10001"})
+	if err != nil {
+		t.Fatalf("create file sink failed: %v", err)
+	}
+	defer sink.Close()
+	fs := sink.(*fileSink)
+
+	data := &types.BcsSyncData{
+		DataType: "Service",
+		Action:   "Add",
+		Item:     &commtypes.BcsService{ObjectMeta: commtypes.ObjectMeta{Name: "svc", NameSpace: "ns"}},
+	}
+	for i := 0; i < 3; i++ {
+		//force rotating before every write
+		fs.size = int64(fs.options.MaxSize) * 1024 * 1024
+		if err := sink.Write(data); err != nil {
+			t.Fatalf("write file failed: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 1 {
+		t.Errorf("expect 1 backup, got %v", backups)
+	}
+	content, _ := ioutil.ReadFile(path)
+	event := &SinkEvent{}
+	if err := json.Unmarshal(content, event); err != nil || event.Kind != "Service" {
+		t.Errorf("unexpected file content %s: %v", content, err)
+	}
+}
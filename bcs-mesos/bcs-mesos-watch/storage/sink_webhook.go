@@ -0,0 +1,139 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"bk-bcs/bcs-mesos/bcs-mesos-watch/types"
+)
+
+const (
+	//WebhookSignatureHeader header of hmac-sha256 signature, value is hex of hmac(secret, timestamp + "." + body)
+	WebhookSignatureHeader = "X-Bcs-Signature"
+	//WebhookTimestampHeader header of unix timestamp when request is signed
+	WebhookTimestampHeader = "X-Bcs-Timestamp"
+)
+
+func init() {
+	RegisterSink(SinkWebhook, newWebhookSink)
+	RegisterSink(SinkFile, newFileSink)
+}
+
+//SinkEvent is payload of webhook, file and kafka sinks
+type SinkEvent struct {
+	ClusterID string      `json:"clusterID"`
+	Kind      string      `json:"kind"`
+	Action    string      `json:"action"`
+	Timestamp int64       `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+//encodeSinkEvent json encode sync data as SinkEvent
+func encodeSinkEvent(clusterID string, data *types.BcsSyncData) ([]byte, error) {
+	return json.Marshal(&SinkEvent{
+		ClusterID: clusterID,
+		Kind:      types.GetDataKind(data.DataType),
+		Action:    data.Action,
+		Timestamp: time.Now().Unix(),
+		Data:      data.Item,
+	})
+}
+
+//WebhookSinkOptions options of webhook sink
+type WebhookSinkOptions struct {
+	URL     string            `json:"url"`
+	Secret  string            `json:"secret,omitempty"`  //hmac key, request is not signed if empty
+	Timeout int               `json:"timeout,omitempty"` //seconds, default 10
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type webhookSink struct {
+	clusterID string
+	options   *WebhookSinkOptions
+	client    *http.Client
+}
+
+func newWebhookSink(cfg *SinkConfig, env *SinkEnv) (Sink, error) {
+	options := &WebhookSinkOptions{}
+	if err := json.Unmarshal(cfg.Options, options); err != nil {
+		return nil, fmt.Errorf("decode webhook options failed: %s", err.Error())
+	}
+	if options.URL == "" {
+		return nil, fmt.Errorf("webhook url is empty")
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10
+	}
+	return &webhookSink{
+		clusterID: env.ClusterID,
+		options:   options,
+		client:    &http.Client{Timeout: time.Duration(options.Timeout) * time.Second},
+	}, nil
+}
+
+//SignWebhookBody return hex of hmac-sha256 signature for webhook body
+func SignWebhookBody(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+//Write post event to webhook, non 2xx response is error
+func (s *webhookSink) Write(data *types.BcsSyncData) error {
+	body, err := encodeSinkEvent(s.clusterID, data)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, value := range s.options.Headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.options.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, SignWebhookBody(s.options.Secret, timestamp, body))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+//Close nothing to release
+func (s *webhookSink) Close() error {
+	return nil
+}
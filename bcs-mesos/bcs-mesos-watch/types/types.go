@@ -13,6 +13,8 @@
 
 package types
 
+import "strings"
+
 //BcsSyncData holder for sync data
 type BcsSyncData struct {
 	DataType string      //data type: reflect.TypeOf(Item).Name()
@@ -42,6 +44,8 @@ type CmdConfig struct {
 	ServerKeyFile  string
 	ServerPassWord string
 	ServerSchem    string
+
+	SinkConfigFile string
 }
 
 const (
@@ -49,3 +53,17 @@ const (
 	TaskgroupChannelPrefix     = "TaskGroup_"
 	ExportserviceChannelPrefix = "Exportservice_"
 )
+
+//GetDataKind return data kind of BcsSyncData.DataType, channel suffix is removed,
+//like TaskGroup_10 to TaskGroup
+func GetDataKind(dataType string) string {
+	switch {
+	case strings.HasPrefix(dataType, ApplicationChannelPrefix):
+		return "Application"
+	case strings.HasPrefix(dataType, TaskgroupChannelPrefix):
+		return "TaskGroup"
+	case strings.HasPrefix(dataType, ExportserviceChannelPrefix):
+		return "ExportService"
+	}
+	return dataType
+}
@@ -23,6 +23,7 @@ import (
 	"bk-bcs/bcs-common/common/blog"
 	"bk-bcs/bcs-common/common/conf"
 	"bk-bcs/bcs-common/common/license"
+	"bk-bcs/bcs-common/common/tracing"
 	schedutil "bk-bcs/bcs-mesos/bcs-scheduler/src/util"
 	"golang.org/x/net/context"
 	"runtime"
@@ -49,6 +50,11 @@ func main() {
 	//license.CheckLicense()
 	license.CheckLicense(op.LicenseServerConfig)
 
+	if err := tracing.InitTracer("bcs-scheduler", op.TracingConfig); err != nil {
+		blog.Errorf("init tracer failed: %s", err.Error())
+	}
+	defer tracing.Shutdown()
+
 	scheduler, _ := NewScheduler(*config)
 	scheduler.Start(context.Background())
 }
@@ -43,7 +43,7 @@ func New(config util.SchedConfig) (*Manager, error) {
 		config: config,
 	}
 
-	dbzk := store.NewDbZk(strings.Split(config.ZkHost, ","))
+	dbzk := store.NewDbTrace("zk", store.NewDbZk(strings.Split(config.ZkHost, ",")))
 	dbzk.Connect()
 
 	zkStore := store.NewManagerStore(dbzk)
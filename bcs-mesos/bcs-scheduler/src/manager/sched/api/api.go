@@ -18,12 +18,15 @@ import (
 	comm "bk-bcs/bcs-common/common"
 	"bk-bcs/bcs-common/common/blog"
 	bhttp "bk-bcs/bcs-common/common/http"
+	"bk-bcs/bcs-common/common/tracing"
 	commtypes "bk-bcs/bcs-common/common/types"
 	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/emicklei/go-restful"
 	"github.com/samuel/go-zookeeper/zk"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -31,18 +34,18 @@ import (
 
 func (r *Router) queryAgentSettingList(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv query agentsettinglist request")
+	tracing.RequestLogger(req).V(3).Info("recv query agentsettinglist request")
 
 	var IPs []string
 	if req.QueryParameter("ips") != "" {
 		IPs = strings.Split(req.QueryParameter("ips"), ",")
 	}
-	settingList, errcode, err := r.backend.QueryAgentSettingList(IPs)
+	settingList, errcode, err := r.backendOf(req).QueryAgentSettingList(IPs)
 	if err != nil {
-		blog.Error("fail to query agentsettinglist, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to query agentsettinglist, err:%s", err.Error())
 		data := createResponeDataV2(errcode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -50,25 +53,25 @@ func (r *Router) queryAgentSettingList(req *restful.Request, resp *restful.Respo
 
 	data := createResponeData(nil, "", settingList)
 	resp.Write([]byte(data))
-	blog.Info("query agentsettinglist finish")
+	tracing.RequestLogger(req).Info("query agentsettinglist finish")
 
 	return
 }
 
 func (r *Router) deleteAgentSettingList(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv delete agentsettinglist request")
+	tracing.RequestLogger(req).V(3).Info("recv delete agentsettinglist request")
 
 	var IPs []string
 	if req.QueryParameter("ips") != "" {
 		IPs = strings.Split(req.QueryParameter("ips"), ",")
 	}
-	errcode, err := r.backend.DeleteAgentSettingList(IPs)
+	errcode, err := r.backendOf(req).DeleteAgentSettingList(IPs)
 	if err != nil {
-		blog.Error("fail to delete agentsettinglist, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to delete agentsettinglist, err:%s", err.Error())
 		data := createResponeDataV2(errcode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -76,31 +79,31 @@ func (r *Router) deleteAgentSettingList(req *restful.Request, resp *restful.Resp
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("delete agentsettinglist finish")
+	tracing.RequestLogger(req).Info("delete agentsettinglist finish")
 
 	return
 }
 
 func (r *Router) setAgentSettingList(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv set agentsettinglist request")
+	tracing.RequestLogger(req).V(3).Info("recv set agentsettinglist request")
 
 	var agents []*commtypes.BcsClusterAgentSetting
 
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&agents); err != nil {
-		blog.Warn("fail to Decode json for agents, err:%s", err.Error())
+		tracing.RequestLogger(req).Warn("fail to Decode json for agents, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	errcode, err := r.backend.SetAgentSettingList(agents)
+	errcode, err := r.backendOf(req).SetAgentSettingList(agents)
 	if err != nil {
-		blog.Error("fail to set agentsettinglist, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to set agentsettinglist, err:%s", err.Error())
 		data := createResponeDataV2(errcode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -108,25 +111,25 @@ func (r *Router) setAgentSettingList(req *restful.Request, resp *restful.Respons
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("set agentsettinglist finish")
+	tracing.RequestLogger(req).Info("set agentsettinglist finish")
 
 	return
 }
 
 func (r *Router) disableAgentList(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv disable agentlist request")
+	tracing.RequestLogger(req).V(3).Info("recv disable agentlist request")
 
 	var IPs []string
 	if req.QueryParameter("ips") != "" {
 		IPs = strings.Split(req.QueryParameter("ips"), ",")
 	}
-	errcode, err := r.backend.DisableAgentList(IPs)
+	errcode, err := r.backendOf(req).DisableAgentList(IPs)
 	if err != nil {
-		blog.Error("fail to disable agentlist, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to disable agentlist, err:%s", err.Error())
 		data := createResponeDataV2(errcode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -134,25 +137,25 @@ func (r *Router) disableAgentList(req *restful.Request, resp *restful.Response)
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("disable agentlist finish")
+	tracing.RequestLogger(req).Info("disable agentlist finish")
 
 	return
 }
 
 func (r *Router) enableAgentList(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv enable agentlist request")
+	tracing.RequestLogger(req).V(3).Info("recv enable agentlist request")
 
 	var IPs []string
 	if req.QueryParameter("ips") != "" {
 		IPs = strings.Split(req.QueryParameter("ips"), ",")
 	}
-	errcode, err := r.backend.EnableAgentList(IPs)
+	errcode, err := r.backendOf(req).EnableAgentList(IPs)
 	if err != nil {
-		blog.Error("fail to enable agentlist, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to enable agentlist, err:%s", err.Error())
 		data := createResponeDataV2(errcode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -160,31 +163,112 @@ func (r *Router) enableAgentList(req *restful.Request, resp *restful.Response) {
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("enable agentlist finish")
+	tracing.RequestLogger(req).Info("enable agentlist finish")
+
+	return
+}
+
+func (r *Router) drainAgent(req *restful.Request, resp *restful.Response) {
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+	tracing.RequestLogger(req).V(3).Info("recv drain agent request")
+
+	IP := req.PathParameter("IP")
+
+	var option commtypes.AgentDrainOption
+	decoder := json.NewDecoder(req.Request.Body)
+	if err := decoder.Decode(&option); err != nil && err != io.EOF {
+		tracing.RequestLogger(req).Error("fail to Decode json for drain agent(%s), err:%s", IP, err.Error())
+		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	drain, err := r.backendOf(req).DrainAgent(IP, &option)
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to drain agent(%s), err:%s", IP, err.Error())
+		data := createResponeDataV2(comm.BcsErrCommCreateZkNodeFail, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	data := createResponeData(nil, "success", drain)
+	resp.Write([]byte(data))
+	tracing.RequestLogger(req).Info("drain agent(%s) started", IP)
+
+	return
+}
+
+func (r *Router) queryAgentDrain(req *restful.Request, resp *restful.Response) {
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+	tracing.RequestLogger(req).V(3).Info("recv query agent drain request")
+
+	IP := req.PathParameter("IP")
+
+	drain, err := r.backendOf(req).QueryAgentDrain(IP)
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to query agent(%s) drain, err:%s", IP, err.Error())
+		data := createResponeDataV2(comm.BcsErrCommGetZkNodeFail, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	data := createResponeData(nil, "", drain)
+	resp.Write([]byte(data))
+	tracing.RequestLogger(req).V(3).Info("query agent(%s) drain finish", IP)
+
+	return
+}
+
+func (r *Router) uncordonAgent(req *restful.Request, resp *restful.Response) {
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+	tracing.RequestLogger(req).V(3).Info("recv uncordon agent request")
+
+	IP := req.PathParameter("IP")
+
+	err := r.backendOf(req).UncordonAgent(IP)
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to uncordon agent(%s), err:%s", IP, err.Error())
+		data := createResponeDataV2(comm.BcsErrCommCreateZkNodeFail, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	data := createResponeData(nil, "success", nil)
+	resp.Write([]byte(data))
+	tracing.RequestLogger(req).Info("uncordon agent(%s) finish", IP)
 
 	return
 }
 
 func (r *Router) updateAgentSettingList(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv update agentsetting request")
+	tracing.RequestLogger(req).V(3).Info("recv update agentsetting request")
 
 	var update commtypes.BcsClusterAgentSettingUpdate
 
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&update); err != nil {
-		blog.Error("fail to Decode json for update, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to Decode json for update, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	errcode, err := r.backend.UpdateAgentSettingList(&update)
+	errcode, err := r.backendOf(req).UpdateAgentSettingList(&update)
 	if err != nil {
-		blog.Error("fail to update agentsetting, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to update agentsetting, err:%s", err.Error())
 		data := createResponeDataV2(errcode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -192,23 +276,23 @@ func (r *Router) updateAgentSettingList(req *restful.Request, resp *restful.Resp
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("update agentsetting finish")
+	tracing.RequestLogger(req).Info("update agentsetting finish")
 
 	return
 }
 
 func (r *Router) queryAgentSetting(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv query agent setting request")
+	tracing.RequestLogger(req).V(3).Info("recv query agent setting request")
 
 	IP := req.PathParameter("IP")
 
-	setting, err := r.backend.QueryAgentSetting(IP)
+	setting, err := r.backendOf(req).QueryAgentSetting(IP)
 	if err != nil {
-		blog.Error("fail to query agent(%s) setting, err:%s", IP, err.Error())
+		tracing.RequestLogger(req).Error("fail to query agent(%s) setting, err:%s", IP, err.Error())
 		data := createResponeDataV2(comm.BcsErrCommGetZkNodeFail, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -216,23 +300,23 @@ func (r *Router) queryAgentSetting(req *restful.Request, resp *restful.Response)
 
 	data := createResponeData(nil, "", setting)
 	resp.Write([]byte(data))
-	blog.Info("query agent(%s) setting finish", IP)
+	tracing.RequestLogger(req).Info("query agent(%s) setting finish", IP)
 
 	return
 }
 
 func (r *Router) disableAgent(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv disable agent request")
+	tracing.RequestLogger(req).V(3).Info("recv disable agent request")
 
 	IP := req.PathParameter("IP")
 
-	err := r.backend.DisableAgent(IP)
+	err := r.backendOf(req).DisableAgent(IP)
 	if err != nil {
-		blog.Error("fail to disable agent(%s), err:%s", IP, err.Error())
+		tracing.RequestLogger(req).Error("fail to disable agent(%s), err:%s", IP, err.Error())
 		data := createResponeDataV2(comm.BcsErrCommCreateZkNodeFail, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -240,23 +324,23 @@ func (r *Router) disableAgent(req *restful.Request, resp *restful.Response) {
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("disable agent(%s) finish", IP)
+	tracing.RequestLogger(req).Info("disable agent(%s) finish", IP)
 
 	return
 }
 
 func (r *Router) enableAgent(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv enable agent request")
+	tracing.RequestLogger(req).V(3).Info("recv enable agent request")
 
 	IP := req.PathParameter("IP")
 
-	err := r.backend.EnableAgent(IP)
+	err := r.backendOf(req).EnableAgent(IP)
 	if err != nil {
-		blog.Error("fail to enable agent(%s), err:%s", IP, err.Error())
+		tracing.RequestLogger(req).Error("fail to enable agent(%s), err:%s", IP, err.Error())
 		data := createResponeDataV2(comm.BcsErrCommCreateZkNodeFail, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -264,7 +348,7 @@ func (r *Router) enableAgent(req *restful.Request, resp *restful.Response) {
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("enable agent(%s) finish", IP)
+	tracing.RequestLogger(req).Info("enable agent(%s) finish", IP)
 
 	return
 }
@@ -272,21 +356,21 @@ func (r *Router) enableAgent(req *restful.Request, resp *restful.Response) {
 func (r *Router) healthCheckReport(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
 	var healthCheck commtypes.HealthCheckResult
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&healthCheck); err != nil {
-		blog.Error("fail to Decode json for healthCheck, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to Decode json for healthCheck, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	//blog.Infof("recv HealthCheckResult: %+v", healthCheck)
-	go r.backend.HealthyReport(&healthCheck)
+	//tracing.RequestLogger(req).Info("recv HealthCheckResult: %+v", healthCheck)
+	go r.backendOf(req).HealthyReport(&healthCheck)
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
@@ -296,29 +380,29 @@ func (r *Router) healthCheckReport(req *restful.Request, resp *restful.Response)
 
 func (r *Router) createDeployment(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv create deployment request")
+	tracing.RequestLogger(req).V(3).Info("recv create deployment request")
 
 	var deploymentDef types.DeploymentDef
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&deploymentDef); err != nil {
-		blog.Error("fail to Decode json to create deployment, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to Decode json to create deployment, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request create deployment(%s.%s)",
+	tracing.RequestLogger(req).Info("request create deployment(%s.%s)",
 		deploymentDef.ObjectMeta.NameSpace, deploymentDef.ObjectMeta.Name)
 	if deploymentDef.RawJson == nil {
-		blog.Warn("request create deployment(%s.%s) without raw json, please check driver version",
+		tracing.RequestLogger(req).Warn("request create deployment(%s.%s) without raw json, please check driver version",
 			deploymentDef.ObjectMeta.NameSpace, deploymentDef.ObjectMeta.Name)
 	}
 
-	if errcode, err := r.backend.CreateDeployment(&deploymentDef); err != nil {
-		blog.Error("fail to create deployment(%s.%s), err:%s",
+	if errcode, err := r.backendOf(req).CreateDeployment(&deploymentDef); err != nil {
+		tracing.RequestLogger(req).Error("fail to create deployment(%s.%s), err:%s",
 			deploymentDef.ObjectMeta.NameSpace, deploymentDef.ObjectMeta.Name, err.Error())
 		data := createResponeDataV2(errcode, err.Error(), nil)
 		resp.Write([]byte(data))
@@ -328,36 +412,36 @@ func (r *Router) createDeployment(req *restful.Request, resp *restful.Response)
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request create deployment(%s.%s) end",
+	tracing.RequestLogger(req).Info("request create deployment(%s.%s) end",
 		deploymentDef.ObjectMeta.NameSpace, deploymentDef.ObjectMeta.Name)
 	return
 }
 
 func (r *Router) updateDeployment(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv update deployment request")
+	tracing.RequestLogger(req).V(3).Info("recv update deployment request")
 
 	var deploymentDef types.DeploymentDef
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&deploymentDef); err != nil {
-		blog.Error("fail to Decode json to update deployment , err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to Decode json to update deployment , err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request update deployment(%s.%s)",
+	tracing.RequestLogger(req).Info("request update deployment(%s.%s)",
 		deploymentDef.ObjectMeta.NameSpace, deploymentDef.ObjectMeta.Name)
 	if deploymentDef.RawJson == nil {
-		blog.Warn("request update deployment(%s.%s) without raw json, please check driver version",
+		tracing.RequestLogger(req).Warn("request update deployment(%s.%s) without raw json, please check driver version",
 			deploymentDef.ObjectMeta.NameSpace, deploymentDef.ObjectMeta.Name)
 	}
 
-	if errCode, err := r.backend.UpdateDeployment(&deploymentDef); err != nil {
-		blog.Error("fail to update deployment(%s.%s), err:%s",
+	if errCode, err := r.backendOf(req).UpdateDeployment(&deploymentDef); err != nil {
+		tracing.RequestLogger(req).Error("fail to update deployment(%s.%s), err:%s",
 			deploymentDef.ObjectMeta.NameSpace, deploymentDef.ObjectMeta.Name, err.Error())
 		data := createResponeDataV2(errCode, err.Error(), nil)
 		resp.Write([]byte(data))
@@ -367,22 +451,22 @@ func (r *Router) updateDeployment(req *restful.Request, resp *restful.Response)
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request update deployment(%s.%s) end",
+	tracing.RequestLogger(req).Info("request update deployment(%s.%s) end",
 		deploymentDef.ObjectMeta.NameSpace, deploymentDef.ObjectMeta.Name)
 	return
 }
 
 func (r *Router) cancelUpdateDeployment(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	ns := req.PathParameter("namespace")
 	name := req.PathParameter("name")
-	blog.Infof("request cancelupdate depolyment(%s.%s)", ns, name)
+	tracing.RequestLogger(req).Info("request cancelupdate depolyment(%s.%s)", ns, name)
 
-	if err := r.backend.CancelUpdateDeployment(ns, name); err != nil {
-		blog.Error("fail to cancelupdate deployment(%s.%s), err:%s", ns, name, err.Error())
+	if err := r.backendOf(req).CancelUpdateDeployment(ns, name); err != nil {
+		tracing.RequestLogger(req).Error("fail to cancelupdate deployment(%s.%s), err:%s", ns, name, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -391,21 +475,21 @@ func (r *Router) cancelUpdateDeployment(req *restful.Request, resp *restful.Resp
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request cancelupdate deployment(%s.%s) end", ns, name)
+	tracing.RequestLogger(req).Info("request cancelupdate deployment(%s.%s) end", ns, name)
 	return
 }
 
 func (r *Router) pauseUpdateDeployment(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	ns := req.PathParameter("namespace")
 	name := req.PathParameter("name")
-	blog.Infof("request pauseupdate depolyment(%s.%s)", ns, name)
+	tracing.RequestLogger(req).Info("request pauseupdate depolyment(%s.%s)", ns, name)
 
-	if err := r.backend.PauseUpdateDeployment(ns, name); err != nil {
-		blog.Error("fail to pauseupdate deployment(%s.%s), err:%s", ns, name, err.Error())
+	if err := r.backendOf(req).PauseUpdateDeployment(ns, name); err != nil {
+		tracing.RequestLogger(req).Error("fail to pauseupdate deployment(%s.%s), err:%s", ns, name, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -414,21 +498,21 @@ func (r *Router) pauseUpdateDeployment(req *restful.Request, resp *restful.Respo
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request pauseupdate deployment(%s.%s) end", ns, name)
+	tracing.RequestLogger(req).Info("request pauseupdate deployment(%s.%s) end", ns, name)
 	return
 }
 
 func (r *Router) resumeUpdateDeployment(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	ns := req.PathParameter("namespace")
 	name := req.PathParameter("name")
-	blog.Infof("request resumeupdate depolyment(%s.%s)", ns, name)
+	tracing.RequestLogger(req).Info("request resumeupdate depolyment(%s.%s)", ns, name)
 
-	if err := r.backend.ResumeUpdateDeployment(ns, name); err != nil {
-		blog.Error("fail to resumeupdate deployment(%s.%s), err:%s", ns, name, err.Error())
+	if err := r.backendOf(req).ResumeUpdateDeployment(ns, name); err != nil {
+		tracing.RequestLogger(req).Error("fail to resumeupdate deployment(%s.%s), err:%s", ns, name, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -437,13 +521,41 @@ func (r *Router) resumeUpdateDeployment(req *restful.Request, resp *restful.Resp
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request resumeupdate deployment(%s.%s) end", ns, name)
+	tracing.RequestLogger(req).Info("request resumeupdate deployment(%s.%s) end", ns, name)
 	return
 }
 
+// fetchDeployment is used to get deployment with the status of rolling update
+func (r *Router) fetchDeployment(req *restful.Request, resp *restful.Response) {
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+
+	ns := req.PathParameter("namespace")
+	name := req.PathParameter("name")
+	tracing.RequestLogger(req).V(3).Info("request fetch deployment(%s.%s)", ns, name)
+
+	deployment, err := r.backendOf(req).GetDeployment(ns, name)
+	if err == zk.ErrNoNode {
+		data := createResponeDataV2(common.BcsErrMesosSchedNotFound, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+	if err != nil {
+		tracing.RequestLogger(req).Error("fail to fetch deployment(%s.%s), err:%s", ns, name, err.Error())
+		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	data := createResponeData(nil, "", deployment)
+	resp.Write([]byte(data))
+}
+
 func (r *Router) deleteDeployment(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
@@ -455,11 +567,11 @@ func (r *Router) deleteDeployment(req *restful.Request, resp *restful.Response)
 
 	ns := req.PathParameter("namespace")
 	name := req.PathParameter("name")
-	blog.Infof("request delete deployment(%s.%s)", ns, name)
+	tracing.RequestLogger(req).Info("request delete deployment(%s.%s)", ns, name)
 
 	var data string
-	if errCode, err := r.backend.DeleteDeployment(ns, name, enforce); err != nil {
-		blog.Error("fail to delete deployment(%s.%s), err:%s", ns, name, err.Error())
+	if errCode, err := r.backendOf(req).DeleteDeployment(ns, name, enforce); err != nil {
+		tracing.RequestLogger(req).Error("fail to delete deployment(%s.%s), err:%s", ns, name, err.Error())
 		//if strings.Contains(err.Error(),"node does not exist") {
 		//	data = createResponeDataV2(common.BcsErrMesosSchedNotFound, err.Error(), nil)
 		//}else{
@@ -474,80 +586,80 @@ func (r *Router) deleteDeployment(req *restful.Request, resp *restful.Response)
 	data = createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request delete deployment(%s.%s) end", ns, name)
+	tracing.RequestLogger(req).Info("request delete deployment(%s.%s) end", ns, name)
 	return
 }
 
 func (r *Router) getClusterResources(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
-	blog.V(3).Info("request get cluster resource request")
+	tracing.RequestLogger(req).V(3).Info("request get cluster resource request")
 
-	res, err := r.backend.GetClusterResources()
+	res, err := r.backendOf(req).GetClusterResources()
 	if err != nil {
-		blog.Error("request get cluster resource request err:%s", err.Error())
+		tracing.RequestLogger(req).Error("request get cluster resource request err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 	} else {
 		data := createResponeData(nil, "", res)
 		resp.Write([]byte(data))
-		blog.Info("request get cluster resource request finish")
+		tracing.RequestLogger(req).Info("request get cluster resource request finish")
 	}
 	return
 }
 
 func (r *Router) getCurrentOffers(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
-	blog.V(3).Info("request get current offers request")
+	tracing.RequestLogger(req).V(3).Info("request get current offers request")
 
-	res := r.backend.GetCurrentOffers()
+	res := r.backendOf(req).GetCurrentOffers()
 	data := createResponeData(nil, "", res)
 	resp.Write([]byte(data))
-	blog.Info("request get current offers request finish")
+	tracing.RequestLogger(req).Info("request get current offers request finish")
 }
 
 func (r *Router) getClusterEndpoints(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
-	blog.V(3).Info("request get endpoints request")
+	tracing.RequestLogger(req).V(3).Info("request get endpoints request")
 
-	endpoints := r.backend.GetClusterEndpoints()
+	endpoints := r.backendOf(req).GetClusterEndpoints()
 	data := createResponeData(nil, "", endpoints)
 	resp.Write([]byte(data))
-	blog.Info("request get endpoints request finish")
+	tracing.RequestLogger(req).Info("request get endpoints request finish")
 	return
 }
 
 func (r *Router) createConfigMap(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
 	var configmap commtypes.BcsConfigMap
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&configmap); err != nil {
-		blog.Error("fail to decode configmap json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode configmap json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request create configmap(%s.%s): %+v", configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name, configmap)
+	tracing.RequestLogger(req).Info("request create configmap(%s.%s): %+v", configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name, configmap)
 
-	currData, _ := r.backend.FetchConfigMap(configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name)
+	currData, _ := r.backendOf(req).FetchConfigMap(configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name)
 	if currData != nil {
 		err := errors.New("configmap already exist")
 		data := createResponeDataV2(comm.BcsErrMesosSchedResourceExist, err.Error(), nil)
@@ -555,8 +667,8 @@ func (r *Router) createConfigMap(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
-	if err := r.backend.SaveConfigMap(&configmap); err != nil {
-		blog.Error("fail to save configmap, err:%s", err.Error())
+	if err := r.backendOf(req).SaveConfigMap(&configmap); err != nil {
+		tracing.RequestLogger(req).Error("fail to save configmap, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -565,29 +677,29 @@ func (r *Router) createConfigMap(req *restful.Request, resp *restful.Response) {
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request create configmap(%s.%s) end", configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name)
+	tracing.RequestLogger(req).Info("request create configmap(%s.%s) end", configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name)
 
 	return
 }
 
 func (r *Router) updateConfigMap(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
 	var configmap commtypes.BcsConfigMap
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&configmap); err != nil {
-		blog.Error("fail to decode configmap json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode configmap json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request update configmap(%s.%s): %+v",
+	tracing.RequestLogger(req).Info("request update configmap(%s.%s): %+v",
 		configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name, configmap)
-	currData, _ := r.backend.FetchConfigMap(configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name)
+	currData, _ := r.backendOf(req).FetchConfigMap(configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name)
 	if currData == nil {
 		err := errors.New("configmap not exist")
 		data := createResponeDataV2(comm.BcsErrMesosSchedNotFound, err.Error(), nil)
@@ -595,8 +707,8 @@ func (r *Router) updateConfigMap(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
-	if err := r.backend.SaveConfigMap(&configmap); err != nil {
-		blog.Error("fail to save configmap, err:%s", err.Error())
+	if err := r.backendOf(req).SaveConfigMap(&configmap); err != nil {
+		tracing.RequestLogger(req).Error("fail to save configmap, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -604,22 +716,22 @@ func (r *Router) updateConfigMap(req *restful.Request, resp *restful.Response) {
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("request update configmap(%s.%s) end", configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name)
+	tracing.RequestLogger(req).Info("request update configmap(%s.%s) end", configmap.ObjectMeta.NameSpace, configmap.ObjectMeta.Name)
 	return
 }
 
 func (r *Router) deleteConfigMap(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	ns := req.PathParameter("namespace")
 	name := req.PathParameter("name")
-	blog.V(3).Infof("request delete configmap(%s.%s)", ns, name)
+	tracing.RequestLogger(req).V(3).Info("request delete configmap(%s.%s)", ns, name)
 
 	var data string
-	if err := r.backend.DeleteConfigMap(ns, name); err != nil {
-		blog.Error("fail to delete configmap, err:%s", err.Error())
+	if err := r.backendOf(req).DeleteConfigMap(ns, name); err != nil {
+		tracing.RequestLogger(req).Error("fail to delete configmap, err:%s", err.Error())
 		if strings.Contains(err.Error(), "node does not exist") {
 			data = createResponeDataV2(common.BcsErrMesosSchedNotFound, err.Error(), nil)
 		} else {
@@ -632,27 +744,27 @@ func (r *Router) deleteConfigMap(req *restful.Request, resp *restful.Response) {
 	data = createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request delete configmap(%s.%s) end", ns, name)
+	tracing.RequestLogger(req).Info("request delete configmap(%s.%s) end", ns, name)
 	return
 }
 
 func (r *Router) createSecret(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	var secret commtypes.BcsSecret
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&secret); err != nil {
-		blog.Error("fail to decode secret json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode secret json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request create secret(%s.%s): %+v", secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name, secret)
+	tracing.RequestLogger(req).Info("request create secret(%s.%s): %+v", secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name, secret)
 
-	currData, _ := r.backend.FetchSecret(secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name)
+	currData, _ := r.backendOf(req).FetchSecret(secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name)
 	if currData != nil {
 		err := errors.New("secret already exist")
 		data := createResponeDataV2(comm.BcsErrMesosSchedResourceExist, err.Error(), nil)
@@ -660,8 +772,8 @@ func (r *Router) createSecret(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
-	if err := r.backend.SaveSecret(&secret); err != nil {
-		blog.Error("fail to save secret, err:%s", err.Error())
+	if err := r.backendOf(req).SaveSecret(&secret); err != nil {
+		tracing.RequestLogger(req).Error("fail to save secret, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -670,28 +782,28 @@ func (r *Router) createSecret(req *restful.Request, resp *restful.Response) {
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request create secret(%s.%s) end", secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name)
+	tracing.RequestLogger(req).Info("request create secret(%s.%s) end", secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name)
 
 	return
 }
 
 func (r *Router) updateSecret(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	var secret commtypes.BcsSecret
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&secret); err != nil {
-		blog.Error("fail to decode secret json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode secret json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request update secret(%s.%s): %+v",
+	tracing.RequestLogger(req).Info("request update secret(%s.%s): %+v",
 		secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name, secret)
-	currData, _ := r.backend.FetchSecret(secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name)
+	currData, _ := r.backendOf(req).FetchSecret(secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name)
 	if currData == nil {
 		err := errors.New("secret not exist")
 		data := createResponeDataV2(comm.BcsErrMesosSchedNotFound, err.Error(), nil)
@@ -699,8 +811,8 @@ func (r *Router) updateSecret(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
-	if err := r.backend.SaveSecret(&secret); err != nil {
-		blog.Error("fail to save secret, err:%s", err.Error())
+	if err := r.backendOf(req).SaveSecret(&secret); err != nil {
+		tracing.RequestLogger(req).Error("fail to save secret, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -708,22 +820,22 @@ func (r *Router) updateSecret(req *restful.Request, resp *restful.Response) {
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("request secret secret(%s.%s) end", secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name)
+	tracing.RequestLogger(req).Info("request secret secret(%s.%s) end", secret.ObjectMeta.NameSpace, secret.ObjectMeta.Name)
 	return
 }
 
 func (r *Router) deleteSecret(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	ns := req.PathParameter("namespace")
 	name := req.PathParameter("name")
-	blog.V(3).Infof("request delete secret(%s.%s)", ns, name)
+	tracing.RequestLogger(req).V(3).Info("request delete secret(%s.%s)", ns, name)
 
 	var data string
-	if err := r.backend.DeleteSecret(ns, name); err != nil {
-		blog.Error("fail to delete secret, err:%s", err.Error())
+	if err := r.backendOf(req).DeleteSecret(ns, name); err != nil {
+		tracing.RequestLogger(req).Error("fail to delete secret, err:%s", err.Error())
 		if strings.Contains(err.Error(), "node does not exist") {
 			data = createResponeDataV2(common.BcsErrMesosSchedNotFound, err.Error(), nil)
 		} else {
@@ -736,27 +848,27 @@ func (r *Router) deleteSecret(req *restful.Request, resp *restful.Response) {
 	data = createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request delete secret(%s.%s) end", ns, name)
+	tracing.RequestLogger(req).Info("request delete secret(%s.%s) end", ns, name)
 	return
 }
 
 func (r *Router) createService(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	var service commtypes.BcsService
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&service); err != nil {
-		blog.Error("fail to decode service json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode service json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request create service(%s.%s):%+v", service.ObjectMeta.NameSpace, service.ObjectMeta.Name, service)
+	tracing.RequestLogger(req).Info("request create service(%s.%s):%+v", service.ObjectMeta.NameSpace, service.ObjectMeta.Name, service)
 
-	currData, _ := r.backend.FetchService(service.ObjectMeta.NameSpace, service.ObjectMeta.Name)
+	currData, _ := r.backendOf(req).FetchService(service.ObjectMeta.NameSpace, service.ObjectMeta.Name)
 	if currData != nil {
 		err := errors.New("service already exist")
 		data := createResponeDataV2(comm.BcsErrMesosSchedResourceExist, err.Error(), nil)
@@ -764,8 +876,8 @@ func (r *Router) createService(req *restful.Request, resp *restful.Response) {
 		return
 	}
 
-	if err := r.backend.SaveService(&service); err != nil {
-		blog.Error("fail to save service, err:%s", err.Error())
+	if err := r.backendOf(req).SaveService(&service); err != nil {
+		tracing.RequestLogger(req).Error("fail to save service, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -774,28 +886,28 @@ func (r *Router) createService(req *restful.Request, resp *restful.Response) {
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request create service(%s.%s) end", service.ObjectMeta.NameSpace, service.ObjectMeta.Name)
+	tracing.RequestLogger(req).Info("request create service(%s.%s) end", service.ObjectMeta.NameSpace, service.ObjectMeta.Name)
 
 	return
 }
 
 func (r *Router) updateService(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	var service commtypes.BcsService
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&service); err != nil {
-		blog.Error("fail to decode service json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode service json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request update servie(%s.%s): %+v", service.ObjectMeta.NameSpace, service.ObjectMeta.Name, service)
+	tracing.RequestLogger(req).Info("request update servie(%s.%s): %+v", service.ObjectMeta.NameSpace, service.ObjectMeta.Name, service)
 
-	currData, _ := r.backend.FetchService(service.ObjectMeta.NameSpace, service.ObjectMeta.Name)
+	currData, _ := r.backendOf(req).FetchService(service.ObjectMeta.NameSpace, service.ObjectMeta.Name)
 	if currData == nil {
 		err := errors.New("service not exist")
 		data := createResponeDataV2(comm.BcsErrMesosSchedNotFound, err.Error(), nil)
@@ -809,8 +921,8 @@ func (r *Router) updateService(req *restful.Request, resp *restful.Response) {
 	service.ObjectMeta.Labels = currData.ObjectMeta.Labels
 	service.TypeMeta = currData.TypeMeta
 
-	if err := r.backend.SaveService(&service); err != nil {
-		blog.Error("fail to save service, err:%s", err.Error())
+	if err := r.backendOf(req).SaveService(&service); err != nil {
+		tracing.RequestLogger(req).Error("fail to save service, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -818,22 +930,22 @@ func (r *Router) updateService(req *restful.Request, resp *restful.Response) {
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("request update service(%s.%s) end", service.ObjectMeta.NameSpace, service.ObjectMeta.Name)
+	tracing.RequestLogger(req).Info("request update service(%s.%s) end", service.ObjectMeta.NameSpace, service.ObjectMeta.Name)
 	return
 }
 
 func (r *Router) deleteService(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	ns := req.PathParameter("namespace")
 	name := req.PathParameter("name")
-	blog.V(3).Infof("request delete service(%s.%s)", ns, name)
+	tracing.RequestLogger(req).V(3).Info("request delete service(%s.%s)", ns, name)
 
 	var data string
-	if err := r.backend.DeleteService(ns, name); err != nil {
-		blog.Error("fail to delete service, err:%s", err.Error())
+	if err := r.backendOf(req).DeleteService(ns, name); err != nil {
+		tracing.RequestLogger(req).Error("fail to delete service, err:%s", err.Error())
 		if strings.Contains(err.Error(), "node does not exist") {
 			data = createResponeDataV2(common.BcsErrMesosSchedNotFound, err.Error(), nil)
 		} else {
@@ -846,42 +958,42 @@ func (r *Router) deleteService(req *restful.Request, resp *restful.Response) {
 	data = createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request delete service(%s.%s) end", ns, name)
+	tracing.RequestLogger(req).Info("request delete service(%s.%s) end", ns, name)
 	return
 }
 
 // BuildApplication is used to build a new application.
 func (r *Router) buildApplication(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv build application request")
+	tracing.RequestLogger(req).V(3).Info("recv build application request")
 
 	var version types.Version
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&version); err != nil {
-		blog.Error("fail to decode json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
 	if version.RawJson == nil {
-		blog.Error("request create application(%s.%s) without raw json", version.RunAs, version.ID)
+		tracing.RequestLogger(req).Error("request create application(%s.%s) without raw json", version.RunAs, version.ID)
 	}
 
 	if version.Instances <= 0 {
-		blog.Error("request build application(%s.%s) Instances(%d) err", version.RunAs, version.ID, version.Instances)
+		tracing.RequestLogger(req).Error("request build application(%s.%s) Instances(%d) err", version.RunAs, version.ID, version.Instances)
 		err := errors.New("instances error")
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	versionErr := r.backend.CheckVersion(&version)
+	versionErr := r.backendOf(req).CheckVersion(&version)
 	if versionErr != nil {
-		blog.Error("build application(%s.%s) version error: %s", version.RunAs, version.ID, versionErr.Error())
+		tracing.RequestLogger(req).Error("build application(%s.%s) version error: %s", version.RunAs, version.ID, versionErr.Error())
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, versionErr.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -890,23 +1002,23 @@ func (r *Router) buildApplication(req *restful.Request, resp *restful.Response)
 	//check the resource, if not, set default
 	err := version.CheckAndDefaultResource()
 	if err != nil {
-		blog.Error("build application(%s.%s) version error: %s", version.RunAs, version.ID, err.Error())
+		tracing.RequestLogger(req).Error("build application(%s.%s) version error: %s", version.RunAs, version.ID, err.Error())
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
 	if version.CheckConstraints() == false {
-		blog.Error("request build: check constraints failed")
+		tracing.RequestLogger(req).Error("request build: check constraints failed")
 		err := errors.New("constraints error")
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	app, err := r.backend.FetchApplication(version.RunAs, version.ID)
+	app, err := r.backendOf(req).FetchApplication(version.RunAs, version.ID)
 	if err != nil && err != zk.ErrNoNode {
-		blog.Error("request build: fail to fetch application, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("request build: fail to fetch application, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -914,7 +1026,7 @@ func (r *Router) buildApplication(req *restful.Request, resp *restful.Response)
 
 	if app != nil {
 		err = errors.New("application already exist")
-		blog.Warn("request build fail: app(%s.%s) is already exist", version.RunAs, version.ID)
+		tracing.RequestLogger(req).Warn("request build fail: app(%s.%s) is already exist", version.RunAs, version.ID)
 		data := createResponeDataV2(comm.BcsErrMesosSchedResourceExist, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -935,23 +1047,23 @@ func (r *Router) buildApplication(req *restful.Request, resp *restful.Response)
 		ObjectMeta:       version.ObjectMeta,
 	}
 
-	blog.Info("request build: save application(RunAs:%s ID:%s)", application.RunAs, application.ID)
-	if err := r.backend.SaveApplication(&application); err != nil {
-		blog.Error("request build: fail to SaveApplication(%s.%s), err:%s", application.RunAs, application.ID, err.Error())
+	tracing.RequestLogger(req).Info("request build: save application(RunAs:%s ID:%s)", application.RunAs, application.ID)
+	if err := r.backendOf(req).SaveApplication(&application); err != nil {
+		tracing.RequestLogger(req).Error("request build: fail to SaveApplication(%s.%s), err:%s", application.RunAs, application.ID, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	if err := r.backend.SaveVersion(version.RunAs, version.ID, &version); err != nil {
-		blog.Error("request build: fail to SaveVersion(%s.%s), err:%s", version.RunAs, version.ID, err.Error())
+	if err := r.backendOf(req).SaveVersion(version.RunAs, version.ID, &version); err != nil {
+		tracing.RequestLogger(req).Error("request build: fail to SaveVersion(%s.%s), err:%s", version.RunAs, version.ID, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	if err := r.backend.LaunchApplication(&version); err != nil {
-		blog.Error("request build application(%s.%s) failed with error: %s", version.RunAs, version.ID, err.Error())
+	if err := r.backendOf(req).LaunchApplication(&version); err != nil {
+		tracing.RequestLogger(req).Error("request build application(%s.%s) failed with error: %s", version.RunAs, version.ID, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -960,24 +1072,24 @@ func (r *Router) buildApplication(req *restful.Request, resp *restful.Response)
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request build application(%s.%s) end", version.RunAs, version.ID)
+	tracing.RequestLogger(req).Info("request build application(%s.%s) end", version.RunAs, version.ID)
 	return
 }
 
 // ListApplications is used to list all applications.
 func (r *Router) listApplications(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv list applications request")
+	tracing.RequestLogger(req).V(3).Info("recv list applications request")
 	runAs := req.PathParameter("runAs")
 
-	blog.Info("request list applications under namespace(%s)", runAs)
+	tracing.RequestLogger(req).Info("request list applications under namespace(%s)", runAs)
 
-	apps, err := r.backend.ListApplications(runAs)
+	apps, err := r.backendOf(req).ListApplications(runAs)
 	if err != nil {
-		blog.Error("request list application under namespace(%s) failed: %s", runAs, err.Error())
+		tracing.RequestLogger(req).Error("request list application under namespace(%s) failed: %s", runAs, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -986,25 +1098,25 @@ func (r *Router) listApplications(req *restful.Request, resp *restful.Response)
 	data := createResponeData(nil, "", apps)
 	resp.Write([]byte(data))
 
-	blog.Info("request list applications under namespcae(%s) end", runAs)
+	tracing.RequestLogger(req).Info("request list applications under namespcae(%s) end", runAs)
 	return
 }
 
 // FetchApplication is used to fetch a application via applicaiton id.
 func (r *Router) fetchApplication(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv fetch application request")
+	tracing.RequestLogger(req).V(3).Info("recv fetch application request")
 	runAs := req.PathParameter("runAs")
 	appId := req.PathParameter("appId")
 
-	blog.Info("request fetch application(%s %s)", runAs, appId)
+	tracing.RequestLogger(req).Info("request fetch application(%s %s)", runAs, appId)
 
-	app, err := r.backend.FetchApplication(runAs, appId)
+	app, err := r.backendOf(req).FetchApplication(runAs, appId)
 	if err != nil {
-		blog.Error("request fetch application(%s %s) failed: %s", runAs, appId, err.Error())
+		tracing.RequestLogger(req).Error("request fetch application(%s %s) failed: %s", runAs, appId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1013,32 +1125,38 @@ func (r *Router) fetchApplication(req *restful.Request, resp *restful.Response)
 	data := createResponeData(nil, "", app)
 	resp.Write([]byte(data))
 
-	blog.Info("request fetch application(%s %s) end", runAs, appId)
+	tracing.RequestLogger(req).Info("request fetch application(%s %s) end", runAs, appId)
 	return
 }
 
 func (r *Router) getApplicationDef(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv get application definition request")
+	tracing.RequestLogger(req).V(3).Info("recv get application definition request")
 	runAs := req.PathParameter("ns")
 	appId := req.PathParameter("name")
 
-	blog.Info("request definition of  application(%s::%s)", runAs, appId)
+	tracing.RequestLogger(req).Info("request definition of  application(%s::%s)", runAs, appId)
 
-	app, err := r.backend.FetchApplication(runAs, appId)
+	app, err := r.backendOf(req).FetchApplication(runAs, appId)
+	if err == zk.ErrNoNode {
+		tracing.RequestLogger(req).Warn("request get definition of application(%s::%s) failed: %s", runAs, appId, err.Error())
+		data := createResponeDataV2(common.BcsErrMesosSchedNotFound, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
 	if err != nil {
-		blog.Error("request get definition of application(%s::%s) failed: %s", runAs, appId, err.Error())
+		tracing.RequestLogger(req).Error("request get definition of application(%s::%s) failed: %s", runAs, appId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
 	if app == nil || app.RawJson == nil {
-		blog.Error("request get definition of application(%s::%s) failed: rawJson is nil ", runAs, appId)
+		tracing.RequestLogger(req).Error("request get definition of application(%s::%s) failed: rawJson is nil ", runAs, appId)
 		err := errors.New("application's definition not exist, maybe the application was created by deployment")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
@@ -1048,7 +1166,7 @@ func (r *Router) getApplicationDef(req *restful.Request, resp *restful.Response)
 	data := createResponeData(nil, "", app.RawJson)
 	resp.Write([]byte(data))
 
-	blog.Info("request get definition of application(%s::%s) end", runAs, appId)
+	tracing.RequestLogger(req).Info("request get definition of application(%s::%s) end", runAs, appId)
 	return
 
 }
@@ -1056,25 +1174,31 @@ func (r *Router) getApplicationDef(req *restful.Request, resp *restful.Response)
 func (r *Router) getDeploymentDef(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv get deployment definition request")
+	tracing.RequestLogger(req).V(3).Info("recv get deployment definition request")
 	runAs := req.PathParameter("ns")
 	deploymentId := req.PathParameter("name")
 
-	blog.Info("request definition of deployment(%s::%s)", runAs, deploymentId)
+	tracing.RequestLogger(req).Info("request definition of deployment(%s::%s)", runAs, deploymentId)
 
-	deployment, err := r.backend.GetDeployment(runAs, deploymentId)
+	deployment, err := r.backendOf(req).GetDeployment(runAs, deploymentId)
+	if err == zk.ErrNoNode {
+		tracing.RequestLogger(req).Warn("request get definition of deployment(%s::%s) failed: %s", runAs, deploymentId, err.Error())
+		data := createResponeDataV2(common.BcsErrMesosSchedNotFound, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
 	if err != nil {
-		blog.Error("request get definition of deployment(%s::%s) failed: %s", runAs, deploymentId, err.Error())
+		tracing.RequestLogger(req).Error("request get definition of deployment(%s::%s) failed: %s", runAs, deploymentId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
 	if deployment == nil || deployment.RawJson == nil {
-		blog.Error("request get definition of deployment(%s::%s) failed: rawJson is nil ", runAs, deploymentId)
+		tracing.RequestLogger(req).Error("request get definition of deployment(%s::%s) failed: rawJson is nil ", runAs, deploymentId)
 		err := errors.New("deployment's definition not exist")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
@@ -1084,17 +1208,65 @@ func (r *Router) getDeploymentDef(req *restful.Request, resp *restful.Response)
 	data := createResponeData(nil, "", deployment.RawJson)
 	resp.Write([]byte(data))
 
-	blog.Info("request get definition of deployment(%s::%s) end", runAs, deploymentId)
+	tracing.RequestLogger(req).Info("request get definition of deployment(%s::%s) end", runAs, deploymentId)
+	return
+}
+
+func (r *Router) getApplicationRecommendation(req *restful.Request, resp *restful.Response) {
+
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+	runAs := req.PathParameter("ns")
+	appId := req.PathParameter("name")
+
+	tracing.RequestLogger(req).V(3).Info("request resource recommendation of application(%s::%s)", runAs, appId)
+
+	recommend, err := r.backendOf(req).GetApplicationRecommendation(runAs, appId)
+	if err != nil {
+		tracing.RequestLogger(req).Error("request recommendation of application(%s::%s) failed: %s", runAs, appId, err.Error())
+		data := createResponeData(err, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	data := createResponeData(nil, "", recommend)
+	resp.Write([]byte(data))
+	return
+}
+
+func (r *Router) getDeploymentRecommendation(req *restful.Request, resp *restful.Response) {
+
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+	runAs := req.PathParameter("ns")
+	deploymentId := req.PathParameter("name")
+
+	tracing.RequestLogger(req).V(3).Info("request resource recommendation of deployment(%s::%s)", runAs, deploymentId)
+
+	recommend, err := r.backendOf(req).GetDeploymentRecommendation(runAs, deploymentId)
+	if err != nil {
+		tracing.RequestLogger(req).Error("request recommendation of deployment(%s::%s) failed: %s", runAs, deploymentId, err.Error())
+		data := createResponeData(err, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	data := createResponeData(nil, "", recommend)
+	resp.Write([]byte(data))
 	return
 }
 
 // DeleteApplication is used to delete a application from mesos and consul via application id.
 func (r *Router) deleteApplication(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv delete application request")
+	tracing.RequestLogger(req).V(3).Info("recv delete application request")
 	runAs := req.PathParameter("runAs")
 	appId := req.PathParameter("appId")
 	kind := commtypes.BcsDataType(req.QueryParameter("kind"))
@@ -1105,11 +1277,11 @@ func (r *Router) deleteApplication(req *restful.Request, resp *restful.Response)
 		enforce = true
 	}
 
-	blog.Info("request delete application(%s %s), enfore:%s", runAs, appId, enforcePara)
+	tracing.RequestLogger(req).Info("request delete application(%s %s), enfore:%s", runAs, appId, enforcePara)
 
 	var data string
-	if err := r.backend.DeleteApplication(runAs, appId, enforce, kind); err != nil {
-		blog.Warn("request delete application (%s %s) failed: %s", runAs, appId, err.Error())
+	if err := r.backendOf(req).DeleteApplication(runAs, appId, enforce, kind); err != nil {
+		tracing.RequestLogger(req).Warn("request delete application (%s %s) failed: %s", runAs, appId, err.Error())
 		if strings.Contains(err.Error(), "node does not exist") {
 			data = createResponeDataV2(common.BcsErrMesosSchedNotFound, common.BcsErrMesosSchedNotFoundStr, nil)
 		} else {
@@ -1122,100 +1294,100 @@ func (r *Router) deleteApplication(req *restful.Request, resp *restful.Response)
 	data = createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request delete application(%s %s) end", runAs, appId)
+	tracing.RequestLogger(req).Info("request delete application(%s %s) end", runAs, appId)
 	return
 }
 
 //ListApplicationTasks is used to list all tasks belong to application via application id.
 func (r *Router) listApplicationTasks(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv list application tasks request")
+	tracing.RequestLogger(req).V(3).Info("recv list application tasks request")
 	runAs := req.PathParameter("runAs")
 	appId := req.PathParameter("appId")
 
-	blog.Info("request list application(%s %s) tasks", runAs, appId)
+	tracing.RequestLogger(req).Info("request list application(%s %s) tasks", runAs, appId)
 
-	tasks, err := r.backend.ListApplicationTasks(runAs, appId)
+	tasks, err := r.backendOf(req).ListApplicationTasks(runAs, appId)
 	if err != nil {
-		blog.Error("request list application tasks (%s %s) failed: %s", runAs, appId, err.Error())
+		tracing.RequestLogger(req).Error("request list application tasks (%s %s) failed: %s", runAs, appId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request list application(%s %s) tasks, return num(%d)", runAs, appId, len(tasks))
+	tracing.RequestLogger(req).Info("request list application(%s %s) tasks, return num(%d)", runAs, appId, len(tasks))
 
 	data := createResponeData(nil, "", tasks)
 	resp.Write([]byte(data))
 
-	blog.Info("request list application(%s %s) tasks end", runAs, appId)
+	tracing.RequestLogger(req).Info("request list application(%s %s) tasks end", runAs, appId)
 
 	return
 }
 
 func (r *Router) listApplicationTaskGroups(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv list application taskgroups request")
+	tracing.RequestLogger(req).V(3).Info("recv list application taskgroups request")
 	runAs := req.PathParameter("runAs")
 	appId := req.PathParameter("appId")
 
-	blog.Info("request list application(%s %s) taskgroups", runAs, appId)
+	tracing.RequestLogger(req).Info("request list application(%s %s) taskgroups", runAs, appId)
 
-	taskGroups, err := r.backend.ListApplicationTaskGroups(runAs, appId)
+	taskGroups, err := r.backendOf(req).ListApplicationTaskGroups(runAs, appId)
 	if err != nil {
-		blog.Error("request list application taskgroups (%s %s) failed: %s", runAs, appId, err.Error())
+		tracing.RequestLogger(req).Error("request list application taskgroups (%s %s) failed: %s", runAs, appId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request list application(%s %s) taskgroups, return num(%d)", runAs, appId, len(taskGroups))
+	tracing.RequestLogger(req).Info("request list application(%s %s) taskgroups, return num(%d)", runAs, appId, len(taskGroups))
 
 	data := createResponeData(nil, "", taskGroups)
 	resp.Write([]byte(data))
-	blog.Info("request list application(%s %s) taskgroups end", runAs, appId)
+	tracing.RequestLogger(req).Info("request list application(%s %s) taskgroups end", runAs, appId)
 	return
 }
 
 // DeleteApplicationTaskGroups is used to delete all tasks belong to application via applicaiton id.
 func (r *Router) deleteApplicationTaskGroups_r(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.Error("receive delete taskgroups request")
+	tracing.RequestLogger(req).Error("receive delete taskgroups request")
 }
 
 // DeleteApplicationTaskGroup is used to delete specified task belong to application via application id and task id.
 func (r *Router) deleteApplicationTaskGroup_r(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.Error("receive delete taskgroup request")
+	tracing.RequestLogger(req).Error("receive delete taskgroup request")
 }
 
 // ListApplicationVersions is used to list all versions for a application specified by applicationId.
 func (r *Router) listApplicationVersions(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv list application versions request")
+	tracing.RequestLogger(req).V(3).Info("recv list application versions request")
 	runAs := req.PathParameter("runAs")
 	appId := req.PathParameter("appId")
 
-	blog.Info("request list application(%s %s) versions", runAs, appId)
+	tracing.RequestLogger(req).Info("request list application(%s %s) versions", runAs, appId)
 
-	appVersions, err := r.backend.ListApplicationVersions(runAs, appId)
+	appVersions, err := r.backendOf(req).ListApplicationVersions(runAs, appId)
 	if err != nil {
-		blog.Error("request list application versions (%s %s) failed: %s", runAs, appId, err.Error())
+		tracing.RequestLogger(req).Error("request list application versions (%s %s) failed: %s", runAs, appId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1223,26 +1395,26 @@ func (r *Router) listApplicationVersions(req *restful.Request, resp *restful.Res
 
 	data := createResponeData(nil, "", appVersions)
 	resp.Write([]byte(data))
-	blog.Info("request list application(%s %s) versions end", runAs, appId)
+	tracing.RequestLogger(req).Info("request list application(%s %s) versions end", runAs, appId)
 	return
 }
 
 // FetchApplicationVersion is used to fetch specified version from consul by version id and application id.
 func (r *Router) fetchApplicationVersion_r(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv fetch application versions request")
+	tracing.RequestLogger(req).V(3).Info("recv fetch application versions request")
 	runAs := req.PathParameter("runAs")
 	appId := req.PathParameter("appId")
 	versionId := req.PathParameter("versionId")
 
-	blog.Info("request fetch application version(%s %s %s)", runAs, appId, versionId)
+	tracing.RequestLogger(req).Info("request fetch application version(%s %s %s)", runAs, appId, versionId)
 
-	version, err := r.backend.FetchApplicationVersion(runAs, appId, versionId)
+	version, err := r.backendOf(req).FetchApplicationVersion(runAs, appId, versionId)
 	if err != nil {
-		blog.Error("request fetch application version (%s %s %s) failed: %s", runAs, appId, versionId, err.Error())
+		tracing.RequestLogger(req).Error("request fetch application version (%s %s %s) failed: %s", runAs, appId, versionId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1250,42 +1422,42 @@ func (r *Router) fetchApplicationVersion_r(req *restful.Request, resp *restful.R
 
 	data := createResponeData(nil, "", version)
 	resp.Write([]byte(data))
-	blog.Info("request fetch application version(%s %s %s) end", runAs, appId, versionId)
+	tracing.RequestLogger(req).Info("request fetch application version(%s %s %s) end", runAs, appId, versionId)
 	return
 }
 
 // UpdateApplication is used to update application version.
 func (r *Router) updateApplication(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("recv update application request")
+	tracing.RequestLogger(req).V(3).Info("recv update application request")
 
 	runAs := req.PathParameter("runAs")
 	appId := req.PathParameter("appId")
 	instances := req.QueryParameter("instances")
 	args := req.QueryParameter("args")
-	blog.Info("request update application(%s.%s): instances(%s), args(%s)", runAs, appId, instances, args)
+	tracing.RequestLogger(req).Info("request update application(%s.%s): instances(%s), args(%s)", runAs, appId, instances, args)
 
 	var version types.Version
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&version); err != nil {
-		blog.Error("request update application(%s.%s) fail to decode version. err:%s", err.Error())
+		tracing.RequestLogger(req).Error("request update application(%s.%s) fail to decode version. err:%s", err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
-	versionErr := r.backend.CheckVersion(&version)
+	versionErr := r.backendOf(req).CheckVersion(&version)
 	if versionErr != nil {
-		blog.Error("update application(%s.%s) version error: %s", version.RunAs, version.ID, versionErr.Error())
+		tracing.RequestLogger(req).Error("update application(%s.%s) version error: %s", version.RunAs, version.ID, versionErr.Error())
 		data := createResponeData(versionErr, versionErr.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
 	if version.RunAs != runAs || version.ID != appId {
-		blog.Error("request update application(%s.%s) version err: version(%s.%s)", runAs, appId, version.RunAs, version.ID)
+		tracing.RequestLogger(req).Error("request update application(%s.%s) version err: version(%s.%s)", runAs, appId, version.RunAs, version.ID)
 		err := errors.New("version RunAs or ID not correct")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
@@ -1295,23 +1467,23 @@ func (r *Router) updateApplication(req *restful.Request, resp *restful.Response)
 	//check the resource, if not, set default
 	err := version.CheckAndDefaultResource()
 	if err != nil {
-		blog.Error("update application (%s.%s) version error: %s", version.RunAs, version.ID, err.Error())
+		tracing.RequestLogger(req).Error("update application (%s.%s) version error: %s", version.RunAs, version.ID, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
 	if version.CheckConstraints() == false {
-		blog.Error("request update application(%s.%s) fail for version constraints error", runAs, appId)
+		tracing.RequestLogger(req).Error("request update application(%s.%s) fail for version constraints error", runAs, appId)
 		err = errors.New("Version Constraints error")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	currVersion, _ := r.backend.GetVersion(runAs, appId)
+	currVersion, _ := r.backendOf(req).GetVersion(runAs, appId)
 	if currVersion == nil {
-		blog.Error("request update application(%s.%s) fail for cannot get curr version", runAs, appId)
+		tracing.RequestLogger(req).Error("request update application(%s.%s) fail for cannot get curr version", runAs, appId)
 		err := errors.New("cannot get old version data")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
@@ -1326,7 +1498,7 @@ func (r *Router) updateApplication(req *restful.Request, resp *restful.Response)
 		currentKind = commtypes.BcsDataType_APP
 	}
 	if currentKind != version.Kind {
-		blog.Errorf("request update application(%s.%s) fail for different kind, current(%s) updated(%s)", runAs, appId, currentKind, version.Kind)
+		tracing.RequestLogger(req).Error("request update application(%s.%s) fail for different kind, current(%s) updated(%s)", runAs, appId, currentKind, version.Kind)
 		err := errors.New("cannot update different kind application")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
@@ -1334,12 +1506,12 @@ func (r *Router) updateApplication(req *restful.Request, resp *restful.Response)
 	}
 
 	if currVersion.Instances != version.Instances {
-		blog.Error("request update application(%s.%s) err for old version instances(%d) != new version instances(%d)",
+		tracing.RequestLogger(req).Error("request update application(%s.%s) err for old version instances(%d) != new version instances(%d)",
 			runAs, appId, currVersion.Instances, version.Instances)
 		version.Instances = currVersion.Instances
-		versionErr := r.backend.CheckVersion(&version)
+		versionErr := r.backendOf(req).CheckVersion(&version)
 		if versionErr != nil {
-			blog.Error("update application (%s %s) version error: %s", version.RunAs, version.ID, versionErr.Error())
+			tracing.RequestLogger(req).Error("update application (%s %s) version error: %s", version.RunAs, version.ID, versionErr.Error())
 			data := createResponeData(versionErr, versionErr.Error(), nil)
 			resp.Write([]byte(data))
 			return
@@ -1349,34 +1521,34 @@ func (r *Router) updateApplication(req *restful.Request, resp *restful.Response)
 	var instanceNum uint64
 	instanceNum, err = strconv.ParseUint(instances, 10, 64)
 	if args == "resource" {
-		blog.Infof("request update application(%s.%s) resource", runAs, appId)
+		tracing.RequestLogger(req).Info("request update application(%s.%s) resource", runAs, appId)
 	} else {
 		if err != nil {
-			blog.Error("request update application(%s.%s) parameter err instances(%s)", runAs, appId, instances)
+			tracing.RequestLogger(req).Error("request update application(%s.%s) parameter err instances(%s)", runAs, appId, instances)
 			err = errors.New("instances must be specified")
 			data := createResponeData(err, err.Error(), nil)
 			resp.Write([]byte(data))
 			return
 		}
-		blog.Infof("request update application(%s.%s), instances(%s)", runAs, appId, instances)
+		tracing.RequestLogger(req).Info("request update application(%s.%s), instances(%s)", runAs, appId, instances)
 	}
 	if instanceNum > uint64(version.Instances) {
-		blog.Error("request update application(%s.%s) err: instances(%d) > version.Instances(%d)", instanceNum, version.Instances)
+		tracing.RequestLogger(req).Error("request update application(%s.%s) err: instances(%d) > version.Instances(%d)", instanceNum, version.Instances)
 		err := errors.New("update instances num is more than version.Instances")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	if err := r.backend.SaveVersion(runAs, appId, &version); err != nil {
-		blog.Error("request update application(%s.%s) fail to save version. err:%s", runAs, appId, err.Error())
+	if err := r.backendOf(req).SaveVersion(runAs, appId, &version); err != nil {
+		tracing.RequestLogger(req).Error("request update application(%s.%s) fail to save version. err:%s", runAs, appId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	if err := r.backend.UpdateApplication(runAs, appId, args, int(instanceNum), &version); err != nil {
-		blog.Error("request update application(%s.%s) err:%s", runAs, appId, err.Error())
+	if err := r.backendOf(req).UpdateApplication(runAs, appId, args, int(instanceNum), &version); err != nil {
+		tracing.RequestLogger(req).Error("request update application(%s.%s) err:%s", runAs, appId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1384,43 +1556,43 @@ func (r *Router) updateApplication(req *restful.Request, resp *restful.Response)
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("request update application(%s.%s) end", runAs, appId)
+	tracing.RequestLogger(req).Info("request update application(%s.%s) end", runAs, appId)
 	return
 }
 
 // ScaleApplication is used to scale application instances.
 func (r *Router) scaleApplication(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive scale application request")
+	tracing.RequestLogger(req).V(3).Info("receive scale application request")
 
 	runAs := req.PathParameter("runAs")
 	appId := req.PathParameter("appId")
 	instances := req.QueryParameter("instances")
 	kind := commtypes.BcsDataType(req.QueryParameter("kind"))
-	blog.Info("request scale application(%s %s) to instances(%s)", runAs, appId, instances)
+	tracing.RequestLogger(req).Info("request scale application(%s %s) to instances(%s)", runAs, appId, instances)
 
 	// limit the target instances
 	instanceNum, err := strconv.ParseUint(instances, 10, 64)
 	if err != nil {
-		blog.Error("request scale application(%s %s): parameter err instances(%s)", instances)
+		tracing.RequestLogger(req).Error("request scale application(%s %s): parameter err instances(%s)", instances)
 		err = errors.New("instances must be specified")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 	if instanceNum <= 0 {
-		blog.Error("request scale application(%s %s): parameter err instances(%s)", instances)
+		tracing.RequestLogger(req).Error("request scale application(%s %s): parameter err instances(%s)", instances)
 		err = errors.New("target instances can not be littler than 1, maybe you want to use delete command")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	if err := r.backend.ScaleApplication(runAs, appId, instanceNum, kind, true); err != nil {
-		blog.Error("request scale application(%s %s) instances(%d) err(%s)", runAs, appId, instanceNum, err.Error())
+	if err := r.backendOf(req).ScaleApplication(runAs, appId, instanceNum, kind, true); err != nil {
+		tracing.RequestLogger(req).Error("request scale application(%s %s) instances(%d) err(%s)", runAs, appId, instanceNum, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1428,28 +1600,28 @@ func (r *Router) scaleApplication(req *restful.Request, resp *restful.Response)
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Infof("request scale application(%s %s) instances(%d) end", runAs, appId, instanceNum)
+	tracing.RequestLogger(req).Info("request scale application(%s %s) instances(%d) end", runAs, appId, instanceNum)
 	return
 }
 
 func (r *Router) sendApplicationCommand(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
 	var command commtypes.BcsCommand
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&command); err != nil {
-		blog.Error("fail to decode json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
 	if command.Spec == nil {
-		blog.Error("command has no spec")
+		tracing.RequestLogger(req).Error("command has no spec")
 		err := errors.New("command has no spec")
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
 		resp.Write([]byte(data))
@@ -1462,7 +1634,7 @@ func (r *Router) sendApplicationCommand(req *restful.Request, resp *restful.Resp
 	ns := command.Spec.CommandTargetRef.Namespace
 	name := command.Spec.CommandTargetRef.Name
 	if kind != "Application" || ns != pathNs || name != pathName {
-		blog.Warn("send application command, data not correct: kind(%s), namespace(%s:%s), name(%s:%s)",
+		tracing.RequestLogger(req).Warn("send application command, data not correct: kind(%s), namespace(%s:%s), name(%s:%s)",
 			kind, pathNs, ns, pathName, name)
 		err := errors.New("request data error")
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
@@ -1471,7 +1643,7 @@ func (r *Router) sendApplicationCommand(req *restful.Request, resp *restful.Resp
 	}
 
 	commandID := kind + "-" + ns + "-" + name + "-" + strconv.FormatInt(time.Now().UnixNano(), 10)
-	blog.Info("send command(%s) to %s:%s.%s", commandID, kind, ns, name)
+	tracing.RequestLogger(req).Info("send command(%s) to %s:%s.%s", commandID, kind, ns, name)
 	commandInfo := commtypes.BcsCommandInfo{
 		Id:         commandID,
 		Spec:       command.Spec,
@@ -1480,8 +1652,8 @@ func (r *Router) sendApplicationCommand(req *restful.Request, resp *restful.Resp
 	}
 
 	//do command
-	if err := r.backend.DoCommand(&commandInfo); err != nil {
-		blog.Error("fail to do command(%s), err:%s", commandID, err.Error())
+	if err := r.backendOf(req).DoCommand(&commandInfo); err != nil {
+		tracing.RequestLogger(req).Error("fail to do command(%s), err:%s", commandID, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1490,22 +1662,22 @@ func (r *Router) sendApplicationCommand(req *restful.Request, resp *restful.Resp
 	data := createResponeData(nil, "success", commandID)
 	resp.Write([]byte(data))
 
-	blog.Info("request send command(%s) end", commandID)
+	tracing.RequestLogger(req).Info("request send command(%s) end", commandID)
 	return
 }
 
 func (r *Router) getApplicationCommand(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
 	id := req.QueryParameter("id")
-	blog.Info("request get command(%s)", id)
-	command, err := r.backend.GetCommand(id)
+	tracing.RequestLogger(req).Info("request get command(%s)", id)
+	command, err := r.backendOf(req).GetCommand(id)
 	if err != nil {
-		blog.Error("request get command(%s) failed: %s", id, err.Error())
+		tracing.RequestLogger(req).Error("request get command(%s) failed: %s", id, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1517,7 +1689,7 @@ func (r *Router) getApplicationCommand(req *restful.Request, resp *restful.Respo
 	ns := command.Spec.CommandTargetRef.Namespace
 	name := command.Spec.CommandTargetRef.Name
 	if kind != "Application" || ns != pathNs || name != pathName {
-		blog.Warn("get application command, data not correct: kind(%s), namespace(%s:%s), name(%s:%s)",
+		tracing.RequestLogger(req).Warn("get application command, data not correct: kind(%s), namespace(%s:%s), name(%s:%s)",
 			kind, pathNs, ns, pathName, name)
 		err := errors.New("request data error")
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
@@ -1528,23 +1700,23 @@ func (r *Router) getApplicationCommand(req *restful.Request, resp *restful.Respo
 	data := createResponeData(nil, "", command)
 	resp.Write([]byte(data))
 
-	blog.Info("request get command(%s) end", id)
+	tracing.RequestLogger(req).Info("request get command(%s) end", id)
 	return
 }
 
 func (r *Router) deleteApplicationCommand(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
 	id := req.QueryParameter("id")
-	blog.Infof("request delete command(%s)", id)
+	tracing.RequestLogger(req).Info("request delete command(%s)", id)
 
 	//should auth the path ns:name with the command data, todo
-	if err := r.backend.DeleteCommand(id); err != nil {
-		blog.Error("fail to delete command(%s), err:%s", id, err.Error())
+	if err := r.backendOf(req).DeleteCommand(id); err != nil {
+		tracing.RequestLogger(req).Error("fail to delete command(%s), err:%s", id, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1552,28 +1724,28 @@ func (r *Router) deleteApplicationCommand(req *restful.Request, resp *restful.Re
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("request delete command(%s) end", id)
+	tracing.RequestLogger(req).Info("request delete command(%s) end", id)
 	return
 }
 
 func (r *Router) sendDeploymentCommand(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
 	var command commtypes.BcsCommand
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&command); err != nil {
-		blog.Error("fail to decode json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
 	if command.Spec == nil {
-		blog.Error("command has no spec")
+		tracing.RequestLogger(req).Error("command has no spec")
 		err := errors.New("command has no spec")
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
 		resp.Write([]byte(data))
@@ -1586,7 +1758,7 @@ func (r *Router) sendDeploymentCommand(req *restful.Request, resp *restful.Respo
 	ns := command.Spec.CommandTargetRef.Namespace
 	name := command.Spec.CommandTargetRef.Name
 	if kind != "Deployment" || ns != pathNs || name != pathName {
-		blog.Warn("send deployment command, data not correct: kind(%s), namespace(%s:%s), name(%s:%s)",
+		tracing.RequestLogger(req).Warn("send deployment command, data not correct: kind(%s), namespace(%s:%s), name(%s:%s)",
 			kind, pathNs, ns, pathName, name)
 		err := errors.New("request data error")
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
@@ -1595,7 +1767,7 @@ func (r *Router) sendDeploymentCommand(req *restful.Request, resp *restful.Respo
 	}
 
 	commandID := kind + "-" + ns + "-" + name + "-" + strconv.FormatInt(time.Now().UnixNano(), 10)
-	blog.Info("send command(%s) to %s:%s.%s", commandID, kind, ns, name)
+	tracing.RequestLogger(req).Info("send command(%s) to %s:%s.%s", commandID, kind, ns, name)
 	commandInfo := commtypes.BcsCommandInfo{
 		Id:         commandID,
 		Spec:       command.Spec,
@@ -1604,8 +1776,8 @@ func (r *Router) sendDeploymentCommand(req *restful.Request, resp *restful.Respo
 	}
 
 	//do command
-	if err := r.backend.DoCommand(&commandInfo); err != nil {
-		blog.Error("fail to do command(%s), err:%s", commandID, err.Error())
+	if err := r.backendOf(req).DoCommand(&commandInfo); err != nil {
+		tracing.RequestLogger(req).Error("fail to do command(%s), err:%s", commandID, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1614,23 +1786,23 @@ func (r *Router) sendDeploymentCommand(req *restful.Request, resp *restful.Respo
 	data := createResponeData(nil, "success", commandID)
 	resp.Write([]byte(data))
 
-	blog.Info("request send command(%s) end", commandID)
+	tracing.RequestLogger(req).Info("request send command(%s) end", commandID)
 	return
 }
 
 func (r *Router) getDeploymentCommand(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
 	id := req.QueryParameter("id")
-	blog.Info("request get command(%s)", id)
+	tracing.RequestLogger(req).Info("request get command(%s)", id)
 
-	command, err := r.backend.GetCommand(id)
+	command, err := r.backendOf(req).GetCommand(id)
 	if err != nil {
-		blog.Error("request get command(%s) failed: %s", id, err.Error())
+		tracing.RequestLogger(req).Error("request get command(%s) failed: %s", id, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1642,7 +1814,7 @@ func (r *Router) getDeploymentCommand(req *restful.Request, resp *restful.Respon
 	ns := command.Spec.CommandTargetRef.Namespace
 	name := command.Spec.CommandTargetRef.Name
 	if kind != "Deployment" || ns != pathNs || name != pathName {
-		blog.Warn("get deployment command, data not correct: kind(%s), namespace(%s:%s), name(%s:%s)",
+		tracing.RequestLogger(req).Warn("get deployment command, data not correct: kind(%s), namespace(%s:%s), name(%s:%s)",
 			kind, pathNs, ns, pathName, name)
 		err := errors.New("request data error")
 		data := createResponeDataV2(comm.BcsErrCommRequestDataErr, err.Error(), nil)
@@ -1653,24 +1825,24 @@ func (r *Router) getDeploymentCommand(req *restful.Request, resp *restful.Respon
 	data := createResponeData(nil, "", command)
 	resp.Write([]byte(data))
 
-	blog.Info("request get command(%s) end", id)
+	tracing.RequestLogger(req).Info("request get command(%s) end", id)
 	return
 }
 
 func (r *Router) deleteDeploymentCommand(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 
 	id := req.QueryParameter("id")
-	blog.Infof("request delete command(%s)", id)
+	tracing.RequestLogger(req).Info("request delete command(%s)", id)
 
 	//should auth the path ns:name with the command data, todo
 
-	if err := r.backend.DeleteCommand(id); err != nil {
-		blog.Error("fail to delete command(%s), err:%s", id, err.Error())
+	if err := r.backendOf(req).DeleteCommand(id); err != nil {
+		tracing.RequestLogger(req).Error("fail to delete command(%s), err:%s", id, err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1678,42 +1850,42 @@ func (r *Router) deleteDeploymentCommand(req *restful.Request, resp *restful.Res
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("request delete command(%s) end", id)
+	tracing.RequestLogger(req).Info("request delete command(%s) end", id)
 	return
 }
 
 //SendMessageApplication send msg to application
 func (r *Router) sendMessageApplication(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive send message to application request")
+	tracing.RequestLogger(req).V(3).Info("receive send message to application request")
 
 	runAs := req.PathParameter("runAs")
 	appId := req.PathParameter("appId")
 
-	blog.Info("request send message to application(%s %s)", runAs, appId)
+	tracing.RequestLogger(req).Info("request send message to application(%s %s)", runAs, appId)
 
 	var msg types.BcsMessage
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&msg); err != nil {
-		blog.Error("request send message to application(%s %s): fail to Decode message json, err:%s", runAs, appId, err.Error())
+		tracing.RequestLogger(req).Error("request send message to application(%s %s): fail to Decode message json, err:%s", runAs, appId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	_, fail, err := r.backend.SendToApplication(runAs, appId, &msg)
+	_, fail, err := r.backendOf(req).SendToApplication(runAs, appId, &msg)
 	if err != nil {
-		blog.Error("request send message to application(%s %s): fail to send message, err:%s", runAs, appId, err.Error())
+		tracing.RequestLogger(req).Error("request send message to application(%s %s): fail to send message, err:%s", runAs, appId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
 	if len(fail) != 0 {
-		blog.Error("request send message to application(%s %s): fail count: %d", runAs, appId, len(fail))
+		tracing.RequestLogger(req).Error("request send message to application(%s %s): fail count: %d", runAs, appId, len(fail))
 		data := createResponeData(nil, "success", fail)
 		resp.Write([]byte(data))
 		return
@@ -1721,35 +1893,35 @@ func (r *Router) sendMessageApplication(req *restful.Request, resp *restful.Resp
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("request send message to application(%s %s) end", runAs, appId)
+	tracing.RequestLogger(req).Info("request send message to application(%s %s) end", runAs, appId)
 	return
 }
 
 //SendMessageApplicationTaskGroup send msg to the specified taskgroup
 func (r *Router) sendMessageApplicationTaskGroup(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive send message to taskgroup request")
+	tracing.RequestLogger(req).V(3).Info("receive send message to taskgroup request")
 
 	runAs := req.PathParameter("runAs")
 	appId := req.PathParameter("appId")
 	taskgroupId := req.PathParameter("taskgroupId")
 
-	blog.Info("request send message to taskgroup(%s)", taskgroupId)
+	tracing.RequestLogger(req).Info("request send message to taskgroup(%s)", taskgroupId)
 
 	var msg types.BcsMessage
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&msg); err != nil {
-		blog.Error("request send message to taskgroup(%s): fail to Decode json, err:%s", taskgroupId, err.Error())
+		tracing.RequestLogger(req).Error("request send message to taskgroup(%s): fail to Decode json, err:%s", taskgroupId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	if err := r.backend.SendToApplicationTaskGroup(runAs, appId, taskgroupId, &msg); err != nil {
-		blog.Error("request send message to taskgroup(%s): fail to send message, err:%s", taskgroupId, err.Error())
+	if err := r.backendOf(req).SendToApplicationTaskGroup(runAs, appId, taskgroupId, &msg); err != nil {
+		tracing.RequestLogger(req).Error("request send message to taskgroup(%s): fail to send message, err:%s", taskgroupId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1757,7 +1929,7 @@ func (r *Router) sendMessageApplicationTaskGroup(req *restful.Request, resp *res
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("request send message to taskgroup(%s) end", taskgroupId)
+	tracing.RequestLogger(req).Info("request send message to taskgroup(%s) end", taskgroupId)
 	return
 }
 
@@ -1791,10 +1963,10 @@ func createResponeDataV2(errCode int, msg string, data interface{}) string {
 // RescheduleTaskgroup is used to rescheduler taskgroup.
 func (r *Router) reschedulerTaskgroup(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive rescheduler taskgroup request")
+	tracing.RequestLogger(req).V(3).Info("receive rescheduler taskgroup request")
 
 	taskgroupId := req.PathParameter("taskgroupId")
 
@@ -1804,10 +1976,10 @@ func (r *Router) reschedulerTaskgroup(req *restful.Request, resp *restful.Respon
 		hostRetainTime, _ = strconv.ParseInt(hostRetainTimeStr, 10, 64)
 	}
 
-	blog.Info("request rescheduler taskgroup(%s) hostRetainTime(%d)", taskgroupId, hostRetainTime)
+	tracing.RequestLogger(req).Info("request rescheduler taskgroup(%s) hostRetainTime(%d)", taskgroupId, hostRetainTime)
 
-	if err := r.backend.RescheduleTaskgroup(taskgroupId, hostRetainTime); err != nil {
-		blog.Error("request rescheduler taskgroup(%s) err(%s)", taskgroupId, err.Error())
+	if err := r.backendOf(req).RescheduleTaskgroup(taskgroupId, hostRetainTime); err != nil {
+		tracing.RequestLogger(req).Error("request rescheduler taskgroup(%s) err(%s)", taskgroupId, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1815,42 +1987,42 @@ func (r *Router) reschedulerTaskgroup(req *restful.Request, resp *restful.Respon
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Infof("request rescheduler taskgroup(%s) end", taskgroupId)
+	tracing.RequestLogger(req).Info("request rescheduler taskgroup(%s) end", taskgroupId)
 	return
 }
 
 // ScaleDeployment is used to scale deployment instances.
 func (r *Router) scaleDeployment_r(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive scale deployment request")
+	tracing.RequestLogger(req).V(3).Info("receive scale deployment request")
 
 	runAs := req.PathParameter("namespace")
 	name := req.PathParameter("name")
 	instances := req.PathParameter("instances")
-	blog.Info("request scale deployment(%s %s) to instances(%s)", runAs, name, instances)
+	tracing.RequestLogger(req).Info("request scale deployment(%s %s) to instances(%s)", runAs, name, instances)
 
 	// limit the target instances
 	instanceNum, err := strconv.ParseUint(instances, 10, 64)
 	if err != nil {
-		blog.Error("request scale deployment(%s %s): parameter err instances(%s)", runAs, name, instances)
+		tracing.RequestLogger(req).Error("request scale deployment(%s %s): parameter err instances(%s)", runAs, name, instances)
 		err = errors.New("instances must be specified")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 	if instanceNum <= 0 {
-		blog.Error("request scale deployment(%s %s): parameter err instances(%s)", instances)
+		tracing.RequestLogger(req).Error("request scale deployment(%s %s): parameter err instances(%s)", instances)
 		err = errors.New("target instances can not be littler than 1, maybe you want to use delete command")
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	if err := r.backend.ScaleDeployment(runAs, name, instanceNum); err != nil {
-		blog.Error("request scale deployment(%s %s) instances(%d) err(%s)", runAs, name, instanceNum, err.Error())
+	if err := r.backendOf(req).ScaleDeployment(runAs, name, instanceNum); err != nil {
+		tracing.RequestLogger(req).Error("request scale deployment(%s %s) instances(%d) err(%s)", runAs, name, instanceNum, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1858,28 +2030,28 @@ func (r *Router) scaleDeployment_r(req *restful.Request, resp *restful.Response)
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Infof("request scale deployment(%s %s) instances(%d) end", runAs, name, instanceNum)
+	tracing.RequestLogger(req).Info("request scale deployment(%s %s) instances(%d) end", runAs, name, instanceNum)
 	return
 }
 
 func (r *Router) registerCustomResource(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive register custom resource request")
+	tracing.RequestLogger(req).V(3).Info("receive register custom resource request")
 
 	var crr *commtypes.Crr
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&crr); err != nil {
-		blog.Error("request register custom resource fail to decode crr. err:%s", err.Error())
+		tracing.RequestLogger(req).Error("request register custom resource fail to decode crr. err:%s", err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	if err := r.backend.RegisterCustomResource(crr); err != nil {
-		blog.Error("request register custom resource(%s) err(%s)", crr.Spec.Names.Kind, err.Error())
+	if err := r.backendOf(req).RegisterCustomResource(crr); err != nil {
+		tracing.RequestLogger(req).Error("request register custom resource(%s) err(%s)", crr.Spec.Names.Kind, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1887,28 +2059,34 @@ func (r *Router) registerCustomResource(req *restful.Request, resp *restful.Resp
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Infof("request register custom resource(%s)end", crr.Spec.Names.Kind)
+	tracing.RequestLogger(req).Info("request register custom resource(%s)end", crr.Spec.Names.Kind)
 	return
 }
 
 func (r *Router) createCustomResource(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive create custom resource request")
+	tracing.RequestLogger(req).V(3).Info("receive create custom resource request")
 
 	var crd *commtypes.Crd
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&crd); err != nil {
-		blog.Error("request create custom resource fail to decode Crd. err:%s", err.Error())
+		tracing.RequestLogger(req).Error("request create custom resource fail to decode Crd. err:%s", err.Error())
+		data := createResponeData(err, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+	if err := checkCustomResourcePath(req, crd); err != nil {
+		tracing.RequestLogger(req).Error("request create custom resource(%s %s %s) err(%s)", crd.Kind, crd.NameSpace, crd.Name, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	if err := r.backend.CreateCustomResource(crd); err != nil {
-		blog.Error("request create custom resource(%s %s %s) err(%s)", crd.Kind, crd.NameSpace, crd.Name, err.Error())
+	if err := r.backendOf(req).CreateCustomResource(crd); err != nil {
+		tracing.RequestLogger(req).Error("request create custom resource(%s %s %s) err(%s)", crd.Kind, crd.NameSpace, crd.Name, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1916,28 +2094,34 @@ func (r *Router) createCustomResource(req *restful.Request, resp *restful.Respon
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Infof("request create custom resource(%s %s %s)end", crd.Kind, crd.NameSpace, crd.Name)
+	tracing.RequestLogger(req).Info("request create custom resource(%s %s %s)end", crd.Kind, crd.NameSpace, crd.Name)
 	return
 }
 
 func (r *Router) updateCustomResource(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive update custom resource request")
+	tracing.RequestLogger(req).V(3).Info("receive update custom resource request")
 
 	var crd *commtypes.Crd
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&crd); err != nil {
-		blog.Error("request update custom resource fail to decode Crd. err:%s", err.Error())
+		tracing.RequestLogger(req).Error("request update custom resource fail to decode Crd. err:%s", err.Error())
+		data := createResponeData(err, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+	if err := checkCustomResourcePath(req, crd); err != nil {
+		tracing.RequestLogger(req).Error("request update custom resource(%s %s %s) err(%s)", crd.Kind, crd.NameSpace, crd.Name, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	if err := r.backend.UpdateCustomResource(crd); err != nil {
-		blog.Error("request update custom resource(%s %s %s) err(%s)", crd.Kind, crd.NameSpace, crd.Name, err.Error())
+	if err := r.backendOf(req).UpdateCustomResource(crd); err != nil {
+		tracing.RequestLogger(req).Error("request update custom resource(%s %s %s) err(%s)", crd.Kind, crd.NameSpace, crd.Name, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1945,24 +2129,38 @@ func (r *Router) updateCustomResource(req *restful.Request, resp *restful.Respon
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Infof("request update custom resource(%s %s %s)end", crd.Kind, crd.NameSpace, crd.Name)
+	tracing.RequestLogger(req).Info("request update custom resource(%s %s %s)end", crd.Kind, crd.NameSpace, crd.Name)
 	return
 }
 
+//checkCustomResourcePath check namespace and kind in url path are consistent with custom resource
+func checkCustomResourcePath(req *restful.Request, crd *commtypes.Crd) error {
+	ns := req.PathParameter("ns")
+	kind := req.PathParameter("kind")
+	if crd.NameSpace != "" && crd.NameSpace != ns {
+		return fmt.Errorf("custom resource namespace %s is not consistent with url namespace %s", crd.NameSpace, ns)
+	}
+	if kind != "" && string(crd.Kind) != kind {
+		return fmt.Errorf("custom resource kind %s is not consistent with url kind %s", crd.Kind, kind)
+	}
+
+	return nil
+}
+
 func (r *Router) deleteCustomResource(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive delete custom resource request")
+	tracing.RequestLogger(req).V(3).Info("receive delete custom resource request")
 
 	kind := req.PathParameter("kind")
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
 
 	var data string
-	if err := r.backend.DeleteCustomResource(kind, ns, name); err != nil {
-		blog.Error("request delete custom resource(%s %s %s) err(%s)", kind, ns, name, err.Error())
+	if err := r.backendOf(req).DeleteCustomResource(kind, ns, name); err != nil {
+		tracing.RequestLogger(req).Error("request delete custom resource(%s %s %s) err(%s)", kind, ns, name, err.Error())
 		if strings.Contains(err.Error(), "node does not exist") {
 			data = createResponeDataV2(common.BcsErrMesosSchedNotFound, common.BcsErrMesosSchedNotFoundStr, nil)
 		} else {
@@ -1974,22 +2172,22 @@ func (r *Router) deleteCustomResource(req *restful.Request, resp *restful.Respon
 
 	data = createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Infof("request delete custom resource(%s %s %s)end", kind, ns, name)
+	tracing.RequestLogger(req).Info("request delete custom resource(%s %s %s)end", kind, ns, name)
 	return
 }
 
 func (r *Router) listCustomResource(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	kind := req.PathParameter("kind")
 	ns := req.PathParameter("ns")
-	blog.V(3).Infof("receive list custom resource request kind %s namespace %s", kind, ns)
+	tracing.RequestLogger(req).V(3).Info("receive list custom resource request kind %s namespace %s", kind, ns)
 
-	crds, err := r.backend.ListCustomResourceDefinition(kind, ns)
+	crds, err := r.backendOf(req).ListCustomResourceDefinition(kind, ns)
 	if err != nil {
-		blog.Error("request list custom resource(%s %s) err(%s)", kind, ns, err.Error())
+		tracing.RequestLogger(req).Error("request list custom resource(%s %s) err(%s)", kind, ns, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -1997,23 +2195,24 @@ func (r *Router) listCustomResource(req *restful.Request, resp *restful.Response
 
 	data := createResponeData(nil, "success", crds)
 	resp.Write([]byte(data))
-	blog.V(3).Infof("request list custom resource(%s %s)end", kind, ns)
+	tracing.RequestLogger(req).V(3).Info("request list custom resource(%s %s)end", kind, ns)
 	return
 }
 
 func (r *Router) getCustomResource(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	kind := req.PathParameter("kind")
 	ns := req.PathParameter("ns")
 	name := req.PathParameter("name")
-	blog.V(3).Infof("receive get custom resource request kind %s namespace %s name %s", kind, ns, name)
+	tracing.RequestLogger(req).V(3).Info("receive get custom resource request kind %s namespace %s name %s", kind, ns, name)
 
-	crd, err := r.backend.FetchCustomResourceDefinition(kind, ns, name)
+	version := req.QueryParameter("version")
+	crd, err := r.backendOf(req).FetchCustomResourceDefinitionVersion(kind, ns, name, version)
 	if err != nil {
-		blog.Error("request get custom resource(%s %s %s) err(%s)", kind, ns, name, err.Error())
+		tracing.RequestLogger(req).Error("request get custom resource(%s %s %s) err(%s)", kind, ns, name, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -2021,17 +2220,17 @@ func (r *Router) getCustomResource(req *restful.Request, resp *restful.Response)
 
 	data := createResponeData(nil, "success", crd)
 	resp.Write([]byte(data))
-	blog.V(3).Infof("request get custom resource(%s %s %s)end", kind, ns, name)
+	tracing.RequestLogger(req).V(3).Info("request get custom resource(%s %s %s)end", kind, ns, name)
 	return
 }
 
 func (r *Router) commitImage(req *restful.Request, resp *restful.Response) {
 
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive commit image request")
+	tracing.RequestLogger(req).V(3).Info("receive commit image request")
 
 	taskgroup := req.PathParameter("taskgroup")
 	image := req.QueryParameter("image")
@@ -2039,8 +2238,8 @@ func (r *Router) commitImage(req *restful.Request, resp *restful.Response) {
 
 	var msg *types.BcsMessage
 	var err error
-	if msg, err = r.backend.CommitImage(taskgroup, image, url); err != nil {
-		blog.Error("request commit image(%s %s %s) err(%s)", taskgroup, image, url, err.Error())
+	if msg, err = r.backendOf(req).CommitImage(taskgroup, image, url); err != nil {
+		tracing.RequestLogger(req).Error("request commit image(%s %s %s) err(%s)", taskgroup, image, url, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -2048,24 +2247,24 @@ func (r *Router) commitImage(req *restful.Request, resp *restful.Response) {
 
 	data := createResponeData(nil, "success", msg)
 	resp.Write([]byte(data))
-	blog.Error("request commit image(%s %s %s)end", taskgroup, image, url)
+	tracing.RequestLogger(req).Error("request commit image(%s %s %s)end", taskgroup, image, url)
 	return
 }
 
 func (r *Router) restartTaskGroup(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive restart taskGroup request")
+	tracing.RequestLogger(req).V(3).Info("receive restart taskGroup request")
 
 	taskGroupID := req.PathParameter("taskGroupID")
 
-	blog.Info("request restart taskGroup(%s)", taskGroupID)
+	tracing.RequestLogger(req).Info("request restart taskGroup(%s)", taskGroupID)
 
-	msg, err := r.backend.RestartTaskGroup(taskGroupID)
+	msg, err := r.backendOf(req).RestartTaskGroup(taskGroupID)
 	if err != nil {
-		blog.Error("request restart taskGroup(%s) err(%s)", taskGroupID, err.Error())
+		tracing.RequestLogger(req).Error("request restart taskGroup(%s) err(%s)", taskGroupID, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -2073,24 +2272,24 @@ func (r *Router) restartTaskGroup(req *restful.Request, resp *restful.Response)
 
 	data := createResponeData(nil, "success", msg)
 	resp.Write([]byte(data))
-	blog.Infof("request restart taskGroup(%s) end", taskGroupID)
+	tracing.RequestLogger(req).Info("request restart taskGroup(%s) end", taskGroupID)
 	return
 }
 
 func (r *Router) reloadTaskGroup(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("receive reload taskGroup request")
+	tracing.RequestLogger(req).V(3).Info("receive reload taskGroup request")
 
 	taskGroupID := req.PathParameter("taskGroupID")
 
-	blog.Info("request reload taskGroup(%s)", taskGroupID)
+	tracing.RequestLogger(req).Info("request reload taskGroup(%s)", taskGroupID)
 
-	msg, err := r.backend.ReloadTaskGroup(taskGroupID)
+	msg, err := r.backendOf(req).ReloadTaskGroup(taskGroupID)
 	if err != nil {
-		blog.Error("request reload taskGroup(%s) err(%s)", taskGroupID, err.Error())
+		tracing.RequestLogger(req).Error("request reload taskGroup(%s) err(%s)", taskGroupID, err.Error())
 		data := createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -2098,27 +2297,27 @@ func (r *Router) reloadTaskGroup(req *restful.Request, resp *restful.Response) {
 
 	data := createResponeData(nil, "success", msg)
 	resp.Write([]byte(data))
-	blog.Infof("request reload taskGroup(%s) end", taskGroupID)
+	tracing.RequestLogger(req).Info("request reload taskGroup(%s) end", taskGroupID)
 	return
 }
 
 func (r *Router) createAdmissionwebhook(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	var admission commtypes.AdmissionWebhookConfiguration
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&admission); err != nil {
-		blog.Error("fail to decode admission json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode admission json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request create admission(%s.%s):%+v", admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name, admission)
+	tracing.RequestLogger(req).Info("request create admission(%s.%s):%+v", admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name, admission)
 
-	currData, _ := r.backend.FetchAdmissionWebhook(admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name)
+	currData, _ := r.backendOf(req).FetchAdmissionWebhook(admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name)
 	if currData != nil {
 		err := errors.New("admission already exist")
 		data := createResponeDataV2(comm.BcsErrMesosSchedResourceExist, err.Error(), nil)
@@ -2126,8 +2325,8 @@ func (r *Router) createAdmissionwebhook(req *restful.Request, resp *restful.Resp
 		return
 	}
 
-	if err := r.backend.SaveAdmissionWebhook(&admission); err != nil {
-		blog.Error("fail to save admission, err:%s", err.Error())
+	if err := r.backendOf(req).SaveAdmissionWebhook(&admission); err != nil {
+		tracing.RequestLogger(req).Error("fail to save admission, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -2136,28 +2335,28 @@ func (r *Router) createAdmissionwebhook(req *restful.Request, resp *restful.Resp
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request create admission(%s.%s) end", admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name)
+	tracing.RequestLogger(req).Info("request create admission(%s.%s) end", admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name)
 
 	return
 }
 
 func (r *Router) updateAdmissionwebhook(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	var admission commtypes.AdmissionWebhookConfiguration
 	decoder := json.NewDecoder(req.Request.Body)
 	if err := decoder.Decode(&admission); err != nil {
-		blog.Error("fail to decode admission json, err:%s", err.Error())
+		tracing.RequestLogger(req).Error("fail to decode admission json, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
 	}
 
-	blog.Info("request update admission(%s.%s): %+v", admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name, admission)
+	tracing.RequestLogger(req).Info("request update admission(%s.%s): %+v", admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name, admission)
 
-	currData, _ := r.backend.FetchAdmissionWebhook(admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name)
+	currData, _ := r.backendOf(req).FetchAdmissionWebhook(admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name)
 	if currData == nil {
 		err := errors.New("admission not exist")
 		data := createResponeDataV2(comm.BcsErrMesosSchedNotFound, err.Error(), nil)
@@ -2169,8 +2368,8 @@ func (r *Router) updateAdmissionwebhook(req *restful.Request, resp *restful.Resp
 	admission.ObjectMeta = currData.ObjectMeta
 	admission.TypeMeta = currData.TypeMeta
 
-	if err := r.backend.UpdateAdmissionWebhook(&admission); err != nil {
-		blog.Error("fail to save admission, err:%s", err.Error())
+	if err := r.backendOf(req).UpdateAdmissionWebhook(&admission); err != nil {
+		tracing.RequestLogger(req).Error("fail to save admission, err:%s", err.Error())
 		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -2178,22 +2377,22 @@ func (r *Router) updateAdmissionwebhook(req *restful.Request, resp *restful.Resp
 
 	data := createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
-	blog.Info("request update admission(%s.%s) end", admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name)
+	tracing.RequestLogger(req).Info("request update admission(%s.%s) end", admission.ObjectMeta.NameSpace, admission.ObjectMeta.Name)
 	return
 }
 
 func (r *Router) deleteAdmissionwebhook(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	ns := req.PathParameter("namespace")
 	name := req.PathParameter("name")
-	blog.V(3).Infof("request delete admission(%s.%s)", ns, name)
+	tracing.RequestLogger(req).V(3).Info("request delete admission(%s.%s)", ns, name)
 
 	var data string
-	if err := r.backend.DeleteAdmissionWebhook(ns, name); err != nil {
-		blog.Error("fail to delete admission, err:%s", err.Error())
+	if err := r.backendOf(req).DeleteAdmissionWebhook(ns, name); err != nil {
+		tracing.RequestLogger(req).Error("fail to delete admission, err:%s", err.Error())
 		if strings.Contains(err.Error(), "node does not exist") {
 			data = createResponeDataV2(common.BcsErrMesosSchedNotFound, err.Error(), nil)
 		} else {
@@ -2206,21 +2405,21 @@ func (r *Router) deleteAdmissionwebhook(req *restful.Request, resp *restful.Resp
 	data = createResponeData(nil, "success", nil)
 	resp.Write([]byte(data))
 
-	blog.Info("request delete admission(%s.%s) end", ns, name)
+	tracing.RequestLogger(req).Info("request delete admission(%s.%s) end", ns, name)
 	return
 }
 
 func (r *Router) fetchAllAdmissionwebhooks(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
-	blog.V(3).Infof("request fetch all admissions")
+	tracing.RequestLogger(req).V(3).Info("request fetch all admissions")
 
 	var data string
-	admissions, err := r.backend.FetchAllAdmissionWebhooks()
+	admissions, err := r.backendOf(req).FetchAllAdmissionWebhooks()
 	if err != nil {
-		blog.Error("request list all admissions err(%s)", err.Error())
+		tracing.RequestLogger(req).Error("request list all admissions err(%s)", err.Error())
 		data = createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -2229,23 +2428,23 @@ func (r *Router) fetchAllAdmissionwebhooks(req *restful.Request, resp *restful.R
 	data = createResponeData(nil, "success", admissions)
 	resp.Write([]byte(data))
 
-	blog.Info("request list all admissions end")
+	tracing.RequestLogger(req).Info("request list all admissions end")
 	return
 }
 
 func (r *Router) fetchAdmissionwebhook(req *restful.Request, resp *restful.Response) {
 	if r.backend.GetRole() != "master" {
-		blog.Warn("scheduler is not master, can not process cmd")
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
 		return
 	}
 	ns := req.PathParameter("namespace")
 	name := req.PathParameter("name")
-	blog.V(3).Infof("request fetch admission(%s:%s)", ns, name)
+	tracing.RequestLogger(req).V(3).Info("request fetch admission(%s:%s)", ns, name)
 
 	var data string
-	admission, err := r.backend.FetchAdmissionWebhook(ns, name)
+	admission, err := r.backendOf(req).FetchAdmissionWebhook(ns, name)
 	if err != nil {
-		blog.Error("request list all admissions err(%s)", err.Error())
+		tracing.RequestLogger(req).Error("request list all admissions err(%s)", err.Error())
 		data = createResponeData(err, err.Error(), nil)
 		resp.Write([]byte(data))
 		return
@@ -2254,6 +2453,154 @@ func (r *Router) fetchAdmissionwebhook(req *restful.Request, resp *restful.Respo
 	data = createResponeData(nil, "success", admission)
 	resp.Write([]byte(data))
 
-	blog.Info("request list all admissions end")
+	tracing.RequestLogger(req).Info("request list all admissions end")
+	return
+}
+
+func (r *Router) createDisruptionBudget(req *restful.Request, resp *restful.Response) {
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+	var budget commtypes.BcsDisruptionBudget
+	decoder := json.NewDecoder(req.Request.Body)
+	if err := decoder.Decode(&budget); err != nil {
+		tracing.RequestLogger(req).Error("fail to decode disruption budget json, err:%s", err.Error())
+		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	tracing.RequestLogger(req).Info("request create disruption budget(%s.%s)", budget.ObjectMeta.NameSpace, budget.ObjectMeta.Name)
+
+	currData, _ := r.backendOf(req).FetchDisruptionBudget(budget.ObjectMeta.NameSpace, budget.ObjectMeta.Name)
+	if currData != nil {
+		err := errors.New("disruption budget already exist")
+		data := createResponeDataV2(comm.BcsErrMesosSchedResourceExist, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	if err := r.backendOf(req).SaveDisruptionBudget(&budget); err != nil {
+		tracing.RequestLogger(req).Error("fail to save disruption budget, err:%s", err.Error())
+		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	data := createResponeData(nil, "success", nil)
+	resp.Write([]byte(data))
+
+	tracing.RequestLogger(req).Info("request create disruption budget(%s.%s) end", budget.ObjectMeta.NameSpace, budget.ObjectMeta.Name)
+	return
+}
+
+func (r *Router) updateDisruptionBudget(req *restful.Request, resp *restful.Response) {
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+	var budget commtypes.BcsDisruptionBudget
+	decoder := json.NewDecoder(req.Request.Body)
+	if err := decoder.Decode(&budget); err != nil {
+		tracing.RequestLogger(req).Error("fail to decode disruption budget json, err:%s", err.Error())
+		data := createResponeDataV2(comm.BcsErrCommJsonDecode, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	tracing.RequestLogger(req).Info("request update disruption budget(%s.%s)", budget.ObjectMeta.NameSpace, budget.ObjectMeta.Name)
+
+	currData, _ := r.backendOf(req).FetchDisruptionBudget(budget.ObjectMeta.NameSpace, budget.ObjectMeta.Name)
+	if currData == nil {
+		err := errors.New("disruption budget not exist")
+		data := createResponeDataV2(comm.BcsErrMesosSchedNotFound, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	if err := r.backendOf(req).SaveDisruptionBudget(&budget); err != nil {
+		tracing.RequestLogger(req).Error("fail to save disruption budget, err:%s", err.Error())
+		data := createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	data := createResponeData(nil, "success", nil)
+	resp.Write([]byte(data))
+	tracing.RequestLogger(req).Info("request update disruption budget(%s.%s) end", budget.ObjectMeta.NameSpace, budget.ObjectMeta.Name)
+	return
+}
+
+func (r *Router) deleteDisruptionBudget(req *restful.Request, resp *restful.Response) {
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+	ns := req.PathParameter("namespace")
+	name := req.PathParameter("name")
+	tracing.RequestLogger(req).Info("request delete disruption budget(%s.%s)", ns, name)
+
+	var data string
+	if err := r.backendOf(req).DeleteDisruptionBudget(ns, name); err != nil {
+		tracing.RequestLogger(req).Error("fail to delete disruption budget, err:%s", err.Error())
+		if strings.Contains(err.Error(), "node does not exist") {
+			data = createResponeDataV2(common.BcsErrMesosSchedNotFound, err.Error(), nil)
+		} else {
+			data = createResponeDataV2(comm.BcsErrMesosSchedCommon, err.Error(), nil)
+		}
+		resp.Write([]byte(data))
+		return
+	}
+
+	data = createResponeData(nil, "success", nil)
+	resp.Write([]byte(data))
+
+	tracing.RequestLogger(req).Info("request delete disruption budget(%s.%s) end", ns, name)
+	return
+}
+
+func (r *Router) fetchDisruptionBudget(req *restful.Request, resp *restful.Response) {
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+	ns := req.PathParameter("namespace")
+	name := req.PathParameter("name")
+	tracing.RequestLogger(req).V(3).Info("request fetch disruption budget(%s:%s)", ns, name)
+
+	var data string
+	budget, err := r.backendOf(req).FetchDisruptionBudget(ns, name)
+	if err != nil {
+		tracing.RequestLogger(req).Error("request fetch disruption budget(%s:%s) err(%s)", ns, name, err.Error())
+		data = createResponeData(err, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	data = createResponeData(nil, "success", budget)
+	resp.Write([]byte(data))
+	return
+}
+
+func (r *Router) listDisruptionBudgets(req *restful.Request, resp *restful.Response) {
+	if r.backend.GetRole() != "master" {
+		tracing.RequestLogger(req).Warn("scheduler is not master, can not process cmd")
+		return
+	}
+	ns := req.PathParameter("namespace")
+	tracing.RequestLogger(req).V(3).Info("request list disruption budgets(%s)", ns)
+
+	var data string
+	budgets, err := r.backendOf(req).ListDisruptionBudgets(ns)
+	if err != nil {
+		tracing.RequestLogger(req).Error("request list disruption budgets(%s) err(%s)", ns, err.Error())
+		data = createResponeData(err, err.Error(), nil)
+		resp.Write([]byte(data))
+		return
+	}
+
+	data = createResponeData(nil, "success", budgets)
+	resp.Write([]byte(data))
 	return
 }
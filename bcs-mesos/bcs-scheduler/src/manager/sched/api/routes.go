@@ -29,6 +29,8 @@ package api
 import (
 	"bk-bcs/bcs-common/common/http/httpserver"
 	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/sched/backend"
+
+	"github.com/emicklei/go-restful"
 )
 
 type Router struct {
@@ -52,6 +54,11 @@ func (r *Router) GetActions() []*httpserver.Action {
 	return r.actions
 }
 
+//backend bound to the request, store operations of backend join the trace of request
+func (r *Router) backendOf(req *restful.Request) backend.Backend {
+	return r.backend.WithContext(req.Request.Context())
+}
+
 func (r *Router) initRoutes() {
 	/*-------------- application ---------------*/
 	r.actions = append(r.actions, httpserver.NewAction("POST", "/apps", nil, r.buildApplication))
@@ -113,6 +120,7 @@ func (r *Router) initRoutes() {
 	r.actions = append(r.actions, httpserver.NewAction("POST", "/deployment/{namespace}/{name}/cancelupdate", nil, r.cancelUpdateDeployment))
 	r.actions = append(r.actions, httpserver.NewAction("POST", "/deployment/{namespace}/{name}/pauseupdate", nil, r.pauseUpdateDeployment))
 	r.actions = append(r.actions, httpserver.NewAction("POST", "/deployment/{namespace}/{name}/resumeupdate", nil, r.resumeUpdateDeployment))
+	r.actions = append(r.actions, httpserver.NewAction("GET", "/deployment/{namespace}/{name}", nil, r.fetchDeployment))
 	r.actions = append(r.actions, httpserver.NewAction("DELETE", "/deployment/{namespace}/{name}", nil, r.deleteDeployment))
 	r.actions = append(r.actions, httpserver.NewAction("PUT", "/deployment/{namespace}/{name}/scale/{instances}", nil, r.scaleDeployment_r))
 	/*-------------- deployment ---------------*/
@@ -131,6 +139,9 @@ func (r *Router) initRoutes() {
 	r.actions = append(r.actions, httpserver.NewAction("POST", "/agentsettings/update", nil, r.updateAgentSettingList))
 	r.actions = append(r.actions, httpserver.NewAction("POST", "/agentsettings/enable", nil, r.enableAgentList))
 	r.actions = append(r.actions, httpserver.NewAction("POST", "/agentsettings/disable", nil, r.disableAgentList))
+	r.actions = append(r.actions, httpserver.NewAction("POST", "/agentsettings/{IP}/drain", nil, r.drainAgent))
+	r.actions = append(r.actions, httpserver.NewAction("GET", "/agentsettings/{IP}/drain", nil, r.queryAgentDrain))
+	r.actions = append(r.actions, httpserver.NewAction("POST", "/agentsettings/{IP}/uncordon", nil, r.uncordonAgent))
 	/*-------------- mesos slave ---------------*/
 
 	/*-------------- custom resource -----------------*/
@@ -151,6 +162,11 @@ func (r *Router) initRoutes() {
 	r.actions = append(r.actions, httpserver.NewAction("GET", "/definition/deployment/{ns}/{name}", nil, r.getDeploymentDef))
 	/*------------- definition --------------------*/
 
+	/*------------- recommendation --------------------*/
+	r.actions = append(r.actions, httpserver.NewAction("GET", "/recommendation/application/{ns}/{name}", nil, r.getApplicationRecommendation))
+	r.actions = append(r.actions, httpserver.NewAction("GET", "/recommendation/deployment/{ns}/{name}", nil, r.getDeploymentRecommendation))
+	/*------------- recommendation --------------------*/
+
 	/*------------- command ---------------------*/
 	r.actions = append(r.actions, httpserver.NewAction("POST", "/command/application/{ns}/{name}", nil, r.sendApplicationCommand))
 	r.actions = append(r.actions, httpserver.NewAction("GET", "/command/application/{ns}/{name}", nil, r.getApplicationCommand))
@@ -168,4 +184,12 @@ func (r *Router) initRoutes() {
 	r.actions = append(r.actions, httpserver.NewAction("GET", "/admissionwebhooks", nil, r.fetchAllAdmissionwebhooks))
 	r.actions = append(r.actions, httpserver.NewAction("GET", "/admissionwebhook/{namespace}/{name}", nil, r.fetchAdmissionwebhook))
 	/*--------------admissionwebhook ----------------------*/
+
+	/*--------------disruptionbudget ----------------------*/
+	r.actions = append(r.actions, httpserver.NewAction("POST", "/disruptionbudget", nil, r.createDisruptionBudget))
+	r.actions = append(r.actions, httpserver.NewAction("PUT", "/disruptionbudget", nil, r.updateDisruptionBudget))
+	r.actions = append(r.actions, httpserver.NewAction("DELETE", "/disruptionbudget/{namespace}/{name}", nil, r.deleteDisruptionBudget))
+	r.actions = append(r.actions, httpserver.NewAction("GET", "/disruptionbudget/{namespace}/{name}", nil, r.fetchDisruptionBudget))
+	r.actions = append(r.actions, httpserver.NewAction("GET", "/disruptionbudgets/{namespace}", nil, r.listDisruptionBudgets))
+	/*--------------disruptionbudget ----------------------*/
 }
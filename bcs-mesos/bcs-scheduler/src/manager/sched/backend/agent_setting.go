@@ -292,3 +292,27 @@ func (b *backend) UpdateAgentSettingList(update *commtypes.BcsClusterAgentSettin
 
 	return comm.BcsSuccess, nil
 }
+
+//DrainAgent disable agent and evict all taskgroups on it in background
+func (b *backend) DrainAgent(IP string, option *commtypes.AgentDrainOption) (*commtypes.BcsAgentDrain, error) {
+	if option.Parallelism < 0 {
+		return nil, errors.New("parallelism can not be negative")
+	}
+	return b.sched.DrainAgent(IP, option, commtypes.AgentDrainReason_Manual)
+}
+
+//UncordonAgent stop draining agent and enable it
+func (b *backend) UncordonAgent(IP string) error {
+	return b.sched.UncordonAgent(IP)
+}
+
+//QueryAgentDrain get drain progress of agent
+func (b *backend) QueryAgentDrain(IP string) (*commtypes.BcsAgentDrain, error) {
+	drain, err := b.sched.GetAgentDrain(IP)
+	if err != nil {
+		blog.Error("fetch agent drain(%s) from db fail:%s", IP, err.Error())
+		return nil, err
+	}
+
+	return drain, nil
+}
@@ -14,6 +14,8 @@
 package backend
 
 import (
+	"context"
+
 	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/sched/scheduler"
 	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/store"
 )
@@ -30,6 +32,13 @@ func NewBackend(sched *scheduler.Scheduler, zkStore store.Store) Backend {
 	}
 }
 
+func (b *backend) WithContext(ctx context.Context) Backend {
+	return &backend{
+		sched: b.sched,
+		store: b.store.WithContext(ctx),
+	}
+}
+
 func (b *backend) ClusterId() string {
 	return b.sched.ClusterId
 }
@@ -14,24 +14,38 @@
 package backend
 
 import (
+	"bk-bcs/bcs-common/common/blog"
 	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/sched/crd"
 	"fmt"
 )
 
-//custom resource register
+//custom resource register, register of existing kind is updated,
+//so versions and schemas can evolve
 func (b *backend) RegisterCustomResource(crr *commtypes.Crr) error {
+	if err := crd.ValidateRegister(crr); err != nil {
+		return err
+	}
+
+	//register is saved by kind, the existing register of the same kind is updated
+	blog.Infof("save custom resource register %s", crr.Spec.Names.Kind)
+	return b.store.SaveCustomResourceRegister(crr)
+}
+
+//fetchCustomResourceRegister get custom resource register by kind
+func (b *backend) fetchCustomResourceRegister(kind string) (*commtypes.Crr, error) {
 	crrs, err := b.store.ListCustomResourceRegister()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, c := range crrs {
-		if c.Spec.Names.Kind == crr.Spec.Names.Kind {
-			return nil
+		if c.Spec.Names.Kind == kind {
+			return c, nil
 		}
 	}
 
-	return b.store.SaveCustomResourceRegister(crr)
+	return nil, fmt.Errorf("custom resource kind %s is invalid", kind)
 }
 
 func (b *backend) UnregisterCustomResource(string) error {
@@ -40,44 +54,32 @@ func (b *backend) UnregisterCustomResource(string) error {
 	return nil
 }
 
-func (b *backend) CreateCustomResource(crd *commtypes.Crd) error {
-	crrs, err := b.store.ListCustomResourceRegister()
+func (b *backend) CreateCustomResource(cr *commtypes.Crd) error {
+	crr, err := b.fetchCustomResourceRegister(string(cr.Kind))
 	if err != nil {
 		return err
 	}
 
-	var kindOk bool
-	for _, c := range crrs {
-		if c.Spec.Names.Kind == string(crd.Kind) {
-			kindOk = true
-		}
-	}
-
-	if !kindOk {
-		return fmt.Errorf("custom resource kind %s is invalid", crd.Kind)
+	//validate against schema, apply defaults and convert to storage version
+	if err := crd.Prepare(crr, cr); err != nil {
+		return err
 	}
 
-	return b.store.SaveCustomResourceDefinition(crd)
+	return b.store.SaveCustomResourceDefinition(cr)
 }
 
-func (b *backend) UpdateCustomResource(crd *commtypes.Crd) error {
-	crrs, err := b.store.ListCustomResourceRegister()
+func (b *backend) UpdateCustomResource(cr *commtypes.Crd) error {
+	crr, err := b.fetchCustomResourceRegister(string(cr.Kind))
 	if err != nil {
 		return err
 	}
 
-	var kindOk bool
-	for _, c := range crrs {
-		if c.Spec.Names.Kind == string(crd.Kind) {
-			kindOk = true
-		}
-	}
-
-	if !kindOk {
-		return fmt.Errorf("custom resource kind %s is invalid", crd.Kind)
+	//validate against schema, apply defaults and convert to storage version
+	if err := crd.Prepare(crr, cr); err != nil {
+		return err
 	}
 
-	return b.store.SaveCustomResourceDefinition(crd)
+	return b.store.SaveCustomResourceDefinition(cr)
 }
 
 func (b *backend) DeleteCustomResource(kind, ns, name string) error {
@@ -91,3 +93,24 @@ func (b *backend) ListCustomResourceDefinition(kind, ns string) ([]*commtypes.Cr
 func (b *backend) FetchCustomResourceDefinition(kind, ns, name string) (*commtypes.Crd, error) {
 	return b.store.FetchCustomResourceDefinition(kind, ns, name)
 }
+
+//FetchCustomResourceDefinitionVersion fetch custom resource and convert it to the served version
+func (b *backend) FetchCustomResourceDefinitionVersion(kind, ns, name, version string) (*commtypes.Crd, error) {
+	cr, err := b.store.FetchCustomResourceDefinition(kind, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		return cr, nil
+	}
+
+	crr, err := b.fetchCustomResourceRegister(kind)
+	if err != nil {
+		return nil, err
+	}
+	if v := crr.GetVersion(version); v == nil || !v.Served {
+		return nil, fmt.Errorf("custom resource %s version %s is not served", kind, version)
+	}
+
+	return crd.Convert(crr, cr, version)
+}
@@ -354,6 +354,7 @@ func (b *backend) UpdateDeployment(deployment *types.DeploymentDef) (int, error)
 		err := errors.New("namespace error")
 		return comm.BcsErrCommRequestDataErr, err
 	}
+	b.applyRecommendation(deployment, currDeployment.Application.ApplicationName)
 	versionErr := b.CheckVersion(version)
 	if versionErr != nil {
 		blog.Error("update deployment, application(%s.%s) version error: %s",
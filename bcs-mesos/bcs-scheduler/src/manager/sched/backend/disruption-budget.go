@@ -0,0 +1,63 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package backend
+
+import (
+	"bk-bcs/bcs-common/common/blog"
+	commtypes "bk-bcs/bcs-common/common/types"
+)
+
+func (b *backend) SaveDisruptionBudget(budget *commtypes.BcsDisruptionBudget) error {
+	if err := budget.Validate(); err != nil {
+		return err
+	}
+	budget.Status = nil
+	return b.store.SaveDisruptionBudget(budget)
+}
+
+//FetchDisruptionBudget fetch budget with current status
+func (b *backend) FetchDisruptionBudget(ns, name string) (*commtypes.BcsDisruptionBudget, error) {
+	budget, err := b.store.FetchDisruptionBudget(ns, name)
+	if err != nil {
+		return nil, err
+	}
+	b.fillDisruptionStatus(budget)
+	return budget, nil
+}
+
+func (b *backend) DeleteDisruptionBudget(ns, name string) error {
+	return b.store.DeleteDisruptionBudget(ns, name)
+}
+
+//ListDisruptionBudgets list budgets under namespace with current status
+func (b *backend) ListDisruptionBudgets(ns string) ([]*commtypes.BcsDisruptionBudget, error) {
+	budgets, err := b.store.ListDisruptionBudgets(ns)
+	if err != nil {
+		return nil, err
+	}
+	for _, budget := range budgets {
+		b.fillDisruptionStatus(budget)
+	}
+	return budgets, nil
+}
+
+func (b *backend) fillDisruptionStatus(budget *commtypes.BcsDisruptionBudget) {
+	status, err := b.sched.GetDisruptionBudgetStatus(budget)
+	if err != nil {
+		blog.Warn("compute status of disruption budget(%s.%s) err: %s",
+			budget.ObjectMeta.NameSpace, budget.ObjectMeta.Name, err.Error())
+		return
+	}
+	budget.Status = status
+}
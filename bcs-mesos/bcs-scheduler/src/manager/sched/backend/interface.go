@@ -26,6 +26,8 @@ Package backend provides api route's backend function implements.
 package backend
 
 import (
+	"context"
+
 	commtypes "bk-bcs/bcs-common/common/types"
 	"bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos"
 	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
@@ -35,6 +37,10 @@ type Backend interface {
 	// get mesos cluster'id, example for BCS-TESTBCSTEST01-10001
 	ClusterId() string
 
+	//return backend whose store operations are traced as children of the span in ctx,
+	//ctx is the context of http request
+	WithContext(ctx context.Context) Backend
+
 	//save application in db, example for zk
 	SaveApplication(*types.Application) error
 
@@ -239,6 +245,15 @@ type Backend interface {
 	//update user custom mesos slaves attributes
 	UpdateAgentSettingList(*commtypes.BcsClusterAgentSettingUpdate) (int, error)
 
+	//disable slave and evict taskgroups on it
+	DrainAgent(string, *commtypes.AgentDrainOption) (*commtypes.BcsAgentDrain, error)
+
+	//stop draining slave and enable it
+	UncordonAgent(string) error
+
+	//get drain progress of slave
+	QueryAgentDrain(string) (*commtypes.BcsAgentDrain, error)
+
 	//custom resource register
 	RegisterCustomResource(*commtypes.Crr) error
 
@@ -269,6 +284,10 @@ type Backend interface {
 	//para3: name
 	FetchCustomResourceDefinition(kind, ns, name string) (*commtypes.Crd, error)
 
+	//fetch custom resource definition, and convert it to the version
+	//para4: served version of custom resource, empty means storage version
+	FetchCustomResourceDefinitionVersion(kind, ns, name, version string) (*commtypes.Crd, error)
+
 	//commit task(taskgroup->image) to url
 	CommitImage(string, string, string) (*types.BcsMessage, error)
 
@@ -294,4 +313,16 @@ type Backend interface {
 	DeleteAdmissionWebhook(ns, name string) error
 	FetchAllAdmissionWebhooks() ([]*commtypes.AdmissionWebhookConfiguration, error)
 	/*=========AdmissionWebhook==========*/
+
+	// get resource recommendation of application, computed from usage history
+	GetApplicationRecommendation(ns, name string) (*types.ResourceRecommendation, error)
+	// get resource recommendation of application bound by deployment
+	GetDeploymentRecommendation(ns, name string) (*types.ResourceRecommendation, error)
+
+	/*=========DisruptionBudget==========*/
+	SaveDisruptionBudget(budget *commtypes.BcsDisruptionBudget) error
+	FetchDisruptionBudget(ns, name string) (*commtypes.BcsDisruptionBudget, error)
+	DeleteDisruptionBudget(ns, name string) error
+	ListDisruptionBudgets(ns string) ([]*commtypes.BcsDisruptionBudget, error)
+	/*=========DisruptionBudget==========*/
 }
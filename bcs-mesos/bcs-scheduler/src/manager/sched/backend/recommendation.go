@@ -0,0 +1,45 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package backend
+
+import (
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+)
+
+func (b *backend) GetApplicationRecommendation(ns, name string) (*types.ResourceRecommendation, error) {
+	return b.sched.GetApplicationRecommendation(ns, name)
+}
+
+func (b *backend) GetDeploymentRecommendation(ns, name string) (*types.ResourceRecommendation, error) {
+	return b.sched.GetDeploymentRecommendation(ns, name)
+}
+
+// applyRecommendation set recommended resources into version of new application
+// when deployment opts in by label RecommendationApplyLabel
+func (b *backend) applyRecommendation(deployment *types.DeploymentDef, currAppID string) {
+	if deployment.ObjectMeta.Labels[types.RecommendationApplyLabel] != "true" {
+		return
+	}
+	ns := deployment.ObjectMeta.NameSpace
+	name := deployment.ObjectMeta.Name
+	recommend, err := b.sched.GetApplicationRecommendation(ns, currAppID)
+	if err != nil {
+		blog.Warn("update deployment(%s.%s): get recommendation of application(%s) err: %s, not applied",
+			ns, name, currAppID, err.Error())
+		return
+	}
+	applied := deployment.Version.ApplyRecommendation(recommend)
+	blog.Info("update deployment(%s.%s): recommended resources applied to containers %v", ns, name, applied)
+}
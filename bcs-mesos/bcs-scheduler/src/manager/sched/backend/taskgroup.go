@@ -76,6 +76,12 @@ func (b *backend) RescheduleTaskgroup(taskgroupId string, hostRetainTime int64)
 		return err
 	}
 
+	// reschedule is voluntary disruption, check disruption budgets
+	if err := b.sched.TryDisruptTaskGroup(taskgroup); err != nil {
+		blog.Warn("reschedule taskgroup(%s) refused: %s", taskgroupId, err.Error())
+		return err
+	}
+
 	// here kill taskGroup
 	resp, err := b.sched.KillTaskGroup(taskgroup)
 	if err != nil {
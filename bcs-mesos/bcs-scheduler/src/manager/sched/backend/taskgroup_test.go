@@ -0,0 +1,108 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package backend
+
+import (
+	"strings"
+	"testing"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+	sched "bk-bcs/bcs-mesos/bcs-scheduler/src/manager/sched/scheduler"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/store"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/util"
+
+	"github.com/samuel/go-zookeeper/zk"
+)
+
+// fakeStore one application with its taskgroups and disruption budget, the methods not implemented panic
+type fakeStore struct {
+	store.Store
+
+	app        *types.Application
+	taskGroups []*types.TaskGroup
+	budget     *commtypes.BcsDisruptionBudget
+}
+
+func (f *fakeStore) InitLockPool()                  {}
+func (f *fakeStore) InitDeploymentLockPool()        {}
+func (f *fakeStore) LockApplication(appID string)   {}
+func (f *fakeStore) UnLockApplication(appID string) {}
+
+func (f *fakeStore) FetchApplication(runAs, appID string) (*types.Application, error) {
+	if f.app.RunAs != runAs || f.app.ID != appID {
+		return nil, zk.ErrNoNode
+	}
+	return f.app, nil
+}
+
+func (f *fakeStore) ListApplications(runAs string) ([]*types.Application, error) {
+	return []*types.Application{f.app}, nil
+}
+
+func (f *fakeStore) GetVersion(runAs, appID string) (*types.Version, error) {
+	return &types.Version{RunAs: runAs}, nil
+}
+
+func (f *fakeStore) FetchTaskGroup(taskGroupID string) (*types.TaskGroup, error) {
+	for _, taskGroup := range f.taskGroups {
+		if taskGroup.ID == taskGroupID {
+			return taskGroup, nil
+		}
+	}
+	return nil, zk.ErrNoNode
+}
+
+func (f *fakeStore) ListTaskGroups(runAs, appID string) ([]*types.TaskGroup, error) {
+	return f.taskGroups, nil
+}
+
+func (f *fakeStore) ListDisruptionBudgets(ns string) ([]*commtypes.BcsDisruptionBudget, error) {
+	return []*commtypes.BcsDisruptionBudget{f.budget}, nil
+}
+
+func TestRescheduleTaskgroupRefusedByDisruptionBudget(t *testing.T) {
+	fake := &fakeStore{
+		app: &types.Application{
+			ID:         "web",
+			RunAs:      "ns",
+			Instances:  2,
+			ObjectMeta: commtypes.ObjectMeta{Name: "web", NameSpace: "ns", Labels: map[string]string{"app": "web"}},
+		},
+		budget: &commtypes.BcsDisruptionBudget{
+			Spec: &commtypes.DisruptionBudgetSpec{MinAvailable: "2", Selector: map[string]string{"app": "web"}},
+		},
+	}
+	fake.budget.ObjectMeta.Name = "web-budget"
+	fake.budget.ObjectMeta.NameSpace = "ns"
+	for _, id := range []string{"0.web.ns.cluster.1536138501685462613", "1.web.ns.cluster.1536138501685462613"} {
+		fake.taskGroups = append(fake.taskGroups, &types.TaskGroup{
+			ID:        id,
+			RunAs:     "ns",
+			AppID:     "web",
+			Status:    types.TASKGROUP_STATUS_RUNNING,
+			Taskgroup: []*types.Task{{Healthy: true}},
+		})
+	}
+	b := NewBackend(sched.NewScheduler(util.Scheduler{}, fake), fake)
+
+	//taskgroup is not killed when budget is exhausted
+	err := b.RescheduleTaskgroup(fake.taskGroups[0].ID, 0)
+	if err == nil || !strings.Contains(err.Error(), "disruption budget web-budget exhausted") {
+		t.Fatalf("expect reschedule refused by disruption budget, got %v", err)
+	}
+	if fake.taskGroups[0].Status != types.TASKGROUP_STATUS_RUNNING {
+		t.Errorf("expect taskgroup kept running, got %s", fake.taskGroups[0].Status)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package crd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+)
+
+const conversionTimeout = time.Second * 10
+
+//convertByWebhook request conversion webhook to convert custom resource
+func convertByWebhook(config *commtypes.CrrWebhookClientConfig, crd *commtypes.Crd, desiredAPIVersion string) (*commtypes.Crd, error) {
+	client, err := newWebhookClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	uid := fmt.Sprintf("%s.%s.%s.%d", crd.Kind, crd.NameSpace, crd.Name, time.Now().UnixNano())
+	review := &commtypes.CrdConversionReview{
+		Request: &commtypes.CrdConversionRequest{
+			UID:               uid,
+			DesiredAPIVersion: desiredAPIVersion,
+			Objects:           []*commtypes.Crd{crd},
+		},
+	}
+	by, err := json.Marshal(review)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Post(config.URL, "application/json", bytes.NewReader(by))
+	if err != nil {
+		return nil, fmt.Errorf("request conversion webhook %s error %s", config.URL, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("conversion webhook %s response status %d body %s", config.URL, resp.StatusCode, string(body))
+	}
+
+	var result *commtypes.CrdConversionReview
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal conversion webhook %s response error %s", config.URL, err.Error())
+	}
+	if result.Response == nil || result.Response.UID != uid {
+		return nil, fmt.Errorf("conversion webhook %s response uid mismatch", config.URL)
+	}
+	if result.Response.Result != commtypes.CrdConversionResultSuccess {
+		return nil, fmt.Errorf("conversion webhook %s failed: %s", config.URL, result.Response.Message)
+	}
+	if len(result.Response.ConvertedObjects) != 1 {
+		return nil, fmt.Errorf("conversion webhook %s return %d objects, expect 1",
+			config.URL, len(result.Response.ConvertedObjects))
+	}
+
+	converted := result.Response.ConvertedObjects[0]
+	if converted.APIVersion != desiredAPIVersion || converted.Kind != crd.Kind ||
+		converted.NameSpace != crd.NameSpace || converted.Name != crd.Name {
+		return nil, fmt.Errorf("conversion webhook %s must not change kind, namespace or name, and must set apiVersion %s",
+			config.URL, desiredAPIVersion)
+	}
+
+	return converted, nil
+}
+
+func newWebhookClient(config *commtypes.CrrWebhookClientConfig) (*http.Client, error) {
+	tr := &http.Transport{}
+	if config.CaBundle != "" {
+		pemCert, err := base64.StdEncoding.DecodeString(config.CaBundle)
+		if err != nil {
+			return nil, fmt.Errorf("decode conversion webhook caBundle error %s", err.Error())
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(pemCert)
+		tr.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Transport: tr, Timeout: conversionTimeout}, nil
+}
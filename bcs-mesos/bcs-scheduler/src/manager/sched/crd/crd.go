@@ -0,0 +1,200 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+//Package crd implements validation, defaulting and version conversion
+//of mesos custom resources according to their registers.
+package crd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+)
+
+//ValidateRegister check custom resource register is valid
+func ValidateRegister(crr *commtypes.Crr) error {
+	if crr.Spec.Names.Kind == "" {
+		return fmt.Errorf("custom resource register spec.names.kind is empty")
+	}
+
+	switch crr.Spec.Scope {
+	case "":
+		crr.Spec.Scope = commtypes.CrrScopeNamespaced
+	case commtypes.CrrScopeNamespaced, commtypes.CrrScopeCluster:
+	default:
+		return fmt.Errorf("custom resource register scope %s is invalid", crr.Spec.Scope)
+	}
+
+	//register without versions is compatible with old version, no validation
+	if len(crr.Spec.Versions) == 0 {
+		return nil
+	}
+
+	storages := 0
+	versions := make(map[string]bool)
+	for _, v := range crr.Spec.Versions {
+		if v.Name == "" {
+			return fmt.Errorf("custom resource register %s version name is empty", crr.Spec.Names.Kind)
+		}
+		if versions[v.Name] {
+			return fmt.Errorf("custom resource register %s version %s is duplicated", crr.Spec.Names.Kind, v.Name)
+		}
+		versions[v.Name] = true
+		if v.Storage {
+			storages++
+		}
+	}
+	if storages != 1 {
+		return fmt.Errorf("custom resource register %s must have exactly one storage version", crr.Spec.Names.Kind)
+	}
+
+	if crr.Spec.Conversion == nil {
+		crr.Spec.Conversion = &commtypes.CrrConversion{Strategy: commtypes.CrrConversionNone}
+	}
+	switch crr.Spec.Conversion.Strategy {
+	case "":
+		crr.Spec.Conversion.Strategy = commtypes.CrrConversionNone
+	case commtypes.CrrConversionNone:
+	case commtypes.CrrConversionWebhook:
+		if crr.Spec.Conversion.WebhookClientConfig == nil || crr.Spec.Conversion.WebhookClientConfig.URL == "" {
+			return fmt.Errorf("custom resource register %s conversion webhook url is empty", crr.Spec.Names.Kind)
+		}
+	default:
+		return fmt.Errorf("custom resource register %s conversion strategy %s is invalid",
+			crr.Spec.Names.Kind, crr.Spec.Conversion.Strategy)
+	}
+
+	return nil
+}
+
+//Prepare validate custom resource against its register and apply schema defaults,
+//then convert custom resource to the storage version
+func Prepare(crr *commtypes.Crr, crd *commtypes.Crd) error {
+	if crr.Spec.Scope != commtypes.CrrScopeCluster && crd.NameSpace == "" {
+		return fmt.Errorf("custom resource %s %s is namespaced, but metadata.namespace is empty", crd.Kind, crd.Name)
+	}
+
+	if len(crr.Spec.Versions) == 0 {
+		return nil
+	}
+
+	version, err := requestVersion(crr, crd)
+	if err != nil {
+		return err
+	}
+
+	if version.Schema != nil && version.Schema.OpenAPIV3Schema != nil {
+		obj, err := toObject(crd)
+		if err != nil {
+			return err
+		}
+		ApplyDefaults(version.Schema.OpenAPIV3Schema, obj)
+		if err := ValidateSchema(version.Schema.OpenAPIV3Schema, obj); err != nil {
+			return fmt.Errorf("custom resource %s %s is invalid: %s", crd.Kind, crd.Name, err.Error())
+		}
+		crd.Spec = obj["spec"]
+		if status, ok := obj["status"]; ok {
+			crd.Status = status
+		}
+	}
+
+	storage := crr.GetStorageVersion()
+	if storage.Name == version.Name {
+		crd.APIVersion = apiVersionOf(crd.APIVersion, version.Name)
+		return nil
+	}
+
+	converted, err := Convert(crr, crd, storage.Name)
+	if err != nil {
+		return err
+	}
+	*crd = *converted
+	return nil
+}
+
+//Convert convert custom resource to desired version
+func Convert(crr *commtypes.Crr, crd *commtypes.Crd, desired string) (*commtypes.Crd, error) {
+	if crr.GetVersion(desired) == nil {
+		return nil, fmt.Errorf("custom resource %s version %s is not registered", crr.Spec.Names.Kind, desired)
+	}
+
+	current := versionOf(crd.APIVersion)
+	if current == desired {
+		return crd, nil
+	}
+
+	desiredAPIVersion := apiVersionOf(crd.APIVersion, desired)
+	if crr.Spec.Conversion == nil || crr.Spec.Conversion.Strategy != commtypes.CrrConversionWebhook {
+		converted := *crd
+		converted.APIVersion = desiredAPIVersion
+		return &converted, nil
+	}
+
+	return convertByWebhook(crr.Spec.Conversion.WebhookClientConfig, crd, desiredAPIVersion)
+}
+
+//requestVersion get the version of custom resource, empty version means storage version
+func requestVersion(crr *commtypes.Crr, crd *commtypes.Crd) (*commtypes.CrrVersion, error) {
+	name := versionOf(crd.APIVersion)
+	if name == "" {
+		return crr.GetStorageVersion(), nil
+	}
+
+	version := crr.GetVersion(name)
+	if version == nil {
+		return nil, fmt.Errorf("custom resource %s version %s is not registered", crr.Spec.Names.Kind, name)
+	}
+	if !version.Served {
+		return nil, fmt.Errorf("custom resource %s version %s is not served", crr.Spec.Names.Kind, name)
+	}
+
+	return version, nil
+}
+
+//toObject convert spec and status into json object, schema root describes the whole custom resource
+func toObject(crd *commtypes.Crd) (map[string]interface{}, error) {
+	raw := map[string]interface{}{"spec": crd.Spec}
+	if crd.Status != nil {
+		raw["status"] = crd.Status
+	}
+
+	//marshal and unmarshal, make all values are generic json values
+	by, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(by, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+//versionOf get version from apiVersion, examples: mygroup/v1 => v1
+func versionOf(apiVersion string) string {
+	if i := strings.LastIndex(apiVersion, "/"); i >= 0 {
+		return apiVersion[i+1:]
+	}
+	return apiVersion
+}
+
+//apiVersionOf replace the version of apiVersion, group is kept
+func apiVersionOf(apiVersion, version string) string {
+	if i := strings.LastIndex(apiVersion, "/"); i >= 0 {
+		return apiVersion[:i+1] + version
+	}
+	return version
+}
@@ -0,0 +1,193 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package crd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+)
+
+const testSchema = `{
+	"type": "object",
+	"properties": {
+		"spec": {
+			"type": "object",
+			"required": ["image"],
+			"additionalProperties": false,
+			"properties": {
+				"image": {"type": "string", "minLength": 1},
+				"replicas": {"type": "integer", "minimum": 1, "default": 1},
+				"mode": {"type": "string", "enum": ["master", "slave"]},
+				"ports": {"type": "array", "items": {"type": "integer"}}
+			}
+		}
+	}
+}`
+
+func newTestCrr(t *testing.T) *commtypes.Crr {
+	var schema *commtypes.JSONSchemaProps
+	if err := json.Unmarshal([]byte(testSchema), &schema); err != nil {
+		t.Fatalf("unmarshal schema error %s", err.Error())
+	}
+
+	crr := &commtypes.Crr{
+		Spec: commtypes.CrrSpec{
+			Names: commtypes.CrrSpecName{Kind: "mysql"},
+			Versions: []*commtypes.CrrVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1", Served: true, Storage: true, Schema: &commtypes.CrrValidation{OpenAPIV3Schema: schema}},
+				{Name: "v0", Served: false},
+			},
+		},
+	}
+	if err := ValidateRegister(crr); err != nil {
+		t.Fatalf("validate register error %s", err.Error())
+	}
+	return crr
+}
+
+func newTestCrd(apiVersion, spec string) *commtypes.Crd {
+	crd := &commtypes.Crd{}
+	crd.APIVersion = apiVersion
+	crd.Kind = "mysql"
+	crd.NameSpace = "default"
+	crd.Name = "db"
+	json.Unmarshal([]byte(spec), &crd.Spec)
+	return crd
+}
+
+func TestValidateRegister(t *testing.T) {
+	for i, tt := range []struct {
+		crr   *commtypes.Crr
+		valid bool
+	}{
+		{&commtypes.Crr{Spec: commtypes.CrrSpec{Names: commtypes.CrrSpecName{Kind: "mysql"}}}, true},
+		{&commtypes.Crr{Spec: commtypes.CrrSpec{}}, false},
+		{&commtypes.Crr{Spec: commtypes.CrrSpec{Names: commtypes.CrrSpecName{Kind: "mysql"}, Scope: "Global"}}, false},
+		{&commtypes.Crr{Spec: commtypes.CrrSpec{
+			Names:    commtypes.CrrSpecName{Kind: "mysql"},
+			Versions: []*commtypes.CrrVersion{{Name: "v1"}, {Name: "v2"}},
+		}}, false},
+		{&commtypes.Crr{Spec: commtypes.CrrSpec{
+			Names:    commtypes.CrrSpecName{Kind: "mysql"},
+			Versions: []*commtypes.CrrVersion{{Name: "v1", Storage: true}, {Name: "v1"}},
+		}}, false},
+		{&commtypes.Crr{Spec: commtypes.CrrSpec{
+			Names:      commtypes.CrrSpecName{Kind: "mysql"},
+			Versions:   []*commtypes.CrrVersion{{Name: "v1", Storage: true}},
+			Conversion: &commtypes.CrrConversion{Strategy: commtypes.CrrConversionWebhook},
+		}}, false},
+	} {
+		err := ValidateRegister(tt.crr)
+		if (err == nil) != tt.valid {
+			t.Errorf("test #%d: expect valid %t, got error %v", i, tt.valid, err)
+		}
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	crr := newTestCrr(t)
+
+	for i, tt := range []struct {
+		apiVersion string
+		spec       string
+		errPart    string
+	}{
+		{"", `{"image": "mysql:5.7"}`, ""},
+		{"db.bk/v1", `{"image": "mysql:5.7", "mode": "master", "ports": [3306]}`, ""},
+		{"db.bk/v1", `{"replicas": 2}`, "spec.image: required field is missing"},
+		{"db.bk/v1", `{"image": "mysql", "replicas": 0}`, "spec.replicas: must be greater than or equal to 1"},
+		{"db.bk/v1", `{"image": "mysql", "replicas": 1.5}`, "spec.replicas: must be integer"},
+		{"db.bk/v1", `{"image": "mysql", "mode": "leader"}`, "spec.mode: unsupported value"},
+		{"db.bk/v1", `{"image": "mysql", "port": 3306}`, "spec.port: unknown field"},
+		{"db.bk/v1", `{"image": "mysql", "ports": ["3306"]}`, "spec.ports[0]: must be integer"},
+		{"db.bk/v0", `{"image": "mysql"}`, "is not served"},
+		{"db.bk/v2", `{"image": "mysql"}`, "is not registered"},
+	} {
+		crd := newTestCrd(tt.apiVersion, tt.spec)
+		err := Prepare(crr, crd)
+		if tt.errPart == "" {
+			if err != nil {
+				t.Errorf("test #%d: unexpected error %s", i, err.Error())
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.errPart) {
+			t.Errorf("test #%d: expect error contains %q, got %v", i, tt.errPart, err)
+		}
+	}
+}
+
+func TestPrepareDefaultsAndConversion(t *testing.T) {
+	crr := newTestCrr(t)
+
+	crd := newTestCrd("", `{"image": "mysql:5.7"}`)
+	if err := Prepare(crr, crd); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	if crd.APIVersion != "v1" {
+		t.Errorf("expect apiVersion v1, got %s", crd.APIVersion)
+	}
+	if replicas := crd.Spec.(map[string]interface{})["replicas"]; replicas != float64(1) {
+		t.Errorf("expect default replicas 1, got %v", replicas)
+	}
+
+	//conversion strategy None only changes apiVersion
+	crd = newTestCrd("db.bk/v1alpha1", `{"anything": true}`)
+	if err := Prepare(crr, crd); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	if crd.APIVersion != "db.bk/v1" {
+		t.Errorf("expect apiVersion db.bk/v1, got %s", crd.APIVersion)
+	}
+}
+
+func TestPrepareConversionWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var review *commtypes.CrdConversionReview
+		json.NewDecoder(r.Body).Decode(&review)
+		obj := review.Request.Objects[0]
+		obj.APIVersion = review.Request.DesiredAPIVersion
+		obj.Spec = map[string]interface{}{"image": obj.Spec.(map[string]interface{})["img"]}
+		review.Response = &commtypes.CrdConversionResponse{
+			UID:              review.Request.UID,
+			ConvertedObjects: []*commtypes.Crd{obj},
+			Result:           commtypes.CrdConversionResultSuccess,
+		}
+		json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+
+	crr := newTestCrr(t)
+	crr.Spec.Conversion = &commtypes.CrrConversion{
+		Strategy:            commtypes.CrrConversionWebhook,
+		WebhookClientConfig: &commtypes.CrrWebhookClientConfig{URL: server.URL},
+	}
+
+	crd := newTestCrd("db.bk/v1alpha1", `{"img": "mysql:5.7"}`)
+	if err := Prepare(crr, crd); err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	if crd.APIVersion != "db.bk/v1" {
+		t.Errorf("expect apiVersion db.bk/v1, got %s", crd.APIVersion)
+	}
+	if image := crd.Spec.(map[string]interface{})["image"]; image != "mysql:5.7" {
+		t.Errorf("expect converted image mysql:5.7, got %v", image)
+	}
+}
@@ -0,0 +1,215 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package crd
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+)
+
+//ValidateSchema validate object against openAPIV3Schema, return all invalid fields
+func ValidateSchema(schema *commtypes.JSONSchemaProps, obj interface{}) error {
+	if schema == nil {
+		return nil
+	}
+
+	var errs []string
+	validateValue(schema, obj, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s", strings.Join(errs, "; "))
+}
+
+func validateValue(schema *commtypes.JSONSchemaProps, value interface{}, path string, errs *[]string) {
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			*errs = append(*errs, fmt.Sprintf("%s: must not be null", fieldPath(path)))
+		}
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		*errs = append(*errs, fmt.Sprintf("%s: unsupported value %v, must be one of %v", fieldPath(path), value, schema.Enum))
+	}
+
+	switch schema.Type {
+	case "":
+		//no type declared, any value is valid
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: must be object", fieldPath(path)))
+			return
+		}
+		validateObject(schema, obj, path, errs)
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: must be array", fieldPath(path)))
+			return
+		}
+		if schema.MinItems != nil && int64(len(arr)) < *schema.MinItems {
+			*errs = append(*errs, fmt.Sprintf("%s: must have at least %d items", fieldPath(path), *schema.MinItems))
+		}
+		if schema.MaxItems != nil && int64(len(arr)) > *schema.MaxItems {
+			*errs = append(*errs, fmt.Sprintf("%s: must have at most %d items", fieldPath(path), *schema.MaxItems))
+		}
+		if schema.Items != nil {
+			for i, item := range arr {
+				validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: must be string", fieldPath(path)))
+			return
+		}
+		if schema.MinLength != nil && int64(len(str)) < *schema.MinLength {
+			*errs = append(*errs, fmt.Sprintf("%s: length must be at least %d", fieldPath(path), *schema.MinLength))
+		}
+		if schema.MaxLength != nil && int64(len(str)) > *schema.MaxLength {
+			*errs = append(*errs, fmt.Sprintf("%s: length must be at most %d", fieldPath(path), *schema.MaxLength))
+		}
+		if schema.Pattern != "" {
+			matched, err := regexp.MatchString(schema.Pattern, str)
+			if err != nil {
+				*errs = append(*errs, fmt.Sprintf("%s: invalid pattern %s in schema", fieldPath(path), schema.Pattern))
+			} else if !matched {
+				*errs = append(*errs, fmt.Sprintf("%s: must match pattern %s", fieldPath(path), schema.Pattern))
+			}
+		}
+	case "integer", "number":
+		num, ok := value.(float64)
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: must be %s", fieldPath(path), schema.Type))
+			return
+		}
+		if schema.Type == "integer" && num != float64(int64(num)) {
+			*errs = append(*errs, fmt.Sprintf("%s: must be integer", fieldPath(path)))
+		}
+		if schema.Minimum != nil && num < *schema.Minimum {
+			*errs = append(*errs, fmt.Sprintf("%s: must be greater than or equal to %v", fieldPath(path), *schema.Minimum))
+		}
+		if schema.Maximum != nil && num > *schema.Maximum {
+			*errs = append(*errs, fmt.Sprintf("%s: must be less than or equal to %v", fieldPath(path), *schema.Maximum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: must be boolean", fieldPath(path)))
+		}
+	default:
+		*errs = append(*errs, fmt.Sprintf("%s: unsupported schema type %s", fieldPath(path), schema.Type))
+	}
+}
+
+func validateObject(schema *commtypes.JSONSchemaProps, obj map[string]interface{}, path string, errs *[]string) {
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: required field is missing", fieldPath(joinPath(path, name))))
+		}
+	}
+
+	//sort keys, make error message stable
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		prop, ok := schema.Properties[k]
+		if !ok {
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+				*errs = append(*errs, fmt.Sprintf("%s: unknown field", fieldPath(joinPath(path, k))))
+			}
+			continue
+		}
+		validateValue(prop, obj[k], joinPath(path, k), errs)
+	}
+}
+
+//ApplyDefaults set schema default values for the missing fields of object
+func ApplyDefaults(schema *commtypes.JSONSchemaProps, obj interface{}) {
+	if schema == nil || obj == nil {
+		return
+	}
+
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for name, prop := range schema.Properties {
+			if _, ok := v[name]; !ok && prop.Default != nil {
+				v[name] = deepCopy(prop.Default)
+			}
+			ApplyDefaults(prop, v[name])
+		}
+	case []interface{}:
+		if schema.Items == nil {
+			return
+		}
+		for _, item := range v {
+			ApplyDefaults(schema.Items, item)
+		}
+	}
+}
+
+//deepCopy copy json value, avoid objects sharing the default value of schema
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = deepCopy(item)
+		}
+		return m
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			arr[i] = deepCopy(item)
+		}
+		return arr
+	default:
+		return v
+	}
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func fieldPath(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+	return path
+}
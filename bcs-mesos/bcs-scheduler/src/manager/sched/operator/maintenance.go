@@ -0,0 +1,63 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package operator
+
+import (
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/sched/client"
+	mesos_maintenance "bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos/maintenance"
+	master "bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos/master"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"net/http"
+)
+
+// Get maintenance schedule from mesos master
+func GetMaintenanceSchedule(mesosClient *client.Client) (*mesos_maintenance.Schedule, error) {
+
+	if mesosClient == nil {
+		blog.Error("get maintenance schedule error: mesos Client is nil")
+		return nil, fmt.Errorf("system error: mesos client is nil")
+	}
+
+	call := &master.Call{
+		Type: master.Call_GET_MAINTENANCE_SCHEDULE.Enum(),
+	}
+	req, err := proto.Marshal(call)
+	if err != nil {
+		blog.Error("get maintenance schedule: proto.Marshal err: %s", err.Error())
+		return nil, fmt.Errorf("system error: proto marshal error")
+	}
+	resp, err := mesosClient.Send(req)
+	if err != nil {
+		blog.Error("get maintenance schedule: Send err: %s", err.Error())
+		return nil, fmt.Errorf("send request to mesos error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		blog.Error("get maintenance schedule: unexpected response statusCode: %d", resp.StatusCode)
+		return nil, fmt.Errorf("mesos response statuscode: %d", resp.StatusCode)
+	}
+
+	var response master.Response
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		blog.Error("get maintenance schedule: Decode response failed: %s", err.Error())
+		return nil, fmt.Errorf("mesos response decode err: %s", err.Error())
+	}
+	blog.V(3).Infof("get maintenance schedule: response msg type(%d)", response.GetType())
+
+	return response.GetGetMaintenanceSchedule().GetSchedule(), nil
+}
@@ -187,7 +187,16 @@ func (s *Scheduler) deploymentBeginRolling(deployment *types.Deployment) bool {
 		s.innerScaleApplication(appExt.RunAs, appExt.ID, uint64(deployment.ApplicationExt.CurrentTargetInstances))
 		s.store.SaveDeployment(deployment)
 	} else {
-		// begin delete
+		// begin delete, number of deleted taskgroups is limited by disruption budgets
+		toDelete := deployment.Application.CurrentRollingInstances
+		allowed := s.limitRollingDelete(deployment, app, toDelete)
+		if allowed <= 0 && toDelete > 0 {
+			deployment.IsInRolling = false
+			s.store.SaveDeployment(deployment)
+			return false
+		}
+		deployment.Application.CurrentTargetInstances = int(app.Instances) - allowed
+		deployment.Application.CurrentRollingInstances = allowed
 		deployment.CurrRollingOp = types.DEPLOYMENT_OPERATION_DELETE
 		s.innerScaleApplication(app.RunAs, app.ID, uint64(deployment.Application.CurrentTargetInstances))
 		s.store.SaveDeployment(deployment)
@@ -240,8 +249,14 @@ func (s *Scheduler) checkCreateFirstRollingStart(deployment *types.Deployment) b
 				ns, name, deployment.Application.ApplicationName, err.Error())
 			return false
 		}
-		// do delete
+		// do delete, number of deleted taskgroups is limited by disruption budgets
 		if app.Instances > uint64(deployment.Application.CurrentTargetInstances) {
+			allowed := s.limitRollingDelete(deployment, app, int(app.Instances)-deployment.Application.CurrentTargetInstances)
+			if allowed <= 0 {
+				s.store.SaveDeployment(deployment)
+				return false
+			}
+			deployment.Application.CurrentTargetInstances = int(app.Instances) - allowed
 			deployment.CurrRollingOp = types.DEPLOYMENT_OPERATION_DELETE
 			s.innerScaleApplication(app.RunAs, app.ID, uint64(deployment.Application.CurrentTargetInstances))
 		} else {
@@ -410,6 +425,7 @@ func (s *Scheduler) finishRollingUpdate(deployment *types.Deployment) bool {
 	name := deployment.ObjectMeta.Name
 	blog.Info("deployment(%s.%s) rolling update finish, call delete bind application(%s)",
 		ns, name, deployment.Application.ApplicationName)
+	s.inheritUsageHistory(ns, deployment.Application.ApplicationName, deployment.ApplicationExt.ApplicationName)
 	s.InnerDeleteApplication(ns, deployment.Application.ApplicationName, false)
 	blog.Info("deployment(%s.%s) rolling update finish, call delete bind application(%s) return",
 		ns, name, deployment.Application.ApplicationName)
@@ -507,6 +523,10 @@ func (s *Scheduler) isRollingStartFinished(app *types.Application, rollingNum in
 				taskGroup.ID, taskGroup.Status)
 			return false
 		}
+		if !taskGroup.IsReady() {
+			blog.Info("taskgroup(%s) is running but not ready, rolling update not finish", taskGroup.ID)
+			return false
+		}
 
 		for _, task := range taskGroup.Taskgroup {
 			hasLocalCheck := false
@@ -0,0 +1,187 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"bk-bcs/bcs-common/common/blog"
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"fmt"
+	"time"
+)
+
+//matchedDisruptionBudgets list budgets in namespace selecting the application labels
+func (s *Scheduler) matchedDisruptionBudgets(ns string, labels map[string]string) ([]*commtypes.BcsDisruptionBudget, error) {
+	budgets, err := s.store.ListDisruptionBudgets(ns)
+	if err != nil {
+		return nil, err
+	}
+	var matched []*commtypes.BcsDisruptionBudget
+	for _, budget := range budgets {
+		if budget.Spec != nil && budget.Spec.Matches(labels) {
+			matched = append(matched, budget)
+		}
+	}
+	return matched, nil
+}
+
+//computeDisruptionStatus compute status of budget from taskgroups of matched applications.
+//caller must hold disruptionLock
+func (s *Scheduler) computeDisruptionStatus(budget *commtypes.BcsDisruptionBudget) (*commtypes.DisruptionBudgetStatus, error) {
+	ns := budget.ObjectMeta.NameSpace
+	apps, err := s.store.ListApplications(ns)
+	if err != nil {
+		blog.Error("disruption budget(%s.%s): list applications err: %s", ns, budget.ObjectMeta.Name, err.Error())
+		return nil, err
+	}
+	var taskgroups []*types.TaskGroup
+	for _, app := range apps {
+		if !budget.Spec.Matches(app.ObjectMeta.Labels) {
+			continue
+		}
+		list, err := s.store.ListTaskGroups(ns, app.ID)
+		if err != nil {
+			blog.Error("disruption budget(%s.%s): list taskgroups of %s err: %s",
+				ns, budget.ObjectMeta.Name, app.ID, err.Error())
+			return nil, err
+		}
+		taskgroups = append(taskgroups, list...)
+	}
+
+	now := time.Now().Unix()
+	for id, t := range s.disruptedTaskgroups {
+		if now-t >= types.DisruptionRecordTimeout {
+			delete(s.disruptedTaskgroups, id)
+		}
+	}
+	return types.ComputeDisruptionStatus(budget, taskgroups, s.disruptedTaskgroups, now)
+}
+
+//GetDisruptionBudgetStatus compute current status of budget
+func (s *Scheduler) GetDisruptionBudgetStatus(budget *commtypes.BcsDisruptionBudget) (*commtypes.DisruptionBudgetStatus, error) {
+	s.disruptionLock.Lock()
+	defer s.disruptionLock.Unlock()
+
+	return s.computeDisruptionStatus(budget)
+}
+
+//TryDisruptTaskGroup check budgets before a voluntary disruption of taskgroup,
+//error is returned when any budget selecting it is exhausted. taskgroup which is
+//not available now is always allowed, it does not decrease healthy count
+func (s *Scheduler) TryDisruptTaskGroup(taskGroup *types.TaskGroup) error {
+	if !taskGroup.IsAvailable() {
+		return nil
+	}
+
+	s.disruptionLock.Lock()
+	defer s.disruptionLock.Unlock()
+
+	app, err := s.store.FetchApplication(taskGroup.RunAs, taskGroup.AppID)
+	if err != nil {
+		blog.Error("disrupt taskgroup(%s): fetch application err: %s", taskGroup.ID, err.Error())
+		return err
+	}
+	budgets, err := s.matchedDisruptionBudgets(app.RunAs, app.ObjectMeta.Labels)
+	if err != nil {
+		blog.Error("disrupt taskgroup(%s): list disruption budgets err: %s", taskGroup.ID, err.Error())
+		return err
+	}
+	for _, budget := range budgets {
+		status, err := s.computeDisruptionStatus(budget)
+		if err != nil {
+			return err
+		}
+		if status.DisruptionsAllowed < 1 {
+			blog.Warn("disrupt taskgroup(%s) refused by disruption budget(%s.%s): healthy %d, desired %d",
+				taskGroup.ID, budget.ObjectMeta.NameSpace, budget.ObjectMeta.Name, status.CurrentHealthy, status.DesiredHealthy)
+			return fmt.Errorf("disruption budget %s exhausted, healthy %d, desired %d",
+				budget.ObjectMeta.Name, status.CurrentHealthy, status.DesiredHealthy)
+		}
+	}
+	if len(budgets) > 0 {
+		s.disruptedTaskgroups[taskGroup.ID] = time.Now().Unix()
+	}
+	return nil
+}
+
+//reserveDisruptions min disruptions allowed by budgets selecting application, -1 means not limited by
+//any budget. scaling application down by count deletes the taskgroups with the largest instance ids, as
+//many of them as allowed are recorded as disrupted under disruptionLock, so that the allowance is not
+//spent again by concurrent drain or reschedule
+func (s *Scheduler) reserveDisruptions(app *types.Application, count int) (int, string, error) {
+	s.disruptionLock.Lock()
+	defer s.disruptionLock.Unlock()
+
+	budgets, err := s.matchedDisruptionBudgets(app.RunAs, app.ObjectMeta.Labels)
+	if err != nil {
+		return 0, "", err
+	}
+	if len(budgets) == 0 {
+		return -1, "", nil
+	}
+	allowed := -1
+	name := ""
+	for _, budget := range budgets {
+		status, err := s.computeDisruptionStatus(budget)
+		if err != nil {
+			return 0, budget.ObjectMeta.Name, err
+		}
+		if allowed < 0 || status.DisruptionsAllowed < allowed {
+			allowed = status.DisruptionsAllowed
+			name = budget.ObjectMeta.Name
+		}
+	}
+
+	reserved := count
+	if allowed < reserved {
+		reserved = allowed
+	}
+	if reserved <= 0 {
+		return allowed, name, nil
+	}
+	taskgroups, err := s.store.ListTaskGroups(app.RunAs, app.ID)
+	if err != nil {
+		return 0, name, err
+	}
+	now := time.Now().Unix()
+	for _, taskgroup := range taskgroups {
+		if taskgroup.InstanceID+uint64(reserved) >= app.Instances && taskgroup.IsAvailable() {
+			s.disruptedTaskgroups[taskgroup.ID] = now
+		}
+	}
+	return allowed, name, nil
+}
+
+//limitRollingDelete cap taskgroups deleted from application in one rolling step by
+//disruption budgets, returns how many taskgroups can be deleted now
+func (s *Scheduler) limitRollingDelete(deployment *types.Deployment, app *types.Application, toDelete int) int {
+	if toDelete <= 0 {
+		return toDelete
+	}
+	allowed, name, err := s.reserveDisruptions(app, toDelete)
+	if err != nil {
+		blog.Warn("deployment(%s.%s) rolling update: check disruption budget err: %s, wait",
+			deployment.ObjectMeta.NameSpace, deployment.ObjectMeta.Name, err.Error())
+		deployment.Message = "check disruption budget failed: " + err.Error()
+		return 0
+	}
+	if allowed < 0 || allowed >= toDelete {
+		return toDelete
+	}
+	blog.Info("deployment(%s.%s) rolling update: delete %d taskgroups limited to %d by disruption budget(%s)",
+		deployment.ObjectMeta.NameSpace, deployment.ObjectMeta.Name, toDelete, allowed, name)
+	deployment.Message = fmt.Sprintf("waiting for disruption budget %s, %d of %d taskgroups can be deleted",
+		name, allowed, toDelete)
+	return allowed
+}
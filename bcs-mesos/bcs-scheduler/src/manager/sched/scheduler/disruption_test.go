@@ -0,0 +1,234 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+)
+
+//newDisruptionStore create store with applications labeled app=web in namespace ns,
+//each application has running and healthy taskgroups of the given count
+func newDisruptionStore(minAvailable int, apps map[string]int) *fakeStore {
+	fake := newFakeStore()
+	for appID, count := range apps {
+		fake.applications["ns."+appID] = &types.Application{
+			ID:         appID,
+			RunAs:      "ns",
+			Instances:  uint64(count),
+			ObjectMeta: commtypes.ObjectMeta{Name: appID, NameSpace: "ns", Labels: map[string]string{"app": "web"}},
+		}
+		for i := 0; i < count; i++ {
+			taskGroup := &types.TaskGroup{
+				ID:         strconv.Itoa(i) + "." + appID + ".ns.cluster.1536138501685462613",
+				RunAs:      "ns",
+				AppID:      appID,
+				InstanceID: uint64(i),
+				Status:     types.TASKGROUP_STATUS_RUNNING,
+				Taskgroup:  []*types.Task{{Healthy: true}},
+			}
+			fake.taskGroups[taskGroup.ID] = taskGroup
+		}
+	}
+	budget := &commtypes.BcsDisruptionBudget{
+		Spec: &commtypes.DisruptionBudgetSpec{
+			MinAvailable: commtypes.DisruptionValue(strconv.Itoa(minAvailable)),
+			Selector:     map[string]string{"app": "web"},
+		},
+	}
+	budget.ObjectMeta.Name = "web-budget"
+	budget.ObjectMeta.NameSpace = "ns"
+	fake.budgets = append(fake.budgets, budget)
+	return fake
+}
+
+func newDisruptionScheduler(fake *fakeStore) *Scheduler {
+	return &Scheduler{store: fake, disruptedTaskgroups: make(map[string]int64)}
+}
+
+func TestTryDisruptTaskGroup(t *testing.T) {
+	fake := newDisruptionStore(2, map[string]int{"web": 3})
+	s := newDisruptionScheduler(fake)
+
+	first, _ := fake.FetchTaskGroup("0.web.ns.cluster.1536138501685462613")
+	if err := s.TryDisruptTaskGroup(first); err != nil {
+		t.Fatalf("expect first disruption allowed, got %s", err.Error())
+	}
+	if _, ok := s.disruptedTaskgroups[first.ID]; !ok {
+		t.Errorf("expect disrupted taskgroup recorded")
+	}
+	//disrupted taskgroup is not counted as healthy before it is really down
+	second, _ := fake.FetchTaskGroup("1.web.ns.cluster.1536138501685462613")
+	if err := s.TryDisruptTaskGroup(second); err == nil || !strings.Contains(err.Error(), "web-budget") {
+		t.Fatalf("expect second disruption refused by budget, got %v", err)
+	}
+	//taskgroup not available is always allowed
+	second.Status = types.TASKGROUP_STATUS_STAGING
+	if err := s.TryDisruptTaskGroup(second); err != nil {
+		t.Errorf("expect unavailable taskgroup allowed, got %s", err.Error())
+	}
+
+	//application without matched budget is not limited and not recorded
+	fake.applications["ns.web"].ObjectMeta.Labels = map[string]string{"app": "other"}
+	third, _ := fake.FetchTaskGroup("2.web.ns.cluster.1536138501685462613")
+	if err := s.TryDisruptTaskGroup(third); err != nil {
+		t.Errorf("expect disruption without budget allowed, got %s", err.Error())
+	}
+	if _, ok := s.disruptedTaskgroups[third.ID]; ok {
+		t.Errorf("expect taskgroup without budget not recorded")
+	}
+}
+
+func TestLimitRollingDelete(t *testing.T) {
+	tests := []struct {
+		name         string
+		minAvailable int
+		toDelete     int
+		expect       int
+		waiting      bool
+	}{
+		{name: "nothing to delete", minAvailable: 1, toDelete: 0, expect: 0},
+		{name: "budget allows all", minAvailable: 1, toDelete: 2, expect: 2},
+		{name: "capped by budget", minAvailable: 2, toDelete: 3, expect: 1, waiting: true},
+		{name: "budget exhausted", minAvailable: 3, toDelete: 1, expect: 0, waiting: true},
+	}
+	for _, test := range tests {
+		fake := newDisruptionStore(test.minAvailable, map[string]int{"web": 3})
+		s := newDisruptionScheduler(fake)
+		deployment := &types.Deployment{}
+		got := s.limitRollingDelete(deployment, fake.applications["ns.web"], test.toDelete)
+		if got != test.expect {
+			t.Errorf("%s: expect %d taskgroups deleted, got %d", test.name, test.expect, got)
+		}
+		if waiting := strings.Contains(deployment.Message, "waiting for disruption budget web-budget"); waiting != test.waiting {
+			t.Errorf("%s: expect waiting %t, got message %q", test.name, test.waiting, deployment.Message)
+		}
+	}
+
+	//not limited without budget
+	fake := newDisruptionStore(3, map[string]int{"web": 3})
+	fake.budgets = nil
+	s := newDisruptionScheduler(fake)
+	if got := s.limitRollingDelete(&types.Deployment{}, fake.applications["ns.web"], 3); got != 3 {
+		t.Errorf("expect not limited without budget, got %d", got)
+	}
+}
+
+func TestLimitRollingDeleteReserveBudget(t *testing.T) {
+	fake := newDisruptionStore(1, map[string]int{"web": 3})
+	s := newDisruptionScheduler(fake)
+	if got := s.limitRollingDelete(&types.Deployment{}, fake.applications["ns.web"], 1); got != 1 {
+		t.Fatalf("expect 1 taskgroup deleted, got %d", got)
+	}
+	//taskgroup with the largest instance id is deleted by scaling down
+	if _, ok := s.disruptedTaskgroups["2.web.ns.cluster.1536138501685462613"]; !ok || len(s.disruptedTaskgroups) != 1 {
+		t.Fatalf("expect deleted taskgroup recorded as disrupted, got %v", s.disruptedTaskgroups)
+	}
+
+	//the rest of budget can be spent by drain once, not twice
+	first, _ := fake.FetchTaskGroup("0.web.ns.cluster.1536138501685462613")
+	if err := s.TryDisruptTaskGroup(first); err != nil {
+		t.Fatalf("expect disruption allowed by the rest of budget, got %s", err.Error())
+	}
+	second, _ := fake.FetchTaskGroup("1.web.ns.cluster.1536138501685462613")
+	if err := s.TryDisruptTaskGroup(second); err == nil {
+		t.Errorf("expect disruption refused as budget is spent by rolling update and drain")
+	}
+}
+
+func newRollingDeployment(order commtypes.RollingOrderType) *types.Deployment {
+	deployment := &types.Deployment{
+		Strategy: commtypes.UpgradeStrategy{
+			RollingUpdate: &commtypes.RollingUpdate{MaxUnavailable: 2, MaxSurge: 1, RollingOrder: order},
+		},
+		Application:    &types.DeploymentReferApplication{ApplicationName: "web-v1"},
+		ApplicationExt: &types.DeploymentReferApplication{ApplicationName: "web-v2"},
+	}
+	deployment.ObjectMeta.Name = "web"
+	deployment.ObjectMeta.NameSpace = "ns"
+	return deployment
+}
+
+func TestDeploymentBeginRollingLimitedByBudget(t *testing.T) {
+	tests := []struct {
+		name         string
+		minAvailable int
+		rolling      bool
+		target       int
+	}{
+		//maxUnavailable 2 is capped to 1 by budget
+		{name: "capped by budget", minAvailable: 2, rolling: true, target: 2},
+		{name: "budget exhausted", minAvailable: 3, rolling: false, target: 1},
+	}
+	for _, test := range tests {
+		fake := newDisruptionStore(test.minAvailable, map[string]int{"web-v1": 3, "web-v2": 0})
+		fake.applications["ns.web-v2"].DefineInstances = 3
+		s := newDisruptionScheduler(fake)
+		deployment := newRollingDeployment(commtypes.DeleteFirstOrder)
+
+		s.deploymentBeginRolling(deployment)
+		if deployment.IsInRolling != test.rolling || fake.deploymentSaves != 1 {
+			t.Errorf("%s: expect rolling %t and deployment saved, got rolling %t, saves %d",
+				test.name, test.rolling, deployment.IsInRolling, fake.deploymentSaves)
+		}
+		if deployment.Application.CurrentTargetInstances != test.target {
+			t.Errorf("%s: expect target instances %d, got %d", test.name, test.target, deployment.Application.CurrentTargetInstances)
+		}
+		if test.rolling && (deployment.CurrRollingOp != types.DEPLOYMENT_OPERATION_DELETE || deployment.Application.CurrentRollingInstances != 1) {
+			t.Errorf("%s: expect delete 1 taskgroup, got op %s, rolling %d",
+				test.name, deployment.CurrRollingOp, deployment.Application.CurrentRollingInstances)
+		}
+		if !strings.Contains(deployment.Message, "web-budget") {
+			t.Errorf("%s: expect message of budget, got %q", test.name, deployment.Message)
+		}
+	}
+}
+
+func TestCreateFirstRollingStartLimitedByBudget(t *testing.T) {
+	tests := []struct {
+		name         string
+		minAvailable int
+		target       int
+		op           string
+	}{
+		//4 healthy taskgroups of web-v1 & web-v2, delete 1 of web-v1
+		{name: "allowed by budget", minAvailable: 3, target: 2, op: types.DEPLOYMENT_OPERATION_DELETE},
+		{name: "budget exhausted", minAvailable: 4, target: 2, op: types.DEPLOYMENT_OPERATION_START},
+	}
+	for _, test := range tests {
+		fake := newDisruptionStore(test.minAvailable, map[string]int{"web-v1": 3, "web-v2": 1})
+		fake.applications["ns.web-v2"].DefineInstances = 3
+		s := newDisruptionScheduler(fake)
+		deployment := newRollingDeployment(commtypes.CreateFirstOrder)
+		deployment.CurrRollingOp = types.DEPLOYMENT_OPERATION_START
+		deployment.Application.CurrentTargetInstances = 2
+		deployment.ApplicationExt.CurrentTargetInstances = 1
+		deployment.ApplicationExt.CurrentRollingInstances = 1
+
+		if s.checkCreateFirstRollingStart(deployment) {
+			t.Errorf("%s: expect rolling update not finished", test.name)
+		}
+		if deployment.CurrRollingOp != test.op || deployment.Application.CurrentTargetInstances != test.target {
+			t.Errorf("%s: expect op %s target %d, got op %s target %d", test.name, test.op, test.target,
+				deployment.CurrRollingOp, deployment.Application.CurrentTargetInstances)
+		}
+		if fake.deploymentSaves != 1 {
+			t.Errorf("%s: expect deployment saved once, got %d", test.name, fake.deploymentSaves)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"bk-bcs/bcs-common/common/blog"
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/sched/operator"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	//DefaultAgentDrainParallelism max taskgroups evicting at the same time if not specified
+	DefaultAgentDrainParallelism = 1
+	//AgentDrainCheckInterval seconds between two ticks of agent drain
+	AgentDrainCheckInterval = 5
+	//AgentDrainRescheduleDelayTime seconds evicted taskgroup waits before rescheduled
+	AgentDrainRescheduleDelayTime = 5
+	//MaintenanceCheckInterval seconds between two checks of mesos maintenance schedule
+	MaintenanceCheckInterval = 60
+)
+
+//DrainAgent mark agent unschedulable and evict all taskgroups on it in background,
+//reschedule of taskgroups is limited by parallelism and disruption budgets
+func (s *Scheduler) DrainAgent(ip string, option *commtypes.AgentDrainOption, reason string) (*commtypes.BcsAgentDrain, error) {
+	s.drainLock.Lock()
+	defer s.drainLock.Unlock()
+
+	if err := s.setAgentDisabled(ip, true); err != nil {
+		return nil, err
+	}
+
+	drain, err := s.store.FetchAgentDrain(ip)
+	if err != nil {
+		blog.Error("drain agent(%s): fetch agent drain err: %s", ip, err.Error())
+		return nil, err
+	}
+	now := time.Now().Unix()
+	if drain == nil || drain.Phase != commtypes.AgentDrainPhase_Draining {
+		drain = &commtypes.BcsAgentDrain{
+			InnerIP:   ip,
+			Phase:     commtypes.AgentDrainPhase_Draining,
+			Evicting:  make(map[string]int64),
+			StartTime: now,
+		}
+	}
+	drain.Reason = reason
+	drain.Option = *option
+	drain.UpdateTime = now
+	if err := s.store.SaveAgentDrain(drain); err != nil {
+		blog.Error("drain agent(%s): save agent drain err: %s", ip, err.Error())
+		return nil, err
+	}
+
+	blog.Info("drain agent(%s) for %s, parallelism(%d)", ip, reason, drain.Option.Parallelism)
+	s.startAgentDrain(ip)
+	return drain, nil
+}
+
+//UncordonAgent stop draining agent and make it schedulable again,
+//taskgroups already evicted are not moved back
+func (s *Scheduler) UncordonAgent(ip string) error {
+	s.drainLock.Lock()
+	defer s.drainLock.Unlock()
+
+	drain, err := s.store.FetchAgentDrain(ip)
+	if err != nil {
+		blog.Error("uncordon agent(%s): fetch agent drain err: %s", ip, err.Error())
+		return err
+	}
+	if drain != nil {
+		if err := s.store.DeleteAgentDrain(ip); err != nil {
+			return err
+		}
+	}
+
+	blog.Info("uncordon agent(%s)", ip)
+	return s.setAgentDisabled(ip, false)
+}
+
+//GetAgentDrain get drain progress of agent, nil if agent is not drained
+func (s *Scheduler) GetAgentDrain(ip string) (*commtypes.BcsAgentDrain, error) {
+	return s.store.FetchAgentDrain(ip)
+}
+
+func (s *Scheduler) setAgentDisabled(ip string, disabled bool) error {
+	agent, err := s.store.FetchAgentSetting(ip)
+	if err != nil {
+		blog.Error("fetch agent setting(%s) from db fail:%s", ip, err.Error())
+		return err
+	}
+	if agent == nil {
+		agent = &commtypes.BcsClusterAgentSetting{
+			InnerIP: ip,
+		}
+	}
+	agent.Disabled = disabled
+	return s.store.SaveAgentSetting(agent)
+}
+
+//startAgentDrain start drain goroutine for agent if it is not running, caller must hold drainLock
+func (s *Scheduler) startAgentDrain(ip string) {
+	if s.drainingAgents[ip] {
+		return
+	}
+	s.drainingAgents[ip] = true
+	go s.runAgentDrain(ip)
+}
+
+//startCheckAgentDrains resume agent drains after scheduler becomes master
+func (s *Scheduler) startCheckAgentDrains() {
+	time.Sleep(60 * time.Second)
+	blog.Info("check agent drains begin")
+	ips, err := s.store.ListAgentDrainNodes()
+	if err != nil {
+		blog.Error("check agent drains: list agent drains err: %s", err.Error())
+		return
+	}
+
+	s.drainLock.Lock()
+	defer s.drainLock.Unlock()
+	for _, ip := range ips {
+		blog.Infof("to check agent(%s) drain", ip)
+		s.startAgentDrain(ip)
+	}
+}
+
+//runAgentDrain run drain ticks of agent until it is finished. drainLock is not held during ticks, which
+//access zk and kill taskgroups, so that DrainAgent and UncordonAgent are not blocked by them
+func (s *Scheduler) runAgentDrain(ip string) {
+	blog.Info("agent(%s) drain check begin", ip)
+	for {
+		finish := s.Role != "master"
+		if !finish {
+			finish = s.agentDrainTick(ip)
+		}
+		if finish && s.finishAgentDrain(ip) {
+			blog.Info("agent(%s) drain check end", ip)
+			return
+		}
+		time.Sleep(AgentDrainCheckInterval * time.Second)
+	}
+}
+
+//finishAgentDrain remove agent from draining agents, return false if the agent is drained
+//again by DrainAgent before the drain goroutine exits
+func (s *Scheduler) finishAgentDrain(ip string) bool {
+	s.drainLock.Lock()
+	if s.Role == "master" {
+		drain, err := s.store.FetchAgentDrain(ip)
+		if err != nil || (drain != nil && drain.Phase == commtypes.AgentDrainPhase_Draining) {
+			s.drainLock.Unlock()
+			return false
+		}
+	}
+	delete(s.drainingAgents, ip)
+	noDraining := len(s.drainingAgents) == 0
+	s.drainLock.Unlock()
+
+	if noDraining {
+		s.agentScanLock.Lock()
+		s.agentTaskGroups = nil
+		s.agentScanLock.Unlock()
+	}
+	return true
+}
+
+//agentDrainTick evict taskgroups on agent as many as parallelism and budgets allow,
+//return true when drain is finished or canceled
+func (s *Scheduler) agentDrainTick(ip string) bool {
+	drain, err := s.store.FetchAgentDrain(ip)
+	if err != nil {
+		blog.Error("agent(%s) drain: fetch agent drain err: %s", ip, err.Error())
+		return false
+	}
+	if drain == nil || drain.Phase != commtypes.AgentDrainPhase_Draining {
+		return true
+	}
+	if drain.Evicting == nil {
+		drain.Evicting = make(map[string]int64)
+	}
+
+	now := time.Now().Unix()
+	taskgroups, err := s.listAgentTaskGroups(ip, now)
+	if err != nil {
+		blog.Error("agent(%s) drain: list taskgroups err: %s", ip, err.Error())
+		return false
+	}
+
+	onAgent := make(map[string]*types.TaskGroup)
+	for _, taskgroup := range taskgroups {
+		onAgent[taskgroup.ID] = taskgroup
+	}
+
+	//rescheduled taskgroup gets a new id, so eviction is done when the old one is gone.
+	//if reschedule transaction timeout, give up the taskgroup or evict it again
+	var pending []*types.TaskGroup
+	for id, evictTime := range drain.Evicting {
+		taskgroup, ok := onAgent[id]
+		if !ok {
+			blog.Info("agent(%s) drain: taskgroup(%s) evicted", ip, id)
+			delete(drain.Evicting, id)
+			drain.Evicted++
+			continue
+		}
+		if now-evictTime <= TRANSACTION_DEFAULT_LIFEPERIOD+s.drainHostRetainTime(drain, taskgroup) {
+			continue
+		}
+		delete(drain.Evicting, id)
+		if isTaskGroupOnAgent(taskgroup) {
+			blog.Warn("agent(%s) drain: taskgroup(%s) still %s after evicted, evict again", ip, id, taskgroup.Status)
+			pending = append(pending, taskgroup)
+		} else {
+			blog.Warn("agent(%s) drain: taskgroup(%s) %s but not rescheduled", ip, id, taskgroup.Status)
+			drain.Evicted++
+		}
+	}
+	for _, taskgroup := range taskgroups {
+		if _, ok := drain.Evicting[taskgroup.ID]; ok {
+			continue
+		}
+		if isTaskGroupOnAgent(taskgroup) && !containsTaskGroup(pending, taskgroup.ID) {
+			pending = append(pending, taskgroup)
+		}
+	}
+
+	parallelism := drain.Option.Parallelism
+	if parallelism <= 0 {
+		parallelism = DefaultAgentDrainParallelism
+	}
+	drain.Message = ""
+	drain.Pending = nil
+	for _, taskgroup := range pending {
+		if len(drain.Evicting) >= parallelism {
+			drain.Pending = append(drain.Pending, taskgroup.ID)
+			continue
+		}
+		if err := s.TryDisruptTaskGroup(taskgroup); err != nil {
+			blog.Info("agent(%s) drain: taskgroup(%s) waiting: %s", ip, taskgroup.ID, err.Error())
+			drain.Message = fmt.Sprintf("taskgroup %s waiting: %s", taskgroup.ID, err.Error())
+			drain.Pending = append(drain.Pending, taskgroup.ID)
+			continue
+		}
+		if err := s.evictTaskGroup(taskgroup, s.drainHostRetainTime(drain, taskgroup)); err != nil {
+			blog.Error("agent(%s) drain: evict taskgroup(%s) err: %s", ip, taskgroup.ID, err.Error())
+			drain.Message = fmt.Sprintf("evict taskgroup %s failed: %s", taskgroup.ID, err.Error())
+			drain.Pending = append(drain.Pending, taskgroup.ID)
+			continue
+		}
+		drain.Evicting[taskgroup.ID] = now
+	}
+
+	if len(drain.Pending) == 0 && len(drain.Evicting) == 0 {
+		blog.Info("agent(%s) drained, %d taskgroups evicted", ip, drain.Evicted)
+		drain.Phase = commtypes.AgentDrainPhase_Drained
+		drain.FinishTime = now
+	}
+	drain.UpdateTime = now
+	return s.saveAgentDrainProgress(ip, drain)
+}
+
+//saveAgentDrainProgress save the progress of drain tick if the agent is still being drained, the drain may be
+//uncordoned or updated by DrainAgent during the tick. return true when drain is finished or canceled
+func (s *Scheduler) saveAgentDrainProgress(ip string, drain *commtypes.BcsAgentDrain) bool {
+	s.drainLock.Lock()
+	defer s.drainLock.Unlock()
+
+	current, err := s.store.FetchAgentDrain(ip)
+	if err != nil {
+		blog.Error("agent(%s) drain: fetch agent drain err: %s", ip, err.Error())
+		return false
+	}
+	if current == nil || current.Phase != commtypes.AgentDrainPhase_Draining {
+		blog.Info("agent(%s) drain canceled during drain check", ip)
+		return true
+	}
+	if current.StartTime != drain.StartTime {
+		//uncordoned and drained again, the new drain is checked in next tick
+		return false
+	}
+	//option and reason may be updated by DrainAgent
+	drain.Option = current.Option
+	drain.Reason = current.Reason
+	if err := s.store.SaveAgentDrain(drain); err != nil {
+		blog.Error("agent(%s) drain: save agent drain err: %s", ip, err.Error())
+		return false
+	}
+
+	return drain.Phase != commtypes.AgentDrainPhase_Draining
+}
+
+//drainHostRetainTime seconds the evicted taskgroup retains the agent
+func (s *Scheduler) drainHostRetainTime(drain *commtypes.BcsAgentDrain, taskgroup *types.TaskGroup) int64 {
+	if drain.Option.HostRetainTime != nil {
+		return *drain.Option.HostRetainTime
+	}
+	if taskgroup.RestartPolicy != nil && taskgroup.RestartPolicy.HostRetainTime > 0 {
+		return taskgroup.RestartPolicy.HostRetainTime
+	}
+	return 0
+}
+
+//evictTaskGroup kill taskgroup and reschedule it by reschedule transaction
+func (s *Scheduler) evictTaskGroup(taskgroup *types.TaskGroup, hostRetainTime int64) error {
+	runAs := taskgroup.RunAs
+	appID := taskgroup.AppID
+
+	s.store.LockApplication(runAs + "." + appID)
+	defer s.store.UnLockApplication(runAs + "." + appID)
+
+	version, _ := s.store.GetVersion(runAs, appID)
+	if version == nil {
+		return fmt.Errorf("no version for application(%s.%s)", runAs, appID)
+	}
+
+	resp, err := s.KillTaskGroup(taskgroup)
+	if err != nil {
+		blog.Warn("taskgroup(%s) evict under status(%s) but do kill failed: %s", taskgroup.ID, taskgroup.Status, err.Error())
+	} else if resp != nil && resp.StatusCode != http.StatusAccepted {
+		blog.Warn("taskgroup(%s) evict under status(%s) but kill return code %d", taskgroup.ID, taskgroup.Status, resp.StatusCode)
+	}
+
+	rescheduleTrans := CreateTransaction()
+	rescheduleTrans.RunAs = runAs
+	rescheduleTrans.AppID = appID
+	rescheduleTrans.OpType = types.OPERATION_RESCHEDULE
+	rescheduleTrans.Status = types.OPERATION_STATUS_INIT
+	rescheduleTrans.DelayTime = AgentDrainRescheduleDelayTime
+
+	var rescheduleOpdata TransRescheduleOpData
+	rescheduleOpdata.TaskGroupID = taskgroup.ID
+	rescheduleOpdata.Force = true
+	rescheduleOpdata.IsInner = false
+	if hostRetainTime > 0 {
+		blog.Info("taskgroup(%s) evicted will retain host(%s) for %d seconds",
+			taskgroup.ID, taskgroup.HostName, hostRetainTime)
+		rescheduleOpdata.HostRetainTime = hostRetainTime
+		rescheduleOpdata.HostRetain = taskgroup.HostName
+		rescheduleTrans.LifePeriod += hostRetainTime
+	}
+	rescheduleOpdata.NeedResource = version.AllResource()
+	rescheduleOpdata.Version = version
+	rescheduleTrans.OpData = &rescheduleOpdata
+
+	go s.RunRescheduleTaskgroup(rescheduleTrans)
+	return nil
+}
+
+//listAgentTaskGroups list taskgroups launched on agent. all taskgroups are scanned
+//at most once per drain tick, other draining agents reuse the scan
+func (s *Scheduler) listAgentTaskGroups(ip string, now int64) ([]*types.TaskGroup, error) {
+	s.agentScanLock.Lock()
+	defer s.agentScanLock.Unlock()
+
+	if s.agentTaskGroups != nil && now-s.agentTaskGroupsTime < AgentDrainCheckInterval {
+		return s.agentTaskGroups[ip], nil
+	}
+
+	runAses, err := s.store.ListRunAs()
+	if err != nil {
+		return nil, err
+	}
+
+	agentTaskGroups := make(map[string][]*types.TaskGroup)
+	for _, runAs := range runAses {
+		appIDs, err := s.store.ListApplicationNodes(runAs)
+		if err != nil {
+			return nil, err
+		}
+		for _, appID := range appIDs {
+			list, err := s.store.ListTaskGroups(runAs, appID)
+			if err != nil {
+				return nil, err
+			}
+			for _, taskgroup := range list {
+				if len(taskgroup.Taskgroup) > 0 {
+					agentIP := taskgroup.Taskgroup[0].AgentIPAddress
+					agentTaskGroups[agentIP] = append(agentTaskGroups[agentIP], taskgroup)
+				}
+			}
+		}
+	}
+	s.agentTaskGroups = agentTaskGroups
+	s.agentTaskGroupsTime = now
+	return agentTaskGroups[ip], nil
+}
+
+//isTaskGroupOnAgent taskgroup is occupying the agent and should be evicted
+func isTaskGroupOnAgent(taskgroup *types.TaskGroup) bool {
+	switch taskgroup.Status {
+	case types.TASKGROUP_STATUS_STAGING, types.TASKGROUP_STATUS_STARTING,
+		types.TASKGROUP_STATUS_RUNNING, types.TASKGROUP_STATUS_RESTARTING:
+		return true
+	}
+	return false
+}
+
+func containsTaskGroup(taskgroups []*types.TaskGroup, id string) bool {
+	for _, taskgroup := range taskgroups {
+		if taskgroup.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+//startCheckMaintenance drain agents ahead of mesos maintenance windows and
+//uncordon them after windows are over, only when maintenance drain ahead is configured
+func (s *Scheduler) startCheckMaintenance() {
+	if s.config.MaintenanceDrainAhead <= 0 {
+		return
+	}
+
+	blog.Info("check maintenance schedule begin, drain ahead %d seconds", s.config.MaintenanceDrainAhead)
+	for {
+		time.Sleep(MaintenanceCheckInterval * time.Second)
+		if s.Role != "master" {
+			blog.Warn("check maintenance schedule exit, because scheduler is not master now")
+			return
+		}
+		s.checkMaintenance()
+	}
+}
+
+func (s *Scheduler) checkMaintenance() {
+	schedule, err := operator.GetMaintenanceSchedule(s.operatorClient)
+	if err != nil {
+		blog.Error("check maintenance schedule: %s", err.Error())
+		return
+	}
+
+	inMaintenance := make(map[string]bool)
+	for _, ip := range types.MaintenanceDrainAgents(schedule, s.config.MaintenanceDrainAhead, time.Now()) {
+		inMaintenance[ip] = true
+		drain, err := s.store.FetchAgentDrain(ip)
+		if err != nil {
+			blog.Error("check maintenance schedule: fetch agent(%s) drain err: %s", ip, err.Error())
+			continue
+		}
+		if drain != nil {
+			continue
+		}
+		blog.Info("agent(%s) will come to maintenance, drain it", ip)
+		if _, err := s.DrainAgent(ip, &commtypes.AgentDrainOption{}, commtypes.AgentDrainReason_Maintenance); err != nil {
+			blog.Error("check maintenance schedule: drain agent(%s) err: %s", ip, err.Error())
+		}
+	}
+
+	ips, err := s.store.ListAgentDrainNodes()
+	if err != nil {
+		blog.Error("check maintenance schedule: list agent drains err: %s", err.Error())
+		return
+	}
+	for _, ip := range ips {
+		if inMaintenance[ip] {
+			continue
+		}
+		drain, err := s.store.FetchAgentDrain(ip)
+		if err != nil || drain == nil || drain.Reason != commtypes.AgentDrainReason_Maintenance {
+			continue
+		}
+		blog.Info("agent(%s) maintenance is over, uncordon it", ip)
+		if err := s.UncordonAgent(ip); err != nil {
+			blog.Error("check maintenance schedule: uncordon agent(%s) err: %s", ip, err.Error())
+		}
+	}
+}
@@ -0,0 +1,274 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/sched/client"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+)
+
+//newDrainScheduler create scheduler whose kill calls are counted by a fake mesos master,
+//caller should close the master
+func newDrainScheduler(fake *fakeStore) (*Scheduler, *int32, *httptest.Server) {
+	kills := new(int32)
+	master := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(kills, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	s := newDisruptionScheduler(fake)
+	s.client = client.New(master.Listener.Addr().String(), "/")
+	s.framework = &mesos.FrameworkInfo{}
+	s.drainingAgents = make(map[string]bool)
+	return s, kills, master
+}
+
+//placeTaskGroups launch taskgroups on agent and give their applications a version.
+//taskgroups not in store are created unhealthy without application, so they are always
+//allowed to disrupt and the reschedule transactions of them fail at once
+func placeTaskGroups(fake *fakeStore, ip string, ids ...string) {
+	for _, id := range ids {
+		taskGroup := fake.taskGroups[id]
+		if taskGroup == nil {
+			taskGroup = &types.TaskGroup{
+				ID:        id,
+				RunAs:     "ns",
+				AppID:     strings.Split(id, ".")[1],
+				Status:    types.TASKGROUP_STATUS_RUNNING,
+				Taskgroup: []*types.Task{{}},
+			}
+			fake.taskGroups[id] = taskGroup
+		}
+		taskGroup.Taskgroup[0].AgentIPAddress = ip
+		fake.versions[taskGroup.RunAs+"."+taskGroup.AppID] = &types.Version{ID: taskGroup.AppID, RunAs: taskGroup.RunAs}
+	}
+}
+
+func newDraining(ip string, parallelism int) *commtypes.BcsAgentDrain {
+	return &commtypes.BcsAgentDrain{
+		InnerIP:  ip,
+		Phase:    commtypes.AgentDrainPhase_Draining,
+		Option:   commtypes.AgentDrainOption{Parallelism: parallelism},
+		Evicting: make(map[string]int64),
+	}
+}
+
+func TestAgentDrainTickParallelism(t *testing.T) {
+	fake := newFakeStore()
+	placeTaskGroups(fake, "10.0.0.1", "0.a.ns.cluster.1", "0.b.ns.cluster.1", "0.c.ns.cluster.1")
+	placeTaskGroups(fake, "10.0.0.2", "0.d.ns.cluster.1")
+	fake.SaveAgentDrain(newDraining("10.0.0.1", 2))
+	s, kills, master := newDrainScheduler(fake)
+	defer master.Close()
+
+	if s.agentDrainTick("10.0.0.1") {
+		t.Fatalf("expect drain not finished")
+	}
+	drain := fake.agentDrains["10.0.0.1"]
+	if len(drain.Evicting) != 2 || len(drain.Pending) != 1 || atomic.LoadInt32(kills) != 2 {
+		t.Fatalf("expect 2 evicting and 1 pending, got evicting %v pending %v kills %d",
+			drain.Evicting, drain.Pending, atomic.LoadInt32(kills))
+	}
+	if _, ok := drain.Evicting["0.d.ns.cluster.1"]; ok {
+		t.Errorf("expect taskgroup on other agent not evicted")
+	}
+
+	//evicting taskgroups keep the slots until they leave the agent
+	s.agentTaskGroups = nil
+	s.agentDrainTick("10.0.0.1")
+	if len(drain.Evicting) != 2 || atomic.LoadInt32(kills) != 2 {
+		t.Fatalf("expect no more eviction, got evicting %v kills %d", drain.Evicting, atomic.LoadInt32(kills))
+	}
+
+	//rescheduled taskgroups get new ids, the old ones are gone
+	for id := range drain.Evicting {
+		delete(fake.taskGroups, id)
+	}
+	s.agentTaskGroups = nil
+	s.agentDrainTick("10.0.0.1")
+	if drain.Evicted != 2 || len(drain.Evicting) != 1 || len(drain.Pending) != 0 {
+		t.Fatalf("expect last taskgroup evicting, got evicted %d evicting %v pending %v",
+			drain.Evicted, drain.Evicting, drain.Pending)
+	}
+
+	for id := range drain.Evicting {
+		delete(fake.taskGroups, id)
+	}
+	s.agentTaskGroups = nil
+	if !s.agentDrainTick("10.0.0.1") {
+		t.Fatalf("expect drain finished")
+	}
+	if drain.Phase != commtypes.AgentDrainPhase_Drained || drain.Evicted != 3 || drain.FinishTime == 0 {
+		t.Errorf("expect agent drained with 3 evicted, got %+v", drain)
+	}
+}
+
+func TestAgentDrainTickWaitBudget(t *testing.T) {
+	fake := newDisruptionStore(2, map[string]int{"web": 2})
+	placeTaskGroups(fake, "10.0.0.1", "0.web.ns.cluster.1536138501685462613", "1.web.ns.cluster.1536138501685462613")
+	fake.SaveAgentDrain(newDraining("10.0.0.1", 2))
+	s, kills, master := newDrainScheduler(fake)
+	defer master.Close()
+
+	if s.agentDrainTick("10.0.0.1") {
+		t.Fatalf("expect drain not finished")
+	}
+	drain := fake.agentDrains["10.0.0.1"]
+	if len(drain.Evicting) != 0 || len(drain.Pending) != 2 || atomic.LoadInt32(kills) != 0 {
+		t.Fatalf("expect all taskgroups pending, got evicting %v pending %v kills %d",
+			drain.Evicting, drain.Pending, atomic.LoadInt32(kills))
+	}
+	if !strings.Contains(drain.Message, "web-budget") {
+		t.Errorf("expect drain message about budget, got %s", drain.Message)
+	}
+}
+
+func TestAgentDrainTickEvictionTimeout(t *testing.T) {
+	fake := newFakeStore()
+	placeTaskGroups(fake, "10.0.0.1", "0.a.ns.cluster.1", "0.b.ns.cluster.1")
+	fake.taskGroups["0.b.ns.cluster.1"].Status = types.TASKGROUP_STATUS_FAIL
+	drain := newDraining("10.0.0.1", 2)
+	timeout := time.Now().Unix() - TRANSACTION_DEFAULT_LIFEPERIOD - 1
+	drain.Evicting["0.a.ns.cluster.1"] = timeout
+	drain.Evicting["0.b.ns.cluster.1"] = timeout
+	fake.SaveAgentDrain(drain)
+	s, kills, master := newDrainScheduler(fake)
+	defer master.Close()
+
+	//running taskgroup is evicted again, failed one is given up
+	if s.agentDrainTick("10.0.0.1") {
+		t.Fatalf("expect drain not finished")
+	}
+	if evictTime, ok := drain.Evicting["0.a.ns.cluster.1"]; !ok || evictTime == timeout || atomic.LoadInt32(kills) != 1 {
+		t.Errorf("expect running taskgroup evicted again, got evicting %v kills %d", drain.Evicting, atomic.LoadInt32(kills))
+	}
+	if _, ok := drain.Evicting["0.b.ns.cluster.1"]; ok || drain.Evicted != 1 {
+		t.Errorf("expect failed taskgroup given up, got evicting %v evicted %d", drain.Evicting, drain.Evicted)
+	}
+}
+
+func TestListAgentTaskGroupsShareScan(t *testing.T) {
+	fake := newFakeStore()
+	placeTaskGroups(fake, "10.0.0.1", "0.a.ns.cluster.1")
+	placeTaskGroups(fake, "10.0.0.2", "0.b.ns.cluster.1", "1.b.ns.cluster.1")
+	s, _, master := newDrainScheduler(fake)
+	defer master.Close()
+
+	now := int64(1000)
+	tests := []struct {
+		ip    string
+		now   int64
+		count int
+		scans int
+	}{
+		{"10.0.0.1", now, 1, 1},
+		{"10.0.0.2", now + 1, 2, 1},
+		{"10.0.0.3", now + AgentDrainCheckInterval - 1, 0, 1},
+		{"10.0.0.1", now + AgentDrainCheckInterval, 1, 2},
+	}
+	for i, test := range tests {
+		list, err := s.listAgentTaskGroups(test.ip, test.now)
+		if err != nil {
+			t.Fatalf("test %d: list agent taskgroups err: %s", i, err.Error())
+		}
+		if len(list) != test.count || fake.runAsLists != test.scans {
+			t.Errorf("test %d: expect %d taskgroups after %d scans, got %d after %d scans",
+				i, test.count, test.scans, len(list), fake.runAsLists)
+		}
+	}
+}
+
+func TestDrainAndUncordonAgent(t *testing.T) {
+	fake := newFakeStore()
+	s, _, master := newDrainScheduler(fake)
+	defer master.Close()
+
+	drain, err := s.DrainAgent("10.0.0.1", &commtypes.AgentDrainOption{Parallelism: 2}, commtypes.AgentDrainReason_Manual)
+	if err != nil {
+		t.Fatalf("drain agent err: %s", err.Error())
+	}
+	if drain.Phase != commtypes.AgentDrainPhase_Draining || !fake.agentSettings["10.0.0.1"].Disabled {
+		t.Fatalf("expect agent disabled and draining, got %+v", drain)
+	}
+
+	if err := s.UncordonAgent("10.0.0.1"); err != nil {
+		t.Fatalf("uncordon agent err: %s", err.Error())
+	}
+	if fake.agentSettings["10.0.0.1"].Disabled {
+		t.Errorf("expect agent enabled after uncordon")
+	}
+	if drain, _ := s.GetAgentDrain("10.0.0.1"); drain != nil {
+		t.Errorf("expect agent drain deleted, got %+v", drain)
+	}
+}
+
+//blockingScanStore blocks scanning taskgroups until released
+type blockingScanStore struct {
+	*fakeStore
+	scanning chan struct{}
+	release  chan struct{}
+}
+
+func (b *blockingScanStore) ListRunAs() ([]string, error) {
+	close(b.scanning)
+	<-b.release
+	return b.fakeStore.ListRunAs()
+}
+
+func TestUncordonAgentDuringDrainTick(t *testing.T) {
+	fake := newFakeStore()
+	placeTaskGroups(fake, "10.0.0.1", "0.a.ns.cluster.1")
+	fake.SaveAgentDrain(newDraining("10.0.0.1", 1))
+	s, _, master := newDrainScheduler(fake)
+	defer master.Close()
+	blocking := &blockingScanStore{fakeStore: fake, scanning: make(chan struct{}), release: make(chan struct{})}
+	s.store = blocking
+
+	done := make(chan bool)
+	go func() {
+		done <- s.agentDrainTick("10.0.0.1")
+	}()
+	<-blocking.scanning
+
+	//uncordon is not blocked by the drain tick scanning taskgroups
+	uncordoned := make(chan error)
+	go func() {
+		uncordoned <- s.UncordonAgent("10.0.0.1")
+	}()
+	select {
+	case err := <-uncordoned:
+		if err != nil {
+			t.Fatalf("uncordon agent err: %s", err.Error())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("uncordon agent blocked by drain tick")
+	}
+
+	close(blocking.release)
+	if !<-done {
+		t.Errorf("expect drain tick finished as agent uncordoned")
+	}
+	if drain, _ := fake.FetchAgentDrain("10.0.0.1"); drain != nil {
+		t.Errorf("expect uncordoned drain not saved back, got %+v", drain)
+	}
+}
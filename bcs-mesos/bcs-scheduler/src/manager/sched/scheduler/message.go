@@ -207,3 +207,39 @@ func (s *Scheduler) ProcessCommandMessage(bcsMsg *types.BcsMessage) {
 
 	return
 }
+
+//ProcessUsageMessage keeps latest resource usage reported by executor in memory and adds it into usage history,
+//the usage is saved into taskgroup at most once in taskGroupUsageSaveInterval
+func (s *Scheduler) ProcessUsageMessage(bcsMsg *types.BcsMessage) {
+	if bcsMsg.TaskGroupUsage == nil || bcsMsg.TaskID == nil {
+		blog.Error("process usage message, but data empty")
+		return
+	}
+	taskGroupID := store.GetTaskGroupID(bcsMsg.TaskID.GetValue())
+	if taskGroupID == "" {
+		blog.Error("process usage message: can not get taskGroupId from taskID(%s)", bcsMsg.TaskID.GetValue())
+		return
+	}
+	usage := bcsMsg.TaskGroupUsage
+
+	newSample, save := s.updateTaskGroupUsage(taskGroupID, usage, time.Now().Unix())
+	if !newSample && !save {
+		blog.V(3).Infof("process usage message: taskgroup(%s) usage is outdated, skip", taskGroupID)
+		return
+	}
+	if newSample {
+		images, err := s.taskGroupImages(taskGroupID, usage)
+		if err != nil {
+			blog.Warn("process usage message: fetch taskgroup(%s) failed: %s", taskGroupID, err.Error())
+			s.forgetTaskGroupUsage(taskGroupID)
+			return
+		}
+		runAs, appID := store.GetRunAsAndAppIDbyTaskGroupID(taskGroupID)
+		s.recordUsageHistory(runAs, appID, images, usage)
+	}
+	if save {
+		s.saveTaskGroupUsage(taskGroupID, usage)
+	}
+	blog.V(3).Infof("process usage message: taskgroup(%s) usage cpu(%f) mem(%d) updated",
+		taskGroupID, usage.CPUUsage(), usage.MemoryUsage())
+}
@@ -0,0 +1,193 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"errors"
+	"github.com/samuel/go-zookeeper/zk"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// usage history is saved into zk at most once in this interval for each application
+const usageHistorySaveInterval = 300
+
+// getUsageHistory get usage history of application from cache, load from store at the first time.
+// caller must hold usageHistoryLock
+func (s *Scheduler) getUsageHistory(runAs, appID string) *types.AppUsageHistory {
+	key := runAs + "." + appID
+	if history, ok := s.usageHistory[key]; ok {
+		return history
+	}
+	history, err := s.store.FetchUsageHistory(runAs, appID)
+	if err != nil && err != zk.ErrNoNode {
+		blog.Warn("fetch usage history(%s) err: %s", key, err.Error())
+	}
+	if history == nil {
+		history = &types.AppUsageHistory{RunAs: runAs, AppID: appID}
+	}
+	s.usageHistory[key] = history
+	return history
+}
+
+// recordUsageHistory add usage samples of taskgroup into usage history of application,
+// images are the image repositories of tasks by task id
+func (s *Scheduler) recordUsageHistory(runAs, appID string, images map[string]string, usage *types.TaskGroupUsage) {
+	s.usageHistoryLock.Lock()
+	defer s.usageHistoryLock.Unlock()
+
+	history := s.getUsageHistory(runAs, appID)
+	for _, c := range usage.Containers {
+		//task.ID = 1536138501685462613.0.0.app-name.namespace.clusterid
+		splitID := strings.Split(c.TaskID, ".")
+		if len(splitID) < 6 {
+			continue
+		}
+		index, err := strconv.Atoi(splitID[1])
+		if err != nil {
+			continue
+		}
+		image := images[c.TaskID]
+		container := history.GetContainer(index)
+		if container != nil && container.Image != image {
+			blog.Info("usage history(%s.%s) container %d image changed %s -> %s, drop history",
+				history.RunAs, history.AppID, index, container.Image, image)
+			container = nil
+			for i, old := range history.Containers {
+				if old.Index == index {
+					history.Containers = append(history.Containers[:i], history.Containers[i+1:]...)
+					break
+				}
+			}
+		}
+		if container == nil {
+			container = types.NewContainerUsageHistory(index, image)
+			history.Containers = append(history.Containers, container)
+		}
+		container.AddUsage(c, usage.Timestamp)
+	}
+
+	now := time.Now().Unix()
+	if now-history.UpdateTime < usageHistorySaveInterval {
+		return
+	}
+	history.UpdateTime = now
+	if err := s.store.SaveUsageHistory(history); err != nil {
+		blog.Error("save usage history(%s.%s) err: %s", history.RunAs, history.AppID, err.Error())
+	}
+}
+
+// inheritUsageHistory merge usage history of old application into the new one,
+// called when deployment rolling update finished
+func (s *Scheduler) inheritUsageHistory(runAs, oldAppID, newAppID string) {
+	s.usageHistoryLock.Lock()
+	defer s.usageHistoryLock.Unlock()
+
+	oldHistory := s.getUsageHistory(runAs, oldAppID)
+	if len(oldHistory.Containers) == 0 {
+		return
+	}
+	newHistory := s.getUsageHistory(runAs, newAppID)
+	//containers not sampled yet in new application
+	for _, c := range oldHistory.Containers {
+		if newHistory.GetContainer(c.Index) == nil {
+			newHistory.Containers = append(newHistory.Containers, types.NewContainerUsageHistory(c.Index, c.Image))
+		}
+	}
+	newHistory.Merge(oldHistory)
+	newHistory.UpdateTime = time.Now().Unix()
+	if err := s.store.SaveUsageHistory(newHistory); err != nil {
+		blog.Error("save usage history(%s.%s) err: %s", runAs, newAppID, err.Error())
+		return
+	}
+	blog.Info("usage history of application(%s.%s) inherited by %s", runAs, oldAppID, newAppID)
+}
+
+// deleteUsageHistory delete usage history when application is deleted
+func (s *Scheduler) deleteUsageHistory(runAs, appID string) {
+	s.usageHistoryLock.Lock()
+	defer s.usageHistoryLock.Unlock()
+
+	delete(s.usageHistory, runAs+"."+appID)
+	if err := s.store.DeleteUsageHistory(runAs, appID); err != nil && err != zk.ErrNoNode {
+		blog.Warn("delete usage history(%s.%s) err: %s", runAs, appID, err.Error())
+	}
+}
+
+// resetUsageHistory drop all cached usage history, history is reloaded
+// from store when scheduler becomes master again
+func (s *Scheduler) resetUsageHistory() {
+	s.usageHistoryLock.Lock()
+	defer s.usageHistoryLock.Unlock()
+
+	s.usageHistory = make(map[string]*types.AppUsageHistory)
+}
+
+// GetApplicationRecommendation compute resource recommendation of application
+// from its usage history and current version
+func (s *Scheduler) GetApplicationRecommendation(runAs, appID string) (*types.ResourceRecommendation, error) {
+	version, err := s.store.GetVersion(runAs, appID)
+	if err != nil {
+		blog.Error("get recommendation: get version(%s.%s) err: %s", runAs, appID, err.Error())
+		return nil, err
+	}
+	if version == nil {
+		blog.Warn("get recommendation: version of application(%s.%s) not exist", runAs, appID)
+		return nil, errors.New("application not exist")
+	}
+
+	s.usageHistoryLock.Lock()
+	defer s.usageHistoryLock.Unlock()
+
+	history := s.getUsageHistory(runAs, appID)
+	recommend := &types.ResourceRecommendation{
+		RunAs:      runAs,
+		AppID:      appID,
+		CreateTime: time.Now().Unix(),
+	}
+	for index, container := range version.Container {
+		image := ""
+		if container.Docker != nil {
+			image = types.ImageRepository(container.Docker.Image)
+		}
+		c := history.GetContainer(index)
+		if c == nil || c.Image != image {
+			c = types.NewContainerUsageHistory(index, image)
+		}
+		recommend.Containers = append(recommend.Containers, c.Recommend(container.Resources, container.LimitResoures))
+	}
+	return recommend, nil
+}
+
+// GetDeploymentRecommendation compute resource recommendation of application bound by deployment
+func (s *Scheduler) GetDeploymentRecommendation(ns, name string) (*types.ResourceRecommendation, error) {
+	deployment, err := s.store.FetchDeployment(ns, name)
+	if err != nil {
+		blog.Error("get recommendation: fetch deployment(%s.%s) err: %s", ns, name, err.Error())
+		return nil, err
+	}
+	if deployment == nil || deployment.Application == nil {
+		blog.Warn("get recommendation: deployment(%s.%s) has no application", ns, name)
+		return nil, errors.New("deployment has no application")
+	}
+	recommend, err := s.GetApplicationRecommendation(ns, deployment.Application.ApplicationName)
+	if err != nil {
+		return nil, err
+	}
+	recommend.Deployment = name
+	return recommend, nil
+}
@@ -0,0 +1,169 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"strconv"
+	"testing"
+
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+)
+
+const mb = 1024 * 1024
+
+//newRecommendScheduler create scheduler with one application of two containers
+func newRecommendScheduler() (*Scheduler, *fakeStore) {
+	fake := newFakeStore()
+	fake.versions["ns.app"] = &types.Version{
+		RunAs: "ns",
+		Container: []*types.Container{
+			{Docker: &types.Docker{Image: "nginx:1.17"}, Resources: &types.Resource{Cpus: 2, Mem: 2048}},
+			{Docker: &types.Docker{Image: "sidecar:1.0"}, Resources: &types.Resource{Cpus: 1, Mem: 1024}},
+		},
+	}
+	fake.deployments["ns.web"] = &types.Deployment{Application: &types.DeploymentReferApplication{ApplicationName: "app"}}
+	s := &Scheduler{
+		store:        fake,
+		usageHistory: make(map[string]*types.AppUsageHistory),
+	}
+	return s, fake
+}
+
+//newUsageTaskGroup taskgroup of application with containers images
+func newUsageTaskGroup(appID string, images ...string) *types.TaskGroup {
+	taskGroup := &types.TaskGroup{ID: "0." + appID + ".ns.cluster.1536138501685462613", RunAs: "ns", AppID: appID}
+	for i, image := range images {
+		//task.ID = 1536138501685462613.0.0.app-name.namespace.clusterid
+		taskID := "1536138501685462613." + strconv.Itoa(i) + ".0." + appID + ".ns.cluster"
+		taskGroup.Taskgroup = append(taskGroup.Taskgroup, &types.Task{ID: taskID, Image: image})
+	}
+	return taskGroup
+}
+
+func recordSamples(s *Scheduler, taskGroup *types.TaskGroup, samples int, workingSet uint64) {
+	for i := 0; i < samples; i++ {
+		usage := &types.TaskGroupUsage{Timestamp: int64(i * 60)}
+		for _, task := range taskGroup.Taskgroup {
+			usage.Containers = append(usage.Containers, &types.ContainerUsage{
+				TaskID:           task.ID,
+				CPUUsage:         0.5,
+				MemoryUsage:      4 * workingSet,
+				MemoryWorkingSet: workingSet,
+			})
+		}
+		s.recordUsageHistory(taskGroup.RunAs, taskGroup.AppID, taskImages(taskGroup), usage)
+	}
+}
+
+func TestRecordUsageHistory(t *testing.T) {
+	s, fake := newRecommendScheduler()
+	taskGroup := newUsageTaskGroup("app", "nginx:1.17", "sidecar:1.0")
+	recordSamples(s, taskGroup, 10, 100*mb)
+
+	history := s.usageHistory["ns.app"]
+	if history == nil || len(history.Containers) != 2 {
+		t.Fatalf("expect history of 2 containers, got %+v", history)
+	}
+	for index, image := range []string{"nginx", "sidecar"} {
+		c := history.GetContainer(index)
+		if c == nil || c.Image != image || c.CPU.Samples != 10 {
+			t.Errorf("container %d: expect 10 samples of %s, got %+v", index, image, c)
+		}
+	}
+	//history is saved at most once in save interval
+	if fake.usageHistorySaves != 1 {
+		t.Errorf("expect usage history saved once, got %d", fake.usageHistorySaves)
+	}
+
+	//history of container is dropped when image repository changed
+	recordSamples(s, newUsageTaskGroup("app", "nginx:1.18", "sidecar-v2:1.0"), 1, 100*mb)
+	if c := history.GetContainer(0); c.CPU.Samples != 11 {
+		t.Errorf("expect history kept when only tag changed, got %d samples", c.CPU.Samples)
+	}
+	if c := history.GetContainer(1); c.Image != "sidecar-v2" || c.CPU.Samples != 1 {
+		t.Errorf("expect history dropped when image changed, got %s with %d samples", c.Image, c.CPU.Samples)
+	}
+
+	//task id without container index is ignored
+	s.recordUsageHistory(taskGroup.RunAs, taskGroup.AppID, taskImages(taskGroup), &types.TaskGroupUsage{
+		Containers: []*types.ContainerUsage{{TaskID: "invalid-task-id"}},
+	})
+	if len(history.Containers) != 2 {
+		t.Errorf("expect invalid task id ignored, got %d containers", len(history.Containers))
+	}
+}
+
+func TestGetRecommendation(t *testing.T) {
+	s, _ := newRecommendScheduler()
+	recordSamples(s, newUsageTaskGroup("app", "nginx:1.17", "other:1.0"), int(types.RecommendationMinSamples), 200*mb)
+
+	recommend, err := s.GetDeploymentRecommendation("ns", "web")
+	if err != nil {
+		t.Fatalf("get recommendation failed: %s", err.Error())
+	}
+	if recommend.Deployment != "web" || recommend.AppID != "app" || len(recommend.Containers) != 2 {
+		t.Fatalf("unexpected recommendation %+v", recommend)
+	}
+	//memory target is computed from working set, not usage including page cache
+	target := recommend.Containers[0].Target
+	if target == nil || target.Mem < 200 || target.Mem > 260 {
+		t.Errorf("expect memory target from working set 200MB, got %+v", target)
+	}
+	if recommend.Containers[0].Current.Cpus != 2 {
+		t.Errorf("expect current resource from version, got %+v", recommend.Containers[0].Current)
+	}
+	//history of other image is not used for container of version
+	if recommend.Containers[1].Target != nil || recommend.Containers[1].Samples != 0 {
+		t.Errorf("expect no recommendation for container with image changed, got %+v", recommend.Containers[1])
+	}
+
+	if _, err := s.GetApplicationRecommendation("ns", "notexist"); err == nil {
+		t.Errorf("expect error for application not exist")
+	}
+	if _, err := s.GetDeploymentRecommendation("ns", "notexist"); err == nil {
+		t.Errorf("expect error for deployment not exist")
+	}
+}
+
+func TestInheritAndDeleteUsageHistory(t *testing.T) {
+	s, fake := newRecommendScheduler()
+	recordSamples(s, newUsageTaskGroup("app", "nginx:1.17"), 5, 100*mb)
+	recordSamples(s, newUsageTaskGroup("app-v2", "nginx:1.18", "sidecar:1.0"), 3, 100*mb)
+
+	s.inheritUsageHistory("ns", "app", "app-v2")
+	history := fake.usageHistories["ns.app-v2"]
+	if history == nil {
+		t.Fatalf("expect inherited history saved, got %+v", history)
+	}
+	if c := history.GetContainer(0); c.CPU.Samples != 8 {
+		t.Errorf("expect samples merged from old application, got %d", c.CPU.Samples)
+	}
+	if c := history.GetContainer(1); c.CPU.Samples != 3 {
+		t.Errorf("expect samples of new container kept, got %d", c.CPU.Samples)
+	}
+
+	s.deleteUsageHistory("ns", "app")
+	if _, ok := s.usageHistory["ns.app"]; ok {
+		t.Errorf("expect cached history deleted")
+	}
+	if _, ok := fake.usageHistories["ns.app"]; ok {
+		t.Errorf("expect stored history deleted")
+	}
+
+	//reload from store after reset
+	s.resetUsageHistory()
+	if history := s.getUsageHistory("ns", "app-v2"); len(history.Containers) != 2 {
+		t.Errorf("expect history reloaded from store, got %+v", history)
+	}
+}
@@ -115,6 +115,28 @@ type Scheduler struct {
 	offerPool offer.OfferPool
 
 	pluginManager *pluginManager.PluginManager
+
+	//usage history of applications, key is runAs.appID
+	usageHistoryLock sync.Mutex
+	usageHistory     map[string]*types.AppUsageHistory
+
+	//latest usage of taskgroups reported by executors, key is taskgroup id
+	taskGroupUsageLock      sync.Mutex
+	taskGroupUsage          map[string]*taskGroupUsage
+	taskGroupUsagePurgeTime int64
+
+	//taskgroups disrupted recently, id -> unix seconds
+	disruptionLock      sync.Mutex
+	disruptedTaskgroups map[string]int64
+
+	//agents being drained by this scheduler
+	drainLock      sync.Mutex
+	drainingAgents map[string]bool
+	//taskgroups grouped by agent ip, scanned once per drain tick and shared by all draining agents.
+	//they are guarded by agentScanLock, so that drain and uncordon are not blocked by scanning
+	agentScanLock       sync.Mutex
+	agentTaskGroups     map[string][]*types.TaskGroup
+	agentTaskGroupsTime int64
 }
 
 // NewScheduler returns a pointer to new Scheduler
@@ -124,6 +146,11 @@ func NewScheduler(config util.Scheduler, store store.Store) *Scheduler {
 		store:        store,
 		eventManager: newBcsEventManager(config),
 		lostSlave:    make(map[string]int64),
+		usageHistory: make(map[string]*types.AppUsageHistory),
+
+		taskGroupUsage:      make(map[string]*taskGroupUsage),
+		disruptedTaskgroups: make(map[string]int64),
+		drainingAgents:      make(map[string]bool),
 	}
 
 	para := &offer.OfferPara{Sched: s}
@@ -805,6 +832,8 @@ func (s *Scheduler) checkRoleChange(currRole string) error {
 		}
 
 		s.store.UnInitCacheMgr()
+		s.resetUsageHistory()
+		s.resetTaskGroupUsage()
 
 		return nil
 	}
@@ -812,6 +841,8 @@ func (s *Scheduler) checkRoleChange(currRole string) error {
 	s.store.InitCacheMgr(s.config.UseCache)
 
 	go s.startCheckDeployments()
+	go s.startCheckAgentDrains()
+	go s.startCheckMaintenance()
 
 	if s.ServiceMgr != nil {
 		var msgOpen ServiceMgrMsg
@@ -1006,6 +1037,8 @@ func (s *Scheduler) handleEvents(resp *http.Response) {
 			switch *bcsMsg.Type {
 			case types.Msg_Res_COMMAND_TASK:
 				go s.ProcessCommandMessage(&bcsMsg)
+			case types.Msg_TASKGROUP_USAGE:
+				go s.ProcessUsageMessage(&bcsMsg)
 			default:
 				blog.Error("unknown message type(%s)", *bcsMsg.Type)
 			}
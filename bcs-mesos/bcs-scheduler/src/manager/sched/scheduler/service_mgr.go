@@ -26,9 +26,13 @@ import (
 	"github.com/samuel/go-zookeeper/zk"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 )
 
+//nodeAttributesCacheTime seconds that node attributes of agent are cached in service manager
+const nodeAttributesCacheTime = 60
+
 // Event for service manager
 type ServiceSyncData struct {
 	// TaskGroup, Service
@@ -85,6 +89,15 @@ type ServiceMgr struct {
 	sched       *Scheduler
 	msgQueue    chan *ServiceMgrMsg
 	isWork      bool
+	//node attributes of agent for endpoints, indexed by agent ip address
+	nodeAttrLock  sync.Mutex
+	nodeAttrCache map[string]*nodeAttributes
+}
+
+//nodeAttributes cached string attributes of agent setting
+type nodeAttributes struct {
+	attrs      map[string]string
+	expireTime int64
 }
 
 // Create service manager
@@ -614,6 +627,10 @@ func (mgr *ServiceMgr) syncEndpointInfo(esInfo *exportServiceInfo) {
 				blog.V(3).Infof("taskgroup(%s) status %s, do nothing ", tskgroup.ID, tskgroup.Status)
 				continue
 			}
+			if !tskgroup.IsReady() {
+				blog.V(3).Infof("taskgroup(%s) is not ready, do nothing ", tskgroup.ID)
+				continue
+			}
 
 			//label := mgr.getTaskGroupServiceLabel(esInfo.bcsService, tskgroup)
 			//if label == "" {
@@ -749,10 +766,74 @@ func (mgr *ServiceMgr) buildEndpoint(service *commtypes.BcsService, tskgroup *ty
 		}
 	}
 
+	mgr.fillEndpointStatus(podEndpoint, tskgroup)
 	blog.V(3).Infof("ServiceMgr: build taskgroup(%s) Endpoint(%+v)", tskgroup.ID, podEndpoint)
 	return podEndpoint
 }
 
+//fillEndpointStatus fill health, readiness and node attributes for endpoint,
+//bcs-dns uses them to filter unhealthy endpoints and prefer endpoints in client zone
+func (mgr *ServiceMgr) fillEndpointStatus(podEndpoint *commtypes.Endpoint, tskgroup *types.TaskGroup) {
+	//agent settings are indexed by agent ip address
+	nodeIP := ""
+	for _, oneTask := range tskgroup.Taskgroup {
+		if nodeIP == "" {
+			nodeIP = oneTask.AgentIPAddress
+		}
+		if len(oneTask.HealthChecks) > 0 {
+			healthy := oneTask.Healthy && (podEndpoint.Healthy == nil || *podEndpoint.Healthy)
+			podEndpoint.Healthy = &healthy
+		}
+		if len(oneTask.ReadinessChecks) > 0 {
+			ready := oneTask.Ready && (podEndpoint.Ready == nil || *podEndpoint.Ready)
+			podEndpoint.Ready = &ready
+		}
+	}
+
+	if nodeIP == "" {
+		nodeIP = podEndpoint.NodeIP
+	}
+	if nodeIP == "" || mgr.sched == nil {
+		return
+	}
+	podEndpoint.NodeAttributes = mgr.getNodeAttributes(nodeIP, time.Now().Unix())
+}
+
+//getNodeAttributes get string attributes of agent setting from cache,
+//agent setting is fetched from store only when cache expired, so that
+//every endpoint building does not read zookeeper
+func (mgr *ServiceMgr) getNodeAttributes(nodeIP string, now int64) map[string]string {
+	mgr.nodeAttrLock.Lock()
+	defer mgr.nodeAttrLock.Unlock()
+
+	if cached, ok := mgr.nodeAttrCache[nodeIP]; ok && cached.expireTime > now {
+		return cached.attrs
+	}
+	if mgr.nodeAttrCache == nil {
+		mgr.nodeAttrCache = make(map[string]*nodeAttributes)
+	}
+	//clean expired agents
+	for ip, cached := range mgr.nodeAttrCache {
+		if cached.expireTime <= now {
+			delete(mgr.nodeAttrCache, ip)
+		}
+	}
+
+	var attrs map[string]string
+	setting, err := mgr.sched.FetchAgentSetting(nodeIP)
+	if err != nil {
+		blog.Warn("ServiceMgr: fetch agent setting %s err: %s", nodeIP, err.Error())
+	}
+	if setting != nil && len(setting.AttrStrings) > 0 {
+		attrs = make(map[string]string, len(setting.AttrStrings))
+		for key, value := range setting.AttrStrings {
+			attrs[key] = value.Value
+		}
+	}
+	mgr.nodeAttrCache[nodeIP] = &nodeAttributes{attrs: attrs, expireTime: now + nodeAttributesCacheTime}
+	return attrs
+}
+
 func (mgr *ServiceMgr) addEndPoint(bcsEndpoint *commtypes.BcsEndpoint, endpoint *commtypes.Endpoint) bool {
 	for index, onePoint := range bcsEndpoint.Endpoints {
 		if onePoint.Target.ID == endpoint.Target.ID && onePoint.Target.Namespace == endpoint.Target.Namespace {
@@ -800,6 +881,10 @@ func (mgr *ServiceMgr) addTaskGroup(tskgroup *types.TaskGroup) {
 		blog.V(3).Infof("ServiceMgr receive taskgroup add event, TaskGroup %s status %s, do nothing ", tskgroup.ID, tskgroup.Status)
 		return
 	}
+	if !tskgroup.IsReady() {
+		blog.V(3).Infof("ServiceMgr receive taskgroup add event, TaskGroup %s is not ready, do nothing ", tskgroup.ID)
+		return
+	}
 
 	keyList := mgr.esInfoCache.ListKeys()
 	for _, key := range keyList {
@@ -883,7 +968,8 @@ func (mgr *ServiceMgr) updateTaskGroup(tskgroup *types.TaskGroup) {
 		}
 
 		var changed bool
-		if tskgroup.Status == types.TASKGROUP_STATUS_RUNNING || tskgroup.Status == types.TASKGROUP_STATUS_LOST {
+		// taskgroup which is not ready is removed from endpoints until readiness check pass
+		if (tskgroup.Status == types.TASKGROUP_STATUS_RUNNING || tskgroup.Status == types.TASKGROUP_STATUS_LOST) && tskgroup.IsReady() {
 			changed = mgr.addEndPoint(esInfo.endpoint, podEndpoint)
 		} else {
 			changed = mgr.deleteEndPoint(esInfo.endpoint, podEndpoint)
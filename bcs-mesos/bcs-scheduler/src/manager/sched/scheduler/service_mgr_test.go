@@ -0,0 +1,69 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"testing"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+)
+
+func TestFillEndpointStatusCachesNodeAttributes(t *testing.T) {
+	fake := newFakeStore()
+	fake.agentSettings = map[string]*commtypes.BcsClusterAgentSetting{
+		"127.0.0.1": {
+			InnerIP:     "127.0.0.1",
+			AttrStrings: map[string]commtypes.MesosValue_Text{"zone": {Value: "zone-a"}},
+		},
+	}
+	mgr := &ServiceMgr{sched: &Scheduler{store: fake}}
+	tskgroup := &types.TaskGroup{
+		ID:        "0.app.ns.cluster.1536138501685462613",
+		Taskgroup: []*types.Task{{ID: "task", AgentIPAddress: "127.0.0.1"}},
+	}
+
+	for i := 0; i < 3; i++ {
+		endpoint := &commtypes.Endpoint{}
+		mgr.fillEndpointStatus(endpoint, tskgroup)
+		if endpoint.NodeAttributes["zone"] != "zone-a" {
+			t.Fatalf("expect node attribute zone-a, got %v", endpoint.NodeAttributes)
+		}
+	}
+	if fake.agentSettingFetchs != 1 {
+		t.Errorf("expect agent setting fetched once, got %d", fake.agentSettingFetchs)
+	}
+
+	//agent without setting is cached too
+	now := int64(1000)
+	for i := 0; i < 3; i++ {
+		if attrs := mgr.getNodeAttributes("127.0.0.2", now); attrs != nil {
+			t.Fatalf("expect no attributes, got %v", attrs)
+		}
+	}
+	if fake.agentSettingFetchs != 2 {
+		t.Errorf("expect agent setting fetched twice, got %d", fake.agentSettingFetchs)
+	}
+
+	//fetch again after cache expired
+	fake.agentSettings["127.0.0.2"] = &commtypes.BcsClusterAgentSetting{
+		AttrStrings: map[string]commtypes.MesosValue_Text{"zone": {Value: "zone-b"}},
+	}
+	if attrs := mgr.getNodeAttributes("127.0.0.2", now+nodeAttributesCacheTime); attrs["zone"] != "zone-b" {
+		t.Errorf("expect node attribute zone-b after cache expired, got %v", attrs)
+	}
+	if len(mgr.nodeAttrCache) != 2 || mgr.nodeAttrCache["127.0.0.2"].expireTime != now+2*nodeAttributesCacheTime {
+		t.Errorf("expect agent cache refreshed, got %v", mgr.nodeAttrCache)
+	}
+}
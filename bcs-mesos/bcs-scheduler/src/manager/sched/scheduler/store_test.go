@@ -0,0 +1,258 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"errors"
+	"sync"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/store"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+
+	"github.com/samuel/go-zookeeper/zk"
+)
+
+// fakeStore keeps taskgroups in memory for scheduler tests, the methods not implemented panic
+type fakeStore struct {
+	store.Store
+
+	sync.Mutex
+	taskGroups         map[string]*types.TaskGroup
+	taskGroupSaves     int
+	taskGroupFetchs    int
+	agentSettings      map[string]*commtypes.BcsClusterAgentSetting
+	agentSettingFetchs int
+	versions           map[string]*types.Version
+	deployments        map[string]*types.Deployment
+	usageHistories     map[string]*types.AppUsageHistory
+	usageHistorySaves  int
+	applications       map[string]*types.Application
+	budgets            []*commtypes.BcsDisruptionBudget
+	deploymentSaves    int
+	agentDrains        map[string]*commtypes.BcsAgentDrain
+	runAsLists         int
+	taskGroupLists     int
+}
+
+func newFakeStore(taskGroups ...*types.TaskGroup) *fakeStore {
+	f := &fakeStore{
+		taskGroups:     make(map[string]*types.TaskGroup),
+		versions:       make(map[string]*types.Version),
+		deployments:    make(map[string]*types.Deployment),
+		usageHistories: make(map[string]*types.AppUsageHistory),
+		applications:   make(map[string]*types.Application),
+	}
+	for _, taskGroup := range taskGroups {
+		f.taskGroups[taskGroup.ID] = taskGroup
+	}
+	return f
+}
+
+func (f *fakeStore) LockApplication(appID string)   {}
+func (f *fakeStore) UnLockApplication(appID string) {}
+
+func (f *fakeStore) FetchTaskGroup(taskGroupID string) (*types.TaskGroup, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.taskGroupFetchs++
+	taskGroup, ok := f.taskGroups[taskGroupID]
+	if !ok {
+		return nil, zk.ErrNoNode
+	}
+	return taskGroup, nil
+}
+
+func (f *fakeStore) SaveTaskGroup(taskGroup *types.TaskGroup) error {
+	f.Lock()
+	defer f.Unlock()
+	f.taskGroups[taskGroup.ID] = taskGroup
+	f.taskGroupSaves++
+	return nil
+}
+
+func (f *fakeStore) FetchUsageHistory(runAs, appID string) (*types.AppUsageHistory, error) {
+	f.Lock()
+	defer f.Unlock()
+	history, ok := f.usageHistories[runAs+"."+appID]
+	if !ok {
+		return nil, zk.ErrNoNode
+	}
+	return history, nil
+}
+
+func (f *fakeStore) SaveUsageHistory(history *types.AppUsageHistory) error {
+	f.Lock()
+	defer f.Unlock()
+	f.usageHistories[history.RunAs+"."+history.AppID] = history
+	f.usageHistorySaves++
+	return nil
+}
+
+func (f *fakeStore) DeleteUsageHistory(runAs, appID string) error {
+	f.Lock()
+	defer f.Unlock()
+	if _, ok := f.usageHistories[runAs+"."+appID]; !ok {
+		return zk.ErrNoNode
+	}
+	delete(f.usageHistories, runAs+"."+appID)
+	return nil
+}
+
+func (f *fakeStore) GetVersion(runAs, appID string) (*types.Version, error) {
+	f.Lock()
+	defer f.Unlock()
+	return f.versions[runAs+"."+appID], nil
+}
+
+func (f *fakeStore) FetchDeployment(ns, name string) (*types.Deployment, error) {
+	f.Lock()
+	defer f.Unlock()
+	deployment, ok := f.deployments[ns+"."+name]
+	if !ok {
+		return nil, zk.ErrNoNode
+	}
+	return deployment, nil
+}
+
+func (f *fakeStore) FetchAgentSetting(ip string) (*commtypes.BcsClusterAgentSetting, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.agentSettingFetchs++
+	return f.agentSettings[ip], nil
+}
+
+func (f *fakeStore) SaveAgentSetting(setting *commtypes.BcsClusterAgentSetting) error {
+	f.Lock()
+	defer f.Unlock()
+	if f.agentSettings == nil {
+		f.agentSettings = make(map[string]*commtypes.BcsClusterAgentSetting)
+	}
+	f.agentSettings[setting.InnerIP] = setting
+	return nil
+}
+
+func (f *fakeStore) FetchAgentDrain(ip string) (*commtypes.BcsAgentDrain, error) {
+	f.Lock()
+	defer f.Unlock()
+	return f.agentDrains[ip], nil
+}
+
+func (f *fakeStore) SaveAgentDrain(drain *commtypes.BcsAgentDrain) error {
+	f.Lock()
+	defer f.Unlock()
+	if f.agentDrains == nil {
+		f.agentDrains = make(map[string]*commtypes.BcsAgentDrain)
+	}
+	f.agentDrains[drain.InnerIP] = drain
+	return nil
+}
+
+func (f *fakeStore) DeleteAgentDrain(ip string) error {
+	f.Lock()
+	defer f.Unlock()
+	delete(f.agentDrains, ip)
+	return nil
+}
+
+func (f *fakeStore) SaveDeployment(deployment *types.Deployment) error {
+	f.Lock()
+	defer f.Unlock()
+	f.deployments[deployment.ObjectMeta.NameSpace+"."+deployment.ObjectMeta.Name] = deployment
+	f.deploymentSaves++
+	return nil
+}
+
+func (f *fakeStore) FetchApplication(runAs, appID string) (*types.Application, error) {
+	f.Lock()
+	defer f.Unlock()
+	app, ok := f.applications[runAs+"."+appID]
+	if !ok {
+		return nil, zk.ErrNoNode
+	}
+	return app, nil
+}
+
+func (f *fakeStore) ListApplications(runAs string) ([]*types.Application, error) {
+	f.Lock()
+	defer f.Unlock()
+	var apps []*types.Application
+	for _, app := range f.applications {
+		if app.RunAs == runAs {
+			apps = append(apps, app)
+		}
+	}
+	return apps, nil
+}
+
+func (f *fakeStore) ListRunAs() ([]string, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.runAsLists++
+	runAses := make(map[string]bool)
+	for _, taskGroup := range f.taskGroups {
+		runAses[taskGroup.RunAs] = true
+	}
+	var list []string
+	for runAs := range runAses {
+		list = append(list, runAs)
+	}
+	return list, nil
+}
+
+func (f *fakeStore) ListApplicationNodes(runAs string) ([]string, error) {
+	f.Lock()
+	defer f.Unlock()
+	appIDs := make(map[string]bool)
+	for _, taskGroup := range f.taskGroups {
+		if taskGroup.RunAs == runAs {
+			appIDs[taskGroup.AppID] = true
+		}
+	}
+	var list []string
+	for appID := range appIDs {
+		list = append(list, appID)
+	}
+	return list, nil
+}
+
+func (f *fakeStore) ListTaskGroups(runAs, appID string) ([]*types.TaskGroup, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.taskGroupLists++
+	var taskGroups []*types.TaskGroup
+	for _, taskGroup := range f.taskGroups {
+		if taskGroup.RunAs == runAs && taskGroup.AppID == appID {
+			taskGroups = append(taskGroups, taskGroup)
+		}
+	}
+	return taskGroups, nil
+}
+
+// ListVersions fails so that scaling application does nothing in tests
+func (f *fakeStore) ListVersions(runAs, appID string) ([]string, error) {
+	return nil, errors.New("versions not supported in fake store")
+}
+
+func (f *fakeStore) ListDisruptionBudgets(ns string) ([]*commtypes.BcsDisruptionBudget, error) {
+	f.Lock()
+	defer f.Unlock()
+	var budgets []*commtypes.BcsDisruptionBudget
+	for _, budget := range f.budgets {
+		if budget.ObjectMeta.NameSpace == ns {
+			budgets = append(budgets, budget)
+		}
+	}
+	return budgets, nil
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"github.com/golang/protobuf/proto"
 	"net/http"
+	"sort"
 	"time"
 )
 
@@ -284,9 +285,51 @@ func (s *Scheduler) IsOfferResourceFitUpdate(needResource *types.Resource, outOf
 	return false
 }
 
-// Check whether the offer match version constraints
-func (s *Scheduler) IsConstraintsFit(version *types.Version, offer *mesos.Offer, taskgroupID string) bool {
+// Check whether the offer match version constraints, topology is built by GetPreferredOffers
+// or SortPreferredOffers for this pass over offers
+func (s *Scheduler) IsConstraintsFit(version *types.Version, offer *mesos.Offer, taskgroupID string, topology *strategy.Topology) bool {
 
 	isFit, _ := strategy.ConstraintsFit(version, offer, s.store, taskgroupID)
-	return isFit
+	if !isFit || !version.Constraints.HasTopology() {
+		return isFit
+	}
+
+	if topology == nil {
+		blog.Warnf("no topology for version(%s.%s), offer from %s not fit", version.RunAs, version.ID, offer.GetHostname())
+		return false
+	}
+	return topology.Fit(offer)
+}
+
+// Get offers in pool for version ordered by soft constraints of version, with the topology of version
+// to check the offers by IsConstraintsFit
+func (s *Scheduler) GetPreferredOffers(version *types.Version, taskgroupID string) ([]*offer.Offer, *strategy.Topology) {
+	return s.SortPreferredOffers(version, taskgroupID, s.GetAllOffers())
+}
+
+// Build topology of version once and sort offers by score of soft constraints by it,
+// offers with the same score keep the order of offer pool.
+// Sort again after one offer is used, because the distribution of taskgroups changes
+func (s *Scheduler) SortPreferredOffers(version *types.Version, taskgroupID string, offers []*offer.Offer) ([]*offer.Offer, *strategy.Topology) {
+	constraints := version.Constraints
+	if !constraints.HasTopology() && !constraints.HasPreference() {
+		return offers, nil
+	}
+
+	topology, err := strategy.NewTopology(version, s.GetCurrentOffers(), s.store, taskgroupID)
+	if err != nil {
+		blog.Warnf("build topology for version(%s.%s) err: %s, offers not sorted", version.RunAs, version.ID, err.Error())
+		return offers, nil
+	}
+	if len(offers) <= 1 || !constraints.HasPreference() {
+		return offers, topology
+	}
+	scores := make(map[*offer.Offer]int, len(offers))
+	for _, o := range offers {
+		scores[o] = topology.Score(o.Offer)
+	}
+	sort.SliceStable(offers, func(i, j int) bool {
+		return scores[offers[i]] > scores[offers[j]]
+	})
+	return offers, topology
 }
@@ -0,0 +1,71 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"testing"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/sched/strategy"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+
+	"github.com/golang/protobuf/proto"
+)
+
+func TestIsConstraintsFitWithTopology(t *testing.T) {
+	fake := newFakeStore(&types.TaskGroup{
+		ID:       "0.web.ns.cluster.1536138501685462613",
+		RunAs:    "ns",
+		AppID:    "web",
+		HostName: "host-a",
+		Status:   types.TASKGROUP_STATUS_RUNNING,
+	})
+	s := &Scheduler{store: fake}
+	version := &types.Version{
+		ID:    "web",
+		RunAs: "ns",
+		Constraints: &commtypes.Constraint{
+			TopologySpreadConstraints: []*commtypes.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: commtypes.TopologyKey_Hostname},
+			},
+		},
+	}
+	offers := []*mesos.Offer{{Hostname: proto.String("host-a")}, {Hostname: proto.String("host-b")}}
+
+	topology, err := strategy.NewTopology(version, offers, fake, "")
+	if err != nil {
+		t.Fatalf("build topology err: %s", err.Error())
+	}
+	lists := fake.taskGroupLists
+	tests := []struct {
+		offer    *mesos.Offer
+		topology *strategy.Topology
+		fit      bool
+	}{
+		{offers[0], topology, false},
+		{offers[1], topology, true},
+		//topology failed to build
+		{offers[1], nil, false},
+	}
+	for i, test := range tests {
+		if fit := s.IsConstraintsFit(version, test.offer, "", test.topology); fit != test.fit {
+			t.Errorf("test %d: expect offer from %s fit %t, got %t", i, test.offer.GetHostname(), test.fit, fit)
+		}
+	}
+	//offers are checked by the topology of this pass without listing taskgroups again
+	if fake.taskGroupLists != lists {
+		t.Errorf("expect taskgroups not listed when checking offers, got %d lists", fake.taskGroupLists-lists)
+	}
+}
@@ -135,6 +135,7 @@ func (s *Scheduler) doDeleteAppTrans(trans *Transaction) bool {
 	if err != nil {
 		blog.Error("transaction %s delete application(%s.%s) failed: %s", trans.ID, runAs, appID, err.Error())
 	}
+	s.deleteUsageHistory(runAs, appID)
 
 	blog.Info("app transaction %s delete application(%s.%s) finish", trans.ID, runAs, appID)
 	trans.Status = types.OPERATION_STATUS_FINISH
@@ -51,16 +51,16 @@ func (s *Scheduler) RunLaunchApplication(transaction *Transaction) {
 		opData := transaction.OpData.(*TransAPILaunchOpdata)
 		version := opData.Version
 
-		offerOut := s.GetFirstOffer()
-		for offerOut != nil {
-			offerIdx := offerOut.Id
-			offer := offerOut.Offer
+		offers, topology := s.GetPreferredOffers(version, "")
+		for len(offers) > 0 {
+			curOffer := offers[0]
+			offers = offers[1:]
+			offerIdx := curOffer.Id
+			offer := curOffer.Offer
 			blog.V(3).Infof("transaction %s get offer(%d) %s||%s ", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
 
-			curOffer := offerOut
-			offerOut = s.GetNextOffer(offerOut)
 			//isFit := s.IsResourceFit(opData.NeedResource, offer) && s.IsConstraintsFit(version, offer, "")
-			isFit := s.IsOfferResourceFitLaunch(opData.NeedResource, curOffer) && s.IsConstraintsFit(version, offer, "")
+			isFit := s.IsOfferResourceFitLaunch(opData.NeedResource, curOffer) && s.IsConstraintsFit(version, offer, "", topology)
 			if isFit == true {
 				blog.V(3).Infof("transaction %s fit offer(%d) %s||%s ", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
 				if s.UseOffer(curOffer) == true {
@@ -70,6 +70,7 @@ func (s *Scheduler) RunLaunchApplication(transaction *Transaction) {
 						blog.Infof("transaction %s launch(%s.%s) end", transaction.ID, runAs, appID)
 						goto run_end
 					}
+					offers, topology = s.SortPreferredOffers(version, "", offers)
 					//time.Sleep(1 * time.Second)
 				} else {
 					blog.Info("transaction %s use offer(%d) %s||%s fail", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
@@ -81,16 +81,14 @@ func (s *Scheduler) RunRescheduleTaskgroup(transaction *Transaction) {
 			hostRetain = true
 		}
 
-		offerOut := s.GetFirstOffer()
-		for offerOut != nil {
-			offerIdx := offerOut.Id
-			offer := offerOut.Offer
+		offers, topology := s.GetPreferredOffers(version, taskGroupID)
+		for _, curOffer := range offers {
+			offerIdx := curOffer.Id
+			offer := curOffer.Offer
 			blog.V(3).Infof("transaction %s get offer(%d) %s||%s ", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
 
-			curOffer := offerOut
-			offerOut = s.GetNextOffer(offerOut)
 			if hostRetain == false || offer.GetHostname() == opData.HostRetain {
-				isFit := s.IsOfferResourceFitLaunch(opData.NeedResource, curOffer) && s.IsConstraintsFit(version, offer, taskGroupID)
+				isFit := s.IsOfferResourceFitLaunch(opData.NeedResource, curOffer) && s.IsConstraintsFit(version, offer, taskGroupID, topology)
 				if isFit == true {
 					blog.V(3).Infof("transaction %s fit offer(%d) %s||%s ", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
 					if s.UseOffer(curOffer) == true {
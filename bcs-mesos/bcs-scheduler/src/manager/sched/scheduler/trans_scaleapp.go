@@ -56,14 +56,14 @@ func (s *Scheduler) RunScaleApplication(transaction *Transaction) {
 				goto run_end
 			}
 		} else {
-			offerOut := s.GetFirstOffer()
-			for offerOut != nil {
-				offer := offerOut.Offer
+			offers, topology := s.GetPreferredOffers(version, "")
+			for len(offers) > 0 {
+				curOffer := offers[0]
+				offers = offers[1:]
+				offer := curOffer.Offer
 
-				curOffer := offerOut
-				offerOut = s.GetNextOffer(offerOut)
 				blog.V(3).Infof("transaction %s get offer %s||%s ", transaction.ID, offer.GetHostname(), *(offer.Id.Value))
-				isFit := s.IsOfferResourceFitLaunch(opData.NeedResource, curOffer) && s.IsConstraintsFit(version, offer, "")
+				isFit := s.IsOfferResourceFitLaunch(opData.NeedResource, curOffer) && s.IsConstraintsFit(version, offer, "", topology)
 				if isFit == true {
 					blog.V(3).Infof("transaction %s fit offer %s||%s ", transaction.ID, offer.GetHostname(), *(offer.Id.Value))
 					if s.UseOffer(curOffer) == true {
@@ -73,6 +73,7 @@ func (s *Scheduler) RunScaleApplication(transaction *Transaction) {
 							blog.Infof("transaction %s scaleup(%s.%s) finish", transaction.ID, runAs, appID)
 							goto run_end
 						}
+						offers, topology = s.SortPreferredOffers(version, "", offers)
 					} else {
 						blog.Info("transaction %s use offer %s||%s fail", transaction.ID, offer.GetHostname(), *(offer.Id.Value))
 					}
@@ -129,15 +130,15 @@ func (s *Scheduler) RunInnerScaleApplication(transaction *Transaction) {
 				goto run_end
 			}
 		} else {
-			offerOut := s.GetFirstOffer()
-			for offerOut != nil {
-				offerIdx := offerOut.Id
-				offer := offerOut.Offer
+			offers, topology := s.GetPreferredOffers(version, "")
+			for len(offers) > 0 {
+				curOffer := offers[0]
+				offers = offers[1:]
+				offerIdx := curOffer.Id
+				offer := curOffer.Offer
 
-				curOffer := offerOut
-				offerOut = s.GetNextOffer(offerOut)
 				blog.V(3).Infof("transaction %s get offer(%d) %s||%s ", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
-				isFit := s.IsOfferResourceFitLaunch(opData.NeedResource, curOffer) && s.IsConstraintsFit(version, offer, "")
+				isFit := s.IsOfferResourceFitLaunch(opData.NeedResource, curOffer) && s.IsConstraintsFit(version, offer, "", topology)
 				if isFit == true {
 					blog.V(3).Infof("transaction %s fit offer(%d) %s||%s ", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
 					if s.UseOffer(curOffer) == true {
@@ -147,6 +148,7 @@ func (s *Scheduler) RunInnerScaleApplication(transaction *Transaction) {
 							blog.Infof("transaction %s innerscaleup(%s.%s) end", transaction.ID, runAs, appID)
 							goto run_end
 						}
+						offers, topology = s.SortPreferredOffers(version, "", offers)
 					} else {
 						blog.Info("transaction %s use offer(%d) %s||%s fail", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
 					}
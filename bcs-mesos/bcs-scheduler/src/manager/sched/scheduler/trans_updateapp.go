@@ -49,18 +49,18 @@ func (s *Scheduler) RunUpdateApplication(transaction *Transaction) {
 		//check doing
 		opData := transaction.OpData.(*TransAPIUpdateOpdata)
 		version := opData.Version
-		offerOut := s.GetFirstOffer()
 
 		taskGroupID := opData.Taskgroups[opData.LaunchedNum].ID
-		for offerOut != nil {
-			offerIdx := offerOut.Id
-			offer := offerOut.Offer
+		offers, topology := s.GetPreferredOffers(version, taskGroupID)
+		for len(offers) > 0 {
+			curOffer := offers[0]
+			offers = offers[1:]
+			offerIdx := curOffer.Id
+			offer := curOffer.Offer
 
-			curOffer := offerOut
-			offerOut = s.GetNextOffer(offerOut)
 			blog.V(3).Infof("transaction %s get offer(%d) %s||%s ", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
 
-			isFit := s.IsOfferResourceFitLaunch(opData.NeedResource, curOffer) && s.IsConstraintsFit(version, offer, taskGroupID)
+			isFit := s.IsOfferResourceFitLaunch(opData.NeedResource, curOffer) && s.IsConstraintsFit(version, offer, taskGroupID, topology)
 			if isFit == true {
 				blog.V(3).Infof("transaction %s fit offer(%d) %s||%s ", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
 				if s.UseOffer(curOffer) == true {
@@ -70,6 +70,7 @@ func (s *Scheduler) RunUpdateApplication(transaction *Transaction) {
 						blog.Infof("transaction %s update(%s.%s) finish", transaction.ID, runAs, appID)
 						goto run_end
 					}
+					offers, topology = s.SortPreferredOffers(version, taskGroupID, offers)
 				} else {
 					blog.Infof("transaction %s use offer(%d) %s||%s fail", transaction.ID, offerIdx, offer.GetHostname(), *(offer.Id.Value))
 				}
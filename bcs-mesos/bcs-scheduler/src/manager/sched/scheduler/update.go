@@ -138,6 +138,10 @@ func (s *Scheduler) StatusReport(status *mesos.TaskStatus) {
 			bcsMsg = containerInfo.BcsMessage
 			task.IsChecked = containerInfo.IsChecked
 			task.ConsecutiveFailureTimes = uint32(containerInfo.ConsecutiveFailureTimes)
+			if len(task.ReadinessChecks) > 0 && task.Ready != containerInfo.Ready {
+				blog.Infof("status report: Task(%s) ready changed to %t", taskId, containerInfo.Ready)
+				task.Ready = containerInfo.Ready
+			}
 		}
 	}
 	if oldData != "" && task.StatusData == "" {
@@ -0,0 +1,153 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"fmt"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/store"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+)
+
+// usage of taskgroup is saved into zk at most once in this interval, usage not reported in
+// taskGroupUsageExpire is dropped from memory
+const (
+	taskGroupUsageSaveInterval = 300
+	taskGroupUsageExpire       = 3 * taskGroupUsageSaveInterval
+)
+
+// taskGroupUsage is the latest usage of taskgroup in memory and the time it is saved into taskgroup,
+// images of tasks are cached for usage history, so that taskgroup is not fetched for every sample
+type taskGroupUsage struct {
+	usage   *types.TaskGroupUsage
+	savedAt int64
+	images  map[string]string
+}
+
+// updateTaskGroupUsage keeps the latest usage of taskgroup in memory. It returns whether the usage is a new sample
+// for usage history, and whether it should be saved into taskgroup, both are false for outdated usage.
+func (s *Scheduler) updateTaskGroupUsage(taskGroupID string, usage *types.TaskGroupUsage, now int64) (bool, bool) {
+	s.taskGroupUsageLock.Lock()
+	defer s.taskGroupUsageLock.Unlock()
+
+	if now-s.taskGroupUsagePurgeTime >= taskGroupUsageSaveInterval {
+		s.taskGroupUsagePurgeTime = now
+		for id, latest := range s.taskGroupUsage {
+			if now-latest.usage.Timestamp >= taskGroupUsageExpire {
+				delete(s.taskGroupUsage, id)
+			}
+		}
+	}
+
+	latest, ok := s.taskGroupUsage[taskGroupID]
+	if !ok {
+		latest = &taskGroupUsage{usage: usage}
+		s.taskGroupUsage[taskGroupID] = latest
+	} else if latest.usage.Timestamp > usage.Timestamp {
+		return false, false
+	}
+	newSample := !ok || latest.usage.Timestamp < usage.Timestamp
+	latest.usage = usage
+
+	save := now-latest.savedAt >= taskGroupUsageSaveInterval
+	if save {
+		latest.savedAt = now
+	}
+	return newSample, save
+}
+
+// taskGroupImages returns image repositories of the tasks in usage by task id. Images are cached with the usage
+// of taskgroup, taskgroup is fetched again only when a task is not cached, e.g. the taskgroup is relaunched
+func (s *Scheduler) taskGroupImages(taskGroupID string, usage *types.TaskGroupUsage) (map[string]string, error) {
+	s.taskGroupUsageLock.Lock()
+	latest, ok := s.taskGroupUsage[taskGroupID]
+	if ok && latest.images != nil {
+		images := latest.images
+		cached := true
+		for _, c := range usage.Containers {
+			if _, ok := images[c.TaskID]; !ok {
+				cached = false
+				break
+			}
+		}
+		if cached {
+			s.taskGroupUsageLock.Unlock()
+			return images, nil
+		}
+	}
+	s.taskGroupUsageLock.Unlock()
+
+	taskGroup, err := s.store.FetchTaskGroup(taskGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if taskGroup == nil {
+		return nil, fmt.Errorf("taskgroup(%s) not exist", taskGroupID)
+	}
+	images := taskImages(taskGroup)
+
+	s.taskGroupUsageLock.Lock()
+	defer s.taskGroupUsageLock.Unlock()
+	if latest, ok := s.taskGroupUsage[taskGroupID]; ok {
+		latest.images = images
+	}
+	return images, nil
+}
+
+// taskImages returns image repositories of tasks in taskgroup by task id
+func taskImages(taskGroup *types.TaskGroup) map[string]string {
+	images := make(map[string]string, len(taskGroup.Taskgroup))
+	for _, task := range taskGroup.Taskgroup {
+		images[task.ID] = types.ImageRepository(task.Image)
+	}
+	return images
+}
+
+// forgetTaskGroupUsage drops the usage of taskgroup which does not exist any more
+func (s *Scheduler) forgetTaskGroupUsage(taskGroupID string) {
+	s.taskGroupUsageLock.Lock()
+	defer s.taskGroupUsageLock.Unlock()
+
+	delete(s.taskGroupUsage, taskGroupID)
+}
+
+// resetTaskGroupUsage drops all usage in memory, usage is reported again by executors
+func (s *Scheduler) resetTaskGroupUsage() {
+	s.taskGroupUsageLock.Lock()
+	defer s.taskGroupUsageLock.Unlock()
+
+	s.taskGroupUsage = make(map[string]*taskGroupUsage)
+	s.taskGroupUsagePurgeTime = 0
+}
+
+// saveTaskGroupUsage saves the usage into taskgroup, so that it can be seen by the clients of taskgroup
+func (s *Scheduler) saveTaskGroupUsage(taskGroupID string, usage *types.TaskGroupUsage) {
+	runAs, appID := store.GetRunAsAndAppIDbyTaskGroupID(taskGroupID)
+	s.store.LockApplication(runAs + "." + appID)
+	defer s.store.UnLockApplication(runAs + "." + appID)
+
+	taskGroup, err := s.store.FetchTaskGroup(taskGroupID)
+	if err != nil || taskGroup == nil {
+		blog.Warn("save usage: fetch taskgroup(%s) failed", taskGroupID)
+		return
+	}
+	if taskGroup.Usage != nil && taskGroup.Usage.Timestamp >= usage.Timestamp {
+		return
+	}
+	taskGroup.Usage = usage
+	if err := s.store.SaveTaskGroup(taskGroup); err != nil {
+		blog.Error("save usage: save taskgroup(%s) err: %s", taskGroupID, err.Error())
+	}
+}
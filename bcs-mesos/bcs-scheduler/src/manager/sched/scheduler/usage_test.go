@@ -0,0 +1,108 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+
+	"github.com/golang/protobuf/proto"
+)
+
+func TestUpdateTaskGroupUsage(t *testing.T) {
+	s := &Scheduler{taskGroupUsage: make(map[string]*taskGroupUsage)}
+	now := int64(10000)
+	steps := []struct {
+		name      string
+		id        string
+		timestamp int64
+		now       int64
+		newSample bool
+		save      bool
+	}{
+		{name: "first usage", id: "tg1", timestamp: now, now: now, newSample: true, save: true},
+		{name: "next sample throttled", id: "tg1", timestamp: now + 30, now: now + 30, newSample: true},
+		{name: "same sample", id: "tg1", timestamp: now + 30, now: now + 31},
+		{name: "outdated sample", id: "tg1", timestamp: now, now: now + 32},
+		{name: "other taskgroup", id: "tg2", timestamp: now + 30, now: now + 40, newSample: true, save: true},
+		{name: "save interval reached", id: "tg1", timestamp: now + 300, now: now + taskGroupUsageSaveInterval, newSample: true, save: true},
+	}
+	for _, step := range steps {
+		usage := &types.TaskGroupUsage{Timestamp: step.timestamp}
+		newSample, save := s.updateTaskGroupUsage(step.id, usage, step.now)
+		if newSample != step.newSample || save != step.save {
+			t.Errorf("%s: updateTaskGroupUsage() = %v, %v, want %v, %v", step.name, newSample, save, step.newSample, step.save)
+		}
+	}
+
+	// tg2 is not reported any more and dropped at purge
+	s.updateTaskGroupUsage("tg1", &types.TaskGroupUsage{Timestamp: now + 800}, now+800)
+	if _, ok := s.taskGroupUsage["tg2"]; !ok {
+		t.Fatalf("tg2 should be kept before expired")
+	}
+	s.updateTaskGroupUsage("tg1", &types.TaskGroupUsage{Timestamp: now + 1400}, now+1400)
+	if _, ok := s.taskGroupUsage["tg2"]; ok {
+		t.Errorf("tg2 should be purged after expired")
+	}
+}
+
+func TestProcessUsageMessageThrottlesSave(t *testing.T) {
+	//task.ID = 1536138501685462613.0.0.app-name.namespace.clusterid
+	taskID := "1536138501685462613.0.0.app.ns.cluster"
+	taskGroupID := "0.app.ns.cluster.1536138501685462613"
+	taskGroup := &types.TaskGroup{
+		ID:        taskGroupID,
+		RunAs:     "ns",
+		AppID:     "app",
+		Taskgroup: []*types.Task{{ID: taskID, Image: "nginx:1.0"}},
+	}
+	fake := newFakeStore(taskGroup)
+	s := &Scheduler{
+		store:          fake,
+		usageHistory:   make(map[string]*types.AppUsageHistory),
+		taskGroupUsage: make(map[string]*taskGroupUsage),
+	}
+
+	start := time.Now().Unix()
+	for i := int64(0); i < 10; i++ {
+		s.ProcessUsageMessage(&types.BcsMessage{
+			TaskID: &mesos.TaskID{Value: proto.String(taskID)},
+			TaskGroupUsage: &types.TaskGroupUsage{
+				Timestamp:  start + i*30,
+				Containers: []*types.ContainerUsage{{TaskID: taskID, CPUUsage: 0.5, MemoryUsage: 64 << 20}},
+			},
+		})
+	}
+
+	if fake.taskGroupSaves != 1 {
+		t.Errorf("expect taskgroup saved once in save interval, got %d", fake.taskGroupSaves)
+	}
+	//taskgroup is fetched once for images of usage history and once for saving usage
+	if fake.taskGroupFetchs != 2 {
+		t.Errorf("expect taskgroup fetched twice, got %d", fake.taskGroupFetchs)
+	}
+	if taskGroup.Usage == nil || taskGroup.Usage.Timestamp != start {
+		t.Errorf("expect first usage saved into taskgroup, got %+v", taskGroup.Usage)
+	}
+	history := s.usageHistory["ns.app"]
+	if history == nil || len(history.Containers) != 1 || history.Containers[0].CPU.Samples != 10 {
+		t.Fatalf("expect all 10 samples in usage history, got %+v", history)
+	}
+	if latest := s.taskGroupUsage[taskGroupID]; latest == nil || latest.usage.Timestamp != start+270 {
+		t.Errorf("expect latest usage kept in memory, got %+v", latest)
+	}
+}
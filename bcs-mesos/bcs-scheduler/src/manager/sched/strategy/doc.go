@@ -14,7 +14,8 @@
 /*
 Package strategy provides schedule constraints implements.
 
-Function ConstraintsFit is the only external interface
+Function ConstraintsFit checks constraints of one offer, and Topology built by NewTopology
+checks topology spread constraints and affinity terms, and scores offers by soft constraints
 
 Currently, constraints include:
 LIKE
@@ -25,5 +26,10 @@ GROUPBY
 CLUSTER
 GREATER
 EXCLUDE
+
+Topology constraints include:
+topologySpreadConstraints(DoNotSchedule, ScheduleAnyway)
+preferred(weighted soft constraints)
+affinity and antiAffinity(required and preferred terms by application labels)
 */
 package strategy
@@ -0,0 +1,322 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package strategy
+
+import (
+	"bk-bcs/bcs-common/common/blog"
+	commtypes "bk-bcs/bcs-common/common/types"
+	offerP "bk-bcs/bcs-mesos/bcs-scheduler/src/manager/sched/offer"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/manager/store"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"strconv"
+)
+
+//score subtracted for each taskgroup more than the domain with least taskgroups,
+//it is the max weight of preferred constraints, so spreading wins one preferred constraint
+const spreadScoreWeight = 100
+
+//Topology is taskgroups distribution in topology domains for the constraints of one version,
+//build it once by NewTopology, then use it to check and score offers
+type Topology struct {
+	version  *types.Version
+	store    store.Store
+	spreads  []*spreadDomains
+	affinity []*affinityDomains
+}
+
+//spreadDomains taskgroups number of every domain for one topology spread constraint
+type spreadDomains struct {
+	constraint *commtypes.TopologySpreadConstraint
+	counts     map[string]int
+}
+
+//affinityDomains taskgroups number of every domain for one affinity or anti-affinity term
+type affinityDomains struct {
+	term *commtypes.AffinityTerm
+	//0 for required term
+	weight int
+	anti   bool
+	//application of version itself is selected by term
+	self   bool
+	counts map[string]int
+	total  int
+}
+
+//NewTopology collects taskgroups referred by topology spread constraints and affinity terms of version.
+//offers are the offers in pool at the moment, they define the topology domains together with taskgroups.
+//taskgroupID is the taskgroup to be replaced in reschedule or update, it is not counted
+func NewTopology(version *types.Version, offers []*mesos.Offer, store store.Store, taskgroupID string) (*Topology, error) {
+	topology := &Topology{
+		version: version,
+		store:   store,
+	}
+	constraints := version.Constraints
+	if constraints == nil {
+		return topology, nil
+	}
+
+	for _, spread := range constraints.TopologySpreadConstraints {
+		if spread == nil {
+			continue
+		}
+		taskGroups, err := listSelectedTaskGroups(version, spread.LabelSelector, nil, store, taskgroupID)
+		if err != nil {
+			return nil, err
+		}
+		counts := make(map[string]int)
+		for _, offer := range offers {
+			if domain := offerTopologyValue(offer, spread.TopologyKey); domain != "" {
+				counts[domain] = 0
+			}
+		}
+		for _, taskGroup := range taskGroups {
+			if domain := taskGroupTopologyValue(taskGroup, spread.TopologyKey); domain != "" {
+				counts[domain]++
+			}
+		}
+		blog.V(3).Infof("topology spread(%s) of version(%s.%s): %v", spread.TopologyKey, version.RunAs, version.ID, counts)
+		topology.spreads = append(topology.spreads, &spreadDomains{constraint: spread, counts: counts})
+	}
+
+	for _, anti := range []bool{false, true} {
+		affinity := constraints.Affinity
+		if anti {
+			affinity = constraints.AntiAffinity
+		}
+		if affinity == nil {
+			continue
+		}
+		for _, term := range affinity.Required {
+			domains, err := newAffinityDomains(version, term, store, taskgroupID)
+			if err != nil {
+				return nil, err
+			}
+			domains.anti = anti
+			topology.affinity = append(topology.affinity, domains)
+		}
+		for _, weighted := range affinity.Preferred {
+			if weighted == nil {
+				continue
+			}
+			domains, err := newAffinityDomains(version, weighted.Term, store, taskgroupID)
+			if err != nil {
+				return nil, err
+			}
+			domains.anti = anti
+			domains.weight = weighted.Weight
+			topology.affinity = append(topology.affinity, domains)
+		}
+	}
+
+	return topology, nil
+}
+
+func newAffinityDomains(version *types.Version, term *commtypes.AffinityTerm, store store.Store, taskgroupID string) (*affinityDomains, error) {
+	taskGroups, err := listSelectedTaskGroups(version, term.LabelSelector, term.Namespaces, store, taskgroupID)
+	if err != nil {
+		return nil, err
+	}
+	domains := &affinityDomains{
+		term:   term,
+		self:   term.Matches(version.ObjectMeta.Labels),
+		counts: make(map[string]int),
+	}
+	for _, taskGroup := range taskGroups {
+		if domain := taskGroupTopologyValue(taskGroup, term.TopologyKey); domain != "" {
+			domains.counts[domain]++
+			domains.total++
+		}
+	}
+	return domains, nil
+}
+
+//Fit check whether offer fits topology spread constraints with DoNotSchedule and required affinity terms
+func (t *Topology) Fit(offer *mesos.Offer) bool {
+	for _, spread := range t.spreads {
+		if spread.constraint.WhenUnsatisfiable == commtypes.WhenUnsatisfiable_ScheduleAnyway {
+			continue
+		}
+		domain := offerTopologyValue(offer, spread.constraint.TopologyKey)
+		if domain == "" {
+			blog.V(3).Infof("topology spread: offer from %s has no %s, not fit", offer.GetHostname(), spread.constraint.TopologyKey)
+			return false
+		}
+		if skew := spreadSkew(spread.counts, domain); skew > spread.constraint.MaxSkew {
+			blog.V(3).Infof("topology spread: offer from %s %s(%s) skew %d > maxSkew %d, not fit",
+				offer.GetHostname(), spread.constraint.TopologyKey, domain, skew, spread.constraint.MaxSkew)
+			return false
+		}
+	}
+
+	for _, affinity := range t.affinity {
+		if affinity.weight != 0 {
+			continue
+		}
+		domain := offerTopologyValue(offer, affinity.term.TopologyKey)
+		if affinity.anti {
+			if domain != "" && affinity.counts[domain] > 0 {
+				blog.V(3).Infof("anti-affinity: offer from %s %s(%s) has %d selected taskgroups, not fit",
+					offer.GetHostname(), affinity.term.TopologyKey, domain, affinity.counts[domain])
+				return false
+			}
+			continue
+		}
+		//the first taskgroup of applications selecting each other can be placed anywhere
+		if affinity.total == 0 && affinity.self {
+			continue
+		}
+		if domain == "" || affinity.counts[domain] == 0 {
+			blog.V(3).Infof("affinity: offer from %s %s(%s) has no selected taskgroups, not fit",
+				offer.GetHostname(), affinity.term.TopologyKey, domain)
+			return false
+		}
+	}
+
+	return true
+}
+
+//Score sums weights of soft constraints the offer fits, offer with higher score is preferred
+func (t *Topology) Score(offer *mesos.Offer) int {
+	score := 0
+	for _, spread := range t.spreads {
+		domain := offerTopologyValue(offer, spread.constraint.TopologyKey)
+		if domain == "" {
+			continue
+		}
+		score -= spreadScoreWeight * (spreadSkew(spread.counts, domain) - 1)
+	}
+
+	if t.version.Constraints != nil {
+		for _, preferred := range t.version.Constraints.Preferred {
+			if preferred == nil || preferred.Preference == nil {
+				continue
+			}
+			if isFit, _ := constraintDataItemFit(preferred.Preference, offer, t.version, t.store); isFit {
+				score += preferred.Weight
+			}
+		}
+	}
+
+	for _, affinity := range t.affinity {
+		if affinity.weight == 0 {
+			continue
+		}
+		domain := offerTopologyValue(offer, affinity.term.TopologyKey)
+		if domain == "" || affinity.counts[domain] == 0 {
+			continue
+		}
+		if affinity.anti {
+			score -= affinity.weight
+		} else {
+			score += affinity.weight
+		}
+	}
+
+	blog.V(3).Infof("constraints score of offer from %s for version(%s.%s): %d",
+		offer.GetHostname(), t.version.RunAs, t.version.ID, score)
+	return score
+}
+
+//spreadSkew is the skew after placing one more taskgroup in domain:
+//taskgroups in domain + 1 - taskgroups in the domain with least taskgroups
+func spreadSkew(counts map[string]int, domain string) int {
+	min := counts[domain]
+	for _, count := range counts {
+		if count < min {
+			min = count
+		}
+	}
+	return counts[domain] + 1 - min
+}
+
+//listSelectedTaskGroups lists running taskgroups of applications whose labels match selector,
+//empty selector selects the application of version itself
+func listSelectedTaskGroups(version *types.Version, selector map[string]string, namespaces []string,
+	store store.Store, taskgroupID string) ([]*types.TaskGroup, error) {
+
+	var appIDs [][2]string
+	if len(selector) == 0 {
+		appIDs = append(appIDs, [2]string{version.RunAs, version.ID})
+	} else {
+		if len(namespaces) == 0 {
+			namespaces = []string{version.RunAs}
+		}
+		term := &commtypes.AffinityTerm{LabelSelector: selector}
+		for _, ns := range namespaces {
+			apps, err := store.ListApplications(ns)
+			if err != nil {
+				blog.Error("topology: list applications of %s err:%s", ns, err.Error())
+				return nil, err
+			}
+			for _, app := range apps {
+				if term.Matches(app.ObjectMeta.Labels) {
+					appIDs = append(appIDs, [2]string{app.RunAs, app.ID})
+				}
+			}
+		}
+	}
+
+	var selected []*types.TaskGroup
+	for _, appID := range appIDs {
+		store.LockApplication(appID[0] + "." + appID[1])
+		taskGroups, err := store.ListTaskGroups(appID[0], appID[1])
+		store.UnLockApplication(appID[0] + "." + appID[1])
+		if err != nil {
+			blog.Error("topology: list taskgroup(%s %s) err:%s", appID[0], appID[1], err.Error())
+			return nil, err
+		}
+		for _, taskGroup := range taskGroups {
+			if taskGroup.ID == taskgroupID || taskGroup.Status == types.TASKGROUP_STATUS_FINISH ||
+				taskGroup.Status == types.TASKGROUP_STATUS_FAIL {
+				continue
+			}
+			selected = append(selected, taskGroup)
+		}
+	}
+	return selected, nil
+}
+
+func offerTopologyValue(offer *mesos.Offer, key string) string {
+	if key == commtypes.TopologyKey_Hostname {
+		return offer.GetHostname()
+	}
+	attribute, _ := offerP.GetOfferAttribute(offer, key)
+	return attributeValue(attribute)
+}
+
+func taskGroupTopologyValue(taskGroup *types.TaskGroup, key string) string {
+	if key == commtypes.TopologyKey_Hostname {
+		return taskGroup.HostName
+	}
+	for _, attribute := range taskGroup.Attributes {
+		if attribute.GetName() == key {
+			return attributeValue(attribute)
+		}
+	}
+	return ""
+}
+
+func attributeValue(attribute *mesos.Attribute) string {
+	if attribute == nil {
+		return ""
+	}
+	switch attribute.GetType() {
+	case mesos.Value_TEXT:
+		return attribute.GetText().GetValue()
+	case mesos.Value_SCALAR:
+		return strconv.FormatFloat(attribute.GetScalar().GetValue(), 'f', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package strategy
+
+import (
+	"testing"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+
+	"github.com/golang/protobuf/proto"
+)
+
+func rackOffer(hostname, rack string) *mesos.Offer {
+	offer := &mesos.Offer{Hostname: proto.String(hostname)}
+	if rack != "" {
+		offer.Attributes = append(offer.Attributes, &mesos.Attribute{
+			Name: proto.String("rack"),
+			Type: mesos.Value_TEXT.Enum(),
+			Text: &mesos.Value_Text{Value: proto.String(rack)},
+		})
+	}
+	return offer
+}
+
+func TestSpreadSkew(t *testing.T) {
+	counts := map[string]int{"rack-a": 2, "rack-b": 1, "rack-c": 1}
+	tests := []struct {
+		domain string
+		skew   int
+	}{
+		{"rack-a", 2},
+		{"rack-b", 1},
+		{"rack-c", 1},
+		//domain without taskgroups and not seen in offers
+		{"rack-d", 1},
+	}
+	for _, test := range tests {
+		if skew := spreadSkew(counts, test.domain); skew != test.skew {
+			t.Errorf("domain %s: expect skew %d, got %d", test.domain, test.skew, skew)
+		}
+	}
+}
+
+func TestTopologyFit(t *testing.T) {
+	spread := &commtypes.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "rack"}
+	anyway := &commtypes.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "rack",
+		WhenUnsatisfiable: commtypes.WhenUnsatisfiable_ScheduleAnyway}
+	term := &commtypes.AffinityTerm{LabelSelector: map[string]string{"app": "db"}, TopologyKey: "rack"}
+	counts := map[string]int{"rack-a": 1, "rack-b": 0}
+
+	tests := []struct {
+		name     string
+		topology *Topology
+		offer    *mesos.Offer
+		fit      bool
+	}{
+		{"spread in least domain", &Topology{spreads: []*spreadDomains{{spread, counts}}}, rackOffer("h1", "rack-b"), true},
+		{"spread over maxSkew", &Topology{spreads: []*spreadDomains{{spread, counts}}}, rackOffer("h1", "rack-a"), false},
+		{"spread without topology key", &Topology{spreads: []*spreadDomains{{spread, counts}}}, rackOffer("h1", ""), false},
+		{"spread schedule anyway", &Topology{spreads: []*spreadDomains{{anyway, counts}}}, rackOffer("h1", "rack-a"), true},
+		{"affinity matched", &Topology{affinity: []*affinityDomains{{term: term, counts: counts, total: 1}}}, rackOffer("h1", "rack-a"), true},
+		{"affinity not matched", &Topology{affinity: []*affinityDomains{{term: term, counts: counts, total: 1}}}, rackOffer("h1", "rack-b"), false},
+		{"affinity first of self", &Topology{affinity: []*affinityDomains{{term: term, self: true, counts: map[string]int{}}}}, rackOffer("h1", "rack-b"), true},
+		{"anti-affinity matched", &Topology{affinity: []*affinityDomains{{term: term, anti: true, counts: counts, total: 1}}}, rackOffer("h1", "rack-a"), false},
+		{"anti-affinity not matched", &Topology{affinity: []*affinityDomains{{term: term, anti: true, counts: counts, total: 1}}}, rackOffer("h1", "rack-b"), true},
+		{"preferred anti-affinity not filtered", &Topology{affinity: []*affinityDomains{{term: term, weight: 10, anti: true, counts: counts, total: 1}}}, rackOffer("h1", "rack-a"), true},
+	}
+	for _, test := range tests {
+		if fit := test.topology.Fit(test.offer); fit != test.fit {
+			t.Errorf("%s: expect fit %t, got %t", test.name, test.fit, fit)
+		}
+	}
+}
+
+func TestTopologyScore(t *testing.T) {
+	spread := &commtypes.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "rack",
+		WhenUnsatisfiable: commtypes.WhenUnsatisfiable_ScheduleAnyway}
+	term := &commtypes.AffinityTerm{LabelSelector: map[string]string{"app": "db"}, TopologyKey: "rack"}
+	version := &types.Version{
+		Constraints: &commtypes.Constraint{
+			Preferred: []*commtypes.PreferredConstraint{
+				{
+					Weight: 30,
+					Preference: &commtypes.ConstraintDataItem{
+						UnionData: []*commtypes.ConstraintData{
+							{
+								Name:    "hostname",
+								Operate: commtypes.Constraint_Type_CLUSTER,
+								Type:    commtypes.ConstValueType_Text,
+								Text:    &commtypes.ConstraintValue_Text{Value: "h2"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	topology := &Topology{
+		version:  version,
+		spreads:  []*spreadDomains{{spread, map[string]int{"rack-a": 2, "rack-b": 0}}},
+		affinity: []*affinityDomains{{term: term, weight: 20, anti: true, counts: map[string]int{"rack-b": 1}}},
+	}
+
+	tests := []struct {
+		offer *mesos.Offer
+		score int
+	}{
+		{rackOffer("h1", "rack-a"), -200},
+		{rackOffer("h1", "rack-b"), -20},
+		{rackOffer("h2", "rack-b"), 10},
+		{rackOffer("h2", ""), 30},
+	}
+	for _, test := range tests {
+		if score := topology.Score(test.offer); score != test.score {
+			t.Errorf("offer %s: expect score %d, got %d", test.offer.GetHostname(), test.score, score)
+		}
+	}
+}
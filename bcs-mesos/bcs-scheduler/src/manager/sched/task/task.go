@@ -254,9 +254,16 @@ func CreateTaskGroup(version *types.Version, ID string, appInstances uint64, app
 				task.KillPolicy = version.KillPolicy
 			}
 			createTaskHealthChecks(&task, container.HealthChecks)
+			task.ReadinessChecks = container.ReadinessChecks
 
 			taskgroup.Taskgroup = append(taskgroup.Taskgroup, &task)
 		}
+		// init containers are launched by executor before all tasks, carried by the first task
+		if len(version.InitContainers) > 0 && len(taskgroup.Taskgroup) > 0 && taskgroup.Taskgroup[0].DataClass != nil {
+			dataClass := *taskgroup.Taskgroup[0].DataClass
+			dataClass.InitContainers = version.InitContainers
+			taskgroup.Taskgroup[0].DataClass = &dataClass
+		}
 	}
 
 	if buildID {
@@ -834,6 +841,7 @@ func createContainerTaskInfo(offer *mesos.Offer, resources []*mesos.Resource, ta
 		Variables: varEnvs,
 	}
 
+	createTaskProbes(task)
 	msgData, err := json.Marshal(task.DataClass)
 	blog.V(3).Infof("task %s dataclass %s", task.ID, string(msgData))
 
@@ -1053,6 +1061,52 @@ func createTaskInfoHealth(task *types.Task, taskInfo *mesos.TaskInfo) {
 	return
 }
 
+//createTaskProbes set ports of readiness & startup checks which are specified by portName,
+//checks are copied because task.DataClass may be shared with version
+func createTaskProbes(task *types.Task) {
+	if task.DataClass == nil || (len(task.DataClass.ReadinessChecks) == 0 && len(task.DataClass.StartupChecks) == 0) {
+		return
+	}
+	dataClass := *task.DataClass
+	dataClass.ReadinessChecks = resolveTaskProbePorts(task, task.DataClass.ReadinessChecks)
+	dataClass.StartupChecks = resolveTaskProbePorts(task, task.DataClass.StartupChecks)
+	task.DataClass = &dataClass
+}
+
+func resolveTaskProbePorts(task *types.Task, checks []*commtypes.HealthCheck) []*commtypes.HealthCheck {
+	var probes []*commtypes.HealthCheck
+	for _, check := range checks {
+		probe := *check
+		switch probe.Type {
+		case bcstype.BcsHealthCheckType_TCP:
+			if probe.Tcp == nil {
+				blog.Error("task(%s) probe(%s) data is nil", task.ID, probe.Type)
+				continue
+			}
+			tcp := *probe.Tcp
+			if tcp.Port <= 0 {
+				tcp.Port, _ = getTaskHealthCheckPort(task, tcp.PortName)
+			}
+			probe.Tcp = &tcp
+		case bcstype.BcsHealthCheckType_HTTP:
+			if probe.Http == nil {
+				blog.Error("task(%s) probe(%s) data is nil", task.ID, probe.Type)
+				continue
+			}
+			httpCheck := *probe.Http
+			if httpCheck.Port <= 0 {
+				httpCheck.Port, _ = getTaskHealthCheckPort(task, httpCheck.PortName)
+			}
+			probe.Http = &httpCheck
+		default:
+			blog.Info("task(%s) probe(%s) not supported", task.ID, probe.Type)
+			continue
+		}
+		probes = append(probes, &probe)
+	}
+	return probes
+}
+
 // added  180807, render the var templates in *types.Task, it will effect taskgroup and the following copy
 // operations from taskgroup to taskinfo.
 func renderProcessTaskVarTemplate(task *types.Task, offer *mesos.Offer) {
@@ -12,3 +12,34 @@
  */
 
 package task
+
+import (
+	"testing"
+
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTaskGroupInitContainers(t *testing.T) {
+	newContainer := func(image string) *types.Container {
+		return &types.Container{
+			Docker:    &types.Docker{Image: image},
+			Resources: &types.Resource{Cpus: 0.1, Mem: 16},
+			DataClass: &types.DataClass{Resources: &types.Resource{Cpus: 0.1, Mem: 16}},
+		}
+	}
+	version := &types.Version{
+		ID:             "app",
+		RunAs:          "ns",
+		Container:      []*types.Container{newContainer("main"), newContainer("sidecar")},
+		InitContainers: []*types.Container{newContainer("init")},
+	}
+	taskgroup, err := CreateTaskGroup(version, "", 0, "cluster", "test", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(taskgroup.Taskgroup))
+	assert.Equal(t, 1, len(taskgroup.Taskgroup[0].DataClass.InitContainers))
+	assert.Equal(t, 0, len(taskgroup.Taskgroup[1].DataClass.InitContainers))
+	//DataClass of version is not modified
+	assert.Equal(t, 0, len(version.Container[0].DataClass.InitContainers))
+}
@@ -0,0 +1,82 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package store
+
+import (
+	"bk-bcs/bcs-common/common/blog"
+	commtypes "bk-bcs/bcs-common/common/types"
+	"encoding/json"
+	"github.com/samuel/go-zookeeper/zk"
+)
+
+func getAgentDrainRootPath() string {
+	return "/" + bcsRootNode + "/" + agentDrainNode
+}
+
+func (store *managerStore) SaveAgentDrain(drain *commtypes.BcsAgentDrain) error {
+
+	data, err := json.Marshal(drain)
+	if err != nil {
+		return err
+	}
+
+	path := getAgentDrainRootPath() + "/" + drain.InnerIP
+	return store.Db.Insert(path, string(data))
+}
+
+func (store *managerStore) FetchAgentDrain(InnerIP string) (*commtypes.BcsAgentDrain, error) {
+
+	path := getAgentDrainRootPath() + "/" + InnerIP
+
+	data, err := store.Db.Fetch(path)
+	if err == zk.ErrNoNode {
+		blog.V(3).Infof("agent drain(%s) not exist", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	drain := &commtypes.BcsAgentDrain{}
+	if err := json.Unmarshal(data, drain); err != nil {
+		blog.Error("fail to unmarshal agent drain(%s). err:%s", string(data), err.Error())
+		return nil, err
+	}
+
+	return drain, nil
+}
+
+func (store *managerStore) DeleteAgentDrain(InnerIP string) error {
+
+	path := getAgentDrainRootPath() + "/" + InnerIP
+	if err := store.Db.Delete(path); err != nil {
+		blog.Error("fail to delete agent drain(%s) err:%s", path, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (store *managerStore) ListAgentDrainNodes() ([]string, error) {
+
+	path := getAgentDrainRootPath()
+
+	nodes, err := store.Db.List(path)
+	if err != nil {
+		blog.Error("fail to list agent drains(%s), err:%s", path, err.Error())
+		return nil, err
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,101 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package store
+
+import (
+	"context"
+
+	"bk-bcs/bcs-common/common/tracing"
+)
+
+//dbTrace decorate Dbdrvier, every db operation is recorded as a span
+type dbTrace struct {
+	name   string
+	driver Dbdrvier
+	//context of request, db spans are children of the span in ctx
+	ctx context.Context
+}
+
+//NewDbTrace wrap driver with tracing, name is the kind of db, such as zk
+func NewDbTrace(name string, driver Dbdrvier) Dbdrvier {
+	return &dbTrace{
+		name:   name,
+		driver: driver,
+		ctx:    context.Background(),
+	}
+}
+
+//WithContext return store whose db operations are traced as children of the span in ctx,
+//store is returned as it is if db is not traced
+func (store *managerStore) WithContext(ctx context.Context) Store {
+	t, ok := store.Db.(*dbTrace)
+	if !ok || ctx == nil {
+		return store
+	}
+	db := *t
+	db.ctx = ctx
+	return &managerStore{Db: &db}
+}
+
+func (t *dbTrace) trace(op, path string, fn func() error) error {
+	span, _ := tracing.StartSpan(t.ctx, t.name+" "+op, tracing.SpanKindClient)
+	span.SetAttribute("db.type", t.name)
+	span.SetAttribute("db.path", path)
+	err := fn()
+	span.SetError(err)
+	span.Finish()
+	return err
+}
+
+func (t *dbTrace) Connect() error {
+	return t.trace("Connect", "", t.driver.Connect)
+}
+
+func (t *dbTrace) Insert(path string, value string) error {
+	return t.trace("Insert", path, func() error {
+		return t.driver.Insert(path, value)
+	})
+}
+
+func (t *dbTrace) Fetch(path string) ([]byte, error) {
+	var data []byte
+	err := t.trace("Fetch", path, func() error {
+		var err error
+		data, err = t.driver.Fetch(path)
+		return err
+	})
+	return data, err
+}
+
+func (t *dbTrace) Update(path string, value string) error {
+	return t.trace("Update", path, func() error {
+		return t.driver.Update(path, value)
+	})
+}
+
+func (t *dbTrace) Delete(path string) error {
+	return t.trace("Delete", path, func() error {
+		return t.driver.Delete(path)
+	})
+}
+
+func (t *dbTrace) List(path string) ([]string, error) {
+	var keys []string
+	err := t.trace("List", path, func() error {
+		var err error
+		keys, err = t.driver.List(path)
+		return err
+	})
+	return keys, err
+}
@@ -0,0 +1,87 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"bk-bcs/bcs-common/common/conf"
+	"bk-bcs/bcs-common/common/tracing"
+)
+
+type recordExporter struct {
+	sync.Mutex
+	spans []*tracing.Span
+}
+
+func (e *recordExporter) Export(span *tracing.Span) {
+	e.Lock()
+	defer e.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+func (e *recordExporter) Shutdown() {}
+
+type fakeDb struct{}
+
+func (d *fakeDb) Connect() error                { return nil }
+func (d *fakeDb) Insert(string, string) error   { return nil }
+func (d *fakeDb) Fetch(string) ([]byte, error)  { return []byte("{}"), nil }
+func (d *fakeDb) Update(string, string) error   { return nil }
+func (d *fakeDb) Delete(string) error           { return nil }
+func (d *fakeDb) List(string) ([]string, error) { return nil, nil }
+
+func TestStoreWithContext(t *testing.T) {
+	e := &recordExporter{}
+	tracing.RegisterExporter("store-test", func(string, string) (tracing.Exporter, error) {
+		return e, nil
+	})
+	if err := tracing.InitTracer("test", conf.TracingConfig{TracingExporter: "store-test", TracingSampleRatio: 1}); err != nil {
+		t.Fatalf("init tracer failed: %s", err.Error())
+	}
+	defer tracing.InitTracer("test", conf.TracingConfig{TracingSampleRatio: 1})
+
+	s := NewManagerStore(NewDbTrace("zk", &fakeDb{}))
+	request, ctx := tracing.StartSpan(context.Background(), "request", tracing.SpanKindServer)
+	if _, err := s.WithContext(ctx).ListRunAs(); err != nil {
+		t.Fatalf("list namespaces failed: %s", err.Error())
+	}
+	//store without context is not affected by the request
+	if _, err := s.ListRunAs(); err != nil {
+		t.Fatalf("list namespaces failed: %s", err.Error())
+	}
+	request.Finish()
+
+	e.Lock()
+	defer e.Unlock()
+	var joined, orphan int
+	for _, span := range e.spans {
+		if span.Kind != tracing.SpanKindClient {
+			continue
+		}
+		if span.Context.TraceID == request.Context.TraceID && span.ParentSpanID == request.Context.SpanID {
+			joined++
+		} else {
+			orphan++
+		}
+	}
+	if joined == 0 {
+		t.Errorf("db spans of store with context should join the request trace")
+	}
+	if orphan == 0 {
+		t.Errorf("db spans of store without context should not join the request trace")
+	}
+}
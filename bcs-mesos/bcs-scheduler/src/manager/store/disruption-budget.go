@@ -0,0 +1,85 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package store
+
+import (
+	"bk-bcs/bcs-common/common/blog"
+	commtypes "bk-bcs/bcs-common/common/types"
+	"encoding/json"
+	"fmt"
+)
+
+func getDisruptionBudgetRootPath() string {
+	return "/" + bcsRootNode + "/" + disruptionBudgetNode
+}
+
+func (store *managerStore) SaveDisruptionBudget(budget *commtypes.BcsDisruptionBudget) error {
+
+	data, err := json.Marshal(budget)
+	if err != nil {
+		return err
+	}
+
+	path := getDisruptionBudgetRootPath() + "/" + budget.ObjectMeta.NameSpace + "/" + budget.ObjectMeta.Name
+	return store.Db.Insert(path, string(data))
+}
+
+func (store *managerStore) FetchDisruptionBudget(ns, name string) (*commtypes.BcsDisruptionBudget, error) {
+
+	path := getDisruptionBudgetRootPath() + "/" + ns + "/" + name
+
+	data, err := store.Db.Fetch(path)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := &commtypes.BcsDisruptionBudget{}
+	if err := json.Unmarshal(data, budget); err != nil {
+		blog.Error("fail to unmarshal disruption budget(%s). err:%s", string(data), err.Error())
+		return nil, err
+	}
+
+	return budget, nil
+}
+
+func (store *managerStore) DeleteDisruptionBudget(ns, name string) error {
+
+	path := getDisruptionBudgetRootPath() + "/" + ns + "/" + name
+	if err := store.Db.Delete(path); err != nil {
+		blog.Error("fail to delete disruption budget(%s) err:%s", path, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (store *managerStore) ListDisruptionBudgets(ns string) ([]*commtypes.BcsDisruptionBudget, error) {
+	nsPath := fmt.Sprintf("%s/%s", getDisruptionBudgetRootPath(), ns)
+	names, err := store.Db.List(nsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets := make([]*commtypes.BcsDisruptionBudget, 0)
+	for _, name := range names {
+		budget, err := store.FetchDisruptionBudget(ns, name)
+		if err != nil {
+			blog.Errorf("fetch disruption budget(%s.%s) error %s", ns, name, err.Error())
+			continue
+		}
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, nil
+}
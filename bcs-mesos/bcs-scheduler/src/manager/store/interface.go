@@ -14,6 +14,8 @@
 package store
 
 import (
+	"context"
+
 	commtypes "bk-bcs/bcs-common/common/types"
 	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
 )
@@ -21,6 +23,9 @@ import (
 // The interface for object storage
 type Store interface {
 
+	// return store whose db operations are traced as children of the span in ctx
+	WithContext(ctx context.Context) Store
+
 	// save framework id to db
 	SaveFrameworkID(string) error
 	// fetch framework id from db
@@ -203,6 +208,31 @@ type Store interface {
 	DeleteAdmissionWebhook(ns, name string) error
 	FetchAllAdmissionWebhooks() ([]*commtypes.AdmissionWebhookConfiguration, error)
 	/*=========AdmissionWebhook==========*/
+
+	// save usage history of application
+	SaveUsageHistory(history *types.AppUsageHistory) error
+	// fetch usage history of application
+	FetchUsageHistory(runAs, appID string) (*types.AppUsageHistory, error)
+	// delete usage history of application
+	DeleteUsageHistory(runAs, appID string) error
+
+	// save disruption budget
+	SaveDisruptionBudget(budget *commtypes.BcsDisruptionBudget) error
+	// fetch disruption budget
+	FetchDisruptionBudget(ns, name string) (*commtypes.BcsDisruptionBudget, error)
+	// delete disruption budget
+	DeleteDisruptionBudget(ns, name string) error
+	// list disruption budgets under namespace
+	ListDisruptionBudgets(ns string) ([]*commtypes.BcsDisruptionBudget, error)
+
+	// save agent drain progress
+	SaveAgentDrain(drain *commtypes.BcsAgentDrain) error
+	// fetch agent drain progress, return nil if agent is not drained
+	FetchAgentDrain(InnerIP string) (*commtypes.BcsAgentDrain, error)
+	// delete agent drain progress
+	DeleteAgentDrain(InnerIP string) error
+	// list ips of drained agents
+	ListAgentDrainNodes() ([]string, error)
 }
 
 // The interface for db operations
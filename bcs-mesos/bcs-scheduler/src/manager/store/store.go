@@ -62,4 +62,10 @@ const (
 	commandNode string = "command"
 	//admission webhook zk node
 	AdmissionWebhookNode string = "admissionwebhook"
+	//resource usage history zk node
+	usageHistoryNode string = "usagehistory"
+	//disruption budget zk node
+	disruptionBudgetNode string = "disruptionbudget"
+	//agent drain zk node
+	agentDrainNode string = "agentdrain"
 )
@@ -0,0 +1,64 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package store
+
+import (
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"encoding/json"
+)
+
+func getUsageHistoryRootPath() string {
+	return "/" + bcsRootNode + "/" + usageHistoryNode
+}
+
+func (store *managerStore) SaveUsageHistory(history *types.AppUsageHistory) error {
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+
+	path := getUsageHistoryRootPath() + "/" + history.RunAs + "/" + history.AppID
+	return store.Db.Insert(path, string(data))
+}
+
+func (store *managerStore) FetchUsageHistory(runAs, appID string) (*types.AppUsageHistory, error) {
+
+	path := getUsageHistoryRootPath() + "/" + runAs + "/" + appID
+
+	data, err := store.Db.Fetch(path)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &types.AppUsageHistory{}
+	if err := json.Unmarshal(data, history); err != nil {
+		blog.Error("fail to unmarshal usage history(%s). err:%s", string(data), err.Error())
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (store *managerStore) DeleteUsageHistory(runAs, appID string) error {
+
+	path := getUsageHistoryRootPath() + "/" + runAs + "/" + appID
+	if err := store.Db.Delete(path); err != nil {
+		blog.Error("fail to delete usage history(%s) err:%s", path, err.Error())
+		return err
+	}
+
+	return nil
+}
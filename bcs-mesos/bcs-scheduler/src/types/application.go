@@ -142,6 +142,8 @@ type Version struct {
 	Instances     int32
 	RunAs         string
 	Container     []*Container
+	//InitContainers run to completion in order before Container start
+	InitContainers []*Container `json:",omitempty"`
 	//add  20180802
 	Process       []*commtypes.Process
 	Labels        map[string]string
@@ -237,6 +239,11 @@ func (version *Version) CheckConstraints() bool {
 		}
 	}
 
+	//topology spread, preferred and affinity constraints
+	if err := version.Constraints.Validate(); err != nil {
+		return false
+	}
+
 	return true
 }
 
@@ -320,9 +327,16 @@ type Container struct {
 	Secrets    []commtypes.Secret
 
 	HealthChecks []*commtypes.HealthCheck
+	//ReadinessChecks decide whether container can receive traffic
+	ReadinessChecks []*commtypes.HealthCheck `json:",omitempty"`
+	//StartupChecks suppress HealthChecks until the first success
+	StartupChecks []*commtypes.HealthCheck `json:",omitempty"`
 
 	//network flow limit
 	NetLimit *commtypes.NetLimit
+
+	//postStart & preStop hooks
+	Lifecycle *commtypes.Lifecycle `json:",omitempty"`
 }
 
 //Docker for container
@@ -416,6 +430,9 @@ type Task struct {
 	IsChecked                   bool
 	ConsecutiveFailureTimes     uint32
 	LocalMaxConsecutiveFailures uint32
+	// readiness check, only meaningful when ReadinessChecks is not empty
+	ReadinessChecks []*commtypes.HealthCheck `json:",omitempty"`
+	Ready           bool
 
 	OfferId        string
 	AgentId        string
@@ -468,6 +485,19 @@ type TaskGroup struct {
 	CurrResource   *Resource
 	//BcsMessages map[int64]*BcsMessage
 	BcsEventMsg *BcsMessage
+	//Usage latest resource usage reported by executor
+	Usage *TaskGroupUsage `json:",omitempty"`
+}
+
+//IsReady check all tasks with readiness checks are ready,
+//taskgroup without readiness checks is always ready
+func (tg *TaskGroup) IsReady() bool {
+	for _, task := range tg.Taskgroup {
+		if len(task.ReadinessChecks) > 0 && !task.Ready {
+			return false
+		}
+	}
+	return true
 }
 
 //Application for container
@@ -560,6 +590,13 @@ type DataClass struct {
 	NetLimit       *commtypes.NetLimit
 	//add for proc 20180730
 	ProcInfo *ProcDef
+	//container lifecycle hooks
+	Lifecycle *commtypes.Lifecycle `json:",omitempty"`
+	//init containers of taskgroup, only set in the first task
+	InitContainers []*Container `json:",omitempty"`
+	//readiness & startup checks, done by executor
+	ReadinessChecks []*commtypes.HealthCheck `json:",omitempty"`
+	StartupChecks   []*commtypes.HealthCheck `json:",omitempty"`
 }
 
 type DeploymentDef struct {
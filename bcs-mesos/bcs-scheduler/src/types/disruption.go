@@ -0,0 +1,67 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package types
+
+import (
+	commtypes "bk-bcs/bcs-common/common/types"
+	"sort"
+)
+
+//DisruptionRecordTimeout seconds a disrupted taskgroup is treated as unavailable
+//even though its status is not updated yet
+const DisruptionRecordTimeout int64 = 120
+
+//IsAvailable taskgroup is running, healthy and ready
+func (tg *TaskGroup) IsAvailable() bool {
+	if tg.Status != TASKGROUP_STATUS_RUNNING {
+		return false
+	}
+	for _, task := range tg.Taskgroup {
+		if !task.Healthy {
+			return false
+		}
+	}
+	return tg.IsReady()
+}
+
+//ComputeDisruptionStatus compute status of budget from taskgroups of all matched applications.
+//disrupted records taskgroup id -> unix seconds when it was disrupted, taskgroups
+//disrupted within DisruptionRecordTimeout are not counted as healthy
+func ComputeDisruptionStatus(budget *commtypes.BcsDisruptionBudget, taskgroups []*TaskGroup,
+	disrupted map[string]int64, now int64) (*commtypes.DisruptionBudgetStatus, error) {
+	status := &commtypes.DisruptionBudgetStatus{
+		ExpectedTaskgroups: len(taskgroups),
+		UpdateTime:         now,
+	}
+	for _, tg := range taskgroups {
+		if t, ok := disrupted[tg.ID]; ok && now-t < DisruptionRecordTimeout {
+			status.DisruptedTaskgroups = append(status.DisruptedTaskgroups, tg.ID)
+			continue
+		}
+		if tg.IsAvailable() {
+			status.CurrentHealthy++
+		}
+	}
+	sort.Strings(status.DisruptedTaskgroups)
+
+	desired, err := budget.Spec.DesiredHealthy(status.ExpectedTaskgroups)
+	if err != nil {
+		return nil, err
+	}
+	status.DesiredHealthy = desired
+	if status.CurrentHealthy > desired {
+		status.DisruptionsAllowed = status.CurrentHealthy - desired
+	}
+	return status, nil
+}
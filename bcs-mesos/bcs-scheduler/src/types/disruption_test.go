@@ -0,0 +1,95 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	commtypes "bk-bcs/bcs-common/common/types"
+)
+
+func newTestTaskGroup(id, status string, healthy bool) *TaskGroup {
+	return &TaskGroup{
+		ID:        id,
+		Status:    status,
+		Taskgroup: []*Task{{Healthy: healthy}},
+	}
+}
+
+func TestComputeDisruptionStatus(t *testing.T) {
+	taskgroups := []*TaskGroup{
+		newTestTaskGroup("0.app.ns.cluster.1", TASKGROUP_STATUS_RUNNING, true),
+		newTestTaskGroup("1.app.ns.cluster.1", TASKGROUP_STATUS_RUNNING, true),
+		newTestTaskGroup("2.app.ns.cluster.1", TASKGROUP_STATUS_RUNNING, true),
+		newTestTaskGroup("3.app.ns.cluster.1", TASKGROUP_STATUS_RUNNING, false),
+		newTestTaskGroup("4.app.ns.cluster.1", TASKGROUP_STATUS_STAGING, true),
+	}
+	tests := []struct {
+		name      string
+		spec      string
+		disrupted map[string]int64
+		healthy   int
+		desired   int
+		allowed   int
+	}{
+		{"min available number", `{"minAvailable":2}`, nil, 3, 2, 1},
+		{"min available percent", `{"minAvailable":"50%"}`, nil, 3, 3, 0},
+		{"max unavailable number", `{"maxUnavailable":3}`, nil, 3, 2, 1},
+		{"max unavailable percent", `{"maxUnavailable":"100%"}`, nil, 3, 0, 3},
+		{"recent disruption", `{"minAvailable":2}`, map[string]int64{"0.app.ns.cluster.1": 990}, 2, 2, 0},
+		{"expired disruption", `{"minAvailable":2}`, map[string]int64{"0.app.ns.cluster.1": 10}, 3, 2, 1},
+	}
+	for _, tc := range tests {
+		budget := &commtypes.BcsDisruptionBudget{Spec: &commtypes.DisruptionBudgetSpec{}}
+		if err := json.Unmarshal([]byte(tc.spec), budget.Spec); err != nil {
+			t.Fatalf("%s: unmarshal spec err: %s", tc.name, err.Error())
+		}
+		status, err := ComputeDisruptionStatus(budget, taskgroups, tc.disrupted, 1000)
+		if err != nil {
+			t.Errorf("%s: unexpected err: %s", tc.name, err.Error())
+			continue
+		}
+		if status.ExpectedTaskgroups != len(taskgroups) || status.CurrentHealthy != tc.healthy ||
+			status.DesiredHealthy != tc.desired || status.DisruptionsAllowed != tc.allowed {
+			t.Errorf("%s: got %+v, want healthy %d desired %d allowed %d",
+				tc.name, status, tc.healthy, tc.desired, tc.allowed)
+		}
+	}
+}
+
+func TestDisruptionBudgetValidate(t *testing.T) {
+	tests := []struct {
+		spec  string
+		valid bool
+	}{
+		{`{"minAvailable":1}`, true},
+		{`{"maxUnavailable":"25%"}`, true},
+		{`{}`, false},
+		{`{"minAvailable":1,"maxUnavailable":1}`, false},
+		{`{"minAvailable":"150%"}`, false},
+		{`{"maxUnavailable":"abc"}`, false},
+	}
+	for _, tc := range tests {
+		budget := &commtypes.BcsDisruptionBudget{Spec: &commtypes.DisruptionBudgetSpec{}}
+		budget.Name = "budget"
+		budget.NameSpace = "ns"
+		if err := json.Unmarshal([]byte(tc.spec), budget.Spec); err != nil {
+			t.Fatalf("%s: unmarshal spec err: %s", tc.spec, err.Error())
+		}
+		if err := budget.Validate(); (err == nil) != tc.valid {
+			t.Errorf("%s: validate got err %v, want valid %t", tc.spec, err, tc.valid)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package types
+
+import (
+	"sort"
+	"time"
+
+	mesos_maintenance "bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos/maintenance"
+)
+
+//MaintenanceDrainAgents ips of machines in mesos maintenance schedule which should be drained at now:
+//window starts within drainAhead seconds and is not over yet. window without duration never ends.
+//machines without ip are ignored because agent settings are keyed by ip
+func MaintenanceDrainAgents(schedule *mesos_maintenance.Schedule, drainAhead int64, now time.Time) []string {
+	ips := make(map[string]bool)
+	for _, window := range schedule.GetWindows() {
+		unavailability := window.GetUnavailability()
+		if unavailability == nil {
+			continue
+		}
+
+		start := time.Unix(0, unavailability.GetStart().GetNanoseconds())
+		if now.Before(start.Add(-time.Duration(drainAhead) * time.Second)) {
+			continue
+		}
+		if unavailability.GetDuration() != nil {
+			end := start.Add(time.Duration(unavailability.GetDuration().GetNanoseconds()))
+			if !now.Before(end) {
+				continue
+			}
+		}
+
+		for _, machine := range window.GetMachineIds() {
+			if machine.GetIp() != "" {
+				ips[machine.GetIp()] = true
+			}
+		}
+	}
+
+	agents := make([]string, 0, len(ips))
+	for ip := range ips {
+		agents = append(agents, ip)
+	}
+	sort.Strings(agents)
+	return agents
+}
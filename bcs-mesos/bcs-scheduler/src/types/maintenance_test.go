@@ -0,0 +1,106 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package types
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos"
+	mesos_maintenance "bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos/maintenance"
+	"github.com/golang/protobuf/proto"
+)
+
+func newTestWindow(start time.Time, duration time.Duration, ips ...string) *mesos_maintenance.Window {
+	window := &mesos_maintenance.Window{
+		Unavailability: &mesos.Unavailability{
+			Start: &mesos.TimeInfo{Nanoseconds: proto.Int64(start.UnixNano())},
+		},
+	}
+	if duration > 0 {
+		window.Unavailability.Duration = &mesos.DurationInfo{Nanoseconds: proto.Int64(int64(duration))}
+	}
+	for _, ip := range ips {
+		window.MachineIds = append(window.MachineIds, &mesos.MachineID{
+			Hostname: proto.String("host-" + ip),
+			Ip:       proto.String(ip),
+		})
+	}
+	return window
+}
+
+func TestMaintenanceDrainAgents(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	schedule := &mesos_maintenance.Schedule{
+		Windows: []*mesos_maintenance.Window{
+			//starts in 10 minutes
+			newTestWindow(now.Add(10*time.Minute), time.Hour, "127.0.0.2", "127.0.0.1"),
+			//starts in 2 hours
+			newTestWindow(now.Add(2*time.Hour), time.Hour, "127.0.0.3"),
+			//in progress
+			newTestWindow(now.Add(-10*time.Minute), time.Hour, "127.0.0.4"),
+			//over
+			newTestWindow(now.Add(-2*time.Hour), time.Hour, "127.0.0.5"),
+			//never ends
+			newTestWindow(now.Add(-24*time.Hour), 0, "127.0.0.6"),
+			//machine without ip
+			{
+				Unavailability: &mesos.Unavailability{
+					Start: &mesos.TimeInfo{Nanoseconds: proto.Int64(now.UnixNano())},
+				},
+				MachineIds: []*mesos.MachineID{{Hostname: proto.String("host-7")}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		schedule   *mesos_maintenance.Schedule
+		drainAhead int64
+		want       []string
+	}{
+		{
+			name:       "no schedule",
+			schedule:   nil,
+			drainAhead: 1800,
+			want:       []string{},
+		},
+		{
+			name:       "drain half an hour ahead",
+			schedule:   schedule,
+			drainAhead: 1800,
+			want:       []string{"127.0.0.1", "127.0.0.2", "127.0.0.4", "127.0.0.6"},
+		},
+		{
+			name:       "drain three hours ahead",
+			schedule:   schedule,
+			drainAhead: 3 * 3600,
+			want:       []string{"127.0.0.1", "127.0.0.2", "127.0.0.3", "127.0.0.4", "127.0.0.6"},
+		},
+		{
+			name:       "drain when window starts",
+			schedule:   schedule,
+			drainAhead: 0,
+			want:       []string{"127.0.0.4", "127.0.0.6"},
+		},
+	}
+
+	for _, test := range tests {
+		got := MaintenanceDrainAgents(test.schedule, test.drainAhead, now)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -37,6 +37,7 @@ const (
 	Msg_RESTART_TASK      Msg_Type = 11
 	Msg_Req_COMMAND_TASK  Msg_Type = 12
 	Msg_Res_COMMAND_TASK  Msg_Type = 13
+	Msg_TASKGROUP_USAGE   Msg_Type = 14
 )
 
 const (
@@ -54,6 +55,7 @@ const (
 	Msg_RESTART_TASK_STR      string = "restart_task"
 	Msg_Req_COMMAND_TASK_STR  string = "request_command_task"
 	Msg_Res_COMMAND_TASK_STR  string = "response_command_task"
+	Msg_TASKGROUP_USAGE_STR   string = "taskgroup_usage"
 )
 
 type Secret_Type int32
@@ -85,6 +87,7 @@ type BcsMessage struct {
 	RestartTask         *Msg_RestartTasks        `json:",omitempty"`
 	RequestCommandTask  *RequestCommandTask      `json:",omitempty"`
 	ResponseCommandTask *ResponseCommandTask     `json:",omitempty"`
+	TaskGroupUsage      *TaskGroupUsage          `json:",omitempty"`
 
 	Status MsgStatus_type
 	//if status=failed, then message is failed info
@@ -0,0 +1,369 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package types
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	//RecommendationApplyLabel deployment label, value "true" means resource recommendations
+	//are applied to containers when deployment rolling update
+	RecommendationApplyLabel = "io.tencent.bcs.recommendation.apply"
+
+	//UsageHalfLife half life of usage sample weight, seconds
+	UsageHalfLife int64 = 24 * 3600
+	//RecommendationMinSamples min samples of container before recommendation is given
+	RecommendationMinSamples uint64 = 30
+	//RecommendationSafetyMargin extra fraction added to all recommended values
+	RecommendationSafetyMargin = 0.15
+	//RecommendationMinCPU min recommended cpu cores
+	RecommendationMinCPU = 0.05
+	//RecommendationMinMem min recommended memory, MB
+	RecommendationMinMem = 32.0
+
+	//CPUHistogramFirstBucket size of first cpu bucket, cores
+	CPUHistogramFirstBucket = 0.01
+	//MemHistogramFirstBucket size of first memory bucket, MB
+	MemHistogramFirstBucket = 1.0
+
+	histogramBucketRatio = 1.05
+	histogramMaxBuckets  = 500
+	//shift reference time when new sample weight grows up to 2^16
+	histogramMaxDecayShift = 16 * UsageHalfLife
+	//buckets whose weight fall below it after shifting are dropped
+	histogramMinWeight = 1e-4
+)
+
+// UsageHistogram decaying histogram of resource usage samples. bucket sizes grow
+// exponentially, so relative error of percentile is bounded in the whole range.
+// weight of sample doubles every UsageHalfLife, newer samples are more important
+type UsageHistogram struct {
+	//FirstBucket size of first bucket, in sample unit
+	FirstBucket float64
+	//Weights bucket index -> decayed weight
+	Weights     map[int]float64
+	TotalWeight float64
+	//ReferenceTime unix seconds, sample at this time weights 1
+	ReferenceTime int64
+	Samples       uint64
+}
+
+// NewUsageHistogram create empty histogram with first bucket size
+func NewUsageHistogram(firstBucket float64) *UsageHistogram {
+	return &UsageHistogram{
+		FirstBucket: firstBucket,
+		Weights:     make(map[int]float64),
+	}
+}
+
+// bucketIndex index of bucket the value falls into
+func (h *UsageHistogram) bucketIndex(value float64) int {
+	if value <= 0 {
+		return 0
+	}
+	index := int(math.Log(1+value*(histogramBucketRatio-1)/h.FirstBucket) / math.Log(histogramBucketRatio))
+	if index >= histogramMaxBuckets {
+		return histogramMaxBuckets - 1
+	}
+	return index
+}
+
+// bucketStart the smallest value of bucket
+func (h *UsageHistogram) bucketStart(index int) float64 {
+	return h.FirstBucket * (math.Pow(histogramBucketRatio, float64(index)) - 1) / (histogramBucketRatio - 1)
+}
+
+// AddSample add usage value sampled at timestamp(unix seconds)
+func (h *UsageHistogram) AddSample(value float64, timestamp int64) {
+	if h.Weights == nil {
+		h.Weights = make(map[int]float64)
+	}
+	if h.Samples == 0 && h.TotalWeight == 0 {
+		h.ReferenceTime = timestamp
+	}
+	if timestamp-h.ReferenceTime > histogramMaxDecayShift {
+		h.shiftReference(timestamp)
+	}
+	weight := math.Exp2(float64(timestamp-h.ReferenceTime) / float64(UsageHalfLife))
+	h.Weights[h.bucketIndex(value)] += weight
+	h.TotalWeight += weight
+	h.Samples++
+}
+
+// shiftReference move reference time forward and scale down all weights,
+// keep weights from growing out of float range
+func (h *UsageHistogram) shiftReference(timestamp int64) {
+	factor := math.Exp2(float64(h.ReferenceTime-timestamp) / float64(UsageHalfLife))
+	h.TotalWeight = 0
+	for index, weight := range h.Weights {
+		weight *= factor
+		if weight < histogramMinWeight {
+			delete(h.Weights, index)
+			continue
+		}
+		h.Weights[index] = weight
+		h.TotalWeight += weight
+	}
+	h.ReferenceTime = timestamp
+}
+
+// Merge add all samples of other histogram with the same bucket size
+func (h *UsageHistogram) Merge(other *UsageHistogram) {
+	if other == nil || other.TotalWeight == 0 || other.FirstBucket != h.FirstBucket {
+		return
+	}
+	if h.Weights == nil {
+		h.Weights = make(map[int]float64)
+	}
+	if h.TotalWeight == 0 {
+		h.ReferenceTime = other.ReferenceTime
+	}
+	factor := math.Exp2(float64(other.ReferenceTime-h.ReferenceTime) / float64(UsageHalfLife))
+	for index, weight := range other.Weights {
+		h.Weights[index] += weight * factor
+		h.TotalWeight += weight * factor
+	}
+	h.Samples += other.Samples
+}
+
+// Percentile the value below which given fraction of weighted samples fall,
+// the end of matched bucket is returned, so the result never underestimates
+func (h *UsageHistogram) Percentile(percentile float64) float64 {
+	if h.TotalWeight == 0 {
+		return 0
+	}
+	indexes := make([]int, 0, len(h.Weights))
+	for index := range h.Weights {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	threshold := percentile * h.TotalWeight
+	var sum float64
+	for _, index := range indexes {
+		sum += h.Weights[index]
+		if sum >= threshold {
+			return h.bucketStart(index + 1)
+		}
+	}
+	return h.bucketStart(indexes[len(indexes)-1] + 1)
+}
+
+// ContainerUsageHistory usage history of one container in application,
+// container is identified by index in version
+type ContainerUsageHistory struct {
+	Index int
+	//image repository without tag, history is dropped when it changed
+	Image string
+	//cpu cores
+	CPU *UsageHistogram
+	//memory MB
+	Memory          *UsageHistogram
+	FirstSampleTime int64
+	LastSampleTime  int64
+}
+
+// NewContainerUsageHistory create empty history for container
+func NewContainerUsageHistory(index int, image string) *ContainerUsageHistory {
+	return &ContainerUsageHistory{
+		Index:  index,
+		Image:  ImageRepository(image),
+		CPU:    NewUsageHistogram(CPUHistogramFirstBucket),
+		Memory: NewUsageHistogram(MemHistogramFirstBucket),
+	}
+}
+
+// AddUsage add one usage sample of container, memory sample is working set,
+// page cache that can be reclaimed is not counted.
+func (history *ContainerUsageHistory) AddUsage(usage *ContainerUsage, timestamp int64) {
+	if history.CPU.Samples == 0 || timestamp < history.FirstSampleTime {
+		history.FirstSampleTime = timestamp
+	}
+	memory := usage.MemoryWorkingSet
+	if memory == 0 {
+		//executor of old version reports no working set
+		memory = usage.MemoryUsage
+	}
+	history.CPU.AddSample(usage.CPUUsage, timestamp)
+	history.Memory.AddSample(float64(memory)/1024/1024, timestamp)
+	if timestamp > history.LastSampleTime {
+		history.LastSampleTime = timestamp
+	}
+}
+
+// Merge add samples of other history of the same container
+func (history *ContainerUsageHistory) Merge(other *ContainerUsageHistory) {
+	if other == nil || other.Image != history.Image {
+		return
+	}
+	if other.CPU.Samples == 0 {
+		return
+	}
+	if history.CPU.Samples == 0 || other.FirstSampleTime < history.FirstSampleTime {
+		history.FirstSampleTime = other.FirstSampleTime
+	}
+	if other.LastSampleTime > history.LastSampleTime {
+		history.LastSampleTime = other.LastSampleTime
+	}
+	history.CPU.Merge(other.CPU)
+	history.Memory.Merge(other.Memory)
+}
+
+// Recommend compute recommended resources of container.
+// cpu target is p90 usage, memory target is p95 usage, both with safety margin.
+// current & limit are resources defined in version, may be nil
+func (history *ContainerUsageHistory) Recommend(current, limit *Resource) *ContainerRecommendation {
+	recommend := &ContainerRecommendation{
+		Index:          history.Index,
+		Image:          history.Image,
+		Samples:        history.CPU.Samples,
+		HistorySeconds: history.LastSampleTime - history.FirstSampleTime,
+		Current:        current,
+		CurrentLimit:   limit,
+	}
+	if history.CPU.Samples < RecommendationMinSamples {
+		recommend.Message = "not enough usage samples"
+		return recommend
+	}
+	recommend.LowerBound = recommendResource(history, 0.5, 0.5)
+	recommend.Target = recommendResource(history, 0.9, 0.95)
+	recommend.UpperBound = recommendResource(history, 0.99, 0.99)
+	return recommend
+}
+
+func recommendResource(history *ContainerUsageHistory, cpuPercentile, memPercentile float64) *Resource {
+	cpu := history.CPU.Percentile(cpuPercentile) * (1 + RecommendationSafetyMargin)
+	mem := history.Memory.Percentile(memPercentile) * (1 + RecommendationSafetyMargin)
+	return &Resource{
+		Cpus: math.Max(math.Ceil(cpu*100)/100, RecommendationMinCPU),
+		Mem:  math.Max(math.Ceil(mem), RecommendationMinMem),
+	}
+}
+
+// AppUsageHistory usage history of all containers in application
+type AppUsageHistory struct {
+	RunAs      string
+	AppID      string
+	Containers []*ContainerUsageHistory
+	UpdateTime int64
+}
+
+// GetContainer get history of container by index, nil if not exist
+func (history *AppUsageHistory) GetContainer(index int) *ContainerUsageHistory {
+	for _, c := range history.Containers {
+		if c.Index == index {
+			return c
+		}
+	}
+	return nil
+}
+
+// Merge add samples of other application, used when deployment switch to new application
+func (history *AppUsageHistory) Merge(other *AppUsageHistory) {
+	if other == nil {
+		return
+	}
+	for _, c := range other.Containers {
+		curr := history.GetContainer(c.Index)
+		if curr == nil {
+			continue
+		}
+		curr.Merge(c)
+	}
+}
+
+// ResourceRecommendation recommended resources of application or deployment
+type ResourceRecommendation struct {
+	RunAs string `json:"namespace"`
+	AppID string `json:"application"`
+	//set when query by deployment
+	Deployment string                     `json:"deployment,omitempty"`
+	Containers []*ContainerRecommendation `json:"containers"`
+	CreateTime int64                      `json:"createTime"`
+}
+
+// ContainerRecommendation recommended resources of one container.
+// cpu in cores, memory in MB. Target is nil when history is not enough
+type ContainerRecommendation struct {
+	Index          int       `json:"index"`
+	Image          string    `json:"image"`
+	Samples        uint64    `json:"samples"`
+	HistorySeconds int64     `json:"historySeconds"`
+	Current        *Resource `json:"current,omitempty"`
+	CurrentLimit   *Resource `json:"currentLimit,omitempty"`
+	LowerBound     *Resource `json:"lowerBound,omitempty"`
+	Target         *Resource `json:"target,omitempty"`
+	UpperBound     *Resource `json:"upperBound,omitempty"`
+	Message        string    `json:"message,omitempty"`
+}
+
+// ImageRepository strip tag or digest from image name
+func ImageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// ApplyRecommendation set container resources with recommended target, containers whose
+// image repository changed or without enough history are skipped. limits are raised
+// when lower than target. returns indexes of containers applied
+func (version *Version) ApplyRecommendation(recommend *ResourceRecommendation) []int {
+	var applied []int
+	if recommend == nil {
+		return applied
+	}
+	for _, r := range recommend.Containers {
+		if r.Target == nil || r.Index < 0 || r.Index >= len(version.Container) {
+			continue
+		}
+		container := version.Container[r.Index]
+		if container.Docker == nil || ImageRepository(container.Docker.Image) != r.Image {
+			continue
+		}
+		if container.Resources == nil {
+			container.Resources = &Resource{}
+		}
+		container.Resources.Cpus = r.Target.Cpus
+		container.Resources.Mem = r.Target.Mem
+		if container.LimitResoures != nil {
+			if container.LimitResoures.Cpus > 0 && container.LimitResoures.Cpus < r.Target.Cpus {
+				container.LimitResoures.Cpus = r.Target.Cpus
+			}
+			if container.LimitResoures.Mem > 0 && container.LimitResoures.Mem < r.Target.Mem {
+				container.LimitResoures.Mem = r.Target.Mem
+			}
+		}
+		if container.DataClass != nil {
+			if container.DataClass.Resources == nil {
+				container.DataClass.Resources = &Resource{}
+			}
+			container.DataClass.Resources.Cpus = r.Target.Cpus
+			container.DataClass.Resources.Mem = r.Target.Mem
+			container.DataClass.Resources.Disk = container.Resources.Disk
+			if container.LimitResoures != nil {
+				limit := *container.LimitResoures
+				container.DataClass.LimitResources = &limit
+			}
+		}
+		applied = append(applied, r.Index)
+	}
+	return applied
+}
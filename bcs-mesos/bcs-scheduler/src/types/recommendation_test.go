@@ -0,0 +1,175 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUsageHistogramPercentile(t *testing.T) {
+	h := NewUsageHistogram(CPUHistogramFirstBucket)
+	//1..100 cores, one sample each
+	for i := 1; i <= 100; i++ {
+		h.AddSample(float64(i), 1000)
+	}
+	tests := []struct {
+		percentile float64
+		want       float64
+	}{
+		{0.5, 50},
+		{0.9, 90},
+		{0.99, 99},
+	}
+	for _, tc := range tests {
+		got := h.Percentile(tc.percentile)
+		//percentile is the end of bucket, never lower than real value
+		//and at most one bucket ratio higher
+		if got < tc.want || got > tc.want*histogramBucketRatio+CPUHistogramFirstBucket {
+			t.Errorf("percentile %v: got %v, want about %v", tc.percentile, got, tc.want)
+		}
+	}
+	if empty := NewUsageHistogram(1).Percentile(0.9); empty != 0 {
+		t.Errorf("empty histogram percentile: got %v, want 0", empty)
+	}
+}
+
+func TestUsageHistogramDecay(t *testing.T) {
+	h := NewUsageHistogram(MemHistogramFirstBucket)
+	//old samples at 1000MB, newer samples two half lives later at 100MB weight 4 times more
+	for i := 0; i < 10; i++ {
+		h.AddSample(1000, 0)
+	}
+	for i := 0; i < 10; i++ {
+		h.AddSample(100, 2*UsageHalfLife)
+	}
+	if got := h.Percentile(0.5); got > 110 {
+		t.Errorf("median after decay: got %v, want about 100", got)
+	}
+	if got := h.Percentile(0.9); got < 1000 {
+		t.Errorf("p90 after decay: got %v, want about 1000", got)
+	}
+
+	//shifting reference keeps percentiles and drops negligible buckets
+	h.AddSample(100, 40*UsageHalfLife)
+	if h.ReferenceTime != 40*UsageHalfLife {
+		t.Errorf("reference time not shifted: %d", h.ReferenceTime)
+	}
+	if len(h.Weights) != 1 || math.IsInf(h.TotalWeight, 0) {
+		t.Errorf("old buckets not dropped: %v", h.Weights)
+	}
+}
+
+func TestContainerRecommend(t *testing.T) {
+	history := NewContainerUsageHistory(0, "nginx:1.17")
+	if r := history.Recommend(nil, nil); r.Target != nil || r.Message == "" {
+		t.Errorf("recommendation without samples: %+v", r)
+	}
+	for i := 0; i < int(RecommendationMinSamples); i++ {
+		history.AddUsage(&ContainerUsage{CPUUsage: 0.5, MemoryUsage: 200 * 1024 * 1024}, int64(i*60))
+	}
+	r := history.Recommend(&Resource{Cpus: 4, Mem: 4096}, nil)
+	if r.Target == nil {
+		t.Fatalf("no recommendation with enough samples: %+v", r)
+	}
+	if r.Target.Cpus < 0.5*(1+RecommendationSafetyMargin) || r.Target.Cpus > 0.7 {
+		t.Errorf("target cpu: got %v", r.Target.Cpus)
+	}
+	if r.Target.Mem < 200*(1+RecommendationSafetyMargin) || r.Target.Mem > 260 {
+		t.Errorf("target mem: got %v", r.Target.Mem)
+	}
+	if r.LowerBound.Cpus > r.Target.Cpus || r.UpperBound.Mem < r.Target.Mem {
+		t.Errorf("bounds not ordered: %+v %+v %+v", r.LowerBound, r.Target, r.UpperBound)
+	}
+	if r.HistorySeconds != int64(RecommendationMinSamples-1)*60 {
+		t.Errorf("history seconds: got %d", r.HistorySeconds)
+	}
+}
+
+func TestApplyRecommendation(t *testing.T) {
+	version := &Version{
+		Container: []*Container{
+			{
+				Docker:        &Docker{Image: "registry/nginx:1.18"},
+				Resources:     &Resource{Cpus: 4, Mem: 4096, Disk: 10},
+				LimitResoures: &Resource{Cpus: 4, Mem: 128},
+				DataClass:     &DataClass{Resources: &Resource{Cpus: 4, Mem: 4096, Disk: 10}},
+			},
+			{
+				Docker:    &Docker{Image: "registry/sidecar:1.0"},
+				Resources: &Resource{Cpus: 1, Mem: 512},
+			},
+		},
+	}
+	recommend := &ResourceRecommendation{
+		Containers: []*ContainerRecommendation{
+			{Index: 0, Image: "registry/nginx", Target: &Resource{Cpus: 0.6, Mem: 256}},
+			//image changed
+			{Index: 1, Image: "registry/other", Target: &Resource{Cpus: 0.1, Mem: 64}},
+			//out of range
+			{Index: 2, Image: "registry/nginx", Target: &Resource{Cpus: 0.1, Mem: 64}},
+		},
+	}
+	applied := version.ApplyRecommendation(recommend)
+	if len(applied) != 1 || applied[0] != 0 {
+		t.Fatalf("applied containers: got %v, want [0]", applied)
+	}
+	c := version.Container[0]
+	if c.Resources.Cpus != 0.6 || c.Resources.Mem != 256 || c.Resources.Disk != 10 {
+		t.Errorf("resources not applied: %+v", c.Resources)
+	}
+	if c.DataClass.Resources.Cpus != 0.6 || c.DataClass.Resources.Mem != 256 {
+		t.Errorf("dataclass resources not applied: %+v", c.DataClass.Resources)
+	}
+	if c.LimitResoures.Cpus != 4 || c.LimitResoures.Mem != 256 {
+		t.Errorf("limit resources: got %+v, want cpu 4 mem 256", c.LimitResoures)
+	}
+	if version.Container[1].Resources.Cpus != 1 {
+		t.Errorf("container with changed image should not be applied")
+	}
+}
+
+func TestImageRepository(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"nginx", "nginx"},
+		{"nginx:1.17", "nginx"},
+		{"registry:5000/ns/nginx", "registry:5000/ns/nginx"},
+		{"registry:5000/ns/nginx:1.17", "registry:5000/ns/nginx"},
+		{"nginx@sha256:abcd", "nginx"},
+	}
+	for _, tc := range tests {
+		if got := ImageRepository(tc.image); got != tc.want {
+			t.Errorf("ImageRepository(%s): got %s, want %s", tc.image, got, tc.want)
+		}
+	}
+}
+
+func TestContainerRecommendWorkingSet(t *testing.T) {
+	history := NewContainerUsageHistory(0, "nginx:1.17")
+	for i := 0; i < int(RecommendationMinSamples); i++ {
+		//page cache is not counted, executor of old version reports no working set
+		usage := &ContainerUsage{CPUUsage: 0.5, MemoryUsage: 1000 * 1024 * 1024, MemoryWorkingSet: 200 * 1024 * 1024}
+		if i%2 == 0 {
+			usage = &ContainerUsage{CPUUsage: 0.5, MemoryUsage: 200 * 1024 * 1024}
+		}
+		history.AddUsage(usage, int64(i*60))
+	}
+	r := history.Recommend(nil, nil)
+	if r.Target == nil || r.Target.Mem < 200*(1+RecommendationSafetyMargin) || r.Target.Mem > 260 {
+		t.Errorf("target mem from working set: got %+v", r.Target)
+	}
+}
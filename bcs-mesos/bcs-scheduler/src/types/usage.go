@@ -0,0 +1,76 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package types
+
+//TaskGroupUsage resource usage of all containers in one taskgroup,
+//sampled by bcs-container-executor and reported through framework message
+type TaskGroupUsage struct {
+	//sampling time, unix seconds
+	Timestamp int64
+	//sampling interval of executor, seconds
+	Interval   int64
+	Containers []*ContainerUsage
+}
+
+//ContainerUsage resource usage of one container. all counters are
+//cumulative since container started, rates are computed by executor
+//between two samplings
+type ContainerUsage struct {
+	TaskID      string
+	ContainerID string
+	//cpu, CPUUsage is cores used during last interval
+	CPUUsage           float64
+	CPUTotalNanos      uint64
+	CPUPeriods         uint64
+	CPUThrottledPeriod uint64
+	CPUThrottledNanos  uint64
+	//memory, bytes, working set is usage without inactive file cache
+	MemoryUsage      uint64
+	MemoryWorkingSet uint64
+	MemoryLimit      uint64
+	MemoryRSS        uint64
+	MemoryCache      uint64
+	MemoryFailCount  uint64
+	OOMEvents        uint64
+	//network, bytes & packets of all interfaces
+	NetRxBytes   uint64
+	NetTxBytes   uint64
+	NetRxPackets uint64
+	NetTxPackets uint64
+	NetRxDropped uint64
+	NetTxDropped uint64
+	//block io, bytes & operations of all devices
+	BlkReadBytes  uint64
+	BlkWriteBytes uint64
+	BlkReadOps    uint64
+	BlkWriteOps   uint64
+}
+
+//CPUUsage total cores used by all containers in taskgroup
+func (usage *TaskGroupUsage) CPUUsage() float64 {
+	var total float64
+	for _, c := range usage.Containers {
+		total += c.CPUUsage
+	}
+	return total
+}
+
+//MemoryUsage total memory bytes used by all containers in taskgroup
+func (usage *TaskGroupUsage) MemoryUsage() uint64 {
+	var total uint64
+	for _, c := range usage.Containers {
+		total += c.MemoryUsage
+	}
+	return total
+}
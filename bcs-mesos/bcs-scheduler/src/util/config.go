@@ -29,6 +29,7 @@ type SchedulerOptions struct {
 	conf.ZkConfig
 	conf.CertConfig
 	conf.LicenseServerConfig
+	conf.TracingConfig
 
 	conf.LogConfig
 	conf.ProcessConfig
@@ -44,6 +45,8 @@ type SchedulerOptions struct {
 	ProcessExecutor   string `json:"process_executor" value:"" usage:"the process executor path"`
 	CniDir            string `json:"cni_dir" value:"" usage:"the cni directory"`
 	NetImage          string `json:"net_image" value:"" usage:"the network image"`
+
+	MaintenanceDrainAhead int64 `json:"maintenance_drain_ahead" value:"0" usage:"seconds ahead of mesos maintenance window to drain agents automatically, 0 means never"`
 }
 
 type SchedConfig struct {
@@ -79,6 +82,8 @@ type Scheduler struct {
 	ProcessExecutor   string
 	CniDir            string
 	NetImage          string
+
+	MaintenanceDrainAhead int64
 }
 
 type HttpListener struct {
@@ -152,6 +157,7 @@ func SetSchedulerCfg(config *SchedConfig, op *SchedulerOptions) {
 	config.Scheduler.ProcessExecutor = op.ProcessExecutor
 	config.Scheduler.CniDir = op.CniDir
 	config.Scheduler.NetImage = op.NetImage
+	config.Scheduler.MaintenanceDrainAhead = op.MaintenanceDrainAhead
 
 	config.HttpListener.TCPAddr = op.Address + ":" + strconv.Itoa(int(op.Port))
 	//config.HttpListener.CertDir = op.ServerCertDir
@@ -31,6 +31,11 @@ const (
 	ActionManage Action = "cluster-manager"
 	ActionRead   Action = "cluster-readonly"
 
+	VerbGet    = "get"
+	VerbCreate = "create"
+	VerbUpdate = "update"
+	VerbDelete = "delete"
+
 	TokenDefaultExpireTime = 2 * time.Hour
 	TokenRandomLength      = 10
 )
@@ -39,6 +44,7 @@ type Token struct {
 	Token      string    `json:"token"`
 	Username   string    `json:"username"`
 	Message    string    `json:"message"`
+	Groups     []string  `json:"groups,omitempty"`
 	ExpireTime time.Time `json:"expire_time"`
 
 	CreateTime time.Time `json:"create_time"`
@@ -59,6 +65,28 @@ func (t *Token) Generate() {
 type Resource struct {
 	ClusterID string `json:"cluster_id"`
 	Namespace string `json:"namespace"`
+
+	// Kind and Verb describe the request in detail for fine grained authorizers such as the local policy engine,
+	// bkiam only cares about the action
+	Kind string `json:"kind,omitempty"`
+	Verb string `json:"verb,omitempty"`
+}
+
+// VerbOf returns the verb of request with the action and http method, read action is always get
+// no matter what the method is
+func VerbOf(action Action, method string) string {
+	if action == ActionRead {
+		return VerbGet
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return VerbGet
+	case http.MethodPost:
+		return VerbCreate
+	case http.MethodDelete:
+		return VerbDelete
+	}
+	return VerbUpdate
 }
 
 var letterRunes = []rune("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
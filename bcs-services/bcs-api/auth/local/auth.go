@@ -0,0 +1,120 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package local
+
+import (
+	"fmt"
+	"net/http"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-services/bcs-api/auth"
+	"bk-bcs/bcs-services/bcs-api/config"
+	pkgauth "bk-bcs/bcs-services/bcs-api/pkg/auth"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
+)
+
+const (
+	BcsUserTokenKey = "X-Bcs-User-Token"
+)
+
+func NewAuth(conf *config.ApiServConfig) (auth.BcsAuth, error) {
+	return &Auth{}, nil
+}
+
+// Auth checks the authority with roles and rolebindings stored in bcs-api database,
+// it is used when there is no bk-iam
+type Auth struct{}
+
+// GetToken finds the user by bcs user token, or by the external authenticators such as oidc
+func (a *Auth) GetToken(header http.Header) (*auth.Token, error) {
+	req := &http.Request{Header: header}
+
+	tokenString := header.Get(BcsUserTokenKey)
+	if tokenString == "" {
+		tokenString = pkgauth.NewTokenAuthenticater(req, &pkgauth.TokenAuthConfig{SourceBearerEnabled: true}).ParseTokenBearer()
+	}
+	if tokenString != "" {
+		if userToken := sqlstore.GetUserToken(tokenString); userToken != nil {
+			if userToken.HasExpired() || userToken.IsRevoked() {
+				return nil, fmt.Errorf("user token has expired or been revoked")
+			}
+			user := sqlstore.GetUser(userToken.UserId)
+			if user == nil {
+				return nil, fmt.Errorf("user of token not found")
+			}
+			return &auth.Token{Token: tokenString, Username: user.Name}, nil
+		}
+	}
+
+	user, ok, err := pkgauth.GExternalAuthenticator.AuthenticateRequest(req)
+	if err != nil {
+		blog.Errorf("local auth authenticate request failed: %v", err)
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no valid user token found")
+	}
+	return &auth.Token{Token: tokenString, Username: user.Name, Groups: user.Groups}, nil
+}
+
+// Allow checks the request by rolebindings of the user and its groups, super user is always allowed
+func (a *Auth) Allow(token *auth.Token, action auth.Action, resource auth.Resource) (bool, error) {
+	if token.Username == "" {
+		blog.Errorf("local auth get a empty username")
+		return false, fmt.Errorf("get a empty username")
+	}
+
+	verb := resource.Verb
+	if verb == "" {
+		verb = auth.VerbOf(action, "")
+	}
+
+	// scoped user token is limited to its clusters, namespaces and read-only requests, even for super user
+	if userToken := sqlstore.GetUserToken(token.Token); userToken != nil {
+		if !userToken.Permits(resource.ClusterID, resource.Namespace, verb != auth.VerbGet) {
+			blog.Infof("local auth: token %s of user %s is not permitted to %s namespace %s of cluster %s",
+				userToken.Name, token.Username, verb, resource.Namespace, resource.ClusterID)
+			return false, nil
+		}
+	}
+
+	user := sqlstore.GetUserByCondition(&m.User{Name: token.Username})
+	if user != nil && user.IsSuperUser {
+		return true, nil
+	}
+
+	bindings, err := sqlstore.ListRoleBindingsBySubjects(token.Username, token.Groups)
+	if err != nil {
+		blog.Errorf("local auth list rolebindings of user %s failed: %v", token.Username, err)
+		return false, err
+	}
+
+	roles := make(map[string]*m.Role)
+	for _, binding := range bindings {
+		if _, ok := roles[binding.RoleName]; ok {
+			continue
+		}
+		if role := sqlstore.GetRole(binding.RoleName); role != nil {
+			roles[binding.RoleName] = role
+		}
+	}
+
+	return Authorize(bindings, roles, Attributes{
+		ClusterID: resource.ClusterID,
+		Namespace: resource.Namespace,
+		Kind:      resource.Kind,
+		Verb:      verb,
+	}), nil
+}
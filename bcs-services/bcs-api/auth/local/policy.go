@@ -0,0 +1,67 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package local
+
+import (
+	"strings"
+
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+)
+
+// Attributes describes the request to be authorized
+type Attributes struct {
+	ClusterID string
+	Namespace string
+	Kind      string
+	Verb      string
+}
+
+// Authorize returns true if any of the bindings grants the request, roles are indexed by name.
+// A binding scoped to a namespace never grants requests on cluster level resources.
+func Authorize(bindings []m.RoleBinding, roles map[string]*m.Role, attrs Attributes) bool {
+	for _, binding := range bindings {
+		if !bindingMatches(&binding, attrs) {
+			continue
+		}
+		role, ok := roles[binding.RoleName]
+		if !ok {
+			continue
+		}
+		for _, rule := range role.Rules {
+			if contains(rule.Kinds, attrs.Kind) && contains(rule.Verbs, attrs.Verb) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func bindingMatches(binding *m.RoleBinding, attrs Attributes) bool {
+	if binding.ClusterID != m.PolicyMatchAll && !strings.EqualFold(binding.ClusterID, attrs.ClusterID) {
+		return false
+	}
+	if binding.Namespace == "" || binding.Namespace == m.PolicyMatchAll {
+		return true
+	}
+	return binding.Namespace == attrs.Namespace
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == m.PolicyMatchAll || item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package local
+
+import (
+	"testing"
+
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+)
+
+func TestAuthorize(t *testing.T) {
+	roles := map[string]*m.Role{
+		"admin": {Name: "admin", Rules: m.PolicyRules{{Kinds: []string{"*"}, Verbs: []string{"*"}}}},
+		"viewer": {Name: "viewer", Rules: m.PolicyRules{
+			{Kinds: []string{"applications", "deployments"}, Verbs: []string{"get"}},
+			{Kinds: []string{"storage"}, Verbs: []string{"get"}},
+		}},
+		"deployer": {Name: "deployer", Rules: m.PolicyRules{{Kinds: []string{"deployments"}, Verbs: []string{"get", "create", "update"}}}},
+	}
+
+	tests := []struct {
+		name     string
+		bindings []m.RoleBinding
+		attrs    Attributes
+		expected bool
+	}{
+		{
+			name:     "no binding",
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Kind: "applications", Verb: "get"},
+			expected: false,
+		},
+		{
+			name:     "admin of all clusters",
+			bindings: []m.RoleBinding{{RoleName: "admin", ClusterID: "*"}},
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Namespace: "ns1", Kind: "secrets", Verb: "delete"},
+			expected: true,
+		},
+		{
+			name:     "cluster id is case insensitive",
+			bindings: []m.RoleBinding{{RoleName: "viewer", ClusterID: "bcs-mesos-10001"}},
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Namespace: "ns1", Kind: "applications", Verb: "get"},
+			expected: true,
+		},
+		{
+			name:     "other cluster",
+			bindings: []m.RoleBinding{{RoleName: "admin", ClusterID: "BCS-MESOS-10002"}},
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Kind: "applications", Verb: "get"},
+			expected: false,
+		},
+		{
+			name:     "verb not granted",
+			bindings: []m.RoleBinding{{RoleName: "viewer", ClusterID: "*"}},
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Namespace: "ns1", Kind: "applications", Verb: "delete"},
+			expected: false,
+		},
+		{
+			name:     "kind not granted",
+			bindings: []m.RoleBinding{{RoleName: "deployer", ClusterID: "*"}},
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Namespace: "ns1", Kind: "applications", Verb: "get"},
+			expected: false,
+		},
+		{
+			name:     "namespace binding",
+			bindings: []m.RoleBinding{{RoleName: "deployer", ClusterID: "BCS-MESOS-10001", Namespace: "ns1"}},
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Namespace: "ns1", Kind: "deployments", Verb: "update"},
+			expected: true,
+		},
+		{
+			name:     "namespace binding on other namespace",
+			bindings: []m.RoleBinding{{RoleName: "deployer", ClusterID: "BCS-MESOS-10001", Namespace: "ns1"}},
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Namespace: "ns2", Kind: "deployments", Verb: "update"},
+			expected: false,
+		},
+		{
+			name:     "namespace binding on cluster level resource",
+			bindings: []m.RoleBinding{{RoleName: "admin", ClusterID: "BCS-MESOS-10001", Namespace: "ns1"}},
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Kind: "agentsettings", Verb: "update"},
+			expected: false,
+		},
+		{
+			name:     "any of the bindings",
+			bindings: []m.RoleBinding{{RoleName: "viewer", ClusterID: "*"}, {RoleName: "deployer", ClusterID: "*", Namespace: "*"}},
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Namespace: "ns1", Kind: "deployments", Verb: "create"},
+			expected: true,
+		},
+		{
+			name:     "role not exist",
+			bindings: []m.RoleBinding{{RoleName: "unknown", ClusterID: "*"}},
+			attrs:    Attributes{ClusterID: "BCS-MESOS-10001", Kind: "applications", Verb: "get"},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		if got := Authorize(test.bindings, roles, test.attrs); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestPolicyRulesScan(t *testing.T) {
+	rules := m.PolicyRules{{Kinds: []string{"deployments"}, Verbs: []string{"get", "update"}}}
+	value, err := rules.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var scanned m.PolicyRules
+	if err := scanned.Scan([]byte(value.(string))); err != nil {
+		t.Fatal(err)
+	}
+	if len(scanned) != 1 || scanned[0].Kinds[0] != "deployments" || len(scanned[0].Verbs) != 2 {
+		t.Errorf("unexpected scanned rules: %v", scanned)
+	}
+}
@@ -50,6 +50,7 @@ type ApiServConfig struct {
 	VerifyClientTLS bool
 
 	BKIamAuth options.AuthOption
+	AuthMode  string
 
 	ServCert   *CertConfig
 	ClientCert *CertConfig
@@ -58,6 +59,9 @@ type ApiServConfig struct {
 	TKE                      options.TKEOptions
 	Edition                  string
 	MesosWebconsoleProxyPort uint
+	Audit                    options.AuditOption
+	Authentication           options.AuthenticationOption
+	RateLimit                options.RateLimitOption
 }
 
 var (
@@ -67,6 +71,7 @@ var (
 	ClusterCredentialsFixtures options.CredentialsFixturesOptions
 	MesosWebconsoleProxyPort   uint
 	TkeConf                    options.TKEOptions
+	Authentication             options.AuthenticationOption
 )
 
 //NewApiServConfig create a config object
@@ -23,7 +23,11 @@ import (
 	"bk-bcs/bcs-common/common/blog"
 	"bk-bcs/bcs-services/bcs-api/auth"
 	"bk-bcs/bcs-services/bcs-api/auth/bkiam"
+	"bk-bcs/bcs-services/bcs-api/auth/local"
 	"bk-bcs/bcs-services/bcs-api/config"
+	"bk-bcs/bcs-services/bcs-api/pkg/audit"
+	"bk-bcs/bcs-services/bcs-api/pkg/federation"
+	"bk-bcs/bcs-services/bcs-api/pkg/ratelimit"
 
 	"github.com/emicklei/go-restful"
 )
@@ -31,20 +35,37 @@ import (
 const (
 	BcsClusterIDHeaderKey = "BCS-ClusterID"
 	ApiPrefix             = `/bcsapi/[^/]+/[^/]+`
-)
 
-func NewAuthFilter(conf *config.ApiServConfig) (RequestFilterFunction, error) {
-	myAuth, err := bkiam.NewAuth(conf)
-	if err != nil {
-		return nil, err
-	}
+	AuthModeBKIam = "bkiam"
+	AuthModeLocal = "local"
+)
 
+func NewAuthFilter(conf *config.ApiServConfig, myAuth auth.BcsAuth) (RequestFilterFunction, error) {
 	return &AuthFilter{
 		conf: conf,
 		auth: myAuth,
 	}, nil
 }
 
+// NewBcsAuth returns the auth of the configured auth mode, bkiam by default
+func NewBcsAuth(conf *config.ApiServConfig) (auth.BcsAuth, error) {
+	switch conf.AuthMode {
+	case AuthModeLocal:
+		return local.NewAuth(conf)
+	case AuthModeBKIam, "":
+		return bkiam.NewAuth(conf)
+	}
+	return nil, fmt.Errorf("unknown auth mode: %s", conf.AuthMode)
+}
+
+// AuditTokenType returns the token type of configured auth mode in audit logs
+func AuditTokenType(conf *config.ApiServConfig) string {
+	if conf.AuthMode == "" {
+		return AuthModeBKIam
+	}
+	return conf.AuthMode
+}
+
 type AuthFilter struct {
 	conf *config.ApiServConfig
 	auth auth.BcsAuth
@@ -55,50 +76,121 @@ func (af *AuthFilter) Execute(req *restful.Request) (errCode int, err error) {
 	if err != nil {
 		return common.BcsErrApiAuthCheckFail, fmt.Errorf("%s: %v", common.BcsErrApiAuthCheckFailStr, err)
 	}
+	audit.SetUser(req.Request.Context(), token.Username, AuditTokenType(af.conf))
 
 	clusterID := req.Request.Header.Get(BcsClusterIDHeaderKey)
 	method := req.Request.Method
 	uri := req.Request.URL.Path
 
-	authRuleList := make([]*AuthURLRule, 0)
+	if strings.HasPrefix(uri, federation.ApiPrefix) {
+		return af.authorizeFederation(req, token)
+	}
+
+	matchedClusterID, errCode, err := af.allow(token, authRules(uri), clusterID, uri, method)
+	audit.SetCluster(req.Request.Context(), matchedClusterID)
+	if err != nil {
+		return errCode, err
+	}
 
+	if clusterID == "" {
+		clusterID = matchedClusterID
+	}
+	req.SetAttribute(ratelimit.RequestAttribute, ratelimit.Request{
+		User:      token.Username,
+		Token:     token.Token,
+		ClusterID: clusterID,
+		Class:     ratelimit.ClassOf(method, false),
+	})
+	return 0, nil
+}
+
+// authorizeFederation selects member clusters of the federated request and checks the authority in each of them
+// as a single cluster request. Member clusters are stored in the request attribute for the fan-out handler,
+// the ones without authority are reported as forbidden by the handler rather than rejecting the whole request.
+func (af *AuthFilter) authorizeFederation(req *restful.Request, token *auth.Token) (errCode int, err error) {
+	clusterIDs, err := federation.SelectClusters(req.Request.Header)
+	if err != nil {
+		return common.BcsErrApiBadRequest, err
+	}
+
+	targets := make([]federation.Target, 0, len(clusterIDs))
+	allowedClusterIDs := make([]string, 0, len(clusterIDs))
+	for _, clusterID := range clusterIDs {
+		target := federation.Target{ClusterID: clusterID}
+		memberPath, err := federation.MemberPath(req.Request.URL.Path, clusterID)
+		if err != nil {
+			return common.BcsErrApiBadRequest, err
+		}
+		_, target.Code, target.Err = af.allow(token, authRules(memberPath), clusterID, memberPath, req.Request.Method)
+		if target.Err == nil {
+			allowedClusterIDs = append(allowedClusterIDs, clusterID)
+		}
+		targets = append(targets, target)
+	}
+	req.SetAttribute(federation.TargetsAttribute, targets)
+	// federated requests are charged once in budgets of user and token, and in the budget of every member cluster
+	// they are sent to, so that they can not bypass the cluster budgets
+	req.SetAttribute(ratelimit.RequestAttribute, ratelimit.Request{
+		User:       token.Username,
+		Token:      token.Token,
+		ClusterIDs: allowedClusterIDs,
+		Class:      ratelimit.ClassOf(req.Request.Method, false),
+	})
+	return 0, nil
+}
+
+// authRules returns the auth rules for the uri, nil means the uri need not be checked
+func authRules(uri string) []*AuthURLRule {
 	switch AuthRuleRegex.ReplaceAllString(uri, "$1") {
 	case "storage":
-		authRuleList = StorageAuthRule
+		return StorageAuthRule
 	case "metric":
-		authRuleList = MetricAuthRule
+		return MetricAuthRule
 	case "scheduler":
 		if AuthRuleRegex.ReplaceAllString(uri, "$2") == "mesos" {
-			authRuleList = MesosAuthRule
-			break
+			return MesosAuthRule
 		}
-		fallthrough
-	default:
-		// no rule match the uri prefix, then just let it pass
-		return 0, nil
 	}
+	return nil
+}
 
+// allow checks the authority of token for the request by the first matched rule,
+// it returns the cluster id of matched rule which is empty if no rule matches.
+func (af *AuthFilter) allow(token *auth.Token, authRuleList []*AuthURLRule, clusterID, uri, method string) (string, int, error) {
 	for _, rule := range authRuleList {
 		match, action, resource := rule.Match(clusterID, "", uri, method)
 		if match {
 			// no cluster id means can not be check auth, just let it pass
 			if resource.ClusterID == "" {
-				return 0, nil
+				return "", 0, nil
 			}
+			resource.Kind = resourceKind(uri)
+			resource.Verb = auth.VerbOf(action, method)
 			ok, err := af.auth.Allow(token, action, resource)
 			if err != nil {
 				blog.Errorf("AuthFilter Execute get auth allow failed: %v", err)
-				return common.BcsErrApiAuthCheckFail, fmt.Errorf("%s: %s", common.BcsErrApiAuthCheckFailStr, err.Error())
+				return resource.ClusterID, common.BcsErrApiAuthCheckFail, fmt.Errorf("%s: %s", common.BcsErrApiAuthCheckFailStr, err.Error())
 			}
 			if !ok {
-				return common.BcsErrApiAuthCheckNoAuthority, fmt.Errorf(common.BcsErrApiAuthCheckFailStr)
+				return resource.ClusterID, common.BcsErrApiAuthCheckNoAuthority, fmt.Errorf(common.BcsErrApiAuthCheckFailStr)
 			}
-			return 0, nil
+			return resource.ClusterID, 0, nil
 		}
 	}
 
-	// no rule list match the uri, then just let it pass
-	return 0, nil
+	// no rule match the uri, then just let it pass
+	return "", 0, nil
+}
+
+// resourceKind returns the kind of resource in uri for fine grained authorizers.
+// It is the first segment after namespace for mesos api, such as applications and deployments,
+// or the segment after mesos for cluster level ones, such as agentsettings.
+// All the storage and metric apis are of kind storage and metric.
+func resourceKind(uri string) string {
+	if ResourceKindRegex.MatchString(uri) {
+		return ResourceKindRegex.ReplaceAllString(uri, "$1")
+	}
+	return AuthRuleRegex.ReplaceAllString(uri, "$1")
 }
 
 const (
@@ -169,7 +261,8 @@ func (aur *AuthURLRule) Match(clusterID, namespace, uri, method string) (match b
 }
 
 var (
-	AuthRuleRegex = regexp.MustCompile(`^/bcsapi/[^/]+/([^/]+)/([^/]+).*`)
+	AuthRuleRegex     = regexp.MustCompile(`^/bcsapi/[^/]+/([^/]+)/([^/]+).*`)
+	ResourceKindRegex = regexp.MustCompile(`^/bcsapi/[^/]+/scheduler/mesos/(?:namespaces/[^/]+/)?([^/]+).*`)
 
 	StorageAuthRule = []*AuthURLRule{
 		// storage dynamic query
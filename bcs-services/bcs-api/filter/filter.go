@@ -18,13 +18,15 @@ import (
 
 	"bk-bcs/bcs-common/common/blog"
 	bcshttp "bk-bcs/bcs-common/common/http"
+	"bk-bcs/bcs-services/bcs-api/auth"
 	"bk-bcs/bcs-services/bcs-api/config"
+	"bk-bcs/bcs-services/bcs-api/pkg/ratelimit"
 
 	"github.com/emicklei/go-restful"
 )
 
-func NewFilter(conf *config.ApiServConfig) (*GeneralFilter, error) {
-	authFilter, err := NewAuthFilter(conf)
+func NewFilter(conf *config.ApiServConfig, myAuth auth.BcsAuth) (*GeneralFilter, error) {
+	authFilter, err := NewAuthFilter(conf, myAuth)
 	if err != nil {
 		blog.Errorf("NewFilter get auth filter failed: %v", err)
 		return nil, err
@@ -58,5 +60,15 @@ func (gf *GeneralFilter) Filter(req *restful.Request, resp *restful.Response, ch
 			return
 		}
 	}
+
+	// requests are limited after authenticated, the ones without rate limit request attribute are not limited
+	if limitRequest, ok := req.Attribute(ratelimit.RequestAttribute).(ratelimit.Request); ok {
+		release, retryAfter, limitedBy, allowed := ratelimit.Allow(limitRequest)
+		if !allowed {
+			ratelimit.WriteRejected(resp.ResponseWriter, retryAfter, limitedBy)
+			return
+		}
+		defer release()
+	}
 	chain.ProcessFilter(req, resp)
 }
@@ -19,9 +19,12 @@ import (
 	"bk-bcs/bcs-common/common/blog/glog"
 	"bk-bcs/bcs-common/common/license"
 	"bk-bcs/bcs-common/common/metric"
+	"bk-bcs/bcs-common/common/tracing"
 	commtype "bk-bcs/bcs-common/common/types"
 	"bk-bcs/bcs-services/bcs-api/config"
 	"bk-bcs/bcs-services/bcs-api/options"
+	"bk-bcs/bcs-services/bcs-api/pkg/audit"
+	"bk-bcs/bcs-services/bcs-api/pkg/ratelimit"
 	"bk-bcs/bcs-services/bcs-api/processor"
 	//"bk-bcs/bcs-services/bcs-api/regdiscv"
 	"fmt"
@@ -59,6 +62,10 @@ func main() {
 	defer blog.CloseLogs()
 
 	blog.Info("init config success")
+	if err := tracing.InitTracer("bcs-api", op.TracingConfig); err != nil {
+		blog.Errorf("init tracer failed: %s", err.Error())
+	}
+	defer tracing.Shutdown()
 	license.CheckLicense(op.LicenseServerConfig)
 
 	//run apiserver
@@ -105,16 +112,21 @@ func parseConfig(op *options.ServerOption) *config.ApiServConfig {
 	apiServConfig.LocalIp = op.LocalIP
 	apiServConfig.MetricPort = op.MetricPort
 	apiServConfig.BKIamAuth = op.BKIamAuth
+	apiServConfig.AuthMode = op.AuthMode
 	apiServConfig.BKE = op.BKE
 	apiServConfig.TKE = op.TKE
 	apiServConfig.Edition = op.Edition
 	apiServConfig.MesosWebconsoleProxyPort = op.MesosWebconsoleProxyPort
+	apiServConfig.Audit = op.Audit
+	apiServConfig.Authentication = op.Authentication
+	apiServConfig.RateLimit = op.RateLimit
 	config.Edition = apiServConfig.Edition
 	config.BKIamAuth = apiServConfig.BKIamAuth
 	config.TurnOnRBAC = apiServConfig.BKE.TurnOnRBAC
 	config.ClusterCredentialsFixtures = apiServConfig.BKE.ClusterCredentialsFixtures
 	config.MesosWebconsoleProxyPort = apiServConfig.MesosWebconsoleProxyPort
 	config.TkeConf = op.TKE
+	config.Authentication = op.Authentication
 
 	//server cert directory
 	if op.CertConfig.ServerCertFile != "" && op.CertConfig.ServerKeyFile != "" {
@@ -186,7 +198,8 @@ func runMetric(conf *config.ApiServConfig, err error) {
 
 	if err := metric.NewMetricController(
 		metricConf,
-		healthFunc); nil != err {
+		healthFunc,
+		append(ratelimit.Metrics(), audit.Metrics()...)...); nil != err {
 		blog.Errorf("run metric fail: %s", err.Error())
 	}
 
@@ -29,11 +29,14 @@ type ServerOption struct {
 	conf.ProcessConfig
 	conf.LocalConfig
 	conf.CustomCertConfig
+	conf.TracingConfig
 
 	VerifyClientTLS bool `json:"verify_client_tls" value:"false" usage:"verify client when brings up a tls server" mapstructure:"verify_client_tls"`
 
 	BKIamAuth AuthOption `json:"bkiam_auth"`
 
+	AuthMode string `json:"auth_mode" value:"bkiam" usage:"authorization mode, available: bkiam, local. local uses roles and rolebindings stored in bcs-api database" mapstructure:"auth_mode"`
+
 	BKE BKEOptions `json:"bke"`
 
 	Edition string `json:"edition" value:"ieod" usage:"api edition"`
@@ -41,6 +44,12 @@ type ServerOption struct {
 	MesosWebconsoleProxyPort uint `json:"mesos_webconsole_proxy_port" value:"8083" usage:"Port to connect to mesos webconsole proxy"`
 
 	TKE TKEOptions `json:"tke"`
+
+	Audit AuditOption `json:"audit"`
+
+	Authentication AuthenticationOption `json:"authentication"`
+
+	RateLimit RateLimitOption `json:"ratelimit"`
 }
 
 type BKEOptions struct {
@@ -87,6 +96,63 @@ type BootStrapUser struct {
 	Tokens      []string `json:"tokens"`
 }
 
+type AuthenticationOption struct {
+	OIDCIssuerURL      string `json:"oidc_issuer_url" value:"" usage:"https issuer url of OpenID Connect provider, oidc authentication is disabled if empty" mapstructure:"oidc_issuer_url"`
+	OIDCClientID       string `json:"oidc_client_id" value:"" usage:"client id of bcs-api in OpenID Connect provider, tokens must be issued for it" mapstructure:"oidc_client_id"`
+	OIDCCAFile         string `json:"oidc_ca_file" value:"" usage:"CA file for connecting to OpenID Connect provider, system CAs are used if empty" mapstructure:"oidc_ca_file"`
+	OIDCUsernameClaim  string `json:"oidc_username_claim" value:"sub" usage:"claim of id token used as user name" mapstructure:"oidc_username_claim"`
+	OIDCUsernamePrefix string `json:"oidc_username_prefix" value:"" usage:"prefix prepended to user name from id token" mapstructure:"oidc_username_prefix"`
+	OIDCGroupsClaim    string `json:"oidc_groups_claim" value:"groups" usage:"claim of id token used as user groups" mapstructure:"oidc_groups_claim"`
+	OIDCGroupsPrefix   string `json:"oidc_groups_prefix" value:"" usage:"prefix prepended to groups from id token" mapstructure:"oidc_groups_prefix"`
+	TokenAuthFile      string `json:"token_auth_file" value:"" usage:"csv file of static tokens, each line is token,user,uid,groups and multiple groups are quoted" mapstructure:"token_auth_file"`
+	KubeConfigServer   string `json:"kubeconfig_server" value:"" usage:"address of bcs-api written in issued kubeconfig, such as https://bcs-api.example.com:8443, address of request is used if empty" mapstructure:"kubeconfig_server"`
+	KubeConfigCAFile   string `json:"kubeconfig_ca_file" value:"" usage:"CA file of bcs-api written in issued kubeconfig, kubectl uses system CAs if empty" mapstructure:"kubeconfig_ca_file"`
+}
+
+type AuditOption struct {
+	Enable    bool   `json:"audit_enable" value:"false" usage:"record audit log for every mutating request" mapstructure:"audit_enable"`
+	Sink      string `json:"audit_sink" value:"sql" usage:"sink of audit records, available: sql, file, storage" mapstructure:"audit_sink"`
+	File      string `json:"audit_file" value:"./logs/bcs-api-audit.log" usage:"file of audit records when audit_sink is file" mapstructure:"audit_file"`
+	QueueSize int    `json:"audit_queue_size" value:"1024" usage:"size of queue buffering audit records to sink" mapstructure:"audit_queue_size"`
+	// EnqueueTimeout is how long a request waits for the queue when it is full, the record is dropped after it
+	EnqueueTimeout int      `json:"audit_enqueue_timeout" value:"1000" usage:"milliseconds waiting for the full audit queue before the record is dropped" mapstructure:"audit_enqueue_timeout"`
+	TrustedProxies []string `json:"audit_trusted_proxies" value:"" usage:"ips or cidrs of proxies in front of bcs-api, X-Forwarded-For is only used as source ip of audit records if requests come from them" mapstructure:"audit_trusted_proxies"`
+}
+
+type RateLimitOption struct {
+	Enable       bool           `json:"ratelimit_enable" value:"false" usage:"limit requests forwarded to clusters by user, token and cluster" mapstructure:"ratelimit_enable"`
+	SyncInterval int            `json:"ratelimit_sync_interval" value:"30" usage:"interval in seconds to load rate limit rules changed at runtime from database" mapstructure:"ratelimit_sync_interval"`
+	Rules        RateLimitRules `json:"ratelimit_rules"`
+}
+
+// RateLimitRules are the initial rate limit rules, they are replaced by the ones changed at runtime in database
+type RateLimitRules struct {
+	User    RateLimitBudget `json:"user"`
+	Token   RateLimitBudget `json:"token"`
+	Cluster RateLimitBudget `json:"cluster"`
+	// UserOverrides and ClusterOverrides replace the budget of given users and clusters, such as larger budget for
+	// users of higher priority
+	UserOverrides    map[string]RateLimitBudget `json:"user_overrides,omitempty"`
+	ClusterOverrides map[string]RateLimitBudget `json:"cluster_overrides,omitempty"`
+	// ExemptUsers are system users which are never limited
+	ExemptUsers []string `json:"exempt_users,omitempty"`
+}
+
+// RateLimitBudget is the budgets of read, mutate and long running requests such as watch and exec
+type RateLimitBudget struct {
+	Read        RateLimit `json:"read"`
+	Mutate      RateLimit `json:"mutate"`
+	LongRunning RateLimit `json:"long_running"`
+}
+
+// RateLimit is a token bucket of QPS and Burst, MaxInflight limits the concurrent requests which is useful for
+// long running ones. Zero value means unlimited.
+type RateLimit struct {
+	QPS         float64 `json:"qps"`
+	Burst       int     `json:"burst"`
+	MaxInflight int     `json:"max_inflight"`
+}
+
 type AuthOption struct {
 	Auth bool `json:"auth" value:"false" usage:"use auth mode or not" mapstructure:"auth"`
 
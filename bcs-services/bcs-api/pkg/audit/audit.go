@@ -0,0 +1,271 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package audit records who created, updated or deleted what through bcs-api.
+//
+// Every non-GET request passing bcs-api, including v4http actions, rest handlers and the
+// k8s reverse proxy, is captured by Middleware as an AuditRecord. Authentication filters
+// fill the user and cluster of the record through SetUser/SetCluster, and the record is
+// written to the configured Sink asynchronously when the request finishes.
+package audit
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-common/common/metric"
+	"bk-bcs/bcs-services/bcs-api/config"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+)
+
+const (
+	SinkSQL     = "sql"
+	SinkFile    = "file"
+	SinkStorage = "storage"
+
+	defaultQueueSize      = 1024
+	defaultEnqueueTimeout = time.Second
+	defaultQueryLimit     = 100
+	maxQueryLimit         = 1000
+)
+
+// Filter is the condition for querying audit records, empty field means no limit
+type Filter struct {
+	User      string
+	ClusterID string
+	Begin     time.Time
+	End       time.Time
+	Offset    int
+	Limit     int
+}
+
+// Match checks if the record matches the filter, offset and limit are not considered
+func (f *Filter) Match(record *m.AuditRecord) bool {
+	if f.User != "" && f.User != record.User {
+		return false
+	}
+	if f.ClusterID != "" && f.ClusterID != record.ClusterID {
+		return false
+	}
+	if !f.Begin.IsZero() && record.CreatedAt.Before(f.Begin) {
+		return false
+	}
+	if !f.End.IsZero() && !record.CreatedAt.Before(f.End) {
+		return false
+	}
+	return true
+}
+
+func (f *Filter) normalize() {
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultQueryLimit
+	}
+	if f.Limit > maxQueryLimit {
+		f.Limit = maxQueryLimit
+	}
+}
+
+// Sink is where the audit records go
+type Sink interface {
+	// Write saves one audit record
+	Write(record *m.AuditRecord) error
+	// Query returns audit records matching filter, newest first
+	Query(filter *Filter) ([]m.AuditRecord, error)
+}
+
+// SinkFactory creates a sink from bcs-api config
+type SinkFactory func(conf *config.ApiServConfig) (Sink, error)
+
+var (
+	sinkFactories = make(map[string]SinkFactory)
+	factoryLock   sync.RWMutex
+)
+
+// RegisterSink registers a sink factory by name, it is called in init of sink implementation
+func RegisterSink(name string, factory SinkFactory) {
+	factoryLock.Lock()
+	defer factoryLock.Unlock()
+	sinkFactories[name] = factory
+}
+
+func newSink(name string, conf *config.ApiServConfig) (Sink, error) {
+	factoryLock.RLock()
+	factory, ok := sinkFactories[name]
+	factoryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("audit sink %s is not supported", name)
+	}
+	return factory(conf)
+}
+
+// recorder writes audit records to sink in background, so that slow sink will not block requests
+type recorder struct {
+	sink  Sink
+	queue chan *m.AuditRecord
+	// how long Record waits when the queue is full
+	timeout time.Duration
+	// proxies whose X-Forwarded-For is trusted as source ip
+	trustedProxies []*net.IPNet
+	// number of records dropped as the queue is full, accessed atomically
+	dropped uint64
+}
+
+var globalRecorder *recorder
+
+// Init initializes the global audit recorder, audit is disabled if not called
+func Init(conf *config.ApiServConfig) error {
+	if !conf.Audit.Enable {
+		blog.Infof("audit is disabled")
+		return nil
+	}
+
+	sink, err := newSink(conf.Audit.Sink, conf)
+	if err != nil {
+		return err
+	}
+
+	size := conf.Audit.QueueSize
+	if size <= 0 {
+		size = defaultQueueSize
+	}
+	timeout := time.Duration(conf.Audit.EnqueueTimeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultEnqueueTimeout
+	}
+	proxies, err := parseTrustedProxies(conf.Audit.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	r := &recorder{
+		sink:           sink,
+		queue:          make(chan *m.AuditRecord, size),
+		timeout:        timeout,
+		trustedProxies: proxies,
+	}
+	go r.run()
+	globalRecorder = r
+
+	blog.Infof("audit is enabled with sink %s", conf.Audit.Sink)
+	return nil
+}
+
+// Enabled returns whether audit is enabled
+func Enabled() bool {
+	return globalRecorder != nil
+}
+
+// Record puts record into queue of recorder. If the queue is full, it waits for the enqueue timeout, then the
+// record is dropped and counted in metric audit_dropped_records_total
+func Record(record *m.AuditRecord) {
+	if globalRecorder == nil {
+		return
+	}
+	globalRecorder.record(record)
+}
+
+func (r *recorder) record(record *m.AuditRecord) {
+	select {
+	case r.queue <- record:
+		return
+	default:
+	}
+
+	timer := time.NewTimer(r.timeout)
+	defer timer.Stop()
+	select {
+	case r.queue <- record:
+	case <-timer.C:
+		dropped := atomic.AddUint64(&r.dropped, 1)
+		blog.Errorf("audit queue is full for %s, drop record: user %s verb %s resource %s, %d records dropped",
+			r.timeout, record.User, record.Verb, record.Resource, dropped)
+	}
+}
+
+// Metrics returns the metrics of audit records dropped as the queue is full
+func Metrics() []*metric.MetricContructor {
+	return []*metric.MetricContructor{{
+		GetMeta: func() *metric.MetricMeta {
+			return &metric.MetricMeta{
+				Name: "audit_dropped_records_total",
+				Help: "number of audit records dropped as the queue is full",
+			}
+		},
+		GetResult: func() (*metric.MetricResult, error) {
+			var dropped uint64
+			if globalRecorder != nil {
+				dropped = atomic.LoadUint64(&globalRecorder.dropped)
+			}
+			value, err := metric.FormFloatOrString(float64(dropped))
+			if err != nil {
+				return nil, err
+			}
+			return &metric.MetricResult{Value: value}, nil
+		},
+	}}
+}
+
+// Query queries audit records from sink
+func Query(filter *Filter) ([]m.AuditRecord, error) {
+	if globalRecorder == nil {
+		return nil, fmt.Errorf("audit is not enabled")
+	}
+
+	filter.normalize()
+	return globalRecorder.sink.Query(filter)
+}
+
+func (r *recorder) run() {
+	for record := range r.queue {
+		if err := r.sink.Write(record); err != nil {
+			blog.Errorf("write audit record failed: %v, user %s verb %s resource %s",
+				err, record.User, record.Verb, record.Resource)
+		}
+	}
+}
+
+// parseTrustedProxies parses ips or cidrs of trusted proxies
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid audit trusted proxy %s", proxy)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit trusted proxy %s: %v", proxy, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
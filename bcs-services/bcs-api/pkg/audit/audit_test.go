@@ -0,0 +1,174 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package audit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+)
+
+func TestMiddleware(t *testing.T) {
+	proxies, err := parseTrustedProxies([]string{"192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalRecorder = &recorder{queue: make(chan *m.AuditRecord, 10), trustedProxies: proxies}
+	defer func() { globalRecorder = nil }()
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ioutil.ReadAll(req.Body)
+		SetUser(req.Context(), "admin", "session")
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest("GET", "/bcsapi/v4/scheduler/mesos/namespaces/ns/applications", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if len(globalRecorder.queue) != 0 {
+		t.Fatalf("GET request should not be audited")
+	}
+
+	req = httptest.NewRequest("POST", "/bcsapi/v4/scheduler/mesos/namespaces/ns/applications", strings.NewReader("{}"))
+	req.Header.Set(ClusterIDHeaderKey, "BCS-MESOS-10001")
+	req.Header.Set("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if len(globalRecorder.queue) != 1 {
+		t.Fatalf("POST request should be audited")
+	}
+
+	record := <-globalRecorder.queue
+	expect := m.AuditRecord{
+		User:         "admin",
+		TokenType:    "session",
+		SourceIP:     "10.0.0.2",
+		ClusterID:    "BCS-MESOS-10001",
+		Verb:         "POST",
+		Resource:     "/bcsapi/v4/scheduler/mesos/namespaces/ns/applications",
+		BodyDigest:   "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+		ResponseCode: http.StatusCreated,
+	}
+	record.LatencyMs = 0
+	record.CreatedAt = time.Time{}
+	if *record != expect {
+		t.Errorf("expect record %+v, got %+v", expect, *record)
+	}
+}
+
+func TestSourceIP(t *testing.T) {
+	proxies, err := parseTrustedProxies([]string{"10.0.0.1", "172.16.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		remoteAddr string
+		forwarded  string
+		expect     string
+	}{
+		{"10.0.0.9:3000", "1.1.1.1", "10.0.0.9"},
+		{"10.0.0.1:3000", "", "10.0.0.1"},
+		{"10.0.0.1:3000", "1.1.1.1, 2.2.2.2", "2.2.2.2"},
+		{"10.0.0.1:3000", "1.1.1.1, 2.2.2.2, 172.16.1.1", "2.2.2.2"},
+		{"10.0.0.1:3000", "172.16.1.2, 172.16.1.1", "172.16.1.2"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("POST", "/rest/tokens", nil)
+		req.RemoteAddr = test.remoteAddr
+		if test.forwarded != "" {
+			req.Header.Set("X-Forwarded-For", test.forwarded)
+		}
+		if got := sourceIP(req, proxies); got != test.expect {
+			t.Errorf("%s %q: expect source ip %s, got %s", test.remoteAddr, test.forwarded, test.expect, got)
+		}
+	}
+
+	if _, err = parseTrustedProxies([]string{"bcs-proxy"}); err == nil {
+		t.Errorf("expect invalid trusted proxy error")
+	}
+}
+
+func TestRecordQueueFull(t *testing.T) {
+	r := &recorder{queue: make(chan *m.AuditRecord, 1), timeout: 10 * time.Millisecond}
+	r.record(&m.AuditRecord{User: "a"})
+	r.record(&m.AuditRecord{User: "b"})
+	if r.dropped != 1 {
+		t.Errorf("expect 1 record dropped, got %d", r.dropped)
+	}
+
+	// the record waits for the queue to be consumed within timeout
+	r.timeout = time.Second
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-r.queue
+	}()
+	r.record(&m.AuditRecord{User: "c"})
+	if r.dropped != 1 {
+		t.Errorf("expect record queued after waiting, got %d dropped", r.dropped)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sink, err := openFileSink(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	records := []m.AuditRecord{
+		{User: "u1", ClusterID: "c1", Verb: "POST", CreatedAt: now.Add(-3 * time.Hour)},
+		{User: "u2", ClusterID: "c1", Verb: "PUT", CreatedAt: now.Add(-2 * time.Hour)},
+		{User: "u1", ClusterID: "c2", Verb: "DELETE", CreatedAt: now.Add(-1 * time.Hour)},
+		{User: "u1", ClusterID: "c1", Verb: "PUT", CreatedAt: now},
+	}
+	for i := range records {
+		if err := sink.Write(&records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		filter Filter
+		verbs  []string
+	}{
+		{Filter{Limit: 10}, []string{"PUT", "DELETE", "PUT", "POST"}},
+		{Filter{User: "u1", Limit: 10}, []string{"PUT", "DELETE", "POST"}},
+		{Filter{User: "u1", ClusterID: "c1", Limit: 10}, []string{"PUT", "POST"}},
+		{Filter{Begin: now.Add(-2 * time.Hour), End: now, Limit: 10}, []string{"DELETE", "PUT"}},
+		{Filter{Offset: 1, Limit: 2}, []string{"DELETE", "PUT"}},
+	}
+	for i, test := range tests {
+		result, err := sink.Query(&test.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verbs := make([]string, 0, len(result))
+		for _, r := range result {
+			verbs = append(verbs, r.Verb)
+		}
+		if strings.Join(verbs, ",") != strings.Join(test.verbs, ",") {
+			t.Errorf("case %d: expect %v, got %v", i, test.verbs, verbs)
+		}
+	}
+}
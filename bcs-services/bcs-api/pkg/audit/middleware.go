@@ -0,0 +1,246 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"bk-bcs/bcs-common/common/tracing"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// ClusterIDHeaderKey header of cluster id for v4http actions
+	ClusterIDHeaderKey = "BCS-ClusterID"
+	// ClusterVarName path parameter of cluster in k8s reverse proxy
+	ClusterVarName = "cluster_identifier"
+)
+
+type recordKey struct{}
+
+// entry is the audit record of request being processed, filled by filters along the chain
+type entry struct {
+	sync.Mutex
+	record *m.AuditRecord
+}
+
+func entryFromContext(ctx context.Context) *entry {
+	if ctx == nil {
+		return nil
+	}
+	e, _ := ctx.Value(recordKey{}).(*entry)
+	return e
+}
+
+// SetUser sets the user and token type of request being audited, it is called by authenticators
+func SetUser(ctx context.Context, user, tokenType string) {
+	e := entryFromContext(ctx)
+	if e == nil {
+		return
+	}
+	e.Lock()
+	e.record.User = user
+	e.record.TokenType = tokenType
+	e.Unlock()
+}
+
+// SetCluster sets the cluster of request being audited when it is not carried by header or path
+func SetCluster(ctx context.Context, clusterID string) {
+	e := entryFromContext(ctx)
+	if e == nil || clusterID == "" {
+		return
+	}
+	e.Lock()
+	e.record.ClusterID = clusterID
+	e.Unlock()
+}
+
+// IsMutating returns whether the method may change resources, only these requests are audited
+func IsMutating(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// Middleware records every mutating request passing bcs-api
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !Enabled() || !IsMutating(req.Method) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		start := time.Now()
+		e := &entry{
+			record: &m.AuditRecord{
+				RequestID: tracing.RequestIDFromContext(req.Context()),
+				SourceIP:  sourceIP(req, globalRecorder.trustedProxies),
+				ClusterID: req.Header.Get(ClusterIDHeaderKey),
+				Verb:      req.Method,
+				// path may be stripped by reverse proxy, so it is saved before serving
+				Resource: req.URL.Path,
+			},
+		}
+		if e.record.ClusterID == "" {
+			e.record.ClusterID = mux.Vars(req)[ClusterVarName]
+		}
+
+		var body *digestReader
+		if req.Body != nil {
+			body = &digestReader{ReadCloser: req.Body, hash: sha256.New()}
+			req.Body = body
+		}
+
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, req.WithContext(context.WithValue(req.Context(), recordKey{}, e)))
+
+		e.Lock()
+		record := e.record
+		e.Unlock()
+		if body != nil {
+			record.BodyDigest = body.digest()
+		}
+		record.ResponseCode = sw.statusCode()
+		record.LatencyMs = int64(time.Since(start) / time.Millisecond)
+		record.CreatedAt = start
+		Record(record)
+	})
+}
+
+// sourceIP returns the remote address of request. X-Forwarded-For can be forged by clients, so it is only used
+// when the request comes from trusted proxies, and the source is the last address not added by trusted proxies.
+func sourceIP(req *http.Request, trustedProxies []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	if !isTrustedProxy(host, trustedProxies) {
+		return host
+	}
+
+	forwarded := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(forwarded[i])
+		if ip == "" {
+			continue
+		}
+		host = ip
+		if !isTrustedProxy(ip, trustedProxies) {
+			break
+		}
+	}
+	return host
+}
+
+func isTrustedProxy(host string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// digestReader computes sha256 of request body while it is read by handler,
+// so that body needs not to be buffered
+type digestReader struct {
+	io.ReadCloser
+	hash hash.Hash
+	size int64
+}
+
+func (r *digestReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.hash.Write(p[:n])
+		r.size += int64(n)
+	}
+	return n, err
+}
+
+func (r *digestReader) digest() string {
+	if r.size == 0 {
+		return ""
+	}
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *statusWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijack")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+func (w *statusWriter) CloseNotify() <-chan bool {
+	if n, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return n.CloseNotify()
+	}
+	return make(chan bool)
+}
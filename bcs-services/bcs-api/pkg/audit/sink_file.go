@@ -0,0 +1,100 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"bk-bcs/bcs-services/bcs-api/config"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+)
+
+func init() {
+	RegisterSink(SinkFile, newFileSink)
+}
+
+// fileSink appends audit records to local file, one json record per line
+type fileSink struct {
+	sync.Mutex
+	path string
+	file *os.File
+}
+
+func newFileSink(conf *config.ApiServConfig) (Sink, error) {
+	return openFileSink(conf.Audit.File)
+}
+
+func openFileSink(path string) (*fileSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("audit file is not set")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{path: path, file: f}, nil
+}
+
+func (s *fileSink) Write(record *m.AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	s.Lock()
+	defer s.Unlock()
+	_, err = s.file.Write(data)
+	return err
+}
+
+// Query scans the whole file, it is fine for the file is expected to be rotated by logrotate
+func (s *fileSink) Query(filter *Filter) ([]m.AuditRecord, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	matched := make([]m.AuditRecord, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		record := m.AuditRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if filter.Match(&record) {
+			matched = append(matched, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// records are appended in time order, return the newest first
+	records := make([]m.AuditRecord, 0, filter.Limit)
+	for i := len(matched) - 1 - filter.Offset; i >= 0 && len(records) < filter.Limit; i-- {
+		records = append(records, matched[i])
+	}
+	return records, nil
+}
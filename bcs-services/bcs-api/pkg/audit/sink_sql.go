@@ -0,0 +1,39 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package audit
+
+import (
+	"bk-bcs/bcs-services/bcs-api/config"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
+)
+
+func init() {
+	RegisterSink(SinkSQL, newSQLSink)
+}
+
+// sqlSink saves audit records into table of core database
+type sqlSink struct{}
+
+func newSQLSink(conf *config.ApiServConfig) (Sink, error) {
+	return &sqlSink{}, nil
+}
+
+func (s *sqlSink) Write(record *m.AuditRecord) error {
+	return sqlstore.CreateAuditRecord(record)
+}
+
+func (s *sqlSink) Query(filter *Filter) ([]m.AuditRecord, error) {
+	return sqlstore.QueryAuditRecords(filter.User, filter.ClusterID, filter.Begin, filter.End, filter.Offset, filter.Limit)
+}
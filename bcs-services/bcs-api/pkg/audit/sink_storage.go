@@ -0,0 +1,150 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"bk-bcs/bcs-common/common/http/httpclient"
+	"bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-services/bcs-api/config"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/regdiscv"
+)
+
+func init() {
+	RegisterSink(SinkStorage, newStorageSink)
+}
+
+// storageSink saves audit records as events of bcs-storage, user is saved in extraInfo.name
+// so that records can be filtered by user with the event query api
+type storageSink struct{}
+
+type storageEventsResp struct {
+	Result  bool                      `json:"result"`
+	Code    int                       `json:"code"`
+	Message string                    `json:"message"`
+	Data    []types.BcsStorageEventIf `json:"data"`
+}
+
+func newStorageSink(conf *config.ApiServConfig) (Sink, error) {
+	return &storageSink{}, nil
+}
+
+func (s *storageSink) Write(record *m.AuditRecord) error {
+	event := &types.BcsStorageEventIf{
+		Env:       types.Event_Env_K8s,
+		Kind:      types.AuditEventKind,
+		Level:     types.Event_Level_Normal,
+		Component: types.Event_Component_Api,
+		Type:      record.Verb,
+		Describe:  fmt.Sprintf("%s %s %s", record.User, record.Verb, record.Resource),
+		ClusterId: record.ClusterID,
+		EventTime: record.CreatedAt.Unix(),
+		ExtraInfo: types.EventExtraInfo{
+			Name: record.User,
+			Kind: types.UserExtraKind,
+		},
+		Data: record,
+	}
+	// only mesos cluster is accessed through the mesos scheduler api
+	if strings.Contains(record.Resource, "/scheduler/mesos/") {
+		event.Env = types.Event_Env_Mesos
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = s.request("PUT", "events", data)
+	return err
+}
+
+func (s *storageSink) Query(filter *Filter) ([]m.AuditRecord, error) {
+	params := url.Values{}
+	params.Set("kind", string(types.AuditEventKind))
+	params.Set("component", string(types.Event_Component_Api))
+	if filter.User != "" {
+		params.Set("extraInfo.name", filter.User)
+	}
+	if filter.ClusterID != "" {
+		params.Set("clusterId", filter.ClusterID)
+	}
+	if !filter.Begin.IsZero() {
+		// bcs-storage compares time with greater than
+		params.Set("timeBegin", strconv.FormatInt(filter.Begin.Unix()-1, 10))
+	}
+	if !filter.End.IsZero() {
+		params.Set("timeEnd", strconv.FormatInt(filter.End.Unix(), 10))
+	}
+	params.Set("offset", strconv.Itoa(filter.Offset))
+	params.Set("length", strconv.Itoa(filter.Limit))
+
+	reply, err := s.request("GET", "events?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &storageEventsResp{}
+	if err = json.Unmarshal(reply, resp); err != nil {
+		return nil, fmt.Errorf("decode storage reply failed: %v", err)
+	}
+	if !resp.Result {
+		return nil, fmt.Errorf("query storage failed: %s", resp.Message)
+	}
+
+	records := make([]m.AuditRecord, 0, len(resp.Data))
+	for _, event := range resp.Data {
+		raw, _ := json.Marshal(event.Data)
+		record := m.AuditRecord{}
+		if err := json.Unmarshal(raw, &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (s *storageSink) request(method, uri string, data []byte) ([]byte, error) {
+	rd, err := regdiscv.GetRDiscover()
+	if err != nil {
+		return nil, err
+	}
+	serv, err := rd.GetModuleServers(types.BCS_MODULE_STORAGE)
+	if err != nil {
+		return nil, err
+	}
+	ser, ok := serv.(*types.BcsStorageInfo)
+	if !ok {
+		return nil, fmt.Errorf("servers convert to BcsStorageInfo failed")
+	}
+
+	httpcli := httpclient.NewHttpClient()
+	httpcli.SetHeader("Content-Type", "application/json")
+	httpcli.SetHeader("Accept", "application/json")
+	if strings.ToLower(ser.Scheme) == "https" {
+		cliTls, err := rd.GetClientTls()
+		if err != nil {
+			return nil, err
+		}
+		httpcli.SetTlsVerityConfig(cliTls)
+	}
+
+	addr := fmt.Sprintf("%s://%s:%d/bcsstorage/v1/%s", ser.Scheme, ser.IP, ser.Port, uri)
+	return httpcli.Request(addr, method, nil, data)
+}
@@ -85,7 +85,8 @@ func (ta *TokenAuthenticater) GetUserFromToken(s string) (*m.User, bool) {
 		return nil, false
 	}
 
-	if token.HasExpired() {
+	// revoked token is treated as expired
+	if token.HasExpired() || token.IsRevoked() {
 		return sqlstore.GetUser(token.UserId), true
 	}
 
@@ -114,3 +115,47 @@ func (ta *TokenAuthenticater) GetUserTokenType() uint {
 	return token.Type
 
 }
+
+// Permits checks the scopes of token in request for the namespace of cluster, see IsMutatingRequest for the
+// requests limited by read only tokens. Requests without a valid user token are not limited by scopes.
+func (ta *TokenAuthenticater) Permits(clusterID, namespace string) bool {
+	token := sqlstore.GetUserToken(ta.ParseTokenString())
+	if token == nil {
+		return true
+	}
+	return token.Permits(clusterID, namespace, IsMutatingRequest(ta.req))
+}
+
+// PermitsCluster checks the scopes of token in request for cluster only, such as discovery requests of kube-apiserver
+func (ta *TokenAuthenticater) PermitsCluster(clusterID string) bool {
+	token := sqlstore.GetUserToken(ta.ParseTokenString())
+	if token == nil {
+		return true
+	}
+	return token.PermitsCluster(clusterID)
+}
+
+// mutatingPodSubresources are subresources of pods requested by GET, but they run commands in or
+// connect to containers, so they can change anything in the containers
+var mutatingPodSubresources = map[string]bool{
+	"exec":        true,
+	"attach":      true,
+	"portforward": true,
+}
+
+// IsMutatingRequest returns true if the request may change resources. Requests with methods other than GET, HEAD
+// and OPTIONS, websocket or spdy upgrade requests and requests to exec, attach or portforward of pods are mutating.
+func IsMutatingRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return true
+	}
+	if req.Header.Get("Upgrade") != "" {
+		return true
+	}
+
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	n := len(parts)
+	return n >= 3 && parts[n-3] == "pods" && mutatingPodSubresources[parts[n-1]]
+}
@@ -0,0 +1,50 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsMutatingRequest(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		upgrade  string
+		mutating bool
+	}{
+		{http.MethodGet, "/tunnels/clusters/BCS-K8S-001/api/v1/namespaces/ns/pods", "", false},
+		{http.MethodHead, "/tunnels/clusters/BCS-K8S-001/api/v1/namespaces/ns/pods/web-0", "", false},
+		{http.MethodGet, "/tunnels/clusters/BCS-K8S-001/api/v1/namespaces/ns/pods/web-0/log", "", false},
+		{http.MethodPost, "/tunnels/clusters/BCS-K8S-001/api/v1/namespaces/ns/pods", "", true},
+		{http.MethodDelete, "/tunnels/clusters/BCS-K8S-001/api/v1/namespaces/ns/pods/web-0", "", true},
+		{http.MethodGet, "/tunnels/clusters/BCS-K8S-001/api/v1/namespaces/ns/pods/web-0/exec", "", true},
+		{http.MethodGet, "/tunnels/clusters/BCS-K8S-001/api/v1/namespaces/ns/pods/web-0/attach", "", true},
+		{http.MethodGet, "/tunnels/clusters/BCS-K8S-001/api/v1/namespaces/ns/pods/web-0/portforward/", "", true},
+		{http.MethodGet, "/tunnels/clusters/BCS-K8S-001/api/v1/namespaces/ns/pods", "websocket", true},
+		{http.MethodGet, "/tunnels/clusters/BCS-K8S-001/api/v1/namespaces/ns/services/exec", "", false},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		if test.upgrade != "" {
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", test.upgrade)
+		}
+		if mutating := IsMutatingRequest(req); mutating != test.mutating {
+			t.Errorf("%s %s upgrade(%s): expect mutating %t, got %t", test.method, test.path, test.upgrade, test.mutating, mutating)
+		}
+	}
+}
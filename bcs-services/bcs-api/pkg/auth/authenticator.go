@@ -0,0 +1,116 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-services/bcs-api/config"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
+)
+
+// Authenticator authenticates the user of request by external identity providers
+type Authenticator interface {
+	// Name returns the backend type of authenticator, it is set as BackendType of user
+	Name() string
+	// AuthenticateRequest returns the internal user of request. ok is false if the request does not carry
+	// credentials of this authenticator, err is not nil if the credentials are recognized but invalid
+	AuthenticateRequest(req *http.Request) (user *m.User, ok bool, err error)
+}
+
+// UnionAuthenticator tries authenticators in order, the first one recognizing the request wins
+type UnionAuthenticator []Authenticator
+
+// AuthenticateRequest implements Authenticator
+func (u UnionAuthenticator) AuthenticateRequest(req *http.Request) (*m.User, bool, error) {
+	for _, authenticator := range u {
+		user, ok, err := authenticator.AuthenticateRequest(req)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %v", authenticator.Name(), err)
+		}
+		if ok {
+			user.BackendType = authenticator.Name()
+			return user, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// GExternalAuthenticator is the chain of external authenticators configured, it is empty if none configured
+var GExternalAuthenticator UnionAuthenticator
+
+// getOrCreateUser maps external user to internal user through ExternalUserRecord
+var getOrCreateUser = sqlstore.GetOrCreateUser
+
+// internalUser returns the internal user of external user with groups, error if the user can not be found or created
+func internalUser(sourceType uint, name, userType string, groups []string) (*m.User, error) {
+	user, err := getOrCreateUser(sourceType, name, userType)
+	if err != nil {
+		return nil, err
+	}
+	// record of external user exists while the internal user has been deleted
+	if user == nil {
+		return nil, fmt.Errorf("internal user of %s %s not found", userType, name)
+	}
+	user.Groups = groups
+	return user, nil
+}
+
+// InitAuthenticators initializes GExternalAuthenticator by authentication config
+func InitAuthenticators(conf *config.ApiServConfig) error {
+	authenticators := make(UnionAuthenticator, 0)
+
+	authConf := conf.Authentication
+	if authConf.TokenAuthFile != "" {
+		tokenAuth, err := NewStaticTokenAuthenticator(authConf.TokenAuthFile)
+		if err != nil {
+			return err
+		}
+		authenticators = append(authenticators, tokenAuth)
+		blog.Infof("static token authentication is enabled with file %s", authConf.TokenAuthFile)
+	}
+
+	if authConf.OIDCIssuerURL != "" {
+		oidcAuth, err := NewOIDCAuthenticator(&OIDCConfig{
+			IssuerURL:      authConf.OIDCIssuerURL,
+			ClientID:       authConf.OIDCClientID,
+			CAFile:         authConf.OIDCCAFile,
+			UsernameClaim:  authConf.OIDCUsernameClaim,
+			UsernamePrefix: authConf.OIDCUsernamePrefix,
+			GroupsClaim:    authConf.OIDCGroupsClaim,
+			GroupsPrefix:   authConf.OIDCGroupsPrefix,
+		})
+		if err != nil {
+			return err
+		}
+		authenticators = append(authenticators, oidcAuth)
+		blog.Infof("oidc authentication is enabled with issuer %s", authConf.OIDCIssuerURL)
+	}
+
+	GExternalAuthenticator = authenticators
+	return nil
+}
+
+// bearerToken parses bearer token from authorization header
+func bearerToken(req *http.Request) string {
+	authHeader := strings.Split(req.Header.Get("Authorization"), " ")
+	if len(authHeader) == 2 && authHeader[0] == "Bearer" {
+		return strings.TrimSpace(authHeader[1])
+	}
+	return ""
+}
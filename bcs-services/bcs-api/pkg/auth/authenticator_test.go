@@ -0,0 +1,218 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+func init() {
+	getOrCreateUser = func(sourceType uint, userID, userType string) (*m.User, error) {
+		return &m.User{Name: userType + ":" + userID}, nil
+	}
+}
+
+func newBearerRequest(token string) *http.Request {
+	req := httptest.NewRequest("GET", "/rest/clusters", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestStaticTokenAuthenticator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tokens.csv")
+	content := "# static tokens\ntoken1,ci-bot,1001,\"ci,deployer\"\ntoken2,admin\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewStaticTokenAuthenticator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		token  string
+		ok     bool
+		name   string
+		groups []string
+	}{
+		{"token1", true, "token:ci-bot", []string{"ci", "deployer"}},
+		{"token2", true, "token:admin", nil},
+		{"token3", false, "", nil},
+		{"", false, "", nil},
+	}
+	for _, test := range tests {
+		user, ok, err := a.AuthenticateRequest(newBearerRequest(test.token))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != test.ok {
+			t.Errorf("token %s: expect ok %v, got %v", test.token, test.ok, ok)
+			continue
+		}
+		if ok && (user.Name != test.name || !reflect.DeepEqual(user.Groups, test.groups)) {
+			t.Errorf("token %s: expect user %s groups %v, got %s %v", test.token, test.name, test.groups, user.Name, user.Groups)
+		}
+	}
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var issuer string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{Issuer: issuer, JWKSURI: issuer + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jsonWebKeySet{Keys: []jsonWebKey{{
+			Kid: "k1",
+			Kty: "RSA",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+	issuer = server.URL
+
+	if _, err = NewOIDCAuthenticator(&OIDCConfig{IssuerURL: "http://" + server.Listener.Addr().String(), ClientID: "bcs"}); err == nil {
+		t.Errorf("expect issuer not using https rejected")
+	}
+
+	a, err := NewOIDCAuthenticator(&OIDCConfig{
+		IssuerURL:      issuer,
+		ClientID:       "bcs",
+		UsernameClaim:  "email",
+		UsernamePrefix: "sso-",
+		GroupsClaim:    "groups",
+		GroupsPrefix:   "sso:",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.client = server.Client()
+
+	sign := func(claims jwt.MapClaims, kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = kid
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name    string
+		token   string
+		ok      bool
+		wantErr bool
+		user    string
+		groups  []string
+	}{
+		{"valid", sign(jwt.MapClaims{"iss": issuer, "aud": "bcs", "exp": exp, "email": "alice@example.com",
+			"groups": []string{"dev", "ops"}}, "k1"), true, false, "oidc:sso-alice@example.com", []string{"sso:dev", "sso:ops"}},
+		{"audience array", sign(jwt.MapClaims{"iss": issuer, "aud": []string{"other", "bcs"}, "exp": exp,
+			"email": "bob@example.com", "groups": "dev"}, "k1"), true, false, "oidc:sso-bob@example.com", []string{"sso:dev"}},
+		{"other issuer", sign(jwt.MapClaims{"iss": "https://other", "aud": "bcs", "exp": exp, "email": "a@b"}, "k1"), false, false, "", nil},
+		{"not jwt", "plain-token", false, false, "", nil},
+		{"wrong audience", sign(jwt.MapClaims{"iss": issuer, "aud": "other", "exp": exp, "email": "a@b"}, "k1"), false, true, "", nil},
+		{"expired", sign(jwt.MapClaims{"iss": issuer, "aud": "bcs", "exp": time.Now().Add(-time.Hour).Unix(), "email": "a@b"}, "k1"), false, true, "", nil},
+		{"no exp", sign(jwt.MapClaims{"iss": issuer, "aud": "bcs", "email": "a@b"}, "k1"), false, true, "", nil},
+		{"unverified email", sign(jwt.MapClaims{"iss": issuer, "aud": "bcs", "exp": exp, "email": "a@b", "email_verified": false}, "k1"), false, true, "", nil},
+		{"unknown key", sign(jwt.MapClaims{"iss": issuer, "aud": "bcs", "exp": exp, "email": "a@b"}, "k2"), false, true, "", nil},
+	}
+	for _, test := range tests {
+		user, ok, err := a.AuthenticateRequest(newBearerRequest(test.token))
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: expect error %v, got %v", test.name, test.wantErr, err)
+			continue
+		}
+		if ok != test.ok {
+			t.Errorf("%s: expect ok %v, got %v", test.name, test.ok, ok)
+			continue
+		}
+		if ok && (user.Name != test.user || !reflect.DeepEqual(user.Groups, test.groups)) {
+			t.Errorf("%s: expect user %s groups %v, got %s %v", test.name, test.user, test.groups, user.Name, user.Groups)
+		}
+	}
+}
+
+func TestUnionAuthenticator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tokens.csv")
+	if err := ioutil.WriteFile(path, []byte("token1,ci-bot\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tokenAuth, err := NewStaticTokenAuthenticator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	union := UnionAuthenticator{tokenAuth}
+	user, ok, err := union.AuthenticateRequest(newBearerRequest("token1"))
+	if err != nil || !ok {
+		t.Fatalf("expect token1 authenticated, got ok %v err %v", ok, err)
+	}
+	if user.BackendType != tokenAuth.Name() {
+		t.Errorf("expect backend type %s, got %s", tokenAuth.Name(), user.BackendType)
+	}
+	if _, ok, _ := union.AuthenticateRequest(newBearerRequest("token2")); ok {
+		t.Errorf("expect token2 not authenticated")
+	}
+}
+
+func TestInternalUserNotFound(t *testing.T) {
+	origin := getOrCreateUser
+	defer func() { getOrCreateUser = origin }()
+	getOrCreateUser = func(sourceType uint, userID, userType string) (*m.User, error) {
+		return nil, nil
+	}
+
+	if _, err := internalUser(m.ExternalUserSourceTypeOIDC, "alice", oidcUserType, nil); err == nil {
+		t.Errorf("expect error when internal user is not found")
+	}
+}
@@ -0,0 +1,369 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"bk-bcs/bcs-common/common/blog"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/server/types"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	oidcUserType = "oidc"
+	// keys of provider are synced at most once in this interval when unknown key id is met
+	oidcKeySyncInterval = time.Minute
+	oidcRequestTimeout  = 10 * time.Second
+)
+
+// OIDCConfig is the config of OpenID Connect authenticator
+type OIDCConfig struct {
+	// IssuerURL must be https, and equals to iss claim of id token
+	IssuerURL string
+	// ClientID must be in aud claim of id token
+	ClientID string
+	CAFile   string
+	// UsernameClaim is the claim used as user name, such as sub, email or name
+	UsernameClaim  string
+	UsernamePrefix string
+	// GroupsClaim is the claim used as user groups, it can be string or array of string
+	GroupsClaim  string
+	GroupsPrefix string
+}
+
+// OIDCAuthenticator authenticates bearer id token issued by OpenID Connect provider, the signature is
+// verified by keys from jwks_uri of provider
+type OIDCAuthenticator struct {
+	config *OIDCConfig
+	client *http.Client
+
+	// syncLock serializes syncing keys from provider, keyLock only guards swapping keys, so that
+	// verifying tokens with known keys is not blocked by requests to provider
+	syncLock sync.Mutex
+	lastSync time.Time
+	keyLock  sync.RWMutex
+	keys     map[string]interface{}
+}
+
+// NewOIDCAuthenticator creates an OIDCAuthenticator, keys are loaded lazily on first request
+func NewOIDCAuthenticator(config *OIDCConfig) (*OIDCAuthenticator, error) {
+	if config.ClientID == "" {
+		return nil, fmt.Errorf("oidc client id is required")
+	}
+	if config.UsernameClaim == "" {
+		config.UsernameClaim = "sub"
+	}
+	config.IssuerURL = strings.TrimSuffix(config.IssuerURL, "/")
+	if err := requireHTTPS(config.IssuerURL); err != nil {
+		return nil, fmt.Errorf("invalid oidc issuer: %v", err)
+	}
+
+	tlsConf := &tls.Config{}
+	if config.CAFile != "" {
+		ca, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read oidc ca file failed: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificate found in oidc ca file %s", config.CAFile)
+		}
+		tlsConf.RootCAs = pool
+	}
+
+	return &OIDCAuthenticator{
+		config: config,
+		client: &http.Client{
+			Timeout:   oidcRequestTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConf, Proxy: http.ProxyFromEnvironment},
+		},
+		keys: make(map[string]interface{}),
+	}, nil
+}
+
+// Name implements Authenticator
+func (a *OIDCAuthenticator) Name() string {
+	return types.UserBackendTypeOIDC
+}
+
+// AuthenticateRequest implements Authenticator
+func (a *OIDCAuthenticator) AuthenticateRequest(req *http.Request) (*m.User, bool, error) {
+	token := bearerToken(req)
+	if token == "" {
+		return nil, false, nil
+	}
+
+	// token not issued by the provider may belong to other authenticators
+	unverified := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, unverified); err != nil {
+		return nil, false, nil
+	}
+	if iss, _ := unverified["iss"].(string); strings.TrimSuffix(iss, "/") != a.config.IssuerURL {
+		return nil, false, nil
+	}
+
+	name, groups, err := a.verify(token)
+	if err != nil {
+		return nil, false, err
+	}
+
+	user, err := internalUser(m.ExternalUserSourceTypeOIDC, name, oidcUserType, groups)
+	if err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// verify validates signature and claims of id token, and returns user name and groups in it
+func (a *OIDCAuthenticator) verify(token string) (string, []string, error) {
+	parser := &jwt.Parser{
+		ValidMethods: []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"},
+	}
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(token, claims, a.keyFunc); err != nil {
+		return "", nil, fmt.Errorf("invalid id token: %v", err)
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return "", nil, fmt.Errorf("id token has expired")
+	}
+	if !verifyAudience(claims["aud"], a.config.ClientID) {
+		return "", nil, fmt.Errorf("id token is not issued for client %s", a.config.ClientID)
+	}
+
+	name, ok := claims[a.config.UsernameClaim].(string)
+	if !ok || name == "" {
+		return "", nil, fmt.Errorf("claim %s not found in id token", a.config.UsernameClaim)
+	}
+	if a.config.UsernameClaim == "email" {
+		if verified, ok := claims["email_verified"].(bool); ok && !verified {
+			return "", nil, fmt.Errorf("email %s in id token is not verified", name)
+		}
+	}
+
+	var groups []string
+	if a.config.GroupsClaim != "" {
+		for _, group := range stringsOfClaim(claims[a.config.GroupsClaim]) {
+			groups = append(groups, a.config.GroupsPrefix+group)
+		}
+	}
+
+	return a.config.UsernamePrefix + name, groups, nil
+}
+
+func (a *OIDCAuthenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key := a.getKey(kid); key != nil {
+		return key, nil
+	}
+
+	// provider may rotate keys, so sync keys when key id is unknown
+	if err := a.syncKeys(); err != nil {
+		return nil, err
+	}
+	if key := a.getKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("key %s not found in provider", kid)
+}
+
+func (a *OIDCAuthenticator) getKey(kid string) interface{} {
+	a.keyLock.RLock()
+	defer a.keyLock.RUnlock()
+
+	// token without kid is allowed only when provider has one key
+	if kid == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key
+		}
+	}
+	return a.keys[kid]
+}
+
+type oidcDiscovery struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+func (a *OIDCAuthenticator) syncKeys() error {
+	a.syncLock.Lock()
+	defer a.syncLock.Unlock()
+
+	if time.Since(a.lastSync) < oidcKeySyncInterval {
+		return nil
+	}
+	a.lastSync = time.Now()
+
+	discovery := &oidcDiscovery{}
+	if err := a.getJSON(a.config.IssuerURL+"/.well-known/openid-configuration", discovery); err != nil {
+		return fmt.Errorf("discover oidc provider failed: %v", err)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != a.config.IssuerURL {
+		return fmt.Errorf("issuer %s of provider does not match %s", discovery.Issuer, a.config.IssuerURL)
+	}
+
+	if err := requireHTTPS(discovery.JWKSURI); err != nil {
+		return fmt.Errorf("invalid jwks_uri of oidc provider: %v", err)
+	}
+
+	keySet := &jsonWebKeySet{}
+	if err := a.getJSON(discovery.JWKSURI, keySet); err != nil {
+		return fmt.Errorf("get oidc provider keys failed: %v", err)
+	}
+
+	keys := make(map[string]interface{})
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseJSONWebKey(&jwk)
+		if err != nil {
+			blog.Warnf("skip invalid key %s of oidc provider: %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	a.keyLock.Lock()
+	a.keys = keys
+	a.keyLock.Unlock()
+	blog.Infof("sync %d keys from oidc provider %s", len(keys), a.config.IssuerURL)
+	return nil
+}
+
+// requireHTTPS checks the url of provider is https, so that keys can not be replaced in transit
+func requireHTTPS(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%s is not a https url", rawURL)
+	}
+	return nil
+}
+
+func (a *OIDCAuthenticator) getJSON(url string, v interface{}) error {
+	resp, err := a.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get %s failed: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func parseJSONWebKey(jwk *jsonWebKey) (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("curve %s is not supported", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("key type %s is not supported", jwk.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// verifyAudience checks aud claim, which may be a string or an array of string
+func verifyAudience(aud interface{}, clientID string) bool {
+	for _, a := range stringsOfClaim(aud) {
+		if a == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+func stringsOfClaim(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
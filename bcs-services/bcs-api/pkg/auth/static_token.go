@@ -0,0 +1,130 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/server/types"
+)
+
+const staticTokenUserType = "token"
+
+type staticTokenUser struct {
+	name   string
+	groups []string
+}
+
+type staticToken struct {
+	hash [sha256.Size]byte
+	user *staticTokenUser
+}
+
+// StaticTokenAuthenticator authenticates bearer tokens listed in a csv file, it is designed for service
+// accounts such as CI. Each line of file is: token,user,uid,"group1,group2", uid and groups are optional.
+// File is loaded once on start, bcs-api should be restarted after file changes.
+type StaticTokenAuthenticator struct {
+	tokens []staticToken
+}
+
+// NewStaticTokenAuthenticator loads tokens from file
+func NewStaticTokenAuthenticator(path string) (*StaticTokenAuthenticator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tokens := make([]staticToken, 0)
+	seen := make(map[string]bool)
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("read token file %s failed: %v", path, err)
+		}
+		if len(record) < 2 || record[0] == "" || record[1] == "" {
+			return nil, fmt.Errorf("token file %s record %d: token and user are required", path, line)
+		}
+		if seen[record[0]] {
+			return nil, fmt.Errorf("token file %s record %d: duplicated token", path, line)
+		}
+		seen[record[0]] = true
+
+		//uid in the third column is reserved, user name is the identity of external user
+		user := &staticTokenUser{name: record[1]}
+		if len(record) > 3 && record[3] != "" {
+			for _, group := range strings.Split(record[3], ",") {
+				if group = strings.TrimSpace(group); group != "" {
+					user.groups = append(user.groups, group)
+				}
+			}
+		}
+		tokens = append(tokens, staticToken{hash: sha256.Sum256([]byte(record[0])), user: user})
+	}
+
+	return &StaticTokenAuthenticator{tokens: tokens}, nil
+}
+
+// Name implements Authenticator
+func (a *StaticTokenAuthenticator) Name() string {
+	return types.UserBackendTypeToken
+}
+
+// AuthenticateRequest implements Authenticator
+func (a *StaticTokenAuthenticator) AuthenticateRequest(req *http.Request) (*m.User, bool, error) {
+	token := bearerToken(req)
+	if token == "" {
+		return nil, false, nil
+	}
+	tokenUser := a.lookup(token)
+	if tokenUser == nil {
+		return nil, false, nil
+	}
+
+	user, err := internalUser(m.ExternalUserSourceTypeStaticToken, tokenUser.name, staticTokenUserType, tokenUser.groups)
+	if err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// lookup finds the user of token. All tokens are compared in constant time so that the time taken does not
+// reveal how much of a token matches, tokens are hashed first to hide their lengths.
+func (a *StaticTokenAuthenticator) lookup(token string) *staticTokenUser {
+	hash := sha256.Sum256([]byte(token))
+	var found *staticTokenUser
+	for i := range a.tokens {
+		if subtle.ConstantTimeCompare(hash[:], a.tokens[i].hash[:]) == 1 {
+			found = a.tokens[i].user
+		}
+	}
+	return found
+}
@@ -0,0 +1,203 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"bk-bcs/bcs-common/common"
+)
+
+const (
+	// TargetsAttribute is the request attribute of member clusters authorized by auth filter
+	TargetsAttribute = "bcs-federation-targets"
+	// MaxConcurrency is the max number of member clusters requested at the same time
+	MaxConcurrency = 10
+
+	StatusSuccess   = "success"
+	StatusFailed    = "failed"
+	StatusForbidden = "forbidden"
+	StatusSkipped   = "skipped"
+)
+
+// Target is a member cluster of federated request
+type Target struct {
+	ClusterID string
+	// Code and Err are set if the request can not be sent to the cluster, such as no authority
+	Code int
+	Err  error
+}
+
+// Requester sends the request to one member cluster, it returns the http status code and body of the reply
+type Requester func(clusterID string) (int, []byte, error)
+
+// ClusterResult is the result of federated request in one member cluster
+type ClusterResult struct {
+	ClusterID string          `json:"cluster_id"`
+	Status    string          `json:"status"`
+	Code      int             `json:"code"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"-"`
+}
+
+// Succeeded returns true if the request succeeded in the cluster
+func (r *ClusterResult) Succeeded() bool {
+	return r.Status == StatusSuccess
+}
+
+// FanOut sends the request to all the targets and returns the results in the order of targets.
+// Targets are requested concurrently at most MaxConcurrency at a time, unless stopOnFailure is set, in which case
+// they are requested one by one and the rest are skipped after the first failure.
+func FanOut(targets []Target, stopOnFailure bool, requester Requester) []ClusterResult {
+	results := make([]ClusterResult, len(targets))
+	if stopOnFailure {
+		failed := false
+		for i, target := range targets {
+			if failed {
+				results[i] = ClusterResult{
+					ClusterID: target.ClusterID,
+					Status:    StatusSkipped,
+					Message:   "skipped for failure in previous cluster",
+				}
+				continue
+			}
+			results[i] = request(target, requester)
+			failed = !results[i].Succeeded()
+		}
+		return results
+	}
+
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, MaxConcurrency)
+	for i := range targets {
+		wg.Add(1)
+		limit <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+			results[i] = request(targets[i], requester)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func request(target Target, requester Requester) ClusterResult {
+	if target.Err != nil {
+		return ClusterResult{
+			ClusterID: target.ClusterID,
+			Status:    StatusForbidden,
+			Code:      target.Code,
+			Message:   target.Err.Error(),
+		}
+	}
+	statusCode, body, err := requester(target.ClusterID)
+	return ParseReply(target.ClusterID, statusCode, body, err)
+}
+
+// ParseReply parses the reply of member cluster into result.
+// Replies in the form of bcs api response are succeeded if the result field is true, the others if status is 2xx.
+func ParseReply(clusterID string, statusCode int, body []byte, err error) ClusterResult {
+	result := ClusterResult{ClusterID: clusterID, Status: StatusFailed}
+	if err != nil {
+		result.Code = common.BcsErrApiInternalFail
+		result.Message = err.Error()
+		return result
+	}
+
+	reply := struct {
+		Result  *bool           `json:"result"`
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}{}
+	if json.Unmarshal(body, &reply) == nil && reply.Result != nil {
+		result.Code = reply.Code
+		result.Message = reply.Message
+		result.Data = reply.Data
+		if *reply.Result && statusCode < http.StatusMultipleChoices {
+			result.Status = StatusSuccess
+		}
+		return result
+	}
+
+	if json.Valid(body) {
+		result.Data = body
+	} else if len(body) > 0 {
+		result.Data, _ = json.Marshal(string(body))
+	}
+	if statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices {
+		result.Status = StatusSuccess
+		return result
+	}
+	result.Code = common.BcsErrApiInternalFail
+	result.Message = fmt.Sprintf("request failed with status %d", statusCode)
+	return result
+}
+
+// Item is an item of merged results, with the member cluster it comes from
+type Item struct {
+	ClusterID string          `json:"cluster_id"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// MergedResult is the data of federated response
+type MergedResult struct {
+	// Items are data of all succeeded clusters, data in form of array are flattened
+	Items    []Item          `json:"items"`
+	Clusters []ClusterResult `json:"clusters"`
+	// Partial is true if the request succeeded in some member clusters only
+	Partial bool `json:"partial"`
+}
+
+// Merge merges results of member clusters, it returns the merged result with the code and message of the response
+func Merge(results []ClusterResult) (merged *MergedResult, code int, message string) {
+	merged = &MergedResult{
+		Items:    make([]Item, 0),
+		Clusters: results,
+	}
+
+	succeeded := 0
+	for _, result := range results {
+		if !result.Succeeded() {
+			continue
+		}
+		succeeded++
+		if len(result.Data) == 0 || string(result.Data) == "null" {
+			continue
+		}
+		elements := make([]json.RawMessage, 0)
+		if json.Unmarshal(result.Data, &elements) != nil {
+			elements = []json.RawMessage{result.Data}
+		}
+		for _, element := range elements {
+			merged.Items = append(merged.Items, Item{ClusterID: result.ClusterID, Data: element})
+		}
+	}
+
+	switch {
+	case succeeded == len(results):
+		return merged, common.BcsSuccess, common.BcsSuccessStr
+	case succeeded == 0:
+		return merged, common.BcsErrApiFederationFail, common.BcsErrApiFederationFailStr
+	default:
+		merged.Partial = true
+		return merged, common.BcsErrApiFederationPartial, common.BcsErrApiFederationPartialStr
+	}
+}
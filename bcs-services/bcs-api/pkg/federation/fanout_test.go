@@ -0,0 +1,107 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"bk-bcs/bcs-common/common"
+)
+
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		err        error
+		status     string
+		data       string
+	}{
+		{"bcs api succeeded", http.StatusOK, `{"result":true,"code":0,"message":"success","data":[1,2]}`, nil, StatusSuccess, `[1,2]`},
+		{"bcs api failed", http.StatusOK, `{"result":false,"code":4001,"message":"not found","data":null}`, nil, StatusFailed, `null`},
+		{"plain json", http.StatusOK, `{"name":"app"}`, nil, StatusSuccess, `{"name":"app"}`},
+		{"plain text failed", http.StatusBadGateway, `bad gateway`, nil, StatusFailed, `"bad gateway"`},
+		{"request error", 0, ``, fmt.Errorf("connection refused"), StatusFailed, ``},
+	}
+	for _, test := range tests {
+		result := ParseReply("BCS-MESOS-10001", test.statusCode, []byte(test.body), test.err)
+		if result.Status != test.status || string(result.Data) != test.data {
+			t.Errorf("%s: expect status %s data %s, got status %s data %s",
+				test.name, test.status, test.data, result.Status, string(result.Data))
+		}
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	targets := []Target{
+		{ClusterID: "BCS-MESOS-10001"},
+		{ClusterID: "BCS-MESOS-10002", Code: common.BcsErrApiAuthCheckNoAuthority, Err: fmt.Errorf("no authority")},
+		{ClusterID: "BCS-MESOS-10003"},
+		{ClusterID: "BCS-MESOS-10004"},
+	}
+	requester := func(clusterID string) (int, []byte, error) {
+		if clusterID == "BCS-MESOS-10003" {
+			return http.StatusOK, []byte(`{"result":false,"code":1,"message":"failed"}`), nil
+		}
+		return http.StatusOK, []byte(fmt.Sprintf(`{"result":true,"data":[{"cluster":"%s"}]}`, clusterID)), nil
+	}
+
+	status := func(results []ClusterResult) []string {
+		statuses := make([]string, 0, len(results))
+		for _, result := range results {
+			statuses = append(statuses, result.Status)
+		}
+		return statuses
+	}
+	results := FanOut(targets, false, requester)
+	expected := []string{StatusSuccess, StatusForbidden, StatusFailed, StatusSuccess}
+	if !reflect.DeepEqual(status(results), expected) {
+		t.Errorf("expect statuses %v, got %v", expected, status(results))
+	}
+
+	// forbidden cluster stops the fan-out too
+	results = FanOut(targets, true, requester)
+	expected = []string{StatusSuccess, StatusForbidden, StatusSkipped, StatusSkipped}
+	if !reflect.DeepEqual(status(results), expected) {
+		t.Errorf("expect statuses %v when stop on failure, got %v", expected, status(results))
+	}
+}
+
+func TestMerge(t *testing.T) {
+	results := []ClusterResult{
+		{ClusterID: "BCS-MESOS-10001", Status: StatusSuccess, Data: json.RawMessage(`[{"name":"a"},{"name":"b"}]`)},
+		{ClusterID: "BCS-MESOS-10002", Status: StatusSuccess, Data: json.RawMessage(`{"name":"c"}`)},
+		{ClusterID: "BCS-MESOS-10003", Status: StatusSuccess, Data: json.RawMessage(`null`)},
+	}
+	merged, code, _ := Merge(results)
+	if code != common.BcsSuccess || merged.Partial || len(merged.Items) != 3 {
+		t.Fatalf("expect 3 items without failure, got code %d partial %t items %d", code, merged.Partial, len(merged.Items))
+	}
+	if merged.Items[1].ClusterID != "BCS-MESOS-10001" || string(merged.Items[2].Data) != `{"name":"c"}` {
+		t.Errorf("items are not merged in order of clusters: %+v", merged.Items)
+	}
+
+	results[2].Status = StatusFailed
+	if merged, code, _ = Merge(results); code != common.BcsErrApiFederationPartial || !merged.Partial {
+		t.Errorf("expect partial result, got code %d partial %t", code, merged.Partial)
+	}
+
+	if merged, code, _ = Merge(results[2:]); code != common.BcsErrApiFederationFail || merged.Partial {
+		t.Errorf("expect failed result, got code %d partial %t", code, merged.Partial)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package federation
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ApiPrefix is the path prefix of federated requests, followed by the kind and the uri in member clusters
+	ApiPrefix = "/bcsapi/v4/federation/"
+	// ClusterIDPlaceholder in uri of federated request is replaced by the id of each member cluster
+	ClusterIDPlaceholder = "{clusterId}"
+
+	KindMesos   = "mesos"
+	KindK8s     = "k8s"
+	KindStorage = "storage"
+)
+
+// memberPrefixes are path prefixes of single cluster requests of each kind
+var memberPrefixes = map[string]string{
+	KindMesos:   "/bcsapi/v4/scheduler/mesos/",
+	KindK8s:     "/bcsapi/v4/scheduler/k8s/",
+	KindStorage: "/bcsapi/v4/storage/",
+}
+
+// MemberURI splits the path of federated request into the kind and the uri in the member cluster.
+// For example, /bcsapi/v4/federation/storage/query/mesos/dynamic/clusters/{clusterId}/application
+// is of kind storage and uri query/mesos/dynamic/clusters/BCS-MESOS-10001/application in BCS-MESOS-10001.
+func MemberURI(path, clusterID string) (kind, uri string, err error) {
+	if !strings.HasPrefix(path, ApiPrefix) {
+		return "", "", fmt.Errorf("%s is not a federated request", path)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(path, ApiPrefix), "/", 2)
+	if _, ok := memberPrefixes[parts[0]]; !ok || len(parts) < 2 || parts[1] == "" {
+		return "", "", fmt.Errorf("federated request %s is not supported, kind must be one of mesos, k8s and storage", path)
+	}
+	if parts[0] == KindStorage {
+		if err := checkStorageURI(parts[1]); err != nil {
+			return "", "", fmt.Errorf("federated request %s is not supported: %v", path, err)
+		}
+	}
+	return parts[0], strings.Replace(parts[1], ClusterIDPlaceholder, clusterID, -1), nil
+}
+
+// checkStorageURI requires the clusters in storage uri to be {clusterId}. Storage is shared by all clusters and
+// authorized by the cluster in uri, so a uri naming a cluster directly would query that cluster with the authority
+// checked against each member cluster.
+func checkStorageURI(uri string) error {
+	segments := strings.Split(uri, "/")
+	found := false
+	for i, segment := range segments {
+		if segment != "clusters" {
+			continue
+		}
+		if i+1 >= len(segments) || segments[i+1] != ClusterIDPlaceholder {
+			return fmt.Errorf("cluster of storage uri must be %s", ClusterIDPlaceholder)
+		}
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("storage uri must contain clusters/%s", ClusterIDPlaceholder)
+	}
+	return nil
+}
+
+// MemberPath returns the path of single cluster request in the member cluster for the federated request,
+// it is used to check the authority in each member cluster.
+func MemberPath(path, clusterID string) (string, error) {
+	kind, uri, err := MemberURI(path, clusterID)
+	if err != nil {
+		return "", err
+	}
+	return memberPrefixes[kind] + uri, nil
+}
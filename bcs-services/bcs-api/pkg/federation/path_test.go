@@ -0,0 +1,43 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package federation
+
+import (
+	"testing"
+)
+
+func TestMemberPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		valid    bool
+	}{
+		{"/bcsapi/v4/federation/mesos/namespaces/ns/applications", "/bcsapi/v4/scheduler/mesos/namespaces/ns/applications", true},
+		{"/bcsapi/v4/federation/k8s/namespaces/ns/deployments", "/bcsapi/v4/scheduler/k8s/namespaces/ns/deployments", true},
+		{"/bcsapi/v4/federation/storage/query/mesos/dynamic/clusters/{clusterId}/application",
+			"/bcsapi/v4/storage/query/mesos/dynamic/clusters/BCS-MESOS-10001/application", true},
+		{"/bcsapi/v4/federation/storage/query/mesos/dynamic/clusters/BCS-MESOS-10002/application", "", false},
+		{"/bcsapi/v4/federation/storage/events", "", false},
+		{"/bcsapi/v4/federation/storage/query/mesos/dynamic/clusters/", "", false},
+		{"/bcsapi/v4/federation/netservice/pools", "", false},
+		{"/bcsapi/v4/federation/mesos", "", false},
+		{"/bcsapi/v4/scheduler/mesos/namespaces/ns/applications", "", false},
+	}
+	for _, test := range tests {
+		memberPath, err := MemberPath(test.path, "BCS-MESOS-10001")
+		if (err == nil) != test.valid || memberPath != test.expected {
+			t.Errorf("%s: expect %s valid %t, got %s error %v", test.path, test.expected, test.valid, memberPath, err)
+		}
+	}
+}
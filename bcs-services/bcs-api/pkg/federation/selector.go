@@ -0,0 +1,167 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package federation
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// ClusterIDsHeaderKey is the header of member cluster ids separated by ","
+	ClusterIDsHeaderKey = "BCS-ClusterIDs"
+	// ProjectIDHeaderKey is the header of project id, clusters of the project are selected
+	ProjectIDHeaderKey = "BCS-ProjectID"
+	// ClusterSelectorHeaderKey is the header of cluster label selector, such as "env=prod,region in (sz,sh)"
+	ClusterSelectorHeaderKey = "BCS-ClusterSelector"
+	// StopOnFailureHeaderKey is the header to stop the fan-out operation at the first failed cluster
+	StopOnFailureHeaderKey = "BCS-StopOnFailure"
+)
+
+// Cluster is a bcs cluster which can be selected as member of federated requests
+type Cluster struct {
+	ID        string            `json:"cluster_id"`
+	ProjectID string            `json:"project_id"`
+	Labels    map[string]string `json:"labels"`
+}
+
+// Selector selects member clusters of federated requests, all the given conditions must be matched
+type Selector struct {
+	ClusterIDs []string
+	ProjectID  string
+	Labels     labels.Selector
+}
+
+// ParseSelector parses the selector from headers of federated request
+func ParseSelector(header http.Header) (*Selector, error) {
+	selector := &Selector{
+		ProjectID: strings.TrimSpace(header.Get(ProjectIDHeaderKey)),
+	}
+	for _, clusterID := range strings.Split(header.Get(ClusterIDsHeaderKey), ",") {
+		if clusterID = strings.TrimSpace(clusterID); clusterID != "" {
+			selector.ClusterIDs = append(selector.ClusterIDs, clusterID)
+		}
+	}
+	if raw := strings.TrimSpace(header.Get(ClusterSelectorHeaderKey)); raw != "" {
+		parsed, err := labels.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid header %s: %s", ClusterSelectorHeaderKey, err.Error())
+		}
+		selector.Labels = parsed
+	}
+
+	if len(selector.ClusterIDs) == 0 && selector.ProjectID == "" && selector.Labels == nil {
+		return nil, fmt.Errorf("one of header %s, %s and %s is required",
+			ClusterIDsHeaderKey, ProjectIDHeaderKey, ClusterSelectorHeaderKey)
+	}
+	return selector, nil
+}
+
+// Select returns the sorted ids of clusters matching the selector.
+// Cluster ids given explicitly are selected even if they are not known, so that they are reported in results
+// rather than ignored silently.
+func (s *Selector) Select(clusters []Cluster) []string {
+	known := make(map[string]Cluster, len(clusters))
+	for _, cluster := range clusters {
+		known[cluster.ID] = cluster
+	}
+
+	candidates := clusters
+	if len(s.ClusterIDs) > 0 {
+		candidates = make([]Cluster, 0, len(s.ClusterIDs))
+		for _, clusterID := range s.ClusterIDs {
+			cluster, ok := known[clusterID]
+			if !ok {
+				cluster = Cluster{ID: clusterID}
+			}
+			candidates = append(candidates, cluster)
+		}
+	}
+
+	selected := make(map[string]bool)
+	for _, cluster := range candidates {
+		if s.ProjectID != "" && cluster.ProjectID != s.ProjectID {
+			continue
+		}
+		if s.Labels != nil && !s.Labels.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+		selected[cluster.ID] = true
+	}
+
+	clusterIDs := make([]string, 0, len(selected))
+	for clusterID := range selected {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+	sort.Strings(clusterIDs)
+	return clusterIDs
+}
+
+// ListClusters returns all the clusters registered in bcs-api or labeled, with their projects and labels
+func ListClusters() ([]Cluster, error) {
+	infos, err := sqlstore.ListBCSClusterInfos()
+	if err != nil {
+		return nil, err
+	}
+	clusterLabels, err := sqlstore.ListClusterLabels()
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make(map[string]*Cluster)
+	get := func(clusterID string) *Cluster {
+		cluster, ok := clusters[clusterID]
+		if !ok {
+			cluster = &Cluster{ID: clusterID, Labels: make(map[string]string)}
+			clusters[clusterID] = cluster
+		}
+		return cluster
+	}
+	for _, info := range infos {
+		get(info.SourceClusterId).ProjectID = info.SourceProjectId
+	}
+	for _, label := range clusterLabels {
+		get(label.ClusterId).Labels[label.Key] = label.Value
+	}
+
+	result := make([]Cluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		result = append(result, *cluster)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// SelectClusters returns ids of member clusters selected by headers of federated request
+func SelectClusters(header http.Header) ([]string, error) {
+	selector, err := ParseSelector(header)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := ListClusters()
+	if err != nil {
+		return nil, fmt.Errorf("list clusters failed: %s", err.Error())
+	}
+	clusterIDs := selector.Select(clusters)
+	if len(clusterIDs) == 0 {
+		return nil, fmt.Errorf("no cluster matches the selector")
+	}
+	return clusterIDs, nil
+}
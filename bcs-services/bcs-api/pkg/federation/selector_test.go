@@ -0,0 +1,69 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package federation
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSelectorSelect(t *testing.T) {
+	clusters := []Cluster{
+		{ID: "BCS-MESOS-10001", ProjectID: "p1", Labels: map[string]string{"env": "prod", "region": "sz"}},
+		{ID: "BCS-MESOS-10002", ProjectID: "p1", Labels: map[string]string{"env": "test", "region": "sh"}},
+		{ID: "BCS-K8S-15001", ProjectID: "p2", Labels: map[string]string{"env": "prod", "region": "sh"}},
+		{ID: "BCS-K8S-15002", Labels: map[string]string{}},
+	}
+	tests := []struct {
+		name     string
+		header   map[string]string
+		expected []string
+	}{
+		{"cluster ids", map[string]string{ClusterIDsHeaderKey: "BCS-MESOS-10002, BCS-MESOS-10001"}, []string{"BCS-MESOS-10001", "BCS-MESOS-10002"}},
+		{"unknown cluster id", map[string]string{ClusterIDsHeaderKey: "BCS-MESOS-19999"}, []string{"BCS-MESOS-19999"}},
+		{"project", map[string]string{ProjectIDHeaderKey: "p1"}, []string{"BCS-MESOS-10001", "BCS-MESOS-10002"}},
+		{"labels", map[string]string{ClusterSelectorHeaderKey: "env=prod"}, []string{"BCS-K8S-15001", "BCS-MESOS-10001"}},
+		{"set based labels", map[string]string{ClusterSelectorHeaderKey: "region in (sh),env!=test"}, []string{"BCS-K8S-15001"}},
+		{"project and labels", map[string]string{ProjectIDHeaderKey: "p1", ClusterSelectorHeaderKey: "region=sh"}, []string{"BCS-MESOS-10002"}},
+		{"cluster ids and labels", map[string]string{ClusterIDsHeaderKey: "BCS-MESOS-10001,BCS-K8S-15002", ClusterSelectorHeaderKey: "env"}, []string{"BCS-MESOS-10001"}},
+		{"no match", map[string]string{ProjectIDHeaderKey: "p3"}, []string{}},
+	}
+	for _, test := range tests {
+		header := make(http.Header)
+		for key, value := range test.header {
+			header.Set(key, value)
+		}
+		selector, err := ParseSelector(header)
+		if err != nil {
+			t.Errorf("%s: parse selector failed: %s", test.name, err.Error())
+			continue
+		}
+		if selected := selector.Select(clusters); !reflect.DeepEqual(selected, test.expected) {
+			t.Errorf("%s: expect %v, got %v", test.name, test.expected, selected)
+		}
+	}
+}
+
+func TestParseSelectorError(t *testing.T) {
+	if _, err := ParseSelector(http.Header{}); err == nil {
+		t.Errorf("selector without conditions should be invalid")
+	}
+
+	header := make(http.Header)
+	header.Set(ClusterSelectorHeaderKey, "env in prod")
+	if _, err := ParseSelector(header); err == nil {
+		t.Errorf("selector %s should be invalid", header.Get(ClusterSelectorHeaderKey))
+	}
+}
@@ -0,0 +1,49 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package models
+
+import "time"
+
+// AuditRecord records who did what through bcs-api, one record for each mutating request
+type AuditRecord struct {
+	ID        uint   `gorm:"primary_key" json:"id"`
+	RequestID string `gorm:"size:64" json:"request_id"`
+	User      string `gorm:"size:128;index" json:"user"`
+	// TokenType describes how the user is authenticated, such as session, kubeconfig or bkiam
+	TokenType string `gorm:"size:32" json:"token_type"`
+	SourceIP  string `gorm:"size:64" json:"source_ip"`
+	ClusterID string `gorm:"size:64;index" json:"cluster_id"`
+	Verb      string `gorm:"size:16" json:"verb"`
+	Resource  string `gorm:"size:1024" json:"resource"`
+	// BodyDigest is the sha256 of request body, body itself is not recorded for it may contain secrets
+	BodyDigest   string    `gorm:"size:64" json:"body_digest"`
+	ResponseCode int       `json:"response_code"`
+	LatencyMs    int64     `json:"latency_ms"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+}
+
+// UserTokenTypeName returns the readable name of user token type
+func UserTokenTypeName(tokenType uint) string {
+	switch tokenType {
+	case UserTokenTypeSession:
+		return "session"
+	case UserTokenTypeKubeConfigForPaas:
+		return "kubeconfig_paas"
+	case UserTokenTypeKubeConfigPlain:
+		return "kubeconfig_plain"
+	case UserTokenTypeScoped:
+		return "scoped"
+	}
+	return "unknown"
+}
@@ -70,6 +70,16 @@ type RegisterToken struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// ClusterLabel is a label of bcs cluster, it is used for selecting member clusters of federated requests.
+// ClusterId is the bcs cluster id, such as BCS-MESOS-10001, rather than the id of Cluster.
+type ClusterLabel struct {
+	ID        uint      `json:"-" gorm:"primary_key"`
+	ClusterId string    `json:"cluster_id" gorm:"size:100;unique_index:idx_cluster_label_key"`
+	Key       string    `json:"key" gorm:"size:63;unique_index:idx_cluster_label_key"`
+	Value     string    `json:"value" gorm:"size:63"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type TkeLbSubnet struct {
 	ID            uint   `gorm:"primary_key"`
 	ClusterRegion string `gorm:"unique;not null"`
@@ -0,0 +1,92 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	RoleBindingSubjectUser  = "user"
+	RoleBindingSubjectGroup = "group"
+
+	// PolicyMatchAll matches any kind, verb, cluster or namespace
+	PolicyMatchAll = "*"
+)
+
+// PolicyRule grants verbs on resource kinds
+type PolicyRule struct {
+	Kinds []string `json:"kinds"`
+	Verbs []string `json:"verbs"`
+}
+
+// PolicyRules is stored as json text in database
+type PolicyRules []PolicyRule
+
+// Value implements driver.Valuer
+func (r PolicyRules) Value() (driver.Value, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (r *PolicyRules) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("can not scan %T into PolicyRules", value)
+	}
+	return json.Unmarshal(data, r)
+}
+
+// Role is a named set of policy rules of the local policy engine
+type Role struct {
+	ID          uint        `gorm:"primary_key" json:"id"`
+	Name        string      `gorm:"unique;not null;size:64" json:"name"`
+	Description string      `gorm:"size:256" json:"description"`
+	Rules       PolicyRules `gorm:"type:text" json:"rules"`
+	// KubeRole is the name of role template(without prefix bke-) bound in k8s clusters for users of this role,
+	// such as cluster-manage, empty means the role is not synced to k8s clusters
+	KubeRole  string    `gorm:"size:64" json:"kube_role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RoleBinding grants a role to a user or group in the scope of cluster and namespace
+type RoleBinding struct {
+	ID          uint   `gorm:"primary_key" json:"id"`
+	Name        string `gorm:"unique;not null;size:64" json:"name"`
+	RoleName    string `gorm:"size:64;index" json:"role_name"`
+	SubjectKind string `gorm:"size:16" json:"subject_kind"`
+	Subject     string `gorm:"size:128;index" json:"subject"`
+	// ClusterID is * for all clusters
+	ClusterID string `gorm:"size:64" json:"cluster_id"`
+	// Namespace is * or empty for all namespaces
+	Namespace string    `gorm:"size:64" json:"namespace"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package models
+
+import "time"
+
+// RateLimitConfig is the rate limit rules changed at runtime, there is at most one record.
+// Rules is the json of options.RateLimitRules.
+type RateLimitConfig struct {
+	ID        uint      `json:"-" gorm:"primary_key"`
+	Rules     string    `json:"rules" gorm:"type:text"`
+	Updater   string    `json:"updater" gorm:"size:256"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
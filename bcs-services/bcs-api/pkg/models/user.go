@@ -13,7 +13,12 @@
 
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 type BackendCredentials map[string]interface{}
 
@@ -29,12 +34,17 @@ type User struct {
 	// These field startswith Backend* will be set by auth filters
 	BackendType        string             `gorm:"-"`
 	BackendCredentials BackendCredentials `gorm:"-"`
+	// Groups is set by external authenticators such as oidc
+	Groups []string `gorm:"-"`
 }
 
 const (
 	UserTokenTypeSession = iota + 1
 	UserTokenTypeKubeConfigForPaas
 	UserTokenTypeKubeConfigPlain
+	// UserTokenTypeScoped is created by users themselves, it may be limited to clusters, namespaces and read-only
+	// requests, and can be revoked or rotated
+	UserTokenTypeScoped
 )
 
 // UserToken is the token which can be used by tools like kubectl to connect to Kubernetes clusers.
@@ -45,6 +55,15 @@ type UserToken struct {
 	Value     string `gorm:"unique;size:64"`
 	ExpiresAt time.Time
 	CreatedAt time.Time
+
+	// Name describes what the token is used for, such as the ci job using it
+	Name string `gorm:"size:64"`
+	// Scopes limits the clusters and namespaces the token can access, empty means no limit
+	Scopes TokenScopes `gorm:"type:text"`
+	// ReadOnly token can only be used for get, list and watch requests
+	ReadOnly bool
+	// RevokedAt is set when the token is revoked, a revoked token is never valid again
+	RevokedAt *time.Time
 }
 
 // HasExpired mean that is this token has been expired
@@ -55,8 +74,92 @@ func (t *UserToken) HasExpired() bool {
 	return false
 }
 
+// IsRevoked means the token has been revoked by its owner or super user
+func (t *UserToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// Permits checks whether the token can access the namespace of cluster, empty namespace means cluster level
+// resources, which can not be accessed by tokens limited to namespaces. write is true for mutating requests.
+func (t *UserToken) Permits(clusterID, namespace string, write bool) bool {
+	if write && t.ReadOnly {
+		return false
+	}
+	if len(t.Scopes) == 0 {
+		return true
+	}
+	for _, scope := range t.Scopes {
+		if scope.ClusterID != clusterID && scope.ClusterID != PolicyMatchAll {
+			continue
+		}
+		if len(scope.Namespaces) == 0 {
+			return true
+		}
+		for _, ns := range scope.Namespaces {
+			if namespace != "" && (ns == namespace || ns == PolicyMatchAll) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PermitsCluster checks whether the cluster is in scopes of the token, no matter which namespaces are limited
+func (t *UserToken) PermitsCluster(clusterID string) bool {
+	if len(t.Scopes) == 0 {
+		return true
+	}
+	for _, scope := range t.Scopes {
+		if scope.ClusterID == clusterID || scope.ClusterID == PolicyMatchAll {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenScope limits a token to a cluster, and namespaces of it if any
+type TokenScope struct {
+	ClusterID  string   `json:"cluster_id"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// TokenScopes is stored as json text in database
+type TokenScopes []TokenScope
+
+// Value implements driver.Valuer
+func (s TokenScopes) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (s *TokenScopes) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("can not scan %T into TokenScopes", value)
+	}
+	if len(data) == 0 {
+		*s = nil
+		return nil
+	}
+	return json.Unmarshal(data, s)
+}
+
 const (
 	ExternalUserSourceTypeBCS = iota + 1
+	ExternalUserSourceTypeOIDC
+	ExternalUserSourceTypeStaticToken
 )
 
 // ExternalUserRecord stores the replationship between [bke internal user] and [user from external provider]
@@ -0,0 +1,72 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package models
+
+import (
+	"testing"
+)
+
+func TestUserTokenPermits(t *testing.T) {
+	scopes := TokenScopes{
+		{ClusterID: "BCS-K8S-001"},
+		{ClusterID: "BCS-K8S-002", Namespaces: []string{"ci", "test"}},
+	}
+	tests := []struct {
+		name      string
+		token     *UserToken
+		clusterID string
+		namespace string
+		write     bool
+		permitted bool
+	}{
+		{"no scopes", &UserToken{}, "BCS-K8S-003", "", true, true},
+		{"read only write", &UserToken{ReadOnly: true}, "BCS-K8S-001", "ci", true, false},
+		{"read only read", &UserToken{ReadOnly: true}, "BCS-K8S-001", "ci", false, true},
+		{"whole cluster", &UserToken{Scopes: scopes}, "BCS-K8S-001", "", true, true},
+		{"cluster not in scopes", &UserToken{Scopes: scopes}, "BCS-K8S-003", "ci", false, false},
+		{"namespace in scopes", &UserToken{Scopes: scopes}, "BCS-K8S-002", "test", true, true},
+		{"namespace not in scopes", &UserToken{Scopes: scopes}, "BCS-K8S-002", "prod", false, false},
+		{"cluster level of namespaced scope", &UserToken{Scopes: scopes}, "BCS-K8S-002", "", false, false},
+		{"all clusters", &UserToken{Scopes: TokenScopes{{ClusterID: PolicyMatchAll, Namespaces: []string{"ci"}}}}, "BCS-K8S-003", "ci", true, true},
+	}
+	for _, test := range tests {
+		if permitted := test.token.Permits(test.clusterID, test.namespace, test.write); permitted != test.permitted {
+			t.Errorf("%s: expect permitted %t, got %t", test.name, test.permitted, permitted)
+		}
+	}
+
+	token := &UserToken{Scopes: scopes}
+	if !token.PermitsCluster("BCS-K8S-002") || token.PermitsCluster("BCS-K8S-003") {
+		t.Errorf("PermitsCluster does not match cluster in scopes")
+	}
+}
+
+func TestTokenScopesScan(t *testing.T) {
+	scopes := TokenScopes{{ClusterID: "BCS-K8S-001", Namespaces: []string{"ci"}}}
+	value, err := scopes.Value()
+	if err != nil {
+		t.Fatalf("value of scopes failed: %s", err.Error())
+	}
+
+	var scanned TokenScopes
+	if err := scanned.Scan(value); err != nil {
+		t.Fatalf("scan scopes failed: %s", err.Error())
+	}
+	if len(scanned) != 1 || scanned[0].ClusterID != "BCS-K8S-001" || scanned[0].Namespaces[0] != "ci" {
+		t.Errorf("scanned scopes %v not equal to %v", scanned, scopes)
+	}
+	if err := scanned.Scan([]byte{}); err != nil || scanned != nil {
+		t.Errorf("scan empty value should get nil scopes, got %v, %v", scanned, err)
+	}
+}
@@ -0,0 +1,272 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"bk-bcs/bcs-services/bcs-api/options"
+
+	"golang.org/x/time/rate"
+)
+
+// Class is the class of request, each class has its own budget
+type Class string
+
+const (
+	ClassRead        Class = "read"
+	ClassMutate      Class = "mutate"
+	ClassLongRunning Class = "long_running"
+
+	DimensionUser    = "user"
+	DimensionToken   = "token"
+	DimensionCluster = "cluster"
+
+	// idleBucketTTL is the time after which idle buckets are removed
+	idleBucketTTL = 10 * time.Minute
+	// inflightRetryAfter is the retry after of requests rejected by max inflight
+	inflightRetryAfter = time.Second
+)
+
+var (
+	// Classes are all the classes of request
+	Classes = []Class{ClassRead, ClassMutate, ClassLongRunning}
+	// Dimensions are all the dimensions requests are limited by
+	Dimensions = []string{DimensionUser, DimensionToken, DimensionCluster}
+)
+
+// ClassOf returns the class of request by method, long running requests such as watch and exec are of their own class
+func ClassOf(method string, longRunning bool) Class {
+	if longRunning {
+		return ClassLongRunning
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ClassRead
+	}
+	return ClassMutate
+}
+
+// Request is the request to be limited, empty User, Token or ClusterID is not limited in that dimension
+type Request struct {
+	User      string
+	Token     string
+	ClusterID string
+	// ClusterIDs are member clusters of federated request, it is charged in the budget of each of them
+	ClusterIDs []string
+	Class      Class
+}
+
+// limitKey is the key of budget the request is charged in
+type limitKey struct {
+	dimension string
+	key       string
+}
+
+// Limiter limits requests by token buckets of user, token and cluster
+type Limiter struct {
+	sync.Mutex
+	rules     options.RateLimitRules
+	exempt    map[string]bool
+	buckets   map[string]*bucket
+	rejected  map[string]uint64
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	limit    options.RateLimit
+	limiter  *rate.Limiter
+	inflight int
+	lastUsed time.Time
+}
+
+// NewLimiter creates a limiter with given rules
+func NewLimiter(rules options.RateLimitRules) *Limiter {
+	l := &Limiter{
+		buckets:  make(map[string]*bucket),
+		rejected: make(map[string]uint64),
+		now:      time.Now,
+	}
+	l.SetRules(rules)
+	return l
+}
+
+// Rules returns the current rules
+func (l *Limiter) Rules() options.RateLimitRules {
+	l.Lock()
+	defer l.Unlock()
+	return l.rules
+}
+
+// SetRules replaces the rules, existing buckets take the new limits when they are used next time
+func (l *Limiter) SetRules(rules options.RateLimitRules) {
+	exempt := make(map[string]bool, len(rules.ExemptUsers))
+	for _, user := range rules.ExemptUsers {
+		exempt[user] = true
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	l.rules = rules
+	l.exempt = exempt
+}
+
+// Allow checks the request against the budgets of its user, token and clusters, it is allowed only if all of
+// them have room for it.
+// If it is allowed, release must be called when the request finishes. Otherwise, retryAfter is the time to wait
+// before retry and limitedBy is the dimension which rejects the request.
+func (l *Limiter) Allow(req Request) (release func(), retryAfter time.Duration, limitedBy string, ok bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	release = func() {}
+	if l.exempt[req.User] {
+		return release, 0, "", true
+	}
+
+	now := l.now()
+	l.sweep(now)
+
+	keys := []limitKey{
+		{DimensionUser, req.User},
+		{DimensionToken, req.Token},
+		{DimensionCluster, req.ClusterID},
+	}
+	for _, clusterID := range req.ClusterIDs {
+		keys = append(keys, limitKey{DimensionCluster, clusterID})
+	}
+	acquired := make([]*bucket, 0, len(keys))
+	reservations := make([]*rate.Reservation, 0, len(keys))
+	for _, key := range keys {
+		if key.key == "" {
+			continue
+		}
+		dimension := key.dimension
+		limit := l.limitOf(dimension, key.key, req.Class)
+		if limit == (options.RateLimit{}) {
+			continue
+		}
+
+		b := l.bucket(dimension+"/"+string(req.Class)+"/"+key.key, limit, now)
+		if limit.MaxInflight > 0 && b.inflight >= limit.MaxInflight {
+			retryAfter = inflightRetryAfter
+		} else if b.limiter != nil {
+			reservation := b.limiter.ReserveN(now, 1)
+			if delay := reservation.DelayFrom(now); delay > 0 {
+				reservation.CancelAt(now)
+				retryAfter = delay
+			} else {
+				reservations = append(reservations, reservation)
+			}
+		}
+		if retryAfter > 0 {
+			// return the tokens taken from buckets of the other dimensions
+			for _, reservation := range reservations {
+				reservation.CancelAt(now)
+			}
+			l.rejected[dimension+"/"+string(req.Class)]++
+			return release, retryAfter, dimension, false
+		}
+		acquired = append(acquired, b)
+	}
+
+	for _, b := range acquired {
+		b.inflight++
+	}
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			l.Lock()
+			defer l.Unlock()
+			for _, b := range acquired {
+				b.inflight--
+				b.lastUsed = l.now()
+			}
+		})
+	}
+	return release, 0, "", true
+}
+
+// Rejected returns the number of rejected requests of the dimension and class
+func (l *Limiter) Rejected(dimension string, class Class) uint64 {
+	l.Lock()
+	defer l.Unlock()
+	return l.rejected[dimension+"/"+string(class)]
+}
+
+// limitOf returns the limit of the class in budget of the dimension, overrides take precedence
+func (l *Limiter) limitOf(dimension, key string, class Class) options.RateLimit {
+	var budget options.RateLimitBudget
+	switch dimension {
+	case DimensionUser:
+		budget = l.rules.User
+		if override, ok := l.rules.UserOverrides[key]; ok {
+			budget = override
+		}
+	case DimensionToken:
+		budget = l.rules.Token
+	case DimensionCluster:
+		budget = l.rules.Cluster
+		if override, ok := l.rules.ClusterOverrides[key]; ok {
+			budget = override
+		}
+	}
+
+	switch class {
+	case ClassRead:
+		return budget.Read
+	case ClassMutate:
+		return budget.Mutate
+	}
+	return budget.LongRunning
+}
+
+// bucket returns the bucket of key, it is created or reset if the limit is changed
+func (l *Limiter) bucket(key string, limit options.RateLimit, now time.Time) *bucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{}
+		l.buckets[key] = b
+	}
+	if !ok || b.limit != limit {
+		b.limit = limit
+		b.limiter = nil
+		if limit.QPS > 0 {
+			burst := limit.Burst
+			if burst <= 0 {
+				burst = int(math.Ceil(limit.QPS))
+			}
+			b.limiter = rate.NewLimiter(rate.Limit(limit.QPS), burst)
+		}
+	}
+	b.lastUsed = now
+	return b
+}
+
+// sweep removes the buckets idle for idleBucketTTL, so that buckets of expired tokens are not kept forever
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleBucketTTL {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.inflight == 0 && now.Sub(b.lastUsed) > idleBucketTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"bk-bcs/bcs-services/bcs-api/options"
+)
+
+func newTestLimiter(rules options.RateLimitRules) (*Limiter, *time.Time) {
+	now := time.Unix(1500000000, 0)
+	l := NewLimiter(rules)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLimiterTokenBucket(t *testing.T) {
+	l, now := newTestLimiter(options.RateLimitRules{
+		User: options.RateLimitBudget{Read: options.RateLimit{QPS: 1, Burst: 2}},
+	})
+	req := Request{User: "alice", Token: "t1", ClusterID: "BCS-MESOS-10001", Class: ClassRead}
+
+	for i := 0; i < 2; i++ {
+		if _, _, _, ok := l.Allow(req); !ok {
+			t.Fatalf("request %d in burst should be allowed", i)
+		}
+	}
+	_, retryAfter, limitedBy, ok := l.Allow(req)
+	if ok || limitedBy != DimensionUser || retryAfter <= 0 || retryAfter > time.Second {
+		t.Fatalf("request over burst should be limited by user for at most 1s, got ok %t by %s after %s", ok, limitedBy, retryAfter)
+	}
+	if rejected := l.Rejected(DimensionUser, ClassRead); rejected != 1 {
+		t.Errorf("expect 1 rejected request, got %d", rejected)
+	}
+
+	// mutate requests and other users have their own budgets
+	if _, _, _, ok := l.Allow(Request{User: "alice", Class: ClassMutate}); !ok {
+		t.Errorf("mutate request should not be limited by read budget")
+	}
+	if _, _, _, ok := l.Allow(Request{User: "bob", Class: ClassRead}); !ok {
+		t.Errorf("request of another user should not be limited")
+	}
+
+	*now = now.Add(time.Second)
+	if _, _, _, ok := l.Allow(req); !ok {
+		t.Errorf("request should be allowed after the bucket is refilled")
+	}
+}
+
+func TestLimiterDimensions(t *testing.T) {
+	l, _ := newTestLimiter(options.RateLimitRules{
+		User:    options.RateLimitBudget{Mutate: options.RateLimit{QPS: 10}},
+		Cluster: options.RateLimitBudget{Mutate: options.RateLimit{QPS: 1, Burst: 1}},
+		ClusterOverrides: map[string]options.RateLimitBudget{
+			"BCS-MESOS-10002": {Mutate: options.RateLimit{QPS: 100}},
+		},
+		UserOverrides: map[string]options.RateLimitBudget{
+			"ci": {Mutate: options.RateLimit{QPS: 1, Burst: 1}},
+		},
+		ExemptUsers: []string{"admin"},
+	})
+
+	if _, _, _, ok := l.Allow(Request{User: "alice", ClusterID: "BCS-MESOS-10001", Class: ClassMutate}); !ok {
+		t.Fatalf("first request should be allowed")
+	}
+	if _, _, limitedBy, ok := l.Allow(Request{User: "bob", ClusterID: "BCS-MESOS-10001", Class: ClassMutate}); ok || limitedBy != DimensionCluster {
+		t.Errorf("request should be limited by cluster, got ok %t by %s", ok, limitedBy)
+	}
+	// tokens taken from user bucket are returned when cluster rejects, so bob is still in burst of user budget
+	for i := 0; i < 10; i++ {
+		if _, _, _, ok := l.Allow(Request{User: "bob", ClusterID: "BCS-MESOS-10002", Class: ClassMutate}); !ok {
+			t.Fatalf("request %d to cluster with larger override budget should be allowed", i)
+		}
+	}
+
+	if _, _, _, ok := l.Allow(Request{User: "ci", Class: ClassMutate}); !ok {
+		t.Fatalf("first request of ci should be allowed")
+	}
+	if _, _, limitedBy, ok := l.Allow(Request{User: "ci", Class: ClassMutate}); ok || limitedBy != DimensionUser {
+		t.Errorf("request of ci should be limited by user override, got ok %t by %s", ok, limitedBy)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, _, _, ok := l.Allow(Request{User: "admin", ClusterID: "BCS-MESOS-10001", Class: ClassMutate}); !ok {
+			t.Fatalf("request of exempt user should never be limited")
+		}
+	}
+}
+
+func TestLimiterFederatedClusters(t *testing.T) {
+	l, _ := newTestLimiter(options.RateLimitRules{
+		User:    options.RateLimitBudget{Read: options.RateLimit{QPS: 1, Burst: 3}},
+		Cluster: options.RateLimitBudget{Read: options.RateLimit{QPS: 1, Burst: 1}},
+		ClusterOverrides: map[string]options.RateLimitBudget{
+			"BCS-MESOS-10002": {Read: options.RateLimit{QPS: 1, Burst: 2}},
+		},
+	})
+	federated := Request{User: "alice", ClusterIDs: []string{"BCS-MESOS-10002", "BCS-MESOS-10001"}, Class: ClassRead}
+
+	if _, _, _, ok := l.Allow(federated); !ok {
+		t.Fatalf("first federated request should be allowed")
+	}
+	// budget of every member cluster is charged
+	if _, _, limitedBy, ok := l.Allow(Request{User: "bob", ClusterID: "BCS-MESOS-10001", Class: ClassRead}); ok || limitedBy != DimensionCluster {
+		t.Errorf("request to member cluster should be limited by cluster, got ok %t by %s", ok, limitedBy)
+	}
+	if _, _, limitedBy, ok := l.Allow(federated); ok || limitedBy != DimensionCluster {
+		t.Fatalf("federated request should be limited by member cluster, got ok %t by %s", ok, limitedBy)
+	}
+	// token taken from the other member cluster is returned when one member cluster rejects
+	if _, _, _, ok := l.Allow(Request{User: "bob", ClusterID: "BCS-MESOS-10002", Class: ClassRead}); !ok {
+		t.Errorf("request to member cluster with budget left should be allowed")
+	}
+}
+
+func TestLimiterMaxInflight(t *testing.T) {
+	l, _ := newTestLimiter(options.RateLimitRules{
+		Token: options.RateLimitBudget{LongRunning: options.RateLimit{MaxInflight: 1}},
+	})
+	req := Request{User: "alice", Token: "t1", Class: ClassLongRunning}
+
+	release, _, _, ok := l.Allow(req)
+	if !ok {
+		t.Fatalf("first watch should be allowed")
+	}
+	if _, _, limitedBy, ok := l.Allow(req); ok || limitedBy != DimensionToken {
+		t.Fatalf("second watch should be limited by token, got ok %t by %s", ok, limitedBy)
+	}
+	release()
+	release()
+	if _, _, _, ok := l.Allow(req); !ok {
+		t.Errorf("watch should be allowed after the previous one is released")
+	}
+}
+
+func TestLimiterSetRules(t *testing.T) {
+	l, _ := newTestLimiter(options.RateLimitRules{
+		User: options.RateLimitBudget{Read: options.RateLimit{QPS: 1, Burst: 1}},
+	})
+	req := Request{User: "alice", Class: ClassRead}
+	l.Allow(req)
+	if _, _, _, ok := l.Allow(req); ok {
+		t.Fatalf("request over burst should be limited")
+	}
+
+	l.SetRules(options.RateLimitRules{ExemptUsers: []string{"alice"}})
+	if _, _, _, ok := l.Allow(req); !ok {
+		t.Errorf("request should be allowed after user is exempted at runtime")
+	}
+}
+
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		method      string
+		longRunning bool
+		class       Class
+	}{
+		{http.MethodGet, false, ClassRead},
+		{http.MethodHead, false, ClassRead},
+		{http.MethodPost, false, ClassMutate},
+		{http.MethodDelete, false, ClassMutate},
+		{http.MethodGet, true, ClassLongRunning},
+		{http.MethodPost, true, ClassLongRunning},
+	}
+	for _, test := range tests {
+		if class := ClassOf(test.method, test.longRunning); class != test.class {
+			t.Errorf("%s long running %t: expect %s, got %s", test.method, test.longRunning, test.class, class)
+		}
+	}
+}
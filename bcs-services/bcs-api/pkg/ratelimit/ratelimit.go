@@ -0,0 +1,207 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bk-bcs/bcs-common/common"
+	"bk-bcs/bcs-common/common/blog"
+	bhttp "bk-bcs/bcs-common/common/http"
+	"bk-bcs/bcs-common/common/metric"
+	"bk-bcs/bcs-services/bcs-api/config"
+	"bk-bcs/bcs-services/bcs-api/options"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
+)
+
+const (
+	// RequestAttribute is the request attribute of Request set by auth filter for v4http actions
+	RequestAttribute = "bcs-ratelimit-request"
+
+	defaultSyncInterval = 30
+)
+
+var globalLimiter *Limiter
+
+// Init creates the global limiter with the rules changed at runtime if any, or the ones in config,
+// and keeps loading the rules changed at runtime by other bcs-api instances.
+func Init(conf *config.ApiServConfig) error {
+	if !conf.RateLimit.Enable {
+		blog.Infof("rate limit is disabled")
+		return nil
+	}
+
+	rules := conf.RateLimit.Rules
+	updatedAt := time.Time{}
+	if record := sqlstore.GetRateLimitConfig(); record != nil {
+		if err := json.Unmarshal([]byte(record.Rules), &rules); err != nil {
+			return fmt.Errorf("invalid rate limit rules in database: %s", err.Error())
+		}
+		updatedAt = record.UpdatedAt
+	}
+	globalLimiter = NewLimiter(rules)
+
+	interval := conf.RateLimit.SyncInterval
+	if interval <= 0 {
+		interval = defaultSyncInterval
+	}
+	go syncRules(time.Duration(interval)*time.Second, updatedAt)
+
+	blog.Infof("rate limit is enabled, rules are synced every %d seconds", interval)
+	return nil
+}
+
+// Enabled returns whether rate limit is enabled
+func Enabled() bool {
+	return globalLimiter != nil
+}
+
+// Allow checks the request by the global limiter, requests are always allowed if rate limit is disabled
+func Allow(req Request) (release func(), retryAfter time.Duration, limitedBy string, ok bool) {
+	if globalLimiter == nil {
+		return func() {}, 0, "", true
+	}
+	release, retryAfter, limitedBy, ok = globalLimiter.Allow(req)
+	if !ok {
+		blog.V(3).Infof("request of user %s to cluster %s is limited by %s %s budget, retry after %s",
+			req.User, req.ClusterID, limitedBy, req.Class, retryAfter)
+	}
+	return release, retryAfter, limitedBy, ok
+}
+
+// GetRules returns the rules of the global limiter
+func GetRules() (options.RateLimitRules, error) {
+	if globalLimiter == nil {
+		return options.RateLimitRules{}, fmt.Errorf("rate limit is disabled")
+	}
+	return globalLimiter.Rules(), nil
+}
+
+// UpdateRules saves the rules to database so that they are synced by all bcs-api instances, and applies them at once
+func UpdateRules(rules options.RateLimitRules, updater string) error {
+	if globalLimiter == nil {
+		return fmt.Errorf("rate limit is disabled")
+	}
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+	if err := sqlstore.SaveRateLimitConfig(&m.RateLimitConfig{Rules: string(raw), Updater: updater}); err != nil {
+		return err
+	}
+	globalLimiter.SetRules(rules)
+	blog.Infof("rate limit rules are updated by %s: %s", updater, string(raw))
+	return nil
+}
+
+func syncRules(interval time.Duration, updatedAt time.Time) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		record := sqlstore.GetRateLimitConfig()
+		if record == nil || !record.UpdatedAt.After(updatedAt) {
+			continue
+		}
+		rules := options.RateLimitRules{}
+		if err := json.Unmarshal([]byte(record.Rules), &rules); err != nil {
+			blog.Errorf("invalid rate limit rules updated by %s: %s", record.Updater, err.Error())
+			continue
+		}
+		updatedAt = record.UpdatedAt
+		globalLimiter.SetRules(rules)
+		blog.Infof("rate limit rules updated by %s are synced", record.Updater)
+	}
+}
+
+// RetryAfterSeconds returns the value of Retry-After header, at least one second
+func RetryAfterSeconds(retryAfter time.Duration) int {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// RejectedMessage returns the message of request rejected by the budget of dimension
+func RejectedMessage(retryAfter time.Duration, limitedBy string) string {
+	return fmt.Sprintf("%s: too many requests of the %s, retry after %d seconds",
+		common.BcsErrApiRateLimitedStr, limitedBy, RetryAfterSeconds(retryAfter))
+}
+
+// WriteRejected replies 429 with Retry-After in seconds for the rejected request
+func WriteRejected(rw http.ResponseWriter, retryAfter time.Duration, limitedBy string) {
+	seconds := RetryAfterSeconds(retryAfter)
+	message := RejectedMessage(retryAfter, limitedBy)
+	data, _ := json.Marshal(bhttp.APIRespone{
+		Result:  false,
+		Code:    common.BcsErrApiRateLimited,
+		Message: message,
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Retry-After", strconv.Itoa(seconds))
+	rw.WriteHeader(http.StatusTooManyRequests)
+	rw.Write(data)
+}
+
+// TokenOf returns the token of request carried by X-Bcs-User-Token or bearer authorization header
+func TokenOf(header http.Header) string {
+	if token := header.Get("X-Bcs-User-Token"); token != "" {
+		return token
+	}
+	authorization := header.Get("Authorization")
+	if strings.HasPrefix(authorization, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+	}
+	return ""
+}
+
+// Metrics returns the metrics of requests rejected in each dimension and class
+func Metrics() []*metric.MetricContructor {
+	metrics := make([]*metric.MetricContructor, 0, len(Dimensions)*len(Classes))
+	for _, dimension := range Dimensions {
+		for _, class := range Classes {
+			dimension, class := dimension, class
+			metrics = append(metrics, &metric.MetricContructor{
+				GetMeta: func() *metric.MetricMeta {
+					// series are distinguished by const labels, as each constructor is described separately
+					return &metric.MetricMeta{
+						Name:        "ratelimit_rejected_requests_total",
+						Help:        "number of requests rejected by rate limit",
+						ConstLables: map[string]string{"limited_by": dimension, "class": string(class)},
+					}
+				},
+				GetResult: func() (*metric.MetricResult, error) {
+					var rejected uint64
+					if globalLimiter != nil {
+						rejected = globalLimiter.Rejected(dimension, class)
+					}
+					value, err := metric.FormFloatOrString(float64(rejected))
+					if err != nil {
+						return nil, err
+					}
+					return &metric.MetricResult{Value: value}, nil
+				},
+			})
+		}
+	}
+	return metrics
+}
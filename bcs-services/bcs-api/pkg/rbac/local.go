@@ -0,0 +1,177 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package rbac
+
+import (
+	"fmt"
+	"strings"
+
+	"bk-bcs/bcs-common/common/blog"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	rbacUtils "bk-bcs/bcs-services/bcs-api/pkg/rbac/utils"
+	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
+)
+
+const (
+	OperationAdd    = "add"
+	OperationDelete = "delete"
+)
+
+// SyncRbacFromLocal syncs all the rolebindings of local policy engine to k8s clusters
+func SyncRbacFromLocal() {
+	bindings, err := sqlstore.ListRoleBindings(&m.RoleBinding{})
+	if err != nil {
+		blog.Errorf("error when list local rolebindings: %s", err.Error())
+		return
+	}
+	for i := range bindings {
+		role := sqlstore.GetRole(bindings[i].RoleName)
+		if role == nil {
+			continue
+		}
+		if err := SyncLocalRoleBinding(&bindings[i], role.KubeRole, OperationAdd); err != nil {
+			blog.Errorf("error when sync local rolebinding %s: %s", bindings[i].Name, err.Error())
+		}
+	}
+}
+
+// KubeGroupName returns the name of group in k8s rbac, it is the group impersonated by bcs-api proxy and the
+// subject of rolebindings synced for the group. k8s rbac doesn't allow label to contain ":", so replaced by "."
+func KubeGroupName(group string) string {
+	return strings.Replace(group, ":", ".", -1)
+}
+
+// SyncLocalRoleBinding syncs the rolebinding of local policy engine to k8s clusters as clusterrolebinding, or as
+// rolebinding if it is scoped to a namespace. Only bindings of roles with kube role are synced. Bindings of groups
+// take effect in k8s because bcs-api proxy impersonates the groups of users from external authenticators.
+func SyncLocalRoleBinding(binding *m.RoleBinding, kubeRole, operation string) error {
+	if kubeRole == "" {
+		return nil
+	}
+
+	var subject string
+	switch binding.SubjectKind {
+	case m.RoleBindingSubjectUser:
+		// k8s rbac doesn't allow label to contain ":", so replaced by "."
+		subject = strings.Replace(binding.Subject, ":", ".", 1)
+	case m.RoleBindingSubjectGroup:
+		subject = KubeGroupName(binding.Subject)
+	default:
+		return nil
+	}
+	namespace := localBindingNamespace(binding.Namespace)
+
+	clusters, err := localBindingClusters(binding.ClusterID)
+	if err != nil {
+		return err
+	}
+	for _, cluster := range clusters {
+		// other bindings may still grant the same kube role after deleting this one
+		if operation == OperationDelete && stillBound(binding, kubeRole, cluster.ID) {
+			blog.Infof("kube role %s is still bound to %s %s in cluster %s by other rolebindings, skipping",
+				kubeRole, binding.SubjectKind, subject, cluster.ID)
+			continue
+		}
+
+		if binding.SubjectKind == m.RoleBindingSubjectGroup {
+			err = syncGroupLevelData(subject, kubeRole, operation, cluster.ID, namespace)
+		} else if namespace == "" {
+			err = syncClusterLevelData(subject, kubeRole, operation, cluster.ID, clusterRoleBindingTypeFromLocal)
+		} else {
+			err = syncNamespaceLevelData(subject, kubeRole, operation, cluster.ID, namespace)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncGroupLevelData syncs the binding of group to the cluster, as clusterrolebinding if namespace is empty
+func syncGroupLevelData(group, kubeRole, operation, clusterID, namespace string) error {
+	kubeClient, err := rbacUtils.GetKubeClient(clusterID)
+	if err != nil {
+		return fmt.Errorf("failed to build kubeclient for cluster %s: %s", clusterID, err.Error())
+	}
+
+	rm := newGroupRbacManager(clusterID, kubeClient)
+	switch {
+	case operation == OperationAdd && namespace == "":
+		if err := rm.ensureRole(kubeRole); err != nil {
+			return err
+		}
+		return rm.ensureAddClusterRoleBinding(group, kubeRole, clusterRoleBindingTypeFromLocal)
+	case operation == OperationAdd:
+		if err := rm.ensureRole(kubeRole); err != nil {
+			return err
+		}
+		return rm.ensureAddRoleBinding(group, kubeRole, namespace)
+	case operation == OperationDelete && namespace == "":
+		return rm.ensureDeleteClusterRoleBinding(group, kubeRole, clusterRoleBindingTypeFromLocal)
+	case operation == OperationDelete:
+		return rm.ensureDeleteRoleBinding(group, kubeRole, namespace)
+	}
+	return fmt.Errorf("invalid operabion: %s", operation)
+}
+
+// localBindingClusters returns the clusters managed by bcs-api which the binding applies to
+func localBindingClusters(clusterID string) ([]m.Cluster, error) {
+	if clusterID == m.PolicyMatchAll {
+		return sqlstore.GetAllCluster(), nil
+	}
+
+	cluster := sqlstore.GetClusterByFuzzyClusterId(clusterID)
+	if cluster == nil {
+		return nil, fmt.Errorf("cluster not exist in bcs-apiserver, cluster: %s", clusterID)
+	}
+	return []m.Cluster{*cluster}, nil
+}
+
+// stillBound checks if other bindings of the subject grant the kube role in the same scope of cluster
+func stillBound(binding *m.RoleBinding, kubeRole, clusterID string) bool {
+	others, err := sqlstore.ListRoleBindings(&m.RoleBinding{SubjectKind: binding.SubjectKind, Subject: binding.Subject})
+	if err != nil {
+		blog.Errorf("error when list local rolebindings of %s %s: %s", binding.SubjectKind, binding.Subject, err.Error())
+		return false
+	}
+
+	for _, other := range others {
+		if other.Name == binding.Name || localBindingNamespace(other.Namespace) != localBindingNamespace(binding.Namespace) {
+			continue
+		}
+		if other.ClusterID != m.PolicyMatchAll && localBindingClusterID(other.ClusterID) != clusterID {
+			continue
+		}
+		if role := sqlstore.GetRole(other.RoleName); role != nil && role.KubeRole == kubeRole {
+			return true
+		}
+	}
+	return false
+}
+
+func localBindingClusterID(clusterID string) string {
+	cluster := sqlstore.GetClusterByFuzzyClusterId(clusterID)
+	if cluster == nil {
+		return ""
+	}
+	return cluster.ID
+}
+
+// localBindingNamespace returns empty namespace for bindings of all namespaces
+func localBindingNamespace(namespace string) string {
+	if namespace == m.PolicyMatchAll {
+		return ""
+	}
+	return namespace
+}
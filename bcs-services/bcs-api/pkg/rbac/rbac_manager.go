@@ -35,20 +35,52 @@ const (
 	clusterRoleBindingTypeFromAny       = "from-any-cluster"
 	clusterRoleBindingTypeFromCommon    = "from-common"
 	clusterRoleBindingTypeFromNamespace = "from-any-namespaces"
+	clusterRoleBindingTypeFromLocal     = "from-local-policy"
 
 	roleBindingLabelUser = "rolebinding.user.bke.bcs"
+
+	clusterRoleBindingLabelGroup = "clusterrolebinding.group.bke.bcs"
+	roleBindingLabelGroup        = "rolebinding.group.bke.bcs"
 )
 
 type rbacManager struct {
 	clusterId  string
 	kubeClient *kubernetes.Clientset
+	// subjectKind is the kind of subjects in bindings managed, User or Group
+	subjectKind string
 }
 
 func newRbacManager(cluster string, client *kubernetes.Clientset) *rbacManager {
 	return &rbacManager{
-		clusterId:  cluster,
-		kubeClient: client,
+		clusterId:   cluster,
+		kubeClient:  client,
+		subjectKind: rbacv1.UserKind,
+	}
+}
+
+// newGroupRbacManager returns rbacManager managing bindings of groups, username in methods is the group name
+func newGroupRbacManager(cluster string, client *kubernetes.Clientset) *rbacManager {
+	return &rbacManager{
+		clusterId:   cluster,
+		kubeClient:  client,
+		subjectKind: rbacv1.GroupKind,
+	}
+}
+
+// clusterRoleBindingLabel returns the label key of subject in clusterrolebindings
+func (rm *rbacManager) clusterRoleBindingLabel() string {
+	if rm.subjectKind == rbacv1.GroupKind {
+		return clusterRoleBindingLabelGroup
+	}
+	return clusterRoleBindingLabelUser
+}
+
+// roleBindingLabel returns the label key of subject in rolebindings
+func (rm *rbacManager) roleBindingLabel() string {
+	if rm.subjectKind == rbacv1.GroupKind {
+		return roleBindingLabelGroup
 	}
+	return roleBindingLabelUser
 }
 
 // ensureRoles确保待sync的clusterrole在集群中已经存在，如果不存在，则创建
@@ -153,7 +185,7 @@ func (rm *rbacManager) updateClusterRole(clusterRole *rbacv1.ClusterRole) error
 // ensureAddClusterRoleBinding 确保创建 clusterrolebinding
 func (rm *rbacManager) ensureAddClusterRoleBinding(username, role, bindingType string) error {
 	// 获取该用户已创建的bindings
-	label := map[string]string{clusterRoleBindingLabelUser: username, clusterRoleBindingLabelFrom: bindingType}
+	label := map[string]string{rm.clusterRoleBindingLabel(): username, clusterRoleBindingLabelFrom: bindingType}
 	alreadyClusterRoleBindings, err := rm.kubeClient.RbacV1().ClusterRoleBindings().List(metav1.ListOptions{LabelSelector: labels.Set(label).AsSelector().String()})
 	if err != nil {
 		return fmt.Errorf("error when list clusterrolebindings from cluster %s for user %s: %s", rm.clusterId, username, err.Error())
@@ -177,7 +209,7 @@ func (rm *rbacManager) ensureAddClusterRoleBinding(username, role, bindingType s
 // ensureDeleteClusterRoleBinding 确保删除 clusterrolebinding
 func (rm *rbacManager) ensureDeleteClusterRoleBinding(username, role, bindingType string) error {
 	// 获取该用户已创建的bindings
-	label := map[string]string{clusterRoleBindingLabelUser: username, clusterRoleBindingLabelFrom: bindingType}
+	label := map[string]string{rm.clusterRoleBindingLabel(): username, clusterRoleBindingLabelFrom: bindingType}
 	alreadyClusterRoleBindings, err := rm.kubeClient.RbacV1().ClusterRoleBindings().List(metav1.ListOptions{LabelSelector: labels.Set(label).AsSelector().String()})
 	if err != nil {
 		return fmt.Errorf("error when list clusterrolebindings from cluster %s for user %s: %s", rm.clusterId, username, err.Error())
@@ -195,14 +227,14 @@ func (rm *rbacManager) ensureDeleteClusterRoleBinding(username, role, bindingTyp
 // createClusterRoleBinding 调用 k8s api 创建 clusterrolebinding
 func (rm *rbacManager) createClusterRoleBinding(username, clusterRole, bindingType string) error {
 	// 给每个用户创建的clusterrolebinding都打上一个特有的label
-	label := map[string]string{clusterRoleBindingLabelUser: username, clusterRoleBindingLabelFrom: bindingType}
+	label := map[string]string{rm.clusterRoleBindingLabel(): username, clusterRoleBindingLabelFrom: bindingType}
 	objectMeta := metav1.ObjectMeta{
 		GenerateName: "clusterrolebinding-",
 		Labels:       label,
 	}
 
 	subject := rbacv1.Subject{
-		Kind: "User",
+		Kind: rm.subjectKind,
 		Name: username,
 	}
 
@@ -229,7 +261,7 @@ func (rm *rbacManager) deleteClusterRoleBinding(clusterRoleBindingName string) e
 // ensureAddRoleBinding 确保创建 rolebinding
 func (rm *rbacManager) ensureAddRoleBinding(username, role, namespace string) error {
 	// 获取该用户已创建的bindings
-	label := map[string]string{roleBindingLabelUser: username}
+	label := map[string]string{rm.roleBindingLabel(): username}
 	alreadyRoleBindings, err := rm.kubeClient.RbacV1().RoleBindings(namespace).List(metav1.ListOptions{LabelSelector: labels.Set(label).AsSelector().String()})
 	if err != nil {
 		return fmt.Errorf("error when list rolebindings from cluster %s namespace %s for user %s: %s", rm.clusterId, namespace, username, err.Error())
@@ -253,7 +285,7 @@ func (rm *rbacManager) ensureAddRoleBinding(username, role, namespace string) er
 // ensureDeleteRoleBinding 确保删除 rolebinding
 func (rm *rbacManager) ensureDeleteRoleBinding(username, role, namespace string) error {
 	// 获取该用户已创建的bindings
-	label := map[string]string{roleBindingLabelUser: username}
+	label := map[string]string{rm.roleBindingLabel(): username}
 	alreadyRoleBindings, err := rm.kubeClient.RbacV1().RoleBindings(namespace).List(metav1.ListOptions{LabelSelector: labels.Set(label).AsSelector().String()})
 	if err != nil {
 		return fmt.Errorf("error when list rolebindings from cluster %s namespace %s for user %s: %s", rm.clusterId, namespace, username, err.Error())
@@ -271,7 +303,7 @@ func (rm *rbacManager) ensureDeleteRoleBinding(username, role, namespace string)
 // createRoleBinding 调用 k8s api 创建 rolebinding
 func (rm *rbacManager) createRoleBinding(username, clusterRole, namespace string) error {
 	// 给每个用户创建的rolebinding都打上一个特有的label
-	label := map[string]string{roleBindingLabelUser: username}
+	label := map[string]string{rm.roleBindingLabel(): username}
 	objectMeta := metav1.ObjectMeta{
 		GenerateName: "rolebinding-",
 		Namespace:    namespace,
@@ -279,7 +311,7 @@ func (rm *rbacManager) createRoleBinding(username, clusterRole, namespace string
 	}
 
 	subject := rbacv1.Subject{
-		Kind: "User",
+		Kind: rm.subjectKind,
 		Name: username,
 	}
 
@@ -20,13 +20,16 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-services/bcs-api/pkg/audit"
 	"bk-bcs/bcs-services/bcs-api/pkg/auth"
 	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/ratelimit"
 	"bk-bcs/bcs-services/bcs-api/pkg/server/credentials"
 	resthdrs_utils "bk-bcs/bcs-services/bcs-api/pkg/server/resthdrs/utils"
 	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
@@ -101,6 +104,7 @@ func (f *ReverseProxyDispatcher) ServeHTTP(rw http.ResponseWriter, req *http.Req
 		return
 	}
 	clusterId := cluster.ID
+	audit.SetCluster(req.Context(), clusterId)
 	externalClusterInfo := sqlstore.QueryBCSClusterInfo(&m.BCSClusterInfo{
 		ClusterId: clusterId,
 	})
@@ -119,16 +123,60 @@ func (f *ReverseProxyDispatcher) ServeHTTP(rw http.ResponseWriter, req *http.Req
 
 	user, hasExpired := authenticater.GetUser()
 	if user == nil {
-		status := utils.NewUnauthorized("anonymous requests is forbidden")
+		// Try external authenticators such as oidc and static token
+		externalUser, ok, err := auth.GExternalAuthenticator.AuthenticateRequest(req)
+		if err != nil {
+			status := utils.NewUnauthorized(err.Error())
+			utils.WriteKubeAPIError(rw, status)
+			return
+		}
+		if !ok {
+			status := utils.NewUnauthorized("anonymous requests is forbidden")
+			utils.WriteKubeAPIError(rw, status)
+			return
+		}
+		user = externalUser
+		audit.SetUser(req.Context(), user.Name, user.BackendType)
+	} else if hasExpired {
+		reason := fmt.Sprintf("this token has expired for user: %s", user.Name)
+		status := utils.NewUnauthorized(reason)
 		utils.WriteKubeAPIError(rw, status)
 		return
+	} else {
+		audit.SetUser(req.Context(), user.Name, m.UserTokenTypeName(authenticater.GetUserTokenType()))
+
+		// Scoped token can only access the clusters and namespaces in its scopes, reading the api discovery paths
+		// only needs the cluster scope. Other non-resource paths such as /logs and /metrics are cluster level.
+		namespace, discovery := KubeRequestNamespace(vars[f.SubPathVarName])
+		readDiscovery := discovery && (req.Method == http.MethodGet || req.Method == http.MethodHead)
+		permitted := authenticater.PermitsCluster(clusterId)
+		if permitted && !readDiscovery {
+			permitted = authenticater.Permits(clusterId, namespace)
+		}
+		if !permitted {
+			err := fmt.Errorf("token of user %s is not permitted to %s namespace %q of cluster %s", user.Name,
+				strings.ToLower(req.Method), namespace, clusterId)
+			status := utils.NewForbidden(utils.ClusterResource, clusterIdentifier, err)
+			utils.WriteKubeAPIError(rw, status)
+			return
+		}
 	}
-	if hasExpired {
-		reason := fmt.Sprintf("this token has expired for user: %s", user.Name)
-		status := utils.NewUnauthorized(reason)
+
+	// Limit the requests by user, token and cluster before they are forwarded
+	release, retryAfter, limitedBy, allowed := ratelimit.Allow(ratelimit.Request{
+		User:      user.Name,
+		Token:     ratelimit.TokenOf(req.Header),
+		ClusterID: externalClusterInfo.SourceClusterId,
+		Class:     ratelimit.ClassOf(req.Method, IsLongRunningKubeRequest(req, vars[f.SubPathVarName])),
+	})
+	if !allowed {
+		seconds := ratelimit.RetryAfterSeconds(retryAfter)
+		status := utils.NewTooManyRequests(ratelimit.RejectedMessage(retryAfter, limitedBy), seconds)
+		rw.Header().Set("Retry-After", strconv.Itoa(seconds))
 		utils.WriteKubeAPIError(rw, status)
 		return
 	}
+	defer release()
 
 	// Delete the original auth header so that the original user token won't be passed to the rev-proxy request and
 	// damage the real cluster authentication process.
@@ -139,7 +187,7 @@ func (f *ReverseProxyDispatcher) ServeHTTP(rw http.ResponseWriter, req *http.Req
 	turnOnRbac := config.TurnOnRBAC
 	if turnOnRbac {
 		if !user.IsSuperUser {
-			req.Header.Set("Impersonate-User", strings.Replace(user.Name, ":", ".", 1))
+			ImpersonateUser(req.Header, user)
 		}
 	}
 
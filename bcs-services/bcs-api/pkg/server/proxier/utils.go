@@ -17,6 +17,7 @@ import (
 	"context"
 	"crypto/tls"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
@@ -25,9 +26,22 @@ import (
 
 	"bk-bcs/bcs-common/common/blog"
 	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/rbac"
 	"fmt"
 )
 
+// ImpersonateUser sets impersonation headers of user for the request forwarded to k8s cluster. Groups from external
+// authenticators are passed too so that rolebindings of groups take effect, while groups given by the client are
+// dropped, user can not impersonate any group by itself.
+func ImpersonateUser(header http.Header, user *m.User) {
+	// Because k8s rbac doesn't allow label to contain ":", so replaced by "."
+	header.Set("Impersonate-User", strings.Replace(user.Name, ":", ".", 1))
+	header.Del("Impersonate-Group")
+	for _, group := range user.Groups {
+		header.Add("Impersonate-Group", rbac.KubeGroupName(group))
+	}
+}
+
 func ExtractIpAddress(serverAddress string) (*url.URL, error) {
 	if !strings.HasSuffix(serverAddress, "/") {
 		serverAddress = serverAddress + "/"
@@ -67,6 +81,84 @@ func TurnCredentialsIntoConfig(clusterCredentials *m.ClusterCredentials) (*restc
 	}, nil
 }
 
+// kubeResourceSegments returns the segments of path to kube-apiserver after /api/{version} or /apis/{group}/{version},
+// such as [namespaces ns1 pods web-0 exec]. It is empty for requests without resources, such as /api, /apis/apps/v1
+// and /version.
+func kubeResourceSegments(subPath string) []string {
+	segments := strings.Split(strings.Trim(subPath, "/"), "/")
+	var index int
+	switch segments[0] {
+	case "api":
+		index = 2
+	case "apis":
+		index = 3
+	default:
+		return nil
+	}
+	if len(segments) <= index {
+		return nil
+	}
+	return segments[index:]
+}
+
+// KubeRequestNamespace returns the namespace of request to kube-apiserver by its path, empty namespace means cluster
+// level resources or non-resource paths. discovery is true only for the api discovery paths: /api, /api/{version},
+// /apis, /apis/{group}, /apis/{group}/{version} and /version. Other non-resource paths such as /logs, /metrics,
+// /debug/pprof and /openapi are not discovery.
+func KubeRequestNamespace(subPath string) (namespace string, discovery bool) {
+	resources := kubeResourceSegments(subPath)
+	if len(resources) > 1 && resources[0] == "namespaces" {
+		return resources[1], false
+	}
+	return "", isKubeDiscoveryPath(subPath)
+}
+
+func isKubeDiscoveryPath(subPath string) bool {
+	segments := strings.Split(strings.Trim(subPath, "/"), "/")
+	switch segments[0] {
+	case "version":
+		return len(segments) == 1
+	case "api":
+		return len(segments) <= 2
+	case "apis":
+		return len(segments) <= 3
+	}
+	return false
+}
+
+// longRunningSubresources are subresources of kube-apiserver which keep the connection open
+var longRunningSubresources = map[string]bool{
+	"exec":        true,
+	"attach":      true,
+	"portforward": true,
+	"proxy":       true,
+}
+
+// IsLongRunningKubeRequest returns true for requests to kube-apiserver which keep the connection open, such as
+// watch, exec and following logs.
+func IsLongRunningKubeRequest(req *http.Request, subPath string) bool {
+	if strings.Contains(strings.ToLower(req.Header.Get("Connection")), "upgrade") {
+		return true
+	}
+	query := req.URL.Query()
+	if watch := query.Get("watch"); watch == "true" || watch == "1" || query.Get("follow") == "true" {
+		return true
+	}
+
+	resources := kubeResourceSegments(subPath)
+	if len(resources) == 0 {
+		return false
+	}
+	if resources[0] == "watch" {
+		return true
+	}
+	if len(resources) > 2 && resources[0] == "namespaces" {
+		resources = resources[2:]
+	}
+	// {resource}/{name}/{subresource}
+	return len(resources) > 2 && longRunningSubresources[resources[2]]
+}
+
 // check tcp connection to addr
 func CheckTcpConn(addr string) error {
 	checkUrl, err := url.Parse(addr)
@@ -0,0 +1,102 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package proxier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+)
+
+func TestKubeRequestNamespace(t *testing.T) {
+	tests := []struct {
+		subPath   string
+		namespace string
+		discovery bool
+	}{
+		{"api", "", true},
+		{"api/v1", "", true},
+		{"apis/apps/v1/", "", true},
+		{"apis/apps", "", true},
+		{"version", "", true},
+		{"openapi/v2", "", false},
+		{"logs/kube-apiserver.log", "", false},
+		{"metrics", "", false},
+		{"debug/pprof/profile", "", false},
+		{"api/v1/nodes", "", false},
+		{"api/v1/namespaces", "", false},
+		{"api/v1/namespaces/ci/pods/web-0/log", "ci", false},
+		{"apis/apps/v1/namespaces/test/deployments", "test", false},
+	}
+	for _, test := range tests {
+		namespace, discovery := KubeRequestNamespace(test.subPath)
+		if namespace != test.namespace || discovery != test.discovery {
+			t.Errorf("%s: expect (%q, %t), got (%q, %t)", test.subPath, test.namespace, test.discovery, namespace, discovery)
+		}
+	}
+}
+
+func TestIsLongRunningKubeRequest(t *testing.T) {
+	tests := []struct {
+		url         string
+		upgrade     bool
+		longRunning bool
+	}{
+		{"/api/v1/namespaces/ci/pods", false, false},
+		{"/api/v1/namespaces/ci/pods?watch=true", false, true},
+		{"/api/v1/watch/namespaces/ci/pods", false, true},
+		{"/apis/apps/v1/watch/deployments", false, true},
+		{"/api/v1/namespaces/ci/pods/web-0/log", false, false},
+		{"/api/v1/namespaces/ci/pods/web-0/log?follow=true", false, true},
+		{"/api/v1/namespaces/ci/pods/web-0/exec?command=sh", true, true},
+		{"/api/v1/namespaces/ci/pods/web-0/portforward", false, true},
+		{"/api/v1/namespaces/watch/pods", false, false},
+		{"/api/v1/namespaces/ci/configmaps/exec", false, false},
+		{"/api/v1/nodes/node-1/proxy/metrics", false, true},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.url, nil)
+		if test.upgrade {
+			req.Header.Set("Connection", "Upgrade")
+		}
+		if longRunning := IsLongRunningKubeRequest(req, req.URL.Path); longRunning != test.longRunning {
+			t.Errorf("%s: expect long running %t, got %t", test.url, test.longRunning, longRunning)
+		}
+	}
+}
+
+func TestImpersonateUser(t *testing.T) {
+	tests := []struct {
+		user   *m.User
+		groups []string
+	}{
+		{&m.User{Name: "oidc:alice"}, nil},
+		{&m.User{Name: "token:ci-bot", Groups: []string{"sso:dev", "ops"}}, []string{"sso.dev", "ops"}},
+	}
+	for _, test := range tests {
+		header := http.Header{}
+		// groups given by client must be dropped
+		header.Add("Impersonate-Group", "system:masters")
+		ImpersonateUser(header, test.user)
+		if got := header.Get("Impersonate-User"); got == "" || got == test.user.Name {
+			t.Errorf("user %s: unexpected impersonated user %q", test.user.Name, got)
+		}
+		if got := header["Impersonate-Group"]; !reflect.DeepEqual(got, test.groups) {
+			t.Errorf("user %s: expect impersonated groups %v, got %v", test.user.Name, test.groups, got)
+		}
+	}
+}
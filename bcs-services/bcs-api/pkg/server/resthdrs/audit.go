@@ -0,0 +1,89 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resthdrs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"bk-bcs/bcs-common/common"
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-services/bcs-api/pkg/audit"
+	"github.com/emicklei/go-restful"
+)
+
+// QueryAuditRecords query audit records by user, cluster and time range.
+// begin_time and end_time are unix timestamps in seconds, end_time is excluded
+func QueryAuditRecords(request *restful.Request, response *restful.Response) {
+	if !audit.Enabled() {
+		message := fmt.Sprintf("errcode: %d, audit is not enabled", common.BcsErrApiBadRequest)
+		WriteClientError(response, "AUDIT_NOT_ENABLED", message)
+		return
+	}
+
+	filter := &audit.Filter{
+		User:      request.QueryParameter("user"),
+		ClusterID: request.QueryParameter("cluster_id"),
+	}
+	var err error
+	if filter.Begin, err = parseUnixTime(request.QueryParameter("begin_time")); err != nil {
+		message := fmt.Sprintf("errcode: %d, invalid begin_time: %s", common.BcsErrApiBadRequest, err.Error())
+		WriteClientError(response, "INVALID_PARAMETER", message)
+		return
+	}
+	if filter.End, err = parseUnixTime(request.QueryParameter("end_time")); err != nil {
+		message := fmt.Sprintf("errcode: %d, invalid end_time: %s", common.BcsErrApiBadRequest, err.Error())
+		WriteClientError(response, "INVALID_PARAMETER", message)
+		return
+	}
+	if filter.Offset, err = parseIntParameter(request.QueryParameter("offset")); err != nil {
+		message := fmt.Sprintf("errcode: %d, invalid offset: %s", common.BcsErrApiBadRequest, err.Error())
+		WriteClientError(response, "INVALID_PARAMETER", message)
+		return
+	}
+	if filter.Limit, err = parseIntParameter(request.QueryParameter("limit")); err != nil {
+		message := fmt.Sprintf("errcode: %d, invalid limit: %s", common.BcsErrApiBadRequest, err.Error())
+		WriteClientError(response, "INVALID_PARAMETER", message)
+		return
+	}
+
+	records, err := audit.Query(filter)
+	if err != nil {
+		blog.Errorf("query audit records failed: %v", err)
+		message := fmt.Sprintf("errcode: %d, query audit records failed: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "QUERY_AUDIT_FAILED", message)
+		return
+	}
+
+	response.WriteEntity(records)
+}
+
+func parseUnixTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	sec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, 0), nil
+}
+
+func parseIntParameter(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,104 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resthdrs
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"bk-bcs/bcs-common/common"
+	"bk-bcs/bcs-services/bcs-api/pkg/federation"
+	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
+	"github.com/emicklei/go-restful"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ListFederationClusters lists clusters with their projects and labels, which can be selected by federated requests.
+// Query parameters cluster_ids, project_id and selector have the same meaning as the headers of federated requests.
+func ListFederationClusters(request *restful.Request, response *restful.Response) {
+	header := make(http.Header)
+	header.Set(federation.ClusterIDsHeaderKey, request.QueryParameter("cluster_ids"))
+	header.Set(federation.ProjectIDHeaderKey, request.QueryParameter("project_id"))
+	header.Set(federation.ClusterSelectorHeaderKey, request.QueryParameter("selector"))
+
+	clusters, err := federation.ListClusters()
+	if err != nil {
+		message := fmt.Sprintf("errcode: %d, can not list clusters, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_LIST_CLUSTERS", message)
+		return
+	}
+
+	selector, err := federation.ParseSelector(header)
+	if err != nil {
+		// list all clusters if no selector is given
+		if header.Get(federation.ClusterSelectorHeaderKey) != "" {
+			message := fmt.Sprintf("errcode: %d, %s", common.BcsErrApiBadRequest, err.Error())
+			WriteClientError(response, "INVALID_SELECTOR", message)
+			return
+		}
+		response.WriteEntity(clusters)
+		return
+	}
+
+	selected := make(map[string]bool)
+	for _, clusterID := range selector.Select(clusters) {
+		selected[clusterID] = true
+	}
+	result := make([]federation.Cluster, 0, len(selected))
+	for _, cluster := range clusters {
+		if selected[cluster.ID] {
+			result = append(result, cluster)
+			delete(selected, cluster.ID)
+		}
+	}
+	// clusters given explicitly but not registered or labeled
+	for _, clusterID := range selector.ClusterIDs {
+		if selected[clusterID] {
+			result = append(result, federation.Cluster{ID: clusterID, Labels: map[string]string{}})
+		}
+	}
+	response.WriteEntity(result)
+}
+
+// UpdateFederationClusterLabels replaces all the labels of bcs cluster, empty labels remove the cluster from
+// federation unless it is registered in bcs-api.
+func UpdateFederationClusterLabels(request *restful.Request, response *restful.Response) {
+	clusterID := request.PathParameter("cluster_id")
+	labels := make(map[string]string)
+	if err := request.ReadEntity(&labels); err != nil {
+		message := fmt.Sprintf("errcode: %d, labels must be a map of string, error: %s", common.BcsErrApiBadRequest, err.Error())
+		WriteClientError(response, "INVALID_LABELS", message)
+		return
+	}
+
+	for key, value := range labels {
+		errs := append(validation.IsQualifiedName(key), validation.IsValidLabelValue(value)...)
+		if len(key) > validation.LabelValueMaxLength {
+			errs = append(errs, validation.MaxLenError(validation.LabelValueMaxLength))
+		}
+		if len(errs) > 0 {
+			message := fmt.Sprintf("errcode: %d, invalid label %s=%s: %s", common.BcsErrApiBadRequest, key, value, strings.Join(errs, "; "))
+			WriteClientError(response, "INVALID_LABELS", message)
+			return
+		}
+	}
+
+	if err := sqlstore.ReplaceClusterLabels(clusterID, labels); err != nil {
+		message := fmt.Sprintf("errcode: %d, can not update labels of cluster %s, error: %s", common.BcsErrApiInternalDbError, clusterID, err.Error())
+		WriteServerError(response, "CANNOT_UPDATE_LABELS", message)
+		return
+	}
+	response.WriteEntity(federation.Cluster{ID: clusterID, Labels: labels})
+}
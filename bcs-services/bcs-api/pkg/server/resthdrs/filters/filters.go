@@ -18,6 +18,7 @@ import (
 
 	"bk-bcs/bcs-common/common"
 	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-services/bcs-api/pkg/audit"
 	"bk-bcs/bcs-services/bcs-api/pkg/auth"
 	m "bk-bcs/bcs-services/bcs-api/pkg/models"
 	"bk-bcs/bcs-services/bcs-api/pkg/server/resthdrs/utils"
@@ -62,10 +63,17 @@ func TokenAuthenticate(request *restful.Request, response *restful.Response, cha
 	})
 	user, hasExpired := authenticater.GetUser()
 	if user != nil && !hasExpired {
+		userTokenType := authenticater.GetUserTokenType()
+		// Scoped token is issued to access clusters only, it can not be used to manage tokens or credentials
+		if userTokenType == m.UserTokenTypeScoped {
+			message := fmt.Sprintf("errcode: %d, scoped token can only be used to access clusters", common.BcsErrApiUnauthorized)
+			utils.WriteForbiddenError(response, "SCOPED_TOKEN_FORBIDDEN", message)
+			return
+		}
 		user.BackendType = types.UserBackendTypeDefault
 		request.SetAttribute(CurrentUserAttr, user)
-		userTokenType := authenticater.GetUserTokenType()
 		request.SetAttribute(CurrentUserTokenType, int(userTokenType))
+		audit.SetUser(request.Request.Context(), user.Name, m.UserTokenTypeName(userTokenType))
 	}
 	// Set current user to request for later procedure
 	chain.ProcessFilter(request, response)
@@ -76,7 +84,8 @@ func SuperTokenAuthenticate(request *restful.Request, response *restful.Response
 
 	authenticater := auth.NewTokenAuthenticater(request.Request, auth.DefaultTokenAuthConfig)
 	user, hasExpired := authenticater.GetUser()
-	if user != nil && !hasExpired && user.IsSuperUser {
+	if user != nil && !hasExpired && user.IsSuperUser && authenticater.GetUserTokenType() != m.UserTokenTypeScoped {
+		audit.SetUser(request.Request.Context(), user.Name, m.UserTokenTypeName(m.UserTokenTypeSession))
 		chain.ProcessFilter(request, response)
 		return
 	}
@@ -87,6 +96,31 @@ func SuperTokenAuthenticate(request *restful.Request, response *restful.Response
 
 }
 
+// ExternalAuthenticate authenticates current user by external authenticators such as oidc and static token
+func ExternalAuthenticate(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+	// If there is already "currentUser" attribute in request object, skip this authenticater
+	if request.Attribute(CurrentUserAttr) != nil {
+		chain.ProcessFilter(request, response)
+		return
+	}
+
+	user, ok, err := auth.GExternalAuthenticator.AuthenticateRequest(request.Request)
+	if err != nil {
+		blog.Warnf("Failed to authenticate user by external authenticators: %s", err.Error())
+		message := fmt.Sprintf("errcode：%d,  %s", common.BcsErrApiUnauthorized, err.Error())
+		utils.WriteUnauthorizedError(response, "UNAUTHORIZED", message)
+		return
+	}
+	if ok {
+		request.SetAttribute(CurrentUserAttr, user)
+		// external users access clusters through tunnels with their own credentials, which carry their groups.
+		// Kubeconfig tokens issued to them by credentials api do not carry groups.
+		request.SetAttribute(CurrentUserTokenType, m.UserTokenTypeKubeConfigForPaas)
+		audit.SetUser(request.Request.Context(), user.Name, user.BackendType)
+	}
+	chain.ProcessFilter(request, response)
+}
+
 // accessTokenAuthenticate authenticates the user using access_token parameter in query string.
 func AccessTokenAuthenticate(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
 	// If there is already "currentUser" attribute in request object, skip this authenticater
@@ -110,6 +144,7 @@ func AccessTokenAuthenticate(request *restful.Request, response *restful.Respons
 			// Set current user to request for later procedure
 			request.SetAttribute(CurrentUserAttr, user)
 			request.SetAttribute(CurrentUserTokenType, m.UserTokenTypeKubeConfigForPaas)
+			audit.SetUser(request.Request.Context(), user.Name, "access_token")
 		}
 	}
 
@@ -131,6 +166,7 @@ func ClusterIdVerify(request *restful.Request, response *restful.Response, chain
 	}
 
 	request.SetAttribute(CurrentCluster, cluster)
+	audit.SetCluster(request.Request.Context(), cluster.ID)
 	chain.ProcessFilter(request, response)
 
 }
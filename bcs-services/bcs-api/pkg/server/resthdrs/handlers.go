@@ -22,6 +22,7 @@ import (
 func AddAuthF(rb *restful.RouteBuilder) *restful.RouteBuilder {
 	rb.Filter(filters.AccessTokenAuthenticate).
 		Filter(filters.TokenAuthenticate).
+		Filter(filters.ExternalAuthenticate).
 		Filter(filters.AuthenticatedRequired)
 	return rb
 }
@@ -60,14 +61,42 @@ func CreateRestContainer(pathPrefix string) *restful.Container {
 	ws.Route(AddAuthClusterF(ws.GET("/clusters/{cluster_id}/credentials")).To(GetCredentials))
 	ws.Route(AddAuthClusterF(ws.GET("/clusters/{cluster_id}/register_tokens")).To(ListRegisterTokens))
 	ws.Route(AddAuthClusterF(ws.POST("/clusters/{cluster_id}/register_tokens")).To(CreateRegisterToken))
+	ws.Route(AddAuthClusterF(ws.GET("/clusters/{cluster_id}/kubeconfig")).To(GetKubeConfig))
 
 	// Handlers for bcs-services
 	ws.Route(ws.GET("/clusters/bcs/query_by_cluster_id/").To(QueryBCSClusterByClusterID))
 
+	// Federation
+	ws.Route(AddAuthF(ws.GET("/federation/clusters")).To(ListFederationClusters))
+	ws.Route(AddSuperUserAuthF(ws.PUT("/federation/clusters/{cluster_id}/labels")).To(UpdateFederationClusterLabels))
+
 	// TODO: Add user management endpoints for admin user
 	ws.Route(AddSuperUserAuthF(ws.POST("/users/")).To(CreateUser))
 	ws.Route(AddSuperUserAuthF(ws.GET("/users/{user_name}")).To(QueryBCSUserByName))
 	ws.Route(AddSuperUserAuthF(ws.POST("/users/{user_id}/tokens")).To(CreateUserToken))
+
+	// Self-service token management, scoped tokens can not be used for them
+	ws.Route(AddAuthF(ws.GET("/tokens")).To(ListTokens))
+	ws.Route(AddAuthF(ws.POST("/tokens")).To(CreateToken))
+	ws.Route(AddAuthF(ws.DELETE("/tokens/{token_id}")).To(RevokeToken))
+	ws.Route(AddAuthF(ws.POST("/tokens/{token_id}/rotate")).To(RotateToken))
+
+	// Rate limit rules, they can be changed at runtime by super user
+	ws.Route(AddSuperUserAuthF(ws.GET("/ratelimit/rules")).To(GetRateLimitRules))
+	ws.Route(AddSuperUserAuthF(ws.PUT("/ratelimit/rules")).To(UpdateRateLimitRules))
+
+	// Audit records, only super user can query them
+	ws.Route(AddSuperUserAuthF(ws.GET("/audits")).To(QueryAuditRecords))
+
+	// Roles and rolebindings of local policy engine, only super user can manage them
+	ws.Route(AddSuperUserAuthF(ws.GET("/policy/roles")).To(ListRoles))
+	ws.Route(AddSuperUserAuthF(ws.POST("/policy/roles")).To(CreateRole))
+	ws.Route(AddSuperUserAuthF(ws.GET("/policy/roles/{role_name}")).To(QueryRoleByName))
+	ws.Route(AddSuperUserAuthF(ws.PUT("/policy/roles/{role_name}")).To(UpdateRole))
+	ws.Route(AddSuperUserAuthF(ws.DELETE("/policy/roles/{role_name}")).To(DeleteRole))
+	ws.Route(AddSuperUserAuthF(ws.GET("/policy/rolebindings")).To(ListRoleBindings))
+	ws.Route(AddSuperUserAuthF(ws.POST("/policy/rolebindings")).To(CreateRoleBinding))
+	ws.Route(AddSuperUserAuthF(ws.DELETE("/policy/rolebindings/{binding_name}")).To(DeleteRoleBinding))
 	// ws.Route(ws.POST("/account/tokens").To(ListAccountTokens))
 
 	container := restful.NewContainer()
@@ -0,0 +1,267 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resthdrs
+
+import (
+	"fmt"
+
+	"bk-bcs/bcs-common/common"
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-services/bcs-api/config"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/rbac"
+	"bk-bcs/bcs-services/bcs-api/pkg/rbac/template"
+	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
+	"github.com/emicklei/go-restful"
+)
+
+type RoleForm struct {
+	Name        string         `json:"name" validate:"required"`
+	Description string         `json:"description"`
+	Rules       []m.PolicyRule `json:"rules" validate:"required,dive"`
+	KubeRole    string         `json:"kube_role"`
+}
+
+type RoleBindingForm struct {
+	Name        string `json:"name" validate:"required"`
+	RoleName    string `json:"role_name" validate:"required"`
+	SubjectKind string `json:"subject_kind" validate:"required,oneof=user group"`
+	Subject     string `json:"subject" validate:"required"`
+	ClusterID   string `json:"cluster_id" validate:"required"`
+	Namespace   string `json:"namespace"`
+}
+
+// ListRoles lists all roles of local policy engine
+func ListRoles(request *restful.Request, response *restful.Response) {
+	roles, err := sqlstore.ListRoles()
+	if err != nil {
+		message := fmt.Sprintf("errcode: %d, can not list roles, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_LIST_ROLES", message)
+		return
+	}
+	response.WriteEntity(roles)
+}
+
+func QueryRoleByName(request *restful.Request, response *restful.Response) {
+	roleName := request.PathParameter("role_name")
+	role := sqlstore.GetRole(roleName)
+	if role == nil {
+		message := fmt.Sprintf("errcode: %d, role with name=%s not found", common.BcsErrApiBadRequest, roleName)
+		WriteNotFoundError(response, "ROLE_NOT_FOUND", message)
+		return
+	}
+	response.WriteEntity(*role)
+}
+
+func CreateRole(request *restful.Request, response *restful.Response) {
+	form := RoleForm{}
+	request.ReadEntity(&form)
+	if !validateRoleForm(&form, response) {
+		return
+	}
+
+	if sqlstore.GetRole(form.Name) != nil {
+		message := fmt.Sprintf("errcode: %d, create failed, role with this name already exists", common.BcsErrApiBadRequest)
+		WriteClientError(response, "ROLE_ALREADY_EXISTS", message)
+		return
+	}
+
+	role := &m.Role{
+		Name:        form.Name,
+		Description: form.Description,
+		Rules:       form.Rules,
+		KubeRole:    form.KubeRole,
+	}
+	if err := sqlstore.SaveRole(role); err != nil {
+		message := fmt.Sprintf("errcode: %d, can not create role, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_CREATE_ROLE", message)
+		return
+	}
+	response.WriteEntity(*role)
+}
+
+// UpdateRole updates the rules and kube role of role, the rolebindings of it are synced to k8s clusters again
+// if kube role is changed
+func UpdateRole(request *restful.Request, response *restful.Response) {
+	form := RoleForm{}
+	request.ReadEntity(&form)
+	form.Name = request.PathParameter("role_name")
+	if !validateRoleForm(&form, response) {
+		return
+	}
+
+	role := sqlstore.GetRole(form.Name)
+	if role == nil {
+		message := fmt.Sprintf("errcode: %d, role with name=%s not found", common.BcsErrApiBadRequest, form.Name)
+		WriteNotFoundError(response, "ROLE_NOT_FOUND", message)
+		return
+	}
+
+	oldKubeRole := role.KubeRole
+	role.Description = form.Description
+	role.Rules = form.Rules
+	role.KubeRole = form.KubeRole
+	if err := sqlstore.SaveRole(role); err != nil {
+		message := fmt.Sprintf("errcode: %d, can not update role, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_UPDATE_ROLE", message)
+		return
+	}
+
+	if oldKubeRole != role.KubeRole && config.TurnOnRBAC {
+		bindings, err := sqlstore.ListRoleBindings(&m.RoleBinding{RoleName: role.Name})
+		if err != nil {
+			blog.Errorf("list rolebindings of role %s failed: %v", role.Name, err)
+		}
+		go func() {
+			for i := range bindings {
+				syncLocalRoleBinding(&bindings[i], oldKubeRole, rbac.OperationDelete)
+				syncLocalRoleBinding(&bindings[i], role.KubeRole, rbac.OperationAdd)
+			}
+		}()
+	}
+	response.WriteEntity(*role)
+}
+
+// DeleteRole deletes the role which is not bound to anyone
+func DeleteRole(request *restful.Request, response *restful.Response) {
+	roleName := request.PathParameter("role_name")
+	if sqlstore.GetRole(roleName) == nil {
+		message := fmt.Sprintf("errcode: %d, role with name=%s not found", common.BcsErrApiBadRequest, roleName)
+		WriteNotFoundError(response, "ROLE_NOT_FOUND", message)
+		return
+	}
+
+	bindings, err := sqlstore.ListRoleBindings(&m.RoleBinding{RoleName: roleName})
+	if err != nil {
+		message := fmt.Sprintf("errcode: %d, can not list rolebindings, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_LIST_ROLEBINDINGS", message)
+		return
+	}
+	if len(bindings) > 0 {
+		message := fmt.Sprintf("errcode: %d, role %s is still bound by %d rolebindings", common.BcsErrApiBadRequest, roleName, len(bindings))
+		WriteClientError(response, "ROLE_IN_USE", message)
+		return
+	}
+
+	if err := sqlstore.DeleteRole(roleName); err != nil {
+		message := fmt.Sprintf("errcode: %d, can not delete role, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_DELETE_ROLE", message)
+		return
+	}
+	response.WriteEntity(m.Role{Name: roleName})
+}
+
+// ListRoleBindings lists rolebindings filtered by role_name, subject and cluster_id
+func ListRoleBindings(request *restful.Request, response *restful.Response) {
+	bindings, err := sqlstore.ListRoleBindings(&m.RoleBinding{
+		RoleName:  request.QueryParameter("role_name"),
+		Subject:   request.QueryParameter("subject"),
+		ClusterID: request.QueryParameter("cluster_id"),
+	})
+	if err != nil {
+		message := fmt.Sprintf("errcode: %d, can not list rolebindings, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_LIST_ROLEBINDINGS", message)
+		return
+	}
+	response.WriteEntity(bindings)
+}
+
+func CreateRoleBinding(request *restful.Request, response *restful.Response) {
+	form := RoleBindingForm{}
+	request.ReadEntity(&form)
+	if err := validate.Struct(&form); err != nil {
+		response.WriteEntity(FormatValidationError(err))
+		return
+	}
+
+	role := sqlstore.GetRole(form.RoleName)
+	if role == nil {
+		message := fmt.Sprintf("errcode: %d, role with name=%s not found", common.BcsErrApiBadRequest, form.RoleName)
+		WriteClientError(response, "ROLE_NOT_FOUND", message)
+		return
+	}
+	if sqlstore.GetRoleBinding(form.Name) != nil {
+		message := fmt.Sprintf("errcode: %d, create failed, rolebinding with this name already exists", common.BcsErrApiBadRequest)
+		WriteClientError(response, "ROLEBINDING_ALREADY_EXISTS", message)
+		return
+	}
+
+	binding := &m.RoleBinding{
+		Name:        form.Name,
+		RoleName:    form.RoleName,
+		SubjectKind: form.SubjectKind,
+		Subject:     form.Subject,
+		ClusterID:   form.ClusterID,
+		Namespace:   form.Namespace,
+	}
+	if err := sqlstore.CreateRoleBinding(binding); err != nil {
+		message := fmt.Sprintf("errcode: %d, can not create rolebinding, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_CREATE_ROLEBINDING", message)
+		return
+	}
+
+	if config.TurnOnRBAC {
+		go syncLocalRoleBinding(binding, role.KubeRole, rbac.OperationAdd)
+	}
+	response.WriteEntity(*binding)
+}
+
+func DeleteRoleBinding(request *restful.Request, response *restful.Response) {
+	bindingName := request.PathParameter("binding_name")
+	binding := sqlstore.GetRoleBinding(bindingName)
+	if binding == nil {
+		message := fmt.Sprintf("errcode: %d, rolebinding with name=%s not found", common.BcsErrApiBadRequest, bindingName)
+		WriteNotFoundError(response, "ROLEBINDING_NOT_FOUND", message)
+		return
+	}
+
+	if err := sqlstore.DeleteRoleBinding(bindingName); err != nil {
+		message := fmt.Sprintf("errcode: %d, can not delete rolebinding, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_DELETE_ROLEBINDING", message)
+		return
+	}
+
+	if role := sqlstore.GetRole(binding.RoleName); role != nil && config.TurnOnRBAC {
+		go syncLocalRoleBinding(binding, role.KubeRole, rbac.OperationDelete)
+	}
+	response.WriteEntity(*binding)
+}
+
+func validateRoleForm(form *RoleForm, response *restful.Response) bool {
+	if err := validate.Struct(form); err != nil {
+		response.WriteEntity(FormatValidationError(err))
+		return false
+	}
+	for _, rule := range form.Rules {
+		if len(rule.Kinds) == 0 || len(rule.Verbs) == 0 {
+			message := fmt.Sprintf("errcode: %d, kinds and verbs of rule can not be empty", common.BcsErrApiBadRequest)
+			WriteClientError(response, "INVALID_ROLE_RULE", message)
+			return false
+		}
+	}
+	if form.KubeRole != "" {
+		if _, ok := template.RoleTemplateStore[template.ClusterRolePrefix+form.KubeRole]; !ok {
+			message := fmt.Sprintf("errcode: %d, kube role %s not found in role templates", common.BcsErrApiBadRequest, form.KubeRole)
+			WriteClientError(response, "INVALID_KUBE_ROLE", message)
+			return false
+		}
+	}
+	return true
+}
+
+func syncLocalRoleBinding(binding *m.RoleBinding, kubeRole, operation string) {
+	if err := rbac.SyncLocalRoleBinding(binding, kubeRole, operation); err != nil {
+		blog.Errorf("sync rolebinding %s to k8s clusters failed: %v", binding.Name, err)
+	}
+}
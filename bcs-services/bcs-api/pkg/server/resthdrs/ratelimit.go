@@ -0,0 +1,90 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resthdrs
+
+import (
+	"fmt"
+
+	"bk-bcs/bcs-common/common"
+	"bk-bcs/bcs-services/bcs-api/options"
+	"bk-bcs/bcs-services/bcs-api/pkg/ratelimit"
+	"bk-bcs/bcs-services/bcs-api/pkg/server/resthdrs/filters"
+	"github.com/emicklei/go-restful"
+)
+
+// GetRateLimitRules returns the rate limit rules in effect
+func GetRateLimitRules(request *restful.Request, response *restful.Response) {
+	rules, err := ratelimit.GetRules()
+	if err != nil {
+		message := fmt.Sprintf("errcode: %d, %s", common.BcsErrApiBadRequest, err.Error())
+		WriteClientError(response, "RATELIMIT_DISABLED", message)
+		return
+	}
+	response.WriteEntity(rules)
+}
+
+// UpdateRateLimitRules replaces the rate limit rules, they are synced to all bcs-api instances
+func UpdateRateLimitRules(request *restful.Request, response *restful.Response) {
+	rules := options.RateLimitRules{}
+	if err := request.ReadEntity(&rules); err != nil {
+		message := fmt.Sprintf("errcode: %d, invalid rate limit rules: %s", common.BcsErrApiBadRequest, err.Error())
+		WriteClientError(response, "INVALID_RATELIMIT_RULES", message)
+		return
+	}
+	if err := validateRateLimitRules(&rules); err != nil {
+		message := fmt.Sprintf("errcode: %d, invalid rate limit rules: %s", common.BcsErrApiBadRequest, err.Error())
+		WriteClientError(response, "INVALID_RATELIMIT_RULES", message)
+		return
+	}
+
+	if !ratelimit.Enabled() {
+		message := fmt.Sprintf("errcode: %d, rate limit is disabled", common.BcsErrApiBadRequest)
+		WriteClientError(response, "RATELIMIT_DISABLED", message)
+		return
+	}
+	if err := ratelimit.UpdateRules(rules, filters.GetUser(request).Name); err != nil {
+		message := fmt.Sprintf("errcode: %d, can not update rate limit rules, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_UPDATE_RATELIMIT_RULES", message)
+		return
+	}
+	response.WriteEntity(rules)
+}
+
+func validateRateLimitRules(rules *options.RateLimitRules) error {
+	budgets := map[string]options.RateLimitBudget{
+		"user":    rules.User,
+		"token":   rules.Token,
+		"cluster": rules.Cluster,
+	}
+	for user, budget := range rules.UserOverrides {
+		budgets["user_overrides."+user] = budget
+	}
+	for cluster, budget := range rules.ClusterOverrides {
+		budgets["cluster_overrides."+cluster] = budget
+	}
+
+	for name, budget := range budgets {
+		limits := map[string]options.RateLimit{
+			"read":         budget.Read,
+			"mutate":       budget.Mutate,
+			"long_running": budget.LongRunning,
+		}
+		for class, limit := range limits {
+			if limit.QPS < 0 || limit.Burst < 0 || limit.MaxInflight < 0 {
+				return fmt.Errorf("%s.%s: qps, burst and max_inflight can not be negative", name, class)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resthdrs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"bk-bcs/bcs-common/common"
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-services/bcs-api/config"
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/server/resthdrs/filters"
+	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
+	"github.com/emicklei/go-restful"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// storage functions used by token handlers, they are replaced in tests
+var (
+	getCluster            = sqlstore.GetCluster
+	listUserTokens        = sqlstore.ListUserTokens
+	createScopedUserToken = sqlstore.CreateScopedUserToken
+	getUserTokenByID      = sqlstore.GetUserTokenByID
+	revokeUserToken       = sqlstore.RevokeUserToken
+	rotateUserToken       = sqlstore.RotateUserToken
+)
+
+// ScopedTokenForm is used to create a scoped token
+type ScopedTokenForm struct {
+	Name     string        `json:"name" validate:"required,max=64"`
+	Scopes   m.TokenScopes `json:"scopes"`
+	ReadOnly bool          `json:"read_only"`
+	// ExpiresIn is the seconds before token expires, sqlstore.UserTokenForScopedExpiredTime is used if it is 0
+	ExpiresIn int64 `json:"expires_in" validate:"gte=0"`
+}
+
+// UserTokenResponse is the token returned by token management apis, value is only returned when the token
+// is created or rotated
+type UserTokenResponse struct {
+	ID        uint          `json:"id"`
+	Name      string        `json:"name"`
+	Type      string        `json:"type"`
+	Value     string        `json:"value,omitempty"`
+	Scopes    m.TokenScopes `json:"scopes,omitempty"`
+	ReadOnly  bool          `json:"read_only"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+func newUserTokenResponse(userToken *m.UserToken, withValue bool) UserTokenResponse {
+	resp := UserTokenResponse{
+		ID:        userToken.ID,
+		Name:      userToken.Name,
+		Type:      m.UserTokenTypeName(userToken.Type),
+		Scopes:    userToken.Scopes,
+		ReadOnly:  userToken.ReadOnly,
+		ExpiresAt: userToken.ExpiresAt,
+		CreatedAt: userToken.CreatedAt,
+	}
+	if withValue {
+		resp.Value = userToken.Value
+	}
+	return resp
+}
+
+// ListTokens lists the tokens of current user which are not revoked, values of tokens are not returned
+func ListTokens(request *restful.Request, response *restful.Response) {
+	user := filters.GetUser(request)
+	userTokens, err := listUserTokens(user.ID)
+	if err != nil {
+		message := fmt.Sprintf("errcode: %d, can not list user tokens, error: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_LIST_USER_TOKENS", message)
+		return
+	}
+	resp := make([]UserTokenResponse, 0, len(userTokens))
+	for i := range userTokens {
+		resp = append(resp, newUserTokenResponse(&userTokens[i], false))
+	}
+	response.WriteEntity(resp)
+}
+
+// CreateToken creates a scoped token for current user
+func CreateToken(request *restful.Request, response *restful.Response) {
+	form := ScopedTokenForm{}
+	request.ReadEntity(&form)
+	if userToken := createScopedToken(filters.GetUser(request), &form, response); userToken != nil {
+		response.WriteEntity(newUserTokenResponse(userToken, true))
+	}
+}
+
+// createScopedToken validates the form and creates token for user, error is written to response if it returns nil
+func createScopedToken(user *m.User, form *ScopedTokenForm, response *restful.Response) *m.UserToken {
+	if err := validate.Struct(form); err != nil {
+		response.WriteEntity(FormatValidationError(err))
+		return nil
+	}
+	for _, scope := range form.Scopes {
+		if scope.ClusterID == m.PolicyMatchAll {
+			continue
+		}
+		if scope.ClusterID == "" || getCluster(scope.ClusterID) == nil {
+			message := fmt.Sprintf("errcode: %d, cluster %s in scopes not found", common.BcsErrApiBadRequest, scope.ClusterID)
+			WriteClientError(response, "CLUSTER_NOT_FOUND", message)
+			return nil
+		}
+	}
+
+	expiresIn := sqlstore.UserTokenForScopedExpiredTime
+	if form.ExpiresIn > 0 {
+		expiresIn = time.Duration(form.ExpiresIn) * time.Second
+	}
+	if expiresIn > sqlstore.UserTokenForSessionExpiredTime {
+		expiresIn = sqlstore.UserTokenForSessionExpiredTime
+	}
+
+	userToken, err := createScopedUserToken(user, form.Name, form.Scopes, form.ReadOnly, time.Now().Add(expiresIn))
+	if err != nil {
+		message := fmt.Sprintf("errcode: %d, can not create user token: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_CREATE_USER_TOKEN", message)
+		return nil
+	}
+	blog.Infof("scoped token %d(%s) is created for user %s", userToken.ID, userToken.Name, user.Name)
+	return userToken
+}
+
+// RevokeToken revokes the token of current user, super user can revoke tokens of any user
+func RevokeToken(request *restful.Request, response *restful.Response) {
+	user := filters.GetUser(request)
+	userToken := getOwnedUserToken(user, request, response)
+	if userToken == nil {
+		return
+	}
+
+	if err := revokeUserToken(userToken); err != nil {
+		message := fmt.Sprintf("errcode: %d, can not revoke user token: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_REVOKE_USER_TOKEN", message)
+		return
+	}
+	blog.Infof("token %d(%s) is revoked by user %s", userToken.ID, userToken.Name, user.Name)
+	response.WriteEntity(newUserTokenResponse(userToken, false))
+}
+
+// RotateToken replaces the value of token and resets its expiration, scopes of token are not changed.
+// The old value is invalid once the token is rotated.
+func RotateToken(request *restful.Request, response *restful.Response) {
+	user := filters.GetUser(request)
+	userToken := getOwnedUserToken(user, request, response)
+	if userToken == nil {
+		return
+	}
+
+	expiresIn := sqlstore.UserTokenForSessionExpiredTime
+	switch userToken.Type {
+	case m.UserTokenTypeKubeConfigForPaas:
+		expiresIn = sqlstore.UserTokenForKubeconfigExpiredTime
+	case m.UserTokenTypeScoped:
+		// keep the lifetime of token given when it is created
+		expiresIn = userToken.ExpiresAt.Sub(userToken.CreatedAt)
+	}
+	if err := rotateUserToken(userToken, time.Now().Add(expiresIn)); err != nil {
+		message := fmt.Sprintf("errcode: %d, can not rotate user token: %s", common.BcsErrApiInternalDbError, err.Error())
+		WriteServerError(response, "CANNOT_ROTATE_USER_TOKEN", message)
+		return
+	}
+	blog.Infof("token %d(%s) is rotated by user %s", userToken.ID, userToken.Name, user.Name)
+	response.WriteEntity(newUserTokenResponse(userToken, true))
+}
+
+// getOwnedUserToken gets the token by token_id in path, the token must belong to user unless user is super user
+func getOwnedUserToken(user *m.User, request *restful.Request, response *restful.Response) *m.UserToken {
+	tokenID, err := strconv.Atoi(request.PathParameter("token_id"))
+	if err != nil {
+		message := fmt.Sprintf("errcode: %d, error parsing token_id to uint: %s", common.BcsErrApiBadRequest, err.Error())
+		WriteClientError(response, "TOKEN_ID_INVALID", message)
+		return nil
+	}
+	userToken := getUserTokenByID(uint(tokenID))
+	if userToken == nil || userToken.IsRevoked() || (userToken.UserId != user.ID && !user.IsSuperUser) {
+		message := fmt.Sprintf("errcode: %d, token with token_id=%d not found", common.BcsErrApiBadRequest, tokenID)
+		WriteNotFoundError(response, "USER_TOKEN_NOT_FOUND", message)
+		return nil
+	}
+	return userToken
+}
+
+// GetKubeConfig issues a scoped token limited to current cluster and returns a kubeconfig using it, the server of
+// kubeconfig is the reverse proxy of bcs-api. Query parameters:
+// namespace: namespaces the token is limited to, can be given multiple times, all namespaces if not given
+// read_only: true for a read-only token
+// expires_in: seconds before the token expires
+func GetKubeConfig(request *restful.Request, response *restful.Response) {
+	user := filters.GetUser(request)
+	cluster := filters.GetCluster(request)
+
+	form := ScopedTokenForm{
+		Name: fmt.Sprintf("kubeconfig-%s", cluster.ID),
+		Scopes: m.TokenScopes{
+			{ClusterID: cluster.ID, Namespaces: request.Request.URL.Query()["namespace"]},
+		},
+		ReadOnly: request.QueryParameter("read_only") == "true",
+	}
+	if value := request.QueryParameter("expires_in"); value != "" {
+		expiresIn, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || expiresIn < 0 {
+			message := fmt.Sprintf("errcode: %d, invalid expires_in: %s", common.BcsErrApiBadRequest, value)
+			WriteClientError(response, "INVALID_EXPIRES_IN", message)
+			return
+		}
+		form.ExpiresIn = expiresIn
+	}
+
+	var caData []byte
+	if config.Authentication.KubeConfigCAFile != "" {
+		data, err := ioutil.ReadFile(config.Authentication.KubeConfigCAFile)
+		if err != nil {
+			message := fmt.Sprintf("errcode: %d, can not read ca file of bcs-api: %s", common.BcsErrApiInternalFail, err.Error())
+			WriteServerError(response, "CANNOT_READ_CA_FILE", message)
+			return
+		}
+		caData = data
+	}
+
+	userToken := createScopedToken(user, &form, response)
+	if userToken == nil {
+		return
+	}
+
+	kubeConfig := buildKubeConfig(kubeConfigServer(request, cluster), caData, user.Name, userToken.Value,
+		form.Scopes[0].Namespaces)
+	data, err := clientcmd.Write(*kubeConfig)
+	if err != nil {
+		message := fmt.Sprintf("errcode: %d, can not build kubeconfig: %s", common.BcsErrApiInternalFail, err.Error())
+		WriteServerError(response, "CANNOT_BUILD_KUBECONFIG", message)
+		return
+	}
+	blog.Infof("kubeconfig with token %d is issued for user %s of cluster %s", userToken.ID, user.Name, cluster.ID)
+	response.AddHeader("Content-Type", "application/yaml")
+	response.Write(data)
+}
+
+// kubeConfigServer returns the reverse proxy address of cluster, by the configured server or the request
+func kubeConfigServer(request *restful.Request, cluster *m.Cluster) string {
+	server := strings.TrimSuffix(config.Authentication.KubeConfigServer, "/")
+	if server == "" {
+		scheme := "http"
+		if request.Request.TLS != nil {
+			scheme = "https"
+		}
+		server = fmt.Sprintf("%s://%s", scheme, request.Request.Host)
+	}
+	return fmt.Sprintf("%s/tunnels/clusters/%s/", server, cluster.Identifier)
+}
+
+// buildKubeConfig builds kubeconfig with one context, the first namespace is the default namespace of context
+func buildKubeConfig(server string, caData []byte, userName, token string, namespaces []string) *clientcmdapi.Config {
+	name := strings.Replace(userName, ":", ".", 1)
+	kubeConfig := clientcmdapi.NewConfig()
+	kubeConfig.Clusters["bcs"] = &clientcmdapi.Cluster{
+		Server:                   server,
+		CertificateAuthorityData: caData,
+	}
+	kubeConfig.AuthInfos[name] = &clientcmdapi.AuthInfo{Token: token}
+	context := &clientcmdapi.Context{Cluster: "bcs", AuthInfo: name}
+	if len(namespaces) > 0 {
+		context.Namespace = namespaces[0]
+	}
+	kubeConfig.Contexts["bcs"] = context
+	kubeConfig.CurrentContext = "bcs"
+	return kubeConfig
+}
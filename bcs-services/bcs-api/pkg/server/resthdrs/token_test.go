@@ -0,0 +1,286 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package resthdrs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+	"bk-bcs/bcs-services/bcs-api/pkg/server/resthdrs/filters"
+	"bk-bcs/bcs-services/bcs-api/pkg/storages/sqlstore"
+	"github.com/emicklei/go-restful"
+)
+
+// fakeTokenStore keeps user tokens in memory instead of database
+type fakeTokenStore struct {
+	tokens map[uint]*m.UserToken
+	nextID uint
+}
+
+// useFakeTokenStore replaces storage functions of token handlers with an in memory store,
+// cluster BCS-K8S-001 exists in it
+func useFakeTokenStore() *fakeTokenStore {
+	store := &fakeTokenStore{tokens: make(map[uint]*m.UserToken)}
+	getCluster = func(clusterID string) *m.Cluster {
+		if clusterID != "BCS-K8S-001" {
+			return nil
+		}
+		return &m.Cluster{ID: clusterID, Identifier: "identifier-001"}
+	}
+	listUserTokens = func(userID uint) ([]m.UserToken, error) {
+		userTokens := make([]m.UserToken, 0)
+		for id := uint(1); id <= store.nextID; id++ {
+			userToken, ok := store.tokens[id]
+			if ok && userToken.UserId == userID && !userToken.IsRevoked() {
+				userTokens = append(userTokens, *userToken)
+			}
+		}
+		return userTokens, nil
+	}
+	createScopedUserToken = func(user *m.User, name string, scopes m.TokenScopes, readOnly bool, expiresAt time.Time) (*m.UserToken, error) {
+		store.nextID++
+		userToken := &m.UserToken{
+			ID:        store.nextID,
+			UserId:    user.ID,
+			Type:      m.UserTokenTypeScoped,
+			Value:     fmt.Sprintf("token-%d", store.nextID),
+			ExpiresAt: expiresAt,
+			CreatedAt: time.Now(),
+			Name:      name,
+			Scopes:    scopes,
+			ReadOnly:  readOnly,
+		}
+		store.tokens[userToken.ID] = userToken
+		return userToken, nil
+	}
+	getUserTokenByID = func(id uint) *m.UserToken {
+		if userToken, ok := store.tokens[id]; ok {
+			copied := *userToken
+			return &copied
+		}
+		return nil
+	}
+	revokeUserToken = func(userToken *m.UserToken) error {
+		now := time.Now()
+		userToken.RevokedAt = &now
+		store.tokens[userToken.ID].RevokedAt = &now
+		return nil
+	}
+	rotateUserToken = func(userToken *m.UserToken, expiresAt time.Time) error {
+		userToken.Value = userToken.Value + "-rotated"
+		userToken.ExpiresAt = expiresAt
+		*store.tokens[userToken.ID] = *userToken
+		return nil
+	}
+	return store
+}
+
+// serveToken serves request by token handlers as user, cluster BCS-K8S-001 is the current cluster
+func serveToken(user *m.User, method, path, body string) *httptest.ResponseRecorder {
+	ws := new(restful.WebService)
+	ws.Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON)
+	ws.Filter(func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		req.SetAttribute(filters.CurrentUserAttr, user)
+		req.SetAttribute(filters.CurrentCluster, getCluster(req.PathParameter("cluster_id")))
+		chain.ProcessFilter(req, resp)
+	})
+	ws.Route(ws.GET("/tokens").To(ListTokens))
+	ws.Route(ws.POST("/tokens").To(CreateToken))
+	ws.Route(ws.DELETE("/tokens/{token_id}").To(RevokeToken))
+	ws.Route(ws.POST("/tokens/{token_id}/rotate").To(RotateToken))
+	ws.Route(ws.GET("/clusters/{cluster_id}/kubeconfig").To(GetKubeConfig))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	req := httptest.NewRequest(method, "http://bcs-api.example.com"+path, strings.NewReader(body))
+	req.Header.Set("Content-Type", restful.MIME_JSON)
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestCreateToken(t *testing.T) {
+	store := useFakeTokenStore()
+	user := &m.User{ID: 1, Name: "user:alice"}
+
+	tests := []struct {
+		body   string
+		status int
+		scopes int
+	}{
+		{`{"name": "ci", "scopes": [{"cluster_id": "BCS-K8S-001", "namespaces": ["ci"]}, {"cluster_id": "*"}]}`, http.StatusOK, 2},
+		{`{"name": "all"}`, http.StatusOK, 0},
+		{`{"name": "ci", "scopes": [{"cluster_id": "BCS-K8S-002"}]}`, http.StatusBadRequest, 0},
+		{`{"name": "ci", "scopes": [{"namespaces": ["ci"]}]}`, http.StatusBadRequest, 0},
+	}
+	for _, test := range tests {
+		created := store.nextID
+		recorder := serveToken(user, http.MethodPost, "/tokens", test.body)
+		if recorder.Code != test.status {
+			t.Errorf("create token %s: expect status %d, got %d: %s", test.body, test.status, recorder.Code, recorder.Body.String())
+			continue
+		}
+		if test.status != http.StatusOK {
+			if store.nextID != created {
+				t.Errorf("create token %s: expect no token created", test.body)
+			}
+			continue
+		}
+		resp := UserTokenResponse{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("create token %s: decode response err: %s", test.body, err.Error())
+		}
+		if resp.Value == "" || resp.Value != store.tokens[resp.ID].Value || len(resp.Scopes) != test.scopes {
+			t.Errorf("create token %s: expect value and %d scopes returned, got %+v", test.body, test.scopes, resp)
+		}
+		expiresAt := time.Now().Add(sqlstore.UserTokenForScopedExpiredTime)
+		if resp.ExpiresAt.After(expiresAt) || resp.ExpiresAt.Before(expiresAt.Add(-time.Minute)) {
+			t.Errorf("create token %s: expect default expiration, got %s", test.body, resp.ExpiresAt)
+		}
+	}
+
+	//expiration is not longer than session token
+	recorder := serveToken(user, http.MethodPost, "/tokens", `{"name": "long", "expires_in": 999999999999}`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("create long token: expect status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if expiresAt := store.tokens[store.nextID].ExpiresAt; expiresAt.After(time.Now().Add(sqlstore.UserTokenForSessionExpiredTime)) {
+		t.Errorf("expect expiration limited by session token, got %s", expiresAt)
+	}
+
+	//values are not returned by list
+	recorder = serveToken(user, http.MethodGet, "/tokens", "")
+	var list []UserTokenResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &list); err != nil {
+		t.Fatalf("list tokens: decode response err: %s", err.Error())
+	}
+	if len(list) != 3 {
+		t.Fatalf("expect 3 tokens listed, got %d", len(list))
+	}
+	for _, resp := range list {
+		if resp.Value != "" {
+			t.Errorf("expect value of token %d not listed", resp.ID)
+		}
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	store := useFakeTokenStore()
+	alice := &m.User{ID: 1, Name: "user:alice"}
+	bob := &m.User{ID: 2, Name: "user:bob"}
+	admin := &m.User{ID: 3, Name: "user:admin", IsSuperUser: true}
+	for _, user := range []*m.User{alice, alice} {
+		createScopedUserToken(user, "ci", nil, false, time.Now().Add(time.Hour))
+	}
+
+	tests := []struct {
+		user   *m.User
+		path   string
+		status int
+	}{
+		{alice, "/tokens/x", http.StatusBadRequest},
+		{alice, "/tokens/9", http.StatusNotFound},
+		//token of other users
+		{bob, "/tokens/1", http.StatusNotFound},
+		{alice, "/tokens/1", http.StatusOK},
+		//token already revoked
+		{alice, "/tokens/1", http.StatusNotFound},
+		{admin, "/tokens/2", http.StatusOK},
+	}
+	for i, test := range tests {
+		recorder := serveToken(test.user, http.MethodDelete, test.path, "")
+		if recorder.Code != test.status {
+			t.Errorf("test %d: %s revoke %s: expect status %d, got %d", i, test.user.Name, test.path, test.status, recorder.Code)
+		}
+	}
+	for id, userToken := range store.tokens {
+		if !userToken.IsRevoked() {
+			t.Errorf("expect token %d revoked", id)
+		}
+	}
+}
+
+func TestRotateToken(t *testing.T) {
+	store := useFakeTokenStore()
+	alice := &m.User{ID: 1, Name: "user:alice"}
+	userToken, _ := createScopedUserToken(alice, "ci", nil, true, time.Now().Add(time.Hour))
+	userToken.CreatedAt = time.Now().Add(-time.Hour)
+	userToken.ExpiresAt = time.Now().Add(time.Hour)
+	oldValue := userToken.Value
+
+	if recorder := serveToken(&m.User{ID: 2, Name: "user:bob"}, http.MethodPost, "/tokens/1/rotate", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("expect token of other user not rotated, got status %d", recorder.Code)
+	}
+	recorder := serveToken(alice, http.MethodPost, "/tokens/1/rotate", "")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("rotate token: expect status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	resp := UserTokenResponse{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("rotate token: decode response err: %s", err.Error())
+	}
+	if resp.Value == oldValue || resp.Value != store.tokens[1].Value || !resp.ReadOnly {
+		t.Errorf("expect new value returned and token kept read only, got %+v", resp)
+	}
+	//scoped token keeps the lifetime given when it is created
+	expiresAt := time.Now().Add(2 * time.Hour)
+	if resp.ExpiresAt.After(expiresAt) || resp.ExpiresAt.Before(expiresAt.Add(-time.Minute)) {
+		t.Errorf("expect token expires after its lifetime, got %s", resp.ExpiresAt)
+	}
+}
+
+func TestGetKubeConfig(t *testing.T) {
+	store := useFakeTokenStore()
+	alice := &m.User{ID: 1, Name: "user:alice"}
+
+	tests := []string{"-1", "1h"}
+	for _, expiresIn := range tests {
+		recorder := serveToken(alice, http.MethodGet, "/clusters/BCS-K8S-001/kubeconfig?expires_in="+expiresIn, "")
+		if recorder.Code != http.StatusBadRequest || store.nextID != 0 {
+			t.Errorf("expect expires_in %s refused without token created, got status %d", expiresIn, recorder.Code)
+		}
+	}
+}
+
+func TestBuildKubeConfig(t *testing.T) {
+	cluster := &m.Cluster{ID: "BCS-K8S-001", Identifier: "identifier-001"}
+	request := restful.NewRequest(httptest.NewRequest(http.MethodGet, "http://bcs-api.example.com/rest/clusters/BCS-K8S-001/kubeconfig", nil))
+	server := kubeConfigServer(request, cluster)
+	if server != "http://bcs-api.example.com/tunnels/clusters/identifier-001/" {
+		t.Errorf("expect server of request, got %s", server)
+	}
+
+	kubeConfig := buildKubeConfig(server, []byte("ca"), "user:alice", "token-1", []string{"ci", "test"})
+	context := kubeConfig.Contexts[kubeConfig.CurrentContext]
+	if context == nil || context.Namespace != "ci" || context.AuthInfo != "user.alice" {
+		t.Fatalf("expect context of user with the first namespace as default, got %+v", context)
+	}
+	if cluster := kubeConfig.Clusters[context.Cluster]; cluster == nil || cluster.Server != server || string(cluster.CertificateAuthorityData) != "ca" {
+		t.Errorf("expect cluster of bcs-api, got %+v", cluster)
+	}
+	if authInfo := kubeConfig.AuthInfos[context.AuthInfo]; authInfo == nil || authInfo.Token != "token-1" {
+		t.Errorf("expect kubeconfig using the token, got %+v", authInfo)
+	}
+
+	kubeConfig = buildKubeConfig(server, nil, "user:alice", "token-1", nil)
+	if namespace := kubeConfig.Contexts[kubeConfig.CurrentContext].Namespace; namespace != "" {
+		t.Errorf("expect no default namespace, got %s", namespace)
+	}
+}
@@ -103,6 +103,16 @@ func CreateUserToken(request *restful.Request, response *restful.Response) {
 		return
 	}
 
+	// Create a new scoped token if name is given, such as tokens for ci jobs
+	form := ScopedTokenForm{}
+	request.ReadEntity(&form)
+	if form.Name != "" {
+		if userToken := createScopedToken(user, &form, response); userToken != nil {
+			response.WriteEntity(newUserTokenResponse(userToken, true))
+		}
+		return
+	}
+
 	// Create a user token if not exists
 	userToken, err := sqlstore.GetOrCreateUserToken(user, m.UserTokenTypeKubeConfigPlain, "")
 	if err != nil {
@@ -42,8 +42,16 @@ func Setup(conf *config.ApiServConfig) {
 		&m.ClusterCredentials{},
 		&m.RegisterToken{},
 		&m.TkeLbSubnet{},
+		&m.ClusterLabel{},
 		// BCS
 		&m.BCSClusterInfo{},
+		// Audit
+		&m.AuditRecord{},
+		// Local policy
+		&m.Role{},
+		&m.RoleBinding{},
+		// Rate limit
+		&m.RateLimitConfig{},
 	)
 
 	if conf != nil {
@@ -69,4 +77,9 @@ func StartRbacSync(conf *config.ApiServConfig) {
 	if conf.BKE.TurnOnAuth {
 		go rbac.SyncRbacFromAuth()
 	}
+
+	// 将本地权限引擎的 rolebinding 同步到集群
+	if conf.BKE.TurnOnRBAC && conf.AuthMode == "local" {
+		go rbac.SyncRbacFromLocal()
+	}
 }
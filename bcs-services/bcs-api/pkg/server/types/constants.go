@@ -16,4 +16,6 @@ package types
 const (
 	UserBackendTypeDefault = "bke_default"
 	UserBackendTypeBCSAuth = "bcs_auth"
+	UserBackendTypeOIDC    = "oidc"
+	UserBackendTypeToken   = "static_token"
 )
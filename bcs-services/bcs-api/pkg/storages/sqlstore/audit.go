@@ -0,0 +1,40 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package sqlstore
+
+import (
+	"time"
+
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+)
+
+func CreateAuditRecord(record *m.AuditRecord) error {
+	err := GCoreDB.Create(record).Error
+	return err
+}
+
+// QueryAuditRecords query audit records by user, cluster and time range, empty condition is ignored,
+// records are ordered by created time desc
+func QueryAuditRecords(user, clusterID string, begin, end time.Time, offset, limit int) ([]m.AuditRecord, error) {
+	records := make([]m.AuditRecord, 0)
+	query := GCoreDB.Where(&m.AuditRecord{User: user, ClusterID: clusterID})
+	if !begin.IsZero() {
+		query = query.Where("created_at >= ?", begin)
+	}
+	if !end.IsZero() {
+		query = query.Where("created_at < ?", end)
+	}
+	err := query.Order("created_at desc").Offset(offset).Limit(limit).Find(&records).Error
+	return records, err
+}
@@ -58,3 +58,10 @@ func GetClusterByBCSInfo(sourceProjectID string, sourceClusterID string) *m.Clus
 	}
 	return nil
 }
+
+// ListBCSClusterInfos query for all BCSClusterInfo objects
+func ListBCSClusterInfos() ([]m.BCSClusterInfo, error) {
+	infos := make([]m.BCSClusterInfo, 0)
+	err := GCoreDB.Find(&infos).Error
+	return infos, err
+}
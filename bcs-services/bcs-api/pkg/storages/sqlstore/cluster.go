@@ -68,3 +68,26 @@ func CreateCluster(cluster *m.Cluster) error {
 	err := GCoreDB.Create(cluster).Error
 	return err
 }
+
+// ListClusterLabels query for labels of all clusters
+func ListClusterLabels() ([]m.ClusterLabel, error) {
+	labels := make([]m.ClusterLabel, 0)
+	err := GCoreDB.Order("cluster_id, `key`").Find(&labels).Error
+	return labels, err
+}
+
+// ReplaceClusterLabels replaces all labels of the bcs cluster with given ones in a transaction
+func ReplaceClusterLabels(clusterId string, labels map[string]string) error {
+	tx := GCoreDB.Begin()
+	if err := tx.Where(&m.ClusterLabel{ClusterId: clusterId}).Delete(&m.ClusterLabel{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	for key, value := range labels {
+		if err := tx.Create(&m.ClusterLabel{ClusterId: clusterId, Key: key, Value: value}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
@@ -0,0 +1,86 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package sqlstore
+
+import (
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+)
+
+// GetRole query for the role by given name
+func GetRole(name string) *m.Role {
+	role := m.Role{}
+	GCoreDB.Where(&m.Role{Name: name}).First(&role)
+	if role.ID != 0 {
+		return &role
+	}
+	return nil
+}
+
+// ListRoles query for all roles
+func ListRoles() ([]m.Role, error) {
+	roles := make([]m.Role, 0)
+	err := GCoreDB.Find(&roles).Error
+	return roles, err
+}
+
+// SaveRole creates the role or updates it if the id is set
+func SaveRole(role *m.Role) error {
+	err := GCoreDB.Save(role).Error
+	return err
+}
+
+// DeleteRole deletes the role by given name
+func DeleteRole(name string) error {
+	err := GCoreDB.Where(&m.Role{Name: name}).Delete(&m.Role{}).Error
+	return err
+}
+
+// GetRoleBinding query for the role binding by given name
+func GetRoleBinding(name string) *m.RoleBinding {
+	binding := m.RoleBinding{}
+	GCoreDB.Where(&m.RoleBinding{Name: name}).First(&binding)
+	if binding.ID != 0 {
+		return &binding
+	}
+	return nil
+}
+
+// ListRoleBindings query for role bindings, empty condition field is ignored
+func ListRoleBindings(condition *m.RoleBinding) ([]m.RoleBinding, error) {
+	bindings := make([]m.RoleBinding, 0)
+	err := GCoreDB.Where(condition).Find(&bindings).Error
+	return bindings, err
+}
+
+// ListRoleBindingsBySubjects query for role bindings of the user or any of the groups
+func ListRoleBindingsBySubjects(user string, groups []string) ([]m.RoleBinding, error) {
+	bindings := make([]m.RoleBinding, 0)
+	query := GCoreDB.Where("subject_kind = ? AND subject = ?", m.RoleBindingSubjectUser, user)
+	if len(groups) > 0 {
+		query = query.Or("subject_kind = ? AND subject IN (?)", m.RoleBindingSubjectGroup, groups)
+	}
+	err := query.Find(&bindings).Error
+	return bindings, err
+}
+
+func CreateRoleBinding(binding *m.RoleBinding) error {
+	err := GCoreDB.Create(binding).Error
+	return err
+}
+
+// DeleteRoleBinding deletes the role binding by given name
+func DeleteRoleBinding(name string) error {
+	err := GCoreDB.Where(&m.RoleBinding{Name: name}).Delete(&m.RoleBinding{}).Error
+	return err
+}
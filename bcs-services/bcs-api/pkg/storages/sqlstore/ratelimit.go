@@ -0,0 +1,37 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package sqlstore
+
+import (
+	m "bk-bcs/bcs-services/bcs-api/pkg/models"
+)
+
+// GetRateLimitConfig query for the rate limit rules changed at runtime, nil if they are never changed
+func GetRateLimitConfig() *m.RateLimitConfig {
+	config := m.RateLimitConfig{}
+	GCoreDB.First(&config)
+	if config.ID != 0 {
+		return &config
+	}
+	return nil
+}
+
+// SaveRateLimitConfig creates the rate limit rules or updates the existing record
+func SaveRateLimitConfig(config *m.RateLimitConfig) error {
+	if existing := GetRateLimitConfig(); existing != nil {
+		config.ID = existing.ID
+	}
+	err := GCoreDB.Save(config).Error
+	return err
+}
@@ -26,6 +26,8 @@ const (
 	UserTokenForKubeconfigExpiredTime = 24 * time.Hour
 	// this means never expired
 	UserTokenForSessionExpiredTime = 10 * 365 * 24 * time.Hour
+	// default expiration of scoped token if it is not given
+	UserTokenForScopedExpiredTime = 90 * 24 * time.Hour
 )
 
 // Query user by user_id
@@ -130,7 +132,7 @@ func GetUserTokenByUser(user *m.User, tokenType uint) *m.UserToken {
 		return nil
 	}
 	userToken := m.UserToken{}
-	GCoreDB.Where(&m.UserToken{UserId: user.ID, Type: tokenType}).First(&userToken)
+	GCoreDB.Where(&m.UserToken{UserId: user.ID, Type: tokenType}).Where("revoked_at IS NULL").First(&userToken)
 	if userToken.ID != 0 {
 		return &userToken
 	}
@@ -186,3 +188,59 @@ func GetOrCreateUserToken(user *m.User, tokenType uint, defaultToken string) (*m
 
 	return userToken, nil
 }
+
+// CreateScopedUserToken creates a new token of type UserTokenTypeScoped for user
+func CreateScopedUserToken(user *m.User, name string, scopes m.TokenScopes, readOnly bool, expiresAt time.Time) (*m.UserToken, error) {
+	userToken := &m.UserToken{
+		UserId:    user.ID,
+		Type:      m.UserTokenTypeScoped,
+		Value:     uniuri.NewLen(DefaultTokenLength),
+		ExpiresAt: expiresAt,
+		Name:      name,
+		Scopes:    scopes,
+		ReadOnly:  readOnly,
+	}
+	if err := CreateUserToken(userToken); err != nil {
+		blog.Warnf("Unable to create scoped user token %s: %s", user.Name, err.Error())
+		return nil, fmt.Errorf("CREATE_USER_TOKEN_FAIL: %s", err.Error())
+	}
+	return userToken, nil
+}
+
+// GetUserTokenByID query for the user token by id
+func GetUserTokenByID(id uint) *m.UserToken {
+	userToken := m.UserToken{}
+	GCoreDB.Where(&m.UserToken{ID: id}).First(&userToken)
+	if userToken.ID != 0 {
+		return &userToken
+	}
+	return nil
+}
+
+// ListUserTokens query for tokens of user which are not revoked
+func ListUserTokens(userID uint) ([]m.UserToken, error) {
+	userTokens := make([]m.UserToken, 0)
+	err := GCoreDB.Where(&m.UserToken{UserId: userID}).Where("revoked_at IS NULL").Order("id").Find(&userTokens).Error
+	return userTokens, err
+}
+
+// RevokeUserToken marks the user token as revoked, it can not be used any more
+func RevokeUserToken(userToken *m.UserToken) error {
+	now := time.Now()
+	err := GCoreDB.Model(userToken).Update("revoked_at", &now).Error
+	if err == nil {
+		userToken.RevokedAt = &now
+	}
+	return err
+}
+
+// RotateUserToken replaces the value of user token and resets its expiration, the old value is invalid at once
+func RotateUserToken(userToken *m.UserToken, expiresAt time.Time) error {
+	value := uniuri.NewLen(DefaultTokenLength)
+	err := GCoreDB.Model(userToken).Updates(map[string]interface{}{"value": value, "expires_at": expiresAt}).Error
+	if err == nil {
+		userToken.Value = value
+		userToken.ExpiresAt = expiresAt
+	}
+	return err
+}
@@ -63,3 +63,8 @@ func NewInvalid(qualifiedKind schema.GroupKind, name string, fieldName string, e
 func NewUnauthorized(reason string) *errors.StatusError {
 	return errors.NewUnauthorized(reason)
 }
+
+// NewTooManyRequests returns an error indicating the request is rejected by rate limit and may be retried later.
+func NewTooManyRequests(message string, retryAfterSeconds int) *errors.StatusError {
+	return errors.NewTooManyRequests(message, retryAfterSeconds)
+}
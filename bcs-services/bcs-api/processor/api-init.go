@@ -16,6 +16,8 @@ package processor
 import (
 	//import v4 http clusterkeeper actions
 	_ "bk-bcs/bcs-services/bcs-api/processor/http/actions/v4http/clusterkeeper"
+	//import v4 http federation actions
+	_ "bk-bcs/bcs-services/bcs-api/processor/http/actions/v4http/federation"
 	//import v4 http k8s actions
 	_ "bk-bcs/bcs-services/bcs-api/processor/http/actions/v4http/k8s"
 	//import v4 http mesos actions
@@ -0,0 +1,157 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"bk-bcs/bcs-common/common"
+	"bk-bcs/bcs-common/common/blog"
+	bhttp "bk-bcs/bcs-common/common/http"
+	"bk-bcs/bcs-common/common/http/httpclient"
+	"bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-services/bcs-api/filter"
+	"bk-bcs/bcs-services/bcs-api/pkg/federation"
+	"bk-bcs/bcs-services/bcs-api/processor/http/actions"
+	"bk-bcs/bcs-services/bcs-api/regdiscv"
+
+	"github.com/emicklei/go-restful"
+)
+
+func init() {
+	actions.RegisterAction(actions.Action{Verb: "POST", Path: "/bcsapi/v4/federation/{uri:*}", Params: nil, Handler: handlerActions})
+	actions.RegisterAction(actions.Action{Verb: "PUT", Path: "/bcsapi/v4/federation/{uri:*}", Params: nil, Handler: handlerActions})
+	actions.RegisterAction(actions.Action{Verb: "GET", Path: "/bcsapi/v4/federation/{uri:*}", Params: nil, Handler: handlerActions})
+	actions.RegisterAction(actions.Action{Verb: "DELETE", Path: "/bcsapi/v4/federation/{uri:*}", Params: nil, Handler: handlerActions})
+	actions.RegisterAction(actions.Action{Verb: "PATCH", Path: "/bcsapi/v4/federation/{uri:*}", Params: nil, Handler: handlerActions})
+}
+
+// handlerActions fans the request out to the member clusters selected and authorized by auth filter,
+// and replies the merged results with status of each member cluster.
+func handlerActions(req *restful.Request, resp *restful.Response) {
+	blog.V(3).Infof("client %s request %s", req.Request.RemoteAddr, req.Request.URL.Path)
+
+	targets, ok := req.Attribute(federation.TargetsAttribute).([]federation.Target)
+	if !ok {
+		blog.Errorf("handler url %s get no member clusters", req.Request.URL.Path)
+		err := bhttp.InternalError(common.BcsErrApiInternalFail, "member clusters of federated request are not selected")
+		resp.Write([]byte(err.Error()))
+		return
+	}
+
+	data, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		blog.Errorf("handler url %s read request body failed, error: %s", req.Request.URL.Path, err.Error())
+		err1 := bhttp.InternalError(common.BcsErrCommHttpReadBodyFail, common.BcsErrCommHttpReadBodyFailStr)
+		resp.Write([]byte(err1.Error()))
+		return
+	}
+
+	stopOnFailure, _ := strconv.ParseBool(req.Request.Header.Get(federation.StopOnFailureHeaderKey))
+	results := federation.FanOut(targets, stopOnFailure, func(clusterID string) (int, []byte, error) {
+		return request2member(req, clusterID, data)
+	})
+	merged, code, message := federation.Merge(results)
+	blog.Infof("federated request %s %s to %d clusters finished: %s",
+		req.Request.Method, req.Request.URL.Path, len(targets), message)
+
+	reply, err := json.Marshal(bhttp.APIRespone{
+		Result:  code == common.BcsSuccess,
+		Code:    code,
+		Message: message,
+		Data:    merged,
+	})
+	if err != nil {
+		blog.Errorf("handler url %s marshal merged results failed, error: %s", req.Request.URL.Path, err.Error())
+		err1 := bhttp.InternalError(common.BcsErrApiInternalFail, common.BcsErrApiInternalFailStr)
+		resp.Write([]byte(err1.Error()))
+		return
+	}
+	resp.Write(reply)
+}
+
+// request2member sends the federated request to the member cluster as a single cluster request,
+// it returns the status code and body of the reply
+func request2member(req *restful.Request, clusterID string, data []byte) (int, []byte, error) {
+	kind, uri, err := federation.MemberURI(req.Request.URL.Path, clusterID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if req.Request.URL.RawQuery != "" {
+		uri = fmt.Sprintf("%s?%s", uri, req.Request.URL.RawQuery)
+	}
+
+	rd, err := regdiscv.GetRDiscover()
+	if err != nil {
+		return 0, nil, fmt.Errorf("get RDiscover error %s", err.Error())
+	}
+
+	var module, pathPrefix string
+	switch kind {
+	case federation.KindMesos:
+		module, pathPrefix = fmt.Sprintf("%s/%s", types.BCS_MODULE_MESOSAPISERVER, clusterID), "mesosdriver/v4"
+	case federation.KindK8s:
+		module, pathPrefix = fmt.Sprintf("%s/%s", types.BCS_MODULE_K8SAPISERVER, clusterID), "k8sdriver/v4"
+	case federation.KindStorage:
+		module, pathPrefix = types.BCS_MODULE_STORAGE, "bcsstorage/v1"
+	}
+	serv, err := rd.GetModuleServers(module)
+	if err != nil {
+		return 0, nil, fmt.Errorf("get servers %s error %s", module, err.Error())
+	}
+
+	var ser *types.ServerInfo
+	switch s := serv.(type) {
+	case *types.BcsMesosApiserverInfo:
+		ser = &s.ServerInfo
+	case *types.BcsK8sApiserverInfo:
+		ser = &s.ServerInfo
+	case *types.BcsStorageInfo:
+		ser = &s.ServerInfo
+	default:
+		return 0, nil, fmt.Errorf("servers of %s is unknown type %T", module, serv)
+	}
+
+	url := fmt.Sprintf("%s://%s:%d/%s/%s", ser.Scheme, ser.IP, ser.Port, pathPrefix, uri)
+	blog.V(3).Infof("do request to url(%s), method(%s) for cluster %s", url, req.Request.Method, clusterID)
+
+	header := make(http.Header, len(req.Request.Header))
+	for key, values := range req.Request.Header {
+		header[key] = append([]string(nil), values...)
+	}
+	header.Set(filter.BcsClusterIDHeaderKey, clusterID)
+
+	httpcli := httpclient.NewHttpClient()
+	httpcli.SetHeader("Content-Type", "application/json")
+	httpcli.SetHeader("Accept", "application/json")
+	if strings.ToLower(ser.Scheme) == "https" {
+		cliTls, err := rd.GetClientTls()
+		if err != nil {
+			blog.Errorf("get client tls error %s", err.Error())
+		}
+		httpcli.SetTlsVerityConfig(cliTls)
+	}
+
+	reply, err := httpcli.WithContext(req.Request.Context()).RequestEx(url, req.Request.Method, header, data)
+	if err != nil {
+		blog.Errorf("request url %s error %s", url, err.Error())
+		return 0, nil, fmt.Errorf("request cluster %s failed: %s", clusterID, err.Error())
+	}
+	return reply.StatusCode, reply.Reply, nil
+}
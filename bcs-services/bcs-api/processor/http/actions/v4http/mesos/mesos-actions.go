@@ -93,7 +93,7 @@ func request2mesosapi(req *restful.Request, uri, method string) (string, error)
 		httpcli.SetTlsVerityConfig(cliTls)
 	}
 
-	reply, err := httpcli.Request(url, method, req.Request.Header, data)
+	reply, err := httpcli.WithContext(req.Request.Context()).Request(url, method, req.Request.Header, data)
 	if err != nil {
 		blog.Error("request url %s error %s", url, err.Error())
 		err1 := bhttp.InternalError(common.BcsErrApiRequestMesosApiFail, common.BcsErrApiRequestMesosApiFailStr)
@@ -37,7 +37,8 @@ func NewHttpReverseProxy(target *url.URL, certConfig *config.CertConfig) (*httpu
 		req.URL.Path = target.Path
 		req.URL.RawQuery = target.RawQuery
 	}
-	reverseProxy := &httputil.ReverseProxy{Director: director}
+	// flush periodically so that streaming responses such as container logs are relayed in time
+	reverseProxy := &httputil.ReverseProxy{Director: director, FlushInterval: 100 * time.Millisecond}
 	if certConfig.IsSSL {
 		cliTls, err := ssl.ClientTslConfVerity(certConfig.CAFile, certConfig.CertFile, certConfig.KeyFile, certConfig.CertPasswd)
 		if err != nil {
@@ -14,27 +14,49 @@
 package webconsole
 
 import (
+	"fmt"
 	"net"
 	"net/http"
 	"net/url"
+	"path"
 	"strconv"
 	"strings"
 
 	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-services/bcs-api/auth"
 	"bk-bcs/bcs-services/bcs-api/config"
-	"fmt"
+	"bk-bcs/bcs-services/bcs-api/filter"
+	"bk-bcs/bcs-services/bcs-api/pkg/audit"
+	"bk-bcs/bcs-services/bcs-api/pkg/ratelimit"
+
 	"github.com/gorilla/websocket"
 )
 
+const (
+	// consoleUsernameHeader tells consoleproxy who operates the console, for session recording and limits
+	consoleUsernameHeader = "X-Bcs-Username"
+	// consoleNamespaceHeader tells consoleproxy the authorized namespace, which the container must belong to
+	consoleNamespaceHeader = "X-Bcs-Namespace"
+	// consoleClusterHeader tells consoleproxy the authorized cluster, which must be the cluster of host_ip, so that
+	// the namespace authorized in one cluster can not be used on agents of other clusters
+	consoleClusterHeader = "X-Bcs-Cluster-Id"
+
+	// resourceKind is the kind of webconsole operations for fine grained authorizers
+	resourceKind = "webconsole"
+)
+
 type WebconsoleProxy struct {
 
 	// Backend returns the backend URL which the proxy uses to reverse proxy
 	Backend func(*http.Request) (*url.URL, error)
 
 	CertConfig *config.CertConfig
+
+	conf *config.ApiServConfig
+	auth auth.BcsAuth
 }
 
-func NewWebconsoleProxy(certConfig *config.CertConfig) *WebconsoleProxy {
+func NewWebconsoleProxy(conf *config.ApiServConfig, bcsAuth auth.BcsAuth) *WebconsoleProxy {
 	backend := func(req *http.Request) (*url.URL, error) {
 		v := req.URL.Query()
 		hostIp := v.Get("host_ip")
@@ -55,7 +77,9 @@ func NewWebconsoleProxy(certConfig *config.CertConfig) *WebconsoleProxy {
 
 	return &WebconsoleProxy{
 		Backend:    backend,
-		CertConfig: certConfig,
+		CertConfig: conf.ClientCert,
+		conf:       conf,
+		auth:       bcsAuth,
 	}
 }
 
@@ -63,9 +87,50 @@ func (w *WebconsoleProxy) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	backendURL, err := w.Backend(req)
 	if err != nil {
 		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	token, resource, status, err := w.authorize(req)
+	if err != nil {
+		http.Error(rw, err.Error(), status)
+		return
+	}
+
+	// the session limit of consoleproxy only counts the sessions on its own agent, console sessions and following
+	// logs are limited across the cluster by the long running budgets of user, token and cluster
+	release, retryAfter, limitedBy, allowed := ratelimit.Allow(ratelimit.Request{
+		User:      token.Username,
+		Token:     token.Token,
+		ClusterID: resource.ClusterID,
+		Class:     ratelimit.ClassOf(req.Method, isLongRunning(req)),
+	})
+	if !allowed {
+		ratelimit.WriteRejected(rw, retryAfter, limitedBy)
+		return
 	}
+	defer release()
+
+	// the username, namespace and cluster headers are only trusted when set by bcs-api
+	req.Header.Del(consoleUsernameHeader)
+	if token.Username != "" {
+		req.Header.Set(consoleUsernameHeader, token.Username)
+	}
+	req.Header.Set(consoleNamespaceHeader, resource.Namespace)
+	req.Header.Set(consoleClusterHeader, resource.ClusterID)
+
 	if websocket.IsWebSocketUpgrade(req) {
 		websocketProxy := NewWebsocketProxy(w.CertConfig, backendURL)
+		if websocketProxy == nil {
+			http.Error(rw, "failed to create websocket proxy", http.StatusInternalServerError)
+			return
+		}
+		websocketProxy.Director = func(incoming *http.Request, out http.Header) {
+			if username := incoming.Header.Get(consoleUsernameHeader); username != "" {
+				out.Set(consoleUsernameHeader, username)
+			}
+			out.Set(consoleNamespaceHeader, incoming.Header.Get(consoleNamespaceHeader))
+			out.Set(consoleClusterHeader, incoming.Header.Get(consoleClusterHeader))
+		}
 		websocketProxy.ServeHTTP(rw, req)
 		return
 	}
@@ -77,3 +142,67 @@ func (w *WebconsoleProxy) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	httpProxy.ServeHTTP(rw, req)
 	return
 }
+
+// authorize checks the user token for the operation on namespace of cluster in query, and returns the token
+// and the authorized resource, whose namespace consoleproxy checks the container against.
+func (w *WebconsoleProxy) authorize(req *http.Request) (*auth.Token, auth.Resource, int, error) {
+	action, verb, err := operationOf(req.URL.Path)
+	if err != nil {
+		return nil, auth.Resource{}, http.StatusForbidden, err
+	}
+
+	token, err := w.auth.GetToken(req.Header)
+	if err != nil {
+		return nil, auth.Resource{}, http.StatusUnauthorized, fmt.Errorf("authenticate failed: %v", err)
+	}
+	audit.SetUser(req.Context(), token.Username, filter.AuditTokenType(w.conf))
+
+	query := req.URL.Query()
+	resource := auth.Resource{
+		ClusterID: query.Get("cluster_id"),
+		Namespace: query.Get("namespace"),
+		Kind:      resourceKind,
+		Verb:      verb,
+	}
+	if resource.ClusterID == "" || resource.Namespace == "" {
+		return nil, auth.Resource{}, http.StatusBadRequest, fmt.Errorf("param cluster_id and namespace must not be empty")
+	}
+	audit.SetCluster(req.Context(), resource.ClusterID)
+
+	allowed, err := w.auth.Allow(token, action, resource)
+	if err != nil {
+		blog.Errorf("webconsole get auth allow failed: %v", err)
+		return nil, auth.Resource{}, http.StatusForbidden, fmt.Errorf("authorize failed: %v", err)
+	}
+	if !allowed {
+		return nil, auth.Resource{}, http.StatusForbidden, fmt.Errorf("user %s has no authority to %s namespace %s of cluster %s",
+			token.Username, verb, resource.Namespace, resource.ClusterID)
+	}
+	return token, resource, 0, nil
+}
+
+// operationOf returns the action and verb of the webconsole operation. Reading logs is read-only, the others run
+// processes in container like exec of kubernetes, so they are all mutating. Operations not listed, such as the
+// session admin api of consoleproxy, can not be reached through bcs-api.
+func operationOf(uri string) (auth.Action, string, error) {
+	switch operation := path.Base(uri); operation {
+	case "logs":
+		return auth.ActionRead, auth.VerbGet, nil
+	case "create_exec", "start_exec", "resize_exec", "copy_from", "copy_to":
+		return auth.ActionManage, auth.VerbCreate, nil
+	default:
+		return "", "", fmt.Errorf("webconsole operation %s is not allowed", operation)
+	}
+}
+
+// isLongRunning returns true for webconsole operations keeping the connection open, which are exec sessions
+// and following logs
+func isLongRunning(req *http.Request) bool {
+	switch path.Base(req.URL.Path) {
+	case "start_exec":
+		return true
+	case "logs":
+		return req.URL.Query().Get("follow") == "true"
+	}
+	return false
+}
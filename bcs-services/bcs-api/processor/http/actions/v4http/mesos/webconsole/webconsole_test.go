@@ -0,0 +1,98 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package webconsole
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bk-bcs/bcs-services/bcs-api/auth"
+	"bk-bcs/bcs-services/bcs-api/config"
+)
+
+// fakeAuth authenticates the token "valid" as user "alice", who is allowed to read namespace ns1 only
+type fakeAuth struct {
+	resource auth.Resource
+}
+
+func (f *fakeAuth) GetToken(header http.Header) (*auth.Token, error) {
+	if header.Get("X-Bcs-User-Token") != "valid" {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return &auth.Token{Token: "valid", Username: "alice"}, nil
+}
+
+func (f *fakeAuth) Allow(token *auth.Token, action auth.Action, resource auth.Resource) (bool, error) {
+	f.resource = resource
+	return action == auth.ActionRead && resource.Namespace == "ns1", nil
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name       string
+		uri        string
+		token      string
+		wantStatus int
+		wantVerb   string
+	}{
+		{name: "read logs", uri: "/bcsapi/v1/webconsole/logs?cluster_id=c1&namespace=ns1", token: "valid", wantVerb: auth.VerbGet},
+		{name: "no token", uri: "/bcsapi/v1/webconsole/logs?cluster_id=c1&namespace=ns1", wantStatus: http.StatusUnauthorized},
+		{name: "no namespace", uri: "/bcsapi/v1/webconsole/logs?cluster_id=c1", token: "valid", wantStatus: http.StatusBadRequest},
+		{name: "other namespace", uri: "/bcsapi/v1/webconsole/logs?cluster_id=c1&namespace=ns2", token: "valid", wantStatus: http.StatusForbidden, wantVerb: auth.VerbGet},
+		{name: "exec is mutating", uri: "/bcsapi/v1/webconsole/start_exec?cluster_id=c1&namespace=ns1", token: "valid", wantStatus: http.StatusForbidden, wantVerb: auth.VerbCreate},
+		{name: "copy from is mutating", uri: "/bcsapi/v1/webconsole/copy_from?cluster_id=c1&namespace=ns1", token: "valid", wantStatus: http.StatusForbidden, wantVerb: auth.VerbCreate},
+		{name: "session admin", uri: "/bcsapi/v1/webconsole/sessions?cluster_id=c1&namespace=ns1", token: "valid", wantStatus: http.StatusForbidden},
+	}
+	for _, test := range tests {
+		fake := &fakeAuth{}
+		proxy := NewWebconsoleProxy(&config.ApiServConfig{}, fake)
+		req := httptest.NewRequest(http.MethodGet, test.uri, nil)
+		if test.token != "" {
+			req.Header.Set("X-Bcs-User-Token", test.token)
+		}
+
+		token, resource, status, err := proxy.authorize(req)
+		if status != test.wantStatus {
+			t.Errorf("%s: authorize() status = %d (%v), want %d", test.name, status, err, test.wantStatus)
+			continue
+		}
+		if fake.resource.Verb != test.wantVerb {
+			t.Errorf("%s: authorize() verb = %q, want %q", test.name, fake.resource.Verb, test.wantVerb)
+		}
+		if test.wantStatus == 0 && (token.Username != "alice" || resource.Namespace != "ns1") {
+			t.Errorf("%s: authorize() = %s, %s, want alice, ns1", test.name, token.Username, resource.Namespace)
+		}
+	}
+}
+
+func TestIsLongRunning(t *testing.T) {
+	tests := []struct {
+		uri         string
+		longRunning bool
+	}{
+		{"/bcsapi/v1/webconsole/start_exec?cluster_id=c1&namespace=ns1", true},
+		{"/bcsapi/v1/webconsole/logs?cluster_id=c1&namespace=ns1&follow=true", true},
+		{"/bcsapi/v1/webconsole/logs?cluster_id=c1&namespace=ns1&follow=false", false},
+		{"/bcsapi/v1/webconsole/create_exec?cluster_id=c1&namespace=ns1", false},
+		{"/bcsapi/v1/webconsole/copy_to?cluster_id=c1&namespace=ns1", false},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.uri, nil)
+		if longRunning := isLongRunning(req); longRunning != test.longRunning {
+			t.Errorf("%s: expect long running %t, got %t", test.uri, test.longRunning, longRunning)
+		}
+	}
+}
@@ -20,6 +20,9 @@ import (
 	"bk-bcs/bcs-common/common/http/httpserver"
 	"bk-bcs/bcs-services/bcs-api/config"
 	"bk-bcs/bcs-services/bcs-api/filter"
+	"bk-bcs/bcs-services/bcs-api/pkg/audit"
+	"bk-bcs/bcs-services/bcs-api/pkg/auth"
+	"bk-bcs/bcs-services/bcs-api/pkg/ratelimit"
 	"bk-bcs/bcs-services/bcs-api/pkg/server"
 	"bk-bcs/bcs-services/bcs-api/pkg/server/proxier"
 	"bk-bcs/bcs-services/bcs-api/pkg/server/resthdrs"
@@ -50,9 +53,26 @@ func NewProcessor(conf *config.ApiServConfig) *Processor {
 func (p *Processor) Start() error {
 	server.Setup(p.config)
 	server.StartRbacSync(p.config)
+	if err := auth.InitAuthenticators(p.config); err != nil {
+		blog.Errorf("init authenticators failed: %v", err)
+		os.Exit(1)
+	}
+	if err := audit.Init(p.config); err != nil {
+		blog.Errorf("init audit failed: %v", err)
+		os.Exit(1)
+	}
+	if err := ratelimit.Init(p.config); err != nil {
+		blog.Errorf("init rate limit failed: %v", err)
+		os.Exit(1)
+	}
 
 	//handler http service
-	generalFilter, err := filter.NewFilter(p.config)
+	bcsAuth, err := filter.NewBcsAuth(p.config)
+	if err != nil {
+		blog.Errorf("new auth failed: %v", err)
+		os.Exit(1)
+	}
+	generalFilter, err := filter.NewFilter(p.config, bcsAuth)
 	if err != nil {
 		blog.Errorf("new filter failed: %v", err)
 		os.Exit(1)
@@ -60,8 +80,9 @@ func (p *Processor) Start() error {
 	proxier.DefaultReverseProxyDispatcher.Initialize()
 	p.httpServ.RegisterWebServer("", generalFilter.Filter, actions.GetApiAction())
 	router := p.httpServ.GetRouter()
+	router.Use(audit.Middleware)
 	webContainer := p.httpServ.GetWebContainer()
-	router.Handle("/bcsapi/v1/webconsole/{sub_path:.*}", webconsole.NewWebconsoleProxy(p.config.ClientCert))
+	router.Handle("/bcsapi/v1/webconsole/{sub_path:.*}", webconsole.NewWebconsoleProxy(p.config, bcsAuth))
 	router.Handle("/bcsapi/{sub_path:.*}", webContainer)
 	router.Handle("/rest/{sub_path:.*}", resthdrs.CreateRestContainer("/rest"))
 	router.Handle("/tunnels/clusters/{cluster_identifier}/{sub_path:.*}", proxier.DefaultReverseProxyDispatcher)
@@ -0,0 +1,199 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
+	"bk-bcs/bcs-services/bcs-client/pkg/storage/v1"
+
+	"github.com/urfave/cli"
+)
+
+func NewApplyCommand() cli.Command {
+	return cli.Command{
+		Name: "apply",
+		Usage: "create or update application/process/service/secret/configmap/deployment in file, " +
+			"objects not existing are created and those changed are updated",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "from-file, f",
+				Usage: "Apply the objects in `FILE`, json or yaml, may contain several objects",
+			},
+			cli.StringFlag{
+				Name:  "clusterid",
+				Usage: "Cluster ID",
+			},
+			cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only print what would be done, without creating or updating anything",
+			},
+		},
+		Subcommands: []cli.Command{
+			newDiffCommand(),
+		},
+		Action: func(c *cli.Context) error {
+			if err := apply(utils.NewClientContext(c)); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+func apply(c *utils.ClientContext) error {
+	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
+		return err
+	}
+
+	manifests, err := c.Manifests()
+	if err != nil {
+		return err
+	}
+
+	dryRun := c.Bool(utils.OptionDryRun)
+	suffix := ""
+	if dryRun {
+		suffix = " (dry run)"
+	}
+
+	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
+	storage := v1.NewBcsStorage(utils.GetClientOption())
+	for _, manifest := range manifests {
+		live, desiredOnly, err := getLiveObject(scheduler, storage, c.ClusterID(), manifest)
+		if err != nil {
+			return err
+		}
+
+		if live == nil {
+			if !dryRun {
+				if err = createObject(scheduler, c.ClusterID(), manifest); err != nil {
+					return fmt.Errorf("failed to create %s: %v", manifest, err)
+				}
+			}
+			fmt.Printf("%s created%s\n", manifest, suffix)
+			continue
+		}
+
+		diffs, err := utils.DiffJSON(live, manifest.Data, desiredOnly)
+		if err != nil {
+			return fmt.Errorf("failed to compare %s: %v", manifest, err)
+		}
+		if len(diffs) == 0 {
+			fmt.Printf("%s unchanged\n", manifest)
+			continue
+		}
+
+		if !dryRun {
+			if err = updateObject(scheduler, c.ClusterID(), manifest); err != nil {
+				return fmt.Errorf("failed to update %s: %v", manifest, err)
+			}
+		}
+		fmt.Printf("%s configured%s\n", manifest, suffix)
+	}
+	return nil
+}
+
+// getLiveObject returns the json of object currently held by bcs, nil if it does not exist.
+// Application, process and deployment come from the definition in scheduler, which is exactly the json submitted
+// at last. The others come from storage, which has some extra fields, so only fields in file should be compared.
+func getLiveObject(scheduler v4.Scheduler, storage v1.Storage, clusterID string, manifest *utils.Manifest) (live []byte, desiredOnly bool, err error) {
+	var obj interface{}
+	switch manifest.Kind {
+	case utils.KindApplication, utils.KindProcess:
+		live, err = scheduler.GetApplicationDefinitionData(clusterID, manifest.Namespace, manifest.Name)
+	case utils.KindDeployment:
+		live, err = scheduler.GetDeploymentDefinitionData(clusterID, manifest.Namespace, manifest.Name)
+	case utils.KindConfigMap:
+		var set *v1.ConfigMapSet
+		if set, err = storage.InspectConfigMap(clusterID, manifest.Namespace, manifest.Name); err == nil {
+			obj = set.Data
+		}
+	case utils.KindSecret:
+		var set *v1.SecretSet
+		if set, err = storage.InspectSecret(clusterID, manifest.Namespace, manifest.Name); err == nil {
+			obj = set.Data
+		}
+	case utils.KindService:
+		var set *v1.ServiceSet
+		if set, err = storage.InspectService(clusterID, manifest.Namespace, manifest.Name); err == nil {
+			obj = set.Data
+		}
+	default:
+		return nil, false, fmt.Errorf("invalid kind: %s", manifest.Kind)
+	}
+
+	if err == v4.ErrDefinitionNotExist || err == v1.ErrResourceNotExist {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get current %s: %v", manifest, err)
+	}
+	if obj == nil {
+		return live, false, nil
+	}
+
+	if live, err = json.Marshal(obj); err != nil {
+		return nil, false, err
+	}
+	return live, true, nil
+}
+
+func createObject(scheduler v4.Scheduler, clusterID string, manifest *utils.Manifest) error {
+	switch manifest.Kind {
+	case utils.KindApplication:
+		return scheduler.CreateApplication(clusterID, manifest.Namespace, manifest.Data)
+	case utils.KindProcess:
+		return scheduler.CreateProcess(clusterID, manifest.Namespace, manifest.Data)
+	case utils.KindConfigMap:
+		return scheduler.CreateConfigMap(clusterID, manifest.Namespace, manifest.Data)
+	case utils.KindSecret:
+		return scheduler.CreateSecret(clusterID, manifest.Namespace, manifest.Data)
+	case utils.KindService:
+		return scheduler.CreateService(clusterID, manifest.Namespace, manifest.Data)
+	case utils.KindDeployment:
+		return scheduler.CreateDeployment(clusterID, manifest.Namespace, manifest.Data)
+	}
+	return fmt.Errorf("invalid kind: %s", manifest.Kind)
+}
+
+// updateObject updates the object, all the instances of application and process are updated
+func updateObject(scheduler v4.Scheduler, clusterID string, manifest *utils.Manifest) error {
+	instances := manifest.Instance()
+	if instances <= 0 {
+		instances = 1
+	}
+	extraValue := make(url.Values)
+	extraValue.Add("instances", fmt.Sprintf("%d", instances))
+
+	switch manifest.Kind {
+	case utils.KindApplication:
+		return scheduler.UpdateApplication(clusterID, manifest.Namespace, manifest.Data, extraValue)
+	case utils.KindProcess:
+		return scheduler.UpdateProcess(clusterID, manifest.Namespace, manifest.Data, extraValue)
+	case utils.KindConfigMap:
+		return scheduler.UpdateConfigMap(clusterID, manifest.Namespace, manifest.Data, nil)
+	case utils.KindSecret:
+		return scheduler.UpdateSecret(clusterID, manifest.Namespace, manifest.Data, nil)
+	case utils.KindService:
+		return scheduler.UpdateService(clusterID, manifest.Namespace, manifest.Data, nil)
+	case utils.KindDeployment:
+		return scheduler.UpdateDeployment(clusterID, manifest.Namespace, manifest.Data, nil)
+	}
+	return fmt.Errorf("invalid kind: %s", manifest.Kind)
+}
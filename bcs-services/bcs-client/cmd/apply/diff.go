@@ -0,0 +1,92 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package apply
+
+import (
+	"fmt"
+
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
+	"bk-bcs/bcs-services/bcs-client/pkg/storage/v1"
+
+	"github.com/urfave/cli"
+)
+
+func newDiffCommand() cli.Command {
+	return cli.Command{
+		Name:  "diff",
+		Usage: "show the field level changes which would be made by apply",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "from-file, f",
+				Usage: "Compare the objects in `FILE`, json or yaml, may contain several objects",
+			},
+			cli.StringFlag{
+				Name:  "clusterid",
+				Usage: "Cluster ID",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if err := diff(utils.NewClientContext(c)); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+func diff(c *utils.ClientContext) error {
+	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
+		return err
+	}
+
+	manifests, err := c.Manifests()
+	if err != nil {
+		return err
+	}
+
+	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
+	storage := v1.NewBcsStorage(utils.GetClientOption())
+	for _, manifest := range manifests {
+		live, desiredOnly, err := getLiveObject(scheduler, storage, c.ClusterID(), manifest)
+		if err != nil {
+			return err
+		}
+
+		if live == nil {
+			fmt.Printf("%s: not exist, will be created\n", manifest)
+			continue
+		}
+
+		diffs, err := utils.DiffJSON(live, manifest.Data, desiredOnly)
+		if err != nil {
+			return fmt.Errorf("failed to compare %s: %v", manifest, err)
+		}
+		if len(diffs) == 0 {
+			fmt.Printf("%s: unchanged\n", manifest)
+			continue
+		}
+
+		fmt.Printf("%s:\n", manifest)
+		for _, d := range diffs {
+			// do not show the content of secret
+			if manifest.Kind == utils.KindSecret {
+				fmt.Printf("  %s %s\n", d.Op, d.Path)
+				continue
+			}
+			fmt.Printf("  %s\n", d)
+		}
+	}
+	return nil
+}
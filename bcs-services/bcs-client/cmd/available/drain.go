@@ -0,0 +1,122 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package available
+
+import (
+	"fmt"
+	"strings"
+
+	commonTypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
+
+	"github.com/urfave/cli"
+)
+
+func NewDrainCommand() cli.Command {
+	return cli.Command{
+		Name:  "drain",
+		Usage: "disable agent and evict all taskgroups on it, or show drain progress with --status",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "clusterid",
+				Usage: "Cluster ID",
+			},
+			cli.StringFlag{
+				Name:  "ip",
+				Usage: "The ip of agent to drain",
+			},
+			cli.IntFlag{
+				Name:  "parallelism",
+				Usage: "Max taskgroups evicting at the same time",
+				Value: 1,
+			},
+			cli.Int64Flag{
+				Name:  "hostretaintime",
+				Usage: "Seconds evicted taskgroups wait for this agent to be uncordoned, default to hostRetainTime in restart policy",
+			},
+			cli.BoolFlag{
+				Name:  "status",
+				Usage: "Show drain progress only",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if err := drain(utils.NewClientContext(c)); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+func drain(c *utils.ClientContext) error {
+	if err := c.MustSpecified(utils.OptionClusterID, utils.OptionIP); err != nil {
+		return err
+	}
+
+	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
+	ip := c.String(utils.OptionIP)
+
+	if c.Bool("status") {
+		result, err := scheduler.GetAgentDrain(c.ClusterID(), ip)
+		if err != nil {
+			return fmt.Errorf("failed to get agent drain: %v", err)
+		}
+		if result == nil {
+			fmt.Printf("agent %s is not drained\n", ip)
+			return nil
+		}
+		printAgentDrain(result)
+		return nil
+	}
+
+	option := &commonTypes.AgentDrainOption{
+		Parallelism: c.Int("parallelism"),
+	}
+	if c.IsSet("hostretaintime") {
+		hostRetainTime := c.Int64("hostretaintime")
+		option.HostRetainTime = &hostRetainTime
+	}
+
+	result, err := scheduler.DrainAgent(c.ClusterID(), ip, option)
+	if err != nil {
+		return fmt.Errorf("failed to drain agent: %v", err)
+	}
+
+	fmt.Printf("success to start draining agent %s\n", result.InnerIP)
+	return nil
+}
+
+func printAgentDrain(result *commonTypes.BcsAgentDrain) {
+	fmt.Printf("%-16s  %-12s  %-10s  %-8s  %-8s  %-8s  %-10s\n",
+		"IP",
+		"REASON",
+		"PHASE",
+		"PENDING",
+		"EVICTING",
+		"EVICTED",
+		"MESSAGE")
+	fmt.Printf("%-16s  %-12s  %-10s  %-8d  %-8d  %-8d  %-10s\n",
+		result.InnerIP,
+		result.Reason,
+		result.Phase,
+		len(result.Pending),
+		len(result.Evicting),
+		result.Evicted,
+		result.Message)
+
+	if len(result.Pending) > 0 {
+		fmt.Printf("\npending taskgroups: %s\n", strings.Join(result.Pending, ","))
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package available
+
+import (
+	"fmt"
+
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
+
+	"github.com/urfave/cli"
+)
+
+func NewUncordonCommand() cli.Command {
+	return cli.Command{
+		Name:  "uncordon",
+		Usage: "stop draining agent and enable it",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "clusterid",
+				Usage: "Cluster ID",
+			},
+			cli.StringFlag{
+				Name:  "ip",
+				Usage: "The ip of agent to uncordon",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if err := uncordon(utils.NewClientContext(c)); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+func uncordon(c *utils.ClientContext) error {
+	if err := c.MustSpecified(utils.OptionClusterID, utils.OptionIP); err != nil {
+		return err
+	}
+
+	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
+	ip := c.String(utils.OptionIP)
+	if err := scheduler.UncordonAgent(c.ClusterID(), ip); err != nil {
+		return fmt.Errorf("failed to uncordon agent: %v", err)
+	}
+
+	fmt.Printf("success to uncordon agent %s\n", ip)
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package container
+
+import (
+	"fmt"
+	"strings"
+
+	commonTypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/storage/v1"
+)
+
+//target is the container to operate and the agent it runs on
+type target struct {
+	HostIP      string
+	ContainerID string
+}
+
+//resolveTarget finds the agent and docker ID of container in taskgroup, container can be empty
+//if the taskgroup has only one container
+func resolveTarget(c *utils.ClientContext, taskGroup, container string) (*target, error) {
+	storage := v1.NewBcsStorage(utils.GetClientOption())
+	single, err := storage.InspectTaskGroup(c.ClusterID(), c.Namespace(), taskGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect taskgroup %s: %v", taskGroup, err)
+	}
+
+	status, err := findContainer(single.Data.ContainerStatuses, container)
+	if err != nil {
+		return nil, fmt.Errorf("taskgroup %s: %v", taskGroup, err)
+	}
+	if single.Data.HostIP == "" {
+		return nil, fmt.Errorf("taskgroup %s is not running on any agent", taskGroup)
+	}
+
+	return &target{HostIP: single.Data.HostIP, ContainerID: status.ContainerID}, nil
+}
+
+func findContainer(statuses []*commonTypes.BcsContainerStatus, container string) (*commonTypes.BcsContainerStatus, error) {
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("no container found")
+	}
+
+	if container == "" {
+		if len(statuses) > 1 {
+			return nil, fmt.Errorf("container must be specified, one of: %s", containerNames(statuses))
+		}
+		return statuses[0], nil
+	}
+
+	for _, status := range statuses {
+		if status.Name == container || status.ContainerID == container {
+			return status, nil
+		}
+	}
+	return nil, fmt.Errorf("container %s not found, one of: %s", container, containerNames(statuses))
+}
+
+func containerNames(statuses []*commonTypes.BcsContainerStatus) string {
+	names := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		names = append(names, status.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,211 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	commonTypes "bk-bcs/bcs-common/common/types"
+
+	"github.com/gorilla/websocket"
+	"github.com/urfave/cli"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		args      []string
+		taskGroup string
+		container string
+		command   []string
+		err       bool
+	}{
+		{args: []string{"tg"}, taskGroup: "tg"},
+		{args: []string{"tg", "c1"}, taskGroup: "tg", container: "c1"},
+		{args: []string{"tg", "--", "ls", "-l"}, taskGroup: "tg", command: []string{"ls", "-l"}},
+		{args: []string{"tg", "c1", "--", "sh"}, taskGroup: "tg", container: "c1", command: []string{"sh"}},
+		{args: []string{}, err: true},
+		{args: []string{"tg", "c1", "sh"}, err: true},
+	}
+
+	for _, test := range tests {
+		taskGroup, container, command, err := splitArgs(test.args)
+		if (err != nil) != test.err {
+			t.Errorf("%v: expect error %v, got %v", test.args, test.err, err)
+			continue
+		}
+		if taskGroup != test.taskGroup || container != test.container || !reflect.DeepEqual(command, test.command) {
+			t.Errorf("%v: got %s %s %v", test.args, taskGroup, container, command)
+		}
+	}
+}
+
+func TestExecResult(t *testing.T) {
+	tests := []struct {
+		closeErr *websocket.CloseError
+		exitCode int
+		err      bool
+	}{
+		{closeErr: &websocket.CloseError{Code: websocket.CloseNormalClosure, Text: "exit code 0"}},
+		{closeErr: &websocket.CloseError{Code: websocket.CloseNormalClosure, Text: "exit code 127"}, exitCode: 127, err: true},
+		{closeErr: &websocket.CloseError{Code: websocket.CloseInternalServerErr, Text: "no such exec"}, err: true},
+		// consoleproxy of old version closes without exit code
+		{closeErr: &websocket.CloseError{Code: websocket.CloseAbnormalClosure}},
+	}
+
+	for _, test := range tests {
+		err := execResult(test.closeErr)
+		if (err != nil) != test.err {
+			t.Errorf("%v: expect error %v, got %v", test.closeErr, test.err, err)
+			continue
+		}
+		if exitErr, ok := err.(cli.ExitCoder); ok && exitErr.ExitCode() != test.exitCode {
+			t.Errorf("%v: expect exit code %d, got %d", test.closeErr, test.exitCode, exitErr.ExitCode())
+		}
+	}
+}
+
+func TestFindContainer(t *testing.T) {
+	statuses := []*commonTypes.BcsContainerStatus{
+		{Name: "web", ContainerID: "aaa"},
+		{Name: "sidecar", ContainerID: "bbb"},
+	}
+
+	if _, err := findContainer(statuses, ""); err == nil {
+		t.Errorf("container must be specified for taskgroup with several containers")
+	}
+	if s, err := findContainer(statuses, "sidecar"); err != nil || s.ContainerID != "bbb" {
+		t.Errorf("expect sidecar found, got %v, %v", s, err)
+	}
+	if s, err := findContainer(statuses[:1], ""); err != nil || s.ContainerID != "aaa" {
+		t.Errorf("expect the only container, got %v, %v", s, err)
+	}
+	if _, err := findContainer(statuses, "db"); err == nil {
+		t.Errorf("expect error for unknown container")
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2019, 6, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		since  string
+		expect int64
+		err    bool
+	}{
+		{since: "", expect: 0},
+		{since: "10m", expect: now.Add(-10 * time.Minute).Unix()},
+		{since: "2019-06-01T08:00:00Z", expect: now.Add(-2 * time.Hour).Unix()},
+		{since: "-1h", err: true},
+		{since: "yesterday", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := parseSince(test.since, now)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expect error %v, got %v", test.since, test.err, err)
+			continue
+		}
+		if got != test.expect {
+			t.Errorf("%s: expect %d, got %d", test.since, test.expect, got)
+		}
+	}
+}
+
+func TestParseCopySpec(t *testing.T) {
+	tests := []struct {
+		arg    string
+		expect copySpec
+		err    bool
+	}{
+		{arg: "tg:/etc/hosts", expect: copySpec{TaskGroup: "tg", Path: "/etc/hosts"}},
+		{arg: "hosts", expect: copySpec{Path: "hosts"}},
+		{arg: "./a:b", expect: copySpec{Path: "./a:b"}},
+		{arg: "/tmp/a:b", expect: copySpec{Path: "/tmp/a:b"}},
+		{arg: "tg:", err: true},
+		{arg: ":/etc", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := parseCopySpec(test.arg)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expect error %v, got %v", test.arg, test.err, err)
+			continue
+		}
+		if got != test.expect {
+			t.Errorf("%s: expect %+v, got %+v", test.arg, test.expect, got)
+		}
+	}
+}
+
+func TestTarRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bcs-client-cp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	if err = os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(src, "sub", "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err = tarPath(tw, src, "conf"); err != nil {
+		t.Fatal(err)
+	}
+	if err = tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	archive := buf.Bytes()
+
+	// dest does not exist, root entry is renamed to dest
+	renamed := filepath.Join(dir, "renamed")
+	if err = untar(bytes.NewReader(archive), renamed); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(renamed, "sub", "a.txt")); err != nil || string(data) != "hello" {
+		t.Errorf("expect renamed/sub/a.txt, got %s, %v", data, err)
+	}
+
+	// dest is an existing directory, root entry is extracted into it
+	into := filepath.Join(dir, "into")
+	if err = os.Mkdir(into, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = untar(bytes.NewReader(archive), into); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(into, "conf", "sub", "a.txt")); err != nil || string(data) != "hello" {
+		t.Errorf("expect into/conf/sub/a.txt, got %s, %v", data, err)
+	}
+
+	// entries out of dest are rejected
+	buf.Reset()
+	tw = tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0644, Typeflag: tar.TypeReg})
+	tw.Close()
+	if err = untar(bytes.NewReader(buf.Bytes()), into); err == nil {
+		t.Errorf("expect error for entry out of dest")
+	}
+}
@@ -0,0 +1,266 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package container
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/webconsole/v1"
+
+	"github.com/urfave/cli"
+)
+
+func NewCopyCommand() cli.Command {
+	return cli.Command{
+		Name: "cp",
+		Usage: "copy files between container and local, the container path is in form <taskgroup>:<path>. " +
+			"When copying to container, a path ending with / is a directory to copy into, " +
+			"otherwise it is the path of the copied file or directory",
+		ArgsUsage: "<src> <dest>",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "clusterid",
+				Usage: "Cluster ID",
+			},
+			cli.StringFlag{
+				Name:  "namespace, ns",
+				Usage: "Namespace",
+				Value: "",
+			},
+			cli.StringFlag{
+				Name:  "container, c",
+				Usage: "Container name, required if taskgroup has more than one container",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if err := copyFiles(utils.NewClientContext(c)); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+//copySpec is a local path or a path in container of taskgroup
+type copySpec struct {
+	TaskGroup string
+	Path      string
+}
+
+func (cs copySpec) isRemote() bool {
+	return cs.TaskGroup != ""
+}
+
+//parseCopySpec parses <taskgroup>:<path>, paths beginning with / or . are always local
+func parseCopySpec(arg string) (copySpec, error) {
+	if strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, ".") {
+		return copySpec{Path: arg}, nil
+	}
+
+	i := strings.Index(arg, ":")
+	if i < 0 {
+		return copySpec{Path: arg}, nil
+	}
+	if i == 0 || i == len(arg)-1 {
+		return copySpec{}, fmt.Errorf("invalid path %s, must be <taskgroup>:<path> or local path", arg)
+	}
+	return copySpec{TaskGroup: arg[:i], Path: arg[i+1:]}, nil
+}
+
+func copyFiles(c *utils.ClientContext) error {
+	if err := c.MustSpecified(utils.OptionClusterID, utils.OptionNamespace); err != nil {
+		return err
+	}
+
+	args := c.Args()
+	if len(args) != 2 {
+		return fmt.Errorf("usage: cp <src> <dest>")
+	}
+	src, err := parseCopySpec(args[0])
+	if err != nil {
+		return err
+	}
+	dest, err := parseCopySpec(args[1])
+	if err != nil {
+		return err
+	}
+	if src.isRemote() == dest.isRemote() {
+		return fmt.Errorf("one of src and dest must be <taskgroup>:<path>, the other a local path")
+	}
+
+	remote := src
+	if dest.isRemote() {
+		remote = dest
+	}
+	t, err := resolveTarget(c, remote.TaskGroup, c.String("container"))
+	if err != nil {
+		return err
+	}
+
+	console := v1.NewBcsWebConsole(utils.GetClientOption(), c.ClusterID(), c.Namespace())
+	if src.isRemote() {
+		stream, err := console.CopyFromContainer(t.HostIP, t.ContainerID, src.Path)
+		if err != nil {
+			return fmt.Errorf("failed to copy from container: %v", err)
+		}
+		defer stream.Close()
+		return untar(stream, dest.Path)
+	}
+
+	if _, err = os.Lstat(src.Path); err != nil {
+		return err
+	}
+
+	// consoleproxy extracts the archive into a directory
+	dir, name := dest.Path, filepath.Base(src.Path)
+	if !strings.HasSuffix(dest.Path, "/") {
+		dir, name = path.Dir(dest.Path), path.Base(dest.Path)
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(writer)
+		err := tarPath(tw, src.Path, name)
+		if err == nil {
+			err = tw.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+
+	if err = console.CopyToContainer(t.HostIP, t.ContainerID, dir, reader); err != nil {
+		return fmt.Errorf("failed to copy to container: %v", err)
+	}
+	return nil
+}
+
+//tarPath writes src into tw, the root entry is renamed to name
+func tarPath(tw *tar.Writer, src, name string) error {
+	return filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(name, rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err = tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+//untar extracts the archive from container to dest. If dest is an existing directory the root entry
+//is extracted into it, otherwise the root entry is renamed to dest.
+func untar(r io.Reader, dest string) error {
+	dest = filepath.Clean(dest)
+	destIsDir := false
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		destIsDir = true
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(header.Name)
+		target := filepath.Join(dest, filepath.FromSlash(name))
+		if !destIsDir {
+			// replace the root entry with dest
+			rel := ""
+			if i := strings.Index(name, "/"); i >= 0 {
+				rel = name[i+1:]
+			}
+			target = filepath.Join(dest, filepath.FromSlash(rel))
+		}
+		if target != dest && !strings.HasPrefix(target, dest+string(filepath.Separator)) {
+			return fmt.Errorf("invalid entry %s in archive, out of %s", header.Name, dest)
+		}
+
+		mode := os.FileMode(header.Mode).Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, mode|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err = writeFile(target, mode, tr); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err = os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "skip %s: unsupported file type\n", header.Name)
+		}
+	}
+}
+
+func writeFile(file string, mode os.FileMode, r io.Reader) error {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
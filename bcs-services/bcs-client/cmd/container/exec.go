@@ -0,0 +1,206 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/webconsole/v1"
+
+	"github.com/gorilla/websocket"
+	"github.com/urfave/cli"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+func NewExecCommand() cli.Command {
+	return cli.Command{
+		Name:      "exec",
+		Usage:     "execute a command in container of taskgroup",
+		ArgsUsage: "<taskgroup> [container] [-- command args...]",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "clusterid",
+				Usage: "Cluster ID",
+			},
+			cli.StringFlag{
+				Name:  "namespace, ns",
+				Usage: "Namespace",
+				Value: "",
+			},
+			cli.BoolFlag{
+				Name:  "interactive, i",
+				Usage: "Pass stdin to the container",
+			},
+			cli.BoolFlag{
+				Name:  "tty, t",
+				Usage: "Relay the local terminal in raw mode, including window resizes",
+			},
+			cli.BoolFlag{
+				Name:  "it",
+				Usage: "Shorthand of -i -t",
+			},
+			cli.StringFlag{
+				Name:  "user, u",
+				Usage: "User to run the command, root by default",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if err := execute(utils.NewClientContext(c)); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+//splitArgs splits the arguments into taskgroup, container and command by "--"
+func splitArgs(args []string) (string, string, []string, error) {
+	var command []string
+	for i, arg := range args {
+		if arg == "--" {
+			command = args[i+1:]
+			args = args[:i]
+			break
+		}
+	}
+
+	switch len(args) {
+	case 1:
+		return args[0], "", command, nil
+	case 2:
+		return args[0], args[1], command, nil
+	}
+	return "", "", nil, fmt.Errorf("usage: exec <taskgroup> [container] [-- command args...]")
+}
+
+func execute(c *utils.ClientContext) error {
+	if err := c.MustSpecified(utils.OptionClusterID, utils.OptionNamespace); err != nil {
+		return err
+	}
+
+	taskGroup, container, command, err := splitArgs(c.Args())
+	if err != nil {
+		return err
+	}
+	interactive := c.Bool("interactive") || c.Bool("it")
+	tty := c.Bool("tty") || c.Bool("it")
+
+	t, err := resolveTarget(c, taskGroup, container)
+	if err != nil {
+		return err
+	}
+
+	console := v1.NewBcsWebConsole(utils.GetClientOption(), c.ClusterID(), c.Namespace())
+	execID, err := console.CreateExec(t.HostIP, &v1.CreateExecReq{
+		ContainerID: t.ContainerID,
+		Cmd:         command,
+		User:        c.String("user"),
+	})
+	if err != nil {
+		return err
+	}
+
+	conn, err := console.StartExec(t.HostIP, execID)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stdinFd := int(os.Stdin.Fd())
+	if tty && terminal.IsTerminal(stdinFd) {
+		state, err := terminal.MakeRaw(stdinFd)
+		if err != nil {
+			return fmt.Errorf("failed to set terminal raw mode: %v", err)
+		}
+		defer terminal.Restore(stdinFd, state)
+
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+		go relayResize(console, t.HostIP, execID, stdinFd, stopCh)
+	}
+
+	if interactive {
+		go relayStdin(conn)
+	}
+
+	return relayOutput(conn)
+}
+
+//relayResize resizes the exec tty at the beginning and every time the local window changes
+func relayResize(console v1.WebConsole, hostIP, execID string, fd int, stopCh <-chan struct{}) {
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+
+	for {
+		if width, height, err := terminal.GetSize(fd); err == nil {
+			_ = console.ResizeExec(hostIP, execID, width, height)
+		}
+
+		select {
+		case <-stopCh:
+			return
+		case <-winch:
+		}
+	}
+}
+
+func relayStdin(conn *websocket.Conn) {
+	buf := make([]byte, 1024)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			if werr := conn.WriteMessage(websocket.BinaryMessage, buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func relayOutput(conn *websocket.Conn) error {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			// the exec ends with the connection closed by consoleproxy, with the exit code of command
+			if closeErr, ok := err.(*websocket.CloseError); ok {
+				return execResult(closeErr)
+			}
+			return fmt.Errorf("exec connection broken: %v", err)
+		}
+		if _, err = os.Stdout.Write(data); err != nil {
+			return err
+		}
+	}
+}
+
+//execResult returns the exit code of exec as the exit code of bcs-client
+func execResult(closeErr *websocket.CloseError) error {
+	if code, ok := v1.ExitCode(closeErr); ok {
+		if code != 0 {
+			return cli.NewExitError("", code)
+		}
+		return nil
+	}
+	if closeErr.Code == websocket.CloseInternalServerErr {
+		return fmt.Errorf("exec failed: %s", closeErr.Text)
+	}
+	return nil
+}
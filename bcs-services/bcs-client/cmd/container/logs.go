@@ -0,0 +1,126 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package container
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/webconsole/v1"
+
+	"github.com/urfave/cli"
+)
+
+func NewLogsCommand() cli.Command {
+	return cli.Command{
+		Name:      "logs",
+		Usage:     "print the logs of container in taskgroup",
+		ArgsUsage: "<taskgroup> [container]",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "clusterid",
+				Usage: "Cluster ID",
+			},
+			cli.StringFlag{
+				Name:  "namespace, ns",
+				Usage: "Namespace",
+				Value: "",
+			},
+			cli.BoolFlag{
+				Name:  "follow, f",
+				Usage: "Keep streaming the new logs",
+			},
+			cli.StringFlag{
+				Name:  "since",
+				Usage: "Only logs newer than relative duration like 10m, or RFC3339 time like 2019-06-01T10:00:00Z",
+			},
+			cli.StringFlag{
+				Name:  "tail",
+				Usage: "Number of lines from the end of logs, all by default",
+			},
+			cli.BoolFlag{
+				Name:  "timestamps",
+				Usage: "Show timestamp of each line",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if err := logs(utils.NewClientContext(c)); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+func logs(c *utils.ClientContext) error {
+	if err := c.MustSpecified(utils.OptionClusterID, utils.OptionNamespace); err != nil {
+		return err
+	}
+
+	args := c.Args()
+	if len(args) != 1 && len(args) != 2 {
+		return fmt.Errorf("usage: logs <taskgroup> [container]")
+	}
+	container := ""
+	if len(args) == 2 {
+		container = args[1]
+	}
+
+	since, err := parseSince(c.String("since"), time.Now())
+	if err != nil {
+		return err
+	}
+
+	t, err := resolveTarget(c, args[0], container)
+	if err != nil {
+		return err
+	}
+
+	console := v1.NewBcsWebConsole(utils.GetClientOption(), c.ClusterID(), c.Namespace())
+	stream, err := console.Logs(t.HostIP, &v1.LogsOptions{
+		ContainerID: t.ContainerID,
+		Follow:      c.Bool("follow"),
+		Since:       since,
+		Tail:        c.String("tail"),
+		Timestamps:  c.Bool("timestamps"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get logs: %v", err)
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(os.Stdout, stream)
+	return err
+}
+
+//parseSince converts relative duration or RFC3339 time to unix timestamp, 0 if since is empty
+func parseSince(since string, now time.Time) (int64, error) {
+	if since == "" {
+		return 0, nil
+	}
+	if d, err := time.ParseDuration(since); err == nil {
+		if d < 0 {
+			return 0, fmt.Errorf("invalid since %s: duration must be positive", since)
+		}
+		return now.Add(-d).Unix(), nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return 0, fmt.Errorf("invalid since %s: must be duration like 10m or RFC3339 time", since)
+	}
+	return t.Unix(), nil
+}
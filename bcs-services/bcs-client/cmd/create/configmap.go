@@ -20,27 +20,13 @@ import (
 	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
 )
 
-func createConfigMap(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
-		return err
-	}
-
-	data, err := c.FileData()
-	if err != nil {
-		return err
-	}
-
-	namespace, err := utils.ParseNamespaceFromJson(data)
-	if err != nil {
-		return err
-	}
-
+func createConfigMap(c *utils.ClientContext, manifest *utils.Manifest) error {
 	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
-	err = scheduler.CreateConfigMap(c.ClusterID(), namespace, data)
+	err := scheduler.CreateConfigMap(c.ClusterID(), manifest.Namespace, manifest.Data)
 	if err != nil {
-		return fmt.Errorf("failed to create configmap: %v", err)
+		return fmt.Errorf("failed to create configmap %s: %v", manifest.Name, err)
 	}
 
-	fmt.Printf("success to create configmap\n")
+	fmt.Printf("success to create configmap %s\n", manifest.Name)
 	return nil
 }
@@ -28,7 +28,7 @@ func NewCreateCommand() cli.Command {
 		Flags: []cli.Flag{
 			cli.StringFlag{
 				Name:  "from-file, f",
-				Usage: "Create with configuration `FILE`",
+				Usage: "Create with configuration `FILE`, json or yaml, may contain several objects",
 			},
 			cli.StringFlag{
 				Name:  "clusterid",
@@ -36,7 +36,7 @@ func NewCreateCommand() cli.Command {
 			},
 			cli.StringFlag{
 				Name:  "type, t",
-				Usage: "Create type, value can be app/service/secret/configmap/deployment",
+				Usage: "Create type, value can be app/service/secret/configmap/deployment. Kind of object is used if not specified",
 			},
 		},
 		Action: func(c *cli.Context) error {
@@ -49,25 +49,42 @@ func NewCreateCommand() cli.Command {
 }
 
 func create(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionType); err != nil {
+	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
 		return err
 	}
 
-	resourceType := c.String(utils.OptionType)
+	manifests, err := c.Manifests()
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		resourceType := manifest.Kind
+		if c.IsSet(utils.OptionType) {
+			resourceType = c.String(utils.OptionType)
+		}
+
+		if err = createResource(c, resourceType, manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	switch resourceType {
-	case "app", "application":
-		return createApplication(c)
-	case "process":
-		return createProcess(c)
-	case "configmap":
-		return createConfigMap(c)
-	case "secret":
-		return createSecret(c)
-	case "service":
-		return createService(c)
-	case "deploy", "deployment":
-		return createDeployment(c)
+func createResource(c *utils.ClientContext, resourceType string, manifest *utils.Manifest) error {
+	switch utils.NormalizeKind(resourceType) {
+	case utils.KindApplication:
+		return createApplication(c, manifest)
+	case utils.KindProcess:
+		return createProcess(c, manifest)
+	case utils.KindConfigMap:
+		return createConfigMap(c, manifest)
+	case utils.KindSecret:
+		return createSecret(c, manifest)
+	case utils.KindService:
+		return createService(c, manifest)
+	case utils.KindDeployment:
+		return createDeployment(c, manifest)
 	default:
 		return fmt.Errorf("invalid type: %s", resourceType)
 	}
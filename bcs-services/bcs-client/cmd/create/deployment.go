@@ -20,27 +20,13 @@ import (
 	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
 )
 
-func createDeployment(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
-		return err
-	}
-
-	data, err := c.FileData()
-	if err != nil {
-		return err
-	}
-
-	namespace, err := utils.ParseNamespaceFromJson(data)
-	if err != nil {
-		return err
-	}
-
+func createDeployment(c *utils.ClientContext, manifest *utils.Manifest) error {
 	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
-	err = scheduler.CreateDeployment(c.ClusterID(), namespace, data)
+	err := scheduler.CreateDeployment(c.ClusterID(), manifest.Namespace, manifest.Data)
 	if err != nil {
-		return fmt.Errorf("failed to create deployment: %v", err)
+		return fmt.Errorf("failed to create deployment %s: %v", manifest.Name, err)
 	}
 
-	fmt.Printf("success to create deployment\n")
+	fmt.Printf("success to create deployment %s\n", manifest.Name)
 	return nil
 }
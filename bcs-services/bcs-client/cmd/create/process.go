@@ -20,27 +20,13 @@ import (
 	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
 )
 
-func createProcess(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
-		return err
-	}
-
-	data, err := c.FileData()
-	if err != nil {
-		return err
-	}
-
-	namespace, err := utils.ParseNamespaceFromJson(data)
-	if err != nil {
-		return err
-	}
-
+func createProcess(c *utils.ClientContext, manifest *utils.Manifest) error {
 	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
-	err = scheduler.CreateProcess(c.ClusterID(), namespace, data)
+	err := scheduler.CreateProcess(c.ClusterID(), manifest.Namespace, manifest.Data)
 	if err != nil {
-		return fmt.Errorf("failed to create process: %v", err)
+		return fmt.Errorf("failed to create process %s: %v", manifest.Name, err)
 	}
 
-	fmt.Printf("success to create process\n")
+	fmt.Printf("success to create process %s\n", manifest.Name)
 	return nil
 }
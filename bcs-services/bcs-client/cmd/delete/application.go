@@ -20,19 +20,15 @@ import (
 	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
 )
 
-func deleteApplication(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionClusterID, utils.OptionNamespace, utils.OptionName); err != nil {
-		return err
-	}
-
+func deleteApplication(c *utils.ClientContext, namespace, name string) error {
 	enforce := c.String(utils.OptionEnforce) == "1"
 
 	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
-	err := scheduler.DeleteApplication(c.ClusterID(), c.Namespace(), c.String(utils.OptionName), enforce)
+	err := scheduler.DeleteApplication(c.ClusterID(), namespace, name, enforce)
 	if err != nil {
-		return fmt.Errorf("failed to delete application: %v", err)
+		return fmt.Errorf("failed to delete application %s: %v", name, err)
 	}
 
-	fmt.Printf("success to delete application\n")
+	fmt.Printf("success to delete application %s\n", name)
 	return nil
 }
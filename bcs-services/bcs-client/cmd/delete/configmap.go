@@ -20,19 +20,15 @@ import (
 	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
 )
 
-func deleteConfigMap(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionClusterID, utils.OptionNamespace, utils.OptionName); err != nil {
-		return err
-	}
-
+func deleteConfigMap(c *utils.ClientContext, namespace, name string) error {
 	enforce := c.String(utils.OptionEnforce) == "1"
 
 	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
-	err := scheduler.DeleteConfigMap(c.ClusterID(), c.Namespace(), c.String(utils.OptionName), enforce)
+	err := scheduler.DeleteConfigMap(c.ClusterID(), namespace, name, enforce)
 	if err != nil {
-		return fmt.Errorf("failed to delete configmap: %v", err)
+		return fmt.Errorf("failed to delete configmap %s: %v", name, err)
 	}
 
-	fmt.Printf("success to delete configmap\n")
+	fmt.Printf("success to delete configmap %s\n", name)
 	return nil
 }
@@ -30,6 +30,10 @@ func NewDeleteCommand() cli.Command {
 				Name:  "type, t",
 				Usage: "Delete type, app/taskgroup/configmap/service/secret/deployment",
 			},
+			cli.StringFlag{
+				Name:  "from-file, f",
+				Usage: "Delete the objects in `FILE`, json or yaml, may contain several objects",
+			},
 			cli.StringFlag{
 				Name:  "name, n",
 				Usage: "Application name",
@@ -59,25 +63,45 @@ func NewDeleteCommand() cli.Command {
 }
 
 func deleteF(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionType); err != nil {
-		return err
+	// delete the objects in file
+	if c.IsSet(utils.OptionFile) {
+		if err := c.MustSpecified(utils.OptionClusterID); err != nil {
+			return err
+		}
+
+		manifests, err := c.Manifests()
+		if err != nil {
+			return err
+		}
+
+		for _, manifest := range manifests {
+			if err = deleteResource(c, manifest.Kind, manifest.Namespace, manifest.Name); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	resourceType := c.String(utils.OptionType)
+	if err := c.MustSpecified(utils.OptionType, utils.OptionClusterID, utils.OptionNamespace, utils.OptionName); err != nil {
+		return err
+	}
+	return deleteResource(c, c.String(utils.OptionType), c.Namespace(), c.String(utils.OptionName))
+}
 
-	switch resourceType {
-	case "app", "application":
-		return deleteApplication(c)
-	case "process":
-		return deleteProcess(c)
-	case "configmap":
-		return deleteConfigMap(c)
-	case "secret":
-		return deleteSecret(c)
-	case "service":
-		return deleteService(c)
-	case "deploy", "deployment":
-		return deleteDeployment(c)
+func deleteResource(c *utils.ClientContext, resourceType, namespace, name string) error {
+	switch utils.NormalizeKind(resourceType) {
+	case utils.KindApplication:
+		return deleteApplication(c, namespace, name)
+	case utils.KindProcess:
+		return deleteProcess(c, namespace, name)
+	case utils.KindConfigMap:
+		return deleteConfigMap(c, namespace, name)
+	case utils.KindSecret:
+		return deleteSecret(c, namespace, name)
+	case utils.KindService:
+		return deleteService(c, namespace, name)
+	case utils.KindDeployment:
+		return deleteDeployment(c, namespace, name)
 	default:
 		return fmt.Errorf("invalid type: %s", resourceType)
 	}
@@ -25,11 +25,11 @@ import (
 func NewGetCommand() cli.Command {
 	return cli.Command{
 		Name:  "get",
-		Usage: "get the original definition of application/process/deployment",
+		Usage: "get the original definition of application/process/deployment, or watch the state changes of application/taskgroup",
 		Flags: []cli.Flag{
 			cli.StringFlag{
 				Name:  "type, t",
-				Usage: "Get type, app/process/deployment",
+				Usage: "Get type, app/process/deployment, app/taskgroup with --watch",
 			},
 			cli.StringFlag{
 				Name:  "clusterid",
@@ -44,6 +44,10 @@ func NewGetCommand() cli.Command {
 				Name:  "name, n",
 				Usage: "Name",
 			},
+			cli.BoolFlag{
+				Name:  "watch, w",
+				Usage: "Watch the state changes of app/taskgroup until interrupted, all in namespace if name is not specified",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			if err := get(utils.NewClientContext(c)); err != nil {
@@ -61,6 +65,10 @@ func get(c *utils.ClientContext) error {
 
 	resourceType := c.String(utils.OptionType)
 
+	if c.Bool(utils.OptionWatch) {
+		return watch(c, resourceType)
+	}
+
 	switch resourceType {
 	case "app", "application":
 		return getApplication(c)
@@ -0,0 +1,100 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package get
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	schedulerTypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
+)
+
+func watch(c *utils.ClientContext, resourceType string) error {
+	if err := c.MustSpecified(utils.OptionClusterID, utils.OptionNamespace); err != nil {
+		return err
+	}
+
+	var watchType string
+	var format func(data []byte) (string, error)
+	switch resourceType {
+	case "app", "application":
+		watchType = v4.WatchTypeApplication
+		format = formatApplication
+		fmt.Printf("%-10s %-50s %-10s %-10s %-17s %-30s\n",
+			"EVENT", "NAME", "STATUS", "INSTANCE", "RUNNING_INSTANCE", "MESSAGE")
+	case "taskgroup":
+		watchType = v4.WatchTypeTaskGroup
+		format = formatTaskGroup
+		fmt.Printf("%-10s %-50s %-25s %-15s %-15s %-30s\n",
+			"EVENT", "NAME", "APPLICATION", "STATUS", "HOSTNAME", "MESSAGE")
+	default:
+		return fmt.Errorf("invalid type for watch: %s, only app/taskgroup are supported", resourceType)
+	}
+
+	stopCh := make(chan struct{})
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signalCh
+		close(stopCh)
+	}()
+
+	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
+	opts := &v4.WatchOptions{Name: c.String(utils.OptionName)}
+	events, err := scheduler.Watch(c.ClusterID(), c.Namespace(), watchType, opts, stopCh)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %v", resourceType, err)
+	}
+
+	// only print the events which change the printed state
+	printed := make(map[string]string)
+	for event := range events {
+		line, err := format(event.Data)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s %s: %v", resourceType, event.Name, err)
+		}
+		if event.Type == v4.WatchEventDeleted {
+			delete(printed, event.Name)
+		} else if printed[event.Name] == line {
+			continue
+		} else {
+			printed[event.Name] = line
+		}
+		fmt.Printf("%-10s %s\n", event.Type, line)
+	}
+	return nil
+}
+
+func formatApplication(data []byte) (string, error) {
+	var app schedulerTypes.Application
+	if err := json.Unmarshal(data, &app); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%-50s %-10s %-10d %-17d %-30s",
+		app.ID, app.Status, app.Instances, app.RunningInstances, app.Message), nil
+}
+
+func formatTaskGroup(data []byte) (string, error) {
+	var taskGroup schedulerTypes.TaskGroup
+	if err := json.Unmarshal(data, &taskGroup); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%-50s %-25s %-15s %-15s %-30s",
+		taskGroup.ID, taskGroup.AppID, taskGroup.Status, taskGroup.HostName, taskGroup.Message), nil
+}
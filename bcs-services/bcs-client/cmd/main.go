@@ -20,7 +20,9 @@ import (
 	"bk-bcs/bcs-common/common/version"
 	"bk-bcs/bcs-services/bcs-client/cmd/agent"
 	"bk-bcs/bcs-services/bcs-client/cmd/application"
+	"bk-bcs/bcs-services/bcs-client/cmd/apply"
 	"bk-bcs/bcs-services/bcs-client/cmd/available"
+	"bk-bcs/bcs-services/bcs-client/cmd/container"
 	"bk-bcs/bcs-services/bcs-client/cmd/create"
 	deletion "bk-bcs/bcs-services/bcs-client/cmd/delete"
 	"bk-bcs/bcs-services/bcs-client/cmd/deployment"
@@ -29,6 +31,8 @@ import (
 	"bk-bcs/bcs-services/bcs-client/cmd/inspect"
 	"bk-bcs/bcs-services/bcs-client/cmd/list"
 	"bk-bcs/bcs-services/bcs-client/cmd/offer"
+	"bk-bcs/bcs-services/bcs-client/cmd/recommend"
+	"bk-bcs/bcs-services/bcs-client/cmd/rollout"
 	"bk-bcs/bcs-services/bcs-client/cmd/template"
 	"bk-bcs/bcs-services/bcs-client/cmd/update"
 	"bk-bcs/bcs-services/bcs-client/cmd/utils"
@@ -47,11 +51,17 @@ func main() {
 		create.NewCreateCommand(),
 		update.NewUpdateCommand(),
 		deletion.NewDeleteCommand(),
+		apply.NewApplyCommand(),
 		application.NewScaleCommand(),
 		application.NewRollBackCommand(),
 		list.NewListCommand(),
 		inspect.NewInspectCommand(),
 		get.NewGetCommand(),
+		rollout.NewRolloutCommand(),
+		recommend.NewRecommendCommand(),
+		container.NewExecCommand(),
+		container.NewLogsCommand(),
+		container.NewCopyCommand(),
 		//metric.NewMetricCommand(),
 		deployment.NewCancelCommand(),
 		deployment.NewPauseCommand(),
@@ -61,6 +71,8 @@ func main() {
 		env.NewEnvCommand(),
 		available.NewEnableCommand(),
 		available.NewDisableCommand(),
+		available.NewDrainCommand(),
+		available.NewUncordonCommand(),
 		offer.NewOfferCommand(),
 		agent.NewAgentSettingCommand(),
 		template.NewTemplateCommand(),
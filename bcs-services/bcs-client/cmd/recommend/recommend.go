@@ -0,0 +1,140 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package recommend
+
+import (
+	"fmt"
+	"time"
+
+	schedulerTypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
+
+	"github.com/urfave/cli"
+)
+
+func NewRecommendCommand() cli.Command {
+	return cli.Command{
+		Name: "recommend",
+		Usage: "show cpu/mem recommendation of application/deployment containers computed from usage history, " +
+			"label deployment with " + schedulerTypes.RecommendationApplyLabel + "=true to apply them in next rolling update",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "type, t",
+				Usage: "Recommend type, app/deployment",
+			},
+			cli.StringFlag{
+				Name:  "clusterid",
+				Usage: "Cluster ID",
+			},
+			cli.StringFlag{
+				Name:  "namespace, ns",
+				Usage: "Namespace",
+				Value: "",
+			},
+			cli.StringFlag{
+				Name:  "name, n",
+				Usage: "Name",
+			},
+			cli.BoolFlag{
+				Name:  "all, a",
+				Usage: "print raw recommendation data",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if err := recommend(utils.NewClientContext(c)); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+func recommend(c *utils.ClientContext) error {
+	if err := c.MustSpecified(utils.OptionType, utils.OptionClusterID, utils.OptionNamespace, utils.OptionName); err != nil {
+		return err
+	}
+
+	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
+	var result *schedulerTypes.ResourceRecommendation
+	var err error
+	resourceType := c.String(utils.OptionType)
+	switch resourceType {
+	case "app", "application":
+		result, err = scheduler.GetApplicationRecommendation(c.ClusterID(), c.Namespace(), c.String(utils.OptionName))
+	case "deploy", "deployment":
+		result, err = scheduler.GetDeploymentRecommendation(c.ClusterID(), c.Namespace(), c.String(utils.OptionName))
+	default:
+		return fmt.Errorf("invalid type: %s", resourceType)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get recommendation: %v", err)
+	}
+
+	if c.IsSet(utils.OptionAll) {
+		fmt.Printf("%s\n", utils.TryIndent(result))
+		return nil
+	}
+	return printRecommendation(result)
+}
+
+func printRecommendation(result *schedulerTypes.ResourceRecommendation) error {
+	fmt.Printf("%-5s  %-40s  %-8s  %-10s  %-8s  %-10s  %-10s  %-10s  %-10s\n",
+		"INDEX",
+		"IMAGE",
+		"SAMPLES",
+		"HISTORY",
+		"CPU",
+		"CPU(REC)",
+		"MEM",
+		"MEM(REC)",
+		"MESSAGE")
+
+	var currCPU, recCPU, currMem, recMem float64
+	for _, item := range result.Containers {
+		cpu, mem := "-", "-"
+		if item.Current != nil {
+			cpu = fmt.Sprintf("%.2f", item.Current.Cpus)
+			mem = fmt.Sprintf("%.0f", item.Current.Mem)
+		}
+		targetCPU, targetMem := "-", "-"
+		if item.Target != nil {
+			targetCPU = fmt.Sprintf("%.2f", item.Target.Cpus)
+			targetMem = fmt.Sprintf("%.0f", item.Target.Mem)
+			if item.Current != nil {
+				currCPU += item.Current.Cpus
+				currMem += item.Current.Mem
+				recCPU += item.Target.Cpus
+				recMem += item.Target.Mem
+			}
+		}
+		fmt.Printf("%-5d  %-40s  %-8d  %-10s  %-8s  %-10s  %-10s  %-10s  %-10s\n",
+			item.Index,
+			item.Image,
+			item.Samples,
+			(time.Duration(item.HistorySeconds) * time.Second).String(),
+			cpu,
+			targetCPU,
+			mem,
+			targetMem,
+			item.Message)
+	}
+
+	if currCPU > 0 && currMem > 0 {
+		fmt.Printf("\ncpu per instance %.2f -> %.2f (%+.0f%%), mem per instance %.0fMB -> %.0fMB (%+.0f%%)\n",
+			currCPU, recCPU, (recCPU-currCPU)/currCPU*100,
+			currMem, recMem, (recMem-currMem)/currMem*100)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package rollout
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	deploymentType "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+	"bk-bcs/bcs-services/bcs-client/cmd/utils"
+	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
+
+	"github.com/urfave/cli"
+)
+
+func newStatusCommand() cli.Command {
+	return cli.Command{
+		Name: "status",
+		Usage: "block and follow the rolling update of deployment until it completes, " +
+			"exit with error if it fails or times out",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "clusterid",
+				Usage: "Cluster ID",
+			},
+			cli.StringFlag{
+				Name:  "namespace, ns",
+				Usage: "Namespace",
+				Value: "",
+			},
+			cli.StringFlag{
+				Name:  "name, n",
+				Usage: "Deployment name",
+			},
+			cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Max time to wait for the rollout",
+				Value: 10 * time.Minute,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if err := status(utils.NewClientContext(c)); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+//progress is the rolling update progress of a deployment computed from its taskgroups
+type progress struct {
+	Desired     int
+	Updated     int
+	Running     int
+	Surge       int
+	Unavailable int
+}
+
+func status(c *utils.ClientContext) error {
+	if err := c.MustSpecified(utils.OptionClusterID, utils.OptionNamespace, utils.OptionName); err != nil {
+		return err
+	}
+
+	name := c.String(utils.OptionName)
+	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
+	definition, err := scheduler.GetDeploymentDefinition(c.ClusterID(), c.Namespace(), name)
+	if err != nil {
+		return fmt.Errorf("failed to get deployment definition: %v", err)
+	}
+	desired := definition.Spec.Instance
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	deployCh, err := scheduler.Watch(c.ClusterID(), c.Namespace(), v4.WatchTypeDeployment, &v4.WatchOptions{Name: name}, stopCh)
+	if err != nil {
+		return fmt.Errorf("failed to watch deployment: %v", err)
+	}
+	taskGroupCh, err := scheduler.Watch(c.ClusterID(), c.Namespace(), v4.WatchTypeTaskGroup, nil, stopCh)
+	if err != nil {
+		return fmt.Errorf("failed to watch taskgroup: %v", err)
+	}
+
+	timeout := c.Duration("timeout")
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var deployment *deploymentType.Deployment
+	// target is the application which the deployment rolls to
+	target := ""
+	taskGroups := make(map[string]*deploymentType.TaskGroup)
+	lastLine := ""
+	for {
+		select {
+		case <-timer.C:
+			return fmt.Errorf("timed out after %s waiting for deployment %s rollout", timeout, name)
+		case event, ok := <-deployCh:
+			if !ok {
+				return fmt.Errorf("watch of deployment %s closed", name)
+			}
+			if event.Type == v4.WatchEventDeleted {
+				return fmt.Errorf("deployment %s was deleted", name)
+			}
+			deployment = new(deploymentType.Deployment)
+			if err = json.Unmarshal(event.Data, deployment); err != nil {
+				return fmt.Errorf("failed to decode deployment %s: %v", name, err)
+			}
+			target = rolloutTarget(deployment, target)
+		case event, ok := <-taskGroupCh:
+			if !ok {
+				return fmt.Errorf("watch of taskgroup closed")
+			}
+			old := taskGroups[event.Name]
+			if event.Type == v4.WatchEventDeleted {
+				delete(taskGroups, event.Name)
+				if old != nil && belongsTo(deployment, old) {
+					fmt.Printf("taskgroup %s: %s -> deleted\n", event.Name, old.Status)
+				}
+				continue
+			}
+
+			taskGroup := new(deploymentType.TaskGroup)
+			if err = json.Unmarshal(event.Data, taskGroup); err != nil {
+				return fmt.Errorf("failed to decode taskgroup %s: %v", event.Name, err)
+			}
+			taskGroups[event.Name] = taskGroup
+			if belongsTo(deployment, taskGroup) {
+				printTransition(old, taskGroup)
+			}
+		}
+
+		if deployment == nil {
+			continue
+		}
+
+		p := computeProgress(deployment, desired, taskGroups)
+		line := fmt.Sprintf("deployment %s %s: %d of %d updated, %d running, surge %d, unavailable %d",
+			name, deployment.Status, p.Updated, p.Desired, p.Running, p.Surge, p.Unavailable)
+		if deployment.Message != "" {
+			line += fmt.Sprintf(", message: %s", deployment.Message)
+		}
+		if line != lastLine {
+			fmt.Println(line)
+			lastLine = line
+		}
+
+		done, err := checkRollout(deployment, target, p)
+		if err != nil {
+			return err
+		}
+		if done {
+			fmt.Printf("deployment %s successfully rolled out\n", name)
+			return nil
+		}
+	}
+}
+
+func printTransition(old, current *deploymentType.TaskGroup) {
+	if old == nil {
+		fmt.Printf("taskgroup %s: %s\n", current.ID, current.Status)
+		return
+	}
+	if old.Status == current.Status {
+		return
+	}
+	if current.Message != "" {
+		fmt.Printf("taskgroup %s: %s -> %s, message: %s\n", current.ID, old.Status, current.Status, current.Message)
+		return
+	}
+	fmt.Printf("taskgroup %s: %s -> %s\n", current.ID, old.Status, current.Status)
+}
+
+//applicationNames returns the name of application being replaced and the one replacing it,
+//they are the same if deployment is not in rolling update
+func applicationNames(deployment *deploymentType.Deployment) (string, string) {
+	var oldApp, newApp string
+	if deployment.Application != nil {
+		oldApp = deployment.Application.ApplicationName
+	}
+	newApp = oldApp
+	if deployment.ApplicationExt != nil {
+		newApp = deployment.ApplicationExt.ApplicationName
+	}
+	return oldApp, newApp
+}
+
+//rolloutTarget returns the application which the deployment rolls to. It is the new application
+//while rolling update is in progress, otherwise the target found before, or the current application
+//if the deployment was not seen in rolling update, e.g. the rollout finished before watching
+func rolloutTarget(deployment *deploymentType.Deployment, target string) string {
+	if deployment.ApplicationExt != nil {
+		return deployment.ApplicationExt.ApplicationName
+	}
+	if target == "" && deployment.Application != nil {
+		return deployment.Application.ApplicationName
+	}
+	return target
+}
+
+func belongsTo(deployment *deploymentType.Deployment, taskGroup *deploymentType.TaskGroup) bool {
+	if deployment == nil {
+		return false
+	}
+	oldApp, newApp := applicationNames(deployment)
+	return taskGroup.AppID != "" && (taskGroup.AppID == oldApp || taskGroup.AppID == newApp)
+}
+
+func isTerminated(s string) bool {
+	return s == deploymentType.TASKGROUP_STATUS_KILLED || s == deploymentType.TASKGROUP_STATUS_FAIL ||
+		s == deploymentType.TASKGROUP_STATUS_FINISH || s == deploymentType.TASKGROUP_STATUS_LOST
+}
+
+func computeProgress(deployment *deploymentType.Deployment, desired int, taskGroups map[string]*deploymentType.TaskGroup) progress {
+	p := progress{Desired: desired}
+	_, newApp := applicationNames(deployment)
+
+	total := 0
+	for _, taskGroup := range taskGroups {
+		if !belongsTo(deployment, taskGroup) || isTerminated(taskGroup.Status) {
+			continue
+		}
+		total++
+		if taskGroup.AppID == newApp {
+			p.Updated++
+		}
+		if taskGroup.Status == deploymentType.TASKGROUP_STATUS_RUNNING {
+			p.Running++
+		}
+	}
+
+	if total > desired {
+		p.Surge = total - desired
+	}
+	if p.Running < desired {
+		p.Unavailable = desired - p.Running
+	}
+	return p
+}
+
+//checkRollout reports whether the rollout to target application completed, or error if the rollout failed.
+//It is not completed until the rolling update finished and the deployment runs the target application
+func checkRollout(deployment *deploymentType.Deployment, target string, p progress) (bool, error) {
+	switch deployment.Status {
+	case deploymentType.DEPLOYMENT_STATUS_ROLLINGUPDATE_SUSPEND:
+		return false, fmt.Errorf("deployment %s rollout failed: %s", deployment.ObjectMeta.Name, deployment.Message)
+	case deploymentType.DEPLOYMENT_STATUS_DELETING:
+		return false, fmt.Errorf("deployment %s is being deleted", deployment.ObjectMeta.Name)
+	case deploymentType.DEPLOYMENT_STATUS_RUNNING:
+		if deployment.IsInRolling || deployment.ApplicationExt != nil {
+			return false, nil
+		}
+		if current, _ := applicationNames(deployment); current != target {
+			return false, fmt.Errorf("deployment %s rollout to %s was canceled, running %s", deployment.ObjectMeta.Name, target, current)
+		}
+		return p.Updated >= p.Desired && p.Running >= p.Desired, nil
+	}
+	return false, nil
+}
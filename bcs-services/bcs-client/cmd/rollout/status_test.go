@@ -0,0 +1,86 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package rollout
+
+import (
+	"testing"
+
+	deploymentType "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+)
+
+func newTaskGroup(appID string, status string) *deploymentType.TaskGroup {
+	return &deploymentType.TaskGroup{AppID: appID, Status: status}
+}
+
+func TestComputeProgress(t *testing.T) {
+	rolling := &deploymentType.Deployment{
+		Status:         deploymentType.DEPLOYMENT_STATUS_ROLLINGUPDATE,
+		IsInRolling:    true,
+		Application:    &deploymentType.DeploymentReferApplication{ApplicationName: "app-v1"},
+		ApplicationExt: &deploymentType.DeploymentReferApplication{ApplicationName: "app-v2"},
+	}
+	taskGroups := map[string]*deploymentType.TaskGroup{
+		"0.app-v1": newTaskGroup("app-v1", deploymentType.TASKGROUP_STATUS_RUNNING),
+		"1.app-v1": newTaskGroup("app-v1", deploymentType.TASKGROUP_STATUS_RUNNING),
+		"2.app-v1": newTaskGroup("app-v1", deploymentType.TASKGROUP_STATUS_KILLED),
+		"0.app-v2": newTaskGroup("app-v2", deploymentType.TASKGROUP_STATUS_RUNNING),
+		"1.app-v2": newTaskGroup("app-v2", deploymentType.TASKGROUP_STATUS_STAGING),
+		"0.other":  newTaskGroup("other", deploymentType.TASKGROUP_STATUS_RUNNING),
+	}
+
+	p := computeProgress(rolling, 3, taskGroups)
+	expect := progress{Desired: 3, Updated: 2, Running: 3, Surge: 1, Unavailable: 0}
+	if p != expect {
+		t.Errorf("expect progress %+v, got %+v", expect, p)
+	}
+	target := rolloutTarget(rolling, "")
+	if target != "app-v2" {
+		t.Errorf("expect rollout target app-v2, got %s", target)
+	}
+	if done, err := checkRollout(rolling, target, p); done || err != nil {
+		t.Errorf("rolling deployment should not be done, got done %v, err %v", done, err)
+	}
+
+	finished := &deploymentType.Deployment{
+		Status:      deploymentType.DEPLOYMENT_STATUS_RUNNING,
+		Application: &deploymentType.DeploymentReferApplication{ApplicationName: "app-v2"},
+	}
+	taskGroups["1.app-v2"].Status = deploymentType.TASKGROUP_STATUS_RUNNING
+	taskGroups["2.app-v2"] = newTaskGroup("app-v2", deploymentType.TASKGROUP_STATUS_RUNNING)
+	p = computeProgress(finished, 3, taskGroups)
+	expect = progress{Desired: 3, Updated: 3, Running: 3}
+	if p != expect {
+		t.Errorf("expect progress %+v, got %+v", expect, p)
+	}
+	if done, err := checkRollout(finished, rolloutTarget(finished, target), p); !done || err != nil {
+		t.Errorf("finished deployment should be done, got done %v, err %v", done, err)
+	}
+
+	//deployment running the old application has not rolled out the update
+	canceled := &deploymentType.Deployment{
+		Status:      deploymentType.DEPLOYMENT_STATUS_RUNNING,
+		Application: &deploymentType.DeploymentReferApplication{ApplicationName: "app-v1"},
+	}
+	if _, err := checkRollout(canceled, rolloutTarget(canceled, target), p); err == nil {
+		t.Errorf("canceled rollout should fail")
+	}
+
+	suspended := &deploymentType.Deployment{
+		Status:  deploymentType.DEPLOYMENT_STATUS_ROLLINGUPDATE_SUSPEND,
+		Message: "create taskgroup timeout, rollingupdate suspend",
+	}
+	if _, err := checkRollout(suspended, target, p); err == nil {
+		t.Errorf("suspended deployment should fail")
+	}
+}
@@ -31,6 +31,11 @@ func NewTemplateCommand() cli.Command {
 				Name:  "type, t",
 				Usage: "Template type, app/service/configmap/secret/deployment/agentsettings",
 			},
+			cli.StringFlag{
+				Name:  "output, o",
+				Usage: "Output format, json or yaml",
+				Value: "json",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			if err := template(utils.NewClientContext(c)); err != nil {
@@ -47,35 +52,45 @@ func template(c *utils.ClientContext) error {
 	}
 
 	resourceType := c.String(utils.OptionType)
+	output := c.String(utils.OptionOutput)
 
 	switch resourceType {
 	case "app", "application":
-		return getTemplate(applicationTemplate)
+		return getTemplate(applicationTemplate, output)
 	case "configmap":
-		return getTemplate(configMapTemplate)
+		return getTemplate(configMapTemplate, output)
 	case "secret":
-		return getTemplate(secretTemplate)
+		return getTemplate(secretTemplate, output)
 	case "service":
-		return getTemplate(serviceTemplate)
+		return getTemplate(serviceTemplate, output)
 	case "deploy", "deployment":
-		return getTemplate(deploymentTemplate)
+		return getTemplate(deploymentTemplate, output)
 	case "as", "agentsettings":
-		return getTemplate(agentSettingsTemplate)
+		return getTemplate(agentSettingsTemplate, output)
 	default:
 		return fmt.Errorf("invalid type: %s", resourceType)
 	}
 }
 
 const (
-	applicationTemplate   = "{\"apiVersion\":\"v4\",\"kind\":\"application\",\"restartPolicy\":{\"policy\":\"Never\",\"interval\":5,\"backoff\":10},\"killPolicy\":{\"gracePeriod\":5},\"constraint\":{\"IntersectionItem\":[]},\"metadata\":{\"annotations\":{},\"labels\":{\"podname\":\"app-test\"},\"name\":\"app-test\",\"namespace\":\"defaultGroup\"},\"spec\":{\"instance\":1,\"template\":{\"spec\":{\"containers\":[{\"command\":\"/test/start.sh\",\"args\":[\"8899\"],\"parameters\":[],\"type\":\"MESOS\",\"env\":[],\"image\":\"docker_image:latest\",\"imagePullPolicy\":\"Always\",\"privileged\":false,\"ports\":[{\"containerPort\":8899,\"name\":\"test-port\",\"protocol\":\"HTTP\"}],\"healthChecks\":[],\"resources\":{\"limits\":{\"cpu\":\"0.1\",\"memory\":\"50\"}},\"volumes\":[],\"secrets\":[],\"configmaps\":[]}],\"networkMode\":\"BRIDGE\",\"networkType\":\"BRIDGE\"}}}}"
+	applicationTemplate   = "{\"apiVersion\":\"v4\",\"kind\":\"application\",\"restartPolicy\":{\"policy\":\"Never\",\"interval\":5,\"backoff\":10},\"killPolicy\":{\"gracePeriod\":5},\"constraint\":{\"IntersectionItem\":[]},\"metadata\":{\"annotations\":{},\"labels\":{\"podname\":\"app-test\"},\"name\":\"app-test\",\"namespace\":\"defaultGroup\"},\"spec\":{\"instance\":1,\"template\":{\"spec\":{\"initContainers\":[{\"command\":\"/test/init.sh\",\"args\":[],\"parameters\":[],\"type\":\"MESOS\",\"env\":[],\"image\":\"docker_image:latest\",\"imagePullPolicy\":\"Always\",\"privileged\":false,\"resources\":{\"limits\":{\"cpu\":\"0.1\",\"memory\":\"50\"}},\"volumes\":[]}],\"containers\":[{\"command\":\"/test/start.sh\",\"args\":[\"8899\"],\"parameters\":[],\"type\":\"MESOS\",\"env\":[],\"image\":\"docker_image:latest\",\"imagePullPolicy\":\"Always\",\"privileged\":false,\"ports\":[{\"containerPort\":8899,\"name\":\"test-port\",\"protocol\":\"HTTP\"}],\"healthChecks\":[],\"lifecycle\":{\"postStart\":{\"exec\":{\"command\":[\"/test/post_start.sh\"]},\"timeoutSeconds\":30},\"preStop\":{\"http\":{\"port\":8899,\"path\":\"/shutdown\"}}},\"resources\":{\"limits\":{\"cpu\":\"0.1\",\"memory\":\"50\"}},\"volumes\":[],\"secrets\":[],\"configmaps\":[]}],\"networkMode\":\"BRIDGE\",\"networkType\":\"BRIDGE\"}}}}"
 	configMapTemplate     = "{\"apiVersion\":\"v4\",\"kind\":\"configmap\",\"metadata\":{\"name\":\"configmap-test\",\"namespace\":\"defaultGroup\",\"labels\":{}},\"datas\":{\"item-one\":{\"type\":\"file\",\"content\":\"Y29uZmlnIGNvbnRleHQ=\"},\"item-two\":{\"type\":\"file\",\"content\":\"Y29uZmlnIGNvbnRleHQ=\"}}}"
 	secretTemplate        = "{\"apiVersion\":\"v4\",\"kind\":\"secret\",\"metadata\":{\"name\":\"secret-test\",\"namespace\":\"defaultGroup\",\"labels\":{}},\"type\":\"\",\"datas\":{\"secret-subkey\":{\"path\":\"ECRET_ENV_TEST\",\"content\":\"Y29uZmlnIGNvbnRleHQ=\"}}}"
 	serviceTemplate       = "{\"apiVersion\":\"v4\",\"kind\":\"service\",\"metadata\":{\"name\":\"service-test\",\"namespace\":\"defaultGroup\",\"labels\":{\"BCSGROUP\":\"external\"}},\"spec\":{\"selector\":{\"podname\":\"app-test\"},\"ports\":[{\"name\":\"test-port\",\"protocol\":\"tcp\",\"servicePort\":8889}]}}"
-	deploymentTemplate    = "{\"apiVersion\":\"v4\",\"kind\":\"deployment\",\"metadata\":{\"labels\":{\"podname\":\"deployment-test\"},\"name\":\"deployment-test\",\"namespace\":\"defaultGroup\"},\"restartPolicy\":{\"policy\":\"Always\",\"interval\":5,\"backoff\":10},\"constraint\":{\"IntersectionItem\":[]},\"spec\":{\"instance\":2,\"selector\":{\"podname\":\"app-test\"},\"template\":{\"metadata\":{\"labels\":{},\"name\":\"deployment-test\",\"namespace\":\"defaultGroup\"},\"spec\":{\"containers\":[{\"command\":\"/test/start.sh\",\"args\":[\"8899\"],\"parameters\":[],\"type\":\"MESOS\",\"env\":[],\"image\":\"docker_image:latest\",\"imagePullPolicy\":\"Always\",\"privileged\":false,\"ports\":[{\"containerPort\":8899,\"name\":\"test-port\",\"protocol\":\"HTTP\"}],\"healthChecks\":[],\"resources\":{\"limits\":{\"cpu\":\"0.1\",\"memory\":\"50\"}},\"volumes\":[],\"secrets\":[],\"configmaps\":[]}],\"networkMode\":\"BRIDGE\",\"networkType\":\"BRIDGE\"}},\"strategy\":{\"type\":\"RollingUpdate\",\"rollingupdate\":{\"maxUnavilable\":1,\"maxSurge\":1,\"upgradeDuration\":60,\"autoUpgrade\":false,\"rollingOrder\":\"CreateFirst\",\"pause\":false}}}}"
+	deploymentTemplate    = "{\"apiVersion\":\"v4\",\"kind\":\"deployment\",\"metadata\":{\"labels\":{\"podname\":\"deployment-test\"},\"name\":\"deployment-test\",\"namespace\":\"defaultGroup\"},\"restartPolicy\":{\"policy\":\"Always\",\"interval\":5,\"backoff\":10},\"constraint\":{\"IntersectionItem\":[]},\"spec\":{\"instance\":2,\"selector\":{\"podname\":\"app-test\"},\"template\":{\"metadata\":{\"labels\":{},\"name\":\"deployment-test\",\"namespace\":\"defaultGroup\"},\"spec\":{\"initContainers\":[{\"command\":\"/test/init.sh\",\"args\":[],\"parameters\":[],\"type\":\"MESOS\",\"env\":[],\"image\":\"docker_image:latest\",\"imagePullPolicy\":\"Always\",\"privileged\":false,\"resources\":{\"limits\":{\"cpu\":\"0.1\",\"memory\":\"50\"}},\"volumes\":[]}],\"containers\":[{\"command\":\"/test/start.sh\",\"args\":[\"8899\"],\"parameters\":[],\"type\":\"MESOS\",\"env\":[],\"image\":\"docker_image:latest\",\"imagePullPolicy\":\"Always\",\"privileged\":false,\"ports\":[{\"containerPort\":8899,\"name\":\"test-port\",\"protocol\":\"HTTP\"}],\"healthChecks\":[],\"lifecycle\":{\"postStart\":{\"exec\":{\"command\":[\"/test/post_start.sh\"]},\"timeoutSeconds\":30},\"preStop\":{\"http\":{\"port\":8899,\"path\":\"/shutdown\"}}},\"resources\":{\"limits\":{\"cpu\":\"0.1\",\"memory\":\"50\"}},\"volumes\":[],\"secrets\":[],\"configmaps\":[]}],\"networkMode\":\"BRIDGE\",\"networkType\":\"BRIDGE\"}},\"strategy\":{\"type\":\"RollingUpdate\",\"rollingupdate\":{\"maxUnavilable\":1,\"maxSurge\":1,\"upgradeDuration\":60,\"autoUpgrade\":false,\"rollingOrder\":\"CreateFirst\",\"pause\":false}}}}"
 	agentSettingsTemplate = "[{\"innerIP\":\"127.0.0.1\",\"disabled\":true,\"strings\":{\"attr1\":{\"value\":\"hahaha\"}},\"scalars\":{\"foo\":{\"value\":0.01}}}]"
 )
 
-func getTemplate(template string) error {
+func getTemplate(template, output string) error {
+	if output == "yaml" {
+		data, err := utils.ToYAML([]byte(template))
+		if err != nil {
+			return fmt.Errorf("convert template to yaml error: %v", err)
+		}
+		fmt.Printf("%s", data)
+		return nil
+	}
+
 	var data interface{}
 	if err := codec.DecJson([]byte(template), &data); err != nil {
 		return fmt.Errorf("decode template error: %v", err)
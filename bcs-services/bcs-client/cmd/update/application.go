@@ -21,35 +21,21 @@ import (
 	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
 )
 
-func updateApplication(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
-		return err
-	}
-
-	data, err := c.FileData()
-	if err != nil {
-		return err
-	}
-
-	namespace, err := utils.ParseNamespaceFromJson(data)
-	if err != nil {
-		return err
-	}
-
+func updateApplication(c *utils.ClientContext, manifest *utils.Manifest) error {
 	instances := c.Int(utils.OptionInstance)
 	if instances <= 0 {
 		return fmt.Errorf("update application error: instances must be a positive number")
 	}
 
-	var extraValue url.Values
+	extraValue := make(url.Values)
 	extraValue.Add("instances", fmt.Sprintf("%d", instances))
 
 	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
-	err = scheduler.UpdateApplication(c.ClusterID(), namespace, data, extraValue)
+	err := scheduler.UpdateApplication(c.ClusterID(), manifest.Namespace, manifest.Data, extraValue)
 	if err != nil {
-		return fmt.Errorf("failed to update application: %v", err)
+		return fmt.Errorf("failed to update application %s: %v", manifest.Name, err)
 	}
 
-	fmt.Printf("success to update application\n")
+	fmt.Printf("success to update application %s\n", manifest.Name)
 	return nil
 }
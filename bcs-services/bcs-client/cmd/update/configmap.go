@@ -20,27 +20,13 @@ import (
 	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
 )
 
-func updateConfigMap(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
-		return err
-	}
-
-	data, err := c.FileData()
-	if err != nil {
-		return err
-	}
-
-	namespace, err := utils.ParseNamespaceFromJson(data)
-	if err != nil {
-		return err
-	}
-
+func updateConfigMap(c *utils.ClientContext, manifest *utils.Manifest) error {
 	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
-	err = scheduler.UpdateConfigMap(c.ClusterID(), namespace, data, nil)
+	err := scheduler.UpdateConfigMap(c.ClusterID(), manifest.Namespace, manifest.Data, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update configmap: %v", err)
+		return fmt.Errorf("failed to update configmap %s: %v", manifest.Name, err)
 	}
 
-	fmt.Printf("success to update configmap\n")
+	fmt.Printf("success to update configmap %s\n", manifest.Name)
 	return nil
 }
@@ -21,35 +21,21 @@ import (
 	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
 )
 
-func updateProcess(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
-		return err
-	}
-
-	data, err := c.FileData()
-	if err != nil {
-		return err
-	}
-
-	namespace, err := utils.ParseNamespaceFromJson(data)
-	if err != nil {
-		return err
-	}
-
+func updateProcess(c *utils.ClientContext, manifest *utils.Manifest) error {
 	instances := c.Int(utils.OptionInstance)
 	if instances <= 0 {
 		return fmt.Errorf("update process error: instances must be a positive number")
 	}
 
-	var extraValue url.Values
+	extraValue := make(url.Values)
 	extraValue.Add("instances", fmt.Sprintf("%d", instances))
 
 	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
-	err = scheduler.UpdateProcess(c.ClusterID(), namespace, data, extraValue)
+	err := scheduler.UpdateProcess(c.ClusterID(), manifest.Namespace, manifest.Data, extraValue)
 	if err != nil {
-		return fmt.Errorf("failed to update process: %v", err)
+		return fmt.Errorf("failed to update process %s: %v", manifest.Name, err)
 	}
 
-	fmt.Printf("success to update process\n")
+	fmt.Printf("success to update process %s\n", manifest.Name)
 	return nil
 }
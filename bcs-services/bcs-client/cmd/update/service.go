@@ -20,27 +20,13 @@ import (
 	"bk-bcs/bcs-services/bcs-client/pkg/scheduler/v4"
 )
 
-func updateService(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
-		return err
-	}
-
-	data, err := c.FileData()
-	if err != nil {
-		return err
-	}
-
-	namespace, err := utils.ParseNamespaceFromJson(data)
-	if err != nil {
-		return err
-	}
-
+func updateService(c *utils.ClientContext, manifest *utils.Manifest) error {
 	scheduler := v4.NewBcsScheduler(utils.GetClientOption())
-	err = scheduler.UpdateService(c.ClusterID(), namespace, data, nil)
+	err := scheduler.UpdateService(c.ClusterID(), manifest.Namespace, manifest.Data, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update service: %v", err)
+		return fmt.Errorf("failed to update service %s: %v", manifest.Name, err)
 	}
 
-	fmt.Printf("success to update service\n")
+	fmt.Printf("success to update service %s\n", manifest.Name)
 	return nil
 }
@@ -26,11 +26,11 @@ func NewUpdateCommand() cli.Command {
 		Flags: []cli.Flag{
 			cli.StringFlag{
 				Name:  "from-file, f",
-				Usage: "read request from file, like myrequest.json",
+				Usage: "read request from file, like myrequest.json or myrequest.yaml, may contain several objects",
 			},
 			cli.StringFlag{
 				Name:  "type, t",
-				Usage: "update type, app/process/service/secret/configmap/deployment",
+				Usage: "update type, app/process/service/secret/configmap/deployment. Kind of object is used if not specified",
 			},
 			cli.StringFlag{
 				Name:  "clusterid",
@@ -52,25 +52,42 @@ func NewUpdateCommand() cli.Command {
 }
 
 func update(c *utils.ClientContext) error {
-	if err := c.MustSpecified(utils.OptionType); err != nil {
+	if err := c.MustSpecified(utils.OptionClusterID); err != nil {
 		return err
 	}
 
-	resourceType := c.String(utils.OptionType)
+	manifests, err := c.Manifests()
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		resourceType := manifest.Kind
+		if c.IsSet(utils.OptionType) {
+			resourceType = c.String(utils.OptionType)
+		}
+
+		if err = updateResource(c, resourceType, manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	switch resourceType {
-	case "app", "application":
-		return updateApplication(c)
-	case "process":
-		return updateProcess(c)
-	case "configmap":
-		return updateConfigMap(c)
-	case "secret":
-		return updateSecret(c)
-	case "service":
-		return updateService(c)
-	case "deploy", "deployment":
-		return updateDeployment(c)
+func updateResource(c *utils.ClientContext, resourceType string, manifest *utils.Manifest) error {
+	switch utils.NormalizeKind(resourceType) {
+	case utils.KindApplication:
+		return updateApplication(c, manifest)
+	case utils.KindProcess:
+		return updateProcess(c, manifest)
+	case utils.KindConfigMap:
+		return updateConfigMap(c, manifest)
+	case utils.KindSecret:
+		return updateSecret(c, manifest)
+	case utils.KindService:
+		return updateService(c, manifest)
+	case utils.KindDeployment:
+		return updateDeployment(c, manifest)
 	default:
 		return fmt.Errorf("invalid type: %s", resourceType)
 	}
@@ -0,0 +1,133 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const (
+	DiffAdded   = "+"
+	DiffRemoved = "-"
+	DiffChanged = "~"
+)
+
+// FieldDiff is the change of a field between live object and the object in file
+type FieldDiff struct {
+	Op   string
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+func (d FieldDiff) String() string {
+	switch d.Op {
+	case DiffAdded:
+		return fmt.Sprintf("%s %s: %s", d.Op, d.Path, diffValue(d.New))
+	case DiffRemoved:
+		return fmt.Sprintf("%s %s: %s", d.Op, d.Path, diffValue(d.Old))
+	}
+	return fmt.Sprintf("%s %s: %s -> %s", d.Op, d.Path, diffValue(d.Old), diffValue(d.New))
+}
+
+// DiffJSON returns the field level changes from live to desired, sorted by path.
+// If desiredOnly is true, fields only existing in live object are ignored, it is used when the live object
+// comes from storage which has more fields than those submitted.
+func DiffJSON(live, desired []byte, desiredOnly bool) ([]FieldDiff, error) {
+	var liveObj, desiredObj interface{}
+	if err := decodeJSON(live, &liveObj); err != nil {
+		return nil, fmt.Errorf("decode live object failed: %v", err)
+	}
+	if err := decodeJSON(desired, &desiredObj); err != nil {
+		return nil, fmt.Errorf("decode object in file failed: %v", err)
+	}
+
+	diffs := make([]FieldDiff, 0)
+	diffValues("", liveObj, desiredObj, desiredOnly, &diffs)
+	sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs, nil
+}
+
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func diffValues(path string, live, desired interface{}, desiredOnly bool, diffs *[]FieldDiff) {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for key, value := range d {
+			liveValue, exist := l[key]
+			if !exist {
+				*diffs = append(*diffs, FieldDiff{Op: DiffAdded, Path: joinPath(path, key), New: value})
+				continue
+			}
+			diffValues(joinPath(path, key), liveValue, value, desiredOnly, diffs)
+		}
+		if desiredOnly {
+			return
+		}
+		for key, value := range l {
+			if _, exist := d[key]; !exist {
+				*diffs = append(*diffs, FieldDiff{Op: DiffRemoved, Path: joinPath(path, key), Old: value})
+			}
+		}
+		return
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			break
+		}
+		for i := range d {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if i >= len(l) {
+				*diffs = append(*diffs, FieldDiff{Op: DiffAdded, Path: itemPath, New: d[i]})
+				continue
+			}
+			diffValues(itemPath, l[i], d[i], desiredOnly, diffs)
+		}
+		for i := len(d); i < len(l); i++ {
+			*diffs = append(*diffs, FieldDiff{Op: DiffRemoved, Path: fmt.Sprintf("%s[%d]", path, i), Old: l[i]})
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(live, desired) {
+		*diffs = append(*diffs, FieldDiff{Op: DiffChanged, Path: path, Old: live, New: desired})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func diffValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,198 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	KindApplication = "application"
+	KindProcess     = "process"
+	KindConfigMap   = "configmap"
+	KindSecret      = "secret"
+	KindService     = "service"
+	KindDeployment  = "deployment"
+)
+
+// Manifest is a resource object read from file
+type Manifest struct {
+	Kind      string
+	Name      string
+	Namespace string
+	// Data is the object encoded in json, it is sent to bcs-api as it is
+	Data []byte
+}
+
+func (m *Manifest) String() string {
+	return fmt.Sprintf("%s %s/%s", m.Kind, m.Namespace, m.Name)
+}
+
+// Instance returns spec.instance of application and deployment, 0 if not specified
+func (m *Manifest) Instance() int {
+	var obj struct {
+		Spec struct {
+			Instance int `json:"instance"`
+		} `json:"spec"`
+	}
+	_ = json.Unmarshal(m.Data, &obj)
+	return obj.Spec.Instance
+}
+
+// NormalizeKind turns the short names of resource type into the kind, such as app into application
+func NormalizeKind(resourceType string) string {
+	switch strings.ToLower(resourceType) {
+	case "app", KindApplication:
+		return KindApplication
+	case "deploy", KindDeployment:
+		return KindDeployment
+	}
+	return strings.ToLower(resourceType)
+}
+
+// Manifests reads all the objects from file specified by --from-file
+func (cc *ClientContext) Manifests() ([]*Manifest, error) {
+	data, err := cc.FileData()
+	if err != nil {
+		return nil, err
+	}
+	return ParseManifests(data)
+}
+
+// ParseManifests parses objects from json or yaml data. json data may contain several objects one after another,
+// and yaml data may contain several documents separated by "---". Every object must have kind, metadata.name and
+// metadata.namespace.
+func ParseManifests(data []byte) ([]*Manifest, error) {
+	var objects []map[string]interface{}
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		objects, err = decodeJSONStream(trimmed)
+	} else {
+		objects, err = decodeYAMLDocuments(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no object found in file")
+	}
+
+	manifests := make([]*Manifest, 0, len(objects))
+	for i, obj := range objects {
+		manifest, err := newManifest(obj)
+		if err != nil {
+			return nil, fmt.Errorf("object %d in file: %v", i+1, err)
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
+
+func newManifest(obj map[string]interface{}) (*Manifest, error) {
+	kind, _ := obj["kind"].(string)
+	if kind == "" {
+		return nil, fmt.Errorf("kind is not specified")
+	}
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	namespace, _ := metadata["namespace"].(string)
+	if name == "" || namespace == "" {
+		return nil, fmt.Errorf("metadata.name and metadata.namespace must be specified")
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{
+		Kind:      NormalizeKind(kind),
+		Name:      name,
+		Namespace: namespace,
+		Data:      data,
+	}, nil
+}
+
+func decodeJSONStream(data []byte) ([]map[string]interface{}, error) {
+	objects := make([]map[string]interface{}, 0)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	for {
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("decode json in file failed, err: %v", err)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+func decodeYAMLDocuments(data []byte) ([]map[string]interface{}, error) {
+	objects := make([]map[string]interface{}, 0)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("decode yaml in file failed, err: %v", err)
+		}
+		// empty document, such as the one before the leading "---"
+		if doc == nil {
+			continue
+		}
+
+		obj, ok := convertYAML(doc).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("decode yaml in file failed, document %d is not an object", len(objects)+1)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// convertYAML converts map[interface{}]interface{} decoded by yaml into map[string]interface{} which can be
+// encoded into json
+func convertYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprintf("%v", key)] = convertYAML(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertYAML(item)
+		}
+		return v
+	}
+	return value
+}
+
+// ToYAML converts json data into yaml
+func ToYAML(data []byte) ([]byte, error) {
+	var obj interface{}
+	if err := yaml.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(obj)
+}
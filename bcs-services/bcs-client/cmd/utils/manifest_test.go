@@ -0,0 +1,138 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package utils
+
+import (
+	"testing"
+)
+
+func TestParseManifests(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		kinds   []string
+		wantErr bool
+	}{
+		{
+			name:  "single json",
+			data:  `{"apiVersion":"v4","kind":"application","metadata":{"name":"app-test","namespace":"defaultGroup"}}`,
+			kinds: []string{KindApplication},
+		},
+		{
+			name: "json stream",
+			data: `{"kind":"configmap","metadata":{"name":"cm","namespace":"ns"}}
+{"kind":"deploy","metadata":{"name":"deploy","namespace":"ns"}}`,
+			kinds: []string{KindConfigMap, KindDeployment},
+		},
+		{
+			name: "multiple yaml documents",
+			data: `---
+# the service
+apiVersion: v4
+kind: service
+metadata:
+  name: svc
+  namespace: ns
+spec:
+  ports:
+  - name: http
+    servicePort: 8080
+---
+kind: app
+metadata:
+  name: app-test
+  namespace: ns
+spec:
+  instance: 2
+`,
+			kinds: []string{KindService, KindApplication},
+		},
+		{
+			name:    "no namespace",
+			data:    "kind: secret\nmetadata:\n  name: s\n",
+			wantErr: true,
+		},
+		{
+			name:    "no kind",
+			data:    "metadata:\n  name: s\n  namespace: ns\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			data:    "---\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		manifests, err := ParseManifests([]byte(test.data))
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.wantErr, err)
+			continue
+		}
+		if len(manifests) != len(test.kinds) {
+			t.Errorf("%s: expected %d manifests, got %d", test.name, len(test.kinds), len(manifests))
+			continue
+		}
+		for i, manifest := range manifests {
+			if manifest.Kind != test.kinds[i] {
+				t.Errorf("%s: expected kind %s, got %s", test.name, test.kinds[i], manifest.Kind)
+			}
+		}
+	}
+}
+
+func TestManifestInstance(t *testing.T) {
+	manifests, err := ParseManifests([]byte("kind: deployment\nmetadata:\n  name: d\n  namespace: ns\nspec:\n  instance: 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := manifests[0].Instance(); got != 3 {
+		t.Errorf("expected instance 3, got %d", got)
+	}
+}
+
+func TestDiffJSON(t *testing.T) {
+	live := `{"kind":"application","metadata":{"name":"a","labels":{"app":"a","old":"x"}},"spec":{"instance":1,"containers":[{"image":"nginx:1.0"},{"image":"sidecar"}]}}`
+	desired := `{"kind":"application","metadata":{"name":"a","labels":{"app":"a","new":"y"}},"spec":{"instance":2,"containers":[{"image":"nginx:1.1"}]}}`
+
+	diffs, err := DiffJSON([]byte(live), []byte(desired), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`+ metadata.labels.new: "y"`,
+		`- metadata.labels.old: "x"`,
+		`~ spec.containers[0].image: "nginx:1.0" -> "nginx:1.1"`,
+		`- spec.containers[1]: {"image":"sidecar"}`,
+		`~ spec.instance: 1 -> 2`,
+	}
+	if len(diffs) != len(expected) {
+		t.Fatalf("expected %d diffs, got %v", len(expected), diffs)
+	}
+	for i, d := range diffs {
+		if d.String() != expected[i] {
+			t.Errorf("diff %d: expected %s, got %s", i, expected[i], d.String())
+		}
+	}
+
+	// fields only in live object are ignored
+	diffs, err = DiffJSON([]byte(live), []byte(`{"metadata":{"name":"a"}}`), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("expected no diff, got %v", diffs)
+	}
+}
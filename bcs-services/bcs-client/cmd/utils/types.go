@@ -35,4 +35,7 @@ const (
 	OptionString        = "string"
 	OptionScalar        = "scalar"
 	OptionAll           = "all"
+	OptionDryRun        = "dry-run"
+	OptionOutput        = "output"
+	OptionWatch         = "watch"
 )
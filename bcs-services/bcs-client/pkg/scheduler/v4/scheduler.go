@@ -18,6 +18,7 @@ import (
 
 	commonTypes "bk-bcs/bcs-common/common/types"
 	"bk-bcs/bcs-mesos/bcs-scheduler/src/mesosproto/mesos"
+	schedulerTypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
 	"bk-bcs/bcs-services/bcs-client/pkg/types"
 	"bk-bcs/bcs-services/bcs-client/pkg/utils"
 )
@@ -52,6 +53,7 @@ type Scheduler interface {
 
 	RescheduleTaskGroup(clusterID, namespace, applicationName, taskGroupName string) error
 
+	GetDeployment(clusterID, namespace, name string) (*schedulerTypes.Deployment, error)
 	ResumeDeployment(clusterID, namespace, name string) error
 	CancelDeployment(clusterID, namespace, name string) error
 	PauseDeployment(clusterID, namespace, name string) error
@@ -65,12 +67,24 @@ type Scheduler interface {
 	DeleteAgentSetting(clusterID string, ipList []string) error
 	EnableAgent(clusterID string, ipList []string) error
 	DisableAgent(clusterID string, ipList []string) error
+	DrainAgent(clusterID, ip string, option *commonTypes.AgentDrainOption) (*commonTypes.BcsAgentDrain, error)
+	GetAgentDrain(clusterID, ip string) (*commonTypes.BcsAgentDrain, error)
+	UncordonAgent(clusterID, ip string) error
 
 	GetApplicationDefinition(clusterID, namespace, name string) (*commonTypes.ReplicaController, error)
 	GetProcessDefinition(clusterID, namespace, name string) (*commonTypes.ReplicaController, error)
 	GetDeploymentDefinition(clusterID, namespace, name string) (*commonTypes.BcsDeployment, error)
+	GetApplicationDefinitionData(clusterID, namespace, name string) ([]byte, error)
+	GetDeploymentDefinitionData(clusterID, namespace, name string) ([]byte, error)
+
+	GetApplicationRecommendation(clusterID, namespace, name string) (*schedulerTypes.ResourceRecommendation, error)
+	GetDeploymentRecommendation(clusterID, namespace, name string) (*schedulerTypes.ResourceRecommendation, error)
 
 	GetOffer(clusterID string) ([]*mesos.Offer, error)
+
+	ListApplications(clusterID, namespace string) ([]*schedulerTypes.Application, error)
+	ListApplicationTaskGroups(clusterID, namespace, name string) ([]*schedulerTypes.TaskGroup, error)
+	Watch(clusterID, namespace, resourceType string, opts *WatchOptions, stopCh <-chan struct{}) (<-chan *WatchEvent, error)
 }
 
 const (
@@ -81,16 +95,23 @@ const (
 	BcsSchedulerResumeDeploymentURI   = "%s/bcsapi/v4/scheduler/mesos/namespaces/%s/deployments/%s/resumeupdate"
 	BcsSchedulerCancelDeploymentURI   = "%s/bcsapi/v4/scheduler/mesos/namespaces/%s/deployments/%s/cancelupdate"
 	BcsSchedulerPauseDeploymentURI    = "%s/bcsapi/v4/scheduler/mesos/namespaces/%s/deployments/%s/pauseupdate"
+	BcsSchedulerDeploymentURI         = "%s/bcsapi/v4/scheduler/mesos/namespaces/%s/deployments/%s"
+	BcsSchedulerApplicationsURI       = "%s/bcsapi/v4/scheduler/mesos/namespaces/%s/applications"
+	BcsSchedulerTaskGroupsURI         = "%s/bcsapi/v4/scheduler/mesos/namespaces/%s/applications/%s/taskgroups"
 	BcsSchedulerClusterResourceURI    = "%s/bcsapi/v4/scheduler/mesos/cluster/resources"
 	BcsSchedulerAgentSettingURI       = "%s/bcsapi/v4/scheduler/mesos/agentsettings/?ips=%s"
 	BcsSchedulerUpdateAgentSettingURI = "%s/bcsapi/v4/scheduler/mesos/agentsettings/update"
 	BcsSchedulerSetAgentSettingURI    = "%s/bcsapi/v4/scheduler/mesos/agentsettings"
 	BcsSchedulerEnableAgentURI        = "%s/bcsapi/v4/scheduler/mesos/agentsettings/enable?ips=%s"
 	BcsSchedulerDisableAgentURI       = "%s/bcsapi/v4/scheduler/mesos/agentsettings/disable?ips=%s"
+	BcsSchedulerDrainAgentURI         = "%s/bcsapi/v4/scheduler/mesos/agentsettings/%s/drain"
+	BcsSchedulerUncordonAgentURI      = "%s/bcsapi/v4/scheduler/mesos/agentsettings/%s/uncordon"
 	BcsSchedulerRescheduleURI         = "%s/bcsapi/v4/scheduler/mesos/namespaces/%s/applications/%s/taskgroups/%s/rescheduler"
 	BcsSchedulerOfferURI              = "%s/bcsapi/v4/scheduler/mesos/cluster/current/offers"
 	BcsSchedulerAppDefinitionURI      = "%s/bcsapi/v4/scheduler/mesos/definition/application/%s/%s"
 	BcsSchedulerDeployDefinitionURI   = "%s/bcsapi/v4/scheduler/mesos/definition/deployment/%s/%s"
+	BcsSchedulerAppRecommendURI       = "%s/bcsapi/v4/scheduler/mesos/recommendation/application/%s/%s"
+	BcsSchedulerDeployRecommendURI    = "%s/bcsapi/v4/scheduler/mesos/recommendation/deployment/%s/%s"
 )
 
 type bcsScheduler struct {
@@ -58,6 +58,18 @@ func (bs *bcsScheduler) DisableAgent(clusterID string, ipList []string) error {
 	return bs.disableAgent(clusterID, ipList)
 }
 
+func (bs *bcsScheduler) DrainAgent(clusterID, ip string, option *commonTypes.AgentDrainOption) (*commonTypes.BcsAgentDrain, error) {
+	return bs.drainAgent(clusterID, ip, option)
+}
+
+func (bs *bcsScheduler) GetAgentDrain(clusterID, ip string) (*commonTypes.BcsAgentDrain, error) {
+	return bs.getAgentDrain(clusterID, ip)
+}
+
+func (bs *bcsScheduler) UncordonAgent(clusterID, ip string) error {
+	return bs.uncordonAgent(clusterID, ip)
+}
+
 func (bs *bcsScheduler) listAgentInfo(clusterID string, ipList []string) ([]*commonTypes.BcsClusterAgentInfo, error) {
 	resp, err := bs.requester.Do(
 		fmt.Sprintf(BcsSchedulerClusterResourceURI, bs.bcsApiAddress),
@@ -263,3 +275,84 @@ func (bs *bcsScheduler) disableAgent(clusterID string, ipList []string) error {
 
 	return nil
 }
+
+func (bs *bcsScheduler) drainAgent(clusterID, ip string, option *commonTypes.AgentDrainOption) (*commonTypes.BcsAgentDrain, error) {
+	var data []byte
+	if err := codec.EncJson(option, &data); err != nil {
+		return nil, err
+	}
+
+	resp, err := bs.requester.Do(
+		fmt.Sprintf(BcsSchedulerDrainAgentURI, bs.bcsApiAddress, ip),
+		http.MethodPost,
+		data,
+		getClusterIDHeader(clusterID),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	code, msg, data, err := parseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != 0 {
+		return nil, fmt.Errorf("drain agent failed: %s", msg)
+	}
+
+	var result commonTypes.BcsAgentDrain
+	err = codec.DecJson(data, &result)
+	return &result, err
+}
+
+func (bs *bcsScheduler) getAgentDrain(clusterID, ip string) (*commonTypes.BcsAgentDrain, error) {
+	resp, err := bs.requester.Do(
+		fmt.Sprintf(BcsSchedulerDrainAgentURI, bs.bcsApiAddress, ip),
+		http.MethodGet,
+		nil,
+		getClusterIDHeader(clusterID),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	code, msg, data, err := parseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != 0 {
+		return nil, fmt.Errorf("get agent drain failed: %s", msg)
+	}
+
+	var result *commonTypes.BcsAgentDrain
+	err = codec.DecJson(data, &result)
+	return result, err
+}
+
+func (bs *bcsScheduler) uncordonAgent(clusterID, ip string) error {
+	resp, err := bs.requester.Do(
+		fmt.Sprintf(BcsSchedulerUncordonAgentURI, bs.bcsApiAddress, ip),
+		http.MethodPost,
+		nil,
+		getClusterIDHeader(clusterID),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	code, msg, _, err := parseResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("uncordon agent failed: %s", msg)
+	}
+
+	return nil
+}
@@ -14,13 +14,18 @@
 package v4
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"bk-bcs/bcs-common/common"
 	"bk-bcs/bcs-common/common/codec"
 	commonTypes "bk-bcs/bcs-common/common/types"
 )
 
+// ErrDefinitionNotExist is returned when the application or deployment does not exist in scheduler
+var ErrDefinitionNotExist = errors.New("definition not exist")
+
 func (bs *bcsScheduler) GetApplicationDefinition(clusterID, namespace, name string) (*commonTypes.ReplicaController, error) {
 	return bs.getApplicationDefinition(clusterID, namespace, name)
 }
@@ -33,6 +38,42 @@ func (bs *bcsScheduler) GetDeploymentDefinition(clusterID, namespace, name strin
 	return bs.getDeploymentDefinition(clusterID, namespace, name)
 }
 
+func (bs *bcsScheduler) GetApplicationDefinitionData(clusterID, namespace, name string) ([]byte, error) {
+	return bs.getDefinitionData(clusterID, fmt.Sprintf(BcsSchedulerAppDefinitionURI, bs.bcsApiAddress, namespace, name))
+}
+
+func (bs *bcsScheduler) GetDeploymentDefinitionData(clusterID, namespace, name string) ([]byte, error) {
+	return bs.getDefinitionData(clusterID, fmt.Sprintf(BcsSchedulerDeployDefinitionURI, bs.bcsApiAddress, namespace, name))
+}
+
+// getDefinitionData returns the raw json of application or deployment which was submitted at last
+func (bs *bcsScheduler) getDefinitionData(clusterID, uri string) ([]byte, error) {
+	resp, err := bs.requester.Do(
+		uri,
+		http.MethodGet,
+		nil,
+		getClusterIDHeader(clusterID),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	code, msg, data, err := parseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != 0 {
+		if code == common.BcsErrMesosSchedNotFound {
+			return nil, ErrDefinitionNotExist
+		}
+		return nil, fmt.Errorf("get definition failed: %s", msg)
+	}
+
+	return data, nil
+}
+
 func (bs *bcsScheduler) getApplicationDefinition(clusterID, namespace, name string) (*commonTypes.ReplicaController, error) {
 	resp, err := bs.requester.Do(
 		fmt.Sprintf(BcsSchedulerAppDefinitionURI, bs.bcsApiAddress, namespace, name),
@@ -14,10 +14,22 @@
 package v4
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+
+	"bk-bcs/bcs-common/common"
+	"bk-bcs/bcs-common/common/codec"
+	schedulerTypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
 )
 
+// ErrDeploymentNotExist is returned when the deployment does not exist in scheduler
+var ErrDeploymentNotExist = errors.New("deployment not exist")
+
+func (bs *bcsScheduler) GetDeployment(clusterID, namespace, name string) (*schedulerTypes.Deployment, error) {
+	return bs.getDeployment(clusterID, namespace, name)
+}
+
 func (bs *bcsScheduler) ResumeDeployment(clusterID, namespace, name string) error {
 	return bs.resumeDeployment(clusterID, namespace, name)
 }
@@ -30,6 +42,38 @@ func (bs *bcsScheduler) PauseDeployment(clusterID, namespace, name string) error
 	return bs.pauseDeployment(clusterID, namespace, name)
 }
 
+// getDeployment returns the deployment with the status of rolling update from scheduler
+func (bs *bcsScheduler) getDeployment(clusterID, namespace, name string) (*schedulerTypes.Deployment, error) {
+	resp, err := bs.requester.Do(
+		fmt.Sprintf(BcsSchedulerDeploymentURI, bs.bcsApiAddress, namespace, name),
+		http.MethodGet,
+		nil,
+		getClusterIDHeader(clusterID),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	code, msg, data, err := parseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if code == common.BcsErrMesosSchedNotFound {
+		return nil, ErrDeploymentNotExist
+	}
+	if code != 0 {
+		return nil, fmt.Errorf("get deployment failed: %s", msg)
+	}
+
+	var deployment schedulerTypes.Deployment
+	if err = codec.DecJson(data, &deployment); err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
 func (bs *bcsScheduler) resumeDeployment(clusterID, namespace, name string) error {
 	resp, err := bs.requester.Do(
 		fmt.Sprintf(BcsSchedulerResumeDeploymentURI, bs.bcsApiAddress, namespace, name),
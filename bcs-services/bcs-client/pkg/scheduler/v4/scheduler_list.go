@@ -0,0 +1,82 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package v4
+
+import (
+	"fmt"
+	"net/http"
+
+	"bk-bcs/bcs-common/common/codec"
+	schedulerTypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+)
+
+func (bs *bcsScheduler) ListApplications(clusterID, namespace string) ([]*schedulerTypes.Application, error) {
+	return bs.listApplications(clusterID, namespace)
+}
+
+func (bs *bcsScheduler) ListApplicationTaskGroups(clusterID, namespace, name string) ([]*schedulerTypes.TaskGroup, error) {
+	return bs.listApplicationTaskGroups(clusterID, namespace, name)
+}
+
+func (bs *bcsScheduler) listApplications(clusterID, namespace string) ([]*schedulerTypes.Application, error) {
+	resp, err := bs.requester.Do(
+		fmt.Sprintf(BcsSchedulerApplicationsURI, bs.bcsApiAddress, namespace),
+		http.MethodGet,
+		nil,
+		getClusterIDHeader(clusterID),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	code, msg, data, err := parseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != 0 {
+		return nil, fmt.Errorf("list applications failed: %s", msg)
+	}
+
+	var result []*schedulerTypes.Application
+	err = codec.DecJson(data, &result)
+	return result, err
+}
+
+func (bs *bcsScheduler) listApplicationTaskGroups(clusterID, namespace, name string) ([]*schedulerTypes.TaskGroup, error) {
+	resp, err := bs.requester.Do(
+		fmt.Sprintf(BcsSchedulerTaskGroupsURI, bs.bcsApiAddress, namespace, name),
+		http.MethodGet,
+		nil,
+		getClusterIDHeader(clusterID),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	code, msg, data, err := parseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != 0 {
+		return nil, fmt.Errorf("list taskgroups failed: %s", msg)
+	}
+
+	var result []*schedulerTypes.TaskGroup
+	err = codec.DecJson(data, &result)
+	return result, err
+}
@@ -0,0 +1,57 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package v4
+
+import (
+	"fmt"
+	"net/http"
+
+	"bk-bcs/bcs-common/common/codec"
+	schedulerTypes "bk-bcs/bcs-mesos/bcs-scheduler/src/types"
+)
+
+func (bs *bcsScheduler) GetApplicationRecommendation(clusterID, namespace, name string) (*schedulerTypes.ResourceRecommendation, error) {
+	return bs.getRecommendation(clusterID, fmt.Sprintf(BcsSchedulerAppRecommendURI, bs.bcsApiAddress, namespace, name))
+}
+
+func (bs *bcsScheduler) GetDeploymentRecommendation(clusterID, namespace, name string) (*schedulerTypes.ResourceRecommendation, error) {
+	return bs.getRecommendation(clusterID, fmt.Sprintf(BcsSchedulerDeployRecommendURI, bs.bcsApiAddress, namespace, name))
+}
+
+// getRecommendation returns the resource recommendation computed by scheduler from usage history
+func (bs *bcsScheduler) getRecommendation(clusterID, uri string) (*schedulerTypes.ResourceRecommendation, error) {
+	resp, err := bs.requester.Do(
+		uri,
+		http.MethodGet,
+		nil,
+		getClusterIDHeader(clusterID),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	code, msg, data, err := parseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != 0 {
+		return nil, fmt.Errorf("get recommendation failed: %s", msg)
+	}
+
+	var result schedulerTypes.ResourceRecommendation
+	err = codec.DecJson(data, &result)
+	return &result, err
+}
@@ -0,0 +1,296 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package v4
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"bk-bcs/bcs-common/common/codec"
+)
+
+const (
+	// BcsStorageWatchDynamicURI is the server-side watch of bcs-storage, it only triggers a refresh from scheduler
+	BcsStorageWatchDynamicURI = "%s/bcsapi/v4/storage/dynamic/watch/%s/%s"
+
+	// DefaultWatchInterval is the max time between two refreshes of a watch
+	DefaultWatchInterval = 3 * time.Second
+
+	// storage event type for a broken watch, see bcs-storage operator.EventType
+	storageEventBreak = -1
+)
+
+// resource types supported by Watch, same as the dynamic types of bcs-storage
+const (
+	WatchTypeApplication = "application"
+	WatchTypeTaskGroup   = "taskgroup"
+	WatchTypeDeployment  = "deployment"
+)
+
+//WatchEventType is the kind of change reported by Watch
+type WatchEventType string
+
+const (
+	WatchEventAdded    WatchEventType = "ADDED"
+	WatchEventModified WatchEventType = "MODIFIED"
+	WatchEventDeleted  WatchEventType = "DELETED"
+)
+
+//WatchEvent is a change of one resource, Name is the id of the resource and Data is its raw json
+//returned by scheduler, for DELETED event it is the last known data
+type WatchEvent struct {
+	Type      WatchEventType
+	Namespace string
+	Name      string
+	Data      []byte
+}
+
+//WatchOptions options for Watch
+type WatchOptions struct {
+	// Name follows only the resource of this id if not empty, it is required for deployment
+	Name string
+	// Interval is the max time between two refreshes, DefaultWatchInterval if zero
+	Interval time.Duration
+}
+
+//Watch follows the resources of resourceType in namespace. It sends ADDED events for all existing
+//resources first, then every change found later. Resources are always read from scheduler, refreshes
+//are triggered by the server-side watch of bcs-storage when it is available, otherwise by polling
+//every Interval. The returned channel is closed after stopCh is closed.
+func (bs *bcsScheduler) Watch(clusterID, namespace, resourceType string, opts *WatchOptions, stopCh <-chan struct{}) (<-chan *WatchEvent, error) {
+	if opts == nil {
+		opts = &WatchOptions{}
+	}
+	if resourceType == WatchTypeDeployment && opts.Name == "" {
+		return nil, fmt.Errorf("name of deployment to watch is required")
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	last, err := bs.snapshot(clusterID, namespace, resourceType, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *WatchEvent, 64)
+	go func() {
+		defer close(ch)
+
+		if !sendWatchEvents(ch, diffSnapshot(nil, last), stopCh) {
+			return
+		}
+
+		serverWatch := true
+		for {
+			if serverWatch {
+				serverWatch = bs.waitServerEvent(clusterID, resourceType, interval)
+			} else {
+				select {
+				case <-stopCh:
+					return
+				case <-time.After(interval):
+				}
+			}
+
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+
+			current, err := bs.snapshot(clusterID, namespace, resourceType, opts.Name)
+			if err != nil {
+				// keep the last snapshot and try again in next round
+				continue
+			}
+
+			if !sendWatchEvents(ch, diffSnapshot(last, current), stopCh) {
+				return
+			}
+			last = current
+		}
+	}()
+
+	return ch, nil
+}
+
+//waitServerEvent blocks until the storage reports a change of resourceType or interval passed.
+//It returns false if the server-side watch is not available, and then the caller should poll.
+func (bs *bcsScheduler) waitServerEvent(clusterID, resourceType string, interval time.Duration) bool {
+	var data []byte
+	watchOpts := map[string]interface{}{
+		"maxEvents": 1,
+		"timeout":   interval,
+	}
+	if err := codec.EncJson(watchOpts, &data); err != nil {
+		return false
+	}
+
+	begin := time.Now()
+	resp, err := bs.requester.Do(
+		fmt.Sprintf(BcsStorageWatchDynamicURI, bs.bcsApiAddress, clusterID, resourceType),
+		http.MethodPost,
+		data,
+	)
+	if err != nil || !isStorageEventStream(resp) {
+		return false
+	}
+
+	// the watch broke immediately, storage can not watch this resource
+	if time.Since(begin) < interval/2 && !hasStorageChange(resp) {
+		return false
+	}
+	return true
+}
+
+type storageEvent struct {
+	Type *int `json:"type"`
+}
+
+func decodeStorageEvents(resp []byte) ([]storageEvent, error) {
+	var events []storageEvent
+	decoder := json.NewDecoder(bytes.NewReader(resp))
+	for {
+		var e storageEvent
+		if err := decoder.Decode(&e); err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+}
+
+func isStorageEventStream(resp []byte) bool {
+	events, err := decodeStorageEvents(resp)
+	if err != nil || len(events) == 0 {
+		return false
+	}
+	for _, e := range events {
+		if e.Type == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func hasStorageChange(resp []byte) bool {
+	events, _ := decodeStorageEvents(resp)
+	for _, e := range events {
+		if e.Type != nil && *e.Type != storageEventBreak {
+			return true
+		}
+	}
+	return false
+}
+
+//snapshot reads the resources from scheduler and index them by id
+func (bs *bcsScheduler) snapshot(clusterID, namespace, resourceType, name string) (map[string]*WatchEvent, error) {
+	objects := make(map[string]interface{})
+	switch resourceType {
+	case WatchTypeApplication:
+		apps, err := bs.listApplications(clusterID, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range apps {
+			objects[app.ID] = app
+		}
+	case WatchTypeTaskGroup:
+		apps, err := bs.listApplications(clusterID, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range apps {
+			taskGroups, err := bs.listApplicationTaskGroups(clusterID, namespace, app.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, taskGroup := range taskGroups {
+				objects[taskGroup.ID] = taskGroup
+			}
+		}
+	case WatchTypeDeployment:
+		deployment, err := bs.getDeployment(clusterID, namespace, name)
+		if err == ErrDeploymentNotExist {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects[name] = deployment
+	default:
+		return nil, fmt.Errorf("watch of %s is not supported", resourceType)
+	}
+
+	result := make(map[string]*WatchEvent, len(objects))
+	for id, obj := range objects {
+		if name != "" && id != name {
+			continue
+		}
+		var data []byte
+		if err := codec.EncJson(obj, &data); err != nil {
+			return nil, err
+		}
+		result[id] = &WatchEvent{Namespace: namespace, Name: id, Data: data}
+	}
+	return result, nil
+}
+
+//diffSnapshot returns the events turning last into current, sorted by namespace/name
+func diffSnapshot(last, current map[string]*WatchEvent) []*WatchEvent {
+	keys := make([]string, 0, len(last)+len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range last {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var events []*WatchEvent
+	for _, key := range keys {
+		old, inLast := last[key]
+		cur, inCurrent := current[key]
+		switch {
+		case !inLast:
+			events = append(events, &WatchEvent{Type: WatchEventAdded, Namespace: cur.Namespace, Name: cur.Name, Data: cur.Data})
+		case !inCurrent:
+			events = append(events, &WatchEvent{Type: WatchEventDeleted, Namespace: old.Namespace, Name: old.Name, Data: old.Data})
+		case !bytes.Equal(old.Data, cur.Data):
+			events = append(events, &WatchEvent{Type: WatchEventModified, Namespace: cur.Namespace, Name: cur.Name, Data: cur.Data})
+		}
+	}
+	return events
+}
+
+func sendWatchEvents(ch chan<- *WatchEvent, events []*WatchEvent, stopCh <-chan struct{}) bool {
+	for _, e := range events {
+		select {
+		case ch <- e:
+		case <-stopCh:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package v4
+
+import (
+	"testing"
+)
+
+func TestDiffSnapshot(t *testing.T) {
+	last := map[string]*WatchEvent{
+		"ns/a": {Namespace: "ns", Name: "a", Data: []byte(`{"status":"Running"}`)},
+		"ns/b": {Namespace: "ns", Name: "b", Data: []byte(`{"status":"Running"}`)},
+		"ns/c": {Namespace: "ns", Name: "c", Data: []byte(`{"status":"Staging"}`)},
+	}
+	current := map[string]*WatchEvent{
+		"ns/a": {Namespace: "ns", Name: "a", Data: []byte(`{"status":"Running"}`)},
+		"ns/c": {Namespace: "ns", Name: "c", Data: []byte(`{"status":"Running"}`)},
+		"ns/d": {Namespace: "ns", Name: "d", Data: []byte(`{"status":"Staging"}`)},
+	}
+
+	tests := []struct {
+		name    string
+		last    map[string]*WatchEvent
+		current map[string]*WatchEvent
+		expect  []string
+	}{
+		{
+			name:    "initial",
+			current: last,
+			expect:  []string{"ADDED a", "ADDED b", "ADDED c"},
+		},
+		{
+			name:    "no change",
+			last:    last,
+			current: last,
+		},
+		{
+			name:    "changes",
+			last:    last,
+			current: current,
+			expect:  []string{"DELETED b", "MODIFIED c", "ADDED d"},
+		},
+	}
+
+	for _, test := range tests {
+		events := diffSnapshot(test.last, test.current)
+		if len(events) != len(test.expect) {
+			t.Errorf("%s: expect %d events, got %d", test.name, len(test.expect), len(events))
+			continue
+		}
+		for i, e := range events {
+			if got := string(e.Type) + " " + e.Name; got != test.expect[i] {
+				t.Errorf("%s: expect event %s, got %s", test.name, test.expect[i], got)
+			}
+		}
+	}
+}
+
+func TestIsStorageEventStream(t *testing.T) {
+	tests := []struct {
+		resp   string
+		stream bool
+		change bool
+	}{
+		{resp: `{"type":-1,"value":null}`, stream: true},
+		{resp: `{"type":3,"value":{"_id":"x"}}{"type":-1,"value":null}`, stream: true, change: true},
+		{resp: `{"result":false,"code":404,"message":"not found"}`},
+		{resp: `not json`},
+	}
+
+	for _, test := range tests {
+		if got := isStorageEventStream([]byte(test.resp)); got != test.stream {
+			t.Errorf("%s: expect stream %v, got %v", test.resp, test.stream, got)
+		}
+		if got := hasStorageChange([]byte(test.resp)); got != test.change {
+			t.Errorf("%s: expect change %v, got %v", test.resp, test.change, got)
+		}
+	}
+}
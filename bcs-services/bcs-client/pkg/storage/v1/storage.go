@@ -14,11 +14,13 @@
 package v1
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
+	"bk-bcs/bcs-common/common"
 	"bk-bcs/bcs-common/common/codec"
 	"bk-bcs/bcs-services/bcs-client/pkg/types"
 	"bk-bcs/bcs-services/bcs-client/pkg/utils"
@@ -161,6 +163,9 @@ func (bs *bcsStorage) ListNamespace(clusterID string, condition url.Values) ([]s
 	return result, err
 }
 
+// ErrResourceNotExist is returned when inspecting a resource which does not exist in storage
+var ErrResourceNotExist = errors.New("resource not exist")
+
 func (bs *bcsStorage) InspectApplication(clusterID, namespace, name string) (*ApplicationSet, error) {
 	data, err := bs.inspectResource(clusterID, namespace, BcsStorageDynamicTypeApplication, name)
 	if err != nil {
@@ -307,6 +312,9 @@ func (bs *bcsStorage) inspectResource(clusterID, namespace, resourceType, name s
 		return nil, err
 	}
 
+	if code == common.BcsErrStorageResourceNotExist {
+		return nil, ErrResourceNotExist
+	}
 	if code != 0 {
 		return nil, fmt.Errorf("inspect dynamic %s failed: %s", resourceType, msg)
 	}
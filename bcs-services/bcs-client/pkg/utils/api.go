@@ -17,10 +17,17 @@ import (
 	"bk-bcs/bcs-common/common/http"
 	"bk-bcs/bcs-common/common/http/httpclient"
 	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	gohttp "net/http"
 )
 
 type ApiRequester interface {
 	Do(uri, method string, data []byte, header ...*http.HeaderSet) ([]byte, error)
+	// DoStream sends body as a stream and returns the response body without reading it,
+	// it is used for long-lived responses such as logs, the caller must close the returned body
+	DoStream(uri, method string, body io.Reader, header ...*http.HeaderSet) (io.ReadCloser, error)
 }
 
 func NewApiRequester(clientSSL *tls.Config, bcsToken string) ApiRequester {
@@ -52,3 +59,31 @@ func (b *bcsApiRequester) Do(uri, method string, data []byte, header ...*http.He
 
 	return httpCli.Request(uri, method, nil, data)
 }
+
+func (b *bcsApiRequester) DoStream(uri, method string, body io.Reader, header ...*http.HeaderSet) (io.ReadCloser, error) {
+	req, err := gohttp.NewRequest(method, uri, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Bcs-User-Token", b.bcsToken)
+	for _, h := range header {
+		req.Header.Set(h.Key, h.Value)
+	}
+
+	client := &gohttp.Client{}
+	if b.clientSSL != nil {
+		client.Transport = &gohttp.Transport{TLSClientConfig: b.clientSSL}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < gohttp.StatusOK || resp.StatusCode >= gohttp.StatusMultipleChoices {
+		defer resp.Body.Close()
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request %s failed, status %s: %s", uri, resp.Status, string(msg))
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,47 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package v1
+
+//CreateExecReq is the request of create_exec of consoleproxy
+type CreateExecReq struct {
+	ContainerID string   `json:"container_id,omitempty"`
+	Cmd         []string `json:"cmd,omitempty"`
+	User        string   `json:"user,omitempty"`
+}
+
+//ResizeExecReq is the request of resize_exec of consoleproxy
+type ResizeExecReq struct {
+	ExecID string `json:"exec_id,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+//LogsOptions options for container logs
+type LogsOptions struct {
+	ContainerID string
+	Follow      bool
+	// Since is unix timestamp in seconds, 0 for all logs
+	Since      int64
+	Tail       string
+	Timestamps bool
+}
+
+type createExecResp struct {
+	ID  string `json:"Id"`
+	Msg string `json:"msg"`
+}
+
+type errorResp struct {
+	Msg string `json:"msg"`
+}
@@ -0,0 +1,189 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package v1
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"bk-bcs/bcs-common/common/codec"
+	"bk-bcs/bcs-services/bcs-client/pkg/types"
+	"bk-bcs/bcs-services/bcs-client/pkg/utils"
+
+	"github.com/gorilla/websocket"
+)
+
+//WebConsole operates the containers through bcs-consoleproxy on the agents, proxied by bcs-api
+type WebConsole interface {
+	CreateExec(hostIP string, req *CreateExecReq) (string, error)
+	StartExec(hostIP, execID string) (*websocket.Conn, error)
+	ResizeExec(hostIP, execID string, width, height int) error
+
+	Logs(hostIP string, opts *LogsOptions) (io.ReadCloser, error)
+	CopyFromContainer(hostIP, containerID, path string) (io.ReadCloser, error)
+	CopyToContainer(hostIP, containerID, path string, tar io.Reader) error
+}
+
+const (
+	BcsWebConsoleURI = "%s/bcsapi/v1/webconsole/%s?%s"
+
+	//exitCodeCloseReason is the reason of close message sent by consoleproxy when exec ends
+	exitCodeCloseReason = "exit code %d"
+)
+
+type bcsWebConsole struct {
+	bcsApiAddress string
+	options       types.ClientOptions
+	requester     utils.ApiRequester
+
+	//clusterID and namespace of the containers, bcs-api authorizes the operations with them
+	clusterID string
+	namespace string
+}
+
+func NewBcsWebConsole(options types.ClientOptions, clusterID, namespace string) WebConsole {
+	return &bcsWebConsole{
+		bcsApiAddress: options.BcsApiAddress,
+		options:       options,
+		requester:     utils.NewApiRequester(options.ClientSSL, options.BcsToken),
+		clusterID:     clusterID,
+		namespace:     namespace,
+	}
+}
+
+func (bw *bcsWebConsole) CreateExec(hostIP string, req *CreateExecReq) (string, error) {
+	var data []byte
+	if err := codec.EncJson(req, &data); err != nil {
+		return "", err
+	}
+
+	resp, err := bw.requester.Do(bw.uri("create_exec", hostIP, nil), http.MethodPost, data)
+	if err != nil {
+		return "", err
+	}
+
+	var result createExecResp
+	if err = codec.DecJson(resp, &result); err != nil {
+		return "", fmt.Errorf("create exec failed: %s", strings.TrimSpace(string(resp)))
+	}
+	if result.ID == "" {
+		return "", fmt.Errorf("create exec failed: %s", result.Msg)
+	}
+	return result.ID, nil
+}
+
+func (bw *bcsWebConsole) StartExec(hostIP, execID string) (*websocket.Conn, error) {
+	uri := bw.uri("start_exec", hostIP, url.Values{"exec_id": []string{execID}})
+	// bcs-api address is http(s)://, websocket needs ws(s)://
+	uri = "ws" + strings.TrimPrefix(uri, "http")
+
+	dialer := &websocket.Dialer{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: bw.options.ClientSSL,
+	}
+	header := http.Header{}
+	header.Set("X-Bcs-User-Token", bw.options.BcsToken)
+
+	conn, resp, err := dialer.Dial(uri, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("start exec failed, status %s: %v", resp.Status, err)
+		}
+		return nil, fmt.Errorf("start exec failed: %v", err)
+	}
+	return conn, nil
+}
+
+func (bw *bcsWebConsole) ResizeExec(hostIP, execID string, width, height int) error {
+	var data []byte
+	if err := codec.EncJson(&ResizeExecReq{ExecID: execID, Width: width, Height: height}, &data); err != nil {
+		return err
+	}
+
+	resp, err := bw.requester.Do(bw.uri("resize_exec", hostIP, nil), http.MethodPost, data)
+	if err != nil {
+		return err
+	}
+	return parseErrorResp("resize exec", resp)
+}
+
+func (bw *bcsWebConsole) Logs(hostIP string, opts *LogsOptions) (io.ReadCloser, error) {
+	values := url.Values{}
+	values.Set("container_id", opts.ContainerID)
+	values.Set("follow", strconv.FormatBool(opts.Follow))
+	values.Set("timestamps", strconv.FormatBool(opts.Timestamps))
+	if opts.Since > 0 {
+		values.Set("since", strconv.FormatInt(opts.Since, 10))
+	}
+	if opts.Tail != "" {
+		values.Set("tail", opts.Tail)
+	}
+
+	return bw.requester.DoStream(bw.uri("logs", hostIP, values), http.MethodGet, nil)
+}
+
+func (bw *bcsWebConsole) CopyFromContainer(hostIP, containerID, path string) (io.ReadCloser, error) {
+	values := url.Values{}
+	values.Set("container_id", containerID)
+	values.Set("path", path)
+
+	return bw.requester.DoStream(bw.uri("copy_from", hostIP, values), http.MethodGet, nil)
+}
+
+func (bw *bcsWebConsole) CopyToContainer(hostIP, containerID, path string, tar io.Reader) error {
+	values := url.Values{}
+	values.Set("container_id", containerID)
+	values.Set("path", path)
+
+	body, err := bw.requester.DoStream(bw.uri("copy_to", hostIP, values), http.MethodPut, tar)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+func (bw *bcsWebConsole) uri(action, hostIP string, values url.Values) string {
+	if values == nil {
+		values = url.Values{}
+	}
+	values.Set("host_ip", hostIP)
+	values.Set("cluster_id", bw.clusterID)
+	values.Set("namespace", bw.namespace)
+	return fmt.Sprintf(BcsWebConsoleURI, bw.bcsApiAddress, action, values.Encode())
+}
+
+//ExitCode returns the exit code of exec from the close message of consoleproxy, false if it has none
+func ExitCode(err *websocket.CloseError) (int, bool) {
+	var code int
+	if _, scanErr := fmt.Sscanf(err.Text, exitCodeCloseReason, &code); scanErr != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+//parseErrorResp parses the response of consoleproxy which is null for success or {"msg": "..."} for failure
+func parseErrorResp(action string, resp []byte) error {
+	var result errorResp
+	if err := codec.DecJson(resp, &result); err != nil {
+		return fmt.Errorf("%s failed: %s", action, strings.TrimSpace(string(resp)))
+	}
+	if result.Msg != "" {
+		return fmt.Errorf("%s failed: %s", action, result.Msg)
+	}
+	return nil
+}
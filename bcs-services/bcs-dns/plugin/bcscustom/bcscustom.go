@@ -48,6 +48,7 @@ type BcsCustom struct {
 	SvrTLS      TLS
 
 	endpoints []string // Stored here as well, to aid in testing.
+	policies  *policyManager
 }
 
 // Services implements the coredns.plugin.ServiceBackend interface.
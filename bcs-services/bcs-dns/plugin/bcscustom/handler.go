@@ -48,6 +48,9 @@ func (bc *BcsCustom) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *dns.
 	if interceptor.Msg != nil && len(interceptor.Msg.Answer) != 0 {
 		m.Answer = append(m.Answer, interceptor.Msg.Answer...)
 		m.Extra = append(m.Extra, interceptor.Msg.Extra...)
+		if state.QType() == dns.TypeA || state.QType() == dns.TypeAAAA {
+			m.Answer = bc.applyPolicy(state, m.Answer)
+		}
 	} else {
 		result, err := bc.Lookup(state, state.Name(), state.QType())
 		if err != nil {
@@ -69,5 +72,38 @@ func (bc *BcsCustom) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *dns.
 	return dns.RcodeSuccess, nil
 }
 
+// applyPolicy keep A/AAAA records selected by domain policy
+func (bc *BcsCustom) applyPolicy(state request.Request, answer []dns.RR) []dns.RR {
+	if bc.policies == nil {
+		return answer
+	}
+	policy, status := bc.policies.getPolicy(state.Name())
+	if policy == nil {
+		return answer
+	}
+	services, err := bc.Records(state, false)
+	if err != nil || len(services) == 0 {
+		return answer
+	}
+	hosts := selectedHosts(policy.selectServices(services, bc.RootPrefix, status))
+	records := make([]dns.RR, 0, len(answer))
+	for _, rr := range answer {
+		var ip string
+		switch r := rr.(type) {
+		case *dns.A:
+			ip = r.A.String()
+		case *dns.AAAA:
+			ip = r.AAAA.String()
+		default:
+			records = append(records, rr)
+			continue
+		}
+		if _, ok := hosts[ip]; ok {
+			records = append(records, rr)
+		}
+	}
+	return records
+}
+
 // Name implements the Handler interface.
 func (bc *BcsCustom) Name() string { return "bcscustom" }
@@ -345,6 +345,121 @@ func (h httpServer) ListDomain(req *restful.Request, resp *restful.Response) {
 	log.Printf("[INFO] list domain[%s] success. data: %s", zone, js)
 }
 
+// SetPolicy api for creating or updating answer policy of domain
+func (h httpServer) SetPolicy(req *restful.Request, resp *restful.Response) {
+	data, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		log.Printf("[ERROR] read set policy request body failed. err: %v", err)
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	log.Printf("[INFO] received set policy request, source[%s], req data:%s", req.Request.RemoteAddr, string(data))
+
+	policy := new(DomainPolicy)
+	if err = json.Unmarshal(data, policy); err != nil {
+		log.Printf("[ERROR] set policy, unmarshal request body failed. err: %v", err)
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	if err = policy.Validate(); err != nil {
+		log.Printf("[ERROR] set policy, invalid policy for domain[%s]. err: %v", policy.Domain, err)
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	js, err := json.Marshal(policy)
+	if err != nil {
+		log.Printf("[ERROR] policy to json marshal err, %s", err.Error())
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	if _, err = h.EtcdCli.Put(h.Ctx, getPolicyKey(policy.Domain, h.RootPrefix), string(js)); err != nil {
+		log.Printf("[ERROR] set policy for domain[%s] failed. err: %v", policy.Domain, err)
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: fmt.Sprintf("set policy for domain[%s] failed. err: %v", policy.Domain, err)})
+		return
+	}
+	h.writeResponse(resp, bresp.APIRespone{Result: true, Code: 0, Message: "set policy success"})
+	log.Printf("[INFO] set policy for domain[%s] success. data: %s", policy.Domain, js)
+}
+
+// GetPolicy api for answer policy of domain
+func (h httpServer) GetPolicy(req *restful.Request, resp *restful.Response) {
+	domain := normalizeDomain(req.Request.FormValue("domain"))
+	if len(domain) == 0 {
+		log.Printf("[ERROR] received get policy reqeust, but get empty domain.")
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: "invalid value with empty domain."})
+		return
+	}
+	r, err := h.EtcdCli.Get(h.Ctx, getPolicyKey(domain, h.RootPrefix))
+	if err != nil {
+		log.Printf("[ERROR] get policy for domain[%s] failed. err: %v", domain, err)
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	if len(r.Kvs) == 0 {
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: fmt.Sprintf("policy for domain [%s] not found", domain)})
+		return
+	}
+	policy := new(DomainPolicy)
+	if err = json.Unmarshal(r.Kvs[0].Value, policy); err != nil {
+		log.Printf("[ERROR] unmarshal policy for domain[%s] failed. err: %v", domain, err)
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	h.writeResponse(resp, bresp.APIRespone{Result: true, Code: 0, Message: "get policy success", Data: policy})
+}
+
+// DeletePolicy api for deleting answer policy and probe status of domain
+func (h httpServer) DeletePolicy(req *restful.Request, resp *restful.Response) {
+	domain := normalizeDomain(req.Request.FormValue("domain"))
+	if len(domain) == 0 {
+		log.Printf("[ERROR] received delete policy reqeust, but get empty domain.")
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: "invalid value with empty domain."})
+		return
+	}
+	log.Printf("[INFO] received delete policy request, source[%s], domain: %s", req.Request.RemoteAddr, domain)
+	r, err := h.EtcdCli.Delete(h.Ctx, getPolicyKey(domain, h.RootPrefix))
+	if err != nil {
+		log.Printf("[ERROR] delete policy for domain[%s] failed. err: %v", domain, err)
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	if r.Deleted == 0 {
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: fmt.Sprintf("policy for domain [%s] not found", domain)})
+		return
+	}
+	if _, err = h.EtcdCli.Delete(h.Ctx, getProbeKey(domain, h.RootPrefix), etcdcv3.WithPrefix()); err != nil {
+		log.Printf("[ERROR] delete probe status for domain[%s] failed. err: %v", domain, err)
+	}
+	h.writeResponse(resp, bresp.APIRespone{Result: true, Code: 0, Message: "delete policy success"})
+	log.Printf("[INFO] delete policy for domain[%s] success.", domain)
+}
+
+// GetProbeStatus api for probe status of all records of domain
+func (h httpServer) GetProbeStatus(req *restful.Request, resp *restful.Response) {
+	domain := normalizeDomain(req.Request.FormValue("domain"))
+	if len(domain) == 0 {
+		log.Printf("[ERROR] received get probe status reqeust, but get empty domain.")
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: "invalid value with empty domain."})
+		return
+	}
+	r, err := h.EtcdCli.Get(h.Ctx, getProbeKey(domain, h.RootPrefix), etcdcv3.WithPrefix())
+	if err != nil {
+		log.Printf("[ERROR] get probe status for domain[%s] failed. err: %v", domain, err)
+		h.writeResponse(resp, bresp.APIRespone{Result: false, Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	status := make([]*ProbeStatus, 0, len(r.Kvs))
+	for _, kv := range r.Kvs {
+		st := new(ProbeStatus)
+		if err := json.Unmarshal(kv.Value, st); err != nil {
+			log.Printf("[ERROR] unmarshal probe status key[%s] failed. err: %v", string(kv.Key), err)
+			continue
+		}
+		status = append(status, st)
+	}
+	h.writeResponse(resp, bresp.APIRespone{Result: true, Code: 0, Message: "get probe status success", Data: status})
+}
+
 // standard wildcard domain name example: demo.ied.*.bcscustom.com
 var wildcardDomain = regexp.MustCompile(`^([a-zA-Z0-9-]+\.)+(\*\.)([a-zA-Z0-9-]+\.)([a-zA-Z0-9]+)$`)
 
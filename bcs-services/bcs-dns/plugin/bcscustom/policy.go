@@ -0,0 +1,264 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bcscustom
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/coredns/coredns/plugin/etcd/msg"
+)
+
+// policy modes for A/AAAA answers of custom domain
+const (
+	// PolicyModeAll answer with all healthy records
+	PolicyModeAll = "all"
+	// PolicyModeWeighted answer with Count records selected randomly by weight
+	PolicyModeWeighted = "weighted"
+	// PolicyModePriority answer with healthy records in the highest priority tier,
+	// lower tiers are used only when all records in higher tiers are unhealthy
+	PolicyModePriority = "priority"
+)
+
+// probe types for custom domain targets
+const (
+	ProbeTypeTCP  = "tcp"
+	ProbeTypeHTTP = "http"
+)
+
+const (
+	defaultProbeInterval         = 10
+	defaultProbeTimeout          = 3
+	defaultProbeFailureThreshold = 3
+	defaultProbeSuccessThreshold = 1
+	policyKeySuffix              = "-policy"
+	probeKeySuffix               = "-probe"
+)
+
+// DomainPolicy answer policy for one custom domain
+type DomainPolicy struct {
+	Domain string `json:"domain"`
+	// Mode is one of all, weighted and priority, default all
+	Mode string `json:"mode,omitempty"`
+	// Count records in answer, 0 means all selected records. default 1 in weighted mode
+	Count int          `json:"count,omitempty"`
+	Probe *ProbeConfig `json:"probe,omitempty"`
+}
+
+// ProbeConfig active probing for all targets of custom domain
+type ProbeConfig struct {
+	// Type is tcp or http
+	Type string `json:"type"`
+	// Port for probing, record port is used when empty
+	Port int `json:"port,omitempty"`
+	// Path for http probing, default /
+	Path string `json:"path,omitempty"`
+	// Interval seconds between two probing, default 10
+	Interval int `json:"interval,omitempty"`
+	// Timeout seconds for one probing, default 3
+	Timeout int `json:"timeout,omitempty"`
+	// FailureThreshold consecutive failures to mark target unhealthy, default 3
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	// SuccessThreshold consecutive successes to mark target healthy, default 1
+	SuccessThreshold int `json:"successThreshold,omitempty"`
+}
+
+// ProbeStatus probing result for one record of custom domain
+type ProbeStatus struct {
+	Domain               string `json:"domain"`
+	Alias                string `json:"alias"`
+	Host                 string `json:"host"`
+	Port                 int    `json:"port"`
+	Healthy              bool   `json:"healthy"`
+	ConsecutiveFailures  int    `json:"consecutiveFailures"`
+	ConsecutiveSuccesses int    `json:"consecutiveSuccesses"`
+	LastProbeTime        int64  `json:"lastProbeTime"`
+	LastChangeTime       int64  `json:"lastChangeTime"`
+	Message              string `json:"message,omitempty"`
+}
+
+// Validate check policy and fill default values
+func (p *DomainPolicy) Validate() error {
+	p.Domain = normalizeDomain(p.Domain)
+	if len(p.Domain) == 0 {
+		return errors.New("domain can not be empty")
+	}
+	if strings.Contains(p.Domain, "*") {
+		return errors.New("policy for wildcard domain is not supported")
+	}
+	switch p.Mode {
+	case "":
+		p.Mode = PolicyModeAll
+	case PolicyModeAll, PolicyModePriority:
+	case PolicyModeWeighted:
+		if p.Count == 0 {
+			p.Count = 1
+		}
+	default:
+		return fmt.Errorf("invalid policy mode: %s", p.Mode)
+	}
+	if p.Count < 0 {
+		return fmt.Errorf("invalid count: %d", p.Count)
+	}
+	if p.Probe == nil {
+		return nil
+	}
+	return p.Probe.validate()
+}
+
+func (c *ProbeConfig) validate() error {
+	switch c.Type {
+	case ProbeTypeTCP:
+	case ProbeTypeHTTP:
+		if c.Path == "" {
+			c.Path = "/"
+		}
+		if !strings.HasPrefix(c.Path, "/") {
+			return fmt.Errorf("invalid probe path: %s", c.Path)
+		}
+	default:
+		return fmt.Errorf("invalid probe type: %s", c.Type)
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid probe port: %d", c.Port)
+	}
+	if c.Interval < 0 || c.Timeout < 0 || c.FailureThreshold < 0 || c.SuccessThreshold < 0 {
+		return errors.New("probe interval, timeout and thresholds can not be negative")
+	}
+	if c.Interval == 0 {
+		c.Interval = defaultProbeInterval
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultProbeTimeout
+	}
+	if c.Timeout > c.Interval {
+		return fmt.Errorf("probe timeout %d is larger than interval %d", c.Timeout, c.Interval)
+	}
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = defaultProbeFailureThreshold
+	}
+	if c.SuccessThreshold == 0 {
+		c.SuccessThreshold = defaultProbeSuccessThreshold
+	}
+	return nil
+}
+
+// getPolicyKey etcd key for domain policy, it is out of root prefix
+// so that etcd plugin never treats it as record
+func getPolicyKey(domain, prefix string) string {
+	return msg.Path(domain, prefix+policyKeySuffix)
+}
+
+// getProbeKey etcd key prefix for domain probe status
+func getProbeKey(domain, prefix string) string {
+	return msg.Path(domain, prefix+probeKeySuffix) + "/"
+}
+
+// recordAlias alias of record relative to domain, record of sub domain
+// is like sub/alias
+func recordAlias(domain, prefix, key string) string {
+	return strings.TrimPrefix(key, msg.Path(domain, prefix)+"/")
+}
+
+// selectServices select services for answer according to policy and probe status,
+// all services are returned when none of them is healthy
+func (p *DomainPolicy) selectServices(services []msg.Service, prefix string, status map[string]*ProbeStatus) []msg.Service {
+	var healthy []msg.Service
+	for _, svc := range services {
+		if p.Probe != nil {
+			if st, ok := status[recordAlias(p.Domain, prefix, svc.Key)]; ok && !st.Healthy {
+				continue
+			}
+		}
+		healthy = append(healthy, svc)
+	}
+
+	candidates := healthy
+	if p.Mode == PolicyModePriority {
+		// lower value is higher priority, like SRV
+		candidates = highestTier(healthy)
+		if len(candidates) == 0 {
+			candidates = highestTier(services)
+		}
+	} else if len(candidates) == 0 {
+		candidates = services
+	}
+	if p.Count == 0 || p.Count >= len(candidates) {
+		return candidates
+	}
+	return weightedSelect(candidates, p.Count)
+}
+
+func highestTier(services []msg.Service) []msg.Service {
+	if len(services) == 0 {
+		return nil
+	}
+	sorted := make([]msg.Service, len(services))
+	copy(sorted, services)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority < sorted[j].Priority })
+	tier := sorted[:1]
+	for _, svc := range sorted[1:] {
+		if svc.Priority != tier[0].Priority {
+			break
+		}
+		tier = append(tier, svc)
+	}
+	return tier
+}
+
+// weightedSelect select count services randomly without replacement,
+// service without weight is regarded as weight 1
+func weightedSelect(services []msg.Service, count int) []msg.Service {
+	pool := make([]msg.Service, len(services))
+	copy(pool, services)
+	selected := make([]msg.Service, 0, count)
+	for len(selected) < count && len(pool) > 0 {
+		total := 0
+		for _, svc := range pool {
+			total += serviceWeight(svc)
+		}
+		n := rand.Intn(total)
+		for i, svc := range pool {
+			n -= serviceWeight(svc)
+			if n < 0 {
+				selected = append(selected, svc)
+				pool = append(pool[:i], pool[i+1:]...)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func serviceWeight(svc msg.Service) int {
+	if svc.Weight <= 0 {
+		return 1
+	}
+	return svc.Weight
+}
+
+// selectedHosts ip addresses of selected services
+func selectedHosts(services []msg.Service) map[string]struct{} {
+	hosts := make(map[string]struct{}, len(services))
+	for _, svc := range services {
+		if ip := net.ParseIP(svc.Host); ip != nil {
+			hosts[ip.String()] = struct{}{}
+		}
+	}
+	return hosts
+}
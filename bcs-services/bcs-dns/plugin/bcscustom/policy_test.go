@@ -0,0 +1,143 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bcscustom
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/coredns/coredns/plugin/etcd/msg"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		policy    DomainPolicy
+		shouldErr bool
+		mode      string
+		count     int
+	}{
+		{DomainPolicy{Domain: "demo.bcscustom.com."}, false, PolicyModeAll, 0},
+		{DomainPolicy{Domain: "demo.bcscustom.com", Mode: PolicyModeWeighted}, false, PolicyModeWeighted, 1},
+		{DomainPolicy{Domain: "demo.bcscustom.com", Mode: PolicyModePriority, Count: 2}, false, PolicyModePriority, 2},
+		{DomainPolicy{Domain: ""}, true, "", 0},
+		{DomainPolicy{Domain: "demo.*.bcscustom.com"}, true, "", 0},
+		{DomainPolicy{Domain: "demo.bcscustom.com", Mode: "random"}, true, "", 0},
+		{DomainPolicy{Domain: "demo.bcscustom.com", Count: -1}, true, "", 0},
+		{DomainPolicy{Domain: "demo.bcscustom.com", Probe: &ProbeConfig{Type: "udp"}}, true, "", 0},
+		{DomainPolicy{Domain: "demo.bcscustom.com", Probe: &ProbeConfig{Type: ProbeTypeTCP, Interval: 2}}, true, "", 0},
+		{DomainPolicy{Domain: "demo.bcscustom.com", Probe: &ProbeConfig{Type: ProbeTypeHTTP, Path: "health"}}, true, "", 0},
+	}
+	for i, test := range tests {
+		err := test.policy.Validate()
+		if test.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: expected error but found none", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: expected no error, got %v", i, err)
+			continue
+		}
+		if test.policy.Domain != "demo.bcscustom.com" || test.policy.Mode != test.mode || test.policy.Count != test.count {
+			t.Errorf("Test %d: unexpected policy after validate: %+v", i, test.policy)
+		}
+	}
+
+	probe := &ProbeConfig{Type: ProbeTypeHTTP}
+	if err := probe.validate(); err != nil {
+		t.Fatalf("expected no error for default probe, got %v", err)
+	}
+	if probe.Path != "/" || probe.Interval != defaultProbeInterval || probe.Timeout != defaultProbeTimeout ||
+		probe.FailureThreshold != defaultProbeFailureThreshold || probe.SuccessThreshold != defaultProbeSuccessThreshold {
+		t.Errorf("unexpected probe defaults: %+v", probe)
+	}
+}
+
+func testServices() []msg.Service {
+	key := msg.Path("demo.bcscustom.com", "bcscustom")
+	return []msg.Service{
+		{Key: key + "/a", Host: "127.0.0.1", Priority: 10, Weight: 100},
+		{Key: key + "/b", Host: "127.0.0.2", Priority: 10},
+		{Key: key + "/c", Host: "127.0.0.3", Priority: 20},
+		{Key: key + "/d", Host: "127.0.0.4", Priority: 20},
+	}
+}
+
+func sortedHosts(services []msg.Service) []string {
+	var hosts []string
+	for _, svc := range services {
+		hosts = append(hosts, svc.Host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func TestSelectServices(t *testing.T) {
+	unhealthy := func(aliases ...string) map[string]*ProbeStatus {
+		status := make(map[string]*ProbeStatus)
+		for _, alias := range aliases {
+			status[alias] = &ProbeStatus{Alias: alias, Healthy: false}
+		}
+		return status
+	}
+	tests := []struct {
+		policy   DomainPolicy
+		status   map[string]*ProbeStatus
+		expected []string
+	}{
+		{DomainPolicy{Mode: PolicyModeAll}, unhealthy("a"), []string{"127.0.0.1", "127.0.0.2", "127.0.0.3", "127.0.0.4"}},
+		{DomainPolicy{Mode: PolicyModeAll, Probe: &ProbeConfig{}}, unhealthy("a"), []string{"127.0.0.2", "127.0.0.3", "127.0.0.4"}},
+		{DomainPolicy{Mode: PolicyModeAll, Probe: &ProbeConfig{}}, unhealthy("a", "b", "c", "d"), []string{"127.0.0.1", "127.0.0.2", "127.0.0.3", "127.0.0.4"}},
+		{DomainPolicy{Mode: PolicyModePriority}, nil, []string{"127.0.0.1", "127.0.0.2"}},
+		{DomainPolicy{Mode: PolicyModePriority, Probe: &ProbeConfig{}}, unhealthy("a"), []string{"127.0.0.2"}},
+		// failover to lower tier
+		{DomainPolicy{Mode: PolicyModePriority, Probe: &ProbeConfig{}}, unhealthy("a", "b", "c"), []string{"127.0.0.4"}},
+		{DomainPolicy{Mode: PolicyModePriority, Probe: &ProbeConfig{}}, unhealthy("a", "b", "c", "d"), []string{"127.0.0.1", "127.0.0.2"}},
+		{DomainPolicy{Mode: PolicyModeWeighted, Count: 3, Probe: &ProbeConfig{}}, unhealthy("b"), []string{"127.0.0.1", "127.0.0.3", "127.0.0.4"}},
+	}
+	for i, test := range tests {
+		test.policy.Domain = "demo.bcscustom.com"
+		hosts := sortedHosts(test.policy.selectServices(testServices(), "bcscustom", test.status))
+		if len(hosts) != len(test.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i, test.expected, hosts)
+			continue
+		}
+		for j := range hosts {
+			if hosts[j] != test.expected[j] {
+				t.Errorf("Test %d: expected %v, got %v", i, test.expected, hosts)
+				break
+			}
+		}
+	}
+}
+
+func TestWeightedSelect(t *testing.T) {
+	services := testServices()[:2]
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		selected := weightedSelect(services, 1)
+		if len(selected) != 1 {
+			t.Fatalf("expected 1 service, got %d", len(selected))
+		}
+		counts[selected[0].Host]++
+	}
+	// weight of 127.0.0.1 is 100, 127.0.0.2 is regarded as 1
+	if counts["127.0.0.1"] < 900 {
+		t.Errorf("unexpected weighted selection result: %v", counts)
+	}
+	if selected := weightedSelect(services, 2); len(selected) != 2 || selected[0].Host == selected[1].Host {
+		t.Errorf("expected both services selected, got %v", selected)
+	}
+}
@@ -0,0 +1,416 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bcscustom
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coredns/coredns/plugin/etcd/msg"
+	etcdcv3 "github.com/coreos/etcd/clientv3"
+	"golang.org/x/net/context"
+)
+
+const (
+	defaultPolicySyncPeriod = 5
+	etcdTimeout             = 5 * time.Second
+	// probeLeaderSuffix only the bcs-dns holding this key probes targets
+	probeLeaderSuffix = "-probe-leader"
+)
+
+// policyManager keeps domain policies and probe status in memory,
+// syncs them with etcd and probes targets of domains with probe config.
+// all replicas share probe status in etcd, so only the elected leader probes,
+// other replicas load status from etcd
+type policyManager struct {
+	rootPrefix string
+	cli        *etcdcv3.Client
+	ctx        context.Context
+	syncPeriod time.Duration
+
+	// id of this replica, value of leader key when elected
+	id      string
+	leaseID etcdcv3.LeaseID
+	leader  bool
+
+	lock      sync.RWMutex
+	policies  map[string]*DomainPolicy           // domain -> policy
+	status    map[string]map[string]*ProbeStatus // domain -> alias -> status
+	lastProbe map[string]time.Time               // domain -> last probing time
+}
+
+func newPolicyManager(prefix string, cli *etcdcv3.Client, syncPeriod int) *policyManager {
+	if syncPeriod <= 0 {
+		syncPeriod = defaultPolicySyncPeriod
+	}
+	hostname, _ := os.Hostname()
+	return &policyManager{
+		id:         fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		rootPrefix: prefix,
+		cli:        cli,
+		ctx:        context.Background(),
+		syncPeriod: time.Duration(syncPeriod) * time.Second,
+		policies:   make(map[string]*DomainPolicy),
+		status:     make(map[string]map[string]*ProbeStatus),
+		lastProbe:  make(map[string]time.Time),
+	}
+}
+
+// run sync policies and probe targets until ctx done
+func (pm *policyManager) run(ctx context.Context) {
+	tick := time.NewTicker(pm.syncPeriod)
+	defer tick.Stop()
+	for {
+		if err := pm.sync(); err != nil {
+			log.Printf("[ERROR] bcscustom sync domain policies failed, %s", err.Error())
+		}
+		if err := pm.campaign(); err != nil {
+			log.Printf("[ERROR] bcscustom campaign for probe leader failed, %s", err.Error())
+		}
+		if pm.leader {
+			pm.probeAll()
+		}
+		select {
+		case <-ctx.Done():
+			pm.resign()
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// campaign keep or take the probe leader key with a lease of three sync periods,
+// the leader key is released when leader quits or its lease expires
+func (pm *policyManager) campaign() error {
+	ctx, cancel := context.WithTimeout(pm.ctx, etcdTimeout)
+	defer cancel()
+	if pm.leaseID != 0 {
+		if _, err := pm.cli.KeepAliveOnce(ctx, pm.leaseID); err != nil {
+			// lease expired, leader key is deleted with it
+			pm.setLeader(false)
+			pm.leaseID = 0
+			return err
+		}
+	}
+	if pm.leaseID == 0 {
+		ttl := int64(3 * pm.syncPeriod / time.Second)
+		lease, err := pm.cli.Grant(ctx, ttl)
+		if err != nil {
+			return err
+		}
+		pm.leaseID = lease.ID
+	}
+
+	key := "/" + pm.rootPrefix + probeLeaderSuffix
+	resp, err := pm.cli.Txn(ctx).
+		If(etcdcv3.Compare(etcdcv3.CreateRevision(key), "=", 0)).
+		Then(etcdcv3.OpPut(key, pm.id, etcdcv3.WithLease(pm.leaseID))).
+		Else(etcdcv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		return err
+	}
+	leader := resp.Succeeded
+	if !leader && len(resp.Responses) > 0 {
+		kvs := resp.Responses[0].GetResponseRange().Kvs
+		leader = len(kvs) > 0 && string(kvs[0].Value) == pm.id
+	}
+	pm.setLeader(leader)
+	return nil
+}
+
+// resign release leader key by revoking lease
+func (pm *policyManager) resign() {
+	if pm.leaseID == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), etcdTimeout)
+	defer cancel()
+	if _, err := pm.cli.Revoke(ctx, pm.leaseID); err != nil {
+		log.Printf("[ERROR] bcscustom revoke probe leader lease failed, %s", err.Error())
+	}
+	pm.leaseID = 0
+	pm.setLeader(false)
+}
+
+func (pm *policyManager) setLeader(leader bool) {
+	if pm.leader != leader {
+		log.Printf("[INFO] bcscustom %s probe leader changed to %t", pm.id, leader)
+	}
+	pm.leader = leader
+}
+
+// getPolicy get policy of domain, nil if domain has no policy
+func (pm *policyManager) getPolicy(domain string) (*DomainPolicy, map[string]*ProbeStatus) {
+	pm.lock.RLock()
+	defer pm.lock.RUnlock()
+	return pm.policies[normalizeDomain(domain)], pm.status[normalizeDomain(domain)]
+}
+
+// sync reload all policies and probe status from etcd
+func (pm *policyManager) sync() error {
+	ctx, cancel := context.WithTimeout(pm.ctx, etcdTimeout)
+	defer cancel()
+	resp, err := pm.cli.Get(ctx, "/"+pm.rootPrefix+policyKeySuffix+"/", etcdcv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+	policies := make(map[string]*DomainPolicy)
+	for _, kv := range resp.Kvs {
+		policy := new(DomainPolicy)
+		if err := json.Unmarshal(kv.Value, policy); err != nil {
+			log.Printf("[ERROR] bcscustom decode policy %s failed, %s", string(kv.Key), err.Error())
+			continue
+		}
+		policies[policy.Domain] = policy
+	}
+
+	resp, err = pm.cli.Get(ctx, "/"+pm.rootPrefix+probeKeySuffix+"/", etcdcv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+	status := make(map[string]map[string]*ProbeStatus)
+	for _, kv := range resp.Kvs {
+		st := new(ProbeStatus)
+		if err := json.Unmarshal(kv.Value, st); err != nil {
+			log.Printf("[ERROR] bcscustom decode probe status %s failed, %s", string(kv.Key), err.Error())
+			continue
+		}
+		if _, ok := policies[st.Domain]; !ok {
+			continue
+		}
+		if status[st.Domain] == nil {
+			status[st.Domain] = make(map[string]*ProbeStatus)
+		}
+		status[st.Domain][st.Alias] = st
+	}
+
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	pm.policies = policies
+	for domain, domainStatus := range pm.status {
+		// leader keeps local probing counters, etcd only records changes of health,
+		// followers always take status from etcd written by leader
+		if _, ok := status[domain]; ok && pm.leader {
+			for alias, st := range domainStatus {
+				if remote, ok := status[domain][alias]; ok && remote.Healthy == st.Healthy {
+					status[domain][alias] = st
+				}
+			}
+		}
+	}
+	pm.status = status
+	for domain := range pm.lastProbe {
+		if p, ok := policies[domain]; !ok || p.Probe == nil {
+			delete(pm.lastProbe, domain)
+		}
+	}
+	return nil
+}
+
+// probeAll probe targets of domains whose probing interval is reached
+func (pm *policyManager) probeAll() {
+	now := time.Now()
+	var due []*DomainPolicy
+	pm.lock.Lock()
+	for domain, policy := range pm.policies {
+		if policy.Probe == nil {
+			continue
+		}
+		if now.Sub(pm.lastProbe[domain]) < time.Duration(policy.Probe.Interval)*time.Second {
+			continue
+		}
+		pm.lastProbe[domain] = now
+		due = append(due, policy)
+	}
+	pm.lock.Unlock()
+
+	wg := sync.WaitGroup{}
+	for _, policy := range due {
+		wg.Add(1)
+		go func(p *DomainPolicy) {
+			defer wg.Done()
+			if err := pm.probeDomain(p); err != nil {
+				log.Printf("[ERROR] bcscustom probe domain %s failed, %s", p.Domain, err.Error())
+			}
+		}(policy)
+	}
+	wg.Wait()
+}
+
+// probeDomain probe all records of domain, save status to etcd when health changed
+func (pm *policyManager) probeDomain(policy *DomainPolicy) error {
+	ctx, cancel := context.WithTimeout(pm.ctx, etcdTimeout)
+	defer cancel()
+	resp, err := pm.cli.Get(ctx, msg.Path(policy.Domain, pm.rootPrefix)+"/", etcdcv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	type result struct {
+		alias string
+		host  string
+		port  int
+		err   error
+	}
+	results := make(chan result, len(resp.Kvs))
+	wg := sync.WaitGroup{}
+	for _, kv := range resp.Kvs {
+		svc := new(msg.Service)
+		if err := json.Unmarshal(kv.Value, svc); err != nil {
+			log.Printf("[ERROR] bcscustom decode record %s failed, %s", string(kv.Key), err.Error())
+			continue
+		}
+		if net.ParseIP(svc.Host) == nil {
+			continue
+		}
+		port := policy.Probe.Port
+		if port == 0 {
+			port = svc.Port
+		}
+		r := result{alias: recordAlias(policy.Domain, pm.rootPrefix, string(kv.Key)), host: svc.Host, port: port}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.err = probeTarget(policy.Probe, r.host, r.port)
+			results <- r
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	now := time.Now().Unix()
+	current := make(map[string]*ProbeStatus)
+	changed := make(map[string]*ProbeStatus)
+	pm.lock.RLock()
+	old := pm.status[policy.Domain]
+	pm.lock.RUnlock()
+	for r := range results {
+		st := &ProbeStatus{Domain: policy.Domain, Alias: r.alias, Healthy: true}
+		if prev, ok := old[r.alias]; ok {
+			*st = *prev
+		}
+		if st.Host != r.host || st.Port != r.port {
+			// new record or record changed, probe it from healthy
+			st = &ProbeStatus{Domain: policy.Domain, Alias: r.alias, Host: r.host, Port: r.port, Healthy: true, LastChangeTime: now}
+			changed[r.alias] = st
+		}
+		st.LastProbeTime = now
+		if r.err != nil {
+			st.ConsecutiveFailures++
+			st.ConsecutiveSuccesses = 0
+			st.Message = r.err.Error()
+			if st.Healthy && st.ConsecutiveFailures >= policy.Probe.FailureThreshold {
+				st.Healthy = false
+				st.LastChangeTime = now
+				changed[r.alias] = st
+				log.Printf("[WARN] bcscustom domain %s alias %s target %s:%d turns unhealthy, %s",
+					st.Domain, st.Alias, st.Host, st.Port, st.Message)
+			}
+		} else {
+			st.ConsecutiveSuccesses++
+			st.ConsecutiveFailures = 0
+			st.Message = ""
+			if !st.Healthy && st.ConsecutiveSuccesses >= policy.Probe.SuccessThreshold {
+				st.Healthy = true
+				st.LastChangeTime = now
+				changed[r.alias] = st
+				log.Printf("[INFO] bcscustom domain %s alias %s target %s:%d turns healthy",
+					st.Domain, st.Alias, st.Host, st.Port)
+			}
+		}
+		current[r.alias] = st
+	}
+
+	for _, st := range changed {
+		if err := pm.saveStatus(st); err != nil {
+			log.Printf("[ERROR] bcscustom save probe status of domain %s alias %s failed, %s", st.Domain, st.Alias, err.Error())
+		}
+	}
+	// clean status of deleted records
+	for alias, st := range old {
+		if _, ok := current[alias]; !ok {
+			if err := pm.deleteStatus(st); err != nil {
+				log.Printf("[ERROR] bcscustom delete probe status of domain %s alias %s failed, %s", st.Domain, alias, err.Error())
+			}
+		}
+	}
+
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	if _, ok := pm.policies[policy.Domain]; ok {
+		pm.status[policy.Domain] = current
+	}
+	return nil
+}
+
+func (pm *policyManager) saveStatus(st *ProbeStatus) error {
+	js, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(pm.ctx, etcdTimeout)
+	defer cancel()
+	_, err = pm.cli.Put(ctx, getProbeKey(st.Domain, pm.rootPrefix)+st.Alias, string(js))
+	return err
+}
+
+func (pm *policyManager) deleteStatus(st *ProbeStatus) error {
+	ctx, cancel := context.WithTimeout(pm.ctx, etcdTimeout)
+	defer cancel()
+	_, err := pm.cli.Delete(ctx, getProbeKey(st.Domain, pm.rootPrefix)+st.Alias)
+	return err
+}
+
+// probeTarget probe target once, return nil when target is healthy
+func probeTarget(cfg *ProbeConfig, host string, port int) error {
+	if port <= 0 {
+		return fmt.Errorf("no port for probing")
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	switch cfg.Type {
+	case ProbeTypeTCP:
+		conn, err := net.DialTimeout("tcp", addr, timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case ProbeTypeHTTP:
+		// probe target directly, never through proxy in environment
+		client := &http.Client{Timeout: timeout, Transport: &http.Transport{DisableKeepAlives: true}}
+		resp, err := client.Get("http://" + addr + cfg.Path)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("http probe status code %d", resp.StatusCode)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown probe type %s", cfg.Type)
+}
+
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimSuffix(domain, "."))
+}
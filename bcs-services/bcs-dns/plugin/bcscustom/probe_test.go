@@ -0,0 +1,60 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bcscustom
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestProbeTarget(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer svr.Close()
+	host, portStr, _ := net.SplitHostPort(svr.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	// get a closed port
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedPort := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	tests := []struct {
+		cfg       ProbeConfig
+		port      int
+		shouldErr bool
+	}{
+		{ProbeConfig{Type: ProbeTypeTCP, Timeout: 1}, port, false},
+		{ProbeConfig{Type: ProbeTypeTCP, Timeout: 1}, closedPort, true},
+		{ProbeConfig{Type: ProbeTypeTCP, Timeout: 1}, 0, true},
+		{ProbeConfig{Type: ProbeTypeHTTP, Timeout: 1, Path: "/healthz"}, port, false},
+		{ProbeConfig{Type: ProbeTypeHTTP, Timeout: 1, Path: "/"}, port, true},
+		{ProbeConfig{Type: ProbeTypeHTTP, Timeout: 1, Path: "/healthz"}, closedPort, true},
+	}
+	for i, test := range tests {
+		err := probeTarget(&test.cfg, host, test.port)
+		if test.shouldErr && err == nil {
+			t.Errorf("Test %d: expected error but found none", i)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("Test %d: expected no error, got %v", i, err)
+		}
+	}
+}
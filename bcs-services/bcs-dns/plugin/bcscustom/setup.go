@@ -22,6 +22,7 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"bk-bcs/bcs-common/common"
@@ -76,9 +77,10 @@ func parseConfig(c *caddy.Controller) (*BcsCustom, error) {
 		Ctx:        context.Background(),
 	}
 	var (
-		tlsConfig *tls.Config
-		errOutter error
-		endpoints = []string{defaultEndpoint}
+		tlsConfig        *tls.Config
+		errOutter        error
+		endpoints        = []string{defaultEndpoint}
+		policySyncPeriod = defaultPolicySyncPeriod
 	)
 	for c.Next() {
 		bc.Zones = c.RemainingArgs()
@@ -143,6 +145,15 @@ func parseConfig(c *caddy.Controller) (*BcsCustom, error) {
 					if len(c.Val()) != 0 {
 						bc.SvrTLS.CertFile = c.Val()
 					}
+				case "policy-sync-period":
+					if !c.NextArg() {
+						return nil, c.ArgErr()
+					}
+					period, err := strconv.Atoi(c.Val())
+					if err != nil || period <= 0 {
+						return nil, c.Errf("invalid policy-sync-period '%s'", c.Val())
+					}
+					policySyncPeriod = period
 				case "root-prefix":
 					if !c.NextArg() {
 						return nil, c.ArgErr()
@@ -182,6 +193,9 @@ func parseConfig(c *caddy.Controller) (*BcsCustom, error) {
 			Ctx: context.Background(),
 		}
 
+		bc.policies = newPolicyManager(bc.RootPrefix, client, policySyncPeriod)
+		go bc.policies.run(bc.Ctx)
+
 		log.Printf("[Info] bcscustom config info: %+#v", bc)
 
 		if err := startHTTPServer(bc.RootPrefix, client, bc.SvrTLS, bc.Listen); err != nil {
@@ -219,6 +233,10 @@ func startHTTPServer(rootPrefix string, etcdCli *etcdcv3.Client, svrTLS TLS, lis
 	api.Route(api.PUT("domains").To(svr.UpdateDomain))
 	api.Route(api.GET("domains").To(svr.GetDomain))
 	api.Route(api.GET("domains/subdomains").To(svr.ListDomain))
+	api.Route(api.PUT("domains/policy").To(svr.SetPolicy))
+	api.Route(api.GET("domains/policy").To(svr.GetPolicy))
+	api.Route(api.DELETE("domains/policy").To(svr.DeletePolicy))
+	api.Route(api.GET("domains/probe").To(svr.GetProbeStatus))
 
 	if len(svrTLS.KeyFile) == 0 &&
 		len(svrTLS.CertFile) == 0 &&
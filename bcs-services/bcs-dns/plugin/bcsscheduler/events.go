@@ -27,12 +27,16 @@ import (
  */
 
 func (bcs *BcsScheduler) svcOnAdd(obj interface{}) {
+	bcs.touch()
 	if bcs.storage == nil || !bcs.registery.IsMaster() {
 		return
 	}
 }
 
 func (bcs *BcsScheduler) svcOnUpdate(old, cur interface{}) {
+	if !reflect.DeepEqual(old, cur) {
+		bcs.touch()
+	}
 	if bcs.storage == nil || !bcs.registery.IsMaster() {
 		return
 	}
@@ -41,6 +45,7 @@ func (bcs *BcsScheduler) svcOnUpdate(old, cur interface{}) {
 }
 
 func (bcs *BcsScheduler) svcOnDelete(obj interface{}) {
+	bcs.touch()
 	if bcs.storage == nil || !bcs.registery.IsMaster() {
 		return
 	}
@@ -51,6 +56,7 @@ func (bcs *BcsScheduler) svcOnDelete(obj interface{}) {
  */
 
 func (bcs *BcsScheduler) endpointOnAdd(obj interface{}) {
+	bcs.touch()
 	if bcs.storage == nil || !bcs.registery.IsMaster() {
 		return
 	}
@@ -71,13 +77,14 @@ func (bcs *BcsScheduler) endpointOnAdd(obj interface{}) {
 }
 
 func (bcs *BcsScheduler) endpointOnUpdate(old, cur interface{}) {
-	if bcs.storage == nil || !bcs.registery.IsMaster() {
+	if reflect.DeepEqual(old, cur) {
 		return
 	}
-
-	if reflect.DeepEqual(old, cur) {
+	bcs.touch()
+	if bcs.storage == nil || !bcs.registery.IsMaster() {
 		return
 	}
+
 	oldEndpoint, ok := old.(*bcstypes.BcsEndpoint)
 	if !ok {
 		log.Printf("[ERROR] scheduler endpoint UPDATE get error data type in oldData.")
@@ -101,6 +108,7 @@ func (bcs *BcsScheduler) endpointOnUpdate(old, cur interface{}) {
 }
 
 func (bcs *BcsScheduler) endpointOnDelete(obj interface{}) {
+	bcs.touch()
 	if bcs.storage == nil || !bcs.registery.IsMaster() {
 		return
 	}
@@ -152,6 +160,9 @@ func (bcs *BcsScheduler) endpoint2Message(endpoint *bcstypes.BcsEndpoint) (svcLi
 	}
 	//no cluster ip, construct etcd service with endpoints
 	for _, ep := range endpoint.Endpoints {
+		if !bcs.endpointAvailable(ep) {
+			continue
+		}
 		mode := strings.ToLower(ep.NetworkMode)
 		if mode == "none" {
 			//filter none network mode
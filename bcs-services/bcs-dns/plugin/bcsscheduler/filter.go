@@ -0,0 +1,140 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bcsscheduler
+
+import (
+	"net"
+
+	bcstypes "bk-bcs/bcs-common/common/types"
+
+	"github.com/coredns/coredns/request"
+	"github.com/miekg/dns"
+)
+
+const (
+	endpointFilterHealthy = "healthy"
+	endpointFilterReady   = "ready"
+)
+
+//endpointAvailable check endpoint with endpoint-filter, endpoint
+//without health or readiness check is always available
+func (bcs *BcsScheduler) endpointAvailable(end bcstypes.Endpoint) bool {
+	for _, filter := range bcs.conf.EndpointFilter {
+		switch filter {
+		case endpointFilterHealthy:
+			if end.Healthy != nil && !*end.Healthy {
+				return false
+			}
+		case endpointFilterReady:
+			if end.Ready != nil && !*end.Ready {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//selectEndpoints filter endpoints that not available, and prefer endpoints
+//in client zone. all available endpoints return when none of them in client zone
+func (bcs *BcsScheduler) selectEndpoints(eps []bcstypes.Endpoint, clientZone string) []bcstypes.Endpoint {
+	var available, local []bcstypes.Endpoint
+	for _, end := range eps {
+		if !bcs.endpointAvailable(end) {
+			continue
+		}
+		available = append(available, end)
+		if clientZone != "" && end.NodeAttributes[bcs.conf.ZoneAttribute] == clientZone {
+			local = append(local, end)
+		}
+	}
+	if len(local) != 0 {
+		return local
+	}
+	return available
+}
+
+//clientSubnet get client subnet from EDNS0 client-subnet option,
+//return nil when request without it
+func clientSubnet(state request.Request) *dns.EDNS0_SUBNET {
+	opt := state.Req.IsEdns0()
+	if opt == nil {
+		return nil
+	}
+	for _, o := range opt.Option {
+		if ecs, ok := o.(*dns.EDNS0_SUBNET); ok && ecs.Address != nil {
+			return ecs
+		}
+	}
+	return nil
+}
+
+//clientZone get zone of client, client address is from EDNS0 client-subnet
+//option first, then remote address of request
+func (bcs *BcsScheduler) clientZone(state request.Request) string {
+	if bcs.conf.ZoneAttribute == "" || len(bcs.conf.ZoneSubnets) == 0 {
+		return ""
+	}
+	var ip net.IP
+	if ecs := clientSubnet(state); ecs != nil {
+		ip = ecs.Address
+	} else {
+		ip = net.ParseIP(state.IP())
+	}
+	return bcs.zoneOfIP(ip)
+}
+
+//zoneOfIP find zone by longest matched subnet
+func (bcs *BcsScheduler) zoneOfIP(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	zone := ""
+	longest := -1
+	for _, zs := range bcs.conf.ZoneSubnets {
+		if !zs.Subnet.Contains(ip) {
+			continue
+		}
+		if ones, _ := zs.Subnet.Mask.Size(); ones > longest {
+			zone, longest = zs.Zone, ones
+		}
+	}
+	return zone
+}
+
+//setClientSubnetReply echo client-subnet option back to client, scope
+//prefix is set when answer is tailored for client zone
+func (bcs *BcsScheduler) setClientSubnetReply(state request.Request, m *dns.Msg) {
+	if bcs.conf.ZoneAttribute == "" {
+		return
+	}
+	ecs := clientSubnet(state)
+	if ecs == nil {
+		return
+	}
+	reply := &dns.EDNS0_SUBNET{
+		Code:          dns.EDNS0SUBNET,
+		Family:        ecs.Family,
+		SourceNetmask: ecs.SourceNetmask,
+		Address:       ecs.Address,
+	}
+	if bcs.zoneOfIP(ecs.Address) != "" {
+		reply.SourceScope = ecs.SourceNetmask
+	}
+	opt := m.IsEdns0()
+	if opt == nil {
+		opt = &dns.OPT{Hdr: dns.RR_Header{Name: ".", Rrtype: dns.TypeOPT}}
+		m.Extra = append(m.Extra, opt)
+	}
+	opt.Option = append(opt.Option, reply)
+}
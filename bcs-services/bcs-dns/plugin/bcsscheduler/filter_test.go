@@ -0,0 +1,127 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bcsscheduler
+
+import (
+	"net"
+	"testing"
+
+	bcstypes "bk-bcs/bcs-common/common/types"
+
+	"github.com/coredns/coredns/plugin/test"
+	"github.com/coredns/coredns/request"
+	"github.com/miekg/dns"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func testZoneConfig() *ConfigItem {
+	config := defaultConfigItem()
+	config.ZoneAttribute = "zone"
+	for zone, cidr := range map[string]string{"sz": "10.1.0.0/16", "sz-1": "10.1.1.0/24", "sh": "10.240.0.0/16"} {
+		_, subnet, _ := net.ParseCIDR(cidr)
+		config.ZoneSubnets = append(config.ZoneSubnets, &ZoneSubnet{Zone: zone, Subnet: subnet})
+	}
+	return config
+}
+
+func TestSelectEndpoints(t *testing.T) {
+	eps := []bcstypes.Endpoint{
+		{ContainerIP: "127.0.0.1", NodeAttributes: map[string]string{"zone": "sz"}},
+		{ContainerIP: "127.0.0.2", Healthy: boolPtr(false), NodeAttributes: map[string]string{"zone": "sz"}},
+		{ContainerIP: "127.0.0.3", Ready: boolPtr(false), NodeAttributes: map[string]string{"zone": "sh"}},
+		{ContainerIP: "127.0.0.4", Healthy: boolPtr(true), Ready: boolPtr(true)},
+	}
+	tests := []struct {
+		filter     []string
+		clientZone string
+		expected   []string
+	}{
+		{nil, "", []string{"127.0.0.1", "127.0.0.2", "127.0.0.3", "127.0.0.4"}},
+		{[]string{endpointFilterHealthy}, "", []string{"127.0.0.1", "127.0.0.3", "127.0.0.4"}},
+		{[]string{endpointFilterHealthy, endpointFilterReady}, "", []string{"127.0.0.1", "127.0.0.4"}},
+		{nil, "sz", []string{"127.0.0.1", "127.0.0.2"}},
+		{[]string{endpointFilterHealthy}, "sz", []string{"127.0.0.1"}},
+		// no available endpoint in client zone, fall back to all available endpoints
+		{[]string{endpointFilterReady}, "sh", []string{"127.0.0.1", "127.0.0.2", "127.0.0.4"}},
+		{nil, "gz", []string{"127.0.0.1", "127.0.0.2", "127.0.0.3", "127.0.0.4"}},
+	}
+	for i, test := range tests {
+		config := testZoneConfig()
+		config.EndpointFilter = test.filter
+		bcs := &BcsScheduler{conf: config}
+		selected := bcs.selectEndpoints(eps, test.clientZone)
+		if len(selected) != len(test.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i, test.expected, selected)
+			continue
+		}
+		for j, end := range selected {
+			if end.ContainerIP != test.expected[j] {
+				t.Errorf("Test %d: expected %v, got %v", i, test.expected, selected)
+				break
+			}
+		}
+	}
+}
+
+func TestClientZone(t *testing.T) {
+	bcs := &BcsScheduler{conf: testZoneConfig()}
+	tests := []struct {
+		ecs      string
+		expected string
+	}{
+		// remote address of test writer is 10.240.0.1
+		{"", "sh"},
+		{"10.1.2.0", "sz"},
+		{"10.1.1.0", "sz-1"},
+		{"192.168.1.0", ""},
+	}
+	for i, tc := range tests {
+		m := new(dns.Msg)
+		m.SetQuestion("web.default.svc.cluster.bcs.com.", dns.TypeA)
+		if tc.ecs != "" {
+			m.SetEdns0(4096, false)
+			opt := m.IsEdns0()
+			opt.Option = append(opt.Option, &dns.EDNS0_SUBNET{
+				Code:          dns.EDNS0SUBNET,
+				Family:        1,
+				SourceNetmask: 24,
+				Address:       net.ParseIP(tc.ecs).To4(),
+			})
+		}
+		state := request.Request{W: &test.ResponseWriter{}, Req: m}
+		if zone := bcs.clientZone(state); zone != tc.expected {
+			t.Errorf("Test %d: expected zone %q, got %q", i, tc.expected, zone)
+		}
+
+		reply := new(dns.Msg)
+		bcs.setClientSubnetReply(state, reply)
+		if tc.ecs == "" {
+			if reply.IsEdns0() != nil {
+				t.Errorf("Test %d: expected no client subnet in reply", i)
+			}
+			continue
+		}
+		ecs := clientSubnet(request.Request{W: &test.ResponseWriter{}, Req: reply})
+		if ecs == nil {
+			t.Errorf("Test %d: expected client subnet in reply", i)
+			continue
+		}
+		if (tc.expected != "") != (ecs.SourceScope == 24) {
+			t.Errorf("Test %d: unexpected client subnet scope %d", i, ecs.SourceScope)
+		}
+	}
+}
@@ -191,6 +191,7 @@ func (bcs *BcsScheduler) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *
 	writeBackMsg := func(rr []dns.RR, ext []dns.RR) {
 		m.Answer = append(m.Answer, rr...)
 		m.Extra = append(m.Extra, ext...)
+		bcs.setClientSubnetReply(state, m)
 		state.SizeAndDo(m)
 		newMsg := state.Scrub(m)
 		w.WriteMsg(newMsg)
@@ -199,6 +200,9 @@ func (bcs *BcsScheduler) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *
 	zone := plugin.Zones(bcs.conf.Zones).Matches(state.Name())
 	//log.Printf("[DEBUG] zones: %v, zone: %s, name: %s", bcs.conf.Zones, zone, state.Name())
 	if zone != "" && bcs.inBcsZone(zone) {
+		if state.QType() == dns.TypeAXFR || state.QType() == dns.TypeIXFR {
+			return bcs.Transfer(ctx, state)
+		}
 		if bcs.inCurrentZone(state.Name()) {
 			// request is in current zone.
 			if e := bcs.validateRequest(state); e != nil {
@@ -66,6 +66,7 @@ type endport struct {
 // for instance.
 func (bcs *BcsScheduler) Records(state request.Request, exact bool) ([]msg.Service, error) {
 	r := bcs.parseRequest(state.Name(), state.QType())
+	r.clientZone = bcs.clientZone(state)
 	return bcs.records(r)
 }
 
@@ -128,12 +129,16 @@ func (bcs *BcsScheduler) svcRecords(req recordRequest) (*service, error) {
 		localSvc.endpoints = append(localSvc.endpoints, endpoints...)
 	} else {
 		//get service ip address list
+		var candidates []bcstypes.Endpoint
 		for _, end := range bcsEndpoint.Endpoints {
 			if req.IsPodDNS() {
 				if req.podname != end.Target.Name {
 					continue
 				}
 			}
+			candidates = append(candidates, end)
+		}
+		for _, end := range bcs.selectEndpoints(candidates, req.clientZone) {
 			ep := bcs.formatBcsEndpoint(end, req)
 			localSvc.endpoints = append(localSvc.endpoints, ep)
 		}
@@ -169,7 +174,7 @@ func (bcs *BcsScheduler) wildChardRecords(req recordRequest) ([]service, error)
 
 		} else {
 			//get service ip address list
-			for _, end := range epItem.Endpoints {
+			for _, end := range bcs.selectEndpoints(epItem.Endpoints, req.clientZone) {
 				ep := bcs.formatBcsEndpoint(end, req)
 				localSvc.endpoints = append(localSvc.endpoints, ep)
 			}
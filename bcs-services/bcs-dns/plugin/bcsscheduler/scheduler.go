@@ -20,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	bcstypes "bk-bcs/bcs-common/common/types"
@@ -35,7 +36,6 @@ import (
 	"github.com/coredns/coredns/plugin/pkg/dnsutil"
 	"github.com/coredns/coredns/request"
 	"github.com/miekg/dns"
-	"golang.org/x/net/context"
 )
 
 //error definition
@@ -59,7 +59,8 @@ const (
 //NewScheduler create bcs scheduler backend
 func NewScheduler(config *ConfigItem) *BcsScheduler {
 	scheduler := &BcsScheduler{
-		conf: config,
+		conf:   config,
+		serial: uint32(time.Now().Unix()),
 	}
 	//create storage
 	var err error
@@ -101,6 +102,7 @@ type recordRequest struct {
 	namespace   string //namespace from domain
 	typeName    string //type name, like svc/pod, pod is not supported
 	zone        string //zone info
+	clientZone  string //zone of client, endpoints in this zone are preferred
 }
 
 func (r recordRequest) IsPodDNS() bool {
@@ -117,6 +119,7 @@ type BcsScheduler struct {
 	storage            storage.Storage //remote etcd storage for all cluster
 	registery          master.Master   //Master interface
 	Next               plugin.Handler  //next plugin
+	serial             uint32          //SOA serial, changed when service or endpoint changed
 }
 
 // Services communicates with the backend to retrieve the service definition. if func Services is called,
@@ -190,7 +193,23 @@ func (bcs *BcsScheduler) IsNameError(err error) bool {
 
 // Serial returns a SOA serial number to construct a SOA record.
 func (bcs *BcsScheduler) Serial(state request.Request) uint32 {
-	return uint32(time.Now().Unix())
+	return atomic.LoadUint32(&bcs.serial)
+}
+
+//touch change SOA serial when data changed, secondary server
+//will start zone transfer when serial changed, so serial is strictly
+//increasing even if data changed more than once in one second
+func (bcs *BcsScheduler) touch() {
+	for {
+		old := atomic.LoadUint32(&bcs.serial)
+		serial := uint32(time.Now().Unix())
+		if serial <= old {
+			serial = old + 1
+		}
+		if atomic.CompareAndSwapUint32(&bcs.serial, old, serial) {
+			return
+		}
+	}
 }
 
 // MinTTL returns the minimum TTL to be used in the SOA record.
@@ -198,12 +217,6 @@ func (bcs *BcsScheduler) MinTTL(state request.Request) uint32 {
 	return 30
 }
 
-// Transfer handles a zone transfer it writes to the client just
-// like any other handler.
-func (bcs *BcsScheduler) Transfer(ctx context.Context, state request.Request) (int, error) {
-	return dns.RcodeServerFailure, nil
-}
-
 // Debug returns a string used when returning debug services.
 func (bcs *BcsScheduler) Debug() string {
 	return "debug"
@@ -15,6 +15,7 @@ package bcsscheduler
 
 import (
 	"errors"
+	"net"
 	"strconv"
 
 	"bk-bcs/bcs-common/common"
@@ -88,6 +89,20 @@ type ConfigItem struct {
 	UpStream     []string    //dns upstream
 	Fallthrough  bool        //pass to next plugin when no data
 	Proxy        proxy.Proxy //proxy for upstream
+	//EndpointFilter only answer with endpoints passing these checks, healthy or ready
+	EndpointFilter []string
+	//ZoneAttribute node attribute name for zone, enable answers preferring client zone
+	ZoneAttribute string
+	//ZoneSubnets client subnets for every zone
+	ZoneSubnets []*ZoneSubnet
+	//TransferTo address or subnet allowed for zone transfer, * for all
+	TransferTo []string
+}
+
+//ZoneSubnet client subnet belongs to zone
+type ZoneSubnet struct {
+	Zone   string
+	Subnet *net.IPNet
 }
 
 func defaultConfigItem() *ConfigItem {
@@ -199,8 +214,57 @@ func schedulerParse(c *caddy.Controller) (*ConfigItem, error) {
 					config.Proxy = proxy.NewLookup(ups)
 				case "fallthrough":
 					config.Fallthrough = true
+				case "endpoint-filter":
+					args := c.RemainingArgs()
+					if len(args) == 0 {
+						return nil, c.ArgErr()
+					}
+					for _, arg := range args {
+						if arg != endpointFilterHealthy && arg != endpointFilterReady {
+							return nil, c.Errf("unknown endpoint-filter %s", arg)
+						}
+					}
+					config.EndpointFilter = args
+				case "zone-attribute":
+					args := c.RemainingArgs()
+					if len(args) == 1 {
+						config.ZoneAttribute = args[0]
+						continue
+					}
+					return nil, c.ArgErr()
+				case "zone-subnet":
+					// zone cidr [cidr...]
+					args := c.RemainingArgs()
+					if len(args) < 2 {
+						return nil, c.ArgErr()
+					}
+					for _, arg := range args[1:] {
+						_, subnet, err := net.ParseCIDR(arg)
+						if err != nil {
+							return nil, c.Errf("invalid zone-subnet %s: %s", arg, err.Error())
+						}
+						config.ZoneSubnets = append(config.ZoneSubnets, &ZoneSubnet{Zone: args[0], Subnet: subnet})
+					}
+				case "transfer":
+					// transfer to * | ip | cidr...
+					args := c.RemainingArgs()
+					if len(args) < 2 || args[0] != "to" {
+						return nil, c.ArgErr()
+					}
+					for _, arg := range args[1:] {
+						if arg == "*" || net.ParseIP(arg) != nil {
+							continue
+						}
+						if _, _, err := net.ParseCIDR(arg); err != nil {
+							return nil, c.Errf("invalid transfer address %s", arg)
+						}
+					}
+					config.TransferTo = append(config.TransferTo, args[1:]...)
 				}
 			}
+			if len(config.ZoneSubnets) != 0 && config.ZoneAttribute == "" {
+				return nil, errors.New("zone-subnet must be used with zone-attribute")
+			}
 			return config, nil
 		}
 	}
@@ -0,0 +1,83 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bcsscheduler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mholt/caddy"
+)
+
+func TestSchedulerParse(t *testing.T) {
+	tests := []struct {
+		input      string
+		shouldErr  bool
+		filter     []string
+		zoneAttr   string
+		subnets    int
+		transferTo []string
+	}{
+		{`bcsscheduler bcs.com {
+			cluster BCS-MESOS-10001
+		}`, false, nil, "", 0, nil},
+		{`bcsscheduler bcs.com {
+			cluster BCS-MESOS-10001
+			endpoint-filter healthy ready
+			zone-attribute zone
+			zone-subnet sz 10.1.0.0/16 10.2.0.0/16
+			zone-subnet sh 10.3.0.0/16
+			transfer to 10.0.0.1 192.168.0.0/24
+		}`, false, []string{"healthy", "ready"}, "zone", 3, []string{"10.0.0.1", "192.168.0.0/24"}},
+		{`bcsscheduler bcs.com {
+			endpoint-filter alive
+		}`, true, nil, "", 0, nil},
+		{`bcsscheduler bcs.com {
+			zone-subnet sz 10.1.0.0/16
+		}`, true, nil, "", 0, nil},
+		{`bcsscheduler bcs.com {
+			zone-attribute zone
+			zone-subnet sz 10.1.0.0
+		}`, true, nil, "", 0, nil},
+		{`bcsscheduler bcs.com {
+			transfer from 10.0.0.1
+		}`, true, nil, "", 0, nil},
+	}
+	for i, test := range tests {
+		c := caddy.NewTestController("dns", test.input)
+		config, err := schedulerParse(c)
+		if test.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: expected error but found none for input %s", i, test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: expected no error but found one for input %s, got: %v", i, test.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(config.EndpointFilter, test.filter) {
+			t.Errorf("Test %d: expected endpoint filter %v, got %v", i, test.filter, config.EndpointFilter)
+		}
+		if config.ZoneAttribute != test.zoneAttr {
+			t.Errorf("Test %d: expected zone attribute %s, got %s", i, test.zoneAttr, config.ZoneAttribute)
+		}
+		if len(config.ZoneSubnets) != test.subnets {
+			t.Errorf("Test %d: expected %d zone subnets, got %d", i, test.subnets, len(config.ZoneSubnets))
+		}
+		if !reflect.DeepEqual(config.TransferTo, test.transferTo) {
+			t.Errorf("Test %d: expected transfer to %v, got %v", i, test.transferTo, config.TransferTo)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bcsscheduler
+
+import (
+	"log"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/coredns/coredns/plugin"
+	"github.com/coredns/coredns/request"
+	"github.com/miekg/dns"
+	"golang.org/x/net/context"
+)
+
+//transferLength max size in bytes of records in one envelope
+const transferLength = 1000
+
+//transferAllowed check remote address with transfer to configuration
+func (bcs *BcsScheduler) transferAllowed(state request.Request) bool {
+	remote := net.ParseIP(state.IP())
+	for _, to := range bcs.conf.TransferTo {
+		if to == "*" {
+			return true
+		}
+		if ip := net.ParseIP(to); ip != nil {
+			if ip.Equal(remote) {
+				return true
+			}
+			continue
+		}
+		if _, subnet, err := net.ParseCIDR(to); err == nil && remote != nil && subnet.Contains(remote) {
+			return true
+		}
+	}
+	return false
+}
+
+// Transfer handles a zone transfer it writes to the client just
+// like any other handler. IXFR is answered with full zone like AXFR.
+func (bcs *BcsScheduler) Transfer(ctx context.Context, state request.Request) (int, error) {
+	if state.QType() != dns.TypeAXFR && state.QType() != dns.TypeIXFR {
+		return dns.RcodeServerFailure, nil
+	}
+	if state.Proto() != "tcp" {
+		return dns.RcodeRefused, nil
+	}
+	if !bcs.transferAllowed(state) {
+		log.Printf("[WARN] scheduler refuse zone transfer of %s to %s", state.Name(), state.IP())
+		return dns.RcodeRefused, nil
+	}
+	if state.Name() != bcs.PrimaryZone() {
+		log.Printf("[WARN] scheduler only support zone transfer of %s, request: %s", bcs.PrimaryZone(), state.Name())
+		return dns.RcodeNotAuth, nil
+	}
+
+	soa, err := plugin.SOA(bcs, state.Name(), state, plugin.Options{})
+	if err != nil {
+		return dns.RcodeServerFailure, err
+	}
+	records := append(soa, bcs.zoneRecords(state)...)
+	// add closing SOA to the end
+	records = append(records, soa...)
+
+	envelopes := splitEnvelopes(records, transferLength)
+	ch := make(chan *dns.Envelope, len(envelopes))
+	for _, env := range envelopes {
+		ch <- env
+	}
+	close(ch)
+
+	log.Printf("[INFO] scheduler outgoing transfer of %d records of zone %s to %s started", len(records), state.Name(), state.IP())
+	tr := new(dns.Transfer)
+	if err := tr.Out(state.W, state.Req, ch); err != nil {
+		log.Printf("[ERROR] scheduler transfer zone %s to %s failed, %s", state.Name(), state.IP(), err.Error())
+	}
+	state.W.Hijack()
+	return dns.RcodeSuccess, nil
+}
+
+//splitEnvelopes split records into envelopes no larger than length in bytes,
+//a record larger than length is sent in its own envelope
+func splitEnvelopes(records []dns.RR, length int) []*dns.Envelope {
+	var envelopes []*dns.Envelope
+	j, l := 0, 0
+	for i, r := range records {
+		size := dns.Len(r)
+		if i > j && l+size > length {
+			envelopes = append(envelopes, &dns.Envelope{RR: records[j:i]})
+			j, l = i, 0
+		}
+		l += size
+	}
+	if j < len(records) {
+		envelopes = append(envelopes, &dns.Envelope{RR: records[j:]})
+	}
+	return envelopes
+}
+
+//zoneRecords create all records of primary zone, including A records for
+//service and pod, SRV records for service ports. endpoint-filter is applied,
+//but client zone is not, secondary server serves all clients.
+func (bcs *BcsScheduler) zoneRecords(state request.Request) (records []dns.RR) {
+	ttl := bcs.MinTTL(state)
+	endpoints := bcs.endpointCache.ListEndpoints()
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].GetNamespace() != endpoints[j].GetNamespace() {
+			return endpoints[i].GetNamespace() < endpoints[j].GetNamespace()
+		}
+		return endpoints[i].GetName() < endpoints[j].GetName()
+	})
+	for _, bcsEndpoint := range endpoints {
+		domain := bcsEndpoint.GetName() + "." + bcsEndpoint.GetNamespace() + ".svc." + bcs.PrimaryZone()
+		bcsSvc := bcs.svcCache.GetServiceByEndpoint(bcsEndpoint)
+		if bcsSvc != nil && (bcsSvc.Spec.Type == serviceTypeClusterIP || bcsSvc.Spec.Type == serviceTypeIntegration) {
+			for _, ip := range bcsSvc.Spec.ClusterIP {
+				//domain in cluster ip is resolved recursively, skip in zone transfer
+				if addr := net.ParseIP(ip); addr != nil {
+					records = append(records, newA(domain, addr, ttl))
+				}
+			}
+			continue
+		}
+		for _, end := range bcs.selectEndpoints(bcsEndpoint.Endpoints, "") {
+			endpt := bcs.formatBcsEndpoint(end, recordRequest{})
+			addr := net.ParseIP(endpt.addr)
+			if addr == nil {
+				continue
+			}
+			records = append(records, newA(domain, addr, ttl))
+			if end.Target.Name == "" {
+				continue
+			}
+			podDomain := strings.ToLower(end.Target.Name) + "." + domain
+			records = append(records, newA(podDomain, addr, ttl))
+			for _, port := range end.Ports {
+				if port.Name == "" || port.Protocol == "" {
+					continue
+				}
+				name := "_" + strings.ToLower(port.Name) + "._" + strings.ToLower(port.Protocol) + "." + domain
+				records = append(records, &dns.SRV{
+					Hdr:      dns.RR_Header{Name: name, Rrtype: dns.TypeSRV, Class: dns.ClassINET, Ttl: ttl},
+					Priority: 10,
+					Weight:   10,
+					Port:     uint16(port.ContainerPort),
+					Target:   podDomain,
+				})
+			}
+		}
+	}
+	return records
+}
+
+func newA(name string, ip net.IP, ttl uint32) *dns.A {
+	return &dns.A{Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: ttl}, A: ip}
+}
@@ -0,0 +1,163 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package bcsscheduler
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	bcstypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-common/pkg/cache"
+
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+	"github.com/coredns/coredns/request"
+	"github.com/miekg/dns"
+	"golang.org/x/net/context"
+)
+
+func newTransferScheduler(transferTo []string) *BcsScheduler {
+	config := defaultConfigItem()
+	config.Cluster = "cluster"
+	config.Zones = []string{"bcs.com."}
+	config.EndpointFilter = []string{endpointFilterHealthy}
+	config.TransferTo = transferTo
+	bcs := &BcsScheduler{
+		conf:          config,
+		svcCache:      &ServiceCache{Store: cache.CreateCache(DNSDataKeyFunc)},
+		endpointCache: &EndpointCache{Store: cache.CreateCache(DNSDataKeyFunc)},
+	}
+	endpoint := &bcstypes.BcsEndpoint{
+		Endpoints: []bcstypes.Endpoint{
+			{
+				NetworkMode: "user",
+				ContainerIP: "127.0.0.1",
+				Target:      bcstypes.TargetRef{Name: "web-0"},
+				Ports:       []bcstypes.ContainerPort{{Name: "http", Protocol: "TCP", ContainerPort: 80}},
+			},
+			{
+				NetworkMode: "user",
+				ContainerIP: "127.0.0.2",
+				Healthy:     boolPtr(false),
+				Target:      bcstypes.TargetRef{Name: "web-1"},
+			},
+		},
+	}
+	endpoint.Name = "web"
+	endpoint.NameSpace = "default"
+	bcs.endpointCache.Store.Add(endpoint)
+	return bcs
+}
+
+func TestTransfer(t *testing.T) {
+	tests := []struct {
+		transferTo []string
+		qname      string
+		tcp        bool
+		rcode      int
+	}{
+		{nil, "cluster.bcs.com.", true, dns.RcodeRefused},
+		{[]string{"10.0.0.1"}, "cluster.bcs.com.", true, dns.RcodeRefused},
+		{[]string{"10.240.0.0/16"}, "other.bcs.com.", true, dns.RcodeNotAuth},
+		{[]string{"10.240.0.0/16"}, "cluster.bcs.com.", true, dns.RcodeSuccess},
+		{[]string{"*"}, "cluster.bcs.com.", true, dns.RcodeSuccess},
+		{[]string{"*"}, "cluster.bcs.com.", false, dns.RcodeRefused},
+	}
+	for i, tc := range tests {
+		bcs := newTransferScheduler(tc.transferTo)
+		m := new(dns.Msg)
+		m.SetAxfr(tc.qname)
+		rec := dnstest.NewMultiRecorder(&test.ResponseWriter{TCP: tc.tcp})
+		code, err := bcs.Transfer(context.TODO(), request.Request{W: rec, Req: m})
+		if err != nil {
+			t.Errorf("Test %d: expected no error, got %v", i, err)
+			continue
+		}
+		if code != tc.rcode {
+			t.Errorf("Test %d: expected rcode %d, got %d", i, tc.rcode, code)
+			continue
+		}
+		if code != dns.RcodeSuccess {
+			continue
+		}
+		var records []dns.RR
+		for _, msg := range rec.Msgs {
+			records = append(records, msg.Answer...)
+		}
+		// SOA, service A, pod A, SRV, SOA
+		if len(records) != 5 {
+			t.Errorf("Test %d: expected 5 records, got %d: %v", i, len(records), records)
+			continue
+		}
+		if records[0].Header().Rrtype != dns.TypeSOA || records[4].Header().Rrtype != dns.TypeSOA {
+			t.Errorf("Test %d: expected transfer wrapped by SOA, got %v", i, records)
+		}
+		srv, ok := records[3].(*dns.SRV)
+		if !ok || srv.Hdr.Name != "_http._tcp.web.default.svc.cluster.bcs.com." ||
+			srv.Target != "web-0.web.default.svc.cluster.bcs.com." || srv.Port != 80 {
+			t.Errorf("Test %d: unexpected SRV record %v", i, records[3])
+		}
+	}
+}
+
+func TestSplitEnvelopes(t *testing.T) {
+	var records []dns.RR
+	for i := 0; i < 7; i++ {
+		records = append(records, newA("web.default.svc.cluster.bcs.com.", net.ParseIP("127.0.0.1"), 10))
+	}
+	size := dns.Len(records[0])
+	tests := []struct {
+		count  int
+		length int
+		expect []int
+	}{
+		{0, 2 * size, nil},
+		{1, 2 * size, []int{1}},
+		{4, 2 * size, []int{2, 2}},
+		{5, 2 * size, []int{2, 2, 1}},
+		{6, 3 * size, []int{3, 3}},
+		{7, 3*size - 1, []int{2, 2, 2, 1}},
+		{2, size - 1, []int{1, 1}},
+	}
+	for i, tc := range tests {
+		envelopes := splitEnvelopes(records[:tc.count], tc.length)
+		var got []int
+		for _, env := range envelopes {
+			got = append(got, len(env.RR))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.expect) {
+			t.Errorf("Test %d: expected envelopes %v, got %v", i, tc.expect, got)
+		}
+	}
+}
+
+func TestTouchSerial(t *testing.T) {
+	now := uint32(time.Now().Unix())
+	bcs := &BcsScheduler{serial: now + 100}
+	//serial set ahead of clock is still increased
+	for i := uint32(1); i <= 3; i++ {
+		bcs.touch()
+		if serial := bcs.Serial(request.Request{}); serial != now+100+i {
+			t.Errorf("expect serial %d after touch %d, got %d", now+100+i, i, serial)
+		}
+	}
+	//serial follows clock
+	bcs = &BcsScheduler{serial: now - 100}
+	bcs.touch()
+	if serial := bcs.Serial(request.Request{}); serial < now {
+		t.Errorf("expect serial not less than %d, got %d", now, serial)
+	}
+}
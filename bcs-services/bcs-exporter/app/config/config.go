@@ -50,6 +50,16 @@ type Config struct {
 	OutputClientKey  string   `json:"output_client_key_file" value:"" usage:"Output client private key file(*.key)"`
 	OutputClientPwd  string   `json:"output_client_key_pwd" value:"" usage:"Output client private key password"`
 
+	MQTopic        string `json:"mq_topic" value:"bcs_{dataid}" usage:"topic template of mq_exporter, {dataid}, {cluster} and {namespace} are replaced"`
+	MQPartitionKey string `json:"mq_partition_key" value:"" usage:"partition key fields of mq_exporter separated by comma, available fields: dataid, cluster, namespace, name, ip"`
+	MQBatchSize    uint   `json:"mq_batch_size" value:"100" usage:"max messages of one batch sent by mq_exporter"`
+	MQBatchTimeout uint   `json:"mq_batch_timeout" value:"1000" usage:"milliseconds to wait before sending a batch which is not full"`
+	MQCompression  string `json:"mq_compression" value:"none" usage:"compression of mq_exporter: none, gzip, snappy, lz4"`
+	MQSASLUser     string `json:"mq_sasl_user" value:"" usage:"SASL/PLAIN user of mq_exporter, SASL is disabled if empty"`
+	MQSASLPassword string `json:"mq_sasl_password" value:"" usage:"SASL/PLAIN password of mq_exporter"`
+	MQSpoolDir     string `json:"mq_spool_dir" value:"" usage:"directory to spool messages while broker is unavailable, messages are dropped if empty"`
+	MQSpoolMaxSize uint   `json:"mq_spool_max_size" value:"1024" usage:"max MB of mq_exporter spool, oldest messages are dropped when exceeded"`
+
 	ListenIP        string
 	ListenPort      uint
 	ZKServerAddress string
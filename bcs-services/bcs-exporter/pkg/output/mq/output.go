@@ -0,0 +1,250 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-services/bcs-exporter/app/config"
+)
+
+// partition key fields
+const (
+	KeyDataID    = "dataid"
+	KeyCluster   = "cluster"
+	KeyNamespace = "namespace"
+	KeyName      = "name"
+	KeyIP        = "ip"
+
+	unknownValue   = "unknown"
+	replayInterval = 10 * time.Second
+)
+
+var invalidTopicChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// dataMeta bcs-metriccollector 上报数据中的对象信息
+type dataMeta struct {
+	ObjMeta struct {
+		Name        string `json:"name"`
+		NameSpace   string `json:"namespace"`
+		ClusterName string `json:"clusterName"`
+		IP          string `json:"ip"`
+	} `json:"objmeta"`
+}
+
+// Output 将数据批量发送到消息队列，broker 不可用时写入 spool
+type Output struct {
+	producer     Producer
+	spool        *spool
+	topic        string
+	keys         []string
+	batchSize    int
+	batchTimeout time.Duration
+	input        chan *Message
+}
+
+// NewOutput 创建 Output，spool_dir 为空时发送失败的消息直接丢弃
+func NewOutput(cfg *config.Config, producer Producer) (*Output, error) {
+	out := &Output{
+		producer:     producer,
+		topic:        cfg.MQTopic,
+		batchSize:    int(cfg.MQBatchSize),
+		batchTimeout: time.Duration(cfg.MQBatchTimeout) * time.Millisecond,
+		input:        make(chan *Message, 4096*2),
+	}
+	if out.topic == "" {
+		out.topic = "bcs_{dataid}"
+	}
+	if out.batchSize <= 0 {
+		out.batchSize = 100
+	}
+	if out.batchTimeout <= 0 {
+		out.batchTimeout = time.Second
+	}
+	for _, key := range strings.Split(cfg.MQPartitionKey, ",") {
+		key = strings.TrimSpace(key)
+		switch key {
+		case "":
+		case KeyDataID, KeyCluster, KeyNamespace, KeyName, KeyIP:
+			out.keys = append(out.keys, key)
+		default:
+			return nil, fmt.Errorf("unsupported partition key %s", key)
+		}
+	}
+	if cfg.MQSpoolDir != "" {
+		sp, err := newSpool(cfg.MQSpoolDir, int64(cfg.MQSpoolMaxSize)*1024*1024)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create spool, %v", err)
+		}
+		out.spool = sp
+	}
+	return out, nil
+}
+
+// needMeta 主题或者分区键是否需要解析数据中的对象信息
+func (out *Output) needMeta() bool {
+	if strings.Contains(out.topic, "{cluster}") || strings.Contains(out.topic, "{namespace}") {
+		return true
+	}
+	for _, key := range out.keys {
+		if key != KeyDataID {
+			return true
+		}
+	}
+	return false
+}
+
+// NewMessage 根据 dataID 和数据中的对象信息生成主题和分区键
+func (out *Output) NewMessage(dataID int, data []byte) *Message {
+	values := map[string]string{
+		KeyDataID: strconv.Itoa(dataID),
+	}
+	if out.needMeta() {
+		meta := &dataMeta{}
+		if err := json.Unmarshal(data, meta); err != nil {
+			blog.V(3).Infof("data of dataid %d is not json with objmeta: %v", dataID, err)
+		}
+		values[KeyCluster] = meta.ObjMeta.ClusterName
+		values[KeyNamespace] = meta.ObjMeta.NameSpace
+		values[KeyName] = meta.ObjMeta.Name
+		values[KeyIP] = meta.ObjMeta.IP
+	}
+	for k, v := range values {
+		if v == "" {
+			values[k] = unknownValue
+		}
+	}
+
+	topic := out.topic
+	for _, k := range []string{KeyDataID, KeyCluster, KeyNamespace} {
+		topic = strings.Replace(topic, "{"+k+"}", values[k], -1)
+	}
+	keys := make([]string, 0, len(out.keys))
+	for _, k := range out.keys {
+		keys = append(keys, values[k])
+	}
+	return &Message{
+		Topic: invalidTopicChars.ReplaceAllString(topic, "_"),
+		Key:   strings.Join(keys, "/"),
+		Value: data,
+	}
+}
+
+// Input 接收数据，队列满时丢弃
+func (out *Output) Input(dataID int, data []byte) {
+	select {
+	case out.input <- out.NewMessage(dataID, data):
+	default:
+		blog.Warn("mq output pipe is full")
+	}
+}
+
+// Run 批量发送消息并定时重发 spool 中的批次
+func (out *Output) Run(ctx context.Context) {
+	flush := time.NewTicker(out.batchTimeout)
+	defer flush.Stop()
+	replay := time.NewTicker(replayInterval)
+	defer replay.Stop()
+
+	batch := make([]*Message, 0, out.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			out.send(batch)
+			if err := out.producer.Close(); err != nil {
+				blog.Errorf("failed to close mq producer, error info is %v", err)
+			}
+			return
+		case msg := <-out.input:
+			batch = append(batch, msg)
+			if len(batch) >= out.batchSize {
+				out.send(batch)
+				batch = make([]*Message, 0, out.batchSize)
+			}
+		case <-flush.C:
+			if len(batch) > 0 {
+				out.send(batch)
+				batch = make([]*Message, 0, out.batchSize)
+			}
+		case <-replay.C:
+			out.replay()
+		}
+	}
+}
+
+// send 发送一批消息，spool 中有积压时先重发积压的批次，失败则写入 spool
+func (out *Output) send(batch []*Message) {
+	if len(batch) == 0 {
+		return
+	}
+	if out.spool != nil && !out.spool.empty() && !out.replay() {
+		out.spoolBatch(batch, nil)
+		return
+	}
+	if err := out.producer.SendMessages(batch); err != nil {
+		out.spoolBatch(failedMessages(batch, err), err)
+		return
+	}
+	blog.V(3).Infof("mq output success to send %d messages", len(batch))
+}
+
+func (out *Output) spoolBatch(batch []*Message, sendErr error) {
+	if sendErr != nil {
+		blog.Errorf("failed to send %d messages to mq, error info is %v", len(batch), sendErr)
+	}
+	if out.spool == nil {
+		blog.Warnf("mq spool is disabled, drop %d messages", len(batch))
+		return
+	}
+	if err := out.spool.put(batch); err != nil {
+		blog.Errorf("failed to spool %d messages, error info is %v", len(batch), err)
+	}
+}
+
+// replay 按顺序重发 spool 中的批次，全部发送成功时返回 true
+func (out *Output) replay() bool {
+	if out.spool == nil {
+		return true
+	}
+	for {
+		name, msgs, err := out.spool.oldest()
+		if name == "" {
+			return true
+		}
+		if err != nil {
+			blog.Errorf("drop broken spool batch %s, error info is %v", name, err)
+			out.spool.remove(name)
+			continue
+		}
+		if err := out.producer.SendMessages(msgs); err != nil {
+			blog.Warnf("failed to replay spool batch %s, error info is %v", name, err)
+			if failed := failedMessages(msgs, err); len(failed) < len(msgs) {
+				// 只保留发送失败的消息，避免重复发送
+				if err := out.spool.rewrite(name, failed); err != nil {
+					blog.Errorf("failed to rewrite spool batch %s, error info is %v", name, err)
+				}
+			}
+			return false
+		}
+		blog.Infof("success to replay spool batch %s with %d messages", name, len(msgs))
+		out.spool.remove(name)
+	}
+}
@@ -0,0 +1,300 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package mq
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"bk-bcs/bcs-services/bcs-exporter/app/config"
+
+	"github.com/Shopify/sarama"
+)
+
+const testData = `{"dataid":1430,"objmeta":{"name":"demo-0","namespace":"default","clusterName":"BCS-K8S-10001","ip":"127.0.0.1"}}`
+
+// fakeProducer 模拟可以宕机的 broker，failTopics 中的主题发送失败
+type fakeProducer struct {
+	down       bool
+	failTopics map[string]bool
+	sent       []*Message
+}
+
+func (fp *fakeProducer) SendMessages(msgs []*Message) error {
+	if fp.down {
+		return fmt.Errorf("broker is down")
+	}
+	var failed []*Message
+	for _, msg := range msgs {
+		if fp.failTopics[msg.Topic] {
+			failed = append(failed, msg)
+			continue
+		}
+		fp.sent = append(fp.sent, msg)
+	}
+	if len(failed) > 0 {
+		return &PartialError{Failed: failed, Err: fmt.Errorf("partition is offline")}
+	}
+	return nil
+}
+
+func (fp *fakeProducer) Close() error {
+	return nil
+}
+
+func TestNewMessage(t *testing.T) {
+	tests := []struct {
+		topic string
+		key   string
+		data  string
+		expT  string
+		expK  string
+	}{
+		{"bcs_{dataid}", "", testData, "bcs_1430", ""},
+		{"bcs_{dataid}_{cluster}_{namespace}", "namespace,name", testData, "bcs_1430_BCS-K8S-10001_default", "default/demo-0"},
+		{"bcs_{cluster}", "dataid,ip", "plain text", "bcs_unknown", "1430/unknown"},
+		{"bcs:{namespace}", "", `{"objmeta":{"namespace":"a/b"}}`, "bcs_a_b", ""},
+	}
+	for _, test := range tests {
+		out, err := NewOutput(&config.Config{MQTopic: test.topic, MQPartitionKey: test.key}, &fakeProducer{})
+		if err != nil {
+			t.Fatalf("failed to create output: %v", err)
+		}
+		msg := out.NewMessage(1430, []byte(test.data))
+		if msg.Topic != test.expT || msg.Key != test.expK {
+			t.Errorf("template %s key %s: expect %s %s, got %s %s", test.topic, test.key, test.expT, test.expK, msg.Topic, msg.Key)
+		}
+	}
+
+	if _, err := NewOutput(&config.Config{MQPartitionKey: "pod"}, &fakeProducer{}); err == nil {
+		t.Errorf("expect error with unsupported partition key")
+	}
+}
+
+func TestOutputSpool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mq-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	producer := &fakeProducer{down: true}
+	out, err := NewOutput(&config.Config{MQSpoolDir: dir, MQSpoolMaxSize: 1}, producer)
+	if err != nil {
+		t.Fatalf("failed to create output: %v", err)
+	}
+
+	// broker 不可用时批次按顺序落盘
+	for i := 0; i < 3; i++ {
+		out.send([]*Message{out.NewMessage(i, []byte(testData))})
+	}
+	if names, _ := out.spool.segments(); len(names) != 3 {
+		t.Fatalf("expect 3 spooled batches, got %d", len(names))
+	}
+
+	// broker 恢复后先重发积压的批次
+	producer.down = false
+	out.send([]*Message{out.NewMessage(3, []byte(testData))})
+	if !out.spool.empty() {
+		t.Errorf("expect spool is empty after replay")
+	}
+	if len(producer.sent) != 4 {
+		t.Fatalf("expect 4 messages sent, got %d", len(producer.sent))
+	}
+	for i, msg := range producer.sent {
+		if exp := fmt.Sprintf("bcs_%d", i); msg.Topic != exp {
+			t.Errorf("message %d: expect topic %s, got %s", i, exp, msg.Topic)
+		}
+	}
+}
+
+func TestOutputSpoolPartialFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mq-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	producer := &fakeProducer{failTopics: map[string]bool{"bcs_1": true}}
+	out, err := NewOutput(&config.Config{MQSpoolDir: dir, MQSpoolMaxSize: 1}, producer)
+	if err != nil {
+		t.Fatalf("failed to create output: %v", err)
+	}
+
+	// 只有失败的消息落盘
+	out.send([]*Message{out.NewMessage(0, []byte(testData)), out.NewMessage(1, []byte(testData))})
+	name, msgs, err := out.spool.oldest()
+	if err != nil || len(msgs) != 1 || msgs[0].Topic != "bcs_1" {
+		t.Fatalf("expect only bcs_1 spooled, got %v %v", msgs, err)
+	}
+
+	// 重发时部分失败，批次中只保留仍然失败的消息
+	if err := out.spool.rewrite(name, append(msgs, out.NewMessage(2, []byte(testData)))); err != nil {
+		t.Fatal(err)
+	}
+	if out.replay() {
+		t.Fatalf("expect replay failed")
+	}
+	_, msgs, err = out.spool.oldest()
+	if err != nil || len(msgs) != 1 || msgs[0].Topic != "bcs_1" {
+		t.Fatalf("expect only bcs_1 left in spool, got %v %v", msgs, err)
+	}
+
+	producer.failTopics = nil
+	if !out.replay() || !out.spool.empty() {
+		t.Fatalf("expect spool is empty after replay")
+	}
+	var topics []string
+	for _, msg := range producer.sent {
+		topics = append(topics, msg.Topic)
+	}
+	if fmt.Sprint(topics) != "[bcs_0 bcs_2 bcs_1]" {
+		t.Errorf("expect each message sent once, got %v", topics)
+	}
+}
+
+func TestSpoolLimit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mq-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sp, err := newSpool(dir, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := sp.put([]*Message{{Topic: fmt.Sprintf("t%d", i), Value: []byte(testData)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names, total := sp.segments()
+	if len(names) != 1 || total > 200 {
+		t.Errorf("expect only the newest batch kept, got %d batches of %d bytes", len(names), total)
+	}
+	_, msgs, err := sp.oldest()
+	if err != nil || len(msgs) != 1 || msgs[0].Topic != "t4" {
+		t.Errorf("expect the newest batch t4, got %v %v", msgs, err)
+	}
+}
+
+func TestKafkaProducer(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("bcs_1430", 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+
+	producer, err := NewKafkaProducer(&config.Config{OutputAddress: broker.Addr(), MQCompression: "gzip"})
+	if err != nil {
+		t.Fatalf("failed to create kafka producer: %v", err)
+	}
+	defer producer.Close()
+
+	msgs := []*Message{
+		{Topic: "bcs_1430", Key: "default", Value: []byte(testData)},
+		{Topic: "bcs_1430", Value: []byte(testData)},
+	}
+	if err := producer.SendMessages(msgs); err != nil {
+		t.Fatalf("failed to send messages: %v", err)
+	}
+
+	produced := 0
+	for _, rr := range broker.History() {
+		if _, ok := rr.Request.(*sarama.ProduceRequest); ok {
+			produced++
+		}
+	}
+	if produced == 0 {
+		t.Errorf("expect produce request sent to broker")
+	}
+}
+
+func TestKafkaProducerPartialFailure(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("bcs_1430", 0, broker.BrokerID()).
+			SetLeader("bcs_1431", 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t).
+			SetError("bcs_1431", 0, sarama.ErrMessageSizeTooLarge),
+	})
+
+	producer, err := NewKafkaProducer(&config.Config{OutputAddress: broker.Addr()})
+	if err != nil {
+		t.Fatalf("failed to create kafka producer: %v", err)
+	}
+	defer producer.Close()
+
+	msgs := []*Message{
+		{Topic: "bcs_1430", Value: []byte(testData)},
+		{Topic: "bcs_1431", Value: []byte(testData)},
+	}
+	err = producer.SendMessages(msgs)
+	pe, ok := err.(*PartialError)
+	if !ok {
+		t.Fatalf("expect partial error, got %v", err)
+	}
+	if len(pe.Failed) != 1 || pe.Failed[0] != msgs[1] {
+		t.Errorf("expect only bcs_1431 failed, got %v", pe.Failed)
+	}
+}
+
+func TestNewSaramaConfig(t *testing.T) {
+	tests := []struct {
+		cfg         *config.Config
+		compression sarama.CompressionCodec
+		sasl        bool
+		expectErr   bool
+	}{
+		{cfg: &config.Config{}, compression: sarama.CompressionNone},
+		{cfg: &config.Config{MQCompression: "GZIP"}, compression: sarama.CompressionGZIP},
+		{cfg: &config.Config{MQCompression: "snappy"}, compression: sarama.CompressionSnappy},
+		{cfg: &config.Config{MQCompression: "lz4", MQSASLUser: "bcs", MQSASLPassword: "pass"}, compression: sarama.CompressionLZ4, sasl: true},
+		{cfg: &config.Config{MQCompression: "zstd"}, expectErr: true},
+	}
+	for _, test := range tests {
+		conf, err := newSaramaConfig(test.cfg)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("compression %s: expect error", test.cfg.MQCompression)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("compression %s: unexpected error %v", test.cfg.MQCompression, err)
+			continue
+		}
+		if conf.Producer.Compression != test.compression {
+			t.Errorf("compression %s: expect codec %v, got %v", test.cfg.MQCompression, test.compression, conf.Producer.Compression)
+		}
+		if conf.Net.SASL.Enable != test.sasl || (test.sasl && conf.Net.SASL.User != test.cfg.MQSASLUser) {
+			t.Errorf("compression %s: expect sasl %t, got %t", test.cfg.MQCompression, test.sasl, conf.Net.SASL.Enable)
+		}
+		if conf.Producer.Retry.Max != 0 || !conf.Producer.Return.Successes || conf.Producer.RequiredAcks != sarama.WaitForAll {
+			t.Errorf("compression %s: unexpected producer config %+v", test.cfg.MQCompression, conf.Producer)
+		}
+		if err := conf.Validate(); err != nil {
+			t.Errorf("compression %s: invalid config %v", test.cfg.MQCompression, err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package mq sends exporter data to kafka compatible message queue
+package mq
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bk-bcs/bcs-common/common/ssl"
+	"bk-bcs/bcs-services/bcs-exporter/app/config"
+
+	"github.com/Shopify/sarama"
+)
+
+// Message 发送到消息队列的一条消息
+type Message struct {
+	Topic string `json:"topic"`
+	Key   string `json:"key,omitempty"`
+	Value []byte `json:"value"`
+}
+
+// Producer 消息队列生产者，部分消息发送失败时返回 *PartialError
+type Producer interface {
+	SendMessages(msgs []*Message) error
+	Close() error
+}
+
+// PartialError 一批消息中部分发送失败，Failed 为失败的消息
+type PartialError struct {
+	Failed []*Message
+	Err    error
+}
+
+func (pe *PartialError) Error() string {
+	return fmt.Sprintf("%d messages failed, %v", len(pe.Failed), pe.Err)
+}
+
+// failedMessages 返回一批消息中发送失败的部分
+func failedMessages(batch []*Message, err error) []*Message {
+	if pe, ok := err.(*PartialError); ok {
+		return pe.Failed
+	}
+	return batch
+}
+
+// kafkaProducer kafka 协议的生产者
+type kafkaProducer struct {
+	producer sarama.SyncProducer
+}
+
+// newSaramaConfig 根据 exporter 配置生成 sarama 配置
+func newSaramaConfig(cfg *config.Config) (*sarama.Config, error) {
+	conf := sarama.NewConfig()
+	conf.ClientID = "bcs-exporter"
+	conf.Producer.RequiredAcks = sarama.WaitForAll
+	conf.Producer.Partitioner = sarama.NewHashPartitioner
+	conf.Producer.Return.Successes = true
+	// 失败的消息由 spool 负责重发
+	conf.Producer.Retry.Max = 0
+	conf.Producer.Timeout = 10 * time.Second
+	conf.Net.DialTimeout = 10 * time.Second
+
+	switch strings.ToLower(cfg.MQCompression) {
+	case "", "none":
+		conf.Producer.Compression = sarama.CompressionNone
+	case "gzip":
+		conf.Producer.Compression = sarama.CompressionGZIP
+	case "snappy":
+		conf.Producer.Compression = sarama.CompressionSnappy
+	case "lz4":
+		conf.Producer.Compression = sarama.CompressionLZ4
+		conf.Version = sarama.V0_10_0_0
+	default:
+		return nil, fmt.Errorf("unsupported compression %s", cfg.MQCompression)
+	}
+
+	if cfg.MQSASLUser != "" {
+		conf.Net.SASL.Enable = true
+		conf.Net.SASL.Handshake = true
+		conf.Net.SASL.User = cfg.MQSASLUser
+		conf.Net.SASL.Password = cfg.MQSASLPassword
+	}
+
+	if cfg.ClientCert != nil && (cfg.ClientCert.IsSSL || cfg.ClientCert.CAFile != "") {
+		tlsConf, err := ssl.ClientTslConfVerity(cfg.ClientCert.CAFile, cfg.ClientCert.CertFile, cfg.ClientCert.KeyFile, cfg.ClientCert.CertPasswd)
+		if err != nil {
+			return nil, fmt.Errorf("load tls config failed, %v", err)
+		}
+		conf.Net.TLS.Enable = true
+		conf.Net.TLS.Config = tlsConf
+	}
+	return conf, nil
+}
+
+// NewKafkaProducer 创建 kafka 生产者，broker 地址为 output_address，以逗号分隔
+func NewKafkaProducer(cfg *config.Config) (Producer, error) {
+	if cfg.OutputAddress == "" {
+		return nil, fmt.Errorf("not set the broker address")
+	}
+	conf, err := newSaramaConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := sarama.NewSyncProducer(strings.Split(cfg.OutputAddress, ","), conf)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaProducer{producer: producer}, nil
+}
+
+// SendMessages 同步发送一批消息
+func (kp *kafkaProducer) SendMessages(msgs []*Message) error {
+	pMsgs := make([]*sarama.ProducerMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		pMsg := &sarama.ProducerMessage{
+			Topic:    msg.Topic,
+			Value:    sarama.ByteEncoder(msg.Value),
+			Metadata: msg,
+		}
+		if msg.Key != "" {
+			pMsg.Key = sarama.StringEncoder(msg.Key)
+		}
+		pMsgs = append(pMsgs, pMsg)
+	}
+	err := kp.producer.SendMessages(pMsgs)
+	pErrs, ok := err.(sarama.ProducerErrors)
+	if !ok || len(pErrs) == 0 {
+		return err
+	}
+	failed := make([]*Message, 0, len(pErrs))
+	for _, pErr := range pErrs {
+		msg, ok := pErr.Msg.Metadata.(*Message)
+		if !ok {
+			return err
+		}
+		failed = append(failed, msg)
+	}
+	return &PartialError{Failed: failed, Err: pErrs[0].Err}
+}
+
+// Close 关闭生产者
+func (kp *kafkaProducer) Close() error {
+	return kp.producer.Close()
+}
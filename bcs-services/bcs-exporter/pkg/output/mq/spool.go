@@ -0,0 +1,152 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package mq
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bk-bcs/bcs-common/common/blog"
+)
+
+const spoolSuffix = ".spool"
+
+// spool broker 不可用时将消息批次落盘，每个批次一个文件，超过容量时丢弃最旧的批次
+type spool struct {
+	lock    sync.Mutex
+	dir     string
+	maxSize int64
+	seq     uint64
+}
+
+func newSpool(dir string, maxSize int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &spool{dir: dir, maxSize: maxSize}, nil
+}
+
+// segments 返回按时间排序的批次文件及总大小
+func (s *spool) segments() ([]string, int64) {
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		blog.Errorf("failed to read spool dir %s, error info is %v", s.dir, err)
+		return nil, 0
+	}
+	var names []string
+	var total int64
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), spoolSuffix) {
+			continue
+		}
+		names = append(names, info.Name())
+		total += info.Size()
+	}
+	sort.Strings(names)
+	return names, total
+}
+
+// put 将一批消息写入 spool
+func (s *spool) put(msgs []*Message) error {
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.seq++
+	name := fmt.Sprintf("%020d-%010d%s", time.Now().UnixNano(), s.seq, spoolSuffix)
+	tmp := filepath.Join(s.dir, name+".tmp")
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		return err
+	}
+
+	names, total := s.segments()
+	for len(names) > 1 && total > s.maxSize {
+		path := filepath.Join(s.dir, names[0])
+		if info, err := os.Stat(path); err == nil {
+			total -= info.Size()
+		}
+		blog.Warnf("spool exceeds %d bytes, drop the oldest batch %s", s.maxSize, names[0])
+		os.Remove(path)
+		names = names[1:]
+	}
+	return nil
+}
+
+// oldest 返回最旧的批次，没有批次时 name 为空
+func (s *spool) oldest() (string, []*Message, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	names, _ := s.segments()
+	if len(names) == 0 {
+		return "", nil, nil
+	}
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, names[0]))
+	if err != nil {
+		return names[0], nil, err
+	}
+	var msgs []*Message
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return names[0], nil, err
+	}
+	return names[0], msgs, nil
+}
+
+// rewrite 用部分未发送的消息替换批次，保持批次顺序
+func (s *spool) rewrite(name string, msgs []*Message) error {
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tmp := filepath.Join(s.dir, name+".tmp")
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, name))
+}
+
+// remove 删除已发送的批次
+func (s *spool) remove(name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	os.Remove(filepath.Join(s.dir, name))
+}
+
+// empty 是否没有待发送的批次
+func (s *spool) empty() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	names, _ := s.segments()
+	return len(names) == 0
+}
@@ -0,0 +1,77 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"bk-bcs/bcs-common/common/tcp/protocol"
+	"bk-bcs/bcs-services/bcs-exporter/app/config"
+	"bk-bcs/bcs-services/bcs-exporter/pkg"
+	"bk-bcs/bcs-services/bcs-exporter/pkg/output"
+	"bk-bcs/bcs-services/bcs-exporter/pkg/output/mq"
+)
+
+// mqOutput 将数据发送到 kafka 消息队列
+type mqOutput struct {
+	out *mq.Output
+	cfg *config.Config
+}
+
+//Init plugin entrance
+func Init(_ *config.Config) (output.PluginIf, error) { //nolint
+	return &mqOutput{}, nil
+}
+
+// Key 返回唯一编码，决定消息的路由，全局唯一
+func (cli *mqOutput) Key() output.PluginKey {
+	return output.PluginKey(protocol.MQExporterPlugin)
+}
+
+// Name 返回Output 插件的名
+func (cli *mqOutput) Name() output.PluginName {
+	return output.PluginName("mq_exporter")
+}
+
+// SetCfg 设置配置，创建生产者并启动发送协程
+func (cli *mqOutput) SetCfg(cfg *config.Config) error {
+	cli.cfg = cfg
+	producer, err := mq.NewKafkaProducer(cfg)
+	if err != nil {
+		return err
+	}
+	out, err := mq.NewOutput(cfg, producer)
+	if err != nil {
+		producer.Close()
+		return err
+	}
+	cli.out = out
+	go out.Run(context.Background())
+	return nil
+}
+
+// AddData 接收外部发送过来的数据
+func (cli *mqOutput) AddData(mapStr pkg.MapStr) error {
+	dataID, ok := mapStr["extID"].(int)
+	if !ok {
+		return fmt.Errorf("extID not found")
+	}
+	data, ok := mapStr["data"].([]byte)
+	if !ok {
+		return fmt.Errorf("data is not byte")
+	}
+	cli.out.Input(dataID, data)
+	return nil
+}
@@ -14,6 +14,7 @@
 package app
 
 import (
+	alarm "bk-bcs/bcs-common/common/bcs-health/api"
 	"bk-bcs/bcs-common/common/blog"
 	"bk-bcs/bcs-services/bcs-metriccollector/app/config"
 	"bk-bcs/bcs-services/bcs-metriccollector/pkg/collector"
@@ -46,6 +47,16 @@ func Run(cfg *config.Config) error {
 		return err
 	}
 
+	// alarm of series limit is sent through bcs-health
+	tls := alarm.TLSConfig{
+		CaFile:   cfg.CAFile,
+		CertFile: cfg.ClientCertFile,
+		KeyFile:  cfg.ClientKeyFile,
+	}
+	if err := alarm.NewBcsHealth(cfg.ZKServerAddress, tls); nil != err {
+		blog.Errorf("new bcs health instance failed. err: %s", err.Error())
+	}
+
 	out, outErr := output.New(context.TODO(), cfg)
 	if nil != outErr {
 		blog.Error("failed to create output object, error info is %s", outErr.Error())
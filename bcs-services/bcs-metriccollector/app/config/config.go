@@ -49,6 +49,15 @@ type Config struct {
 	conf.LocalConfig
 	RunMode               RunType              `json:"run_mode" value:"container" usage:"should be one of container or traditional. container for containerized app in mesos/k8s, traditional for traditional app."`
 	ExporterType          int                  `json:"exporter_type" value:"3" usage:"the type of exporter"`
+	DisableExporter       bool                 `json:"disable_exporter" value:"false" usage:"do not send data to bcs-exporter, useful when only prometheus remote write is needed"`
+	RemoteWriteURL        string               `json:"remote_write_url" value:"" usage:"prometheus remote write url, remote write is disabled if empty"`
+	RemoteWriteShards     int                  `json:"remote_write_shards" value:"4" usage:"concurrent shards for prometheus remote write"`
+	RemoteWriteBatchSize  int                  `json:"remote_write_batch_size" value:"500" usage:"max samples in one prometheus remote write request"`
+	RemoteWriteFlush      int                  `json:"remote_write_flush_interval" value:"5" usage:"seconds to flush samples in shard even if batch is not full"`
+	RemoteWriteRetries    int                  `json:"remote_write_max_retries" value:"3" usage:"max retries for recoverable remote write failure without wal, retries until succeeded with wal"`
+	RemoteWriteTimeout    int                  `json:"remote_write_timeout" value:"30" usage:"timeout seconds of one remote write request"`
+	RemoteWriteWALDir     string               `json:"remote_write_wal_dir" value:"" usage:"directory of remote write ahead log, samples are kept until sent and replayed in order after restart. disabled if empty"`
+	HTTPSDPort            uint                 `json:"http_sd_port" value:"0" usage:"port to expose collector configs as prometheus http_sd targets, disabled if 0"`
 	ZKServerAddress       string               // discovery
 	MetricClientCertDir   string               // client cert directory of the metric service client
 	MetricClientCert      *CertConfig          // cert of the  metric service client
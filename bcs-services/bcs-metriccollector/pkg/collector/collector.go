@@ -135,6 +135,12 @@ func (cli *collector) fetchData(ctx context.Context, cfg types.CollectorCfg) {
 		return
 	}
 
+	filter, err := newSeriesFilter(cfg.FilterConfig)
+	if err != nil {
+		blog.Errorf("get metric addr[%s] with dataid[%s] and name[%s] failed, invalid filter config, exist now. err: %v", address, cfg.DataID, cfg.Meta.Name, err)
+		return
+	}
+
 	client := httpclient.NewHttpClient()
 	client.SetTlsVerityConfig(tlsCfg)
 
@@ -176,7 +182,35 @@ func (cli *collector) fetchData(ctx context.Context, cfg types.CollectorCfg) {
 					blog.Errorf("parse prometheus metric failed, addr[%s], err: %v ", address, err)
 					continue
 				}
+				exceeded := 0
+				if !filter.empty() {
+					exceeded = filter.filterOldVersion(data)
+				}
 				msg.Prometheus = data
+
+				if cli.cfg.RemoteWriteURL != "" {
+					families, err := ParsePromFamilies(bytes.NewReader(textData))
+					if err != nil {
+						blog.Errorf("parse prometheus metric families failed, addr[%s], err: %v ", address, err)
+						continue
+					}
+					msg.ConstLabels = cfg.PrometheusConstLabels
+					if cli.clusterID != TraditionalClusterID {
+						msg.ClusterID = cli.clusterID
+					}
+					msg.Series = output.ConvertFamilies(msg, families, time.Now())
+					if !filter.empty() {
+						var seriesExceeded int
+						msg.Series, seriesExceeded = filter.filterSeries(msg.Series)
+						if seriesExceeded > exceeded {
+							exceeded = seriesExceeded
+						}
+					}
+				}
+
+				if exceeded > 0 {
+					cli.sendSeriesLimitAlarm(cfg, exceeded)
+				}
 			} else {
 				msg.Data = string(textData)
 			}
@@ -200,6 +234,10 @@ func (cli *collector) Run(ctx context.Context, cfg *config.Config) error {
 	blog.Infof("start sync data per %d seconds", duration)
 	go cli.syncConfig(ctx, time.Duration(duration)*time.Second)
 
+	if cfg.HTTPSDPort > 0 {
+		go cli.runHTTPSD(cfg.LocalIP, cfg.HTTPSDPort)
+	}
+
 	pool := &agentPool{}
 	for {
 		select {
@@ -114,5 +114,5 @@ func initialMetricServer(cfg config.Config, roleC role.RoleInterface) error {
 		}
 	}
 
-	return metric.NewMetricController(c, health)
+	return metric.NewMetricController(c, health, output.Metrics()...)
 }
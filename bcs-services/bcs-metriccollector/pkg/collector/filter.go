@@ -0,0 +1,166 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	alarm "bk-bcs/bcs-common/common/bcs-health/api"
+	"bk-bcs/bcs-common/common/blog"
+	bcsType "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-common/common/version"
+	"bk-bcs/bcs-services/bcs-metriccollector/pkg/output"
+	"bk-bcs/bcs-services/bcs-metriccollector/pkg/output/prompb"
+	"bk-bcs/bcs-services/bcs-metriccollector/pkg/relabel"
+	"bk-bcs/bcs-services/bcs-metricservice/pkg/types"
+)
+
+// alarm of series limit is sent at most once in seriesLimitAlarmSeconds for a collector config
+const seriesLimitAlarmSeconds uint16 = 600
+
+// seriesFilter filters scraped series by metric name, relabel rules and series limit
+type seriesFilter struct {
+	allow     []*regexp.Regexp
+	deny      []*regexp.Regexp
+	relabeler *relabel.Relabeler
+	limit     int
+}
+
+func newSeriesFilter(cfg types.MetricFilterCfg) (*seriesFilter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	f := &seriesFilter{limit: cfg.SeriesLimit}
+	for _, re := range cfg.AllowList {
+		f.allow = append(f.allow, regexp.MustCompile("^(?:"+re+")$"))
+	}
+	for _, re := range cfg.DenyList {
+		f.deny = append(f.deny, regexp.MustCompile("^(?:"+re+")$"))
+	}
+	relabeler, err := relabel.New(cfg.RelabelConfigs)
+	if err != nil {
+		return nil, err
+	}
+	f.relabeler = relabeler
+	return f, nil
+}
+
+// empty return true if the filter does nothing
+func (f *seriesFilter) empty() bool {
+	return len(f.allow) == 0 && len(f.deny) == 0 && f.relabeler.Empty() && f.limit == 0
+}
+
+func (f *seriesFilter) nameAllowed(name string) bool {
+	for _, re := range f.deny {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	if len(f.allow) == 0 {
+		return true
+	}
+	for _, re := range f.allow {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// process filters one series whose labels contain __name__, nil is returned if dropped
+func (f *seriesFilter) process(labels map[string]string) map[string]string {
+	if !f.nameAllowed(labels[relabel.MetricNameLabel]) {
+		return nil
+	}
+	if f.relabeler.Empty() {
+		return labels
+	}
+	labels = f.relabeler.Process(labels)
+	if labels == nil || labels[relabel.MetricNameLabel] == "" {
+		return nil
+	}
+	return labels
+}
+
+// filterSeries filters remote write series, returns the kept series and number of series over the limit
+func (f *seriesFilter) filterSeries(series []*prompb.TimeSeries) ([]*prompb.TimeSeries, int) {
+	result := make([]*prompb.TimeSeries, 0, len(series))
+	exceeded := 0
+	for _, s := range series {
+		labels := f.process(output.LabelsToMap(s.Labels))
+		if labels == nil {
+			continue
+		}
+		if f.limit > 0 && len(result) >= f.limit {
+			exceeded++
+			continue
+		}
+		s.Labels = output.MapToLabels(labels)
+		result = append(result, s)
+	}
+	return result, exceeded
+}
+
+// filterOldVersion filters the metrics parsed to old version, returns number of metrics over the limit
+func (f *seriesFilter) filterOldVersion(data *CollectorWrapper) int {
+	result := make([]*PromMetric, 0, len(data.Collector.Metrics))
+	exceeded := 0
+	for _, m := range data.Collector.Metrics {
+		labels := make(map[string]string, len(m.Labels)+1)
+		for k, v := range m.Labels {
+			labels[k] = v
+		}
+		labels[relabel.MetricNameLabel] = m.Name
+		if labels = f.process(labels); labels == nil {
+			continue
+		}
+		if f.limit > 0 && len(result) >= f.limit {
+			exceeded++
+			continue
+		}
+		m.Name = labels[relabel.MetricNameLabel]
+		delete(labels, relabel.MetricNameLabel)
+		m.Labels = labels
+		result = append(result, m)
+	}
+	data.Collector.Metrics = result
+	return exceeded
+}
+
+// sendSeriesLimitAlarm raises an alarm through bcs-health when the series limit of cfg is exceeded
+func (cli *collector) sendSeriesLimitAlarm(cfg types.CollectorCfg, exceeded int) {
+	message := fmt.Sprintf("metric %s of %s/%s from %s exceeds series limit %d, %d series are dropped",
+		cfg.CfgKey, cfg.Meta.NameSpace, cfg.Meta.Name, cfg.Address, cfg.FilterConfig.SeriesLimit, exceeded)
+	blog.Warnf("%s", message)
+
+	convergenceSeconds := seriesLimitAlarmSeconds
+	health := alarm.HealthInfo{
+		Module:             bcsType.BCS_MODULE_METRICCOLLECTOR,
+		AlarmName:          "seriesLimit",
+		Kind:               alarm.WarnKind,
+		AlarmID:            fmt.Sprintf("%s-%s-series-limit", cli.clusterID, cfg.CfgKey),
+		ConvergenceSeconds: &convergenceSeconds,
+		IP:                 cli.cfg.LocalIP,
+		ClusterID:          cli.clusterID,
+		Namespace:          cfg.Meta.NameSpace,
+		Message:            message,
+		Version:            version.GetVersion(),
+		ReportTime:         time.Now().Local().Format("2006-01-02 15:04:05.000"),
+	}
+	if err := alarm.SendHealthInfo(&health); err != nil {
+		blog.Warnf("send series limit alarm of %s failed: %v", cfg.CfgKey, err)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"testing"
+
+	"bk-bcs/bcs-services/bcs-metricservice/pkg/types"
+)
+
+func TestSeriesFilterOldVersion(t *testing.T) {
+	f, err := newSeriesFilter(types.MetricFilterCfg{
+		AllowList: []string{"http_.*", "go_goroutines"},
+		DenyList:  []string{"http_debug_.*"},
+		RelabelConfigs: []*types.RelabelConfig{
+			{SourceLabels: []string{"__name__"}, Regex: "http_(.*)", TargetLabel: "__name__", Replacement: "app_http_$1"},
+		},
+		SeriesLimit: 2,
+	})
+	if err != nil {
+		t.Fatalf("new series filter failed: %v", err)
+	}
+	data := &CollectorWrapper{Collector: CollectorMetrics{Metrics: []*PromMetric{
+		{Name: "http_requests_total", Labels: map[string]string{"code": "200"}},
+		{Name: "http_debug_total"},
+		{Name: "process_cpu_seconds_total"},
+		{Name: "go_goroutines"},
+		{Name: "http_requests_total", Labels: map[string]string{"code": "500"}},
+	}}}
+
+	if exceeded := f.filterOldVersion(data); exceeded != 1 {
+		t.Errorf("expect 1 exceeded series, got %d", exceeded)
+	}
+	metrics := data.Collector.Metrics
+	if len(metrics) != 2 {
+		t.Fatalf("expect 2 metrics, got %d", len(metrics))
+	}
+	if metrics[0].Name != "app_http_requests_total" || metrics[0].Labels["code"] != "200" {
+		t.Errorf("unexpected relabeled metric: %+v", metrics[0])
+	}
+	if _, ok := metrics[0].Labels["__name__"]; ok {
+		t.Errorf("__name__ should not be kept in labels: %v", metrics[0].Labels)
+	}
+	if metrics[1].Name != "go_goroutines" {
+		t.Errorf("expect go_goroutines, got %s", metrics[1].Name)
+	}
+}
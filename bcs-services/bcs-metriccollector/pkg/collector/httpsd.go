@@ -0,0 +1,147 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-services/bcs-metricservice/pkg/types"
+)
+
+// HTTPSDPath path of prometheus http_sd api
+const HTTPSDPath = "/api/v1/http_sd"
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// SDTargetGroup target group of prometheus http_sd
+type SDTargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// runHTTPSD exposes prometheus collector configs as http_sd targets
+func (cli *collector) runHTTPSD(ip string, port uint) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HTTPSDPath, cli.httpSDHandler)
+	addr := net.JoinHostPort(ip, strconv.Itoa(int(port)))
+	blog.Infof("start http_sd server on %s%s", addr, HTTPSDPath)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		blog.Errorf("http_sd server on %s exit, err: %v", addr, err)
+	}
+}
+
+func (cli *collector) httpSDHandler(w http.ResponseWriter, r *http.Request) {
+	cli.lock.RLock()
+	groups := buildSDTargetGroups(cli.clusterID, cli.cfgs.cfgs)
+	cli.lock.RUnlock()
+
+	data, err := json.Marshal(groups)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// buildSDTargetGroups converts prometheus collector configs to target groups,
+// configs of other metric type are skipped because prometheus can not scrape them.
+func buildSDTargetGroups(clusterID string, cfgs []types.CollectorCfg) []*SDTargetGroup {
+	groups := make([]*SDTargetGroup, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.MetricType != types.MetricPrometheus {
+			continue
+		}
+		group, err := buildSDTargetGroup(clusterID, cfg)
+		if err != nil {
+			blog.Warnf("skip collector config %s for http_sd: %v", cfg.CfgKey, err)
+			continue
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Targets[0] < groups[j].Targets[0]
+	})
+	return groups
+}
+
+func buildSDTargetGroup(clusterID string, cfg types.CollectorCfg) (*SDTargetGroup, error) {
+	u, err := url.Parse(cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("parse address %s failed, %v", cfg.Address, err)
+	}
+	target := u.Host
+	if target == "" {
+		if cfg.IP == "" {
+			return nil, fmt.Errorf("no target address")
+		}
+		target = net.JoinHostPort(cfg.IP, strconv.Itoa(int(cfg.Port)))
+	}
+	scheme := u.Scheme
+	if scheme == "" {
+		scheme = cfg.Scheme
+	}
+
+	labels := map[string]string{
+		"__scheme__":           scheme,
+		"__meta_bcs_cfg_key":   cfg.CfgKey,
+		"__meta_bcs_data_id":   strconv.Itoa(cfg.DataID),
+		"__meta_bcs_namespace": cfg.Meta.NameSpace,
+		"__meta_bcs_name":      cfg.Meta.Name,
+		"__meta_bcs_ip":        cfg.IP,
+	}
+	if u.Path != "" {
+		labels["__metrics_path__"] = u.Path
+	}
+	if cfg.Frequency > 0 {
+		labels["__scrape_interval__"] = fmt.Sprintf("%ds", cfg.Frequency)
+	}
+	if cfg.Timeout > 0 {
+		labels["__scrape_timeout__"] = fmt.Sprintf("%ds", cfg.Timeout)
+	}
+	for k, v := range cfg.Parameters {
+		if k != "" {
+			labels["__param_"+k] = v
+		}
+	}
+	for k, v := range cfg.Meta.Labels {
+		labels["__meta_bcs_label_"+invalidLabelChars.ReplaceAllString(k, "_")] = v
+	}
+	// bcs labels and const labels are kept on scraped series without relabeling
+	if clusterID != TraditionalClusterID {
+		labels["bcs_cluster_id"] = clusterID
+	}
+	if cfg.Meta.NameSpace != "" {
+		labels["bcs_namespace"] = cfg.Meta.NameSpace
+	}
+	if cfg.Meta.Name != "" {
+		labels["bcs_name"] = cfg.Meta.Name
+	}
+	for k, v := range cfg.PrometheusConstLabels {
+		labels[k] = v
+	}
+
+	return &SDTargetGroup{
+		Targets: []string{target},
+		Labels:  labels,
+	}, nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package collector
+
+import (
+	"testing"
+
+	btypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-services/bcs-metricservice/pkg/types"
+)
+
+func TestBuildSDTargetGroups(t *testing.T) {
+	cfgs := []types.CollectorCfg{
+		{
+			CfgKey:     "demo_http://127.0.0.1:8080/metrics",
+			Meta:       btypes.ObjectMeta{Name: "demo-0", NameSpace: "default", Labels: map[string]string{"io.tencent.app": "demo"}},
+			DataID:     100,
+			IP:         "127.0.0.1",
+			Port:       8080,
+			Address:    "http://127.0.0.1:8080/metrics",
+			Parameters: map[string]string{"module": "all"},
+			Frequency:  60,
+			MetricType: types.MetricPrometheus,
+		},
+		{
+			CfgKey:  "text_http://127.0.0.1:8081/data",
+			Address: "http://127.0.0.1:8081/data",
+		},
+	}
+
+	groups := buildSDTargetGroups("BCS-MESOS-10001", cfgs)
+	if len(groups) != 1 {
+		t.Fatalf("expect 1 target group, got %d", len(groups))
+	}
+	if groups[0].Targets[0] != "127.0.0.1:8080" {
+		t.Errorf("expect target 127.0.0.1:8080, got %s", groups[0].Targets[0])
+	}
+	for k, v := range map[string]string{
+		"__scheme__":                      "http",
+		"__metrics_path__":                "/metrics",
+		"__param_module":                  "all",
+		"__scrape_interval__":             "60s",
+		"__meta_bcs_label_io_tencent_app": "demo",
+		"bcs_cluster_id":                  "BCS-MESOS-10001",
+		"bcs_namespace":                   "default",
+		"bcs_name":                        "demo-0",
+	} {
+		if groups[0].Labels[k] != v {
+			t.Errorf("expect label %s=%s, got %s", k, v, groups[0].Labels[k])
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
@@ -37,6 +38,24 @@ func ParsePromMetric(metric io.Reader, cLabels map[string]string) ([]byte, error
 	return json.Marshal(MetricWrapper{result})
 }
 
+// ParsePromFamilies parse prometheus text format to metric families sorted by name
+func ParsePromFamilies(metric io.Reader) ([]*dto.MetricFamily, error) {
+	var parser expfmt.TextParser
+	mf, err := parser.TextToMetricFamilies(metric)
+	if err != nil {
+		return nil, fmt.Errorf("new parser failed, err: %v", err)
+	}
+
+	result := make([]*dto.MetricFamily, 0, len(mf))
+	for _, m := range mf {
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetName() < result[j].GetName()
+	})
+	return result, nil
+}
+
 type MetricWrapper struct {
 	Data []*Family `json:"data"`
 }
@@ -0,0 +1,146 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package output
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"bk-bcs/bcs-services/bcs-metriccollector/pkg/output/prompb"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// labels attached to every remote write series
+const (
+	LabelClusterID = "bcs_cluster_id"
+	LabelNamespace = "bcs_namespace"
+	LabelName      = "bcs_name"
+	LabelIP        = "bcs_ip"
+)
+
+// ConvertFamilies converts the metric families scraped for message to remote write time series.
+// summary and histogram are expanded like prometheus does, const labels and bcs labels
+// are added to every series, samples without timestamp use now.
+func ConvertFamilies(msg *InputMessage, families []*dto.MetricFamily, now time.Time) []*prompb.TimeSeries {
+	extra := map[string]string{}
+	for k, v := range msg.ConstLabels {
+		extra[k] = v
+	}
+	for k, v := range map[string]string{
+		LabelClusterID: msg.ClusterID,
+		LabelNamespace: msg.ObjMeta.NameSpace,
+		LabelName:      msg.ObjMeta.Name,
+		LabelIP:        msg.ObjMeta.IP,
+	} {
+		if v != "" {
+			extra[k] = v
+		}
+	}
+
+	defaultTs := now.UnixNano() / int64(time.Millisecond)
+	var result []*prompb.TimeSeries
+	for _, mf := range families {
+		name := mf.GetName()
+		for _, m := range mf.Metric {
+			ts := defaultTs
+			if m.TimestampMs != nil {
+				ts = m.GetTimestampMs()
+			}
+			add := func(suffix string, value float64, lName, lValue string) {
+				result = append(result, newSeries(name+suffix, m.Label, extra, lName, lValue, value, ts))
+			}
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				add("", m.GetCounter().GetValue(), "", "")
+			case dto.MetricType_GAUGE:
+				add("", m.GetGauge().GetValue(), "", "")
+			case dto.MetricType_SUMMARY:
+				for _, q := range m.GetSummary().GetQuantile() {
+					add("", q.GetValue(), "quantile", formatFloat(q.GetQuantile()))
+				}
+				add("_sum", m.GetSummary().GetSampleSum(), "", "")
+				add("_count", float64(m.GetSummary().GetSampleCount()), "", "")
+			case dto.MetricType_HISTOGRAM:
+				hasInf := false
+				for _, b := range m.GetHistogram().GetBucket() {
+					if math.IsInf(b.GetUpperBound(), +1) {
+						hasInf = true
+					}
+					add("_bucket", float64(b.GetCumulativeCount()), "le", formatFloat(b.GetUpperBound()))
+				}
+				if !hasInf {
+					add("_bucket", float64(m.GetHistogram().GetSampleCount()), "le", "+Inf")
+				}
+				add("_sum", m.GetHistogram().GetSampleSum(), "", "")
+				add("_count", float64(m.GetHistogram().GetSampleCount()), "", "")
+			default:
+				add("", m.GetUntyped().GetValue(), "", "")
+			}
+		}
+	}
+	return result
+}
+
+func newSeries(name string, pairs []*dto.LabelPair, extra map[string]string, lName, lValue string, value float64, ts int64) *prompb.TimeSeries {
+	labels := make(map[string]string, len(pairs)+len(extra)+2)
+	for _, lp := range pairs {
+		labels[lp.GetName()] = lp.GetValue()
+	}
+	for k, v := range extra {
+		labels[k] = v
+	}
+	if lName != "" {
+		labels[lName] = lValue
+	}
+	labels["__name__"] = name
+
+	return &prompb.TimeSeries{
+		Labels:  MapToLabels(labels),
+		Samples: []prompb.Sample{{Value: value, Timestamp: ts}},
+	}
+}
+
+// LabelsToMap converts series labels to map
+func LabelsToMap(labels []prompb.Label) map[string]string {
+	m := make(map[string]string, len(labels))
+	for _, l := range labels {
+		m[l.Name] = l.Value
+	}
+	return m
+}
+
+// MapToLabels converts map to series labels sorted by name
+func MapToLabels(m map[string]string) []prompb.Label {
+	labels := make([]prompb.Label, 0, len(m))
+	for k, v := range m {
+		labels = append(labels, prompb.Label{Name: k, Value: v})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+	return labels
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, +1) {
+		return "+Inf"
+	}
+	if math.IsInf(f, -1) {
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -18,12 +18,43 @@ import (
 	"bk-bcs/bcs-common/common/http/httpclient"
 	"bk-bcs/bcs-services/bcs-metriccollector/app/config"
 	"context"
+	"fmt"
 	"time"
 )
 
-// New create a new Output object
+// New create a new Output object, data is sent to bcs-exporter and prometheus remote write endpoint as configured
 func New(ctx context.Context, cfg *config.Config) (Output, error) {
 
+	var outputs multiOutput
+	if !cfg.DisableExporter {
+		out, err := newExporterOutput(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, out)
+	}
+
+	if cfg.RemoteWriteURL != "" {
+		out, err := newRemoteWriter(ctx, cfg)
+		if err != nil {
+			blog.Error("failed to create remote writer, error info is %s", err.Error())
+			return nil, err
+		}
+		outputs = append(outputs, out)
+	}
+
+	switch len(outputs) {
+	case 0:
+		return nil, fmt.Errorf("no output is enabled, set remote_write_url if exporter is disabled")
+	case 1:
+		return outputs[0], nil
+	}
+	return outputs, nil
+}
+
+// newExporterOutput create output which sends data to bcs-exporter
+func newExporterOutput(ctx context.Context, cfg *config.Config) (Output, error) {
+
 	httpcli := httpclient.NewHttpClient()
 	httpcli.SetTlsNoVerity()
 	httpcli.SetTimeOut(time.Duration(60) * time.Second)
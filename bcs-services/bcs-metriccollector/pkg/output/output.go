@@ -90,3 +90,16 @@ func (cli *output) Input(msg *InputMessage) error {
 	}
 	return nil
 }
+
+// multiOutput sends message to all outputs
+type multiOutput []Output
+
+func (m multiOutput) Input(msg *InputMessage) error {
+	var lastErr error
+	for _, out := range m {
+		if err := out.Input(msg); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,211 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+//Package prompb implements the subset of prometheus remote write protocol
+//which is used by bcs-metriccollector, wire compatible with prometheus prompb.WriteRequest
+package prompb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+//WriteRequest remote write request body
+type WriteRequest struct {
+	Timeseries []*TimeSeries
+}
+
+//TimeSeries series of samples with same labels
+type TimeSeries struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+//Label name/value pair, labels in TimeSeries must be sorted by name
+type Label struct {
+	Name  string
+	Value string
+}
+
+//Sample value with timestamp in milliseconds
+type Sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+//Marshal encode WriteRequest to protobuf
+func (m *WriteRequest) Marshal() ([]byte, error) {
+	buf := proto.NewBuffer(nil)
+	for _, ts := range m.Timeseries {
+		data, err := ts.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		buf.EncodeVarint(1<<3 | wireBytes)
+		buf.EncodeRawBytes(data)
+	}
+	return buf.Bytes(), nil
+}
+
+//Marshal encode TimeSeries to protobuf
+func (m *TimeSeries) Marshal() ([]byte, error) {
+	buf := proto.NewBuffer(nil)
+	for _, l := range m.Labels {
+		buf.EncodeVarint(1<<3 | wireBytes)
+		buf.EncodeRawBytes(l.marshal())
+	}
+	for _, s := range m.Samples {
+		buf.EncodeVarint(2<<3 | wireBytes)
+		buf.EncodeRawBytes(s.marshal())
+	}
+	return buf.Bytes(), nil
+}
+
+func (m *Label) marshal() []byte {
+	buf := proto.NewBuffer(nil)
+	if m.Name != "" {
+		buf.EncodeVarint(1<<3 | wireBytes)
+		buf.EncodeStringBytes(m.Name)
+	}
+	if m.Value != "" {
+		buf.EncodeVarint(2<<3 | wireBytes)
+		buf.EncodeStringBytes(m.Value)
+	}
+	return buf.Bytes()
+}
+
+func (m *Sample) marshal() []byte {
+	buf := proto.NewBuffer(nil)
+	if m.Value != 0 || math.Signbit(m.Value) {
+		buf.EncodeVarint(1<<3 | wireFixed64)
+		buf.EncodeFixed64(math.Float64bits(m.Value))
+	}
+	if m.Timestamp != 0 {
+		buf.EncodeVarint(2<<3 | wireVarint)
+		buf.EncodeVarint(uint64(m.Timestamp))
+	}
+	return buf.Bytes()
+}
+
+//Unmarshal decode WriteRequest from protobuf, unknown fields are skipped
+func (m *WriteRequest) Unmarshal(data []byte) error {
+	m.Timeseries = nil
+	return decodeFields(data, func(field, wire int, value uint64, b []byte) error {
+		if field == 1 && wire == wireBytes {
+			ts := &TimeSeries{}
+			if err := ts.Unmarshal(b); err != nil {
+				return err
+			}
+			m.Timeseries = append(m.Timeseries, ts)
+		}
+		return nil
+	})
+}
+
+//Unmarshal decode TimeSeries from protobuf, unknown fields are skipped
+func (m *TimeSeries) Unmarshal(data []byte) error {
+	m.Labels = nil
+	m.Samples = nil
+	return decodeFields(data, func(field, wire int, value uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireBytes:
+			var l Label
+			if err := l.unmarshal(b); err != nil {
+				return err
+			}
+			m.Labels = append(m.Labels, l)
+		case field == 2 && wire == wireBytes:
+			var s Sample
+			if err := s.unmarshal(b); err != nil {
+				return err
+			}
+			m.Samples = append(m.Samples, s)
+		}
+		return nil
+	})
+}
+
+func (m *Label) unmarshal(data []byte) error {
+	return decodeFields(data, func(field, wire int, value uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireBytes:
+			m.Name = string(b)
+		case field == 2 && wire == wireBytes:
+			m.Value = string(b)
+		}
+		return nil
+	})
+}
+
+func (m *Sample) unmarshal(data []byte) error {
+	return decodeFields(data, func(field, wire int, value uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireFixed64:
+			m.Value = math.Float64frombits(value)
+		case field == 2 && wire == wireVarint:
+			m.Timestamp = int64(value)
+		}
+		return nil
+	})
+}
+
+//decodeFields call fn with every field of protobuf message, value is set for varint and fixed64
+//fields, b is set for length delimited fields
+func decodeFields(data []byte, fn func(field, wire int, value uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("invalid field key")
+		}
+		data = data[n:]
+		field, wire := int(key>>3), int(key&7)
+
+		var value uint64
+		var b []byte
+		switch wire {
+		case wireVarint:
+			if value, n = binary.Uvarint(data); n <= 0 {
+				return fmt.Errorf("invalid varint of field %d", field)
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("invalid fixed64 of field %d", field)
+			}
+			value = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return fmt.Errorf("invalid length of field %d", field)
+			}
+			b = data[n : n+int(length)]
+			data = data[n+int(length):]
+		default:
+			return fmt.Errorf("unsupported wire type %d of field %d", wire, field)
+		}
+		if err := fn(field, wire, value, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package output
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"bk-bcs/bcs-common/common/blog"
+	"bk-bcs/bcs-common/common/metric"
+	"bk-bcs/bcs-services/bcs-metriccollector/app/config"
+	"bk-bcs/bcs-services/bcs-metriccollector/pkg/output/prompb"
+
+	"github.com/golang/snappy"
+)
+
+const (
+	remoteWriteMinBackoff = time.Second
+	remoteWriteMaxBackoff = 30 * time.Second
+)
+
+// remoteWriteDroppedSamples number of samples dropped by remote writer, as queue is full without wal,
+// failed to write wal, rejected by remote or retries exhausted without wal
+var remoteWriteDroppedSamples uint64
+
+// Metrics returns the metrics of remote write
+func Metrics() []*metric.MetricContructor {
+	return []*metric.MetricContructor{{
+		GetMeta: func() *metric.MetricMeta {
+			return &metric.MetricMeta{
+				Name: "remote_write_dropped_samples_total",
+				Help: "number of samples dropped by prometheus remote write",
+			}
+		},
+		GetResult: func() (*metric.MetricResult, error) {
+			value, err := metric.FormFloatOrString(float64(atomic.LoadUint64(&remoteWriteDroppedSamples)))
+			if err != nil {
+				return nil, err
+			}
+			return &metric.MetricResult{Value: value}, nil
+		},
+	}}
+}
+
+func dropSamples(count int) {
+	atomic.AddUint64(&remoteWriteDroppedSamples, uint64(count))
+}
+
+// recoverableError is a failure that can be retried, network error, 5xx or 429
+type recoverableError struct {
+	error
+}
+
+// remoteWriter sends prometheus samples to remote write endpoint.
+// samples are sharded by labels hash so the samples of one series keep in order,
+// every shard batches samples and sends them with retry.
+// with wal, input is appended to wal and dispatched to shards by reading wal in order,
+// otherwise it is dispatched directly and dropped if the queue of shard is full.
+type remoteWriter struct {
+	cfg    *config.Config
+	client *http.Client
+	shards []*shard
+	wal    *wal
+}
+
+type shard struct {
+	writer *remoteWriter
+	queue  chan queuedSeries
+}
+
+// queuedSeries is series waiting in shard, segment is the wal segment it is read from, 0 without wal
+type queuedSeries struct {
+	series  *prompb.TimeSeries
+	segment uint64
+}
+
+func newRemoteWriter(ctx context.Context, cfg *config.Config) (*remoteWriter, error) {
+	shardNum := cfg.RemoteWriteShards
+	if shardNum <= 0 {
+		shardNum = 1
+	}
+	if cfg.RemoteWriteBatchSize <= 0 {
+		cfg.RemoteWriteBatchSize = 500
+	}
+	if cfg.RemoteWriteFlush <= 0 {
+		cfg.RemoteWriteFlush = 5
+	}
+	timeout := cfg.RemoteWriteTimeout
+	if timeout <= 0 {
+		timeout = 30
+	}
+
+	rw := &remoteWriter{
+		cfg:    cfg,
+		client: &http.Client{Timeout: time.Duration(timeout) * time.Second},
+	}
+	if cfg.RemoteWriteWALDir != "" {
+		w, err := newWAL(cfg.RemoteWriteWALDir)
+		if err != nil {
+			return nil, err
+		}
+		rw.wal = w
+	}
+	for i := 0; i < shardNum; i++ {
+		s := &shard{
+			writer: rw,
+			queue:  make(chan queuedSeries, cfg.RemoteWriteBatchSize*10),
+		}
+		rw.shards = append(rw.shards, s)
+		go s.run(ctx)
+	}
+	if rw.wal != nil {
+		go rw.readWAL(ctx)
+	}
+	blog.Infof("prometheus remote write to %s with %d shards, wal dir: %s", cfg.RemoteWriteURL, shardNum, cfg.RemoteWriteWALDir)
+	return rw, nil
+}
+
+// Input appends series of message to wal, or dispatches them to shards without wal
+func (rw *remoteWriter) Input(msg *InputMessage) error {
+	if len(msg.Series) == 0 {
+		return nil
+	}
+	if rw.wal == nil {
+		dropped := 0
+		for _, series := range msg.Series {
+			select {
+			case rw.shardOf(series).queue <- queuedSeries{series: series}:
+			default:
+				dropped++
+			}
+		}
+		if dropped > 0 {
+			dropSamples(dropped)
+			blog.Warnf("remote write queue is full, drop %d samples of dataid[%d] name[%s]", dropped, msg.DataID, msg.ObjMeta.Name)
+		}
+		return nil
+	}
+
+	data, err := (&prompb.WriteRequest{Timeseries: msg.Series}).Marshal()
+	if err == nil {
+		err = rw.wal.append(snappy.Encode(nil, data))
+	}
+	if err != nil {
+		dropSamples(len(msg.Series))
+		return fmt.Errorf("drop %d samples for remote write, %s", len(msg.Series), err.Error())
+	}
+	return nil
+}
+
+// readWAL dispatches series in wal to shards in order, it waits for shards rather than dropping series,
+// the series left by last running are dispatched before the new ones
+func (rw *remoteWriter) readWAL(ctx context.Context) {
+	for {
+		segment, body, err := rw.wal.next(ctx)
+		if err != nil {
+			return
+		}
+		req := &prompb.WriteRequest{}
+		data, err := snappy.Decode(nil, body)
+		if err == nil {
+			err = req.Unmarshal(data)
+		}
+		if err != nil {
+			blog.Errorf("decode wal record of segment %d failed, skip it: %s", segment, err.Error())
+			continue
+		}
+
+		rw.wal.dispatch(segment, len(req.Timeseries))
+		for _, series := range req.Timeseries {
+			select {
+			case rw.shardOf(series).queue <- queuedSeries{series: series, segment: segment}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (rw *remoteWriter) shardOf(series *prompb.TimeSeries) *shard {
+	return rw.shards[labelsHash(series.Labels)%uint64(len(rw.shards))]
+}
+
+func labelsHash(labels []prompb.Label) uint64 {
+	h := fnv.New64a()
+	for _, l := range labels {
+		io.WriteString(h, l.Name)
+		h.Write([]byte{0xff})
+		io.WriteString(h, l.Value)
+		h.Write([]byte{0xff})
+	}
+	return h.Sum64()
+}
+
+func (s *shard) run(ctx context.Context) {
+	tick := time.NewTicker(time.Duration(s.writer.cfg.RemoteWriteFlush) * time.Second)
+	defer tick.Stop()
+	batch := make([]queuedSeries, 0, s.writer.cfg.RemoteWriteBatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			blog.Infof("remote write shard exit, %d samples are not sent", len(batch))
+			return
+		case series := <-s.queue:
+			batch = append(batch, series)
+			if len(batch) >= s.writer.cfg.RemoteWriteBatchSize {
+				s.flush(ctx, batch)
+				batch = make([]queuedSeries, 0, s.writer.cfg.RemoteWriteBatchSize)
+			}
+		case <-tick.C:
+			if len(batch) > 0 {
+				s.flush(ctx, batch)
+				batch = make([]queuedSeries, 0, s.writer.cfg.RemoteWriteBatchSize)
+			}
+		}
+	}
+}
+
+// flush writes batch and marks the samples done in wal segments they are read from
+func (s *shard) flush(ctx context.Context, batch []queuedSeries) {
+	series := make([]*prompb.TimeSeries, 0, len(batch))
+	for _, q := range batch {
+		series = append(series, q.series)
+	}
+	if !s.writer.write(ctx, series) || s.writer.wal == nil {
+		return
+	}
+	done := make(map[uint64]int)
+	for _, q := range batch {
+		done[q.segment]++
+	}
+	for segment, samples := range done {
+		s.writer.wal.done(segment, samples)
+	}
+}
+
+// write encodes batch and sends it, returns false if it is not sent as writer exits,
+// the samples are kept in wal for next running
+func (rw *remoteWriter) write(ctx context.Context, batch []*prompb.TimeSeries) bool {
+	req := &prompb.WriteRequest{Timeseries: batch}
+	data, err := req.Marshal()
+	if err != nil {
+		blog.Errorf("marshal remote write request failed, drop %d samples, %s", len(batch), err.Error())
+		dropSamples(len(batch))
+		return true
+	}
+	err = rw.sendWithRetry(ctx, snappy.Encode(nil, data))
+	if err == nil {
+		return true
+	}
+	if _, ok := err.(recoverableError); ok && ctx.Err() != nil {
+		return false
+	}
+	blog.Errorf("remote write %d samples failed, drop them: %s", len(batch), err.Error())
+	dropSamples(len(batch))
+	return true
+}
+
+// sendWithRetry retries recoverable failure with exponential backoff. with wal, it retries until
+// succeeded, the samples behind are kept in wal meanwhile, so that samples are sent in order
+func (rw *remoteWriter) sendWithRetry(ctx context.Context, body []byte) error {
+	backoff := remoteWriteMinBackoff
+	var err error
+	for try := 0; ; try++ {
+		err = rw.send(body)
+		if _, ok := err.(recoverableError); !ok || (rw.wal == nil && try >= rw.cfg.RemoteWriteRetries) {
+			return err
+		}
+		blog.Warnf("remote write failed, retry after %s: %s", backoff.String(), err.Error())
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > remoteWriteMaxBackoff {
+			backoff = remoteWriteMaxBackoff
+		}
+	}
+}
+
+func (rw *remoteWriter) send(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, rw.cfg.RemoteWriteURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "bcs-metriccollector")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	rsp, err := rw.client.Do(req)
+	if err != nil {
+		return recoverableError{err}
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode/100 == 2 {
+		return nil
+	}
+	msg, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, 512))
+	err = fmt.Errorf("server returned HTTP status %s: %s", rsp.Status, string(msg))
+	if rsp.StatusCode/100 == 5 || rsp.StatusCode == http.StatusTooManyRequests {
+		return recoverableError{err}
+	}
+	return err
+}
@@ -0,0 +1,273 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package output
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	btypes "bk-bcs/bcs-common/common/types"
+	"bk-bcs/bcs-services/bcs-metriccollector/app/config"
+	"bk-bcs/bcs-services/bcs-metriccollector/pkg/output/prompb"
+
+	"github.com/golang/snappy"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+const testMetrics = `# TYPE http_requests_total counter
+http_requests_total{code="200"} 10
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 0.5
+latency_seconds_count 3
+`
+
+func testFamilies(t *testing.T) []*dto.MetricFamily {
+	var parser expfmt.TextParser
+	mf, err := parser.TextToMetricFamilies(strings.NewReader(testMetrics))
+	if err != nil {
+		t.Fatalf("parse metrics failed: %v", err)
+	}
+	return []*dto.MetricFamily{mf["http_requests_total"], mf["latency_seconds"]}
+}
+
+func testMessage() *InputMessage {
+	return &InputMessage{
+		ClusterID:   "BCS-MESOS-10001",
+		ConstLabels: map[string]string{"app": "demo"},
+		ObjMeta: MessageMeta{
+			ObjectMeta: btypes.ObjectMeta{Name: "demo-0", NameSpace: "default"},
+			IP:         "127.0.0.1",
+		},
+	}
+}
+
+func seriesName(ts *prompb.TimeSeries) string {
+	for _, l := range ts.Labels {
+		if l.Name == "__name__" {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+func TestConvertFamilies(t *testing.T) {
+	now := time.Unix(100, 0)
+	series := ConvertFamilies(testMessage(), testFamilies(t), now)
+
+	var names []string
+	for _, ts := range series {
+		names = append(names, seriesName(ts))
+		for i := 1; i < len(ts.Labels); i++ {
+			if ts.Labels[i-1].Name >= ts.Labels[i].Name {
+				t.Fatalf("labels of %s not sorted: %v", seriesName(ts), ts.Labels)
+			}
+		}
+		if ts.Samples[0].Timestamp != 100000 {
+			t.Errorf("expect timestamp 100000, got %d", ts.Samples[0].Timestamp)
+		}
+	}
+	expect := "http_requests_total,latency_seconds_bucket,latency_seconds_bucket,latency_seconds_sum,latency_seconds_count"
+	if strings.Join(names, ",") != expect {
+		t.Fatalf("expect series %s, got %s", expect, strings.Join(names, ","))
+	}
+
+	labels := map[string]string{}
+	for _, l := range series[0].Labels {
+		labels[l.Name] = l.Value
+	}
+	for k, v := range map[string]string{"code": "200", "app": "demo", LabelClusterID: "BCS-MESOS-10001",
+		LabelNamespace: "default", LabelName: "demo-0", LabelIP: "127.0.0.1"} {
+		if labels[k] != v {
+			t.Errorf("expect label %s=%s, got %s", k, v, labels[k])
+		}
+	}
+	if series[0].Samples[0].Value != 10 {
+		t.Errorf("expect value 10, got %f", series[0].Samples[0].Value)
+	}
+}
+
+func TestRemoteWriterWALReplay(t *testing.T) {
+	var calls int32
+	var lock sync.Mutex
+	var received [][]int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		data, _ := snappy.Decode(nil, body)
+		req := &prompb.WriteRequest{}
+		if err := req.Unmarshal(data); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+		var timestamps []int64
+		for _, ts := range req.Timeseries {
+			timestamps = append(timestamps, ts.Samples[0].Timestamp)
+		}
+		lock.Lock()
+		received = append(received, timestamps)
+		lock.Unlock()
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// samples left in wal by last running
+	old := ConvertFamilies(testMessage(), testFamilies(t), time.Unix(100, 0))
+	w, err := newWAL(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := (&prompb.WriteRequest{Timeseries: old}).Marshal()
+	if err = w.append(snappy.Encode(nil, data)); err != nil {
+		t.Fatal(err)
+	}
+	w.file.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rw, err := newRemoteWriter(ctx, &config.Config{
+		RemoteWriteURL:       srv.URL,
+		RemoteWriteShards:    1,
+		RemoteWriteBatchSize: len(old),
+		RemoteWriteWALDir:    dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := testMessage()
+	msg.Series = ConvertFamilies(msg, testFamilies(t), time.Unix(200, 0))
+	if err = rw.Input(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first request fails and is retried, the old samples are sent before the new ones
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		lock.Lock()
+		n := len(received)
+		lock.Unlock()
+		files, _ := ioutil.ReadDir(dir)
+		if n == 2 && len(files) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expect 2 requests received and wal segments removed, got %d requests %d files", n, len(files))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if received[0][0] != 100000 || received[1][0] != 200000 {
+		t.Errorf("expect old samples replayed before new ones, got %v", received)
+	}
+}
+
+func TestWALTornRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	w, err := newWAL(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.append([]byte("first"))
+	w.append([]byte("second"))
+	// crash in the middle of writing record
+	w.file.Write([]byte{0, 0, 0, 100, 0, 0})
+	w.file.Close()
+
+	w, err = newWAL(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	for _, expect := range []string{"first", "second"} {
+		segment, body, err := w.next(ctx)
+		if err != nil || string(body) != expect {
+			t.Fatalf("expect record %s, got %s err %v", expect, body, err)
+		}
+		w.dispatch(segment, 1)
+		w.done(segment, 1)
+	}
+	// the torn record is skipped, and nothing in the new segment
+	if _, _, err = w.next(ctx); err == nil {
+		t.Fatalf("expect no more records")
+	}
+	if len(w.segments) != 1 {
+		t.Errorf("expect old segment removed after done, got segments %v", w.segments)
+	}
+}
+
+func TestInputDropWithoutWAL(t *testing.T) {
+	rw := &remoteWriter{
+		cfg:    &config.Config{},
+		shards: []*shard{{queue: make(chan queuedSeries, 1)}},
+	}
+	msg := testMessage()
+	msg.Series = ConvertFamilies(msg, testFamilies(t), time.Now())
+	before := atomic.LoadUint64(&remoteWriteDroppedSamples)
+	rw.Input(msg)
+	if dropped := atomic.LoadUint64(&remoteWriteDroppedSamples) - before; dropped != uint64(len(msg.Series)-1) {
+		t.Errorf("expect %d samples dropped, got %d", len(msg.Series)-1, dropped)
+	}
+}
+
+func TestWriteRequestUnmarshal(t *testing.T) {
+	series := ConvertFamilies(testMessage(), testFamilies(t), time.Unix(100, 0))
+	data, _ := (&prompb.WriteRequest{Timeseries: series}).Marshal()
+	req := &prompb.WriteRequest{}
+	if err := req.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(req.Timeseries, series) {
+		t.Errorf("expect %v, got %v", series, req.Timeseries)
+	}
+	if err := req.Unmarshal(data[:len(data)-1]); err == nil {
+		t.Errorf("expect error for truncated request")
+	}
+}
+
+func TestSampleMarshal(t *testing.T) {
+	ts := &prompb.TimeSeries{
+		Labels:  []prompb.Label{{Name: "__name__", Value: "up"}},
+		Samples: []prompb.Sample{{Value: 1, Timestamp: 1}},
+	}
+	data, _ := ts.Marshal()
+	// labels{1:"__name__",2:"up"} samples{1:double 1.0,2:varint 1}
+	expect := []byte{0x0a, 0x0e, 0x0a, 0x08, '_', '_', 'n', 'a', 'm', 'e', '_', '_', 0x12, 0x02, 'u', 'p',
+		0x12, 0x0b, 0x09, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0x10, 0x01}
+	if string(data) != string(expect) {
+		t.Fatalf("expect %x, got %x", expect, data)
+	}
+}
@@ -15,6 +15,8 @@ package output
 
 import (
 	btypes "bk-bcs/bcs-common/common/types"
+
+	"bk-bcs/bcs-services/bcs-metriccollector/pkg/output/prompb"
 )
 
 // Message will be sent to net
@@ -33,6 +35,11 @@ type InputMessage struct {
 	ObjMeta    MessageMeta `json:"objmeta"`
 	Prometheus interface{} `json:"prometheus,omitempty"`
 	Data       interface{} `json:"data,omitempty"`
+
+	// fields below are only used by prometheus remote write
+	ClusterID   string               `json:"-"`
+	ConstLabels map[string]string    `json:"-"`
+	Series      []*prompb.TimeSeries `json:"-"`
 }
 
 type MessageMeta struct {
@@ -0,0 +1,309 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package output
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"bk-bcs/bcs-common/common/blog"
+)
+
+const (
+	walSuffix = ".wal"
+	// walSegmentSize a new segment is created when the segment being written exceeds it
+	walSegmentSize = 16 * 1024 * 1024
+	// walMaxSegments the oldest segments are dropped when exceeded, avoid filling up disk
+	// when remote write endpoint is down for a long time
+	walMaxSegments = 64
+	// walRecordHeaderSize length and crc32 of record
+	walRecordHeaderSize = 8
+)
+
+var walCastagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// wal appends every input of remote writer to segment files before it is queued, records are read in order
+// by one reader and dispatched to shards. segment is removed after it is read and all samples in it are sent
+// or rejected by remote, so samples queued in shards or batched are not lost on crash. segments left by last
+// running are read before the segment created for this running, so they are replayed before new samples.
+type wal struct {
+	dir  string
+	lock sync.Mutex
+	// ids of segments on disk from oldest, the last one is being written
+	segments []uint64
+	file     *os.File
+	size     int64
+	// notify is signaled when records are appended
+	notify chan struct{}
+
+	// reading position of reader
+	readSegment uint64
+	readFile    *os.File
+	readOffset  int64
+	// samples of segment which are dispatched to shards but not sent or dropped yet
+	outstanding map[uint64]int
+}
+
+func newWAL(dir string) (*wal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create wal dir %s failed, %s", dir, err.Error())
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read wal dir %s failed, %s", dir, err.Error())
+	}
+	w := &wal{
+		dir:         dir,
+		notify:      make(chan struct{}, 1),
+		outstanding: make(map[uint64]int),
+	}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), walSuffix) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(f.Name(), walSuffix), 10, 64)
+		if err != nil {
+			blog.Warnf("skip unknown file %s in wal dir", f.Name())
+			continue
+		}
+		w.segments = append(w.segments, id)
+	}
+	sort.Slice(w.segments, func(i, j int) bool { return w.segments[i] < w.segments[j] })
+	if len(w.segments) > 0 {
+		blog.Infof("%d wal segments left in %s will be replayed", len(w.segments), dir)
+	}
+	if err := w.createSegment(); err != nil {
+		return nil, err
+	}
+	w.readSegment = w.segments[0]
+	return w, nil
+}
+
+func (w *wal) segmentPath(id uint64) string {
+	return filepath.Join(w.dir, fmt.Sprintf("%020d%s", id, walSuffix))
+}
+
+// createSegment closes the segment being written and creates the next one, caller must hold lock
+func (w *wal) createSegment() error {
+	var id uint64 = 1
+	if len(w.segments) > 0 {
+		id = w.segments[len(w.segments)-1] + 1
+	}
+	file, err := os.OpenFile(w.segmentPath(id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("create wal segment failed, %s", err.Error())
+	}
+	// sync dir so that the new segment is not lost on crash
+	if dir, err := os.Open(w.dir); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	if w.file != nil {
+		w.file.Close()
+	}
+	w.file = file
+	w.size = 0
+	w.segments = append(w.segments, id)
+	w.truncate()
+	return nil
+}
+
+// append writes body as a record and syncs it to disk
+func (w *wal) append(body []byte) error {
+	record := make([]byte, walRecordHeaderSize+len(body))
+	binary.BigEndian.PutUint32(record, uint32(len(body)))
+	binary.BigEndian.PutUint32(record[4:], crc32.Checksum(body, walCastagnoli))
+	copy(record[walRecordHeaderSize:], body)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.size >= walSegmentSize {
+		if err := w.createSegment(); err != nil {
+			return err
+		}
+	}
+	n, err := w.file.Write(record)
+	if err == nil {
+		err = w.file.Sync()
+	}
+	if err != nil {
+		// the partial record is overwritten by next one
+		w.file.Seek(w.size, io.SeekStart)
+		w.file.Truncate(w.size)
+		return fmt.Errorf("write wal segment failed, %s", err.Error())
+	}
+	w.size += int64(n)
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// next reads the next record from oldest segment, it blocks until a record is appended or ctx is done
+func (w *wal) next(ctx context.Context) (uint64, []byte, error) {
+	for {
+		w.lock.Lock()
+		segment, offset := w.readSegment, w.readOffset
+		current := segment == w.segments[len(w.segments)-1]
+		limit := w.size
+		w.lock.Unlock()
+
+		var err error
+		if w.readFile == nil {
+			w.readFile, err = os.Open(w.segmentPath(segment))
+		}
+		if err == nil && !current {
+			// segments not being written are synced and never change
+			var info os.FileInfo
+			if info, err = w.readFile.Stat(); err == nil {
+				limit = info.Size()
+			}
+		}
+		if err == nil && offset+walRecordHeaderSize <= limit {
+			var body []byte
+			if body, err = w.readRecord(offset, limit); err == nil {
+				w.lock.Lock()
+				w.readOffset += walRecordHeaderSize + int64(len(body))
+				w.lock.Unlock()
+				return segment, body, nil
+			}
+		}
+
+		switch {
+		case err != nil && current:
+			// only by broken disk, skip the records appended so far
+			blog.Errorf("read wal segment %d at %d failed, skip to %d: %s", segment, offset, limit, err.Error())
+			w.lock.Lock()
+			w.readOffset = limit
+			w.lock.Unlock()
+		case err != nil:
+			// torn by crash or corrupted, the rest of segment can not be read
+			blog.Errorf("read wal segment %d at %d failed, skip the rest: %s", segment, offset, err.Error())
+			w.finishRead()
+			continue
+		case !current:
+			w.finishRead()
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, nil, ctx.Err()
+		case <-w.notify:
+		}
+	}
+}
+
+func (w *wal) readRecord(offset, limit int64) ([]byte, error) {
+	header := make([]byte, walRecordHeaderSize)
+	if _, err := w.readFile.ReadAt(header, offset); err != nil {
+		return nil, err
+	}
+	length := int64(binary.BigEndian.Uint32(header))
+	if offset+walRecordHeaderSize+length > limit {
+		return nil, fmt.Errorf("record of %d bytes exceeds segment", length)
+	}
+	body := make([]byte, length)
+	if _, err := w.readFile.ReadAt(body, offset+walRecordHeaderSize); err != nil {
+		return nil, err
+	}
+	if crc32.Checksum(body, walCastagnoli) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+	return body, nil
+}
+
+// finishRead moves reader to the segment after the one which is read up
+func (w *wal) finishRead() {
+	if w.readFile != nil {
+		w.readFile.Close()
+		w.readFile = nil
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	done := w.readSegment
+	for _, id := range w.segments {
+		if id > done {
+			w.readSegment = id
+			break
+		}
+	}
+	w.readOffset = 0
+	w.removeIfDone(done)
+}
+
+// dispatch counts samples read from segment which are dispatched to shards, it must be called
+// before next record is read
+func (w *wal) dispatch(segment uint64, samples int) {
+	w.lock.Lock()
+	w.outstanding[segment] += samples
+	w.lock.Unlock()
+}
+
+// done counts samples of segment which are sent or dropped, segment is removed if it is read up
+// and all samples in it are done
+func (w *wal) done(segment uint64, samples int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.outstanding[segment] -= samples
+	w.removeIfDone(segment)
+}
+
+// removeIfDone removes segment which is read up and all samples in it are done, caller must hold lock
+func (w *wal) removeIfDone(segment uint64) {
+	if segment >= w.readSegment || w.outstanding[segment] > 0 {
+		return
+	}
+	delete(w.outstanding, segment)
+	for i, id := range w.segments {
+		if id == segment {
+			w.segments = append(w.segments[:i], w.segments[i+1:]...)
+			if err := os.Remove(w.segmentPath(segment)); err != nil && !os.IsNotExist(err) {
+				blog.Errorf("remove wal segment %d failed, %s", segment, err.Error())
+			}
+			return
+		}
+	}
+}
+
+// truncate drops the oldest segments except the ones being read and written when exceeding
+// max segments, caller must hold lock
+func (w *wal) truncate() {
+	for len(w.segments) > walMaxSegments {
+		drop := 0
+		if w.segments[0] == w.readSegment {
+			drop = 1
+		}
+		if drop >= len(w.segments)-1 {
+			return
+		}
+		id := w.segments[drop]
+		blog.Warnf("wal segments exceed %d, drop segment %d which is not sent", walMaxSegments, id)
+		w.segments = append(w.segments[:drop], w.segments[drop+1:]...)
+		os.Remove(w.segmentPath(id))
+	}
+}
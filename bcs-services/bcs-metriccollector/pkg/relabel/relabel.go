@@ -0,0 +1,165 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package relabel
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"bk-bcs/bcs-services/bcs-metricservice/pkg/types"
+)
+
+// MetricNameLabel label name of the metric name during relabeling
+const MetricNameLabel = "__name__"
+
+var labelNameRegex = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+type rule struct {
+	sourceLabels []string
+	separator    string
+	regex        *regexp.Regexp
+	modulus      uint64
+	targetLabel  string
+	replacement  string
+	action       types.RelabelAction
+}
+
+// Relabeler applies relabel rules to label sets with prometheus semantics
+type Relabeler struct {
+	rules []*rule
+}
+
+// New compiles the relabel configs, defaults are filled like prometheus does
+func New(cfgs []*types.RelabelConfig) (*Relabeler, error) {
+	r := &Relabeler{}
+	for i, cfg := range cfgs {
+		if cfg == nil {
+			continue
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("relabel config %d: %v", i, err)
+		}
+		ru := &rule{
+			sourceLabels: cfg.SourceLabels,
+			separator:    cfg.Separator,
+			modulus:      cfg.Modulus,
+			targetLabel:  cfg.TargetLabel,
+			replacement:  cfg.Replacement,
+			action:       cfg.Action,
+		}
+		regex := cfg.Regex
+		if regex == "" {
+			regex = types.DefaultRelabelRegex
+		}
+		ru.regex = regexp.MustCompile("^(?:" + regex + ")$")
+		if ru.separator == "" {
+			ru.separator = types.DefaultRelabelSeparator
+		}
+		if ru.replacement == "" {
+			ru.replacement = types.DefaultRelabelReplacement
+		}
+		if ru.action == "" {
+			ru.action = types.RelabelReplace
+		}
+		r.rules = append(r.rules, ru)
+	}
+	return r, nil
+}
+
+// Empty return true if there is no rule
+func (r *Relabeler) Empty() bool {
+	return r == nil || len(r.rules) == 0
+}
+
+// Process applies the rules to a copy of labels, nil is returned if the series is dropped
+func (r *Relabeler) Process(labels map[string]string) map[string]string {
+	lset := make(map[string]string, len(labels))
+	for k, v := range labels {
+		lset[k] = v
+	}
+	if r == nil {
+		return lset
+	}
+	for _, ru := range r.rules {
+		if lset = ru.apply(lset); lset == nil {
+			return nil
+		}
+	}
+	return lset
+}
+
+func (ru *rule) apply(lset map[string]string) map[string]string {
+	values := make([]string, 0, len(ru.sourceLabels))
+	for _, name := range ru.sourceLabels {
+		values = append(values, lset[name])
+	}
+	val := strings.Join(values, ru.separator)
+
+	switch ru.action {
+	case types.RelabelDrop:
+		if ru.regex.MatchString(val) {
+			return nil
+		}
+	case types.RelabelKeep:
+		if !ru.regex.MatchString(val) {
+			return nil
+		}
+	case types.RelabelReplace:
+		indexes := ru.regex.FindStringSubmatchIndex(val)
+		// no match, nothing to do
+		if indexes == nil {
+			break
+		}
+		target := string(ru.regex.ExpandString([]byte{}, ru.targetLabel, val, indexes))
+		if !labelNameRegex.MatchString(target) {
+			break
+		}
+		res := string(ru.regex.ExpandString([]byte{}, ru.replacement, val, indexes))
+		if len(res) == 0 {
+			delete(lset, target)
+			break
+		}
+		lset[target] = res
+	case types.RelabelHashMod:
+		sum := md5.Sum([]byte(val))
+		mod := binary.BigEndian.Uint64(sum[8:]) % ru.modulus
+		lset[ru.targetLabel] = fmt.Sprintf("%d", mod)
+	case types.RelabelLabelMap:
+		mapped := make(map[string]string, len(lset))
+		for name, value := range lset {
+			if ru.regex.MatchString(name) {
+				mapped[ru.regex.ReplaceAllString(name, ru.replacement)] = value
+			}
+		}
+		for name, value := range mapped {
+			lset[name] = value
+		}
+	case types.RelabelLabelDrop:
+		for name := range lset {
+			if ru.regex.MatchString(name) {
+				delete(lset, name)
+			}
+		}
+	case types.RelabelLabelKeep:
+		for name := range lset {
+			if !ru.regex.MatchString(name) {
+				delete(lset, name)
+			}
+		}
+	}
+	return lset
+}
@@ -0,0 +1,121 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package relabel
+
+import (
+	"reflect"
+	"testing"
+
+	"bk-bcs/bcs-services/bcs-metricservice/pkg/types"
+)
+
+func TestProcess(t *testing.T) {
+	input := map[string]string{
+		"__name__": "http_requests_total",
+		"code":     "200",
+		"method":   "GET",
+		"pod_ip":   "127.0.0.1",
+	}
+	tests := []struct {
+		name   string
+		cfgs   []*types.RelabelConfig
+		expect map[string]string
+	}{
+		{
+			name: "replace with defaults",
+			cfgs: []*types.RelabelConfig{
+				{SourceLabels: []string{"method", "code"}, TargetLabel: "req"},
+			},
+			expect: map[string]string{"__name__": "http_requests_total", "code": "200", "method": "GET", "pod_ip": "127.0.0.1", "req": "GET;200"},
+		},
+		{
+			name: "replace without match",
+			cfgs: []*types.RelabelConfig{
+				{SourceLabels: []string{"code"}, Regex: "5..", TargetLabel: "error", Replacement: "true"},
+			},
+			expect: input,
+		},
+		{
+			name: "replace empty deletes target",
+			cfgs: []*types.RelabelConfig{
+				{SourceLabels: []string{"not_exist"}, TargetLabel: "method"},
+			},
+			expect: map[string]string{"__name__": "http_requests_total", "code": "200", "pod_ip": "127.0.0.1"},
+		},
+		{
+			name: "keep",
+			cfgs: []*types.RelabelConfig{
+				{SourceLabels: []string{"code"}, Regex: "5..", Action: types.RelabelKeep},
+			},
+			expect: nil,
+		},
+		{
+			name: "drop by name",
+			cfgs: []*types.RelabelConfig{
+				{SourceLabels: []string{"__name__"}, Regex: "http_.*", Action: types.RelabelDrop},
+			},
+			expect: nil,
+		},
+		{
+			name: "hashmod",
+			cfgs: []*types.RelabelConfig{
+				{SourceLabels: []string{"pod_ip"}, Modulus: 1, TargetLabel: "shard", Action: types.RelabelHashMod},
+				{SourceLabels: []string{"shard"}, Regex: "0", Action: types.RelabelKeep},
+			},
+			expect: map[string]string{"__name__": "http_requests_total", "code": "200", "method": "GET", "pod_ip": "127.0.0.1", "shard": "0"},
+		},
+		{
+			name: "labelmap and labeldrop",
+			cfgs: []*types.RelabelConfig{
+				{Regex: "pod_(.+)", Replacement: "k8s_$1", Action: types.RelabelLabelMap},
+				{Regex: "pod_.+", Action: types.RelabelLabelDrop},
+			},
+			expect: map[string]string{"__name__": "http_requests_total", "code": "200", "method": "GET", "k8s_ip": "127.0.0.1"},
+		},
+		{
+			name: "labelkeep",
+			cfgs: []*types.RelabelConfig{
+				{Regex: "__name__|code", Action: types.RelabelLabelKeep},
+			},
+			expect: map[string]string{"__name__": "http_requests_total", "code": "200"},
+		},
+	}
+	for _, test := range tests {
+		r, err := New(test.cfgs)
+		if err != nil {
+			t.Fatalf("%s: new relabeler failed: %v", test.name, err)
+		}
+		result := r.Process(input)
+		if !reflect.DeepEqual(result, test.expect) {
+			t.Errorf("%s: expect %v, got %v", test.name, test.expect, result)
+		}
+	}
+	if len(input) != 4 {
+		t.Errorf("input labels are modified: %v", input)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	cfgs := [][]*types.RelabelConfig{
+		{{Regex: "(", TargetLabel: "a"}},
+		{{SourceLabels: []string{"a"}}},
+		{{SourceLabels: []string{"a"}, TargetLabel: "b", Action: types.RelabelHashMod}},
+		{{Action: "unknown"}},
+	}
+	for i, cfg := range cfgs {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("case %d: expect error, got nil", i)
+		}
+	}
+}
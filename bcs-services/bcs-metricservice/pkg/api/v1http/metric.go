@@ -408,6 +408,12 @@ func isValidMetric(metric *types.Metric) error {
 	if _, err := metric.TLSConfig.GetTLSConfig(); err != nil {
 		return err
 	}
+	if metric.MetricType != types.MetricPrometheus && !metric.FilterConfig.IsEmpty() {
+		return fmt.Errorf("filterConfig only works with metricType %s", types.MetricPrometheus)
+	}
+	if err := metric.FilterConfig.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -111,6 +111,7 @@ func (mm *MetricMeta) SetCollectorSettings(ipMeta map[string]btypes.ObjectMeta)
 			TLSConfig:             tlsConfig,
 			MetricType:            mm.metric.MetricType,
 			PrometheusConstLabels: mm.metric.PrometheusConstLabels,
+			FilterConfig:          mm.metric.FilterConfig,
 		})
 	}
 
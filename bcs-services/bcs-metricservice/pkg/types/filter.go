@@ -0,0 +1,127 @@
+/*
+ * Tencent is pleased to support the open source community by making Blueking Container Service available.
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ * http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing, software distributed under
+ * the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package types
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RelabelAction action of relabel, same as prometheus
+type RelabelAction string
+
+const (
+	// RelabelReplace replace target label with replacement if regex matches the concatenated source labels
+	RelabelReplace RelabelAction = "replace"
+	// RelabelKeep drop series if regex does not match the concatenated source labels
+	RelabelKeep RelabelAction = "keep"
+	// RelabelDrop drop series if regex matches the concatenated source labels
+	RelabelDrop RelabelAction = "drop"
+	// RelabelHashMod set target label to modulus of hash of the concatenated source labels
+	RelabelHashMod RelabelAction = "hashmod"
+	// RelabelLabelMap copy labels whose name matches regex to the name given by replacement
+	RelabelLabelMap RelabelAction = "labelmap"
+	// RelabelLabelDrop drop labels whose name matches regex
+	RelabelLabelDrop RelabelAction = "labeldrop"
+	// RelabelLabelKeep drop labels whose name does not match regex
+	RelabelLabelKeep RelabelAction = "labelkeep"
+)
+
+// relabel defaults, same as prometheus
+const (
+	DefaultRelabelSeparator   = ";"
+	DefaultRelabelRegex       = "(.*)"
+	DefaultRelabelReplacement = "$1"
+)
+
+// RelabelConfig relabel rule applied to every scraped series, the metric name is label __name__
+type RelabelConfig struct {
+	SourceLabels []string      `json:"sourceLabels,omitempty"`
+	Separator    string        `json:"separator,omitempty"`
+	Regex        string        `json:"regex,omitempty"`
+	Modulus      uint64        `json:"modulus,omitempty"`
+	TargetLabel  string        `json:"targetLabel,omitempty"`
+	Replacement  string        `json:"replacement,omitempty"`
+	Action       RelabelAction `json:"action,omitempty"`
+}
+
+// Validate check relabel config, defaults are not filled
+func (rc *RelabelConfig) Validate() error {
+	regex := rc.Regex
+	if regex == "" {
+		regex = DefaultRelabelRegex
+	}
+	if _, err := regexp.Compile("^(?:" + regex + ")$"); err != nil {
+		return fmt.Errorf("invalid regex %s: %v", rc.Regex, err)
+	}
+	switch rc.Action {
+	case "", RelabelReplace:
+		if rc.TargetLabel == "" {
+			return fmt.Errorf("targetLabel is required by action replace")
+		}
+	case RelabelHashMod:
+		if rc.TargetLabel == "" {
+			return fmt.Errorf("targetLabel is required by action hashmod")
+		}
+		if rc.Modulus == 0 {
+			return fmt.Errorf("modulus is required by action hashmod")
+		}
+	case RelabelKeep, RelabelDrop:
+		if len(rc.SourceLabels) == 0 {
+			return fmt.Errorf("sourceLabels is required by action %s", rc.Action)
+		}
+	case RelabelLabelMap, RelabelLabelDrop, RelabelLabelKeep:
+	default:
+		return fmt.Errorf("unknown relabel action %s", rc.Action)
+	}
+	return nil
+}
+
+// MetricFilterCfg relabel, filter and limit settings for scraped prometheus metrics
+type MetricFilterCfg struct {
+	// relabel rules, applied after allow and deny list
+	RelabelConfigs []*RelabelConfig `json:"relabelConfigs,omitempty"`
+	// regex of metric names to keep, all metrics are kept if empty
+	AllowList []string `json:"allowList,omitempty"`
+	// regex of metric names to drop
+	DenyList []string `json:"denyList,omitempty"`
+	// max series of one scrape, excess series are dropped. no limit if 0
+	SeriesLimit int `json:"seriesLimit,omitempty"`
+}
+
+// IsEmpty return true if nothing is configured
+func (fc MetricFilterCfg) IsEmpty() bool {
+	return len(fc.RelabelConfigs) == 0 && len(fc.AllowList) == 0 && len(fc.DenyList) == 0 && fc.SeriesLimit == 0
+}
+
+// Validate check filter config
+func (fc MetricFilterCfg) Validate() error {
+	for i, rc := range fc.RelabelConfigs {
+		if rc == nil {
+			return fmt.Errorf("relabel config %d is empty", i)
+		}
+		if err := rc.Validate(); err != nil {
+			return fmt.Errorf("relabel config %d: %v", i, err)
+		}
+	}
+	for _, re := range append(append([]string{}, fc.AllowList...), fc.DenyList...) {
+		if _, err := regexp.Compile("^(?:" + re + ")$"); err != nil {
+			return fmt.Errorf("invalid metric name regex %s: %v", re, err)
+		}
+	}
+	if fc.SeriesLimit < 0 {
+		return fmt.Errorf("seriesLimit can not be negative")
+	}
+	return nil
+}
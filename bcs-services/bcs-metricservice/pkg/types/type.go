@@ -65,6 +65,7 @@ type Metric struct {
 	// prometheus
 	MetricType            MetricType        `json:"metricType"`
 	PrometheusConstLabels map[string]string `json:"constLabels"`
+	FilterConfig          MetricFilterCfg   `json:"filterConfig"`
 }
 
 // ApplicationCollectorCfg application collector configuration
@@ -95,6 +96,7 @@ type CollectorCfg struct {
 
 	MetricType            MetricType        `json:"metricType"`
 	PrometheusConstLabels map[string]string `json:"constLabels"`
+	FilterConfig          MetricFilterCfg   `json:"filterConfig"`
 }
 
 // data struct of MetricTask in storage